@@ -155,8 +155,15 @@ func withRetryCount(retryCount uint) func(*getRequestConfig) {
 
 // getRequest makes a GET request to the API
 func (c *Client) getRequest(relPath string, data interface{}, response interface{}, opts ...func(*getRequestConfig)) error {
+	encoder := schema.NewEncoder()
+
+	// handle encoding of timestamps, keeping sub-second precision so that times returned by the API can be sent back
+	encoder.RegisterEncoder(time.Time{}, func(t reflect.Value) string {
+		return t.Interface().(time.Time).Format(time.RFC3339Nano)
+	})
+
 	vals := make(map[string][]string)
-	_ = schema.NewEncoder().Encode(data, vals)
+	_ = encoder.Encode(data, vals)
 	var err error
 
 	urlVals := url.Values(vals)
@@ -328,6 +335,32 @@ func (c *Client) patchRequest(relPath string, data interface{}, response interfa
 	return err
 }
 
+func (c *Client) putRequest(relPath string, data interface{}, response interface{}) error {
+	strData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(
+		"PUT",
+		fmt.Sprintf("%s%s", c.BaseURL, relPath),
+		strings.NewReader(string(strData)),
+	)
+	if err != nil {
+		return err
+	}
+
+	if httpErr, err := c.sendRequest(req, response, true); httpErr != nil || err != nil {
+		if httpErr != nil {
+			return fmt.Errorf("%v", httpErr.Error)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
 func (c *Client) deleteRequest(relPath string, data interface{}, response interface{}) error {
 	strData, err := json.Marshal(data)
 	if err != nil {
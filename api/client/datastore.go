@@ -4,9 +4,30 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/porter-dev/porter/api/server/handlers/datastore"
 	"github.com/porter-dev/porter/api/types"
 )
 
+// GetDatastore returns a datastore in a project by name
+func (c *Client) GetDatastore(
+	ctx context.Context,
+	projectID uint,
+	datastoreName string,
+) (*datastore.GetDatastoreResponse, error) {
+	resp := &datastore.GetDatastoreResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/datastores/%s",
+			projectID, datastoreName,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
 // CreateDatastoreProxy creates a proxy to connect to a datastore
 func (c *Client) CreateDatastoreProxy(
 	ctx context.Context,
@@ -27,3 +48,85 @@ func (c *Client) CreateDatastoreProxy(
 
 	return resp, err
 }
+
+// ListDatastoreSnapshots lists the snapshots of a datastore
+func (c *Client) ListDatastoreSnapshots(
+	ctx context.Context,
+	projectID uint,
+	datastoreName string,
+) (*types.ListDatastoreSnapshotsResponse, error) {
+	resp := &types.ListDatastoreSnapshotsResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/datastores/%s/snapshots",
+			projectID, datastoreName,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+// CreateDatastoreSnapshot triggers an on-demand snapshot of a datastore
+func (c *Client) CreateDatastoreSnapshot(
+	ctx context.Context,
+	projectID uint,
+	datastoreName string,
+) (*types.CreateDatastoreSnapshotResponse, error) {
+	resp := &types.CreateDatastoreSnapshotResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/datastores/%s/snapshots",
+			projectID, datastoreName,
+		),
+		&types.CreateDatastoreSnapshotRequest{},
+		resp,
+	)
+
+	return resp, err
+}
+
+// RestoreDatastore restores a snapshot or point in time of a datastore into a new datastore
+func (c *Client) RestoreDatastore(
+	ctx context.Context,
+	projectID uint,
+	datastoreName string,
+	req *types.RestoreDatastoreRequest,
+) (*types.RestoreDatastoreResponse, error) {
+	resp := &types.RestoreDatastoreResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/datastores/%s/restore",
+			projectID, datastoreName,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// CloneDatastore clones a datastore into a new datastore for a preview environment
+func (c *Client) CloneDatastore(
+	ctx context.Context,
+	projectID uint,
+	datastoreName string,
+	req *types.CloneDatastoreRequest,
+) (*types.CloneDatastoreResponse, error) {
+	resp := &types.CloneDatastoreResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/datastores/%s/clone",
+			projectID, datastoreName,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/porter-dev/porter/api/server/handlers/environment_groups"
+	"github.com/porter-dev/porter/api/types"
 )
 
 // GetLatestEnvGroupVariables gets the latest environment group variables for a given environment group
@@ -54,3 +55,178 @@ func (c *Client) UpdateEnvGroup(
 		nil,
 	)
 }
+
+// ListEnvGroupVersions lists every version of an environment group, newest first
+func (c *Client) ListEnvGroupVersions(
+	ctx context.Context,
+	projID, clusterID uint,
+	envGroupName string,
+) (*environment_groups.ListEnvGroupVersionsResponse, error) {
+	resp := &environment_groups.ListEnvGroupVersionsResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf("/projects/%d/clusters/%d/environment-groups/%s/versions", projID, clusterID, envGroupName),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+// DiffEnvGroupVersions returns the keys which differ between two versions of an environment group
+func (c *Client) DiffEnvGroupVersions(
+	ctx context.Context,
+	projID, clusterID uint,
+	envGroupName string,
+	req *environment_groups.DiffEnvGroupVersionsRequest,
+) (*environment_groups.DiffEnvGroupVersionsResponse, error) {
+	resp := &environment_groups.DiffEnvGroupVersionsResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf("/projects/%d/clusters/%d/environment-groups/%s/diff", projID, clusterID, envGroupName),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// RollbackEnvGroup creates a new version of an environment group with the values of an earlier version
+func (c *Client) RollbackEnvGroup(
+	ctx context.Context,
+	projID, clusterID uint,
+	envGroupName string,
+	req *environment_groups.RollbackEnvGroupRequest,
+) (*environment_groups.RollbackEnvGroupResponse, error) {
+	resp := &environment_groups.RollbackEnvGroupResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf("/projects/%d/clusters/%d/environment-groups/%s/rollback", projID, clusterID, envGroupName),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ImportEnvGroup imports variables, secrets and files from a file into an environment group
+func (c *Client) ImportEnvGroup(
+	ctx context.Context,
+	projID, clusterID uint,
+	envGroupName string,
+	req *environment_groups.ImportEnvGroupRequest,
+) (*environment_groups.ImportEnvGroupResponse, error) {
+	resp := &environment_groups.ImportEnvGroupResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf("/projects/%d/clusters/%d/environment-groups/%s/import", projID, clusterID, envGroupName),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ExportEnvGroup exports a version of an environment group as a file
+func (c *Client) ExportEnvGroup(
+	ctx context.Context,
+	projID, clusterID uint,
+	envGroupName string,
+	req *environment_groups.ExportEnvGroupRequest,
+) (*environment_groups.ExportEnvGroupResponse, error) {
+	resp := &environment_groups.ExportEnvGroupResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf("/projects/%d/clusters/%d/environment-groups/%s/export", projID, clusterID, envGroupName),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ListEnvGroupRotationPolicies lists the rotation policies of an environment group
+func (c *Client) ListEnvGroupRotationPolicies(
+	ctx context.Context,
+	projID, clusterID uint,
+	envGroupName string,
+) (*types.ListEnvGroupRotationPoliciesResponse, error) {
+	resp := &types.ListEnvGroupRotationPoliciesResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf("/projects/%d/clusters/%d/environment-groups/%s/rotation-policies", projID, clusterID, envGroupName),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+// CreateEnvGroupRotationPolicy creates a rotation policy for a key of an environment group
+func (c *Client) CreateEnvGroupRotationPolicy(
+	ctx context.Context,
+	projID, clusterID uint,
+	envGroupName string,
+	req *types.CreateEnvGroupRotationPolicyRequest,
+) (*types.CreateEnvGroupRotationPolicyResponse, error) {
+	resp := &types.CreateEnvGroupRotationPolicyResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf("/projects/%d/clusters/%d/environment-groups/%s/rotation-policies", projID, clusterID, envGroupName),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// UpdateEnvGroupRotationPolicy replaces the definition of a rotation policy of an environment group
+func (c *Client) UpdateEnvGroupRotationPolicy(
+	ctx context.Context,
+	projID, clusterID uint,
+	envGroupName string,
+	policyID uint,
+	req *types.UpdateEnvGroupRotationPolicyRequest,
+) (*types.UpdateEnvGroupRotationPolicyResponse, error) {
+	resp := &types.UpdateEnvGroupRotationPolicyResponse{}
+
+	err := c.putRequest(
+		fmt.Sprintf("/projects/%d/clusters/%d/environment-groups/%s/rotation-policies/%d", projID, clusterID, envGroupName, policyID),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// DeleteEnvGroupRotationPolicy deletes a rotation policy of an environment group
+func (c *Client) DeleteEnvGroupRotationPolicy(
+	ctx context.Context,
+	projID, clusterID uint,
+	envGroupName string,
+	policyID uint,
+) error {
+	return c.deleteRequest(
+		fmt.Sprintf("/projects/%d/clusters/%d/environment-groups/%s/rotation-policies/%d", projID, clusterID, envGroupName, policyID),
+		nil,
+		nil,
+	)
+}
+
+// RotateEnvGroupKey rotates the key of a rotation policy now
+func (c *Client) RotateEnvGroupKey(
+	ctx context.Context,
+	projID, clusterID uint,
+	envGroupName string,
+	policyID uint,
+) (*types.RotateEnvGroupKeyResponse, error) {
+	resp := &types.RotateEnvGroupKeyResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf("/projects/%d/clusters/%d/environment-groups/%s/rotation-policies/%d/rotate", projID, clusterID, envGroupName, policyID),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
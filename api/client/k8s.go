@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/fatih/color"
+	"github.com/gorilla/websocket"
 	"github.com/porter-dev/porter/api/types"
 
 	v1 "k8s.io/api/batch/v1"
@@ -58,6 +59,27 @@ func (c *Client) CreateNewK8sNamespace(
 	return resp, err
 }
 
+// SimulateClusterCapacity simulates scheduling pods onto the current nodes of a k8s cluster
+func (c *Client) SimulateClusterCapacity(
+	ctx context.Context,
+	projectID uint,
+	clusterID uint,
+	req *types.SimulateClusterCapacityRequest,
+) (*types.SimulateClusterCapacityResponse, error) {
+	resp := &types.SimulateClusterCapacityResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/capacity/simulate",
+			projectID, clusterID,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
 func (c *Client) GetKubeconfig(
 	ctx context.Context,
 	projectID uint,
@@ -230,3 +252,76 @@ func (c *Client) GetK8sAllPods(
 
 	return resp, err
 }
+
+// CordonNode stops new pods from being scheduled onto a node
+func (c *Client) CordonNode(
+	ctx context.Context,
+	projectID, clusterID uint,
+	nodeName string,
+) (*types.NodeSchedulingResponse, error) {
+	resp := &types.NodeSchedulingResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/nodes/%s/cordon",
+			projectID, clusterID, nodeName,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+// UncordonNode allows new pods to be scheduled onto a node again
+func (c *Client) UncordonNode(
+	ctx context.Context,
+	projectID, clusterID uint,
+	nodeName string,
+) (*types.NodeSchedulingResponse, error) {
+	resp := &types.NodeSchedulingResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/nodes/%s/uncordon",
+			projectID, clusterID, nodeName,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+// DrainNodeStream opens a websocket which drains a node. Each message is a types.NodeMaintenanceEvent, and the last
+// message is either a completed or a failed event.
+func (c *Client) DrainNodeStream(
+	ctx context.Context,
+	projectID, clusterID uint,
+	nodeName string,
+	req *types.DrainNodeRequest,
+) (*websocket.Conn, error) {
+	return c.websocketDial(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/nodes/%s/drain",
+			projectID, clusterID, nodeName,
+		),
+		req,
+	)
+}
+
+// RotateNodesStream opens a websocket which drains nodes one at a time. Each message is a types.NodeMaintenanceEvent,
+// and the last message is either a completed or a failed event.
+func (c *Client) RotateNodesStream(
+	ctx context.Context,
+	projectID, clusterID uint,
+	req *types.RotateNodesRequest,
+) (*websocket.Conn, error) {
+	return c.websocketDial(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/nodes/rotate",
+			projectID, clusterID,
+		),
+		req,
+	)
+}
@@ -323,6 +323,433 @@ func (c *Client) AppLogsStream(
 	return conn, nil
 }
 
+// SearchAppLogs searches the logs of an app with a log query, returning a continue time if more logs may match
+func (c *Client) SearchAppLogs(
+	ctx context.Context,
+	projectID, clusterID uint,
+	appName string,
+	req *porter_app.AppLogSearchRequest,
+) (*porter_app.AppLogSearchResponse, error) {
+	resp := &porter_app.AppLogSearchResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/logs/search",
+			projectID, clusterID, appName,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// AppPortForwardInput is the input for the AppPortForwardStream method
+type AppPortForwardInput struct {
+	ProjectID            uint
+	ClusterID            uint
+	AppName              string
+	ServiceName          string
+	DeploymentTargetName string
+	Port                 int
+}
+
+// AppPortForwardStream opens a websocket which carries a single TCP connection to a port on a pod of an app service.
+// Binary messages are connection data, while text messages contain an error from the server.
+func (c *Client) AppPortForwardStream(
+	ctx context.Context,
+	inp AppPortForwardInput,
+) (*websocket.Conn, error) {
+	req := &porter_app.AppPortForwardRequest{
+		ServiceName:          inp.ServiceName,
+		DeploymentTargetName: inp.DeploymentTargetName,
+		Port:                 inp.Port,
+	}
+
+	conn, err := c.websocketDial(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/port-forward",
+			inp.ProjectID, inp.ClusterID, inp.AppName,
+		),
+		req,
+	)
+	if err != nil {
+		return conn, err
+	}
+
+	return conn, nil
+}
+
+// QueryAppMetricsInput is the input for the QueryAppMetrics method
+type QueryAppMetricsInput struct {
+	ProjectID            uint
+	ClusterID            uint
+	AppName              string
+	DeploymentTargetName string
+	Query                string
+	StartRange           uint
+	EndRange             uint
+	Resolution           string
+}
+
+// QueryAppMetrics runs a PromQL query against the metrics of an app's deployment target
+func (c *Client) QueryAppMetrics(
+	ctx context.Context,
+	inp QueryAppMetricsInput,
+) (*porter_app.AppMetricsQueryResponse, error) {
+	resp := &porter_app.AppMetricsQueryResponse{}
+
+	req := &porter_app.AppMetricsQueryRequest{
+		DeploymentTargetName: inp.DeploymentTargetName,
+		Query:                inp.Query,
+		StartRange:           inp.StartRange,
+		EndRange:             inp.EndRange,
+		Resolution:           inp.Resolution,
+	}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/metrics/query",
+			inp.ProjectID, inp.ClusterID, inp.AppName,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// GetAppTimeline gets the timeline of revisions, helm upgrades, kubernetes events, scaling events and incidents of an app
+func (c *Client) GetAppTimeline(
+	ctx context.Context,
+	projectID, clusterID uint,
+	appName string,
+	req *types.GetAppTimelineRequest,
+) (*types.GetAppTimelineResponse, error) {
+	resp := &types.GetAppTimelineResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/timeline",
+			projectID, clusterID, appName,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ListAppDashboards lists the saved metrics dashboards of an app
+func (c *Client) ListAppDashboards(
+	ctx context.Context,
+	projectID, clusterID uint,
+	appName string,
+) (*types.ListAppDashboardsResponse, error) {
+	resp := &types.ListAppDashboardsResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/dashboards",
+			projectID, clusterID, appName,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ListAppSLOs lists the SLOs of an app in a deployment target, along with their most recent status
+func (c *Client) ListAppSLOs(
+	ctx context.Context,
+	projectID, clusterID uint,
+	appName string,
+	deploymentTargetID string,
+) (*types.ListAppSLOsResponse, error) {
+	resp := &types.ListAppSLOsResponse{}
+
+	req := &porter_app.ListAppSLOsRequest{
+		DeploymentTargetID: deploymentTargetID,
+	}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/slos",
+			projectID, clusterID, appName,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// UpdateAppSLOs replaces the SLOs of an app in a deployment target which were declared by the source in the request
+func (c *Client) UpdateAppSLOs(
+	ctx context.Context,
+	projectID, clusterID uint,
+	appName string,
+	req *types.UpdateAppSLOsRequest,
+) (*types.UpdateAppSLOsResponse, error) {
+	resp := &types.UpdateAppSLOsResponse{}
+
+	err := c.putRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/slos",
+			projectID, clusterID, appName,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ListAppSyntheticChecks lists the synthetic checks of an app in a deployment target, along with their most recent result
+func (c *Client) ListAppSyntheticChecks(
+	ctx context.Context,
+	projectID, clusterID uint,
+	appName string,
+	deploymentTargetID string,
+	deploymentTargetName string,
+) (*types.ListAppSyntheticChecksResponse, error) {
+	resp := &types.ListAppSyntheticChecksResponse{}
+
+	req := &porter_app.ListAppSyntheticChecksRequest{
+		DeploymentTargetID:   deploymentTargetID,
+		DeploymentTargetName: deploymentTargetName,
+	}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/synthetic-checks",
+			projectID, clusterID, appName,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// UpdateAppSyntheticChecks replaces the synthetic checks of an app in a deployment target
+func (c *Client) UpdateAppSyntheticChecks(
+	ctx context.Context,
+	projectID, clusterID uint,
+	appName string,
+	req *types.UpdateAppSyntheticChecksRequest,
+) (*types.UpdateAppSyntheticChecksResponse, error) {
+	resp := &types.UpdateAppSyntheticChecksResponse{}
+
+	err := c.putRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/synthetic-checks",
+			projectID, clusterID, appName,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ListAppSyntheticCheckResults lists the most recent results of a synthetic check of an app
+func (c *Client) ListAppSyntheticCheckResults(
+	ctx context.Context,
+	projectID, clusterID uint,
+	appName string,
+	checkID uint,
+	limit int,
+) (*types.ListAppSyntheticCheckResultsResponse, error) {
+	resp := &types.ListAppSyntheticCheckResultsResponse{}
+
+	req := &types.ListAppSyntheticCheckResultsRequest{
+		Limit: limit,
+	}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/synthetic-checks/%d/results",
+			projectID, clusterID, appName, checkID,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// CreateAppCanaries starts a canary of a new image for each of the given web services of an app
+func (c *Client) CreateAppCanaries(
+	ctx context.Context,
+	projectID, clusterID uint,
+	appName string,
+	req *types.CreateAppCanariesRequest,
+) (*types.CreateAppCanariesResponse, error) {
+	resp := &types.CreateAppCanariesResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/canaries",
+			projectID, clusterID, appName,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// GetAppCanary returns the progress of a canary, by the id of the app event it is recorded in
+func (c *Client) GetAppCanary(
+	ctx context.Context,
+	projectID, clusterID uint,
+	appName string,
+	eventID string,
+) (*types.GetAppCanaryResponse, error) {
+	resp := &types.GetAppCanaryResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/canaries/%s",
+			projectID, clusterID, appName, eventID,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ReportPreviewSeed records the result of the seed steps of a preview environment
+func (c *Client) ReportPreviewSeed(
+	ctx context.Context,
+	projectID, clusterID uint,
+	appName string,
+	req *types.ReportPreviewSeedRequest,
+) (*types.ReportPreviewSeedResponse, error) {
+	resp := &types.ReportPreviewSeedResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/preview-seed",
+			projectID, clusterID, appName,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// GetPreviewSeed returns the latest seed of a preview environment
+func (c *Client) GetPreviewSeed(
+	ctx context.Context,
+	projectID, clusterID uint,
+	appName string,
+	deploymentTargetID string,
+) (*types.GetPreviewSeedResponse, error) {
+	resp := &types.GetPreviewSeedResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/preview-seed",
+			projectID, clusterID, appName,
+		),
+		&types.GetPreviewSeedRequest{
+			DeploymentTargetID: deploymentTargetID,
+		},
+		resp,
+	)
+
+	return resp, err
+}
+
+// AppExecInput is the input for the AppExecStream method
+type AppExecInput struct {
+	ProjectID            uint
+	ClusterID            uint
+	AppName              string
+	ServiceName          string
+	DeploymentTargetName string
+	PodName              string
+	ContainerName        string
+	Command              []string
+	TTY                  bool
+}
+
+// AppExecStream opens a websocket which runs a command in a running pod of an app service.
+// Messages are framed as described by types.ExecStream.
+func (c *Client) AppExecStream(
+	ctx context.Context,
+	inp AppExecInput,
+) (*websocket.Conn, error) {
+	req := &porter_app.AppExecRequest{
+		ServiceName:          inp.ServiceName,
+		DeploymentTargetName: inp.DeploymentTargetName,
+		PodName:              inp.PodName,
+		ContainerName:        inp.ContainerName,
+		Command:              inp.Command,
+		TTY:                  inp.TTY,
+	}
+
+	conn, err := c.websocketDial(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/exec",
+			inp.ProjectID, inp.ClusterID, inp.AppName,
+		),
+		req,
+	)
+	if err != nil {
+		return conn, err
+	}
+
+	return conn, nil
+}
+
+// AppCopyInput is the input for the AppCopyStream method
+type AppCopyInput struct {
+	ProjectID            uint
+	ClusterID            uint
+	AppName              string
+	ServiceName          string
+	DeploymentTargetName string
+	PodName              string
+	ContainerName        string
+	Path                 string
+	Direction            types.AppCopyDirection
+}
+
+// AppCopyStream opens a websocket which copies a tar archive to or from a running pod of an app service.
+// Messages are framed as described by types.ExecStream.
+func (c *Client) AppCopyStream(
+	ctx context.Context,
+	inp AppCopyInput,
+) (*websocket.Conn, error) {
+	req := &porter_app.AppCopyRequest{
+		ServiceName:          inp.ServiceName,
+		DeploymentTargetName: inp.DeploymentTargetName,
+		PodName:              inp.PodName,
+		ContainerName:        inp.ContainerName,
+		Path:                 inp.Path,
+		Direction:            inp.Direction,
+	}
+
+	conn, err := c.websocketDial(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/cp",
+			inp.ProjectID, inp.ClusterID, inp.AppName,
+		),
+		req,
+	)
+	if err != nil {
+		return conn, err
+	}
+
+	return conn, nil
+}
+
 // DefaultDeploymentTarget returns the default deployment target for a given project and cluster
 func (c *Client) DefaultDeploymentTarget(
 	ctx context.Context,
@@ -799,6 +1226,44 @@ func (c *Client) RunAppJob(
 	return resp, err
 }
 
+// RunAppJobWithOverridesInput contains the information necessary to run a job against a deployment target by id,
+// optionally overriding its run command
+type RunAppJobWithOverridesInput struct {
+	ProjectID          uint
+	ClusterID          uint
+	AppName            string
+	JobName            string
+	DeploymentTargetID string
+	// RunCommand overrides the run command of the job if set
+	RunCommand string
+}
+
+// RunAppJobWithOverrides runs a job for an app, overriding its run command if one is given
+func (c *Client) RunAppJobWithOverrides(
+	ctx context.Context,
+	inp RunAppJobWithOverridesInput,
+) (*porter_app.RunAppJobResponse, error) {
+	resp := &porter_app.RunAppJobResponse{}
+
+	req := &porter_app.RunAppJobRequest{
+		ServiceName:        inp.JobName,
+		DeploymentTargetID: inp.DeploymentTargetID,
+		RunCommand:         inp.RunCommand,
+	}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/run",
+			inp.ProjectID, inp.ClusterID,
+			inp.AppName,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
 // CancelAppJobInput contains all the information necessary to cancel a job
 type CancelAppJobInput struct {
 	ProjectID            uint
@@ -806,6 +1271,8 @@ type CancelAppJobInput struct {
 	AppName              string
 	JobName              string
 	DeploymentTargetName string
+	// DeploymentTargetID can be set instead of DeploymentTargetName
+	DeploymentTargetID string
 }
 
 // CancelAppJobRun cancels a in progress job run
@@ -817,6 +1284,7 @@ func (c *Client) CancelAppJobRun(
 
 	req := &porter_app.CancelJobRunRequest{
 		DeploymentTargetName: inp.DeploymentTargetName,
+		DeploymentTargetID:   inp.DeploymentTargetID,
 	}
 
 	err := c.postRequest(
@@ -843,6 +1311,9 @@ type RunAppJobStatusInput struct {
 	// DeploymentTargetName is the id of the deployment target the job was run against
 	DeploymentTargetName string
 
+	// DeploymentTargetID is the id of the deployment target the job was run against, which can be set instead of DeploymentTargetName
+	DeploymentTargetID string
+
 	// ServiceName is the name of the app service that was triggered
 	ServiceName string
 
@@ -862,6 +1333,7 @@ func (c *Client) RunAppJobStatus(
 
 	req := &porter_app.AppJobRunStatusRequest{
 		DeploymentTargetName: input.DeploymentTargetName,
+		DeploymentTargetID:   input.DeploymentTargetID,
 		JobRunID:             input.JobRunID,
 		ServiceName:          input.ServiceName,
 	}
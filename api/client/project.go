@@ -177,3 +177,57 @@ func (c *Client) DeleteProject(
 		nil,
 	)
 }
+
+// CreateLogSearch saves a log search query for a project
+func (c *Client) CreateLogSearch(
+	ctx context.Context,
+	projectID uint,
+	req *types.CreateLogSearchRequest,
+) (*types.CreateLogSearchResponse, error) {
+	resp := &types.CreateLogSearchResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/log_searches",
+			projectID,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ListLogSearches lists the saved log searches of a project
+func (c *Client) ListLogSearches(
+	ctx context.Context,
+	projectID uint,
+) (*types.ListLogSearchesResponse, error) {
+	resp := &types.ListLogSearchesResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/log_searches",
+			projectID,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+// DeleteLogSearch deletes a saved log search of a project
+func (c *Client) DeleteLogSearch(
+	ctx context.Context,
+	projectID, logSearchID uint,
+) error {
+	return c.deleteRequest(
+		fmt.Sprintf(
+			"/projects/%d/log_searches/%d",
+			projectID, logSearchID,
+		),
+		nil,
+		nil,
+	)
+}
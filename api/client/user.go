@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/porter-dev/porter/api/types"
@@ -22,9 +23,16 @@ func (c *Client) AuthCheck(ctx context.Context) (*types.GetAuthenticatedUserResp
 	return resp, err
 }
 
-// Login authorizes the user and grants them a cookie-based session
+// ErrMFARequired is returned from Login when the user must complete a second factor through LoginMFA
+var ErrMFARequired = errors.New("multi-factor authentication is required")
+
+// Login authorizes the user and grants them a cookie-based session. If the user has multi-factor
+// authentication enabled, ErrMFARequired is returned and the session is only authorized after LoginMFA.
 func (c *Client) Login(ctx context.Context, req *types.LoginUserRequest) (*types.GetAuthenticatedUserResponse, error) {
-	resp := &types.GetAuthenticatedUserResponse{}
+	resp := &struct {
+		types.GetAuthenticatedUserResponse
+		types.LoginMFARequiredResponse
+	}{}
 
 	err := c.postRequest(
 		fmt.Sprintf(
@@ -33,6 +41,26 @@ func (c *Client) Login(ctx context.Context, req *types.LoginUserRequest) (*types
 		req,
 		resp,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.MFARequired {
+		return nil, ErrMFARequired
+	}
+
+	return &resp.GetAuthenticatedUserResponse, nil
+}
+
+// LoginMFA completes a login with a second factor
+func (c *Client) LoginMFA(ctx context.Context, req *types.LoginMFARequest) (*types.GetAuthenticatedUserResponse, error) {
+	resp := &types.GetAuthenticatedUserResponse{}
+
+	err := c.postRequest(
+		"/login/mfa",
+		req,
+		resp,
+	)
 
 	return resp, err
 }
@@ -126,7 +126,7 @@ func (authn *AuthN) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	authn.nextWithUserID(w, r, userID)
+	authn.nextWithUserID(w, r, userID, mfaVerifiedAtFromSession(session))
 }
 
 func (authn *AuthN) handleForbiddenForSession(
@@ -192,7 +192,7 @@ func (authn *AuthN) verifyTokenWithNext(w http.ResponseWriter, r *http.Request,
 		authn.nextWithAPIToken(w, r, apiToken)
 	} else {
 		// otherwise we just use nextWithUser using the `iby` field for the token
-		authn.nextWithUserID(w, r, tok.IBy)
+		authn.nextWithUserID(w, r, tok.IBy, tok.MFAVerifiedAt)
 	}
 }
 
@@ -213,8 +213,8 @@ func (authn *AuthN) nextWithAPIToken(w http.ResponseWriter, r *http.Request, tok
 }
 
 // nextWithUserID calls the next handler with the user set in the context with key
-// `types.UserScope`.
-func (authn *AuthN) nextWithUserID(w http.ResponseWriter, r *http.Request, userID uint) {
+// `types.UserScope`, and when the user completed a second factor with key `types.MFAVerifiedAtCtxKey`.
+func (authn *AuthN) nextWithUserID(w http.ResponseWriter, r *http.Request, userID uint, mfaVerifiedAt *time.Time) {
 	// search for the user
 	user, err := authn.config.Repo.User().ReadUser(userID)
 	if err != nil {
@@ -226,6 +226,10 @@ func (authn *AuthN) nextWithUserID(w http.ResponseWriter, r *http.Request, userI
 	ctx := r.Context()
 	ctx = context.WithValue(ctx, types.UserScope, user)
 
+	if mfaVerifiedAt != nil {
+		ctx = context.WithValue(ctx, types.MFAVerifiedAtCtxKey, *mfaVerifiedAt)
+	}
+
 	r = r.Clone(ctx)
 	authn.next.ServeHTTP(w, r)
 }
@@ -1,8 +1,12 @@
 package authn
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/gorilla/sessions"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/internal/models"
 )
@@ -12,6 +16,27 @@ func SaveUserAuthenticated(
 	r *http.Request,
 	config *config.Config,
 	user *models.User,
+) (string, error) {
+	return saveUserAuthenticated(w, r, config, user, false)
+}
+
+// SaveUserAuthenticatedWithMFA authenticates the session of a user who has completed a second factor, which
+// is recorded in the session so that projects which require multi-factor authentication can check it
+func SaveUserAuthenticatedWithMFA(
+	w http.ResponseWriter,
+	r *http.Request,
+	config *config.Config,
+	user *models.User,
+) (string, error) {
+	return saveUserAuthenticated(w, r, config, user, true)
+}
+
+func saveUserAuthenticated(
+	w http.ResponseWriter,
+	r *http.Request,
+	config *config.Config,
+	user *models.User,
+	mfaVerified bool,
 ) (string, error) {
 	session, err := config.Store.Get(r, config.ServerConf.CookieName)
 	if err != nil {
@@ -28,6 +53,19 @@ func SaveUserAuthenticated(
 	session.Values["user_id"] = user.ID
 	session.Values["email"] = user.Email
 
+	// any pending second factor has been completed
+	delete(session.Values, "mfa_pending_user_id")
+	delete(session.Values, "mfa_pending_at")
+	delete(session.Values, "mfa_failed_attempts")
+	delete(session.Values, "webauthn_challenge")
+
+	// a new login only counts as verified if it completed a second factor
+	if mfaVerified {
+		session.Values["mfa_verified_at"] = time.Now().Unix()
+	} else {
+		delete(session.Values, "mfa_verified_at")
+	}
+
 	// we unset the redirect uri after login
 	session.Values["redirect_uri"] = ""
 
@@ -47,5 +85,170 @@ func SaveUserUnauthenticated(
 	session.Values["authenticated"] = false
 	session.Values["user_id"] = nil
 	session.Values["email"] = nil
+	delete(session.Values, "mfa_verified_at")
+	return session.Save(r, w)
+}
+
+// mfaVerifiedAtFromSession returns when the user of a session completed a second factor, or nil if they have not
+func mfaVerifiedAtFromSession(session *sessions.Session) *time.Time {
+	verifiedAt, ok := session.Values["mfa_verified_at"].(int64)
+	if !ok || verifiedAt == 0 {
+		return nil
+	}
+
+	t := time.Unix(verifiedAt, 0)
+
+	return &t
+}
+
+const (
+	// mfaPendingTTL is how long a user has to complete the second factor after entering their password
+	mfaPendingTTL = 5 * time.Minute
+	// mfaMaxFailedAttempts is the number of invalid second factors after which the user has to enter their password again
+	mfaMaxFailedAttempts = 5
+)
+
+// ErrMFAAttemptsExceeded is returned when a pending login has been cleared after too many invalid second factors
+var ErrMFAAttemptsExceeded = errors.New("too many invalid authentication codes, please log in again")
+
+// SaveUserMFAPending records in the session that the user has verified their password but still needs
+// to complete a second factor. The session is not authenticated until SaveUserAuthenticated is called.
+func SaveUserMFAPending(
+	w http.ResponseWriter,
+	r *http.Request,
+	config *config.Config,
+	user *models.User,
+) error {
+	session, err := config.Store.Get(r, config.ServerConf.CookieName)
+	if err != nil {
+		return err
+	}
+
+	session.Values["authenticated"] = false
+	session.Values["mfa_pending_user_id"] = user.ID
+	session.Values["mfa_pending_at"] = time.Now().Unix()
+	session.Values["mfa_failed_attempts"] = 0
+
 	return session.Save(r, w)
 }
+
+// GetUserMFAPending returns the id of the user that is waiting to complete a second factor
+func GetUserMFAPending(
+	r *http.Request,
+	config *config.Config,
+) (uint, error) {
+	session, err := config.Store.Get(r, config.ServerConf.CookieName)
+	if err != nil {
+		return 0, err
+	}
+
+	userID, ok := session.Values["mfa_pending_user_id"].(uint)
+	if !ok || userID == 0 {
+		return 0, fmt.Errorf("no login is waiting for a second factor")
+	}
+
+	pendingAt, ok := session.Values["mfa_pending_at"].(int64)
+	if !ok || time.Since(time.Unix(pendingAt, 0)) > mfaPendingTTL {
+		return 0, fmt.Errorf("second factor was not provided in time, please log in again")
+	}
+
+	if failed, _ := session.Values["mfa_failed_attempts"].(int); failed >= mfaMaxFailedAttempts {
+		return 0, ErrMFAAttemptsExceeded
+	}
+
+	return userID, nil
+}
+
+// RecordUserMFAFailure counts an invalid second factor for the pending login. Once the user has provided too
+// many invalid second factors, the pending login is cleared and ErrMFAAttemptsExceeded is returned.
+func RecordUserMFAFailure(
+	w http.ResponseWriter,
+	r *http.Request,
+	config *config.Config,
+) error {
+	session, err := config.Store.Get(r, config.ServerConf.CookieName)
+	if err != nil {
+		return err
+	}
+
+	failed, _ := session.Values["mfa_failed_attempts"].(int)
+	failed++
+
+	if failed < mfaMaxFailedAttempts {
+		session.Values["mfa_failed_attempts"] = failed
+		return session.Save(r, w)
+	}
+
+	delete(session.Values, "mfa_pending_user_id")
+	delete(session.Values, "mfa_pending_at")
+	delete(session.Values, "mfa_failed_attempts")
+	delete(session.Values, "webauthn_challenge")
+
+	if err := session.Save(r, w); err != nil {
+		return err
+	}
+
+	return ErrMFAAttemptsExceeded
+}
+
+// ClearUserMFAPending removes the pending second factor state from the session
+func ClearUserMFAPending(
+	w http.ResponseWriter,
+	r *http.Request,
+	config *config.Config,
+) error {
+	session, err := config.Store.Get(r, config.ServerConf.CookieName)
+	if err != nil {
+		return err
+	}
+
+	delete(session.Values, "mfa_pending_user_id")
+	delete(session.Values, "mfa_pending_at")
+	delete(session.Values, "mfa_failed_attempts")
+	delete(session.Values, "webauthn_challenge")
+
+	return session.Save(r, w)
+}
+
+// SaveWebAuthnChallenge stores the challenge for an in-progress WebAuthn ceremony in the session
+func SaveWebAuthnChallenge(
+	w http.ResponseWriter,
+	r *http.Request,
+	config *config.Config,
+	challenge string,
+) error {
+	session, err := config.Store.Get(r, config.ServerConf.CookieName)
+	if err != nil {
+		return err
+	}
+
+	session.Values["webauthn_challenge"] = challenge
+
+	return session.Save(r, w)
+}
+
+// PopWebAuthnChallenge returns the challenge for an in-progress WebAuthn ceremony and removes it from
+// the session, so that each challenge can only be used once
+func PopWebAuthnChallenge(
+	w http.ResponseWriter,
+	r *http.Request,
+	config *config.Config,
+) (string, error) {
+	session, err := config.Store.Get(r, config.ServerConf.CookieName)
+	if err != nil {
+		return "", err
+	}
+
+	challenge, _ := session.Values["webauthn_challenge"].(string)
+	delete(session.Values, "webauthn_challenge")
+
+	if err := session.Save(r, w); err != nil {
+		return "", err
+	}
+
+	if challenge == "" {
+		return "", fmt.Errorf("no webauthn challenge found in session")
+	}
+
+	return challenge, nil
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/auth/mfa"
 	"github.com/porter-dev/porter/internal/models"
 	"gorm.io/gorm"
 )
@@ -51,11 +53,53 @@ func (p *ProjectScopedMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	if project.RequireMFA {
+		if err := p.checkUserMFA(r); err != nil {
+			apierrors.HandleAPIError(p.config.Logger, p.config.Alerter, w, r, err, true)
+			return
+		}
+	}
+
 	ctx := NewProjectContext(r.Context(), project)
 	r = r.Clone(ctx)
 	p.next.ServeHTTP(w, r)
 }
 
+// checkUserMFA returns an error if the user making the request does not have multi-factor
+// authentication enabled, or if the session or token of the request was not created by a login
+// which completed a second factor. Requests made with a project API token are not subject to the check.
+func (p *ProjectScopedMiddleware) checkUserMFA(r *http.Request) apierrors.RequestError {
+	if _, ok := r.Context().Value("api_token").(*models.APIToken); ok {
+		return nil
+	}
+
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	if user == nil || user.ID == 0 {
+		return nil
+	}
+
+	methods, err := mfa.EnabledMethods(p.config.Repo.MFA(), user.ID)
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	if len(methods) == 0 {
+		return apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("this project requires multi-factor authentication: enable it in your account settings to continue"),
+			http.StatusForbidden,
+		)
+	}
+
+	if _, ok := r.Context().Value(types.MFAVerifiedAtCtxKey).(time.Time); !ok {
+		return apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("this project requires multi-factor authentication: log in again with your second factor to continue"),
+			http.StatusForbidden,
+		)
+	}
+
+	return nil
+}
+
 func NewProjectContext(ctx context.Context, project *models.Project) context.Context {
 	return context.WithValue(ctx, types.ProjectScope, project)
 }
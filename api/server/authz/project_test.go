@@ -1,8 +1,10 @@
 package authz_test
 
 import (
+	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/porter-dev/porter/api/server/authz"
 	"github.com/porter-dev/porter/api/server/handlers/project"
@@ -70,6 +72,79 @@ func TestProjectMiddlewareFailedRead(t *testing.T) {
 	apitest.AssertResponseInternalServerError(t, rr)
 }
 
+func TestProjectMiddlewareRequireMFA(t *testing.T) {
+	config, handler, next := loadProjectHandlers(t)
+
+	user := apitest.CreateTestUser(t, config, true)
+	_, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name:       "test-project",
+		RequireMFA: true,
+	}, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/projects/1", nil)
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithRequestScopes(t, req, map[types.PermissionScope]*types.RequestAction{
+		types.ProjectScope: {
+			Verb: types.APIVerbCreate,
+			Resource: types.NameOrUInt{
+				UInt: 1,
+			},
+		},
+	})
+
+	handler.ServeHTTP(rr, req)
+	assert.False(t, next.WasCalled, "next handler should not have been called")
+	apitest.AssertResponseError(t, rr, http.StatusForbidden, &types.ExternalError{
+		Error: "this project requires multi-factor authentication: enable it in your account settings to continue",
+	})
+
+	// once the user enables a second factor, a session which did not complete it is still rejected
+	_, err = config.Repo.MFA().CreateUserTOTP(&models.UserTOTP{
+		UserID:  user.ID,
+		Secret:  []byte("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"),
+		Enabled: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr = apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/projects/1", nil)
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithRequestScopes(t, req, map[types.PermissionScope]*types.RequestAction{
+		types.ProjectScope: {
+			Verb: types.APIVerbCreate,
+			Resource: types.NameOrUInt{
+				UInt: 1,
+			},
+		},
+	})
+
+	handler.ServeHTTP(rr, req)
+	assert.False(t, next.WasCalled, "next handler should not have been called")
+	apitest.AssertResponseError(t, rr, http.StatusForbidden, &types.ExternalError{
+		Error: "this project requires multi-factor authentication: log in again with your second factor to continue",
+	})
+
+	// a session which completed the second factor is allowed
+	req, rr = apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/projects/1", nil)
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = req.WithContext(context.WithValue(req.Context(), types.MFAVerifiedAtCtxKey, time.Now()))
+	req = apitest.WithRequestScopes(t, req, map[types.PermissionScope]*types.RequestAction{
+		types.ProjectScope: {
+			Verb: types.APIVerbCreate,
+			Resource: types.NameOrUInt{
+				UInt: 1,
+			},
+		},
+	})
+
+	handler.ServeHTTP(rr, req)
+	assert.True(t, next.WasCalled, "next handler should have been called")
+}
+
 func loadProjectHandlers(
 	t *testing.T,
 	failingRepoMethods ...string,
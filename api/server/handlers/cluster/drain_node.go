@@ -0,0 +1,122 @@
+package cluster
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/server/shared/websocket"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/nodes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// defaultNodeMaintenanceTimeout bounds node maintenance operations which do not set a timeout
+const defaultNodeMaintenanceTimeout = 10 * time.Minute
+
+// DrainNodeHandler handles the /nodes/{node_name}/drain endpoint
+type DrainNodeHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewDrainNodeHandler returns a new DrainNodeHandler
+func NewDrainNodeHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *DrainNodeHandler {
+	return &DrainNodeHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ServeHTTP cordons a node and evicts its pods, streaming the progress of the drain over the request's websocket.
+// The last message is either a completed or a failed event.
+func (c *DrainNodeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-drain-node")
+	defer span.End()
+
+	safeRW := ctx.Value(types.RequestCtxWebsocketKey).(*websocket.WebsocketSafeReadWriter)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	name, reqErr := requestutils.GetURLParamString(r, types.URLParamNodeName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving node name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.DrainNodeRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	timeout := nodeMaintenanceTimeout(request.TimeoutSeconds)
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "node-name", Value: name},
+		telemetry.AttributeKV{Key: "timeout-seconds", Value: int(timeout.Seconds())},
+		telemetry.AttributeKV{Key: "force", Value: request.Force},
+	)
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting k8s agent")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	_, err = nodes.DrainNode(ctx, agent.Clientset, name, nodes.DrainOptions{
+		Timeout:  timeout,
+		Force:    request.Force,
+		Progress: writeNodeMaintenanceEvent(safeRW),
+	})
+	if err != nil {
+		_ = telemetry.Error(ctx, span, err, "error draining node")
+	}
+
+	writeNodeMaintenanceResult(safeRW, name, err)
+}
+
+// nodeMaintenanceTimeout returns the timeout of a node maintenance operation, which defaults if unset
+func nodeMaintenanceTimeout(timeoutSeconds int) time.Duration {
+	if timeoutSeconds == 0 {
+		return defaultNodeMaintenanceTimeout
+	}
+
+	return time.Duration(timeoutSeconds) * time.Second
+}
+
+// writeNodeMaintenanceEvent returns a progress callback which writes each event to the websocket. Write errors are
+// ignored, since the operation must run to completion or time out even if the client disconnects.
+func writeNodeMaintenanceEvent(safeRW *websocket.WebsocketSafeReadWriter) func(types.NodeMaintenanceEvent) {
+	return func(event types.NodeMaintenanceEvent) {
+		_ = safeRW.WriteJSON(event)
+	}
+}
+
+// writeNodeMaintenanceResult writes the final completed or failed event of an operation to the websocket
+func writeNodeMaintenanceResult(safeRW *websocket.WebsocketSafeReadWriter, node string, err error) {
+	event := types.NodeMaintenanceEvent{
+		Type: types.NodeMaintenanceEventType_Completed,
+		Time: time.Now().UTC(),
+		Node: node,
+	}
+
+	if err != nil {
+		event.Type = types.NodeMaintenanceEventType_Failed
+		event.Message = err.Error()
+	}
+
+	_ = safeRW.WriteJSON(event)
+}
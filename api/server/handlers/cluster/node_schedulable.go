@@ -0,0 +1,93 @@
+package cluster
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/nodes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// SetNodeSchedulableHandler handles the /nodes/{node_name}/cordon and /nodes/{node_name}/uncordon endpoints
+type SetNodeSchedulableHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+
+	schedulable bool
+}
+
+// NewCordonNodeHandler returns a handler which stops new pods from being scheduled onto a node
+func NewCordonNodeHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *SetNodeSchedulableHandler {
+	return &SetNodeSchedulableHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+		schedulable:           false,
+	}
+}
+
+// NewUncordonNodeHandler returns a handler which allows new pods to be scheduled onto a node again
+func NewUncordonNodeHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *SetNodeSchedulableHandler {
+	return &SetNodeSchedulableHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+		schedulable:           true,
+	}
+}
+
+// ServeHTTP cordons or uncordons the node
+func (c *SetNodeSchedulableHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-set-node-schedulable")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	name, reqErr := requestutils.GetURLParamString(r, types.URLParamNodeName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving node name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "node-name", Value: name},
+		telemetry.AttributeKV{Key: "schedulable", Value: c.schedulable},
+	)
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting k8s agent")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	node, err := nodes.SetNodeSchedulable(ctx, agent.Clientset, name, c.schedulable)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if k8serrors.IsNotFound(err) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error updating node")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	c.WriteResult(w, r, &types.NodeSchedulingResponse{
+		Name:          node.Name,
+		Unschedulable: node.Spec.Unschedulable,
+	})
+}
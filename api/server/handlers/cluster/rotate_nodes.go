@@ -0,0 +1,122 @@
+package cluster
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/websocket"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/nodes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/telemetry"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// RotateNodesHandler handles the /nodes/rotate endpoint
+type RotateNodesHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewRotateNodesHandler returns a new RotateNodesHandler
+func NewRotateNodesHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *RotateNodesHandler {
+	return &RotateNodesHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ServeHTTP drains the given nodes one at a time, waiting for the evicted app pods of each node to be ready on other
+// nodes before draining the next one. Progress is streamed over the request's websocket, and the last message is
+// either a completed or a failed event.
+func (c *RotateNodesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-rotate-nodes")
+	defer span.End()
+
+	safeRW := ctx.Value(types.RequestCtxWebsocketKey).(*websocket.WebsocketSafeReadWriter)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.RotateNodesRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	if (len(request.NodeNames) == 0) == (request.NodeSelector == "") {
+		err := telemetry.Error(ctx, span, nil, "exactly one of node names and node selector must be set")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting k8s agent")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	names := request.NodeNames
+	if request.NodeSelector != "" {
+		if _, err := labels.Parse(request.NodeSelector); err != nil {
+			err = telemetry.Error(ctx, span, err, "invalid node selector")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		nodeList, err := agent.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: request.NodeSelector})
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error listing nodes")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		for _, node := range nodeList.Items {
+			names = append(names, node.Name)
+		}
+		sort.Strings(names)
+	}
+
+	timeout := nodeMaintenanceTimeout(request.TimeoutSeconds)
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "node-count", Value: len(names)},
+		telemetry.AttributeKV{Key: "node-selector", Value: request.NodeSelector},
+		telemetry.AttributeKV{Key: "timeout-seconds", Value: int(timeout.Seconds())},
+		telemetry.AttributeKV{Key: "force", Value: request.Force},
+	)
+
+	if len(names) == 0 {
+		writeNodeMaintenanceResult(safeRW, "", errors.New("no nodes match the node selector"))
+		return
+	}
+
+	// only app pods must be ready elsewhere, since system pods may be pinned to the rotated node group
+	appPods, _ := labels.Parse(porter_app.LabelKey_AppName)
+
+	err = nodes.RotateNodes(ctx, agent.Clientset, names, nodes.RotateOptions{
+		DrainOptions: nodes.DrainOptions{
+			Timeout:  timeout,
+			Force:    request.Force,
+			Progress: writeNodeMaintenanceEvent(safeRW),
+		},
+		ReadySelector: appPods,
+	})
+	if err != nil {
+		_ = telemetry.Error(ctx, span, err, "error rotating nodes")
+	}
+
+	writeNodeMaintenanceResult(safeRW, "", err)
+}
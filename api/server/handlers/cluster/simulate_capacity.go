@@ -0,0 +1,150 @@
+package cluster
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/nodes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/telemetry"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// SimulateCapacityHandler handles the POST /clusters/{cluster_id}/capacity/simulate endpoint
+type SimulateCapacityHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewSimulateCapacityHandler returns a new SimulateCapacityHandler
+func NewSimulateCapacityHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *SimulateCapacityHandler {
+	return &SimulateCapacityHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ServeHTTP simulates scheduling the requested pods onto the current nodes of the cluster, and reports the pods which
+// would not fit along with the extra nodes needed to fit them. If an app is given, its current pods are replaced by
+// the requested pods, and pods without scheduling constraints inherit those of the deployed service of the same name.
+func (c *SimulateCapacityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-simulate-capacity")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.SimulateClusterCapacityRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: request.AppName},
+		telemetry.AttributeKV{Key: "deployment-target-id", Value: request.DeploymentTargetID},
+		telemetry.AttributeKV{Key: "pod-group-count", Value: len(request.Pods)},
+	)
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting k8s agent")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	var replaced labels.Selector
+	deployed := make(map[string]corev1.PodSpec)
+
+	if request.AppName != "" {
+		appLabels := labels.Set{porter_app.LabelKey_AppName: request.AppName}
+		if request.DeploymentTargetID != "" {
+			appLabels[porter_app.LabelKey_DeploymentTargetID] = request.DeploymentTargetID
+		}
+		replaced = labels.SelectorFromSet(appLabels)
+
+		deployed, err = deployedServicePodSpecs(ctx, agent.Clientset, replaced)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error reading deployed services")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	requests := make([]nodes.CapacityRequest, 0, len(request.Pods))
+	for _, group := range request.Pods {
+		req := nodes.CapacityRequest{
+			Name:         group.Name,
+			Replicas:     group.Replicas,
+			CPUMillis:    group.CPUMillis,
+			MemoryBytes:  group.MemoryBytes,
+			GPU:          group.GPU,
+			NodeSelector: group.NodeSelector,
+		}
+
+		for _, toleration := range group.Tolerations {
+			req.Tolerations = append(req.Tolerations, corev1.Toleration{
+				Key:      toleration.Key,
+				Operator: corev1.TolerationOperator(toleration.Operator),
+				Value:    toleration.Value,
+				Effect:   corev1.TaintEffect(toleration.Effect),
+			})
+		}
+
+		if spec, ok := deployed[group.Name]; ok && len(req.NodeSelector) == 0 && len(req.Tolerations) == 0 {
+			req.NodeSelector = spec.NodeSelector
+			req.Tolerations = spec.Tolerations
+		}
+
+		requests = append(requests, req)
+	}
+
+	res, err := nodes.SimulateClusterCapacity(ctx, agent.Clientset, replaced, requests)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error simulating cluster capacity")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "fits", Value: res.Fits},
+		telemetry.AttributeKV{Key: "extra-node-types", Value: len(res.ExtraNodes)},
+	)
+
+	c.WriteResult(w, r, res)
+}
+
+// deployedServicePodSpecs returns the pod spec of each deployed service of an app, keyed by service name
+func deployedServicePodSpecs(ctx context.Context, clientset kubernetes.Interface, appSelector labels.Selector) (map[string]corev1.PodSpec, error) {
+	deployments, err := clientset.AppsV1().Deployments("").List(ctx, metav1.ListOptions{LabelSelector: appSelector.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	specs := make(map[string]corev1.PodSpec)
+	for _, deployment := range deployments.Items {
+		if _, ok := deployment.Labels[porter_app.LabelKey_Canary]; ok {
+			continue
+		}
+
+		if serviceName, ok := deployment.Labels[porter_app.LabelKey_ServiceName]; ok {
+			specs[serviceName] = deployment.Spec.Template.Spec
+		}
+	}
+
+	return specs, nil
+}
@@ -0,0 +1,117 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/datastore"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// readBackupSource reads the datastore named in the request URL for use in a backup operation. It
+// returns an api error which can be passed directly to HandleAPIError if the datastore cannot be backed up.
+func readBackupSource(ctx context.Context, conf *config.Config, project *models.Project, r *http.Request) (*models.Datastore, apierrors.RequestError) {
+	ctx, span := telemetry.NewSpan(ctx, "read-backup-source")
+	defer span.End()
+
+	if conf.DatastoreBackupProvider == nil {
+		err := telemetry.Error(ctx, span, datastore.ErrBackupsNotEnabled, "no datastore backup provider is configured")
+		return nil, apierrors.NewErrPassThroughToClient(err, http.StatusNotImplemented)
+	}
+
+	datastoreName, reqErr := requestutils.GetURLParamString(r, types.URLParamDatastoreName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, nil, "error parsing datastore name")
+		return nil, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest)
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "datastore-name", Value: datastoreName})
+
+	datastoreRecord, err := conf.Repo.Datastore().GetByProjectIDAndName(ctx, project.ID, datastoreName)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "datastore record not found")
+		return nil, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError)
+	}
+
+	if datastoreRecord == nil || datastoreRecord.ID == uuid.Nil {
+		err = telemetry.Error(ctx, span, nil, "datastore record does not exist")
+		return nil, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound)
+	}
+
+	return datastoreRecord, nil
+}
+
+// createBackupTarget inserts the record for a datastore that is created from a backup of source.
+// The record starts in the CREATING state and inherits the type, engine and cloud account of the source.
+func createBackupTarget(ctx context.Context, conf *config.Config, source *models.Datastore, name string) (*models.Datastore, apierrors.RequestError) {
+	ctx, span := telemetry.NewSpan(ctx, "create-backup-target")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "target-name", Value: name})
+
+	existing, err := conf.Repo.Datastore().GetByProjectIDAndName(ctx, source.ProjectID, name)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error checking for existing datastore")
+		return nil, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError)
+	}
+
+	if existing != nil && existing.ID != uuid.Nil {
+		err = telemetry.Error(ctx, span, nil, "a datastore with this name already exists")
+		return nil, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest)
+	}
+
+	target, err := conf.Repo.Datastore().Insert(ctx, &models.Datastore{
+		ProjectID:                         source.ProjectID,
+		Name:                              name,
+		CloudProvider:                     source.CloudProvider,
+		CloudProviderCredentialIdentifier: source.CloudProviderCredentialIdentifier,
+		Type:                              source.Type,
+		Engine:                            source.Engine,
+		Status:                            models.DatastoreStatus_Creating,
+		OnManagementCluster:               source.OnManagementCluster,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error inserting datastore record")
+		return nil, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError)
+	}
+
+	return target, nil
+}
+
+func toBackupTarget(record *models.Datastore) datastore.BackupTarget {
+	return datastore.BackupTarget{
+		ProjectID:                         record.ProjectID,
+		DatastoreID:                       record.ID,
+		Name:                              record.Name,
+		Type:                              record.Type,
+		Engine:                            record.Engine,
+		CloudProviderCredentialIdentifier: record.CloudProviderCredentialIdentifier,
+	}
+}
+
+func toSnapshotType(snapshot datastore.Snapshot) types.DatastoreSnapshot {
+	return types.DatastoreSnapshot{
+		ID:            snapshot.ID,
+		DatastoreName: snapshot.DatastoreName,
+		Type:          string(snapshot.Type),
+		Status:        string(snapshot.Status),
+		SizeGigabytes: snapshot.SizeGigabytes,
+		CreatedAt:     snapshot.CreatedAtUTC,
+	}
+}
+
+// backupProviderStatusCode returns the status code that should be returned to the client for
+// an error from the backup provider
+func backupProviderStatusCode(err error) int {
+	if errors.Is(err, datastore.ErrBackupsNotSupported) || errors.Is(err, datastore.ErrRestoreTimeOutOfRange) {
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
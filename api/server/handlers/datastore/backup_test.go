@@ -0,0 +1,341 @@
+package datastore_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers/datastore"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	internaldatastore "github.com/porter-dev/porter/internal/datastore"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+func TestDatastoreBackupsNotEnabled(t *testing.T) {
+	conf := apitest.LoadConfig(t)
+	proj := createTestDatastore(t, conf, "db", models.DatastoreStatus_Available)
+
+	tests := map[string]http.Handler{
+		"list snapshots":  datastore.NewListDatastoreSnapshotsHandler(conf, resultWriter(conf)),
+		"create snapshot": datastore.NewCreateDatastoreSnapshotHandler(conf, resultWriter(conf)),
+		"restore":         datastore.NewRestoreDatastoreHandler(conf, decoderValidator(conf), resultWriter(conf)),
+		"clone":           datastore.NewCloneDatastoreHandler(conf, decoderValidator(conf), resultWriter(conf)),
+	}
+
+	requests := map[string]interface{}{
+		"restore": &types.RestoreDatastoreRequest{Name: "db-restored", SnapshotID: "snapshot"},
+		"clone":   &types.CloneDatastoreRequest{DeploymentTargetID: "preview"},
+	}
+
+	for name, handler := range tests {
+		t.Run(name, func(t *testing.T) {
+			rr := serve(t, handler, proj, "db", requests[name])
+
+			apitest.AssertResponseError(t, rr, http.StatusNotImplemented, &types.ExternalError{
+				Error: internaldatastore.ErrBackupsNotEnabled.Error(),
+			})
+		})
+	}
+
+	datastores, err := conf.Repo.Datastore().ListByProjectID(context.Background(), proj.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(datastores) != 1 {
+		t.Errorf("expected no datastores to be created when backups are not enabled, got %d", len(datastores))
+	}
+}
+
+func TestCreateAndListDatastoreSnapshots(t *testing.T) {
+	conf, provider := loadBackupConfig(t)
+	proj := createTestDatastore(t, conf, "db", models.DatastoreStatus_Available)
+
+	rr := serve(t, datastore.NewCreateDatastoreSnapshotHandler(conf, resultWriter(conf)), proj, "db", nil)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	created := &types.CreateDatastoreSnapshotResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(created); err != nil {
+		t.Fatal(err)
+	}
+
+	if created.Snapshot.ID == "" {
+		t.Fatal("expected the snapshot to have an id")
+	}
+
+	if created.Snapshot.DatastoreName != "db" ||
+		created.Snapshot.Type != string(internaldatastore.SnapshotType_OnDemand) ||
+		created.Snapshot.Status != string(internaldatastore.SnapshotStatus_Available) ||
+		!created.Snapshot.CreatedAt.Equal(provider.Now()) {
+		t.Errorf("unexpected snapshot %+v", created.Snapshot)
+	}
+
+	rr = serve(t, datastore.NewListDatastoreSnapshotsHandler(conf, resultWriter(conf)), proj, "db", nil)
+
+	listed := &types.ListDatastoreSnapshotsResponse{}
+	apitest.AssertResponseExpected(t, rr, &types.ListDatastoreSnapshotsResponse{
+		Snapshots: []types.DatastoreSnapshot{created.Snapshot},
+		RestoreWindow: &types.DatastoreRestoreWindow{
+			Earliest: provider.Now().UTC(),
+			Latest:   provider.Now().UTC(),
+		},
+	}, listed)
+}
+
+func TestCreateDatastoreSnapshotNotAvailable(t *testing.T) {
+	conf, provider := loadBackupConfig(t)
+	proj := createTestDatastore(t, conf, "db", models.DatastoreStatus_Creating)
+
+	rr := serve(t, datastore.NewCreateDatastoreSnapshotHandler(conf, resultWriter(conf)), proj, "db", nil)
+
+	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
+		Error: "datastore must be available to take a snapshot",
+	})
+
+	snapshots, _ := provider.ListSnapshots(context.Background(), internaldatastore.BackupTarget{})
+	if len(snapshots) != 0 {
+		t.Errorf("expected no snapshots to be taken, got %d", len(snapshots))
+	}
+}
+
+func TestDatastoreNotFound(t *testing.T) {
+	conf, _ := loadBackupConfig(t)
+	proj := createTestDatastore(t, conf, "db", models.DatastoreStatus_Available)
+
+	rr := serve(t, datastore.NewListDatastoreSnapshotsHandler(conf, resultWriter(conf)), proj, "other-db", nil)
+
+	apitest.AssertResponseError(t, rr, http.StatusNotFound, &types.ExternalError{
+		Error: "datastore record does not exist",
+	})
+}
+
+func TestRestoreDatastore(t *testing.T) {
+	conf, provider := loadBackupConfig(t)
+	proj := createTestDatastore(t, conf, "db", models.DatastoreStatus_Available)
+	source, _ := conf.Repo.Datastore().GetByProjectIDAndName(context.Background(), proj.ID, "db")
+
+	snapshot, err := provider.CreateSnapshot(context.Background(), internaldatastore.BackupTarget{DatastoreID: source.ID, Name: "db"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := serve(t, datastore.NewRestoreDatastoreHandler(conf, decoderValidator(conf), resultWriter(conf)), proj, "db", &types.RestoreDatastoreRequest{
+		Name:       "db-restored",
+		SnapshotID: snapshot.ID,
+	})
+
+	apitest.AssertResponseExpected(t, rr, &types.RestoreDatastoreResponse{Name: "db-restored"}, &types.RestoreDatastoreResponse{})
+
+	target, err := conf.Repo.Datastore().GetByProjectIDAndName(context.Background(), proj.ID, "db-restored")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if target.Status != models.DatastoreStatus_Creating || target.Engine != source.Engine || target.Type != source.Type {
+		t.Errorf("expected the restored datastore to be created from the source, got %+v", target)
+	}
+
+	if len(provider.Restores) != 1 || provider.Restores[0].TargetID != target.ID || provider.Restores[0].SnapshotID != snapshot.ID {
+		t.Errorf("expected the snapshot to be restored into the new datastore, got %+v", provider.Restores)
+	}
+
+	if source.Status != models.DatastoreStatus_Available {
+		t.Errorf("expected the source datastore not to be modified, got status %s", source.Status)
+	}
+}
+
+func TestRestoreDatastoreInvalid(t *testing.T) {
+	pointInTime := time.Now().Add(-time.Hour)
+
+	tests := map[string]struct {
+		req       *types.RestoreDatastoreRequest
+		expStatus int
+		expError  string
+	}{
+		"snapshot and point in time": {
+			req:       &types.RestoreDatastoreRequest{Name: "db-restored", SnapshotID: "snapshot", PointInTime: &pointInTime},
+			expStatus: http.StatusBadRequest,
+			expError:  "exactly one of snapshot_id and point_in_time must be set",
+		},
+		"neither snapshot nor point in time": {
+			req:       &types.RestoreDatastoreRequest{Name: "db-restored"},
+			expStatus: http.StatusBadRequest,
+			expError:  "exactly one of snapshot_id and point_in_time must be set",
+		},
+		"point in time outside the restore window": {
+			req:       &types.RestoreDatastoreRequest{Name: "db-restored", PointInTime: &pointInTime},
+			expStatus: http.StatusBadRequest,
+			expError:  internaldatastore.ErrRestoreTimeOutOfRange.Error(),
+		},
+		"name already in use": {
+			req:       &types.RestoreDatastoreRequest{Name: "db", SnapshotID: "snapshot"},
+			expStatus: http.StatusBadRequest,
+			expError:  "a datastore with this name already exists",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			conf, provider := loadBackupConfig(t)
+			proj := createTestDatastore(t, conf, "db", models.DatastoreStatus_Available)
+			source, _ := conf.Repo.Datastore().GetByProjectIDAndName(context.Background(), proj.ID, "db")
+
+			if _, err := provider.CreateSnapshot(context.Background(), internaldatastore.BackupTarget{DatastoreID: source.ID, Name: "db"}); err != nil {
+				t.Fatal(err)
+			}
+
+			rr := serve(t, datastore.NewRestoreDatastoreHandler(conf, decoderValidator(conf), resultWriter(conf)), proj, "db", tc.req)
+
+			apitest.AssertResponseError(t, rr, tc.expStatus, &types.ExternalError{Error: tc.expError})
+
+			if len(provider.Restores) != 0 {
+				t.Errorf("expected no restore to be started, got %+v", provider.Restores)
+			}
+		})
+	}
+}
+
+func TestRestoreDatastoreProviderError(t *testing.T) {
+	conf, provider := loadBackupConfig(t)
+	proj := createTestDatastore(t, conf, "db", models.DatastoreStatus_Available)
+
+	rr := serve(t, datastore.NewRestoreDatastoreHandler(conf, decoderValidator(conf), resultWriter(conf)), proj, "db", &types.RestoreDatastoreRequest{
+		Name:       "db-restored",
+		SnapshotID: "does-not-exist",
+	})
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, rr.Code)
+	}
+
+	// the record for a restore which failed to start is removed, so that the name can be used again
+	target, err := conf.Repo.Datastore().GetByProjectIDAndName(context.Background(), proj.ID, "db-restored")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if target.Name != "" {
+		t.Errorf("expected the datastore record to be removed, got %+v", target)
+	}
+
+	if len(provider.Restores) != 0 {
+		t.Errorf("expected no restore to be recorded, got %+v", provider.Restores)
+	}
+}
+
+func TestCloneDatastore(t *testing.T) {
+	conf, provider := loadBackupConfig(t)
+	proj := createTestDatastore(t, conf, "db", models.DatastoreStatus_Available)
+
+	preview, err := conf.Repo.DeploymentTarget().CreateDeploymentTarget(&models.DeploymentTarget{
+		ProjectID:  int(proj.ID),
+		ClusterID:  1,
+		VanityName: "pr-42",
+		Preview:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := serve(t, datastore.NewCloneDatastoreHandler(conf, decoderValidator(conf), resultWriter(conf)), proj, "db", &types.CloneDatastoreRequest{
+		DeploymentTargetID: "pr-42",
+	})
+
+	apitest.AssertResponseExpected(t, rr, &types.CloneDatastoreResponse{
+		Name:               "db-pr-42",
+		DeploymentTargetID: preview.ID.String(),
+	}, &types.CloneDatastoreResponse{})
+
+	if len(provider.Clones) != 1 || provider.Clones[0].TargetName != "db-pr-42" || provider.Clones[0].DeploymentTargetID != preview.ID {
+		t.Errorf("expected the datastore to be cloned into the preview environment, got %+v", provider.Clones)
+	}
+}
+
+func TestCloneDatastoreNotPreview(t *testing.T) {
+	conf, provider := loadBackupConfig(t)
+	proj := createTestDatastore(t, conf, "db", models.DatastoreStatus_Available)
+
+	if _, err := conf.Repo.DeploymentTarget().CreateDeploymentTarget(&models.DeploymentTarget{
+		ProjectID:  int(proj.ID),
+		ClusterID:  1,
+		VanityName: "production",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	rr := serve(t, datastore.NewCloneDatastoreHandler(conf, decoderValidator(conf), resultWriter(conf)), proj, "db", &types.CloneDatastoreRequest{
+		DeploymentTargetID: "production",
+	})
+
+	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
+		Error: "datastores can only be cloned into preview environments",
+	})
+
+	if len(provider.Clones) != 0 {
+		t.Errorf("expected no clone to be started, got %+v", provider.Clones)
+	}
+}
+
+func loadBackupConfig(t *testing.T) (*config.Config, *internaldatastore.FakeBackupProvider) {
+	conf := apitest.LoadConfig(t)
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	provider := internaldatastore.NewFakeBackupProvider()
+	provider.Now = func() time.Time { return now }
+
+	conf.DatastoreBackupProvider = provider
+
+	return conf, provider
+}
+
+func createTestDatastore(t *testing.T, conf *config.Config, name string, status models.DatastoreStatus) *models.Project {
+	proj, err := conf.Repo.Project().CreateProject(&models.Project{Name: "test-project"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = conf.Repo.Datastore().Insert(context.Background(), &models.Datastore{
+		ProjectID:                         proj.ID,
+		Name:                              name,
+		CloudProvider:                     "AWS",
+		CloudProviderCredentialIdentifier: "arn:aws:iam::123456789012:role/porter-manager",
+		Type:                              "RDS",
+		Engine:                            "POSTGRES",
+		Status:                            status,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return proj
+}
+
+func serve(t *testing.T, handler http.Handler, proj *models.Project, datastoreName string, body interface{}) *httptest.ResponseRecorder {
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/projects/1/datastores/"+datastoreName, body)
+	req = apitest.WithProject(t, req, proj)
+	req = apitest.WithURLParams(t, req, map[string]string{
+		string(types.URLParamDatastoreName): datastoreName,
+	})
+
+	handler.ServeHTTP(rr, req)
+
+	return rr
+}
+
+func resultWriter(conf *config.Config) shared.ResultWriter {
+	return shared.NewDefaultResultWriter(conf.Logger, conf.Alerter)
+}
+
+func decoderValidator(conf *config.Config) shared.RequestDecoderValidator {
+	return shared.NewDefaultRequestDecoderValidator(conf.Logger, conf.Alerter)
+}
@@ -0,0 +1,101 @@
+package datastore
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/datastore"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// CloneDatastoreHandler is a struct for cloning a datastore into a preview environment
+type CloneDatastoreHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewCloneDatastoreHandler returns a CloneDatastoreHandler
+func NewCloneDatastoreHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CloneDatastoreHandler {
+	return &CloneDatastoreHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP clones the datastore in the given project into a new datastore for a preview environment
+func (h *CloneDatastoreHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-clone-datastore")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.CloneDatastoreRequest{}
+	if ok := h.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding clone datastore request")
+		h.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "deployment-target-id", Value: request.DeploymentTargetID})
+
+	source, apiErr := readBackupSource(ctx, h.Config(), project, r)
+	if apiErr != nil {
+		h.HandleAPIError(w, r, apiErr)
+		return
+	}
+
+	deploymentTarget, err := h.Repo().DeploymentTarget().DeploymentTarget(project.ID, request.DeploymentTargetID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error reading deployment target")
+		h.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	// clones contain production data, so they may only be attached to preview environments
+	if !deploymentTarget.Preview {
+		err = telemetry.Error(ctx, span, nil, "datastores can only be cloned into preview environments")
+		h.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	name := request.Name
+	if name == "" {
+		name = fmt.Sprintf("%s-%s", source.Name, deploymentTarget.VanityName)
+	}
+
+	target, apiErr := createBackupTarget(ctx, h.Config(), source, name)
+	if apiErr != nil {
+		h.HandleAPIError(w, r, apiErr)
+		return
+	}
+
+	err = h.Config().DatastoreBackupProvider.Clone(ctx, datastore.CloneInput{
+		Source:             toBackupTarget(source),
+		TargetID:           target.ID,
+		TargetName:         target.Name,
+		DeploymentTargetID: deploymentTarget.ID,
+	})
+	if err != nil {
+		// the datastore will never become available, so remove the record to free up the name
+		_, _ = h.Repo().Datastore().Delete(ctx, target)
+
+		h.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			telemetry.Error(ctx, span, err, "error cloning datastore"),
+			backupProviderStatusCode(err),
+		))
+		return
+	}
+
+	h.WriteResult(w, r, types.CloneDatastoreResponse{
+		Name:               target.Name,
+		DeploymentTargetID: deploymentTarget.ID.String(),
+	})
+}
@@ -0,0 +1,112 @@
+package datastore
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/datastore"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// RestoreDatastoreHandler is a struct for restoring a datastore into a new datastore
+type RestoreDatastoreHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewRestoreDatastoreHandler returns a RestoreDatastoreHandler
+func NewRestoreDatastoreHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *RestoreDatastoreHandler {
+	return &RestoreDatastoreHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP restores a snapshot or point in time of the datastore in the given project into a new datastore.
+// The source datastore is never modified.
+func (h *RestoreDatastoreHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-restore-datastore")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.RestoreDatastoreRequest{}
+	if ok := h.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding restore datastore request")
+		h.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	if (request.SnapshotID == "") == (request.PointInTime == nil) {
+		err := telemetry.Error(ctx, span, nil, "exactly one of snapshot_id and point_in_time must be set")
+		h.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "target-name", Value: request.Name},
+		telemetry.AttributeKV{Key: "snapshot-id", Value: request.SnapshotID},
+	)
+
+	source, apiErr := readBackupSource(ctx, h.Config(), project, r)
+	if apiErr != nil {
+		h.HandleAPIError(w, r, apiErr)
+		return
+	}
+
+	input := datastore.RestoreInput{
+		Source:     toBackupTarget(source),
+		SnapshotID: request.SnapshotID,
+	}
+
+	if request.PointInTime != nil {
+		input.PointInTimeUTC = request.PointInTime.UTC()
+
+		window, err := h.Config().DatastoreBackupProvider.RestoreWindow(ctx, input.Source)
+		if err != nil {
+			h.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				telemetry.Error(ctx, span, err, "error reading restore window"),
+				backupProviderStatusCode(err),
+			))
+			return
+		}
+
+		if !window.Contains(input.PointInTimeUTC) {
+			err := telemetry.Error(ctx, span, datastore.ErrRestoreTimeOutOfRange, "point in time is not restorable")
+			h.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+	}
+
+	target, apiErr := createBackupTarget(ctx, h.Config(), source, request.Name)
+	if apiErr != nil {
+		h.HandleAPIError(w, r, apiErr)
+		return
+	}
+
+	input.TargetID = target.ID
+	input.TargetName = target.Name
+
+	err := h.Config().DatastoreBackupProvider.Restore(ctx, input)
+	if err != nil {
+		// the datastore will never become available, so remove the record to free up the name
+		_, _ = h.Repo().Datastore().Delete(ctx, target)
+
+		h.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			telemetry.Error(ctx, span, err, "error restoring datastore"),
+			backupProviderStatusCode(err),
+		))
+		return
+	}
+
+	h.WriteResult(w, r, types.RestoreDatastoreResponse{
+		Name: target.Name,
+	})
+}
@@ -0,0 +1,122 @@
+package datastore
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// ListDatastoreSnapshotsHandler is a struct for listing the snapshots of a datastore
+type ListDatastoreSnapshotsHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewListDatastoreSnapshotsHandler returns a ListDatastoreSnapshotsHandler
+func NewListDatastoreSnapshotsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListDatastoreSnapshotsHandler {
+	return &ListDatastoreSnapshotsHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP lists the snapshots and restorable window of the datastore in the given project
+func (h *ListDatastoreSnapshotsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-datastore-snapshots")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	datastoreRecord, apiErr := readBackupSource(ctx, h.Config(), project, r)
+	if apiErr != nil {
+		h.HandleAPIError(w, r, apiErr)
+		return
+	}
+
+	target := toBackupTarget(datastoreRecord)
+
+	snapshots, err := h.Config().DatastoreBackupProvider.ListSnapshots(ctx, target)
+	if err != nil {
+		h.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			telemetry.Error(ctx, span, err, "error listing datastore snapshots"),
+			backupProviderStatusCode(err),
+		))
+		return
+	}
+
+	resp := types.ListDatastoreSnapshotsResponse{
+		Snapshots: make([]types.DatastoreSnapshot, 0, len(snapshots)),
+	}
+
+	for _, snapshot := range snapshots {
+		resp.Snapshots = append(resp.Snapshots, toSnapshotType(snapshot))
+	}
+
+	// the restore window is unavailable until the first backup has been taken, so it is omitted rather than treated as an error
+	window, err := h.Config().DatastoreBackupProvider.RestoreWindow(ctx, target)
+	if err == nil {
+		resp.RestoreWindow = &types.DatastoreRestoreWindow{
+			Earliest: window.EarliestUTC,
+			Latest:   window.LatestUTC,
+		}
+	}
+
+	h.WriteResult(w, r, resp)
+}
+
+// CreateDatastoreSnapshotHandler is a struct for triggering an on-demand snapshot of a datastore
+type CreateDatastoreSnapshotHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewCreateDatastoreSnapshotHandler returns a CreateDatastoreSnapshotHandler
+func NewCreateDatastoreSnapshotHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *CreateDatastoreSnapshotHandler {
+	return &CreateDatastoreSnapshotHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP triggers an on-demand snapshot of the datastore in the given project
+func (h *CreateDatastoreSnapshotHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-create-datastore-snapshot")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	datastoreRecord, apiErr := readBackupSource(ctx, h.Config(), project, r)
+	if apiErr != nil {
+		h.HandleAPIError(w, r, apiErr)
+		return
+	}
+
+	if datastoreRecord.Status != models.DatastoreStatus_Available {
+		err := telemetry.Error(ctx, span, nil, "datastore must be available to take a snapshot")
+		h.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	snapshot, err := h.Config().DatastoreBackupProvider.CreateSnapshot(ctx, toBackupTarget(datastoreRecord))
+	if err != nil {
+		h.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			telemetry.Error(ctx, span, err, "error creating datastore snapshot"),
+			backupProviderStatusCode(err),
+		))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "snapshot-id", Value: snapshot.ID})
+
+	h.WriteResult(w, r, types.CreateDatastoreSnapshotResponse{
+		Snapshot: toSnapshotType(snapshot),
+	})
+}
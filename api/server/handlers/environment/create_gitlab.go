@@ -0,0 +1,139 @@
+package environment
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/integrations/ci/gitlab"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	gogitlab "github.com/xanzy/go-gitlab"
+	"gorm.io/gorm"
+)
+
+// CreateGitlabEnvironmentHandler creates a preview environment for the merge requests of a GitLab project
+type CreateGitlabEnvironmentHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewCreateGitlabEnvironmentHandler returns a CreateGitlabEnvironmentHandler
+func NewCreateGitlabEnvironmentHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateGitlabEnvironmentHandler {
+	return &CreateGitlabEnvironmentHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP adds a merge request webhook to the GitLab project and creates the environment. Merge requests are previewed
+// as they are opened, so GitLab environments are always in auto mode.
+func (c *CreateGitlabEnvironmentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-create-gitlab-environment")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.CreateGitlabEnvironmentRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "gitlab-integration-id", Value: request.GitlabIntegrationID},
+		telemetry.AttributeKV{Key: "git-repo-path", Value: request.GitRepoPath},
+	)
+
+	owner, name, err := gitlab.SplitProjectPath(request.GitRepoPath)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "invalid gitlab project path")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	_, err = c.Repo().Environment().ReadEnvironmentByOwnerRepoName(project.ID, cluster.ID, owner, name)
+	if err == nil {
+		err := telemetry.Error(ctx, span, nil, "a preview environment already exists for this project")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		err := telemetry.Error(ctx, span, err, "error reading environment")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	gi, err := c.Repo().GitlabIntegration().ReadGitlabIntegration(project.ID, request.GitlabIntegrationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err := telemetry.Error(ctx, span, err, "gitlab integration not found")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+			return
+		}
+
+		err := telemetry.Error(ctx, span, err, "error reading gitlab integration")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	client, err := gitlab.NewClient(c.Config(), c.Repo(), gi, project.ID, user.ID)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error getting gitlab client")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusUnauthorized))
+		return
+	}
+
+	webhookID := uuid.New().String()
+
+	hook, _, err := client.Projects.AddProjectHook(request.GitRepoPath, &gogitlab.AddProjectHookOptions{
+		URL:                   gogitlab.String(gitlab.PreviewWebhookURL(c.Config().ServerConf.ServerURL, webhookID)),
+		Token:                 gogitlab.String(c.Config().ServerConf.GitlabIncomingWebhookSecret),
+		MergeRequestsEvents:   gogitlab.Bool(true),
+		PushEvents:            gogitlab.Bool(false),
+		EnableSSLVerification: gogitlab.Bool(true),
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error creating gitlab project webhook")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+
+	env, err := c.Repo().Environment().CreateEnvironment(&models.Environment{
+		ProjectID:           project.ID,
+		ClusterID:           cluster.ID,
+		GitlabIntegrationID: gi.ID,
+		GitlabUserID:        user.ID,
+		GitlabWebhookID:     hook.ID,
+		GitRepoOwner:        owner,
+		GitRepoName:         name,
+		GitRepoBranches:     strings.Join(request.GitRepoBranches, ","),
+		GitDeployBranches:   strings.Join(request.GitDeployBranches, ","),
+		Name:                request.Name,
+		Mode:                "auto",
+		WebhookID:           webhookID,
+		NewCommentsDisabled: request.DisableNewComments,
+	})
+	if err != nil {
+		if _, deleteErr := client.Projects.DeleteProjectHook(request.GitRepoPath, hook.ID); deleteErr != nil {
+			_ = telemetry.Error(ctx, span, deleteErr, "error deleting gitlab project webhook")
+		}
+
+		err := telemetry.Error(ctx, span, err, "error creating environment")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	c.WriteResult(w, r, env.ToEnvironmentType())
+}
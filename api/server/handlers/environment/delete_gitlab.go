@@ -0,0 +1,156 @@
+package environment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/api/utils"
+	"github.com/porter-dev/porter/internal/integrations/ci/gitlab"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// DeleteGitlabEnvironmentHandler deletes a GitLab preview environment along with the previews of its merge requests
+type DeleteGitlabEnvironmentHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewDeleteGitlabEnvironmentHandler returns a DeleteGitlabEnvironmentHandler
+func NewDeleteGitlabEnvironmentHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *DeleteGitlabEnvironmentHandler {
+	return &DeleteGitlabEnvironmentHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP deletes the preview deployment targets and deployments of the environment, the environment itself, and its
+// webhook on the GitLab project
+func (c *DeleteGitlabEnvironmentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-delete-gitlab-environment")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	envID, reqErr := requestutils.GetURLParamUint(r, "environment_id")
+	if reqErr != nil {
+		_ = telemetry.Error(ctx, span, reqErr, "could not get environment id from url")
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "environment-id", Value: envID})
+
+	env, err := c.Repo().Environment().ReadEnvironmentByID(project.ID, cluster.ID, envID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err := telemetry.Error(ctx, span, err, "environment not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+
+		err := telemetry.Error(ctx, span, err, "error reading environment")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if env.GitlabIntegrationID == 0 {
+		err := telemetry.Error(ctx, span, nil, "environment is not a gitlab preview environment")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	depls, err := c.Repo().Environment().ListDeployments(env.ID)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error listing deployments")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	for _, depl := range depls {
+		if depl.Status != types.DeploymentStatusInactive {
+			if err := c.deletePreviewTarget(ctx, env, depl); err != nil {
+				err := telemetry.Error(ctx, span, err, "error deleting preview deployment target")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+				return
+			}
+		}
+
+		if _, err := c.Repo().Environment().DeleteDeployment(depl); err != nil {
+			err := telemetry.Error(ctx, span, err, "error deleting deployment")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	env, err = c.Repo().Environment().DeleteEnvironment(env)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error deleting environment")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	// the webhook is removed on a best-effort basis, since events for a deleted environment are ignored
+	if err := deleteGitlabWebhook(c.Config(), env); err != nil {
+		_ = telemetry.Error(ctx, span, err, "error deleting gitlab project webhook")
+	}
+
+	c.WriteResult(w, r, env.ToEnvironmentType())
+}
+
+func (c *DeleteGitlabEnvironmentHandler) deletePreviewTarget(ctx context.Context, env *models.Environment, depl *models.Deployment) error {
+	deploymentTarget, err := c.Repo().DeploymentTarget().DeploymentTargetBySelectorAndSelectorType(
+		env.ProjectID,
+		env.ClusterID,
+		utils.ValidDNSLabel(depl.PRBranchFrom),
+		string(models.DeploymentTargetSelectorType_Namespace),
+	)
+	if err != nil {
+		return fmt.Errorf("error getting deployment target: %w", err)
+	}
+
+	if deploymentTarget.ID == uuid.Nil || !deploymentTarget.Preview {
+		return nil
+	}
+
+	_, err = c.Config().ClusterControlPlaneClient.DeleteDeploymentTarget(ctx, connect.NewRequest(&porterv1.DeleteDeploymentTargetRequest{
+		ProjectId:          int64(env.ProjectID),
+		DeploymentTargetId: deploymentTarget.ID.String(),
+	}))
+
+	return err
+}
+
+func deleteGitlabWebhook(conf *config.Config, env *models.Environment) error {
+	if env.GitlabWebhookID == 0 {
+		return nil
+	}
+
+	gi, err := conf.Repo.GitlabIntegration().ReadGitlabIntegration(env.ProjectID, env.GitlabIntegrationID)
+	if err != nil {
+		return fmt.Errorf("error reading gitlab integration: %w", err)
+	}
+
+	client, err := gitlab.NewClient(conf, conf.Repo, gi, env.ProjectID, env.GitlabUserID)
+	if err != nil {
+		return fmt.Errorf("error getting gitlab client: %w", err)
+	}
+
+	_, err = client.Projects.DeleteProjectHook(fmt.Sprintf("%s/%s", env.GitRepoOwner, env.GitRepoName), env.GitlabWebhookID)
+
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/porter-dev/porter/api/server/authz"
 	"github.com/porter-dev/porter/api/server/handlers"
@@ -141,6 +142,7 @@ func (c *UpdateDeploymentByClusterHandler) ServeHTTP(w http.ResponseWriter, r *h
 	depl.Namespace = request.Namespace
 	depl.GHDeploymentID = ghDeployment.GetID()
 	depl.CommitSHA = request.CommitSHA
+	depl.MarkPushed(time.Now().UTC())
 
 	// update the deployment
 	depl, err = c.Repo().Environment().UpdateDeployment(depl)
@@ -18,6 +18,7 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/requestutils"
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/previewpolicy"
 	"gorm.io/gorm"
 )
 
@@ -72,6 +73,12 @@ func (c *UpdateEnvironmentSettingsHandler) ServeHTTP(w http.ResponseWriter, r *h
 		telemetry.AttributeKV{Key: "git-deploy-branches", Value: request.GitDeployBranches},
 	)
 
+	if err := previewpolicy.ValidatePolicy(request.Policy); err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid preview policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
 	env, err := c.Repo().Environment().ReadEnvironmentByID(project.ID, cluster.ID, envID)
 	if err != nil {
 		err = telemetry.Error(ctx, span, err, "could not read environment by id")
@@ -115,7 +122,10 @@ func (c *UpdateEnvironmentSettingsHandler) ServeHTTP(w http.ResponseWriter, r *h
 
 	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "changed", Value: changed})
 
-	if changed {
+	if changed && env.GitlabIntegrationID != 0 {
+		// GitLab preview environments only use deploy branches to skip previews of merge requests from them
+		env.GitDeployBranches = strings.Join(request.GitDeployBranches, ",")
+	} else if changed {
 		// let us check if the webhook has access to the "push" event
 		client, err := getGithubClientFromEnvironment(c.Config(), env)
 		if err != nil {
@@ -211,6 +221,11 @@ func (c *UpdateEnvironmentSettingsHandler) ServeHTTP(w http.ResponseWriter, r *h
 		changed = true
 	}
 
+	if request.Policy != nil && !reflect.DeepEqual(env.PreviewPolicy(), request.Policy) {
+		env.SetPreviewPolicy(request.Policy)
+		changed = true
+	}
+
 	if changed {
 		env, err = c.Repo().Environment().UpdateEnvironment(env)
 
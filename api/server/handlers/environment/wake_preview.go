@@ -0,0 +1,135 @@
+package environment
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/previewpolicy"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// previewWakingRetrySeconds is how often the waking page reloads until the preview deployment is ready
+const previewWakingRetrySeconds = 5
+
+const previewWakingPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="%d">
+<title>Waking up preview environment</title>
+</head>
+<body style="font-family: sans-serif; text-align: center; margin-top: 20vh;">
+<h2>This preview environment is waking up</h2>
+<p>It was put to sleep to save costs. This page will reload once it is ready, which usually takes under a minute.</p>
+</body>
+</html>
+`
+
+// PreviewWakerHandler is the default backend of the ingresses of sleeping preview deployments. It wakes up the
+// deployment of the requested host, and responds with a page which reloads until the deployment is ready.
+type PreviewWakerHandler struct {
+	handlers.PorterHandler
+	authz.KubernetesAgentGetter
+}
+
+// NewPreviewWakerHandler returns a new PreviewWakerHandler
+func NewPreviewWakerHandler(config *config.Config) *PreviewWakerHandler {
+	return &PreviewWakerHandler{
+		PorterHandler:         handlers.NewDefaultPorterHandler(config, nil, nil),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ServeHTTP wakes up the sleeping preview deployments of the requested host
+func (c *PreviewWakerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-wake-preview")
+	defer span.End()
+
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "host", Value: host})
+
+	candidates, err := c.Repo().Environment().ListDeploymentsBySleepingHost(host)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error listing sleeping deployments")
+		c.HandleAPIErrorNoWrite(w, r, apierrors.NewErrInternal(err))
+		http.Error(w, "error waking up preview environment", http.StatusInternalServerError)
+		return
+	}
+
+	var depls []*models.Deployment
+	for _, depl := range candidates {
+		if depl.HasSleepingHost(host) {
+			depls = append(depls, depl)
+		}
+	}
+
+	if len(depls) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	// deployments which are already waking up keep routing requests here until they are ready
+	for _, depl := range depls {
+		if depl.SleepingSince == nil {
+			continue
+		}
+
+		if err := c.wake(r, depl); err != nil {
+			err := telemetry.Error(ctx, span, err, "error waking deployment")
+			c.HandleAPIErrorNoWrite(w, r, apierrors.NewErrInternal(err))
+			http.Error(w, "error waking up preview environment", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Retry-After", fmt.Sprintf("%d", previewWakingRetrySeconds))
+	w.WriteHeader(http.StatusServiceUnavailable)
+	fmt.Fprintf(w, previewWakingPage, previewWakingRetrySeconds) // nolint:errcheck
+}
+
+// wake scales a sleeping deployment back up and records when it was woken, which keeps it awake for the grace
+// period of its sleep schedule
+func (c *PreviewWakerHandler) wake(r *http.Request, depl *models.Deployment) error {
+	env, err := c.Repo().Environment().ReadEnvironmentForDeployment(depl)
+	if err != nil {
+		return fmt.Errorf("error reading environment: %w", err)
+	}
+
+	cluster, err := c.Repo().Cluster().ReadCluster(env.ProjectID, env.ClusterID)
+	if err != nil {
+		return fmt.Errorf("error reading cluster: %w", err)
+	}
+
+	agent, err := c.GetAgent(r, cluster, depl.Namespace)
+	if err != nil {
+		return fmt.Errorf("error getting k8s agent: %w", err)
+	}
+
+	if err := previewpolicy.Wake(r.Context(), agent.Clientset, depl.Namespace); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	depl.LastWokenAt = &now
+	depl.SleepingSince = nil
+	depl.SleepReason = ""
+
+	if _, err := c.Repo().Environment().UpdateDeployment(depl); err != nil {
+		return fmt.Errorf("error updating deployment: %w", err)
+	}
+
+	return nil
+}
@@ -97,5 +97,16 @@ func (c *DeleteEnvironmentGroupHandler) ServeHTTP(w http.ResponseWriter, r *http
 		}
 	}
 
+	// rotation policies of the deleted env group would only fail, so they are removed with it
+	policies, err := c.Repo().EnvGroupRotationPolicy().ListEnvGroupRotationPolicies(cluster.ID, request.Name)
+	if err != nil {
+		_ = telemetry.Error(ctx, span, err, "unable to list env group rotation policies")
+	}
+	for _, policy := range policies {
+		if err := c.Repo().EnvGroupRotationPolicy().DeleteEnvGroupRotationPolicy(policy); err != nil {
+			_ = telemetry.Error(ctx, span, err, "unable to delete env group rotation policy")
+		}
+	}
+
 	c.WriteResult(w, r, nil)
 }
@@ -0,0 +1,137 @@
+package environment_groups
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	environmentgroups "github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// DiffEnvGroupVersionsHandler is the handler for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/diff endpoint
+type DiffEnvGroupVersionsHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewDiffEnvGroupVersionsHandler handles GET requests to /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/diff
+func NewDiffEnvGroupVersionsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *DiffEnvGroupVersionsHandler {
+	return &DiffEnvGroupVersionsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// DiffEnvGroupVersionsRequest is the request object for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/diff endpoint
+type DiffEnvGroupVersionsRequest struct {
+	// FromVersion defaults to the version before ToVersion
+	FromVersion int `schema:"from_version"`
+	// ToVersion defaults to the latest version
+	ToVersion int `schema:"to_version"`
+	// RevealSecrets includes the values of secrets and files in the diff, which are masked by default
+	RevealSecrets bool `schema:"reveal_secrets"`
+}
+
+// DiffEnvGroupVersionsResponse is the response object for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/diff endpoint
+type DiffEnvGroupVersionsResponse struct {
+	FromVersion int                                         `json:"from_version"`
+	ToVersion   int                                         `json:"to_version"`
+	Changes     []environmentgroups.EnvironmentGroupKeyDiff `json:"changes"`
+}
+
+// ServeHTTP returns the keys which differ between two versions of an environment group
+func (c *DiffEnvGroupVersionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-diff-env-group-versions")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	envGroupName, reqErr := requestutils.GetURLParamString(r, types.URLParamEnvGroupName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing env group name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &DiffEnvGroupVersionsRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to connect to cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusServiceUnavailable))
+		return
+	}
+
+	toVersion := request.ToVersion
+	if toVersion == 0 {
+		latest, err := environmentgroups.LatestBaseEnvironmentGroup(ctx, agent, envGroupName)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "unable to get latest env group version")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+		if latest.Version == 0 {
+			err = telemetry.Error(ctx, span, nil, "env group not found")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+			return
+		}
+		toVersion = latest.Version
+	}
+
+	fromVersion := request.FromVersion
+	if fromVersion == 0 {
+		fromVersion = toVersion - 1
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "env-group-name", Value: envGroupName},
+		telemetry.AttributeKV{Key: "from-version", Value: fromVersion},
+		telemetry.AttributeKV{Key: "to-version", Value: toVersion},
+		telemetry.AttributeKV{Key: "reveal-secrets", Value: request.RevealSecrets},
+	)
+
+	changes, err := environmentgroups.DiffBaseEnvironmentGroupVersions(ctx, agent, environmentgroups.DiffBaseEnvironmentGroupVersionsInput{
+		Name:          envGroupName,
+		FromVersion:   fromVersion,
+		ToVersion:     toVersion,
+		RevealSecrets: request.RevealSecrets,
+	})
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, environmentgroups.ErrEnvironmentGroupVersionNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "unable to diff env group versions")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	res := &DiffEnvGroupVersionsResponse{
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Changes:     changes,
+	}
+	if res.Changes == nil {
+		res.Changes = []environmentgroups.EnvironmentGroupKeyDiff{}
+	}
+
+	c.WriteResult(w, r, res)
+}
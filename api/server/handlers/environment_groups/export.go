@@ -0,0 +1,142 @@
+package environment_groups
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	environmentgroups "github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// ExportEnvGroupHandler is the handler for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/export endpoint
+type ExportEnvGroupHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewExportEnvGroupHandler handles GET requests to /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/export
+func NewExportEnvGroupHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ExportEnvGroupHandler {
+	return &ExportEnvGroupHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ExportEnvGroupRequest is the request object for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/export endpoint
+type ExportEnvGroupRequest struct {
+	// Format is the format of the exported file, one of dotenv, json or yaml. Defaults to dotenv.
+	Format environmentgroups.EnvFileFormat `schema:"format"`
+	// Version is the version to export. Defaults to the latest version.
+	Version int `schema:"version"`
+	// IncludeSecrets includes the values of secrets and files, which are masked by default
+	IncludeSecrets bool `schema:"include_secrets"`
+}
+
+// ExportEnvGroupResponse is the response object for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/export endpoint
+type ExportEnvGroupResponse struct {
+	Format  environmentgroups.EnvFileFormat `json:"format"`
+	Version int                             `json:"version"`
+	Content string                          `json:"content"`
+}
+
+// ServeHTTP exports the variables, secrets and files of a version of an environment group as a file
+func (c *ExportEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-export-env-group")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	envGroupName, reqErr := requestutils.GetURLParamString(r, types.URLParamEnvGroupName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing env group name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &ExportEnvGroupRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	if request.Format == "" {
+		request.Format = environmentgroups.EnvFileFormat_Dotenv
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to connect to cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusServiceUnavailable))
+		return
+	}
+
+	version := request.Version
+	if version == 0 {
+		latest, err := environmentgroups.LatestBaseEnvironmentGroup(ctx, agent, envGroupName)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "unable to get latest env group version")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+		if latest.Version == 0 {
+			err = telemetry.Error(ctx, span, nil, "env group not found")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+			return
+		}
+		version = latest.Version
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "env-group-name", Value: envGroupName},
+		telemetry.AttributeKV{Key: "format", Value: string(request.Format)},
+		telemetry.AttributeKV{Key: "version", Value: version},
+		telemetry.AttributeKV{Key: "include-secrets", Value: request.IncludeSecrets},
+	)
+
+	eg, err := environmentgroups.BaseEnvironmentGroupVersionWithSecrets(ctx, agent, envGroupName, version)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, environmentgroups.ErrEnvironmentGroupVersionNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "unable to get env group version")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	if !request.IncludeSecrets {
+		for k := range eg.SecretVariables {
+			eg.SecretVariables[k] = environmentgroups.EnvGroupSecretDummyValue
+		}
+		for i := range eg.Files {
+			eg.Files[i].Contents = environmentgroups.EnvGroupSecretDummyValue
+		}
+	}
+
+	content, err := environmentgroups.FormatEnvFile(eg, request.Format)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to format env file")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	c.WriteResult(w, r, &ExportEnvGroupResponse{
+		Format:  request.Format,
+		Version: version,
+		Content: string(content),
+	})
+}
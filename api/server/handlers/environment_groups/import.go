@@ -0,0 +1,218 @@
+package environment_groups
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+
+	"connectrpc.com/connect"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	environmentgroups "github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/secretref"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// ImportEnvGroupHandler is the handler for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/import endpoint
+type ImportEnvGroupHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewImportEnvGroupHandler handles POST requests to /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/import
+func NewImportEnvGroupHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ImportEnvGroupHandler {
+	return &ImportEnvGroupHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ImportEnvGroupRequest is the request object for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/import endpoint
+type ImportEnvGroupRequest struct {
+	// Format is the format of Content, one of dotenv, json or yaml
+	Format environmentgroups.EnvFileFormat `json:"format" form:"required"`
+	// Content is the decrypted contents of the file being imported
+	Content string `json:"content" form:"required"`
+	// Mode is how imported values are combined with the existing values, one of add, overwrite or prune. Defaults to overwrite.
+	Mode environmentgroups.EnvImportMode `json:"mode"`
+	// AllSecrets imports every key of a dotenv file or flat json or yaml object as a secret
+	AllSecrets bool `json:"all_secrets"`
+	// DryRun returns the changes the import would make without making them
+	DryRun bool `json:"dry_run"`
+	// SkipAppAutoDeploy does not redeploy the apps linked to the environment group once it is updated
+	SkipAppAutoDeploy bool `json:"skip_app_auto_deploy"`
+}
+
+// ImportEnvGroupResponse is the response object for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/import endpoint
+type ImportEnvGroupResponse struct {
+	// Changes are the keys which the import changes. Secret and file values are masked.
+	Changes []environmentgroups.EnvironmentGroupKeyDiff `json:"changes"`
+	// DryRun is true if the changes were not made
+	DryRun bool `json:"dry_run"`
+	// Version is the latest version of the environment group after the import
+	Version int `json:"version"`
+}
+
+// ServeHTTP imports variables, secrets and files from a file into an environment group, creating the environment group if it does not exist
+func (c *ImportEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-import-env-group")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	envGroupName, reqErr := requestutils.GetURLParamString(r, types.URLParamEnvGroupName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing env group name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &ImportEnvGroupRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	if request.Mode == "" {
+		request.Mode = environmentgroups.EnvImportMode_Overwrite
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "env-group-name", Value: envGroupName},
+		telemetry.AttributeKV{Key: "format", Value: string(request.Format)},
+		telemetry.AttributeKV{Key: "mode", Value: string(request.Mode)},
+		telemetry.AttributeKV{Key: "dry-run", Value: request.DryRun},
+	)
+
+	imported, err := environmentgroups.ParseEnvFile(environmentgroups.ParseEnvFileInput{
+		Format:     request.Format,
+		Contents:   []byte(request.Content),
+		AllSecrets: request.AllSecrets,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to parse env file")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to connect to cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusServiceUnavailable))
+		return
+	}
+
+	latest, err := environmentgroups.LatestBaseEnvironmentGroup(ctx, agent, envGroupName)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to get latest env group version")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	// an env group which does not exist yet is treated as empty, so that importing a file creates it
+	current := environmentgroups.EnvironmentGroup{Name: envGroupName}
+	if latest.Version != 0 {
+		if latest.Type != "" && latest.Type != string(EnvironmentGroupType_Porter) {
+			err = telemetry.Error(ctx, span, nil, "values can only be imported into porter env groups")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		// the true secret values are needed so that secrets which are not imported are kept, and changed secrets are detected
+		current, err = environmentgroups.BaseEnvironmentGroupVersionWithSecrets(ctx, agent, envGroupName, latest.Version)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if errors.Is(err, environmentgroups.ErrEnvironmentGroupVersionNotFound) {
+				statusCode = http.StatusConflict
+			}
+
+			err = telemetry.Error(ctx, span, err, "unable to get latest env group values")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+			return
+		}
+	}
+
+	proposed, err := environmentgroups.MergeEnvironmentGroup(current, imported, request.Mode)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to merge imported values")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	if _, err := secretref.ParseReferences(proposed.SecretVariables); err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid secret reference")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	res := &ImportEnvGroupResponse{
+		Changes: environmentgroups.DiffEnvironmentGroups(current, proposed, false),
+		DryRun:  request.DryRun,
+		Version: latest.Version,
+	}
+	if res.Changes == nil {
+		res.Changes = []environmentgroups.EnvironmentGroupKeyDiff{}
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "change-count", Value: len(res.Changes)})
+
+	if request.DryRun || (len(res.Changes) == 0 && latest.Version != 0) {
+		c.WriteResult(w, r, res)
+		return
+	}
+
+	var files []*porterv1.EnvGroupFile
+	for _, file := range proposed.Files {
+		files = append(files, &porterv1.EnvGroupFile{
+			Name:        file.Name,
+			B64Contents: base64.StdEncoding.EncodeToString([]byte(file.Contents)),
+		})
+	}
+
+	_, err = c.Config().ClusterControlPlaneClient.CreateOrUpdateEnvGroup(ctx, connect.NewRequest(&porterv1.CreateOrUpdateEnvGroupRequest{
+		ProjectId:            int64(project.ID),
+		ClusterId:            int64(cluster.ID),
+		EnvGroupProviderType: porterv1.EnumEnvGroupProviderType_ENUM_ENV_GROUP_PROVIDER_TYPE_PORTER,
+		EnvGroupName:         envGroupName,
+		EnvVars: &porterv1.EnvGroupVariables{
+			Normal: proposed.Variables,
+			Secret: proposed.SecretVariables,
+			Files:  files,
+		},
+		// the merge has already been computed, so the proposed values replace the latest values
+		IsEnvOverride:     true,
+		SkipAppAutoDeploy: request.SkipAppAutoDeploy,
+	}))
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to create env group version")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	recordEnvGroupAuthor(ctx, agent, envGroupName, latest.Version, user)
+
+	latest, err = environmentgroups.LatestBaseEnvironmentGroup(ctx, agent, envGroupName)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to get latest env group version")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	res.Version = latest.Version
+
+	c.WriteResult(w, r, res)
+}
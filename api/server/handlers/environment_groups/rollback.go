@@ -0,0 +1,171 @@
+package environment_groups
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+
+	"connectrpc.com/connect"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	environmentgroups "github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// RollbackEnvGroupHandler is the handler for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/rollback endpoint
+type RollbackEnvGroupHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewRollbackEnvGroupHandler handles POST requests to /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/rollback
+func NewRollbackEnvGroupHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *RollbackEnvGroupHandler {
+	return &RollbackEnvGroupHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// RollbackEnvGroupRequest is the request object for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/rollback endpoint
+type RollbackEnvGroupRequest struct {
+	// Version is the version whose values are restored
+	Version int `json:"version" form:"required,min=1"`
+	// RedeployLinkedApps redeploys the apps linked to the environment group once the new version is created
+	RedeployLinkedApps bool `json:"redeploy_linked_apps"`
+}
+
+// RollbackEnvGroupResponse is the response object for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/rollback endpoint
+type RollbackEnvGroupResponse struct {
+	// Version is the new version, whose values are equal to the restored version
+	Version int `json:"version"`
+	// RestoredVersion is the version whose values were restored
+	RestoredVersion int `json:"restored_version"`
+}
+
+// ServeHTTP rolls an environment group back to an earlier version by creating a new version with the same values. Older versions are left
+// unchanged, so a rollback can itself be rolled back.
+func (c *RollbackEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-rollback-env-group")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	envGroupName, reqErr := requestutils.GetURLParamString(r, types.URLParamEnvGroupName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing env group name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &RollbackEnvGroupRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "env-group-name", Value: envGroupName},
+		telemetry.AttributeKV{Key: "version", Value: request.Version},
+		telemetry.AttributeKV{Key: "redeploy-linked-apps", Value: request.RedeployLinkedApps},
+	)
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to connect to cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusServiceUnavailable))
+		return
+	}
+
+	target, err := environmentgroups.BaseEnvironmentGroupVersionWithSecrets(ctx, agent, envGroupName, request.Version)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, environmentgroups.ErrEnvironmentGroupVersionNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "unable to get env group version")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	// values of externally managed env groups are synced from their provider, so only porter env groups have versions worth restoring
+	if target.Type != "" && target.Type != string(EnvironmentGroupType_Porter) {
+		err = telemetry.Error(ctx, span, nil, "only porter env groups can be rolled back")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	// the latest version is read before the new version is created, so that only the version created by the rollback is attributed to the user
+	previous, err := environmentgroups.LatestBaseEnvironmentGroup(ctx, agent, envGroupName)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to get latest env group version")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	var files []*porterv1.EnvGroupFile
+	for _, file := range target.Files {
+		files = append(files, &porterv1.EnvGroupFile{
+			Name:        file.Name,
+			B64Contents: base64.StdEncoding.EncodeToString([]byte(file.Contents)),
+		})
+	}
+
+	_, err = c.Config().ClusterControlPlaneClient.CreateOrUpdateEnvGroup(ctx, connect.NewRequest(&porterv1.CreateOrUpdateEnvGroupRequest{
+		ProjectId:            int64(project.ID),
+		ClusterId:            int64(cluster.ID),
+		EnvGroupProviderType: porterv1.EnumEnvGroupProviderType_ENUM_ENV_GROUP_PROVIDER_TYPE_PORTER,
+		EnvGroupName:         envGroupName,
+		EnvVars: &porterv1.EnvGroupVariables{
+			Normal: target.Variables,
+			Secret: target.SecretVariables,
+			Files:  files,
+		},
+		// the restored version replaces the latest values rather than being merged with them
+		IsEnvOverride:     true,
+		SkipAppAutoDeploy: true,
+	}))
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to create env group version")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	recordEnvGroupAuthor(ctx, agent, envGroupName, previous.Version, user)
+
+	latest, err := environmentgroups.LatestBaseEnvironmentGroup(ctx, agent, envGroupName)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to get latest env group version")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	if request.RedeployLinkedApps {
+		err = updateLinkedApps(ctx, c.Config().ClusterControlPlaneClient, project.ID, cluster.ID, envGroupName)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "unable to redeploy linked apps")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	c.WriteResult(w, r, &RollbackEnvGroupResponse{
+		Version:         latest.Version,
+		RestoredVersion: request.Version,
+	})
+}
@@ -0,0 +1,99 @@
+package environment_groups
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/secretrotation"
+)
+
+// errInvalidRotationPolicy is returned by applyRotationPolicySpec when a policy cannot be run as written
+var errInvalidRotationPolicy = errors.New("invalid rotation policy")
+
+// applyRotationPolicySpec validates a rotation policy spec and sets it on a policy
+func applyRotationPolicySpec(repo repository.Repository, projectID uint, policy *models.EnvGroupRotationPolicy, spec types.EnvGroupRotationPolicySpec) error {
+	policy.Key = spec.Key
+	policy.IntervalHours = spec.IntervalHours
+	policy.HookType = spec.HookType
+
+	policy.GeneratorLength = 0
+	policy.GeneratorLower = false
+	policy.JobImage = ""
+	policy.JobCommand = nil
+	policy.JobTimeoutSeconds = 0
+
+	switch spec.HookType {
+	case types.EnvGroupRotationHookType_Generator:
+		if spec.Job != nil {
+			return fmt.Errorf("%w: job cannot be set when the hook type is generator", errInvalidRotationPolicy)
+		}
+
+		policy.GeneratorLength = secretrotation.DefaultGeneratorLength
+		if spec.Generator != nil {
+			if spec.Generator.Length != 0 {
+				policy.GeneratorLength = spec.Generator.Length
+			}
+			policy.GeneratorLower = spec.Generator.Lower
+		}
+	case types.EnvGroupRotationHookType_Job:
+		if spec.Job == nil || spec.Job.Image == "" {
+			return fmt.Errorf("%w: job image must be set when the hook type is job", errInvalidRotationPolicy)
+		}
+		if spec.Generator != nil {
+			return fmt.Errorf("%w: generator cannot be set when the hook type is job", errInvalidRotationPolicy)
+		}
+
+		policy.JobImage = spec.Job.Image
+		policy.JobTimeoutSeconds = uint(secretrotation.DefaultJobTimeout.Seconds())
+		if spec.Job.TimeoutSeconds != 0 {
+			policy.JobTimeoutSeconds = spec.Job.TimeoutSeconds
+		}
+
+		command, err := json.Marshal(spec.Job.Command)
+		if err != nil {
+			return err
+		}
+		policy.JobCommand = command
+	default:
+		return fmt.Errorf("%w: unknown hook type %s", errInvalidRotationPolicy, spec.HookType)
+	}
+
+	channels, err := rotationPolicyChannels(repo, projectID, spec.Channels)
+	if err != nil {
+		return err
+	}
+	policy.Channels = channels
+
+	return nil
+}
+
+// rotationPolicyChannels checks that the Slack channels notified of failed rotations are connected to the project, and encodes them for storage
+func rotationPolicyChannels(repo repository.Repository, projectID uint, channels []string) ([]byte, error) {
+	if len(channels) == 0 {
+		return json.Marshal([]string{})
+	}
+
+	slackInts, err := repo.SlackIntegration().ListSlackIntegrationsByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// channel names are compared without their leading #, which may or may not be stored with the integration
+	connected := make(map[string]bool)
+	for _, slackInt := range slackInts {
+		connected[strings.TrimPrefix(slackInt.Channel, "#")] = true
+	}
+
+	for _, channel := range channels {
+		if !connected[strings.TrimPrefix(channel, "#")] {
+			return nil, fmt.Errorf("%w: slack channel %s is not connected to the project", errInvalidRotationPolicy, channel)
+		}
+	}
+
+	return json.Marshal(channels)
+}
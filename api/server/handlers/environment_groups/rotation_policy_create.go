@@ -0,0 +1,153 @@
+package environment_groups
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	environmentgroups "github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/secretref"
+	"github.com/porter-dev/porter/internal/secretrotation"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// CreateEnvGroupRotationPolicyHandler handles the POST /environment-groups/{env_group_name}/rotation-policies endpoint
+type CreateEnvGroupRotationPolicyHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewCreateEnvGroupRotationPolicyHandler returns a new CreateEnvGroupRotationPolicyHandler
+func NewCreateEnvGroupRotationPolicyHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateEnvGroupRotationPolicyHandler {
+	return &CreateEnvGroupRotationPolicyHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ServeHTTP saves a new rotation policy for a key of an environment group. The key is first rotated one interval after the policy is created.
+func (c *CreateEnvGroupRotationPolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-create-env-group-rotation-policy")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	envGroupName, reqErr := requestutils.GetURLParamString(r, types.URLParamEnvGroupName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing env group name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.CreateEnvGroupRotationPolicyRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "env-group-name", Value: envGroupName},
+		telemetry.AttributeKV{Key: "key", Value: request.Key},
+		telemetry.AttributeKV{Key: "hook-type", Value: string(request.HookType)},
+		telemetry.AttributeKV{Key: "interval-hours", Value: request.IntervalHours},
+	)
+
+	_, err := c.Repo().EnvGroupRotationPolicy().ReadEnvGroupRotationPolicyByKey(cluster.ID, envGroupName, request.Key)
+	if err == nil {
+		err = telemetry.Error(ctx, span, nil, "a rotation policy for this key already exists")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		err = telemetry.Error(ctx, span, err, "error reading rotation policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to connect to cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusServiceUnavailable))
+		return
+	}
+
+	latest, err := environmentgroups.LatestBaseEnvironmentGroup(ctx, agent, envGroupName)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to get latest env group version")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if latest.Version == 0 {
+		err = telemetry.Error(ctx, span, nil, "env group not found")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+		return
+	}
+	if latest.Type != "" && latest.Type != string(EnvironmentGroupType_Porter) {
+		err = telemetry.Error(ctx, span, nil, "only keys of porter env groups can be rotated")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	// secret values are masked when listed, so the unmasked version is read to check whether the key is a secret reference
+	withSecrets, err := environmentgroups.BaseEnvironmentGroupVersionWithSecrets(ctx, agent, envGroupName, latest.Version)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to get latest env group values")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if secretref.IsReference(withSecrets.SecretVariables[request.Key]) {
+		err = telemetry.Error(ctx, span, nil, "keys which reference an external secret manager are rotated there")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	policy := &models.EnvGroupRotationPolicy{
+		ProjectID:    project.ID,
+		ClusterID:    cluster.ID,
+		EnvGroupName: envGroupName,
+	}
+
+	if err := applyRotationPolicySpec(c.Repo(), project.ID, policy, request.EnvGroupRotationPolicySpec); err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errInvalidRotationPolicy) {
+			statusCode = http.StatusBadRequest
+		}
+
+		err = telemetry.Error(ctx, span, err, "error validating rotation policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	policy.NextRotationAt = secretrotation.NextRotationAt(policy, time.Now().UTC())
+
+	policy, err = c.Repo().EnvGroupRotationPolicy().CreateEnvGroupRotationPolicy(policy)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating rotation policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res, err := policy.ToEnvGroupRotationPolicyType()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error decoding rotation policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	c.WriteResult(w, r, types.CreateEnvGroupRotationPolicyResponse{Policy: res})
+}
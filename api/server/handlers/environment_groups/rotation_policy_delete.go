@@ -0,0 +1,79 @@
+package environment_groups
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// DeleteEnvGroupRotationPolicyHandler handles the DELETE /environment-groups/{env_group_name}/rotation-policies/{env_group_rotation_policy_id} endpoint
+type DeleteEnvGroupRotationPolicyHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewDeleteEnvGroupRotationPolicyHandler returns a new DeleteEnvGroupRotationPolicyHandler
+func NewDeleteEnvGroupRotationPolicyHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *DeleteEnvGroupRotationPolicyHandler {
+	return &DeleteEnvGroupRotationPolicyHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP deletes a rotation policy. The current value of its key is kept.
+func (c *DeleteEnvGroupRotationPolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-delete-env-group-rotation-policy")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	envGroupName, reqErr := requestutils.GetURLParamString(r, types.URLParamEnvGroupName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing env group name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	policyID, reqErr := requestutils.GetURLParamUint(r, types.URLParamEnvGroupRotationPolicyID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing rotation policy id from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "env-group-name", Value: envGroupName},
+		telemetry.AttributeKV{Key: "policy-id", Value: policyID},
+	)
+
+	policy, err := c.Repo().EnvGroupRotationPolicy().ReadEnvGroupRotationPolicy(cluster.ID, envGroupName, policyID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading rotation policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	if err := c.Repo().EnvGroupRotationPolicy().DeleteEnvGroupRotationPolicy(policy); err != nil {
+		err = telemetry.Error(ctx, span, err, "error deleting rotation policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
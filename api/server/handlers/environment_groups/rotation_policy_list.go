@@ -0,0 +1,70 @@
+package environment_groups
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// ListEnvGroupRotationPoliciesHandler handles the GET /environment-groups/{env_group_name}/rotation-policies endpoint
+type ListEnvGroupRotationPoliciesHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewListEnvGroupRotationPoliciesHandler returns a new ListEnvGroupRotationPoliciesHandler
+func NewListEnvGroupRotationPoliciesHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListEnvGroupRotationPoliciesHandler {
+	return &ListEnvGroupRotationPoliciesHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP lists the rotation policies of an environment group, along with the result of their most recent rotation
+func (c *ListEnvGroupRotationPoliciesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-env-group-rotation-policies")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	envGroupName, reqErr := requestutils.GetURLParamString(r, types.URLParamEnvGroupName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing env group name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "env-group-name", Value: envGroupName})
+
+	policies, err := c.Repo().EnvGroupRotationPolicy().ListEnvGroupRotationPolicies(cluster.ID, envGroupName)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing rotation policies")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res := types.ListEnvGroupRotationPoliciesResponse{
+		Policies: []types.EnvGroupRotationPolicy{},
+	}
+
+	for _, policy := range policies {
+		policyType, err := policy.ToEnvGroupRotationPolicyType()
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error decoding rotation policy")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		res.Policies = append(res.Policies, policyType)
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,128 @@
+package environment_groups
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/secretrotation"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// RotateEnvGroupKeyHandler handles the POST /environment-groups/{env_group_name}/rotation-policies/{env_group_rotation_policy_id}/rotate endpoint
+type RotateEnvGroupKeyHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewRotateEnvGroupKeyHandler returns a new RotateEnvGroupKeyHandler
+func NewRotateEnvGroupKeyHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *RotateEnvGroupKeyHandler {
+	return &RotateEnvGroupKeyHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ServeHTTP rotates the key of a rotation policy now, creating a new version of the environment group and rolling its linked apps.
+// The next scheduled rotation is an interval after this one.
+func (c *RotateEnvGroupKeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-rotate-env-group-key")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	envGroupName, reqErr := requestutils.GetURLParamString(r, types.URLParamEnvGroupName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing env group name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	policyID, reqErr := requestutils.GetURLParamUint(r, types.URLParamEnvGroupRotationPolicyID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing rotation policy id from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "env-group-name", Value: envGroupName},
+		telemetry.AttributeKV{Key: "policy-id", Value: policyID},
+	)
+
+	policy, err := c.Repo().EnvGroupRotationPolicy().ReadEnvGroupRotationPolicy(cluster.ID, envGroupName, policyID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading rotation policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to connect to cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusServiceUnavailable))
+		return
+	}
+
+	out, rotateErr := secretrotation.RotateEnvironmentGroup(ctx, secretrotation.RotateEnvironmentGroupInput{
+		Agent:        agent,
+		CCPClient:    c.Config().ClusterControlPlaneClient,
+		ProjectID:    project.ID,
+		ClusterID:    cluster.ID,
+		EnvGroupName: envGroupName,
+		Policies:     []*models.EnvGroupRotationPolicy{policy},
+	})
+
+	// the outcome is recorded even if the rotation failed, so that it is shown with the policy
+	notRotatable := errors.Is(rotateErr, secretrotation.ErrNotRotatable)
+	for _, result := range out.Results {
+		secretrotation.RecordResult(result, out.Version, time.Now().UTC())
+		notRotatable = notRotatable || errors.Is(result.Err, secretrotation.ErrNotRotatable)
+	}
+
+	policy, err = c.Repo().EnvGroupRotationPolicy().UpdateEnvGroupRotationPolicy(policy)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating rotation policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	if policy.LastStatus == types.EnvGroupRotationStatus_Failed {
+		statusCode := http.StatusInternalServerError
+		if notRotatable {
+			statusCode = http.StatusBadRequest
+		}
+
+		err = telemetry.Error(ctx, span, errors.New(policy.LastError), "error rotating env group key")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	res, err := policy.ToEnvGroupRotationPolicyType()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error decoding rotation policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	c.WriteResult(w, r, types.RotateEnvGroupKeyResponse{Policy: res})
+}
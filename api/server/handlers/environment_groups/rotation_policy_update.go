@@ -0,0 +1,134 @@
+package environment_groups
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/secretrotation"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// UpdateEnvGroupRotationPolicyHandler handles the PUT /environment-groups/{env_group_name}/rotation-policies/{env_group_rotation_policy_id} endpoint
+type UpdateEnvGroupRotationPolicyHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdateEnvGroupRotationPolicyHandler returns a new UpdateEnvGroupRotationPolicyHandler
+func NewUpdateEnvGroupRotationPolicyHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateEnvGroupRotationPolicyHandler {
+	return &UpdateEnvGroupRotationPolicyHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP replaces the definition of a rotation policy. The next rotation is rescheduled, so that a changed interval takes effect
+// from the last rotation.
+func (c *UpdateEnvGroupRotationPolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-env-group-rotation-policy")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	envGroupName, reqErr := requestutils.GetURLParamString(r, types.URLParamEnvGroupName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing env group name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	policyID, reqErr := requestutils.GetURLParamUint(r, types.URLParamEnvGroupRotationPolicyID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing rotation policy id from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.UpdateEnvGroupRotationPolicyRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "env-group-name", Value: envGroupName},
+		telemetry.AttributeKV{Key: "policy-id", Value: policyID},
+		telemetry.AttributeKV{Key: "key", Value: request.Key},
+		telemetry.AttributeKV{Key: "hook-type", Value: string(request.HookType)},
+	)
+
+	policy, err := c.Repo().EnvGroupRotationPolicy().ReadEnvGroupRotationPolicy(cluster.ID, envGroupName, policyID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading rotation policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	if request.Key != policy.Key {
+		_, err = c.Repo().EnvGroupRotationPolicy().ReadEnvGroupRotationPolicyByKey(cluster.ID, envGroupName, request.Key)
+		if err == nil {
+			err = telemetry.Error(ctx, span, nil, "a rotation policy for this key already exists")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+			return
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "error reading rotation policy")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		// the rotation history belongs to the previous key
+		policy.LastStatus = ""
+		policy.LastError = ""
+		policy.LastVersion = 0
+		policy.LastAttemptedAt = nil
+		policy.LastRotatedAt = nil
+	}
+
+	if err := applyRotationPolicySpec(c.Repo(), project.ID, policy, request.EnvGroupRotationPolicySpec); err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errInvalidRotationPolicy) {
+			statusCode = http.StatusBadRequest
+		}
+
+		err = telemetry.Error(ctx, span, err, "error validating rotation policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	policy.NextRotationAt = secretrotation.NextRotationAt(policy, time.Now().UTC())
+
+	policy, err = c.Repo().EnvGroupRotationPolicy().UpdateEnvGroupRotationPolicy(policy)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating rotation policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res, err := policy.ToEnvGroupRotationPolicyType()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error decoding rotation policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	c.WriteResult(w, r, types.UpdateEnvGroupRotationPolicyResponse{Policy: res})
+}
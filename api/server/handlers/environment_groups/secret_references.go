@@ -0,0 +1,137 @@
+package environment_groups
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	environmentgroups "github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/secretref"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// ListEnvGroupSecretReferencesHandler is the handler for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/secret-references endpoint
+type ListEnvGroupSecretReferencesHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewListEnvGroupSecretReferencesHandler handles GET requests to /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/secret-references
+func NewListEnvGroupSecretReferencesHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListEnvGroupSecretReferencesHandler {
+	return &ListEnvGroupSecretReferencesHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// EnvGroupSecretReference is a secret variable of the latest version of an environment group which references an external secret manager
+type EnvGroupSecretReference struct {
+	Key       string `json:"key"`
+	Reference string `json:"reference"`
+	Scheme    string `json:"scheme"`
+	// RefreshIntervalSeconds is how often the reference is resolved again
+	RefreshIntervalSeconds int `json:"refresh_interval_seconds"`
+}
+
+// ListEnvGroupSecretReferencesResponse is the response object for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/secret-references endpoint
+type ListEnvGroupSecretReferencesResponse struct {
+	// References are the secret references of the latest version
+	References []EnvGroupSecretReference `json:"references"`
+	// Namespaces are the resolution status of the references in each namespace the environment group is synced to
+	Namespaces []environmentgroups.SecretReferenceNamespaceStatus `json:"namespaces"`
+}
+
+// ServeHTTP returns the secret references of an environment group, and the status of their resolution in each namespace the environment group is synced to
+func (c *ListEnvGroupSecretReferencesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-env-group-secret-references")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	envGroupName, reqErr := requestutils.GetURLParamString(r, types.URLParamEnvGroupName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing env group name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "env-group-name", Value: envGroupName})
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to connect to cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusServiceUnavailable))
+		return
+	}
+
+	latest, err := environmentgroups.LatestBaseEnvironmentGroup(ctx, agent, envGroupName)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to get latest env group version")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if latest.Version == 0 {
+		err = telemetry.Error(ctx, span, nil, "env group not found")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+		return
+	}
+
+	// secret values are masked when listed, so the unmasked version is read to find the references. Only the references are returned.
+	withSecrets, err := environmentgroups.BaseEnvironmentGroupVersionWithSecrets(ctx, agent, envGroupName, latest.Version)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to get latest env group values")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	refs, err := secretref.ParseReferences(withSecrets.SecretVariables)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to parse secret references")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	statuses, err := environmentgroups.SecretReferenceStatuses(ctx, agent, envGroupName)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to get secret reference statuses")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res := &ListEnvGroupSecretReferencesResponse{
+		References: []EnvGroupSecretReference{},
+		Namespaces: statuses,
+	}
+	if res.Namespaces == nil {
+		res.Namespaces = []environmentgroups.SecretReferenceNamespaceStatus{}
+	}
+
+	for key, ref := range refs {
+		res.References = append(res.References, EnvGroupSecretReference{
+			Key:                    key,
+			Reference:              ref.Raw,
+			Scheme:                 ref.Scheme,
+			RefreshIntervalSeconds: int(ref.RefreshInterval.Seconds()),
+		})
+	}
+	sort.Slice(res.References, func(i, j int) bool {
+		return res.References[i].Key < res.References[j].Key
+	})
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "reference-count", Value: len(res.References)},
+		telemetry.AttributeKV{Key: "namespace-count", Value: len(res.Namespaces)},
+	)
+
+	c.WriteResult(w, r, res)
+}
@@ -14,7 +14,9 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
+	environmentgroups "github.com/porter-dev/porter/internal/kubernetes/environment_groups"
 	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/secretref"
 	"github.com/porter-dev/porter/internal/telemetry"
 )
 
@@ -118,6 +120,7 @@ func (c *UpdateEnvironmentGroupHandler) ServeHTTP(w http.ResponseWriter, r *http
 	if ok := c.DecodeAndValidate(w, r, request); !ok {
 		return
 	}
+	user, _ := ctx.Value(types.UserScope).(*models.User)
 	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
 
 	telemetry.WithAttributes(span,
@@ -166,6 +169,28 @@ func (c *UpdateEnvironmentGroupHandler) ServeHTTP(w http.ResponseWriter, r *http
 		}
 
 	default:
+		// secret references are resolved when the env group is synced, so malformed references are rejected up front
+		if _, err := secretref.ParseReferences(request.SecretVariables); err != nil {
+			err := telemetry.Error(ctx, span, err, "invalid secret reference")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		// the latest version is read before the new version is created, so that only the version created by this request is attributed to
+		// the user. The author is only recorded on a best effort basis, so the update does not depend on connecting to the cluster.
+		agent, err := c.GetAgent(r, cluster, "")
+		if err != nil {
+			_ = telemetry.Error(ctx, span, err, "unable to connect to cluster to record env group author")
+		}
+		var previous environmentgroups.EnvironmentGroup
+		if agent != nil {
+			previous, err = environmentgroups.LatestBaseEnvironmentGroup(ctx, agent, request.Name)
+			if err != nil {
+				_ = telemetry.Error(ctx, span, err, "unable to get latest env group version to record env group author")
+				agent = nil
+			}
+		}
+
 		var files []*porterv1.EnvGroupFile
 		for _, file := range request.Files {
 			contents := file.Contents
@@ -176,7 +201,7 @@ func (c *UpdateEnvironmentGroupHandler) ServeHTTP(w http.ResponseWriter, r *http
 			})
 		}
 
-		_, err := c.Config().ClusterControlPlaneClient.CreateOrUpdateEnvGroup(ctx, connect.NewRequest(&porterv1.CreateOrUpdateEnvGroupRequest{
+		_, err = c.Config().ClusterControlPlaneClient.CreateOrUpdateEnvGroup(ctx, connect.NewRequest(&porterv1.CreateOrUpdateEnvGroupRequest{
 			ProjectId:            int64(cluster.ProjectID),
 			ClusterId:            int64(cluster.ID),
 			EnvGroupProviderType: porterv1.EnumEnvGroupProviderType_ENUM_ENV_GROUP_PROVIDER_TYPE_PORTER,
@@ -198,6 +223,10 @@ func (c *UpdateEnvironmentGroupHandler) ServeHTTP(w http.ResponseWriter, r *http
 			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 			return
 		}
+
+		if agent != nil {
+			recordEnvGroupAuthor(ctx, agent, request.Name, previous.Version, user)
+		}
 	}
 
 	envGroupResponse := &UpdateEnvironmentGroupResponse{
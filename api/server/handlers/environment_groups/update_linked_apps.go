@@ -1,11 +1,13 @@
 package environment_groups
 
 import (
+	"context"
 	"net/http"
 
 	"connectrpc.com/connect"
 
 	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/api-contracts/generated/go/porter/v1/porterv1connect"
 	"github.com/porter-dev/porter/api/server/authz"
 	"github.com/porter-dev/porter/api/server/handlers"
 	"github.com/porter-dev/porter/api/server/shared"
@@ -64,14 +66,9 @@ func (c *UpdateLinkedAppsHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 	}
 	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "env-group-name", Value: request.Name})
 
-	updateLinkedAppsReq := connect.NewRequest(&porterv1.UpdateAppsLinkedToEnvGroupRequest{
-		ProjectId:    int64(project.ID),
-		ClusterId:    int64(cluster.ID),
-		EnvGroupName: request.Name,
-	})
-	_, err := c.Config().ClusterControlPlaneClient.UpdateAppsLinkedToEnvGroup(ctx, updateLinkedAppsReq)
+	err := updateLinkedApps(ctx, c.Config().ClusterControlPlaneClient, project.ID, cluster.ID, request.Name)
 	if err != nil {
-		err := telemetry.Error(ctx, span, err, "error calling ccp update apps linked to env group")
+		err := telemetry.Error(ctx, span, err, "error updating apps linked to env group")
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 		return
 	}
@@ -80,3 +77,21 @@ func (c *UpdateLinkedAppsHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 
 	c.WriteResult(w, r, res)
 }
+
+// updateLinkedApps redeploys all apps linked to an environment group, so that they use its latest version
+func updateLinkedApps(ctx context.Context, ccpClient porterv1connect.ClusterControlPlaneServiceClient, projectID, clusterID uint, envGroupName string) error {
+	ctx, span := telemetry.NewSpan(ctx, "update-apps-linked-to-env-group")
+	defer span.End()
+
+	updateLinkedAppsReq := connect.NewRequest(&porterv1.UpdateAppsLinkedToEnvGroupRequest{
+		ProjectId:    int64(projectID),
+		ClusterId:    int64(clusterID),
+		EnvGroupName: envGroupName,
+	})
+	_, err := ccpClient.UpdateAppsLinkedToEnvGroup(ctx, updateLinkedAppsReq)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error calling ccp update apps linked to env group")
+	}
+
+	return nil
+}
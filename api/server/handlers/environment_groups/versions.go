@@ -0,0 +1,143 @@
+package environment_groups
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	environmentgroups "github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// ListEnvGroupVersionsHandler is the handler for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/versions endpoint
+type ListEnvGroupVersionsHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewListEnvGroupVersionsHandler handles GET requests to /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/versions
+func NewListEnvGroupVersionsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListEnvGroupVersionsHandler {
+	return &ListEnvGroupVersionsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// EnvGroupVersion is a single version of an environment group, without its values
+type EnvGroupVersion struct {
+	Version int `json:"version"`
+	// Author is the email of the user who created the version. Versions created before authors were recorded have no author.
+	Author       string    `json:"author,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	VariableKeys []string  `json:"variable_keys"`
+	SecretKeys   []string  `json:"secret_keys"`
+	FileNames    []string  `json:"file_names,omitempty"`
+	IsLatest     bool      `json:"is_latest"`
+}
+
+// ListEnvGroupVersionsResponse is the response object for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/versions endpoint
+type ListEnvGroupVersionsResponse struct {
+	// Versions are ordered from newest to oldest
+	Versions []EnvGroupVersion `json:"versions"`
+}
+
+// ServeHTTP lists every version of an environment group, along with who created each version and when
+func (c *ListEnvGroupVersionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-env-group-versions")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	envGroupName, reqErr := requestutils.GetURLParamString(r, types.URLParamEnvGroupName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing env group name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "env-group-name", Value: envGroupName})
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to connect to cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusServiceUnavailable))
+		return
+	}
+
+	versions, err := environmentgroups.BaseEnvironmentGroupVersions(ctx, agent, envGroupName)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to list env group versions")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if len(versions) == 0 {
+		err = telemetry.Error(ctx, span, nil, "env group not found")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+		return
+	}
+
+	res := &ListEnvGroupVersionsResponse{
+		Versions: make([]EnvGroupVersion, 0, len(versions)),
+	}
+	for i, version := range versions {
+		v := EnvGroupVersion{
+			Version:      version.Version,
+			Author:       version.Author,
+			CreatedAt:    version.CreatedAtUTC,
+			VariableKeys: sortedKeys(version.Variables),
+			SecretKeys:   sortedKeys(version.SecretVariables),
+			IsLatest:     i == 0,
+		}
+		for _, file := range version.Files {
+			v.FileNames = append(v.FileNames, file.Name)
+		}
+
+		res.Versions = append(res.Versions, v)
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+// recordEnvGroupAuthor records the user as the author of the version of an environment group created after previousVersion. The version has
+// already been created when this is called, so failures are only recorded rather than failing the request.
+func recordEnvGroupAuthor(ctx context.Context, agent *kubernetes.Agent, envGroupName string, previousVersion int, user *models.User) {
+	ctx, span := telemetry.NewSpan(ctx, "record-env-group-author")
+	defer span.End()
+
+	if user == nil {
+		return
+	}
+
+	err := environmentgroups.SetBaseEnvironmentGroupAuthor(ctx, agent, environmentgroups.SetBaseEnvironmentGroupAuthorInput{
+		Name:            envGroupName,
+		PreviousVersion: previousVersion,
+		Author:          user.Email,
+	})
+	if err != nil {
+		_ = telemetry.Error(ctx, span, err, "unable to record env group author")
+	}
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
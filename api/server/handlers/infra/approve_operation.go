@@ -0,0 +1,42 @@
+package infra
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// InfraApproveOperationHandler approves the terraform plan for an operation, after which the
+// provisioner applies it
+type InfraApproveOperationHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewInfraApproveOperationHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *InfraApproveOperationHandler {
+	return &InfraApproveOperationHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *InfraApproveOperationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+	infra, _ := r.Context().Value(types.InfraScope).(*models.Infra)
+	operation, _ := r.Context().Value(types.OperationScope).(*models.Operation)
+
+	resp, err := c.Config().ProvisionerClient.Approve(context.Background(), proj.ID, infra.ID, operation.UID)
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, resp)
+}
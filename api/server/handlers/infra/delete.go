@@ -53,8 +53,8 @@ func (c *InfraDeleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// if the last operation is in a "starting" state, block apply
-	if lastOperation.Status == "starting" {
+	// if the last operation is in a "starting" or "planning" state, block apply
+	if lastOperation.Status == types.OperationStatusStarting || lastOperation.Status == types.OperationStatusPlanning {
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
 			fmt.Errorf("Operation currently in progress. Please try again when latest operation has completed."),
 			http.StatusBadRequest,
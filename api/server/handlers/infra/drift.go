@@ -0,0 +1,67 @@
+package infra
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// InfraDriftCheckHandler starts a drift check for an infra, outside of the provisioner's schedule
+type InfraDriftCheckHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewInfraDriftCheckHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *InfraDriftCheckHandler {
+	return &InfraDriftCheckHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *InfraDriftCheckHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+	infra, _ := r.Context().Value(types.InfraScope).(*models.Infra)
+
+	resp, err := c.Config().ProvisionerClient.DriftCheck(context.Background(), proj.ID, infra.ID)
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, resp)
+}
+
+// InfraReconcileHandler re-applies the last-applied configuration for an infra which has drifted
+type InfraReconcileHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewInfraReconcileHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *InfraReconcileHandler {
+	return &InfraReconcileHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *InfraReconcileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+	infra, _ := r.Context().Value(types.InfraScope).(*models.Infra)
+
+	resp, err := c.Config().ProvisionerClient.Reconcile(context.Background(), proj.ID, infra.ID)
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, resp)
+}
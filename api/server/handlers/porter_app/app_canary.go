@@ -0,0 +1,243 @@
+package porter_app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/prometheus"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+const (
+	// canaryReadyTimeout is how long the canary pod has to become available before the canary is rolled back
+	canaryReadyTimeout = 5 * time.Minute
+	// canaryRunMargin is added to the total duration of the steps of a canary, to bound how long a canary can run
+	canaryRunMargin = 10 * time.Minute
+)
+
+// canaryFromEvent decodes a canary from the metadata of its CANARY app event
+func canaryFromEvent(event *models.PorterAppEvent) (types.AppCanary, error) {
+	canary, _, err := porter_app.CanaryFromEventMetadata(event.Metadata)
+	if err != nil {
+		return canary, err
+	}
+
+	canary.EventID = event.ID.String()
+	canary.CreatedAt = event.CreatedAt
+	canary.UpdatedAt = event.UpdatedAt
+
+	return canary, nil
+}
+
+// canaryRun shifts traffic to a canary step by step, analyzing it after each step, and records its progress in the
+// canary's app event. The canary resources are always deleted once the run ends, since the new image is only rolled
+// out to the stable deployment once the revision is deployed.
+//
+// The event is updated at least every porter_app.CanaryHeartbeatInterval while the canary runs. If the process running
+// the canary stops, the canary-reconciler worker job finds the canary by its stale event and rolls it back.
+type canaryRun struct {
+	repo repository.Repository
+	// agentConf is used to connect to the cluster before each step, so that credentials do not expire during long runs
+	agentConf *kubernetes.OutOfClusterConfig
+	// mu guards the event and the canary, which are updated by both the steps and the heartbeat
+	mu        sync.Mutex
+	event     *models.PorterAppEvent
+	canary    types.AppCanary
+	resources porter_app.CanaryResources
+}
+
+// timeout is the longest that the run may take
+func (c *canaryRun) timeout() time.Duration {
+	timeout := canaryReadyTimeout + canaryRunMargin
+	for _, step := range c.canary.Spec.Steps {
+		timeout += time.Duration(step.DurationSeconds) * time.Second
+	}
+
+	return timeout
+}
+
+// run runs every step of the canary, and promotes or rolls back the canary depending on the analysis
+func (c *canaryRun) run(ctx context.Context) {
+	ctx, span := telemetry.NewSpan(ctx, "run-app-canary")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "event-id", Value: c.canary.EventID},
+		telemetry.AttributeKV{Key: "service-name", Value: c.canary.Spec.ServiceName},
+	)
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout())
+	defer cancel()
+
+	heartbeatCtx, stopHeartbeat := context.WithCancel(ctx)
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		c.heartbeat(heartbeatCtx)
+	}()
+
+	result, reason := c.runSteps(ctx)
+
+	// the heartbeat must have stopped before the result is recorded, so that it cannot mark the canary progressing again
+	stopHeartbeat()
+	<-heartbeatDone
+
+	// a cancelled context would leave the canary serving traffic, so cleanup uses its own context
+	cleanupCtx, cleanupCancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cleanupCancel()
+
+	agent, err := kubernetes.GetAgentOutOfClusterConfig(cleanupCtx, c.agentConf)
+	if err == nil {
+		err = porter_app.DeleteCanary(cleanupCtx, agent.Clientset, c.resources)
+	}
+	if err != nil {
+		_ = telemetry.Error(ctx, span, err, "error deleting canary resources")
+		result = types.AppCanaryResult_RolledBack
+		reason = fmt.Sprintf("canary resources could not be deleted: %s", err.Error())
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "result", Value: string(result)})
+
+	c.mu.Lock()
+	c.canary.Result = result
+	c.canary.Reason = reason
+	c.mu.Unlock()
+
+	status := types.PorterAppEventStatus_Success
+	if result != types.AppCanaryResult_Promoted {
+		status = types.PorterAppEventStatus_Failed
+	}
+
+	if err := c.updateEvent(cleanupCtx, status); err != nil {
+		_ = telemetry.Error(ctx, span, err, "error recording canary result")
+	}
+}
+
+// runSteps shifts traffic to the canary step by step, and returns the result of the canary along with the reason
+// for a rollback
+func (c *canaryRun) runSteps(ctx context.Context) (types.AppCanaryResult, string) {
+	ctx, span := telemetry.NewSpan(ctx, "run-app-canary-steps")
+	defer span.End()
+
+	agent, err := kubernetes.GetAgentOutOfClusterConfig(ctx, c.agentConf)
+	if err != nil {
+		_ = telemetry.Error(ctx, span, err, "error getting k8s agent")
+		return types.AppCanaryResult_RolledBack, "could not connect to the cluster"
+	}
+
+	if err := porter_app.WaitForCanaryReady(ctx, agent.Clientset, c.resources, canaryReadyTimeout); err != nil {
+		_ = telemetry.Error(ctx, span, err, "canary did not become ready")
+		return types.AppCanaryResult_RolledBack, fmt.Sprintf("canary did not become available: %s", err.Error())
+	}
+
+	for _, step := range c.canary.Spec.Steps {
+		agent, err := kubernetes.GetAgentOutOfClusterConfig(ctx, c.agentConf)
+		if err != nil {
+			_ = telemetry.Error(ctx, span, err, "error getting k8s agent")
+			return types.AppCanaryResult_RolledBack, "could not connect to the cluster"
+		}
+
+		if err := porter_app.SetCanaryWeight(ctx, agent.Clientset, c.resources, step.Weight); err != nil {
+			_ = telemetry.Error(ctx, span, err, "error setting canary weight")
+			return types.AppCanaryResult_RolledBack, fmt.Sprintf("could not send %d%% of traffic to the canary", step.Weight)
+		}
+
+		startedAt := time.Now().UTC()
+		duration := time.Duration(step.DurationSeconds) * time.Second
+
+		select {
+		case <-ctx.Done():
+			return types.AppCanaryResult_RolledBack, "canary timed out"
+		case <-time.After(duration):
+		}
+
+		// reconnect, since the step may have outlasted the credentials of the previous agent
+		agent, err = kubernetes.GetAgentOutOfClusterConfig(ctx, c.agentConf)
+		if err != nil {
+			_ = telemetry.Error(ctx, span, err, "error getting k8s agent")
+			return types.AppCanaryResult_RolledBack, "could not connect to the cluster"
+		}
+
+		promSvc, found, err := prometheus.GetPrometheusService(agent.Clientset)
+		if err != nil || !found {
+			_ = telemetry.Error(ctx, span, err, "error getting prometheus service")
+			return types.AppCanaryResult_RolledBack, "prometheus is not available to analyze the canary"
+		}
+
+		res, err := prometheus.AnalyzeCanary(ctx, agent.Clientset, promSvc, prometheus.CanaryAnalysisOpts{
+			Namespace:     c.resources.Namespace,
+			StableIngress: c.resources.StableIngressName,
+			CanaryIngress: c.resources.IngressName,
+			Window:        duration,
+			Analysis:      c.canary.Spec.Analysis,
+		})
+		if err != nil {
+			_ = telemetry.Error(ctx, span, err, "error analyzing canary")
+			return types.AppCanaryResult_RolledBack, "canary metrics could not be analyzed"
+		}
+
+		res.Weight = step.Weight
+		res.StartedAt = startedAt
+		res.CompletedAt = time.Now().UTC()
+
+		c.mu.Lock()
+		c.canary.Steps = append(c.canary.Steps, res)
+		c.mu.Unlock()
+
+		if err := c.updateEvent(ctx, types.PorterAppEventStatus_Progressing); err != nil {
+			// the analysis is still valid, so the canary continues without its progress being visible
+			_ = telemetry.Error(ctx, span, err, "error recording canary step")
+		}
+
+		if !res.Passed {
+			return types.AppCanaryResult_RolledBack, res.Reason
+		}
+	}
+
+	return types.AppCanaryResult_Promoted, ""
+}
+
+// heartbeat updates the app event of the canary until ctx is done, so that a canary which is still running is never
+// mistaken for an orphaned one while it waits for a long step
+func (c *canaryRun) heartbeat(ctx context.Context) {
+	ctx, span := telemetry.NewSpan(ctx, "app-canary-heartbeat")
+	defer span.End()
+
+	ticker := time.NewTicker(porter_app.CanaryHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.updateEvent(ctx, types.PorterAppEventStatus_Progressing); err != nil {
+				_ = telemetry.Error(ctx, span, err, "error recording canary heartbeat")
+			}
+		}
+	}
+}
+
+// updateEvent stores the progress of the canary in its app event
+func (c *canaryRun) updateEvent(ctx context.Context, status types.PorterAppEventStatus) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	metadata, err := porter_app.CanaryEventMetadata(c.canary, c.resources)
+	if err != nil {
+		return err
+	}
+
+	c.event.Status = string(status)
+	c.event.Metadata = metadata
+	c.event.UpdatedAt = time.Now().UTC()
+
+	return c.repo.PorterAppEvent().UpdateEvent(ctx, c.event)
+}
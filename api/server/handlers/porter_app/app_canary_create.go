@@ -0,0 +1,234 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/prometheus"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// CreateAppCanariesHandler handles the POST /apps/{porter_app_name}/canaries endpoint
+type CreateAppCanariesHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewCreateAppCanariesHandler returns a new CreateAppCanariesHandler
+func NewCreateAppCanariesHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateAppCanariesHandler {
+	return &CreateAppCanariesHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ServeHTTP starts a canary of a new image for each of the given web services which is already deployed. The canaries
+// run in the background after the response is written, and their progress is recorded in CANARY app events which can
+// be read from the GET /apps/{porter_app_name}/canaries/{porter_app_event_id} endpoint.
+func (c *CreateAppCanariesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-create-app-canaries")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.CreateAppCanariesRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "app-revision-id", Value: request.AppRevisionID},
+		telemetry.AttributeKV{Key: "image-tag", Value: request.ImageTag},
+		telemetry.AttributeKV{Key: "canary-count", Value: len(request.Canaries)},
+	)
+
+	serviceNames := make(map[string]bool)
+	for _, spec := range request.Canaries {
+		if serviceNames[spec.ServiceName] {
+			err := telemetry.Error(ctx, span, nil, fmt.Sprintf("service %s has more than one canary", spec.ServiceName))
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+		serviceNames[spec.ServiceName] = true
+	}
+
+	app, err := porterAppByName(c.Repo(), cluster.ID, appName)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errPorterAppNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	deploymentTarget, err := appDeploymentTarget(ctx, appDeploymentTargetInput{
+		ProjectID:            project.ID,
+		ClusterID:            cluster.ID,
+		DeploymentTargetID:   request.DeploymentTargetID,
+		DeploymentTargetName: request.DeploymentTargetName,
+		CCPClient:            c.Config().ClusterControlPlaneClient,
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error getting deployment target")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "deployment-target-id", Value: deploymentTarget.ID},
+		telemetry.AttributeKV{Key: "namespace", Value: deploymentTarget.Namespace},
+	)
+
+	deploymentTargetID, err := uuid.Parse(deploymentTarget.ID)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error parsing deployment target id")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting k8s agent")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	// canaries cannot be analyzed without ingress metrics, so they are rejected before any traffic is shifted
+	_, found, err := prometheus.GetPrometheusService(agent.Clientset)
+	if err != nil || !found {
+		err = telemetry.Error(ctx, span, err, "prometheus is required to analyze canaries")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	var runs []*canaryRun
+
+	// if any canary cannot be started, the canaries which were already created are deleted so that none of them run
+	cleanup := func() {
+		for _, run := range runs {
+			_ = porter_app.DeleteCanary(ctx, agent.Clientset, run.resources)
+
+			if run.event != nil {
+				run.canary.Result = types.AppCanaryResult_RolledBack
+				run.canary.Reason = "another canary of the revision could not be started"
+				_ = run.updateEvent(ctx, types.PorterAppEventStatus_Failed)
+			}
+		}
+	}
+
+	for _, spec := range request.Canaries {
+		resources, err := porter_app.CreateCanary(ctx, agent.Clientset, porter_app.CreateCanaryInput{
+			Namespace:          deploymentTarget.Namespace,
+			AppName:            appName,
+			ServiceName:        spec.ServiceName,
+			DeploymentTargetID: deploymentTarget.ID,
+			ImageRepository:    request.ImageRepository,
+			ImageTag:           request.ImageTag,
+		})
+		if errors.Is(err, porter_app.ErrNoStableDeployment) {
+			// services which are deployed for the first time have no traffic to shift, so they are rolled out directly
+			continue
+		}
+		if err != nil {
+			// the failed canary may have been partially created
+			_ = porter_app.DeleteCanary(ctx, agent.Clientset, resources)
+			cleanup()
+
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "service-name", Value: spec.ServiceName})
+			err = telemetry.Error(ctx, span, err, "error creating canary")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		spec.Analysis = prometheus.CanaryAnalysisWithDefaults(spec.Analysis)
+
+		run := &canaryRun{
+			repo:      c.Repo(),
+			agentConf: c.GetOutOfClusterConfig(cluster),
+			canary: types.AppCanary{
+				AppRevisionID: request.AppRevisionID,
+				Spec:          spec,
+				Image:         fmt.Sprintf("%s:%s", request.ImageRepository, request.ImageTag),
+				Result:        types.AppCanaryResult_Progressing,
+				Steps:         []types.AppCanaryStepResult{},
+			},
+			resources: resources,
+		}
+		runs = append(runs, run)
+
+		metadata, err := porter_app.CanaryEventMetadata(run.canary, run.resources)
+		if err != nil {
+			cleanup()
+
+			err = telemetry.Error(ctx, span, err, "error encoding canary event metadata")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		event := &models.PorterAppEvent{
+			ID:                 uuid.New(),
+			Status:             string(types.PorterAppEventStatus_Progressing),
+			Type:               string(types.PorterAppEventType_Canary),
+			PorterAppID:        app.ID,
+			DeploymentTargetID: deploymentTargetID,
+			Metadata:           metadata,
+		}
+
+		if err := c.Repo().PorterAppEvent().CreateEvent(ctx, event); err != nil {
+			cleanup()
+
+			err = telemetry.Error(ctx, span, err, "error creating canary event")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		run.event = event
+		run.canary.EventID = event.ID.String()
+		run.canary.CreatedAt = event.CreatedAt
+		run.canary.UpdatedAt = event.UpdatedAt
+	}
+
+	res := &types.CreateAppCanariesResponse{
+		Canaries: make([]types.AppCanary, 0, len(runs)),
+	}
+
+	for _, run := range runs {
+		res.Canaries = append(res.Canaries, run.canary)
+
+		// canaries outlive the request, so they run on a context which is not cancelled when the response is written.
+		// If this process stops before a canary finishes, the canary-reconciler worker job rolls it back.
+		go run.run(context.Background())
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,93 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// GetAppCanaryHandler handles the GET /apps/{porter_app_name}/canaries/{porter_app_event_id} endpoint
+type GetAppCanaryHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewGetAppCanaryHandler returns a new GetAppCanaryHandler
+func NewGetAppCanaryHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetAppCanaryHandler {
+	return &GetAppCanaryHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP returns the progress of a canary, which is read from its CANARY app event
+func (c *GetAppCanaryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-app-canary")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	eventID, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppEventID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving event id")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "event-id", Value: eventID},
+	)
+
+	parsedEventID, err := uuid.Parse(eventID)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error parsing event id")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	app, err := porterAppByName(c.Repo(), cluster.ID, appName)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errPorterAppNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	event, err := c.Repo().PorterAppEvent().ReadEvent(ctx, parsedEventID)
+	if err != nil || event.PorterAppID != app.ID || event.Type != string(types.PorterAppEventType_Canary) {
+		err = telemetry.Error(ctx, span, err, "canary not found")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+		return
+	}
+
+	canary, err := canaryFromEvent(&event)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error decoding canary from event")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	c.WriteResult(w, r, &types.GetAppCanaryResponse{Canary: canary})
+}
@@ -0,0 +1,41 @@
+package porter_app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/prometheus"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// errPorterAppNotFound is returned by porterAppByName when the app does not exist in the cluster
+var errPorterAppNotFound = errors.New("porter app not found")
+
+// porterAppByName reads an app in the cluster by its name
+func porterAppByName(repo repository.Repository, clusterID uint, appName string) (*models.PorterApp, error) {
+	app, err := repo.PorterApp().ReadPorterAppByName(clusterID, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	if app == nil || app.ID == 0 {
+		return nil, errPorterAppNotFound
+	}
+
+	return app, nil
+}
+
+// encodeDashboardPanels checks that the query of each panel can be restricted to a namespace, and encodes the panels
+// for storage. Queries are restricted when they are run, since the namespace depends on the deployment target.
+func encodeDashboardPanels(panels []types.AppDashboardPanel) ([]byte, error) {
+	for _, panel := range panels {
+		if _, err := prometheus.EnforceLabels(panel.Query, map[string]string{"namespace": "default"}); err != nil {
+			return nil, fmt.Errorf("invalid query for panel %s: %w", panel.Title, err)
+		}
+	}
+
+	return json.Marshal(panels)
+}
@@ -0,0 +1,114 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// CreateAppDashboardHandler handles the POST /apps/{porter_app_name}/dashboards endpoint
+type CreateAppDashboardHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewCreateAppDashboardHandler returns a new CreateAppDashboardHandler
+func NewCreateAppDashboardHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateAppDashboardHandler {
+	return &CreateAppDashboardHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP saves a new metrics dashboard for an app
+func (c *CreateAppDashboardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-create-app-dashboard")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.CreateAppDashboardRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "dashboard-name", Value: request.Name},
+		telemetry.AttributeKV{Key: "panel-count", Value: len(request.Panels)},
+	)
+
+	app, err := porterAppByName(c.Repo(), cluster.ID, appName)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errPorterAppNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	_, err = c.Repo().AppDashboard().ReadAppDashboardByName(app.ID, request.Name)
+	if err == nil {
+		err = telemetry.Error(ctx, span, nil, "a dashboard with this name already exists")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		err = telemetry.Error(ctx, span, err, "error reading dashboard")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	panels, err := encodeDashboardPanels(request.Panels)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid dashboard panels")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	dashboard, err := c.Repo().AppDashboard().CreateAppDashboard(&models.AppDashboard{
+		ProjectID:   project.ID,
+		ClusterID:   cluster.ID,
+		PorterAppID: app.ID,
+		Name:        request.Name,
+		Panels:      panels,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating dashboard")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res, err := dashboard.ToAppDashboardType()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error decoding dashboard")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	c.WriteResult(w, r, types.CreateAppDashboardResponse{Dashboard: res})
+}
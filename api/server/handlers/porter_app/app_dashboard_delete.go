@@ -0,0 +1,91 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// DeleteAppDashboardHandler handles the DELETE /apps/{porter_app_name}/dashboards/{app_dashboard_id} endpoint
+type DeleteAppDashboardHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewDeleteAppDashboardHandler returns a new DeleteAppDashboardHandler
+func NewDeleteAppDashboardHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *DeleteAppDashboardHandler {
+	return &DeleteAppDashboardHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP deletes a saved metrics dashboard
+func (c *DeleteAppDashboardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-delete-app-dashboard")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	dashboardID, reqErr := requestutils.GetURLParamUint(r, types.URLParamAppDashboardID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving dashboard id")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "dashboard-id", Value: dashboardID},
+	)
+
+	app, err := porterAppByName(c.Repo(), cluster.ID, appName)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errPorterAppNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	dashboard, err := c.Repo().AppDashboard().ReadAppDashboard(app.ID, dashboardID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading dashboard")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	if err := c.Repo().AppDashboard().DeleteAppDashboard(dashboard); err != nil {
+		err = telemetry.Error(ctx, span, err, "error deleting dashboard")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
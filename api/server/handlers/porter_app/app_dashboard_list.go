@@ -0,0 +1,84 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// ListAppDashboardsHandler handles the GET /apps/{porter_app_name}/dashboards endpoint
+type ListAppDashboardsHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewListAppDashboardsHandler returns a new ListAppDashboardsHandler
+func NewListAppDashboardsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListAppDashboardsHandler {
+	return &ListAppDashboardsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP lists the saved metrics dashboards of an app
+func (c *ListAppDashboardsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-app-dashboards")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "app-name", Value: appName})
+
+	app, err := porterAppByName(c.Repo(), cluster.ID, appName)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errPorterAppNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	dashboards, err := c.Repo().AppDashboard().ListAppDashboards(app.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing dashboards")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res := types.ListAppDashboardsResponse{
+		Dashboards: make([]types.AppDashboard, 0, len(dashboards)),
+	}
+
+	for _, dashboard := range dashboards {
+		d, err := dashboard.ToAppDashboardType()
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error decoding dashboard")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		res.Dashboards = append(res.Dashboards, d)
+	}
+
+	c.WriteResult(w, r, res)
+}
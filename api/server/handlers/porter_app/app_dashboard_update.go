@@ -0,0 +1,132 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// UpdateAppDashboardHandler handles the PUT /apps/{porter_app_name}/dashboards/{app_dashboard_id} endpoint
+type UpdateAppDashboardHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdateAppDashboardHandler returns a new UpdateAppDashboardHandler
+func NewUpdateAppDashboardHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateAppDashboardHandler {
+	return &UpdateAppDashboardHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP replaces the name and panels of a saved metrics dashboard
+func (c *UpdateAppDashboardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-app-dashboard")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	dashboardID, reqErr := requestutils.GetURLParamUint(r, types.URLParamAppDashboardID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving dashboard id")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.UpdateAppDashboardRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "dashboard-id", Value: dashboardID},
+		telemetry.AttributeKV{Key: "dashboard-name", Value: request.Name},
+		telemetry.AttributeKV{Key: "panel-count", Value: len(request.Panels)},
+	)
+
+	app, err := porterAppByName(c.Repo(), cluster.ID, appName)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errPorterAppNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	dashboard, err := c.Repo().AppDashboard().ReadAppDashboard(app.ID, dashboardID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading dashboard")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	if request.Name != dashboard.Name {
+		_, err = c.Repo().AppDashboard().ReadAppDashboardByName(app.ID, request.Name)
+		if err == nil {
+			err = telemetry.Error(ctx, span, nil, "a dashboard with this name already exists")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+			return
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "error reading dashboard")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	panels, err := encodeDashboardPanels(request.Panels)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid dashboard panels")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	dashboard.Name = request.Name
+	dashboard.Panels = panels
+
+	dashboard, err = c.Repo().AppDashboard().UpdateAppDashboard(dashboard)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating dashboard")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res, err := dashboard.ToAppDashboardType()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error decoding dashboard")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	c.WriteResult(w, r, types.UpdateAppDashboardResponse{Dashboard: res})
+}
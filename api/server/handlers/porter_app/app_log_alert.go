@@ -0,0 +1,56 @@
+package porter_app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// errInvalidLogAlertRule is returned by validateLogAlertRule when a rule cannot be evaluated as written
+var errInvalidLogAlertRule = errors.New("invalid log alert rule")
+
+// validateLogAlertRule checks that the query of a rule can be evaluated against historical logs and that its channels
+// are connected to the project, and encodes the channels for storage
+func validateLogAlertRule(repo repository.Repository, projectID uint, spec types.AppLogAlertRuleSpec) ([]byte, error) {
+	query, err := porter_app.ParseLogQuery(spec.Query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidLogAlertRule, err.Error())
+	}
+
+	// historical logs are selected by pod, so the service is set on the rule rather than in the query, and revisions
+	// change too often to be alerted on
+	if query.ServiceName() != "" {
+		return nil, fmt.Errorf("%w: set the service name of the rule instead of using service: in the query", errInvalidLogAlertRule)
+	}
+	if query.AppRevisionID() != "" || len(query.RevisionNumbers()) > 0 {
+		return nil, fmt.Errorf("%w: queries cannot filter by revision", errInvalidLogAlertRule)
+	}
+
+	if len(spec.Channels) == 0 {
+		return json.Marshal([]string{})
+	}
+
+	slackInts, err := repo.SlackIntegration().ListSlackIntegrationsByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// channel names are compared without their leading #, which may or may not be stored with the integration
+	connected := make(map[string]bool)
+	for _, slackInt := range slackInts {
+		connected[strings.TrimPrefix(slackInt.Channel, "#")] = true
+	}
+
+	for _, channel := range spec.Channels {
+		if !connected[strings.TrimPrefix(channel, "#")] {
+			return nil, fmt.Errorf("%w: slack channel %s is not connected to the project", errInvalidLogAlertRule, channel)
+		}
+	}
+
+	return json.Marshal(spec.Channels)
+}
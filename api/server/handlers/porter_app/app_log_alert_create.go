@@ -0,0 +1,146 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// CreateAppLogAlertRuleHandler handles the POST /apps/{porter_app_name}/log-alerts endpoint
+type CreateAppLogAlertRuleHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewCreateAppLogAlertRuleHandler returns a new CreateAppLogAlertRuleHandler
+func NewCreateAppLogAlertRuleHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateAppLogAlertRuleHandler {
+	return &CreateAppLogAlertRuleHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP saves a new log alert rule for an app in a deployment target
+func (c *CreateAppLogAlertRuleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-create-app-log-alert-rule")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.CreateAppLogAlertRuleRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "rule-name", Value: request.Name},
+		telemetry.AttributeKV{Key: "query", Value: request.Query},
+	)
+
+	app, err := porterAppByName(c.Repo(), cluster.ID, appName)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errPorterAppNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	deploymentTarget, err := appDeploymentTarget(ctx, appDeploymentTargetInput{
+		ProjectID:            project.ID,
+		ClusterID:            cluster.ID,
+		DeploymentTargetID:   request.DeploymentTargetID,
+		DeploymentTargetName: request.DeploymentTargetName,
+		CCPClient:            c.Config().ClusterControlPlaneClient,
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error getting deployment target")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "deployment-target-id", Value: deploymentTarget.ID})
+
+	_, err = c.Repo().AppLogAlertRule().ReadAppLogAlertRuleByName(app.ID, deploymentTarget.ID, request.Name)
+	if err == nil {
+		err = telemetry.Error(ctx, span, nil, "a log alert rule with this name already exists")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		err = telemetry.Error(ctx, span, err, "error reading log alert rule")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	channels, err := validateLogAlertRule(c.Repo(), project.ID, request.AppLogAlertRuleSpec)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errInvalidLogAlertRule) {
+			statusCode = http.StatusBadRequest
+		}
+
+		err = telemetry.Error(ctx, span, err, "error validating log alert rule")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	windowMinutes := request.WindowMinutes
+	if windowMinutes == 0 {
+		windowMinutes = uint(porter_app.DefaultLogAlertWindow.Minutes())
+	}
+
+	rule, err := c.Repo().AppLogAlertRule().CreateAppLogAlertRule(&models.AppLogAlertRule{
+		ProjectID:          project.ID,
+		ClusterID:          cluster.ID,
+		PorterAppID:        app.ID,
+		DeploymentTargetID: deploymentTarget.ID,
+		Namespace:          deploymentTarget.Namespace,
+		Name:               request.Name,
+		ServiceName:        request.ServiceName,
+		Query:              request.Query,
+		Threshold:          request.Threshold,
+		WindowMinutes:      windowMinutes,
+		Channels:           channels,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating log alert rule")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res, err := rule.ToAppLogAlertRuleType()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error decoding log alert rule")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	c.WriteResult(w, r, types.CreateAppLogAlertRuleResponse{Rule: res})
+}
@@ -0,0 +1,156 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// UpdateAppLogAlertRuleHandler handles the PUT /apps/{porter_app_name}/log-alerts/{app_log_alert_rule_id} endpoint
+type UpdateAppLogAlertRuleHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdateAppLogAlertRuleHandler returns a new UpdateAppLogAlertRuleHandler
+func NewUpdateAppLogAlertRuleHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateAppLogAlertRuleHandler {
+	return &UpdateAppLogAlertRuleHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP replaces the definition of a log alert rule. Changing the query, service or window resets the state of
+// the rule, so that it is notified again if it is still firing.
+func (c *UpdateAppLogAlertRuleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-app-log-alert-rule")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	ruleID, reqErr := requestutils.GetURLParamUint(r, types.URLParamAppLogAlertRuleID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving log alert rule id")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.UpdateAppLogAlertRuleRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "rule-id", Value: ruleID},
+		telemetry.AttributeKV{Key: "rule-name", Value: request.Name},
+		telemetry.AttributeKV{Key: "query", Value: request.Query},
+	)
+
+	app, err := porterAppByName(c.Repo(), cluster.ID, appName)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errPorterAppNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	rule, err := c.Repo().AppLogAlertRule().ReadAppLogAlertRule(app.ID, ruleID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading log alert rule")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	if request.Name != rule.Name {
+		_, err = c.Repo().AppLogAlertRule().ReadAppLogAlertRuleByName(app.ID, rule.DeploymentTargetID, request.Name)
+		if err == nil {
+			err = telemetry.Error(ctx, span, nil, "a log alert rule with this name already exists")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+			return
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "error reading log alert rule")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	channels, err := validateLogAlertRule(c.Repo(), project.ID, request.AppLogAlertRuleSpec)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errInvalidLogAlertRule) {
+			statusCode = http.StatusBadRequest
+		}
+
+		err = telemetry.Error(ctx, span, err, "error validating log alert rule")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	windowMinutes := request.WindowMinutes
+	if windowMinutes == 0 {
+		windowMinutes = uint(porter_app.DefaultLogAlertWindow.Minutes())
+	}
+
+	if request.Query != rule.Query || request.ServiceName != rule.ServiceName || windowMinutes != rule.WindowMinutes {
+		rule.LastState = ""
+		rule.LastMatchCount = 0
+		rule.LastCheckedAt = nil
+		rule.LastFiredAt = nil
+	}
+
+	rule.Name = request.Name
+	rule.ServiceName = request.ServiceName
+	rule.Query = request.Query
+	rule.Threshold = request.Threshold
+	rule.WindowMinutes = windowMinutes
+	rule.Channels = channels
+
+	rule, err = c.Repo().AppLogAlertRule().UpdateAppLogAlertRule(rule)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating log alert rule")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res, err := rule.ToAppLogAlertRuleType()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error decoding log alert rule")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	c.WriteResult(w, r, types.UpdateAppLogAlertRuleResponse{Rule: res})
+}
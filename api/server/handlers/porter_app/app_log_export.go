@@ -0,0 +1,148 @@
+package porter_app
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+const (
+	// maxExportedLogLines is the most lines written by a single export. Larger ranges are exported by splitting them.
+	maxExportedLogLines = 100000
+	// exportLogPageSize is the number of matching logs written at a time
+	exportLogPageSize = 5000
+)
+
+const (
+	logExportFormat_CSV    = "csv"
+	logExportFormat_NDJSON = "ndjson"
+)
+
+// AppLogExportHandler handles the GET /apps/{porter_app_name}/logs/export endpoint
+type AppLogExportHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewAppLogExportHandler returns a new AppLogExportHandler
+func NewAppLogExportHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *AppLogExportHandler {
+	return &AppLogExportHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// AppLogExportRequest represents the accepted fields on a request to the /apps/{porter_app_name}/logs/export endpoint
+type AppLogExportRequest struct {
+	AppLogSearchRequest
+	// Format is either csv or ndjson, and defaults to ndjson
+	Format string `schema:"format"`
+}
+
+// ServeHTTP writes the logs of an app which match a query as a CSV or NDJSON file, from the oldest log to the newest.
+// Logs are written as they are read, so an error after the first page ends the file early rather than failing the
+// request.
+func (c *AppLogExportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-app-log-export")
+	defer span.End()
+
+	request := &AppLogExportRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	format := request.Format
+	if format == "" {
+		format = logExportFormat_NDJSON
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "format", Value: format})
+
+	contentType := "application/x-ndjson"
+	switch format {
+	case logExportFormat_NDJSON:
+	case logExportFormat_CSV:
+		contentType = "text/csv"
+	default:
+		err := telemetry.Error(ctx, span, nil, "format must be csv or ndjson")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	// exports always read forward, so that files are in chronological order
+	request.Direction = porter_app.LogSearchDirection_Forward
+
+	search, err := prepareAppLogSearch(ctx, r, c.Config(), c.KubernetesAgentGetter, request.AppLogSearchRequest)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error preparing log search")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, appLogSearchStatusCode(err)))
+		return
+	}
+
+	// the first page is read before the response is started, so that errors reading logs can still be returned
+	search.Limit = exportLogPageSize
+	result, err := porter_app.SearchLogs(ctx, search)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error searching logs")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	appName, _ := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(
+		`attachment; filename="%s-logs-%s.%s"`,
+		appName, search.Start.UTC().Format("20060102T150405Z"), format,
+	))
+	w.WriteHeader(http.StatusOK)
+
+	written := 0
+	header := true
+	for {
+		if written+len(result.Logs) > maxExportedLogLines {
+			result.Logs = result.Logs[:maxExportedLogLines-written]
+			result.ContinueTime = nil
+		}
+
+		switch format {
+		case logExportFormat_CSV:
+			err = porter_app.WriteLogsCSV(w, result.Logs, header)
+		default:
+			err = porter_app.WriteLogsNDJSON(w, result.Logs)
+		}
+		if err != nil {
+			_ = telemetry.Error(ctx, span, err, "error writing logs")
+			return
+		}
+		written += len(result.Logs)
+		header = false
+
+		if result.ContinueTime == nil || written >= maxExportedLogLines {
+			break
+		}
+
+		search.Start = *result.ContinueTime
+		search.Offset = result.ContinueOffset
+		result, err = porter_app.SearchLogs(ctx, search)
+		if err != nil {
+			_ = telemetry.Error(ctx, span, err, "error searching logs")
+			return
+		}
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "written", Value: written})
+}
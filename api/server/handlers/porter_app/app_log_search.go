@@ -0,0 +1,303 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"connectrpc.com/connect"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	porter_agent "github.com/porter-dev/porter/internal/kubernetes/porter_agent/v2"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+const (
+	defaultLogSearchLimit = 100
+	maxLogSearchLimit     = 5000
+	defaultLogSearchRange = 24 * time.Hour
+)
+
+// errInvalidLogSearch is returned when a log search request cannot be served as written
+var errInvalidLogSearch = errors.New("invalid log search")
+
+// AppLogSearchHandler handles the GET /apps/{porter_app_name}/logs/search endpoint
+type AppLogSearchHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewAppLogSearchHandler returns a new AppLogSearchHandler
+func NewAppLogSearchHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *AppLogSearchHandler {
+	return &AppLogSearchHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// AppLogSearchRequest represents the accepted fields on a request to the /apps/{porter_app_name}/logs/search endpoint
+type AppLogSearchRequest struct {
+	DeploymentTargetID   string `schema:"deployment_target_id"`
+	DeploymentTargetName string `schema:"deployment_target_name"`
+	// Query is a log query, such as `level:>=warn service:web -healthcheck`. See porter_app.LogQuery for the syntax.
+	Query string `schema:"query"`
+	// StartRange defaults to one day before EndRange
+	StartRange time.Time `schema:"start_range,omitempty"`
+	// EndRange defaults to now, and is exclusive
+	EndRange time.Time `schema:"end_range,omitempty"`
+	// Offset is the continue offset of a previous search, which is continued from its continue time
+	Offset uint `schema:"offset"`
+	// Limit is the maximum number of matching logs returned
+	Limit uint `schema:"limit"`
+	// Direction is either forward or backward, and defaults to backward
+	Direction string `schema:"direction"`
+}
+
+// AppLogSearchResponse represents the response to the /apps/{porter_app_name}/logs/search endpoint
+type AppLogSearchResponse struct {
+	// Logs are ordered in the direction of the search
+	Logs []porter_app.StructuredLog `json:"logs"`
+	// ContinueTime is set when more logs may match. The search continues from it by passing it as the start range when
+	// searching forward, or as the end range when searching backward.
+	ContinueTime *time.Time `json:"continue_time,omitempty"`
+	// ContinueOffset is the number of logs at the continue time which were already read, and is passed as the offset
+	// of the continued search
+	ContinueOffset int `json:"continue_offset,omitempty"`
+	// Scanned is the number of logs which were read to find the matching logs
+	Scanned int `json:"scanned"`
+}
+
+// ServeHTTP searches the logs of an app with a log query
+func (c *AppLogSearchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-app-log-search")
+	defer span.End()
+
+	request := &AppLogSearchRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	limit := int(request.Limit)
+	if limit == 0 {
+		limit = defaultLogSearchLimit
+	}
+	if limit > maxLogSearchLimit {
+		limit = maxLogSearchLimit
+	}
+
+	search, err := prepareAppLogSearch(ctx, r, c.Config(), c.KubernetesAgentGetter, *request)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error preparing log search")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, appLogSearchStatusCode(err)))
+		return
+	}
+	search.Limit = limit
+
+	result, err := porter_app.SearchLogs(ctx, search)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error searching logs")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "matched", Value: len(result.Logs)},
+		telemetry.AttributeKV{Key: "scanned", Value: result.Scanned},
+	)
+
+	res := AppLogSearchResponse{
+		Logs:           result.Logs,
+		ContinueTime:   result.ContinueTime,
+		ContinueOffset: result.ContinueOffset,
+		Scanned:        result.Scanned,
+	}
+	if res.Logs == nil {
+		res.Logs = []porter_app.StructuredLog{}
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+// appLogSearchStatusCode returns the status code for an error returned by prepareAppLogSearch
+func appLogSearchStatusCode(err error) int {
+	switch {
+	case errors.Is(err, errInvalidLogSearch):
+		return http.StatusBadRequest
+	case errors.Is(err, errPorterAppNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// lokiSearchParamRegex matches text which can be passed to the porter agent as a search param without needing to be
+// escaped or being interpreted as a pattern
+var lokiSearchParamRegex = regexp.MustCompile(`^[A-Za-z0-9 _\-:,=@#%&/]+$`)
+
+// prepareAppLogSearch parses the query of a log search request and returns a search over the logs of the app, which
+// reads logs from the porter agent. The agent is asked only for the logs of the services and revision required by the
+// query, so that fewer logs have to be filtered.
+func prepareAppLogSearch(
+	ctx context.Context,
+	r *http.Request,
+	config *config.Config,
+	agentGetter authz.KubernetesAgentGetter,
+	request AppLogSearchRequest,
+) (porter_app.SearchLogsInput, error) {
+	ctx, span := telemetry.NewSpan(ctx, "prepare-app-log-search")
+	defer span.End()
+
+	var search porter_app.SearchLogsInput
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		return search, fmt.Errorf("%w: porter app name not found in request", errInvalidLogSearch)
+	}
+
+	end := request.EndRange
+	if end.IsZero() {
+		end = time.Now().UTC()
+	}
+	start := request.StartRange
+	if start.IsZero() {
+		start = end.Add(-defaultLogSearchRange)
+	}
+	if !end.After(start) {
+		return search, fmt.Errorf("%w: end range must be after start range", errInvalidLogSearch)
+	}
+
+	direction := request.Direction
+	if direction == "" {
+		direction = porter_app.LogSearchDirection_Backward
+	}
+	if direction != porter_app.LogSearchDirection_Forward && direction != porter_app.LogSearchDirection_Backward {
+		return search, fmt.Errorf("%w: direction must be forward or backward", errInvalidLogSearch)
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "query", Value: request.Query},
+		telemetry.AttributeKV{Key: "start-range", Value: start.String()},
+		telemetry.AttributeKV{Key: "end-range", Value: end.String()},
+		telemetry.AttributeKV{Key: "direction", Value: direction},
+	)
+
+	query, err := porter_app.ParseLogQuery(request.Query)
+	if err != nil {
+		return search, fmt.Errorf("%w: %s", errInvalidLogSearch, err.Error())
+	}
+
+	app, err := porterAppByName(config.Repo, cluster.ID, appName)
+	if err != nil {
+		return search, telemetry.Error(ctx, span, err, "error reading porter app")
+	}
+
+	deploymentTarget, err := appDeploymentTarget(ctx, appDeploymentTargetInput{
+		ProjectID:            project.ID,
+		ClusterID:            cluster.ID,
+		DeploymentTargetID:   request.DeploymentTargetID,
+		DeploymentTargetName: request.DeploymentTargetName,
+		CCPClient:            config.ClusterControlPlaneClient,
+	})
+	if err != nil {
+		return search, telemetry.Error(ctx, span, err, "error getting deployment target")
+	}
+
+	if len(query.RevisionNumbers()) > 0 {
+		listAppRevisionsReq := connect.NewRequest(&porterv1.ListAppRevisionsRequest{
+			ProjectId:          int64(project.ID),
+			AppId:              int64(app.ID),
+			DeploymentTargetId: deploymentTarget.ID,
+			AppName:            appName,
+		})
+
+		listAppRevisionsResp, err := config.ClusterControlPlaneClient.ListAppRevisions(ctx, listAppRevisionsReq)
+		if err != nil || listAppRevisionsResp == nil || listAppRevisionsResp.Msg == nil {
+			return search, telemetry.Error(ctx, span, err, "error listing app revisions")
+		}
+
+		revisionIDs := make(map[uint64]string)
+		for _, revision := range listAppRevisionsResp.Msg.AppRevisions {
+			revisionIDs[revision.RevisionNumber] = revision.Id
+		}
+
+		if err := query.ResolveRevisionNumbers(revisionIDs); err != nil {
+			return search, fmt.Errorf("%w: %s", errInvalidLogSearch, err.Error())
+		}
+	}
+
+	agent, err := agentGetter.GetAgent(r, cluster, "")
+	if err != nil {
+		return search, telemetry.Error(ctx, span, err, "error getting k8s agent")
+	}
+
+	agentSvc, err := porter_agent.GetAgentService(agent.Clientset)
+	if err != nil {
+		return search, telemetry.Error(ctx, span, err, "error getting agent service")
+	}
+
+	matchLabels := map[string]string{
+		lokiLabel_Namespace:          deploymentTarget.Namespace,
+		lokiLabel_PorterAppName:      appName,
+		lokiLabel_DeploymentTargetId: deploymentTarget.ID,
+	}
+	if serviceName := query.ServiceName(); serviceName != "" {
+		matchLabels[lokiLabel_PorterServiceName] = serviceName
+	}
+	if appRevisionID := query.AppRevisionID(); appRevisionID != "" {
+		matchLabels[lokiLabel_PorterAppRevisionID] = appRevisionID
+	}
+
+	// the search param narrows the logs read from the agent, but is only passed on when it cannot be mistaken for a
+	// pattern, since the query matches text terms literally
+	searchParam := query.SearchParam()
+	if !lokiSearchParamRegex.MatchString(searchParam) {
+		searchParam = ""
+	}
+
+	return porter_app.SearchLogsInput{
+		Query:     query,
+		Start:     start,
+		End:       end,
+		Offset:    int(request.Offset),
+		Direction: direction,
+		Fetch: func(ctx context.Context, inp porter_app.LogFetchInput) ([]porter_app.StructuredLog, error) {
+			logs, err := porter_agent.Logs(ctx, agent.Clientset, agentSvc, &types.LogRequest{
+				Limit:       inp.Limit,
+				StartRange:  &inp.Start,
+				EndRange:    &inp.End,
+				SearchParam: searchParam,
+				MatchLabels: matchLabels,
+				Direction:   inp.Direction,
+			})
+			if err != nil {
+				return nil, err
+			}
+			if logs == nil {
+				return nil, nil
+			}
+
+			return porter_app.AgentLogToStructuredLog(logs.Logs), nil
+		},
+	}, nil
+}
@@ -0,0 +1,149 @@
+package porter_app
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/prometheus"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+const (
+	// maxMetricsQueryPoints is the number of points per series that a metrics query is resolved to by default
+	maxMetricsQueryPoints = 200
+	// minMetricsQueryStepSeconds is the smallest default step of a metrics query, which matches the default scrape interval
+	minMetricsQueryStepSeconds = 15
+)
+
+// AppMetricsQueryHandler handles the /apps/{porter_app_name}/metrics/query endpoint
+type AppMetricsQueryHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewAppMetricsQueryHandler returns a new AppMetricsQueryHandler
+func NewAppMetricsQueryHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *AppMetricsQueryHandler {
+	return &AppMetricsQueryHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// AppMetricsQueryRequest is the expected request for the /apps/{porter_app_name}/metrics/query endpoint
+type AppMetricsQueryRequest struct {
+	DeploymentTargetID   string `schema:"deployment_target_id"`
+	DeploymentTargetName string `schema:"deployment_target_name"`
+	// Query is a PromQL expression. Every selector in the query is restricted to the namespace of the deployment target
+	Query string `schema:"query" form:"required"`
+	// start time (in unix timestamp) for prometheus results
+	StartRange uint `schema:"startrange" form:"required"`
+	// end time (in unix timestamp) for prometheus results
+	EndRange uint `schema:"endrange" form:"required"`
+	// Resolution is the query step, such as 1m. If empty, a step is chosen based on the time range
+	Resolution string `schema:"resolution"`
+}
+
+// AppMetricsQueryResponse is the response for the /apps/{porter_app_name}/metrics/query endpoint
+type AppMetricsQueryResponse struct {
+	Series []prometheus.Series `json:"series"`
+}
+
+// ServeHTTP runs a user-defined PromQL query against the metrics of an app's deployment target
+func (c *AppMetricsQueryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-app-metrics-query")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	request := &AppMetricsQueryRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "query", Value: request.Query},
+		telemetry.AttributeKV{Key: "start-range", Value: request.StartRange},
+		telemetry.AttributeKV{Key: "end-range", Value: request.EndRange},
+		telemetry.AttributeKV{Key: "resolution", Value: request.Resolution},
+	)
+
+	if request.EndRange <= request.StartRange {
+		err := telemetry.Error(ctx, span, nil, "end range must be after start range")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	resolution := request.Resolution
+	if resolution == "" {
+		step := (request.EndRange - request.StartRange) / maxMetricsQueryPoints
+		if step < minMetricsQueryStepSeconds {
+			step = minMetricsQueryStepSeconds
+		}
+		resolution = fmt.Sprintf("%ds", step)
+	}
+
+	deploymentTarget, err := appDeploymentTarget(ctx, appDeploymentTargetInput{
+		ProjectID:            project.ID,
+		ClusterID:            cluster.ID,
+		DeploymentTargetID:   request.DeploymentTargetID,
+		DeploymentTargetName: request.DeploymentTargetName,
+		CCPClient:            c.Config().ClusterControlPlaneClient,
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error getting deployment target")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "namespace", Value: deploymentTarget.Namespace})
+
+	// reject invalid queries before connecting to the cluster
+	if _, err := prometheus.EnforceLabels(request.Query, map[string]string{"namespace": deploymentTarget.Namespace}); err != nil {
+		err := telemetry.Error(ctx, span, err, "invalid query")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting k8s agent")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	promSvc, found, err := prometheus.GetPrometheusService(agent.Clientset)
+	if err != nil || !found {
+		err = telemetry.Error(ctx, span, err, "error getting prometheus service")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	series, err := prometheus.QueryPrometheusCustom(ctx, agent.Clientset, promSvc, prometheus.CustomQueryOpts{
+		Query:      request.Query,
+		Namespace:  deploymentTarget.Namespace,
+		StartRange: request.StartRange,
+		EndRange:   request.EndRange,
+		Resolution: resolution,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error querying prometheus")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	c.WriteResult(w, r, AppMetricsQueryResponse{Series: series})
+}
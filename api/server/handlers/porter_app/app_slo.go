@@ -0,0 +1,56 @@
+package porter_app
+
+import (
+	"fmt"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// defaultAppSLOWindowDays is the window of SLOs which do not declare one
+const defaultAppSLOWindowDays = 30
+
+// validateAppSLOSpecs checks the constraints on a set of SLOs which cannot be expressed as validation tags
+func validateAppSLOSpecs(specs []types.AppSLOSpec) error {
+	names := make(map[string]bool, len(specs))
+
+	for _, spec := range specs {
+		if names[spec.Name] {
+			return fmt.Errorf("slo name %s is declared more than once", spec.Name)
+		}
+		names[spec.Name] = true
+
+		if spec.Type == types.AppSLOType_Latency && spec.LatencyThresholdMs == 0 {
+			return fmt.Errorf("slo %s must set a latency threshold", spec.Name)
+		}
+	}
+
+	return nil
+}
+
+// appSLOWithLastStatus converts an SLO to its external type, including the result of its most recent evaluation
+func appSLOWithLastStatus(repo repository.Repository, slo *models.AppSLO) (types.AppSLO, error) {
+	res := slo.ToAppSLOType()
+
+	if slo.LastCheckedAt == nil {
+		return res, nil
+	}
+
+	statuses, err := repo.AppSLO().ListAppSLOStatuses(slo.ID, 1)
+	if err != nil {
+		return res, err
+	}
+
+	if len(statuses) == 0 {
+		return res, nil
+	}
+
+	status, err := statuses[0].ToAppSLOStatusEntryType()
+	if err != nil {
+		return res, err
+	}
+	res.LastStatus = &status
+
+	return res, nil
+}
@@ -0,0 +1,123 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// defaultAppSLOStatusLimit is the number of statuses returned when no limit is requested
+const defaultAppSLOStatusLimit = 100
+
+// ListAppSLOStatusesHandler handles the GET /apps/{porter_app_name}/slos/{app_slo_id}/statuses endpoint
+type ListAppSLOStatusesHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewListAppSLOStatusesHandler returns a new ListAppSLOStatusesHandler
+func NewListAppSLOStatusesHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListAppSLOStatusesHandler {
+	return &ListAppSLOStatusesHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP lists the status history of an SLO, most recent first
+func (c *ListAppSLOStatusesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-app-slo-statuses")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	sloID, reqErr := requestutils.GetURLParamUint(r, types.URLParamAppSLOID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving slo id")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.ListAppSLOStatusesRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultAppSLOStatusLimit
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "slo-id", Value: sloID},
+		telemetry.AttributeKV{Key: "limit", Value: limit},
+	)
+
+	app, err := porterAppByName(c.Repo(), cluster.ID, appName)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errPorterAppNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	slo, err := c.Repo().AppSLO().ReadAppSLO(app.ID, sloID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading slo")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	statuses, err := c.Repo().AppSLO().ListAppSLOStatuses(slo.ID, limit)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing slo statuses")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res := types.ListAppSLOStatusesResponse{
+		Statuses: make([]types.AppSLOStatusEntry, 0, len(statuses)),
+	}
+
+	for _, status := range statuses {
+		s, err := status.ToAppSLOStatusEntryType()
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error decoding slo status")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		res.Statuses = append(res.Statuses, s)
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,190 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// UpdateAppSLOsHandler handles the PUT /apps/{porter_app_name}/slos endpoint
+type UpdateAppSLOsHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdateAppSLOsHandler returns a new UpdateAppSLOsHandler
+func NewUpdateAppSLOsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateAppSLOsHandler {
+	return &UpdateAppSLOsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP replaces the SLOs of an app in a deployment target which were declared by the same source. SLOs are
+// matched by name, so that the status history of an SLO is kept when its objective changes.
+func (c *UpdateAppSLOsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-app-slos")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.UpdateAppSLOsRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	source := request.Source
+	if source == "" {
+		source = types.AppSLOSource_API
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "source", Value: source},
+		telemetry.AttributeKV{Key: "slo-count", Value: len(request.SLOs)},
+	)
+
+	if err := validateAppSLOSpecs(request.SLOs); err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid slos")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	app, err := porterAppByName(c.Repo(), cluster.ID, appName)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errPorterAppNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	deploymentTarget, err := appDeploymentTarget(ctx, appDeploymentTargetInput{
+		ProjectID:            project.ID,
+		ClusterID:            cluster.ID,
+		DeploymentTargetID:   request.DeploymentTargetID,
+		DeploymentTargetName: request.DeploymentTargetName,
+		CCPClient:            c.Config().ClusterControlPlaneClient,
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error getting deployment target")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "deployment-target-id", Value: deploymentTarget.ID},
+		telemetry.AttributeKV{Key: "namespace", Value: deploymentTarget.Namespace},
+	)
+
+	existing, err := c.Repo().AppSLO().ListAppSLOs(app.ID, deploymentTarget.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing slos")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	existingByName := make(map[string]*models.AppSLO, len(existing))
+	for _, slo := range existing {
+		existingByName[slo.Name] = slo
+	}
+
+	for _, spec := range request.SLOs {
+		if slo, ok := existingByName[spec.Name]; ok && slo.Source != source {
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "slo-name", Value: spec.Name})
+			err = telemetry.Error(ctx, span, nil, "an slo with this name was declared by another source")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+			return
+		}
+	}
+
+	declared := make(map[string]bool, len(request.SLOs))
+	res := types.UpdateAppSLOsResponse{
+		SLOs: make([]types.AppSLO, 0, len(request.SLOs)),
+	}
+
+	for _, spec := range request.SLOs {
+		declared[spec.Name] = true
+
+		windowDays := spec.WindowDays
+		if windowDays == 0 {
+			windowDays = defaultAppSLOWindowDays
+		}
+
+		slo, ok := existingByName[spec.Name]
+		if !ok {
+			slo = &models.AppSLO{
+				ProjectID:          project.ID,
+				ClusterID:          cluster.ID,
+				PorterAppID:        app.ID,
+				DeploymentTargetID: deploymentTarget.ID,
+				Name:               spec.Name,
+				Source:             source,
+			}
+		}
+
+		slo.Namespace = deploymentTarget.Namespace
+		slo.ServiceName = spec.ServiceName
+		slo.Type = spec.Type
+		slo.Target = spec.Target
+		slo.LatencyThresholdMs = spec.LatencyThresholdMs
+		slo.WindowDays = windowDays
+
+		if ok {
+			slo, err = c.Repo().AppSLO().UpdateAppSLO(slo)
+		} else {
+			slo, err = c.Repo().AppSLO().CreateAppSLO(slo)
+		}
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error saving slo")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		s, err := appSLOWithLastStatus(c.Repo(), slo)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error reading slo status")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		res.SLOs = append(res.SLOs, s)
+	}
+
+	for _, slo := range existing {
+		if slo.Source != source || declared[slo.Name] {
+			continue
+		}
+
+		if err := c.Repo().AppSLO().DeleteAppSLO(slo); err != nil {
+			err = telemetry.Error(ctx, span, err, "error deleting slo")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	c.WriteResult(w, r, res)
+}
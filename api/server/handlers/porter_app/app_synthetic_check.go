@@ -0,0 +1,31 @@
+package porter_app
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// appSyntheticCheckWithLastResult converts a synthetic check to its external type, including the result of its most
+// recent run
+func appSyntheticCheckWithLastResult(repo repository.Repository, check *models.AppSyntheticCheck) (types.AppSyntheticCheck, error) {
+	res := check.ToAppSyntheticCheckType()
+
+	if check.LastCheckedAt == nil {
+		return res, nil
+	}
+
+	results, err := repo.AppSyntheticCheck().ListAppSyntheticCheckResults(check.ID, 1)
+	if err != nil {
+		return res, err
+	}
+
+	if len(results) == 0 {
+		return res, nil
+	}
+
+	result := results[0].ToAppSyntheticCheckResultType()
+	res.LastResult = &result
+
+	return res, nil
+}
@@ -0,0 +1,116 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// defaultAppSyntheticCheckResultLimit is the number of results returned when no limit is requested
+const defaultAppSyntheticCheckResultLimit = 100
+
+// ListAppSyntheticCheckResultsHandler handles the GET /apps/{porter_app_name}/synthetic-checks/{app_synthetic_check_id}/results endpoint
+type ListAppSyntheticCheckResultsHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewListAppSyntheticCheckResultsHandler returns a new ListAppSyntheticCheckResultsHandler
+func NewListAppSyntheticCheckResultsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListAppSyntheticCheckResultsHandler {
+	return &ListAppSyntheticCheckResultsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP lists the result history of a synthetic check, most recent first
+func (c *ListAppSyntheticCheckResultsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-app-synthetic-check-results")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	checkID, reqErr := requestutils.GetURLParamUint(r, types.URLParamAppSyntheticCheckID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving synthetic check id")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.ListAppSyntheticCheckResultsRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultAppSyntheticCheckResultLimit
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "synthetic-check-id", Value: checkID},
+		telemetry.AttributeKV{Key: "limit", Value: limit},
+	)
+
+	app, err := porterAppByName(c.Repo(), cluster.ID, appName)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errPorterAppNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	check, err := c.Repo().AppSyntheticCheck().ReadAppSyntheticCheck(app.ID, checkID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading synthetic check")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	results, err := c.Repo().AppSyntheticCheck().ListAppSyntheticCheckResults(check.ID, limit)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing synthetic check results")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res := types.ListAppSyntheticCheckResultsResponse{
+		Results: make([]types.AppSyntheticCheckResult, 0, len(results)),
+	}
+
+	for _, result := range results {
+		res.Results = append(res.Results, result.ToAppSyntheticCheckResultType())
+	}
+
+	c.WriteResult(w, r, res)
+}
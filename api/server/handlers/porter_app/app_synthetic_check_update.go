@@ -0,0 +1,254 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/api-contracts/generated/go/porter/v1/porterv1connect"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	v2 "github.com/porter-dev/porter/internal/porter_app/v2"
+	"github.com/porter-dev/porter/internal/synthetics"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// UpdateAppSyntheticChecksHandler handles the PUT /apps/{porter_app_name}/synthetic-checks endpoint
+type UpdateAppSyntheticChecksHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdateAppSyntheticChecksHandler returns a new UpdateAppSyntheticChecksHandler
+func NewUpdateAppSyntheticChecksHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateAppSyntheticChecksHandler {
+	return &UpdateAppSyntheticChecksHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP replaces the synthetic checks of an app in a deployment target. Checks are matched by name, so that the
+// result history of a check is kept when its expectations change. Changed checks run again right away.
+func (c *UpdateAppSyntheticChecksHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-app-synthetic-checks")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.UpdateAppSyntheticChecksRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "check-count", Value: len(request.Checks)},
+	)
+
+	if err := synthetics.ValidateSpecs(request.Checks); err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid synthetic checks")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	app, err := porterAppByName(c.Repo(), cluster.ID, appName)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errPorterAppNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	deploymentTarget, err := appDeploymentTarget(ctx, appDeploymentTargetInput{
+		ProjectID:            project.ID,
+		ClusterID:            cluster.ID,
+		DeploymentTargetID:   request.DeploymentTargetID,
+		DeploymentTargetName: request.DeploymentTargetName,
+		CCPClient:            c.Config().ClusterControlPlaneClient,
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error getting deployment target")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "deployment-target-id", Value: deploymentTarget.ID},
+		telemetry.AttributeKV{Key: "namespace", Value: deploymentTarget.Namespace},
+	)
+
+	// checks may only be sent to the domains of the app's own web services, which are read from its current revision
+	domains, err := webServiceDomains(ctx, webServiceDomainsInput{
+		ProjectID:          project.ID,
+		DeploymentTargetID: deploymentTarget.ID,
+		AppName:            appName,
+		CCPClient:          c.Config().ClusterControlPlaneClient,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting domains of web services")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	if err := synthetics.ValidateServiceDomains(request.Checks, domains); err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid synthetic check domains")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	existing, err := c.Repo().AppSyntheticCheck().ListAppSyntheticChecks(app.ID, deploymentTarget.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing synthetic checks")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	existingByName := make(map[string]*models.AppSyntheticCheck, len(existing))
+	for _, check := range existing {
+		existingByName[check.Name] = check
+	}
+
+	now := time.Now().UTC()
+	declared := make(map[string]bool, len(request.Checks))
+	res := types.UpdateAppSyntheticChecksResponse{
+		Checks: make([]types.AppSyntheticCheck, 0, len(request.Checks)),
+	}
+
+	for _, spec := range request.Checks {
+		declared[spec.Name] = true
+		spec = synthetics.WithDefaults(spec)
+
+		check, ok := existingByName[spec.Name]
+		if !ok {
+			check = &models.AppSyntheticCheck{
+				ProjectID:          project.ID,
+				ClusterID:          cluster.ID,
+				PorterAppID:        app.ID,
+				DeploymentTargetID: deploymentTarget.ID,
+				Name:               spec.Name,
+				NextCheckAt:        now,
+			}
+		}
+
+		// a check whose request changed starts counting failures again
+		if ok && (check.Domain != spec.Domain || check.Path != spec.Path || check.ServiceName != spec.ServiceName) {
+			check.ConsecutiveFailures = 0
+			check.NextCheckAt = now
+		}
+
+		check.Namespace = deploymentTarget.Namespace
+		check.ServiceName = spec.ServiceName
+		check.Domain = spec.Domain
+		check.Path = spec.Path
+		check.ExpectedStatus = spec.ExpectedStatus
+		check.BodyRegex = spec.BodyRegex
+		check.TLSExpiryWarningDays = spec.TLSExpiryWarningDays
+		check.IntervalSeconds = spec.IntervalSeconds
+
+		if ok {
+			check, err = c.Repo().AppSyntheticCheck().UpdateAppSyntheticCheck(check)
+		} else {
+			check, err = c.Repo().AppSyntheticCheck().CreateAppSyntheticCheck(check)
+		}
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error saving synthetic check")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		s, err := appSyntheticCheckWithLastResult(c.Repo(), check)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error reading synthetic check result")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		res.Checks = append(res.Checks, s)
+	}
+
+	for _, check := range existing {
+		if declared[check.Name] {
+			continue
+		}
+
+		if err := c.Repo().AppSyntheticCheck().DeleteAppSyntheticCheck(check); err != nil {
+			err = telemetry.Error(ctx, span, err, "error deleting synthetic check")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+// webServiceDomainsInput is the input to webServiceDomains
+type webServiceDomainsInput struct {
+	ProjectID          uint
+	DeploymentTargetID string
+	AppName            string
+	CCPClient          porterv1connect.ClusterControlPlaneServiceClient
+}
+
+// webServiceDomains returns the domains of each web service of the current revision of an app, keyed by service name
+func webServiceDomains(ctx context.Context, inp webServiceDomainsInput) (map[string][]string, error) {
+	ctx, span := telemetry.NewSpan(ctx, "web-service-domains")
+	defer span.End()
+
+	currentAppRevisionResp, err := inp.CCPClient.CurrentAppRevision(ctx, connect.NewRequest(&porterv1.CurrentAppRevisionRequest{
+		ProjectId: int64(inp.ProjectID),
+		DeploymentTargetIdentifier: &porterv1.DeploymentTargetIdentifier{
+			Id: inp.DeploymentTargetID,
+		},
+		AppName: inp.AppName,
+	}))
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error getting current app revision")
+	}
+
+	if currentAppRevisionResp == nil || currentAppRevisionResp.Msg == nil || currentAppRevisionResp.Msg.AppRevision == nil || currentAppRevisionResp.Msg.AppRevision.App == nil {
+		return nil, telemetry.Error(ctx, span, nil, "current app revision is nil")
+	}
+
+	app, err := v2.AppFromProto(currentAppRevisionResp.Msg.AppRevision.App)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error converting app proto")
+	}
+
+	domains := make(map[string][]string)
+	for _, service := range app.Services {
+		if service.Type != v2.ServiceType_Web {
+			continue
+		}
+
+		domains[service.Name] = []string{}
+		for _, domain := range service.Domains {
+			domains[service.Name] = append(domains[service.Name], domain.Name)
+		}
+	}
+
+	return domains, nil
+}
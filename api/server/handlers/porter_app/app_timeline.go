@@ -0,0 +1,241 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	porter_agent "github.com/porter-dev/porter/internal/kubernetes/porter_agent/v2"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/storage/driver"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// defaultAppTimelineRange is how far back the timeline goes when no start range is requested
+	defaultAppTimelineRange = 24 * time.Hour
+	// maxAppTimelineKubernetesEvents is the number of kubernetes events read from the porter agent for a timeline
+	maxAppTimelineKubernetesEvents = 5000
+)
+
+// GetAppTimelineHandler handles the GET /apps/{porter_app_name}/timeline endpoint
+type GetAppTimelineHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewGetAppTimelineHandler returns a new GetAppTimelineHandler
+func NewGetAppTimelineHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *GetAppTimelineHandler {
+	return &GetAppTimelineHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ServeHTTP merges the revisions, helm upgrades, kubernetes events, scaling events and incidents of an app into a
+// single timeline. Revisions are required, while the other sources are best effort and reported as warnings when they
+// cannot be read.
+func (c *GetAppTimelineHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-app-timeline")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.GetAppTimelineRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	end := time.Now().UTC()
+	if request.EndRange != 0 {
+		end = time.Unix(int64(request.EndRange), 0).UTC()
+	}
+	start := end.Add(-defaultAppTimelineRange)
+	if request.StartRange != 0 {
+		start = time.Unix(int64(request.StartRange), 0).UTC()
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "service-name", Value: request.ServiceName},
+		telemetry.AttributeKV{Key: "start-range", Value: start.String()},
+		telemetry.AttributeKV{Key: "end-range", Value: end.String()},
+	)
+
+	if !end.After(start) {
+		err := telemetry.Error(ctx, span, nil, "end range must be after start range")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	app, err := porterAppByName(c.Repo(), cluster.ID, appName)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errPorterAppNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	deploymentTarget, err := appDeploymentTarget(ctx, appDeploymentTargetInput{
+		ProjectID:            project.ID,
+		ClusterID:            cluster.ID,
+		DeploymentTargetID:   request.DeploymentTargetID,
+		DeploymentTargetName: request.DeploymentTargetName,
+		CCPClient:            c.Config().ClusterControlPlaneClient,
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error getting deployment target")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "deployment-target-id", Value: deploymentTarget.ID},
+		telemetry.AttributeKV{Key: "namespace", Value: deploymentTarget.Namespace},
+	)
+
+	listAppRevisionsReq := connect.NewRequest(&porterv1.ListAppRevisionsRequest{
+		ProjectId:          int64(project.ID),
+		AppId:              int64(app.ID),
+		DeploymentTargetId: deploymentTarget.ID,
+		AppName:            appName,
+	})
+
+	listAppRevisionsResp, err := c.Config().ClusterControlPlaneClient.ListAppRevisions(ctx, listAppRevisionsReq)
+	if err != nil || listAppRevisionsResp == nil || listAppRevisionsResp.Msg == nil {
+		err = telemetry.Error(ctx, span, err, "error listing app revisions")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	var revisions []porter_app.TimelineRevision
+	for _, revision := range listAppRevisionsResp.Msg.AppRevisions {
+		timelineRevision, err := porter_app.TimelineRevisionFromProto(revision)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error converting app revision")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		revisions = append(revisions, timelineRevision)
+	}
+
+	entries := porter_app.RevisionTimelineEntries(revisions)
+	res := &types.GetAppTimelineResponse{}
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting k8s agent")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	kubernetesInput := porter_app.KubernetesTimelineInput{
+		Namespace:          deploymentTarget.Namespace,
+		AppName:            appName,
+		DeploymentTargetID: deploymentTarget.ID,
+	}
+
+	agentSvc, err := porter_agent.GetAgentService(agent.Clientset)
+	switch {
+	case err == nil:
+		// the porter agent stores kubernetes events for longer than the hour that kubernetes keeps them for
+		kubernetesInput.FromAgent = true
+
+		eventsResp, eventsErr := porter_agent.GetHistoricalKubernetesEvents(agent.Clientset, agentSvc, &types.GetKubernetesEventRequest{
+			Limit:       maxAppTimelineKubernetesEvents,
+			StartRange:  &start,
+			EndRange:    &end,
+			PodSelector: porter_app.LogAlertPodSelector(appName, ""),
+			Namespace:   deploymentTarget.Namespace,
+		})
+		if eventsErr != nil {
+			_ = telemetry.Error(ctx, span, eventsErr, "error getting historical kubernetes events")
+			res.Warnings = append(res.Warnings, "kubernetes events could not be read from the porter agent")
+		} else if eventsResp != nil {
+			kubernetesInput.HistoricalEvents = porter_app.AgentKubernetesEvents(eventsResp.Events)
+		}
+	case k8serrors.IsNotFound(err):
+		// without the porter agent, only the events which the cluster still holds are shown
+	default:
+		_ = telemetry.Error(ctx, span, err, "error getting agent service")
+		kubernetesInput.FromAgent = true
+		res.Warnings = append(res.Warnings, "kubernetes events could not be read from the porter agent")
+	}
+
+	if err == nil {
+		var incidentsResp *types.ListIncidentsResponse
+		incidentsResp, err = porter_agent.ListIncidents(agent.Clientset, agentSvc, &types.ListIncidentsRequest{
+			ReleaseName:      &appName,
+			ReleaseNamespace: &deploymentTarget.Namespace,
+		})
+		if err == nil {
+			kubernetesInput.Incidents = incidentsResp.Incidents
+		}
+	}
+	if err != nil {
+		_ = telemetry.Error(ctx, span, err, "error listing incidents")
+		res.Warnings = append(res.Warnings, "incidents could not be read from the porter agent")
+	}
+
+	kubernetesEntries, err := porter_app.KubernetesTimelineEntries(ctx, agent.Clientset, kubernetesInput)
+	if err != nil {
+		_ = telemetry.Error(ctx, span, err, "error getting kubernetes timeline entries")
+		res.Warnings = append(res.Warnings, "kubernetes events could not be read from the cluster")
+	}
+	entries = append(entries, kubernetesEntries...)
+
+	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, deploymentTarget.Namespace)
+	if err == nil {
+		history, historyErr := helmAgent.GetReleaseHistory(ctx, appName)
+		switch {
+		case errors.Is(historyErr, driver.ErrReleaseNotFound):
+			// apps which are not deployed with helm have no upgrades to show
+		case historyErr != nil:
+			err = historyErr
+		default:
+			entries = append(entries, porter_app.HelmTimelineEntries(history)...)
+		}
+	}
+	if err != nil {
+		_ = telemetry.Error(ctx, span, err, "error getting helm release history")
+		res.Warnings = append(res.Warnings, "helm release history could not be read from the cluster")
+	}
+
+	res.Entries = porter_app.BuildTimeline(entries, porter_app.TimelineFilter{
+		ServiceName: request.ServiceName,
+		Start:       start,
+		End:         end,
+	})
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,102 @@
+package porter_app
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// AppCopyHandler handles the /apps/{porter_app_name}/cp endpoint
+type AppCopyHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewAppCopyHandler returns a new AppCopyHandler
+func NewAppCopyHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *AppCopyHandler {
+	return &AppCopyHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// AppCopyRequest represents the accepted fields on a request to the /apps/{porter_app_name}/cp endpoint
+type AppCopyRequest struct {
+	DeploymentTargetName string `schema:"deployment_target_name"`
+	DeploymentTargetID   string `schema:"deployment_target_id"`
+	ServiceName          string `schema:"service_name" form:"required"`
+	// PodName selects a specific replica. If empty, a healthy replica is chosen
+	PodName string `schema:"pod_name"`
+	// ContainerName selects a specific container. If empty, the pod's default container is used
+	ContainerName string `schema:"container_name"`
+	// Path is the absolute path in the container. For downloads this is the file or directory to copy,
+	// for uploads it is the directory that the archive is extracted into
+	Path      string                 `schema:"path" form:"required"`
+	Direction types.AppCopyDirection `schema:"direction" form:"required,oneof=upload download"`
+}
+
+// ServeHTTP copies files to or from a running replica of an app service. The files are streamed as a tar archive
+// over the stdin or stdout stream of the websocket, so the container image must include tar.
+func (c *AppCopyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-app-copy")
+	defer span.End()
+
+	request := &AppCopyRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "invalid request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "service-name", Value: request.ServiceName},
+		telemetry.AttributeKV{Key: "path", Value: request.Path},
+		telemetry.AttributeKV{Key: "direction", Value: string(request.Direction)},
+	)
+
+	if !path.IsAbs(request.Path) {
+		err := telemetry.Error(ctx, span, nil, "path must be absolute")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	containerPath := path.Clean(request.Path)
+
+	var command []string
+	switch request.Direction {
+	case types.AppCopyDirection_Download:
+		if containerPath == "/" {
+			err := telemetry.Error(ctx, span, nil, "cannot copy the root directory")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		command = []string{"tar", "cf", "-", "-C", path.Dir(containerPath), path.Base(containerPath)}
+	case types.AppCopyDirection_Upload:
+		command = []string{"tar", "xf", "-", "-C", containerPath}
+	}
+
+	serveExecSession(ctx, c, w, r, execSessionInput{
+		ServiceName:          request.ServiceName,
+		DeploymentTargetID:   request.DeploymentTargetID,
+		DeploymentTargetName: request.DeploymentTargetName,
+		PodName:              request.PodName,
+		ContainerName:        request.ContainerName,
+		Command:              command,
+		Metadata: map[string]any{
+			"action": "copy_" + string(request.Direction),
+			"path":   containerPath,
+		},
+	})
+}
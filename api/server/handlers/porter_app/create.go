@@ -167,6 +167,13 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	secretReferenceResolver, err := newSecretReferenceResolver(ctx, c.Config(), cluster)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating secret reference resolver")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
 	helmRelease, err := helmAgent.GetRelease(ctx, appName, 0, false)
 	shouldCreate := err != nil
 
@@ -264,11 +271,12 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 			ExistingHelmValues:        releaseValues,
 			ExistingChartDependencies: releaseDependencies,
 			SubdomainCreateOpts: SubdomainCreateOpts{
-				k8sAgent:      k8sAgent,
-				dnsRepo:       c.Repo().DNSRecord(),
-				dnsClient:     c.Config().DNSClient,
-				appRootDomain: c.Config().ServerConf.AppRootDomain,
-				stackName:     appName,
+				k8sAgent:                k8sAgent,
+				dnsRepo:                 c.Repo().DNSRecord(),
+				dnsClient:               c.Config().DNSClient,
+				appRootDomain:           c.Config().ServerConf.AppRootDomain,
+				stackName:               appName,
+				secretReferenceResolver: secretReferenceResolver,
 			},
 			InjectLauncherToStartCommand: injectLauncher,
 			ShouldValidateHelmValues:     shouldCreate,
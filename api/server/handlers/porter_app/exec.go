@@ -0,0 +1,210 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/server/shared/websocket"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// AppExecHandler handles the /apps/{porter_app_name}/exec endpoint
+type AppExecHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewAppExecHandler returns a new AppExecHandler
+func NewAppExecHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *AppExecHandler {
+	return &AppExecHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// AppExecRequest represents the accepted fields on a request to the /apps/{porter_app_name}/exec endpoint
+type AppExecRequest struct {
+	DeploymentTargetName string `schema:"deployment_target_name"`
+	DeploymentTargetID   string `schema:"deployment_target_id"`
+	ServiceName          string `schema:"service_name" form:"required"`
+	// PodName selects a specific replica. If empty, a healthy replica is chosen
+	PodName string `schema:"pod_name"`
+	// ContainerName selects a specific container. If empty, the pod's default container is used
+	ContainerName string   `schema:"container_name"`
+	Command       []string `schema:"command" form:"required,min=1"`
+	TTY           bool     `schema:"tty"`
+}
+
+// ServeHTTP runs a command in a running replica of an app service, unlike `porter app run` which runs
+// commands in an ephemeral copy of a pod
+func (c *AppExecHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-app-exec")
+	defer span.End()
+
+	request := &AppExecRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "invalid request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "service-name", Value: request.ServiceName},
+		telemetry.AttributeKV{Key: "pod-name", Value: request.PodName},
+		telemetry.AttributeKV{Key: "tty", Value: request.TTY},
+	)
+
+	serveExecSession(ctx, c, w, r, execSessionInput{
+		ServiceName:          request.ServiceName,
+		DeploymentTargetID:   request.DeploymentTargetID,
+		DeploymentTargetName: request.DeploymentTargetName,
+		PodName:              request.PodName,
+		ContainerName:        request.ContainerName,
+		Command:              request.Command,
+		TTY:                  request.TTY,
+		Metadata: map[string]any{
+			"action":  "exec",
+			"command": strings.Join(request.Command, " "),
+			"tty":     request.TTY,
+		},
+	})
+}
+
+// execSessionHandler is implemented by handlers which run commands in app containers over a websocket
+type execSessionHandler interface {
+	handlers.PorterHandler
+	authz.KubernetesAgentGetter
+}
+
+// execSessionInput is the input to serveExecSession
+type execSessionInput struct {
+	ServiceName          string
+	DeploymentTargetID   string
+	DeploymentTargetName string
+	PodName              string
+	ContainerName        string
+	Command              []string
+	TTY                  bool
+	// Metadata is recorded on the exec event for the session
+	Metadata map[string]any
+}
+
+// serveExecSession selects a pod of the requested app service, records an exec event and runs the command
+// over the request's websocket. The session is refused if the exec event cannot be recorded.
+func serveExecSession(ctx context.Context, c execSessionHandler, w http.ResponseWriter, r *http.Request, inp execSessionInput) {
+	ctx, span := telemetry.NewSpan(ctx, "serve-exec-session")
+	defer span.End()
+
+	safeRW := ctx.Value(types.RequestCtxWebsocketKey).(*websocket.WebsocketSafeReadWriter)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "porter app name not found in request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "app-name", Value: appName})
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to get agent")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	deploymentTarget, pod, err := servicePod(ctx, servicePodInput{
+		ProjectID:            project.ID,
+		ClusterID:            cluster.ID,
+		AppName:              appName,
+		ServiceName:          inp.ServiceName,
+		DeploymentTargetID:   inp.DeploymentTargetID,
+		DeploymentTargetName: inp.DeploymentTargetName,
+		PodName:              inp.PodName,
+		Agent:                agent,
+		CCPClient:            c.Config().ClusterControlPlaneClient,
+	})
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errServicePodNotFound) {
+			statusCode = http.StatusBadRequest
+		}
+
+		err = telemetry.Error(ctx, span, err, "unable to find pod")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	container, err := podContainer(pod, inp.ContainerName)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to find container")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "pod-name", Value: pod.Name},
+		telemetry.AttributeKV{Key: "container-name", Value: container},
+	)
+
+	metadata := map[string]any{
+		"service_name":   inp.ServiceName,
+		"pod_name":       pod.Name,
+		"container_name": container,
+	}
+	for k, v := range inp.Metadata {
+		metadata[k] = v
+	}
+
+	event, err := createExecEvent(ctx, c.Repo(), execEventInput{
+		ClusterID:          cluster.ID,
+		AppName:            appName,
+		DeploymentTargetID: deploymentTarget.ID,
+		User:               user,
+		Metadata:           metadata,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to record exec event")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	// errors from the command are sent to the client in the exit message, so they are only recorded here
+	exitCode, execErr := agent.ExecPod(ctx, kubernetes.ExecPodOptions{
+		Namespace: deploymentTarget.Namespace,
+		PodName:   pod.Name,
+		Container: container,
+		Command:   inp.Command,
+		TTY:       inp.TTY,
+	}, safeRW)
+	if execErr != nil {
+		_ = telemetry.Error(ctx, span, execErr, "error running command")
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "exit-code", Value: exitCode})
+
+	// the request context is canceled once the client disconnects, but the event should still be completed
+	err = completeExecEvent(context.Background(), c.Repo(), event, exitCode, execErr)
+	if err != nil {
+		_ = telemetry.Error(ctx, span, err, "unable to complete exec event")
+	}
+}
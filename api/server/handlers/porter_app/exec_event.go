@@ -0,0 +1,93 @@
+package porter_app
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// execEventInput is the input to createExecEvent
+type execEventInput struct {
+	ClusterID          uint
+	AppName            string
+	DeploymentTargetID string
+	User               *models.User
+	// Metadata describes the session, e.g. the command that was run or the path that was copied
+	Metadata map[string]any
+}
+
+// createExecEvent records the start of an exec or copy session in a running container as an EXEC app event,
+// so that every session is visible in the app's activity feed
+func createExecEvent(ctx context.Context, repo repository.Repository, inp execEventInput) (*models.PorterAppEvent, error) {
+	ctx, span := telemetry.NewSpan(ctx, "create-exec-event")
+	defer span.End()
+
+	app, err := repo.PorterApp().ReadPorterAppByName(inp.ClusterID, inp.AppName)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error reading porter app")
+	}
+	if app == nil || app.ID == 0 {
+		return nil, telemetry.Error(ctx, span, nil, "porter app not found")
+	}
+
+	deploymentTargetID, err := uuid.Parse(inp.DeploymentTargetID)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error parsing deployment target id")
+	}
+
+	metadata := make(map[string]any)
+	for k, v := range inp.Metadata {
+		metadata[k] = v
+	}
+
+	if inp.User != nil {
+		metadata["user_id"] = inp.User.ID
+		metadata["user_email"] = inp.User.Email
+	}
+
+	event := models.PorterAppEvent{
+		ID:                 uuid.New(),
+		Status:             string(types.PorterAppEventStatus_Progressing),
+		Type:               string(types.PorterAppEventType_Exec),
+		PorterAppID:        app.ID,
+		DeploymentTargetID: deploymentTargetID,
+		Metadata:           metadata,
+	}
+
+	err = repo.PorterAppEvent().CreateEvent(ctx, &event)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error creating exec event")
+	}
+
+	return &event, nil
+}
+
+// completeExecEvent marks an exec event as finished with the exit code of the session
+func completeExecEvent(ctx context.Context, repo repository.Repository, event *models.PorterAppEvent, exitCode int, execErr error) error {
+	ctx, span := telemetry.NewSpan(ctx, "complete-exec-event")
+	defer span.End()
+
+	event.Status = string(types.PorterAppEventStatus_Success)
+	if exitCode != 0 || execErr != nil {
+		event.Status = string(types.PorterAppEventStatus_Failed)
+	}
+
+	event.Metadata["exit_code"] = exitCode
+	if execErr != nil {
+		event.Metadata["error"] = execErr.Error()
+	}
+
+	event.UpdatedAt = time.Now().UTC()
+
+	err := repo.PorterAppEvent().UpdateEvent(ctx, event)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error updating exec event")
+	}
+
+	return nil
+}
@@ -0,0 +1,597 @@
+package porter_app_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	gorillaws "github.com/gorilla/websocket"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/api-contracts/generated/go/porter/v1/porterv1connect"
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers/porter_app"
+	"github.com/porter-dev/porter/api/server/router/middleware"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/websocket"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+)
+
+const (
+	testAppName            = "my-app"
+	testServiceName        = "web"
+	testNamespace          = "default"
+	testDeploymentTargetID = "1f3b1c1e-6b2c-4d3e-9f4a-5b6c7d8e9f01"
+)
+
+// fakeAgentGetter returns the same agent for every cluster
+type fakeAgentGetter struct {
+	authz.KubernetesAgentGetter
+	agent *kubernetes.Agent
+}
+
+func (g *fakeAgentGetter) GetAgent(r *http.Request, cluster *models.Cluster, namespace string) (*kubernetes.Agent, error) {
+	return g.agent, nil
+}
+
+// fakeCCPClient returns the test deployment target for every deployment target
+type fakeCCPClient struct {
+	porterv1connect.ClusterControlPlaneServiceClient
+}
+
+func (c *fakeCCPClient) DeploymentTargetDetails(ctx context.Context, req *connect.Request[porterv1.DeploymentTargetDetailsRequest]) (*connect.Response[porterv1.DeploymentTargetDetailsResponse], error) {
+	return connect.NewResponse(&porterv1.DeploymentTargetDetailsResponse{
+		DeploymentTarget: &porterv1.DeploymentTarget{
+			Id:        testDeploymentTargetID,
+			Name:      "default",
+			Namespace: testNamespace,
+			ClusterId: 1,
+			IsDefault: true,
+		},
+	}), nil
+}
+
+// fakeExecutor runs stream in place of a command in a pod, and records the options of every session
+type fakeExecutor struct {
+	mu       sync.Mutex
+	sessions []kubernetes.ExecPodOptions
+
+	stream func(ctx context.Context, opts remotecommand.StreamOptions) error
+}
+
+func (e *fakeExecutor) newExecutor(opts kubernetes.ExecPodOptions) (remotecommand.Executor, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.sessions = append(e.sessions, opts)
+
+	return e, nil
+}
+
+func (e *fakeExecutor) Sessions() []kubernetes.ExecPodOptions {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]kubernetes.ExecPodOptions{}, e.sessions...)
+}
+
+func (e *fakeExecutor) Stream(opts remotecommand.StreamOptions) error {
+	return e.stream(context.Background(), opts)
+}
+
+func (e *fakeExecutor) StreamWithContext(ctx context.Context, opts remotecommand.StreamOptions) error {
+	return e.stream(ctx, opts)
+}
+
+// execTestEnv is an app with a single running replica of its web service
+type execTestEnv struct {
+	conf     *config.Config
+	app      *models.PorterApp
+	user     *models.User
+	executor *fakeExecutor
+}
+
+func newExecTestEnv(t *testing.T, stream func(ctx context.Context, opts remotecommand.StreamOptions) error) *execTestEnv {
+	t.Helper()
+
+	conf := apitest.LoadConfig(t)
+	conf.ClusterControlPlaneClient = &fakeCCPClient{}
+	conf.WSUpgrader = &websocket.Upgrader{
+		WSUpgrader: &gorillaws.Upgrader{
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+
+	app, err := conf.Repo.PorterApp().CreatePorterApp(&models.PorterApp{
+		ProjectID: 1,
+		ClusterID: 1,
+		Name:      testAppName,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := &models.User{Email: "dev@example.com"}
+	user.ID = 1
+
+	return &execTestEnv{
+		conf:     conf,
+		app:      app,
+		user:     user,
+		executor: &fakeExecutor{stream: stream},
+	}
+}
+
+// serve runs handler behind the websocket middleware, and returns the client end of the websocket
+func (e *execTestEnv) serve(t *testing.T, handler http.Handler, query url.Values) *gorillaws.Conn {
+	t.Helper()
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-app-web-6d4cf56db6-abcde",
+			Namespace: testNamespace,
+			Labels: map[string]string{
+				"porter.run/app-name":             testAppName,
+				"porter.run/service-name":         testServiceName,
+				"porter.run/deployment-target-id": testDeploymentTargetID,
+			},
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: "web"}, {Name: "sidecar"}},
+		},
+		Status: v1.PodStatus{
+			Phase:      v1.PodRunning,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+		},
+	}
+
+	agentGetter := &fakeAgentGetter{agent: kubernetes.GetExecAgentTesting(e.executor.newExecutor, pod)}
+
+	switch h := handler.(type) {
+	case *porter_app.AppExecHandler:
+		h.KubernetesAgentGetter = agentGetter
+	case *porter_app.AppCopyHandler:
+		h.KubernetesAgentGetter = agentGetter
+	}
+
+	wsHandler := middleware.NewWebsocketMiddleware(e.conf).Middleware(handler)
+
+	project := &models.Project{}
+	project.ID = 1
+
+	cluster := &models.Cluster{ProjectID: 1}
+	cluster.ID = 1
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = apitest.WithURLParams(t, r, map[string]string{
+			string(types.URLParamPorterAppName): testAppName,
+		})
+		r = apitest.WithProject(t, r, project)
+		r = apitest.WithAuthenticatedUser(t, r, e.user)
+		r = r.WithContext(context.WithValue(r.Context(), types.ClusterScope, cluster))
+
+		wsHandler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	query.Set("deployment_target_id", testDeploymentTargetID)
+
+	conn, _, err := gorillaws.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"?"+query.Encode(), nil)
+	if err != nil {
+		t.Fatalf("error dialing websocket: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() }) // nolint:errcheck
+
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second)) // nolint:errcheck,gosec
+
+	return conn
+}
+
+// execEvents returns the exec events of the test app, waiting for the last one to be completed, since the
+// event is completed after the exit message has been sent
+func (e *execTestEnv) execEvents(t *testing.T) []*models.PorterAppEvent {
+	t.Helper()
+
+	var events []*models.PorterAppEvent
+
+	deadline := time.Now().Add(10 * time.Second)
+
+	for time.Now().Before(deadline) {
+		var err error
+
+		events, _, err = e.conf.Repo.PorterAppEvent().ListEventsByPorterAppID(context.Background(), e.app.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(events) == 0 || events[len(events)-1].Status != string(types.PorterAppEventStatus_Progressing) {
+			return events
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("timed out waiting for exec event to be completed")
+
+	return nil
+}
+
+func TestAppExec(t *testing.T) {
+	env := newExecTestEnv(t, func(ctx context.Context, opts remotecommand.StreamOptions) error {
+		stdin, err := io.ReadAll(opts.Stdin)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(opts.Stdout, "hello %s", stdin) // nolint:errcheck
+
+		return nil
+	})
+
+	handler := porter_app.NewAppExecHandler(env.conf, decoderValidator(env.conf), resultWriter(env.conf))
+
+	conn := env.serve(t, handler, url.Values{
+		"service_name": {testServiceName},
+		"command":      {"cat"},
+	})
+
+	writeExecMessage(t, conn, types.ExecStreamStdin, []byte("world"))
+	writeExecMessage(t, conn, types.ExecStreamStdinClose, nil)
+
+	stdout, exit := readExecSession(t, conn)
+
+	if stdout != "hello world" {
+		t.Errorf("expected stdin to be sent to the command, got %q", stdout)
+	}
+
+	if exit != (types.ExecExitMessage{ExitCode: 0}) {
+		t.Errorf("expected the command to succeed, got %+v", exit)
+	}
+
+	expSessions := []kubernetes.ExecPodOptions{{
+		Namespace: testNamespace,
+		PodName:   "my-app-web-6d4cf56db6-abcde",
+		Container: "web",
+		Command:   []string{"cat"},
+	}}
+	if sessions := env.executor.Sessions(); !reflect.DeepEqual(expSessions, sessions) {
+		t.Errorf("expected the command to run in the first container of the service pod, got %+v", sessions)
+	}
+
+	events := env.execEvents(t)
+	if len(events) != 1 {
+		t.Fatalf("expected one exec event, got %d", len(events))
+	}
+
+	event := events[0]
+
+	if event.Type != string(types.PorterAppEventType_Exec) || event.Status != string(types.PorterAppEventStatus_Success) {
+		t.Errorf("expected a successful exec event, got type %s and status %s", event.Type, event.Status)
+	}
+
+	if event.DeploymentTargetID.String() != testDeploymentTargetID {
+		t.Errorf("expected the event to belong to deployment target %s, got %s", testDeploymentTargetID, event.DeploymentTargetID)
+	}
+
+	expMetadata := map[string]any{
+		"action":         "exec",
+		"command":        "cat",
+		"tty":            false,
+		"service_name":   testServiceName,
+		"pod_name":       "my-app-web-6d4cf56db6-abcde",
+		"container_name": "web",
+		"user_id":        uint(1),
+		"user_email":     "dev@example.com",
+		"exit_code":      0,
+	}
+	for key, val := range expMetadata {
+		if event.Metadata[key] != val {
+			t.Errorf("expected event metadata %s to be %v, got %v", key, val, event.Metadata[key])
+		}
+	}
+}
+
+func TestAppExecFailed(t *testing.T) {
+	tests := map[string]struct {
+		err         error
+		expExitCode int
+		expError    bool
+	}{
+		"command exits with non-zero code": {
+			err:         exec.CodeExitError{Err: errors.New("command terminated with exit code 2"), Code: 2},
+			expExitCode: 2,
+		},
+		"command cannot be run": {
+			err:         errors.New("executable file not found in $PATH"),
+			expExitCode: -1,
+			expError:    true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			env := newExecTestEnv(t, func(ctx context.Context, opts remotecommand.StreamOptions) error {
+				return tc.err
+			})
+
+			handler := porter_app.NewAppExecHandler(env.conf, decoderValidator(env.conf), resultWriter(env.conf))
+
+			conn := env.serve(t, handler, url.Values{
+				"service_name": {testServiceName},
+				"command":      {"migrate", "--dry-run"},
+			})
+
+			_, exit := readExecSession(t, conn)
+
+			if exit.ExitCode != tc.expExitCode || (exit.Error != "") != tc.expError {
+				t.Errorf("expected exit code %d, got %+v", tc.expExitCode, exit)
+			}
+
+			events := env.execEvents(t)
+			if len(events) != 1 {
+				t.Fatalf("expected one exec event, got %d", len(events))
+			}
+
+			if events[0].Status != string(types.PorterAppEventStatus_Failed) {
+				t.Errorf("expected the exec event to be failed, got %s", events[0].Status)
+			}
+
+			if events[0].Metadata["exit_code"] != tc.expExitCode {
+				t.Errorf("expected the exit code to be recorded, got %v", events[0].Metadata["exit_code"])
+			}
+
+			if _, ok := events[0].Metadata["error"]; ok != tc.expError {
+				t.Errorf("expected error to be recorded: %t, got %v", tc.expError, events[0].Metadata["error"])
+			}
+		})
+	}
+}
+
+func TestAppExecRefused(t *testing.T) {
+	tests := map[string]struct {
+		query    url.Values
+		expError string
+	}{
+		"pod does not belong to the service": {
+			query: url.Values{
+				"service_name": {testServiceName},
+				"pod_name":     {"other-app-web-0"},
+				"command":      {"sh"},
+			},
+			expError: "no matching pod found for service: pod other-app-web-0 does not belong to service web",
+		},
+		"service has no pods": {
+			query: url.Values{
+				"service_name": {"worker"},
+				"command":      {"sh"},
+			},
+			expError: "no matching pod found for service: no healthy pods found",
+		},
+		"container does not exist": {
+			query: url.Values{
+				"service_name":   {testServiceName},
+				"container_name": {"debug"},
+				"command":        {"sh"},
+			},
+			expError: "no matching pod found for service: container debug not found in pod my-app-web-6d4cf56db6-abcde",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			env := newExecTestEnv(t, func(ctx context.Context, opts remotecommand.StreamOptions) error {
+				return errors.New("command should not be run")
+			})
+
+			handler := porter_app.NewAppExecHandler(env.conf, decoderValidator(env.conf), resultWriter(env.conf))
+
+			conn := env.serve(t, handler, tc.query)
+
+			if reqErr := readExecError(t, conn); reqErr.Error != tc.expError {
+				t.Errorf("expected error %q, got %q", tc.expError, reqErr.Error)
+			}
+
+			if sessions := env.executor.Sessions(); len(sessions) != 0 {
+				t.Errorf("expected no command to be run, got %+v", sessions)
+			}
+
+			if events := env.execEvents(t); len(events) != 0 {
+				t.Errorf("expected no exec events, got %d", len(events))
+			}
+		})
+	}
+}
+
+func TestAppCopy(t *testing.T) {
+	tests := map[string]struct {
+		path       string
+		direction  types.AppCopyDirection
+		expCommand []string
+		expPath    string
+	}{
+		"download": {
+			path:       "/app/data/",
+			direction:  types.AppCopyDirection_Download,
+			expCommand: []string{"tar", "cf", "-", "-C", "/app", "data"},
+			expPath:    "/app/data",
+		},
+		"upload": {
+			path:       "/tmp/../app/uploads",
+			direction:  types.AppCopyDirection_Upload,
+			expCommand: []string{"tar", "xf", "-", "-C", "/app/uploads"},
+			expPath:    "/app/uploads",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			env := newExecTestEnv(t, func(ctx context.Context, opts remotecommand.StreamOptions) error {
+				if _, err := io.Copy(opts.Stdout, opts.Stdin); err != nil {
+					return err
+				}
+
+				return nil
+			})
+
+			handler := porter_app.NewAppCopyHandler(env.conf, decoderValidator(env.conf), resultWriter(env.conf))
+
+			conn := env.serve(t, handler, url.Values{
+				"service_name": {testServiceName},
+				"path":         {tc.path},
+				"direction":    {string(tc.direction)},
+			})
+
+			writeExecMessage(t, conn, types.ExecStreamStdin, []byte("archive"))
+			writeExecMessage(t, conn, types.ExecStreamStdinClose, nil)
+
+			stdout, exit := readExecSession(t, conn)
+
+			if stdout != "archive" || exit.ExitCode != 0 {
+				t.Errorf("expected the archive to be streamed, got %q and %+v", stdout, exit)
+			}
+
+			sessions := env.executor.Sessions()
+			if len(sessions) != 1 || !reflect.DeepEqual(tc.expCommand, sessions[0].Command) || sessions[0].TTY {
+				t.Errorf("expected command %v without a tty, got %+v", tc.expCommand, sessions)
+			}
+
+			events := env.execEvents(t)
+			if len(events) != 1 {
+				t.Fatalf("expected one exec event, got %d", len(events))
+			}
+
+			if events[0].Metadata["action"] != "copy_"+string(tc.direction) || events[0].Metadata["path"] != tc.expPath {
+				t.Errorf("expected the copy to be recorded, got %v", events[0].Metadata)
+			}
+		})
+	}
+}
+
+func TestAppCopyInvalidPath(t *testing.T) {
+	tests := map[string]struct {
+		path      string
+		direction types.AppCopyDirection
+		expError  string
+	}{
+		"relative path": {
+			path:      "data",
+			direction: types.AppCopyDirection_Download,
+			expError:  "path must be absolute",
+		},
+		"download root directory": {
+			path:      "/app/..",
+			direction: types.AppCopyDirection_Download,
+			expError:  "cannot copy the root directory",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			env := newExecTestEnv(t, func(ctx context.Context, opts remotecommand.StreamOptions) error {
+				return errors.New("command should not be run")
+			})
+
+			handler := porter_app.NewAppCopyHandler(env.conf, decoderValidator(env.conf), resultWriter(env.conf))
+
+			conn := env.serve(t, handler, url.Values{
+				"service_name": {testServiceName},
+				"path":         {tc.path},
+				"direction":    {string(tc.direction)},
+			})
+
+			if reqErr := readExecError(t, conn); reqErr.Error != tc.expError {
+				t.Errorf("expected error %q, got %q", tc.expError, reqErr.Error)
+			}
+
+			if sessions := env.executor.Sessions(); len(sessions) != 0 {
+				t.Errorf("expected no command to be run, got %+v", sessions)
+			}
+		})
+	}
+}
+
+func writeExecMessage(t *testing.T, conn *gorillaws.Conn, stream types.ExecStream, payload []byte) {
+	t.Helper()
+
+	if err := conn.WriteMessage(gorillaws.BinaryMessage, append([]byte{byte(stream)}, payload...)); err != nil {
+		t.Fatalf("error writing message: %v", err)
+	}
+}
+
+// readExecSession reads the stdout of a session until its exit message
+func readExecSession(t *testing.T, conn *gorillaws.Conn) (string, types.ExecExitMessage) {
+	t.Helper()
+
+	var stdout string
+
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("error reading message before exit: %v", err)
+		}
+
+		if messageType != gorillaws.BinaryMessage || len(data) == 0 {
+			t.Fatalf("expected a binary message with a stream byte, got type %d: %q", messageType, data)
+		}
+
+		switch types.ExecStream(data[0]) {
+		case types.ExecStreamStdout:
+			stdout += string(data[1:])
+		case types.ExecStreamExit:
+			exit := types.ExecExitMessage{}
+			if err := json.Unmarshal(data[1:], &exit); err != nil {
+				t.Fatalf("error decoding exit message: %v", err)
+			}
+
+			return stdout, exit
+		}
+	}
+}
+
+// readExecError reads the error that the handler sent instead of starting a session
+func readExecError(t *testing.T, conn *gorillaws.Conn) *types.ExternalError {
+	t.Helper()
+
+	messageType, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("error reading error message: %v", err)
+	}
+
+	if messageType != gorillaws.TextMessage {
+		t.Fatalf("expected an error message, got type %d: %q", messageType, data)
+	}
+
+	reqErr := &types.ExternalError{}
+	if err := json.Unmarshal(data, reqErr); err != nil {
+		t.Fatalf("error decoding error message: %v", err)
+	}
+
+	return reqErr
+}
+
+func decoderValidator(conf *config.Config) shared.RequestDecoderValidator {
+	return shared.NewDefaultRequestDecoderValidator(conf.Logger, conf.Alerter)
+}
+
+func resultWriter(conf *config.Config) shared.ResultWriter {
+	return shared.NewDefaultResultWriter(conf.Logger, conf.Alerter)
+}
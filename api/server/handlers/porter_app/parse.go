@@ -16,6 +16,7 @@ import (
 	"github.com/porter-dev/porter/internal/kubernetes/environment_groups"
 	"github.com/porter-dev/porter/internal/kubernetes/porter_app"
 	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/secretref"
 	"github.com/porter-dev/porter/internal/telemetry"
 	"github.com/porter-dev/porter/internal/templater/utils"
 	"github.com/stefanmcshane/helm/pkg/chart"
@@ -75,6 +76,8 @@ type SubdomainCreateOpts struct {
 	dnsClient     *dns.Client
 	appRootDomain string
 	stackName     string
+	// secretReferenceResolver resolves secret references when environment groups are synced to the app namespace
+	secretReferenceResolver *secretref.Resolver
 }
 
 type ParseConf struct {
@@ -291,7 +294,7 @@ func buildUmbrellaChartValues(
 			return nil, fmt.Errorf("error validating service \"%s\": %s", name, validateErr)
 		}
 
-		err := syncEnvironmentGroupToNamespaceIfLabelsExist(ctx, opts.k8sAgent, service, namespace)
+		err := syncEnvironmentGroupToNamespaceIfLabelsExist(ctx, opts.k8sAgent, opts.secretReferenceResolver, service, namespace)
 		if err != nil {
 			return nil, fmt.Errorf("error syncing environment group to namespace: %w", err)
 		}
@@ -348,7 +351,7 @@ func buildUmbrellaChartValues(
 }
 
 // syncEnvironmentGroupToNamespaceIfLabelsExist will sync the latest version of the environment group to the target namespace if the service has the appropriate label.
-func syncEnvironmentGroupToNamespaceIfLabelsExist(ctx context.Context, agent *kubernetes.Agent, service *Service, targetNamespace string) error {
+func syncEnvironmentGroupToNamespaceIfLabelsExist(ctx context.Context, agent *kubernetes.Agent, secretReferenceResolver *secretref.Resolver, service *Service, targetNamespace string) error {
 	var linkedGroupNames string
 
 	// patchwork because we are not consistent with the type of labels
@@ -370,6 +373,7 @@ func syncEnvironmentGroupToNamespaceIfLabelsExist(ctx context.Context, agent *ku
 		inp := environment_groups.SyncLatestVersionToNamespaceInput{
 			BaseEnvironmentGroupName: linkedGroupName,
 			TargetNamespace:          targetNamespace,
+			SecretReferenceResolver:  secretReferenceResolver,
 		}
 
 		syncedEnvironment, err := environment_groups.SyncLatestVersionToNamespace(ctx, agent, inp, nil)
@@ -0,0 +1,338 @@
+package porter_app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+	"k8s.io/utils/pointer"
+)
+
+// ReportPreviewSeedHandler handles the POST /apps/{porter_app_name}/preview-seed endpoint
+type ReportPreviewSeedHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewReportPreviewSeedHandler returns a new ReportPreviewSeedHandler
+func NewReportPreviewSeedHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ReportPreviewSeedHandler {
+	return &ReportPreviewSeedHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP records the result of the seed steps of a preview environment in a SEED app event, and comments the result
+// on the pull request that the preview environment was created for
+func (c *ReportPreviewSeedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-report-preview-seed")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.ReportPreviewSeedRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "deployment-target-id", Value: request.DeploymentTargetID},
+		telemetry.AttributeKV{Key: "pr-number", Value: request.PRNumber},
+		telemetry.AttributeKV{Key: "reseed", Value: request.Reseed},
+		telemetry.AttributeKV{Key: "step-count", Value: len(request.Steps)},
+	)
+
+	app, err := porterAppByName(c.Repo(), cluster.ID, appName)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errPorterAppNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	deploymentTarget, err := appDeploymentTarget(ctx, appDeploymentTargetInput{
+		ProjectID:          project.ID,
+		ClusterID:          cluster.ID,
+		DeploymentTargetID: request.DeploymentTargetID,
+		CCPClient:          c.Config().ClusterControlPlaneClient,
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error getting deployment target")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	if !deploymentTarget.IsPreview {
+		err := telemetry.Error(ctx, span, nil, "only preview environments can be seeded")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	deploymentTargetID, err := uuid.Parse(deploymentTarget.ID)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error parsing deployment target id")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	seed := types.PreviewSeed{
+		CommitSHA: request.CommitSHA,
+		Reseed:    request.Reseed,
+		Succeeded: true,
+		Steps:     request.Steps,
+	}
+	// steps are also skipped when their work is already done, such as a datastore which was cloned by a previous seed,
+	// so only failed steps fail the seed
+	for _, step := range request.Steps {
+		if step.Status == types.PreviewSeedStepStatus_Failed || step.Status == types.PreviewSeedStepStatus_TimedOut {
+			seed.Succeeded = false
+		}
+	}
+
+	metadata, err := previewSeedEventMetadata(seed)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error encoding seed event metadata")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	status := types.PorterAppEventStatus_Success
+	if !seed.Succeeded {
+		status = types.PorterAppEventStatus_Failed
+	}
+
+	event := &models.PorterAppEvent{
+		ID:                 uuid.New(),
+		Status:             string(status),
+		Type:               string(types.PorterAppEventType_Seed),
+		PorterAppID:        app.ID,
+		DeploymentTargetID: deploymentTargetID,
+		Metadata:           metadata,
+	}
+
+	if err := c.Repo().PorterAppEvent().CreateEvent(ctx, event); err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating seed event")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	seed.EventID = event.ID.String()
+	seed.CreatedAt = event.CreatedAt
+
+	// the seed has been recorded, so a comment which cannot be written does not fail the request
+	if request.PRNumber != 0 && app.GitRepoID != 0 {
+		err = writePreviewSeedComment(ctx, writePreviewSeedCommentInput{
+			seed:            seed,
+			porterApp:       app,
+			prNumber:        request.PRNumber,
+			dashboardURL:    fmt.Sprintf("%s/preview-environments/apps/%s?target=%s", c.Config().ServerConf.ServerURL, app.Name, deploymentTarget.ID),
+			githubAppSecret: c.Config().ServerConf.GithubAppSecret,
+			githubAppID:     c.Config().ServerConf.GithubAppID,
+		})
+		if err != nil {
+			_ = telemetry.Error(ctx, span, err, "error writing seed pr comment")
+		}
+	}
+
+	c.WriteResult(w, r, &types.ReportPreviewSeedResponse{Seed: seed})
+}
+
+// previewSeedEventMetadata encodes a preview seed as the metadata of its SEED app event
+func previewSeedEventMetadata(seed types.PreviewSeed) (map[string]any, error) {
+	by, err := json.Marshal(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := make(map[string]any)
+	if err := json.Unmarshal(by, &metadata); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// previewSeedFromEvent decodes a preview seed from the metadata of its SEED app event
+func previewSeedFromEvent(event *models.PorterAppEvent) (types.PreviewSeed, error) {
+	seed := types.PreviewSeed{}
+
+	by, err := json.Marshal(event.Metadata)
+	if err != nil {
+		return seed, err
+	}
+
+	if err := json.Unmarshal(by, &seed); err != nil {
+		return seed, err
+	}
+
+	seed.EventID = event.ID.String()
+	seed.CreatedAt = event.CreatedAt
+
+	return seed, nil
+}
+
+type writePreviewSeedCommentInput struct {
+	seed         types.PreviewSeed
+	porterApp    *models.PorterApp
+	prNumber     int
+	dashboardURL string
+
+	githubAppSecret []byte
+	githubAppID     string
+}
+
+func writePreviewSeedComment(ctx context.Context, inp writePreviewSeedCommentInput) error {
+	ctx, span := telemetry.NewSpan(ctx, "write-preview-seed-comment")
+	defer span.End()
+
+	if inp.githubAppSecret == nil {
+		return telemetry.Error(ctx, span, nil, "github app secret is empty")
+	}
+	if inp.githubAppID == "" {
+		return telemetry.Error(ctx, span, nil, "github app id is empty")
+	}
+
+	client, err := porter_app.GetGithubClientByRepoID(ctx, inp.porterApp.GitRepoID, inp.githubAppSecret, inp.githubAppID)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error getting github client")
+	}
+
+	repoDetails := strings.Split(inp.porterApp.RepoName, "/")
+	if len(repoDetails) != 2 {
+		return telemetry.Error(ctx, span, nil, "repo name is not in the format <org>/<repo>")
+	}
+
+	_, _, err = client.Issues.CreateComment(
+		ctx,
+		repoDetails[0],
+		repoDetails[1],
+		inp.prNumber,
+		&github.IssueComment{
+			Body: pointer.String(porter_app.PreviewSeedCommentBody(inp.seed, inp.dashboardURL)),
+		},
+	)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error creating github comment")
+	}
+
+	return nil
+}
+
+// GetPreviewSeedHandler handles the GET /apps/{porter_app_name}/preview-seed endpoint
+type GetPreviewSeedHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewGetPreviewSeedHandler returns a new GetPreviewSeedHandler
+func NewGetPreviewSeedHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *GetPreviewSeedHandler {
+	return &GetPreviewSeedHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP returns the latest seed of a preview environment, which is used to only seed a preview environment once
+// unless a re-seed is requested
+func (c *GetPreviewSeedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-preview-seed")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.GetPreviewSeedRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "deployment-target-id", Value: request.DeploymentTargetID},
+	)
+
+	deploymentTargetID, err := uuid.Parse(request.DeploymentTargetID)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error parsing deployment target id")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	app, err := porterAppByName(c.Repo(), cluster.ID, appName)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errPorterAppNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	res := &types.GetPreviewSeedResponse{}
+
+	event, err := c.Repo().PorterAppEvent().ReadLatestEventByType(ctx, app.ID, deploymentTargetID, string(types.PorterAppEventType_Seed))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.WriteResult(w, r, res)
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading seed event")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	seed, err := previewSeedFromEvent(&event)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error decoding seed from event")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	res.Seed = &seed
+
+	c.WriteResult(w, r, res)
+}
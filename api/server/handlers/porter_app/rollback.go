@@ -70,6 +70,13 @@ func (c *RollbackPorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	secretReferenceResolver, err := newSecretReferenceResolver(ctx, c.Config(), cluster)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating secret reference resolver")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
 	helmReleaseFromRequestedRevision, err := helmAgent.GetRelease(ctx, appName, request.Revision, false)
 	if err != nil {
 		err = telemetry.Error(ctx, span, err, "error getting helm release for requested revision")
@@ -121,11 +128,12 @@ func (c *RollbackPorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 			ProjectID:     cluster.ProjectID,
 			Namespace:     namespace,
 			SubdomainCreateOpts: SubdomainCreateOpts{
-				k8sAgent:      k8sAgent,
-				dnsRepo:       c.Repo().DNSRecord(),
-				dnsClient:     c.Config().DNSClient,
-				appRootDomain: c.Config().ServerConf.AppRootDomain,
-				stackName:     appName,
+				k8sAgent:                k8sAgent,
+				dnsRepo:                 c.Repo().DNSRecord(),
+				dnsClient:               c.Config().DNSClient,
+				appRootDomain:           c.Config().ServerConf.AppRootDomain,
+				stackName:               appName,
+				secretReferenceResolver: secretReferenceResolver,
 			},
 			InjectLauncherToStartCommand: injectLauncher,
 			FullHelmValues:               string(valuesYaml),
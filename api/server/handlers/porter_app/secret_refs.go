@@ -0,0 +1,28 @@
+package porter_app
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/ee/integrations/vault"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/secretref"
+)
+
+// newSecretReferenceResolver creates the resolver for the secret references in the environment groups synced to a cluster
+func newSecretReferenceResolver(ctx context.Context, conf *config.Config, cluster *models.Cluster) (*secretref.Resolver, error) {
+	refConf := conf.ServerConf.SecretReferenceConf
+
+	inp := secretref.NewClusterResolverInput{
+		Cluster:           cluster,
+		Repo:              conf.Repo,
+		VaultMount:        refConf.SecretReferenceVaultMount,
+		VaultProjectsPath: refConf.SecretReferenceVaultProjectsPath,
+	}
+
+	if refConf.SecretReferenceVaultServerURL != "" && refConf.SecretReferenceVaultToken != "" {
+		inp.Vault = vault.NewClient(refConf.SecretReferenceVaultServerURL, refConf.SecretReferenceVaultToken, "")
+	}
+
+	return secretref.NewClusterResolver(ctx, inp)
+}
@@ -0,0 +1,146 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/porter-dev/api-contracts/generated/go/porter/v1/porterv1connect"
+	"github.com/porter-dev/porter/internal/deployment_target"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/telemetry"
+	v1 "k8s.io/api/core/v1"
+)
+
+// errServicePodNotFound is returned by servicePod when no suitable pod exists for the service
+var errServicePodNotFound = errors.New("no matching pod found for service")
+
+// servicePodInput is the input to servicePod
+type servicePodInput struct {
+	ProjectID            uint
+	ClusterID            uint
+	AppName              string
+	ServiceName          string
+	DeploymentTargetID   string
+	DeploymentTargetName string
+	// PodName selects a specific replica of the service. If empty, a healthy replica is chosen
+	PodName   string
+	Agent     *kubernetes.Agent
+	CCPClient porterv1connect.ClusterControlPlaneServiceClient
+}
+
+// servicePod resolves the deployment target of an app and returns a running pod of the given service. Pods are
+// always looked up by the app's labels, so a caller can only reach pods that belong to the app they have access to.
+func servicePod(ctx context.Context, inp servicePodInput) (deployment_target.DeploymentTarget, *v1.Pod, error) {
+	ctx, span := telemetry.NewSpan(ctx, "service-pod")
+	defer span.End()
+
+	deploymentTarget, err := appDeploymentTarget(ctx, appDeploymentTargetInput{
+		ProjectID:            inp.ProjectID,
+		ClusterID:            inp.ClusterID,
+		DeploymentTargetID:   inp.DeploymentTargetID,
+		DeploymentTargetName: inp.DeploymentTargetName,
+		CCPClient:            inp.CCPClient,
+	})
+	if err != nil {
+		return deploymentTarget, nil, telemetry.Error(ctx, span, err, "error getting deployment target")
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "namespace", Value: deploymentTarget.Namespace},
+		telemetry.AttributeKV{Key: "deployment-target-id", Value: deploymentTarget.ID},
+	)
+
+	selectors := fmt.Sprintf("porter.run/service-name=%s,porter.run/deployment-target-id=%s,porter.run/app-name=%s", inp.ServiceName, deploymentTarget.ID, inp.AppName)
+	podsList, err := inp.Agent.GetPodsByLabel(selectors, deploymentTarget.Namespace)
+	if err != nil {
+		return deploymentTarget, nil, telemetry.Error(ctx, span, err, "unable to get pods by label")
+	}
+
+	if inp.PodName == "" {
+		pod, err := kubernetes.SelectPortForwardPod(podsList.Items)
+		if err != nil {
+			return deploymentTarget, nil, fmt.Errorf("%w: %s", errServicePodNotFound, err.Error())
+		}
+
+		return deploymentTarget, pod, nil
+	}
+
+	for i := range podsList.Items {
+		pod := &podsList.Items[i]
+
+		if pod.Name == inp.PodName {
+			if pod.Status.Phase != v1.PodRunning {
+				return deploymentTarget, nil, fmt.Errorf("%w: pod %s is not running", errServicePodNotFound, pod.Name)
+			}
+
+			return deploymentTarget, pod, nil
+		}
+	}
+
+	return deploymentTarget, nil, fmt.Errorf("%w: pod %s does not belong to service %s", errServicePodNotFound, inp.PodName, inp.ServiceName)
+}
+
+// appDeploymentTargetInput is the input to appDeploymentTarget
+type appDeploymentTargetInput struct {
+	ProjectID            uint
+	ClusterID            uint
+	DeploymentTargetID   string
+	DeploymentTargetName string
+	CCPClient            porterv1connect.ClusterControlPlaneServiceClient
+}
+
+// appDeploymentTarget returns the deployment target with the given id or name, or the default deployment target
+// of the cluster if neither is set
+func appDeploymentTarget(ctx context.Context, inp appDeploymentTargetInput) (deployment_target.DeploymentTarget, error) {
+	ctx, span := telemetry.NewSpan(ctx, "app-deployment-target")
+	defer span.End()
+
+	deploymentTargetName := inp.DeploymentTargetName
+	if inp.DeploymentTargetName == "" && inp.DeploymentTargetID == "" {
+		defaultDeploymentTarget, err := defaultDeploymentTarget(ctx, defaultDeploymentTargetInput{
+			ProjectID:                 inp.ProjectID,
+			ClusterID:                 inp.ClusterID,
+			ClusterControlPlaneClient: inp.CCPClient,
+		})
+		if err != nil {
+			return deployment_target.DeploymentTarget{}, telemetry.Error(ctx, span, err, "error getting default deployment target")
+		}
+		deploymentTargetName = defaultDeploymentTarget.Name
+	}
+
+	deploymentTarget, err := deployment_target.DeploymentTargetDetails(ctx, deployment_target.DeploymentTargetDetailsInput{
+		ProjectID:            int64(inp.ProjectID),
+		ClusterID:            int64(inp.ClusterID),
+		DeploymentTargetID:   inp.DeploymentTargetID,
+		DeploymentTargetName: deploymentTargetName,
+		CCPClient:            inp.CCPClient,
+	})
+	if err != nil {
+		return deploymentTarget, telemetry.Error(ctx, span, err, "error getting deployment target details")
+	}
+
+	return deploymentTarget, nil
+}
+
+// defaultContainerAnnotation is the annotation used by kubectl to select the container for exec and copy
+const defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+// podContainer returns the named container of the pod, or the pod's default container if name is empty
+func podContainer(pod *v1.Pod, name string) (string, error) {
+	if name == "" {
+		name = pod.Annotations[defaultContainerAnnotation]
+	}
+
+	if name == "" && len(pod.Spec.Containers) > 0 {
+		return pod.Spec.Containers[0].Name, nil
+	}
+
+	for _, container := range pod.Spec.Containers {
+		if container.Name == name {
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: container %s not found in pod %s", errServicePodNotFound, name, pod.Name)
+}
@@ -0,0 +1,85 @@
+package project
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// CreateLogSearchHandler handles the POST /projects/{project_id}/log_searches endpoint
+type CreateLogSearchHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewCreateLogSearchHandler returns a new CreateLogSearchHandler
+func NewCreateLogSearchHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateLogSearchHandler {
+	return &CreateLogSearchHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP saves a log search query for the project, after checking that the query parses
+func (c *CreateLogSearchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-create-log-search")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.CreateLogSearchRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "log-search-name", Value: request.Name},
+		telemetry.AttributeKV{Key: "app-name", Value: request.AppName},
+	)
+
+	if _, err := porter_app.ParseLogQuery(request.Query); err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid log query")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	_, err := c.Repo().LogSearch().ReadLogSearchByName(project.ID, request.Name)
+	if err == nil {
+		err = telemetry.Error(ctx, span, nil, "a log search with this name already exists")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		err = telemetry.Error(ctx, span, err, "error reading log search")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	search, err := c.Repo().LogSearch().CreateLogSearch(&models.LogSearch{
+		ProjectID: project.ID,
+		Name:      request.Name,
+		Query:     request.Query,
+		AppName:   request.AppName,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating log search")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	c.WriteResult(w, r, types.CreateLogSearchResponse{LogSearch: search.ToLogSearchType()})
+}
@@ -0,0 +1,69 @@
+package project
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// DeleteLogSearchHandler handles the DELETE /projects/{project_id}/log_searches/{log_search_id} endpoint
+type DeleteLogSearchHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewDeleteLogSearchHandler returns a new DeleteLogSearchHandler
+func NewDeleteLogSearchHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *DeleteLogSearchHandler {
+	return &DeleteLogSearchHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP deletes a saved log search
+func (c *DeleteLogSearchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-delete-log-search")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	searchID, reqErr := requestutils.GetURLParamUint(r, types.URLParamLogSearchID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving log search id")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "log-search-id", Value: searchID})
+
+	search, err := c.Repo().LogSearch().ReadLogSearch(project.ID, searchID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading log search")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	if err := c.Repo().LogSearch().DeleteLogSearch(search); err != nil {
+		err = telemetry.Error(ctx, span, err, "error deleting log search")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
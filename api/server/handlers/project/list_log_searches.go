@@ -0,0 +1,50 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// ListLogSearchesHandler handles the GET /projects/{project_id}/log_searches endpoint
+type ListLogSearchesHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewListLogSearchesHandler returns a new ListLogSearchesHandler
+func NewListLogSearchesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListLogSearchesHandler {
+	return &ListLogSearchesHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP lists the saved log searches of the project
+func (c *ListLogSearchesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-log-searches")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	searches, err := c.Repo().LogSearch().ListLogSearches(project.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing log searches")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res := types.ListLogSearchesResponse{LogSearches: []types.LogSearch{}}
+	for _, search := range searches {
+		res.LogSearches = append(res.LogSearches, search.ToLogSearchType())
+	}
+
+	c.WriteResult(w, r, res)
+}
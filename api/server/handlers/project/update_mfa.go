@@ -0,0 +1,81 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/auth/mfa"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// UpdateProjectMFAHandler updates whether collaborators must have MFA enabled to access a project
+type UpdateProjectMFAHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdateProjectMFAHandler returns an UpdateProjectMFAHandler
+func NewUpdateProjectMFAHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateProjectMFAHandler {
+	return &UpdateProjectMFAHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (p *UpdateProjectMFAHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-project-mfa")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	request := &types.UpdateProjectMFARequest{}
+	if ok := p.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: proj.ID},
+		telemetry.AttributeKV{Key: "require-mfa", Value: request.RequireMFA},
+	)
+
+	// the user enabling the requirement must satisfy it, so that they do not lock themselves out
+	if request.RequireMFA {
+		if user.ID == 0 {
+			err := telemetry.Error(ctx, span, nil, "mfa requirement must be enabled by a user")
+			p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		methods, err := mfa.EnabledMethods(p.Repo().MFA(), user.ID)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error reading mfa methods")
+			p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		if len(methods) == 0 {
+			err = telemetry.Error(ctx, span, nil, "you must enable multi-factor authentication before requiring it for the project")
+			p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+	}
+
+	proj.RequireMFA = request.RequireMFA
+
+	project, err := p.Repo().Project().UpdateProject(proj)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating project")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	p.WriteResult(w, r, project.ToProjectType(p.Config().LaunchDarklyClient))
+}
@@ -0,0 +1,117 @@
+package status_page
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// CreateIncidentHandler handles the POST /projects/{project_id}/status-page/incidents endpoint
+type CreateIncidentHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewCreateIncidentHandler returns a new CreateIncidentHandler
+func NewCreateIncidentHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateIncidentHandler {
+	return &CreateIncidentHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP posts an incident on the status page of the project
+func (c *CreateIncidentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-create-status-page-incident")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.CreateStatusPageIncidentRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "component-id", Value: request.ComponentID},
+		telemetry.AttributeKV{Key: "severity", Value: string(request.Severity)},
+	)
+
+	page, err := c.Repo().StatusPage().ReadStatusPage(project.ID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading status page")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	if request.ComponentID != 0 {
+		components, err := c.Repo().StatusPage().ListStatusPageComponents(page.ID)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error listing status page components")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		found := false
+		for _, component := range components {
+			if component.ID == request.ComponentID {
+				found = true
+			}
+		}
+
+		if !found {
+			err = telemetry.Error(ctx, span, nil, "component not found on status page")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+	}
+
+	status := request.Status
+	if status == "" {
+		status = types.StatusPageIncidentStatus_Investigating
+	}
+
+	now := time.Now().UTC()
+
+	incident := &models.StatusPageIncident{
+		StatusPageID: page.ID,
+		ComponentID:  request.ComponentID,
+		Title:        request.Title,
+		Message:      request.Message,
+		Status:       status,
+		Severity:     request.Severity,
+		StartedAt:    now,
+	}
+
+	if status == types.StatusPageIncidentStatus_Resolved {
+		incident.ResolvedAt = &now
+	}
+
+	incident, err = c.Repo().StatusPage().CreateStatusPageIncident(incident)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating status page incident")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	c.WriteResult(w, r, types.StatusPageIncidentResponse{Incident: incident.ToStatusPageIncidentType()})
+}
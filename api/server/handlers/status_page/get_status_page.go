@@ -0,0 +1,66 @@
+package status_page
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/statuspage"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// GetStatusPageHandler handles the GET /projects/{project_id}/status-page endpoint
+type GetStatusPageHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewGetStatusPageHandler returns a new GetStatusPageHandler
+func NewGetStatusPageHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetStatusPageHandler {
+	return &GetStatusPageHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP returns the configuration of the status page of the project along with its current summary
+func (c *GetStatusPageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-status-page")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	page, err := c.Repo().StatusPage().ReadStatusPage(project.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.WriteResult(w, r, types.GetStatusPageResponse{})
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading status page")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	components, summary, err := statuspage.Load(ctx, c.Repo(), page, time.Now().UTC())
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error loading status page")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	statusPage := page.ToStatusPageType(components)
+
+	c.WriteResult(w, r, types.GetStatusPageResponse{
+		StatusPage: &statusPage,
+		Summary:    &summary,
+	})
+}
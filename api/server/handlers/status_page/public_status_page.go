@@ -0,0 +1,170 @@
+package status_page
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/statuspage"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// FeedFormat is the format of a status page feed
+type FeedFormat string
+
+const (
+	// FeedFormat_RSS is an RSS 2.0 feed
+	FeedFormat_RSS FeedFormat = "rss"
+	// FeedFormat_JSON is a JSON Feed
+	FeedFormat_JSON FeedFormat = "json"
+)
+
+// GetPublicStatusPageHandler handles the GET /status-pages/{status_page_slug} endpoint, which does not require
+// authentication
+type GetPublicStatusPageHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewGetPublicStatusPageHandler returns a new GetPublicStatusPageHandler
+func NewGetPublicStatusPageHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetPublicStatusPageHandler {
+	return &GetPublicStatusPageHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP returns the summary of a public status page. Private pages are reported as not found.
+func (c *GetPublicStatusPageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-public-status-page")
+	defer span.End()
+
+	summary, ok := loadPublicSummary(w, r.WithContext(ctx), c.PorterHandlerWriter)
+	if !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "slug", Value: summary.Slug})
+
+	w.Header().Set("Cache-Control", "public, max-age=30")
+	c.WriteResult(w, r, summary)
+}
+
+// GetPublicStatusPageFeedHandler handles the GET /status-pages/{status_page_slug}/feed.rss and
+// GET /status-pages/{status_page_slug}/feed.json endpoints, which do not require authentication
+type GetPublicStatusPageFeedHandler struct {
+	handlers.PorterHandlerWriter
+
+	format FeedFormat
+}
+
+// NewGetPublicStatusPageFeedHandler returns a new GetPublicStatusPageFeedHandler for a feed format
+func NewGetPublicStatusPageFeedHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+	format FeedFormat,
+) *GetPublicStatusPageFeedHandler {
+	return &GetPublicStatusPageFeedHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+		format:              format,
+	}
+}
+
+// ServeHTTP returns the incidents of a public status page as a feed
+func (c *GetPublicStatusPageFeedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-public-status-page-feed")
+	defer span.End()
+
+	summary, ok := loadPublicSummary(w, r.WithContext(ctx), c.PorterHandlerWriter)
+	if !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "slug", Value: summary.Slug},
+		telemetry.AttributeKV{Key: "format", Value: string(c.format)},
+	)
+
+	link := fmt.Sprintf("%s/api/status-pages/%s", strings.TrimSuffix(c.Config().ServerConf.ServerURL, "/"), summary.Slug)
+
+	var feed []byte
+	var err error
+	var contentType string
+
+	switch c.format {
+	case FeedFormat_RSS:
+		feed, err = statuspage.RSS(summary, link)
+		contentType = "application/rss+xml; charset=utf-8"
+	default:
+		feed, err = statuspage.JSONFeed(summary, link, link+"/feed.json")
+		contentType = "application/feed+json; charset=utf-8"
+	}
+
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error generating feed")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	w.WriteHeader(http.StatusOK)
+	w.Write(feed) // nolint:errcheck
+}
+
+// loadPublicSummary reads the status page of the slug in the request and summarizes it, writing an error and returning
+// false if the page does not exist or is not public
+func loadPublicSummary(w http.ResponseWriter, r *http.Request, c handlers.PorterHandlerWriter) (types.StatusPageSummary, bool) {
+	ctx, span := telemetry.NewSpan(r.Context(), "load-public-status-page")
+	defer span.End()
+
+	slug, reqErr := requestutils.GetURLParamString(r, types.URLParamStatusPageSlug)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving status page slug")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return types.StatusPageSummary{}, false
+	}
+
+	page, err := c.Repo().StatusPage().ReadStatusPageBySlug(slug)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		err = telemetry.Error(ctx, span, err, "error reading status page")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return types.StatusPageSummary{}, false
+	}
+
+	if page == nil || !page.Public {
+		err := telemetry.Error(ctx, span, nil, "status page not found")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+		return types.StatusPageSummary{}, false
+	}
+
+	_, summary, err := statuspage.Load(ctx, c.Repo(), page, time.Now().UTC())
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error loading status page")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return types.StatusPageSummary{}, false
+	}
+
+	return publicSummary(summary), true
+}
+
+// publicSummary removes the messages of automatic incidents, which contain the raw errors of the synthetic checks
+func publicSummary(summary types.StatusPageSummary) types.StatusPageSummary {
+	for i := range summary.Incidents {
+		if summary.Incidents[i].Automatic {
+			summary.Incidents[i].Message = ""
+		}
+	}
+
+	return summary
+}
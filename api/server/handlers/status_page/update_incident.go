@@ -0,0 +1,108 @@
+package status_page
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// UpdateIncidentHandler handles the PATCH /projects/{project_id}/status-page/incidents/{status_page_incident_id} endpoint
+type UpdateIncidentHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdateIncidentHandler returns a new UpdateIncidentHandler
+func NewUpdateIncidentHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateIncidentHandler {
+	return &UpdateIncidentHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP posts an update to an incident of the status page of the project. Resolved incidents can be reopened by
+// setting any other status.
+func (c *UpdateIncidentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-status-page-incident")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	incidentID, reqErr := requestutils.GetURLParamUint(r, types.URLParamStatusPageIncidentID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error retrieving incident id")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.UpdateStatusPageIncidentRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "incident-id", Value: incidentID},
+		telemetry.AttributeKV{Key: "status", Value: string(request.Status)},
+	)
+
+	page, err := c.Repo().StatusPage().ReadStatusPage(project.ID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading status page")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	incident, err := c.Repo().StatusPage().ReadStatusPageIncident(page.ID, incidentID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading status page incident")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	incident.Status = request.Status
+	if request.Message != "" {
+		incident.Message = request.Message
+	}
+
+	if request.Status == types.StatusPageIncidentStatus_Resolved {
+		if incident.ResolvedAt == nil {
+			now := time.Now().UTC()
+			incident.ResolvedAt = &now
+		}
+	} else {
+		incident.ResolvedAt = nil
+	}
+
+	incident, err = c.Repo().StatusPage().UpdateStatusPageIncident(incident)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating status page incident")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	c.WriteResult(w, r, types.StatusPageIncidentResponse{Incident: incident.ToStatusPageIncidentType()})
+}
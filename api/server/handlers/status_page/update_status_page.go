@@ -0,0 +1,231 @@
+package status_page
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/statuspage"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// UpdateStatusPageHandler handles the PUT /projects/{project_id}/status-page endpoint
+type UpdateStatusPageHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdateStatusPageHandler returns a new UpdateStatusPageHandler
+func NewUpdateStatusPageHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateStatusPageHandler {
+	return &UpdateStatusPageHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP creates or replaces the status page of the project. Components are matched to the existing components of
+// the page by id, and existing components which are not in the request are removed.
+func (c *UpdateStatusPageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-status-page")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.UpdateStatusPageRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "slug", Value: request.Slug},
+		telemetry.AttributeKV{Key: "public", Value: request.Public},
+		telemetry.AttributeKV{Key: "num-components", Value: len(request.Components)},
+	)
+
+	if err := statuspage.ValidateSlug(request.Slug); err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid slug")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	for _, component := range request.Components {
+		if err := c.validateComponent(project, component); err != nil {
+			err = telemetry.Error(ctx, span, err, "invalid component")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+	}
+
+	existingPage, err := c.Repo().StatusPage().ReadStatusPageBySlug(request.Slug)
+	if err == nil && existingPage.ProjectID != project.ID {
+		err = telemetry.Error(ctx, span, nil, "a status page with this slug already exists")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		err = telemetry.Error(ctx, span, err, "error reading status page by slug")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	page, err := c.Repo().StatusPage().ReadStatusPage(project.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		err = telemetry.Error(ctx, span, err, "error reading status page")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		page, err = c.Repo().StatusPage().CreateStatusPage(&models.StatusPage{
+			ProjectID: project.ID,
+			Slug:      request.Slug,
+			Title:     request.Title,
+			Public:    request.Public,
+		})
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error creating status page")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	} else {
+		page.Slug = request.Slug
+		page.Title = request.Title
+		page.Public = request.Public
+
+		page, err = c.Repo().StatusPage().UpdateStatusPage(page)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error updating status page")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	components, err := c.reconcileComponents(page, request.Components)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating status page components")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	c.WriteResult(w, r, types.UpdateStatusPageResponse{StatusPage: page.ToStatusPageType(components)})
+}
+
+// validateComponent checks the fields of a component, and that its cluster belongs to the project
+func (c *UpdateStatusPageHandler) validateComponent(project *models.Project, component types.StatusPageComponent) error {
+	if err := statuspage.ValidateComponent(component); err != nil {
+		return err
+	}
+
+	if component.ClusterID == 0 {
+		return nil
+	}
+
+	if _, err := c.Repo().Cluster().ReadCluster(project.ID, component.ClusterID); err != nil {
+		return fmt.Errorf("cluster %d of component %q not found in project", component.ClusterID, component.Name)
+	}
+
+	return nil
+}
+
+// reconcileComponents creates, updates and deletes the components of a status page so that they match the request
+func (c *UpdateStatusPageHandler) reconcileComponents(page *models.StatusPage, requested []types.StatusPageComponent) ([]*models.StatusPageComponent, error) {
+	existing, err := c.Repo().StatusPage().ListStatusPageComponents(page.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing status page components: %w", err)
+	}
+
+	existingByID := make(map[uint]*models.StatusPageComponent, len(existing))
+	for _, component := range existing {
+		existingByID[component.ID] = component
+	}
+
+	for _, component := range requested {
+		if component.ID != 0 && existingByID[component.ID] == nil {
+			return nil, fmt.Errorf("component %d not found on status page", component.ID)
+		}
+	}
+
+	kept := make(map[uint]bool)
+	res := make([]*models.StatusPageComponent, 0, len(requested))
+
+	for i, component := range requested {
+		if component.ID == 0 {
+			created, err := c.Repo().StatusPage().CreateStatusPageComponent(&models.StatusPageComponent{
+				StatusPageID: page.ID,
+				Position:     i,
+				Name:         component.Name,
+				Type:         component.Type,
+				ClusterID:    component.ClusterID,
+				AppName:      component.AppName,
+				URL:          component.URL,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error creating component %q: %w", component.Name, err)
+			}
+
+			res = append(res, created)
+			continue
+		}
+
+		model := existingByID[component.ID]
+		kept[model.ID] = true
+
+		// the check state only applies to the url it was recorded for
+		if model.URL != component.URL {
+			model.LastCheckedAt = nil
+			model.LastLatencyMs = 0
+			model.LastCheckError = ""
+			model.ConsecutiveFailures = 0
+		}
+
+		model.Position = i
+		model.Name = component.Name
+		model.Type = component.Type
+		model.ClusterID = component.ClusterID
+		model.AppName = component.AppName
+		model.URL = component.URL
+
+		updated, err := c.Repo().StatusPage().UpdateStatusPageComponent(model)
+		if err != nil {
+			return nil, fmt.Errorf("error updating component %q: %w", component.Name, err)
+		}
+
+		res = append(res, updated)
+	}
+
+	for _, component := range existing {
+		if kept[component.ID] {
+			continue
+		}
+
+		// automatic incidents of removed components would otherwise never be resolved
+		incident, err := c.Repo().StatusPage().ReadOpenAutomaticStatusPageIncident(page.ID, component.ID)
+		if err == nil {
+			statuspage.ResolveIncident(incident, "The component was removed from the status page", time.Now().UTC())
+
+			if _, err := c.Repo().StatusPage().UpdateStatusPageIncident(incident); err != nil {
+				return nil, fmt.Errorf("error resolving incident of component %q: %w", component.Name, err)
+			}
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("error reading incident of component %q: %w", component.Name, err)
+		}
+
+		if err := c.Repo().StatusPage().DeleteStatusPageComponent(component); err != nil {
+			return nil, fmt.Errorf("error deleting component %q: %w", component.Name, err)
+		}
+	}
+
+	return res, nil
+}
@@ -54,6 +54,11 @@ func (c *CLILoginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the CLI token counts as verified only if the login it was issued from completed a second factor
+	if mfaVerifiedAt, ok := r.Context().Value(types.MFAVerifiedAtCtxKey).(time.Time); ok {
+		jwt.MFAVerifiedAt = &mfaVerifiedAt
+	}
+
 	encoded, err := jwt.EncodeToken(c.Config().TokenConf)
 	if err != nil {
 		err = fmt.Errorf("CLI token encoding failed: %s", err.Error())
@@ -12,6 +12,7 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/server/shared/config/env"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/auth/mfa"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -61,6 +62,27 @@ func (u *UserLoginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	methods, err := mfa.EnabledMethods(u.Repo().MFA(), storedUser.ID)
+	if err != nil {
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// if the user has a second factor configured, the session is only authenticated once
+	// the second factor is verified through POST /api/login/mfa
+	if len(methods) > 0 {
+		if err := authn.SaveUserMFAPending(w, r, u.Config(), storedUser); err != nil {
+			u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		u.WriteResult(w, r, &types.LoginMFARequiredResponse{
+			MFARequired: true,
+			Methods:     methods,
+		})
+		return
+	}
+
 	// save the user as authenticated in the session
 	redirect, err := authn.SaveUserAuthenticated(w, r, u.Config(), storedUser)
 	if err != nil {
@@ -0,0 +1,298 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authn"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/auth/mfa"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+var errInvalidSecondFactor = errors.New("invalid authentication code")
+
+// UserLoginMFAHandler completes a login for a user with multi-factor authentication enabled
+type UserLoginMFAHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUserLoginMFAHandler returns a UserLoginMFAHandler
+func NewUserLoginMFAHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UserLoginMFAHandler {
+	return &UserLoginMFAHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (u *UserLoginMFAHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-login-mfa")
+	defer span.End()
+
+	request := &types.LoginMFARequest{}
+	if ok := u.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	userID, err := authn.GetUserMFAPending(r, u.Config())
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "no pending mfa login")
+		u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusUnauthorized))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "user-id", Value: userID})
+
+	user, err := u.Repo().User().ReadUser(userID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error reading user")
+		u.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
+		return
+	}
+
+	err = verifySecondFactor(w, r, u.Config(), user, request)
+	if err != nil {
+		if errors.Is(err, errInvalidSecondFactor) {
+			// the pending login is cleared after too many invalid second factors, so codes cannot be guessed
+			if recordErr := authn.RecordUserMFAFailure(w, r, u.Config()); recordErr != nil {
+				if errors.Is(recordErr, authn.ErrMFAAttemptsExceeded) {
+					err = telemetry.Error(ctx, span, recordErr, "too many invalid second factors")
+					u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusUnauthorized))
+					return
+				}
+
+				err = telemetry.Error(ctx, span, recordErr, "error recording invalid second factor")
+				u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+				return
+			}
+
+			err = telemetry.Error(ctx, span, err, "invalid second factor")
+			u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusUnauthorized))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error verifying second factor")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	redirect, err := authn.SaveUserAuthenticatedWithMFA(w, r, u.Config(), user)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error saving session")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if redirect != "" {
+		http.Redirect(w, r, redirect, http.StatusFound)
+		return
+	}
+
+	u.WriteResult(w, r, user.ToUserType())
+}
+
+// UserLoginWebAuthnChallengeHandler issues a WebAuthn challenge for a user completing a login
+type UserLoginWebAuthnChallengeHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewUserLoginWebAuthnChallengeHandler returns a UserLoginWebAuthnChallengeHandler
+func NewUserLoginWebAuthnChallengeHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *UserLoginWebAuthnChallengeHandler {
+	return &UserLoginWebAuthnChallengeHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (u *UserLoginWebAuthnChallengeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-login-webauthn-challenge")
+	defer span.End()
+
+	userID, err := authn.GetUserMFAPending(r, u.Config())
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "no pending mfa login")
+		u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusUnauthorized))
+		return
+	}
+
+	creds, err := u.Repo().MFA().ListWebAuthnCredentials(userID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing webauthn credentials")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if len(creds) == 0 {
+		err = telemetry.Error(ctx, span, nil, "user has no webauthn credentials")
+		u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	res, err := newWebAuthnChallenge(w, r, u.Config())
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating webauthn challenge")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	for _, cred := range creds {
+		res.AllowCredentials = append(res.AllowCredentials, cred.CredentialID)
+	}
+
+	u.WriteResult(w, r, res)
+}
+
+// newWebAuthnChallenge creates a new challenge and stores it in the session
+func newWebAuthnChallenge(w http.ResponseWriter, r *http.Request, config *config.Config) (*types.WebAuthnChallengeResponse, error) {
+	rp, err := mfa.RelyingPartyFromServerURL(config.ServerConf.ServerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := mfa.NewWebAuthnChallenge()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authn.SaveWebAuthnChallenge(w, r, config, challenge); err != nil {
+		return nil, err
+	}
+
+	return &types.WebAuthnChallengeResponse{
+		Challenge: challenge,
+		RPID:      rp.ID,
+		RPName:    "Porter",
+	}, nil
+}
+
+// verifySecondFactor checks the TOTP code, recovery code or WebAuthn assertion in the request against
+// the user's configured second factors. It returns errInvalidSecondFactor if verification fails.
+func verifySecondFactor(
+	w http.ResponseWriter,
+	r *http.Request,
+	config *config.Config,
+	user *models.User,
+	request *types.LoginMFARequest,
+) error {
+	switch {
+	case request.Code != "":
+		return verifyTOTPCode(config, user.ID, request.Code)
+	case request.RecoveryCode != "":
+		ok, err := config.Repo.MFA().UseRecoveryCode(user.ID, mfa.HashRecoveryCode(request.RecoveryCode))
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return errInvalidSecondFactor
+		}
+
+		return nil
+	case request.WebAuthn != nil:
+		return verifyWebAuthnAssertion(w, r, config, user.ID, request.WebAuthn)
+	}
+
+	return errInvalidSecondFactor
+}
+
+func verifyTOTPCode(config *config.Config, userID uint, code string) error {
+	totp, err := config.Repo.MFA().ReadUserTOTP(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errInvalidSecondFactor
+		}
+
+		return err
+	}
+
+	if !totp.Enabled {
+		return errInvalidSecondFactor
+	}
+
+	step, ok := mfa.ValidateTOTPCode(string(totp.Secret), code, time.Now(), totp.LastUsedStep)
+	if !ok {
+		return errInvalidSecondFactor
+	}
+
+	totp.LastUsedStep = step
+
+	if _, err := config.Repo.MFA().UpdateUserTOTP(totp); err != nil {
+		return fmt.Errorf("error updating totp enrollment: %w", err)
+	}
+
+	return nil
+}
+
+func verifyWebAuthnAssertion(
+	w http.ResponseWriter,
+	r *http.Request,
+	config *config.Config,
+	userID uint,
+	assertion *types.WebAuthnAssertionResponse,
+) error {
+	challenge, err := authn.PopWebAuthnChallenge(w, r, config)
+	if err != nil {
+		return errInvalidSecondFactor
+	}
+
+	rp, err := mfa.RelyingPartyFromServerURL(config.ServerConf.ServerURL)
+	if err != nil {
+		return err
+	}
+
+	cred, err := config.Repo.MFA().ReadWebAuthnCredentialByCredentialID(userID, assertion.CredentialID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errInvalidSecondFactor
+		}
+
+		return err
+	}
+
+	clientDataJSON, err := mfa.DecodeBase64URL(assertion.ClientDataJSON)
+	if err != nil {
+		return errInvalidSecondFactor
+	}
+
+	authData, err := mfa.DecodeBase64URL(assertion.AuthenticatorData)
+	if err != nil {
+		return errInvalidSecondFactor
+	}
+
+	sig, err := mfa.DecodeBase64URL(assertion.Signature)
+	if err != nil {
+		return errInvalidSecondFactor
+	}
+
+	signCount, err := rp.VerifyAssertion(mfa.WebAuthnAssertion{
+		ClientDataJSON:    clientDataJSON,
+		AuthenticatorData: authData,
+		Signature:         sig,
+	}, challenge, cred.PublicKey, cred.SignCount)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errInvalidSecondFactor, err.Error())
+	}
+
+	now := time.Now().UTC()
+	cred.SignCount = signCount
+	cred.LastUsedAt = &now
+
+	if _, err := config.Repo.MFA().UpdateWebAuthnCredential(cred); err != nil {
+		return fmt.Errorf("error updating webauthn credential: %w", err)
+	}
+
+	return nil
+}
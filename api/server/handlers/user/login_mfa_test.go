@@ -0,0 +1,176 @@
+package user_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers/user"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/auth/mfa"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+func TestLoginUserMFARequired(t *testing.T) {
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/login",
+		&types.LoginUserRequest{
+			Email:    "mrp@porter.run",
+			Password: "hello",
+		},
+	)
+
+	config := apitest.LoadConfig(t)
+	testUser := apitest.CreateTestUser(t, config, true)
+
+	_, err := config.Repo.MFA().CreateUserTOTP(&models.UserTOTP{
+		UserID:  testUser.ID,
+		Secret:  []byte("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"),
+		Enabled: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := user.NewUserLoginHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	expResp := &types.LoginMFARequiredResponse{
+		MFARequired: true,
+		Methods:     []types.MFAMethod{types.MFAMethodTOTP, types.MFAMethodRecoveryCode},
+	}
+
+	gotResp := &types.LoginMFARequiredResponse{}
+
+	apitest.AssertResponseExpected(t, rr, expResp, gotResp)
+}
+
+func TestLoginMFAWithoutPendingLogin(t *testing.T) {
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/login/mfa",
+		&types.LoginMFARequest{
+			Code: "123456",
+		},
+	)
+
+	config := apitest.LoadConfig(t)
+	apitest.CreateTestUser(t, config, true)
+
+	handler := user.NewUserLoginMFAHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusUnauthorized, &types.ExternalError{
+		Error: "no login is waiting for a second factor",
+	})
+}
+
+func TestLoginMFATooManyFailures(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	testUser := apitest.CreateTestUser(t, config, true)
+
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	_, err := config.Repo.MFA().CreateUserTOTP(&models.UserTOTP{
+		UserID:  testUser.ID,
+		Secret:  []byte(secret),
+		Enabled: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loginHandler := user.NewUserLoginHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+
+	mfaHandler := user.NewUserLoginMFAHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/login",
+		&types.LoginUserRequest{
+			Email:    "mrp@porter.run",
+			Password: "hello",
+		},
+	)
+
+	loginHandler.ServeHTTP(rr, req)
+
+	cookies := rr.Result().Cookies()
+	if len(cookies) == 0 {
+		t.Fatal("no session cookie in login response")
+	}
+
+	cookie := cookies[0]
+
+	loginMFA := func(code string) *httptest.ResponseRecorder {
+		req, rr := apitest.GetRequestAndRecorder(
+			t,
+			string(types.HTTPVerbPost),
+			"/api/login/mfa",
+			&types.LoginMFARequest{
+				Code: code,
+			},
+		)
+		req.AddCookie(cookie)
+
+		mfaHandler.ServeHTTP(rr, req)
+
+		if cookies := rr.Result().Cookies(); len(cookies) > 0 {
+			cookie = cookies[0]
+		}
+
+		return rr
+	}
+
+	for i := 1; i < 5; i++ {
+		rr := loginMFA("000000")
+
+		apitest.AssertResponseError(t, rr, http.StatusUnauthorized, &types.ExternalError{
+			Error: "invalid authentication code",
+		})
+	}
+
+	// the fifth invalid code clears the pending login
+	rr = loginMFA("000000")
+
+	apitest.AssertResponseError(t, rr, http.StatusUnauthorized, &types.ExternalError{
+		Error: "too many invalid authentication codes, please log in again",
+	})
+
+	// a valid code can no longer complete the login without entering the password again
+	code, err := mfa.GenerateTOTPCode(secret, mfa.TOTPStep(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr = loginMFA(code)
+
+	apitest.AssertResponseError(t, rr, http.StatusUnauthorized, &types.ExternalError{
+		Error: "no login is waiting for a second factor",
+	})
+}
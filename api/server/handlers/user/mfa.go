@@ -0,0 +1,674 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authn"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/auth/mfa"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// GetMFAStatusHandler returns the second factors configured for the current user
+type GetMFAStatusHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewGetMFAStatusHandler returns a GetMFAStatusHandler
+func NewGetMFAStatusHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetMFAStatusHandler {
+	return &GetMFAStatusHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (u *GetMFAStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-mfa-status")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	res := &types.GetMFAStatusResponse{
+		WebAuthnCredentials: make([]types.WebAuthnCredential, 0),
+	}
+
+	totp, err := u.Repo().MFA().ReadUserTOTP(user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		err = telemetry.Error(ctx, span, err, "error reading totp enrollment")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res.TOTPEnabled = err == nil && totp.Enabled
+
+	creds, err := u.Repo().MFA().ListWebAuthnCredentials(user.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing webauthn credentials")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	for _, cred := range creds {
+		res.WebAuthnCredentials = append(res.WebAuthnCredentials, cred.ToWebAuthnCredentialType())
+	}
+
+	codes, err := u.Repo().MFA().ListRecoveryCodes(user.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing recovery codes")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	for _, code := range codes {
+		if code.UsedAt == nil {
+			res.RecoveryCodesRemaining++
+		}
+	}
+
+	res.Enabled = res.TOTPEnabled || len(res.WebAuthnCredentials) > 0
+
+	u.WriteResult(w, r, res)
+}
+
+// EnrollTOTPHandler starts a TOTP enrollment for the current user. The enrollment is not active
+// until it is confirmed through VerifyTOTPHandler.
+type EnrollTOTPHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewEnrollTOTPHandler returns an EnrollTOTPHandler
+func NewEnrollTOTPHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *EnrollTOTPHandler {
+	return &EnrollTOTPHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (u *EnrollTOTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-enroll-totp")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	existing, err := u.Repo().MFA().ReadUserTOTP(user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		err = telemetry.Error(ctx, span, err, "error reading totp enrollment")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err == nil && existing.Enabled {
+		err = telemetry.Error(ctx, span, nil, "totp is already enabled")
+		u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+
+	secret, err := mfa.GenerateTOTPSecret()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error generating totp secret")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// a pending enrollment is replaced, so that the most recently displayed secret is the one that is verified
+	if existing != nil {
+		existing.Secret = []byte(secret)
+		_, err = u.Repo().MFA().UpdateUserTOTP(existing)
+	} else {
+		_, err = u.Repo().MFA().CreateUserTOTP(&models.UserTOTP{
+			UserID: user.ID,
+			Secret: []byte(secret),
+		})
+	}
+
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error saving totp enrollment")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	u.WriteResult(w, r, &types.EnrollTOTPResponse{
+		Secret:          secret,
+		ProvisioningURI: mfa.TOTPProvisioningURI("Porter", user.Email, secret),
+	})
+}
+
+// VerifyTOTPHandler confirms a pending TOTP enrollment and issues recovery codes
+type VerifyTOTPHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewVerifyTOTPHandler returns a VerifyTOTPHandler
+func NewVerifyTOTPHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *VerifyTOTPHandler {
+	return &VerifyTOTPHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (u *VerifyTOTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-verify-totp")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	request := &types.VerifyTOTPRequest{}
+	if ok := u.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	totp, err := u.Repo().MFA().ReadUserTOTP(user.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "no totp enrollment found")
+			u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading totp enrollment")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if totp.Enabled {
+		err = telemetry.Error(ctx, span, nil, "totp is already enabled")
+		u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+
+	step, ok := mfa.ValidateTOTPCode(string(totp.Secret), request.Code, time.Now(), totp.LastUsedStep)
+	if !ok {
+		err = telemetry.Error(ctx, span, errInvalidSecondFactor, "invalid totp code")
+		u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	totp.Enabled = true
+	totp.LastUsedStep = step
+
+	if _, err := u.Repo().MFA().UpdateUserTOTP(totp); err != nil {
+		err = telemetry.Error(ctx, span, err, "error enabling totp")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	codes, err := generateRecoveryCodes(u.Config(), user.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error generating recovery codes")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	u.WriteResult(w, r, &types.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTOTPHandler removes the TOTP enrollment for the current user. A valid code is required.
+type DisableTOTPHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewDisableTOTPHandler returns a DisableTOTPHandler
+func NewDisableTOTPHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *DisableTOTPHandler {
+	return &DisableTOTPHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (u *DisableTOTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-disable-totp")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	request := &types.VerifyTOTPRequest{}
+	if ok := u.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if err := verifyTOTPCode(u.Config(), user.ID, request.Code); err != nil {
+		if errors.Is(err, errInvalidSecondFactor) {
+			err = telemetry.Error(ctx, span, err, "invalid totp code")
+			u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error verifying totp code")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	totp, err := u.Repo().MFA().ReadUserTOTP(user.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error reading totp enrollment")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := u.Repo().MFA().DeleteUserTOTP(totp); err != nil {
+		err = telemetry.Error(ctx, span, err, "error deleting totp enrollment")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := deleteRecoveryCodesIfMFADisabled(u.Config(), user.ID); err != nil {
+		err = telemetry.Error(ctx, span, err, "error deleting recovery codes")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// RegenerateRecoveryCodesHandler replaces the recovery codes for the current user. A valid TOTP code or
+// WebAuthn assertion is required.
+type RegenerateRecoveryCodesHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewRegenerateRecoveryCodesHandler returns a RegenerateRecoveryCodesHandler
+func NewRegenerateRecoveryCodesHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *RegenerateRecoveryCodesHandler {
+	return &RegenerateRecoveryCodesHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (u *RegenerateRecoveryCodesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-regenerate-recovery-codes")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	request := &types.ConfirmSecondFactorRequest{}
+	if ok := u.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	methods, err := mfa.EnabledMethods(u.Repo().MFA(), user.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error reading mfa methods")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if len(methods) == 0 {
+		err = telemetry.Error(ctx, span, nil, "mfa is not enabled")
+		u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	if err := confirmSecondFactor(w, r, u.Config(), user.ID, request); err != nil {
+		if errors.Is(err, errInvalidSecondFactor) {
+			err = telemetry.Error(ctx, span, err, "invalid second factor")
+			u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusForbidden))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error verifying second factor")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	codes, err := generateRecoveryCodes(u.Config(), user.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error generating recovery codes")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	u.WriteResult(w, r, &types.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// WebAuthnRegisterChallengeHandler issues a challenge for registering a new WebAuthn credential
+type WebAuthnRegisterChallengeHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewWebAuthnRegisterChallengeHandler returns a WebAuthnRegisterChallengeHandler
+func NewWebAuthnRegisterChallengeHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *WebAuthnRegisterChallengeHandler {
+	return &WebAuthnRegisterChallengeHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (u *WebAuthnRegisterChallengeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-webauthn-register-challenge")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	res, err := newWebAuthnChallenge(w, r, u.Config())
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating webauthn challenge")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res.UserHandle = mfa.WebAuthnUserHandle(user.ID)
+
+	// existing credentials are returned so that the browser does not register the same authenticator twice
+	creds, err := u.Repo().MFA().ListWebAuthnCredentials(user.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing webauthn credentials")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	for _, cred := range creds {
+		res.ExcludeCredentials = append(res.ExcludeCredentials, cred.CredentialID)
+	}
+
+	u.WriteResult(w, r, res)
+}
+
+// WebAuthnConfirmChallengeHandler issues a challenge for a WebAuthn assertion which confirms a change to the
+// second factors of the current user
+type WebAuthnConfirmChallengeHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewWebAuthnConfirmChallengeHandler returns a WebAuthnConfirmChallengeHandler
+func NewWebAuthnConfirmChallengeHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *WebAuthnConfirmChallengeHandler {
+	return &WebAuthnConfirmChallengeHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (u *WebAuthnConfirmChallengeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-webauthn-confirm-challenge")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	creds, err := u.Repo().MFA().ListWebAuthnCredentials(user.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing webauthn credentials")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if len(creds) == 0 {
+		err = telemetry.Error(ctx, span, nil, "user has no webauthn credentials")
+		u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	res, err := newWebAuthnChallenge(w, r, u.Config())
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating webauthn challenge")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	for _, cred := range creds {
+		res.AllowCredentials = append(res.AllowCredentials, cred.CredentialID)
+	}
+
+	u.WriteResult(w, r, res)
+}
+
+// RegisterWebAuthnCredentialHandler verifies and stores a new WebAuthn credential for the current user
+type RegisterWebAuthnCredentialHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewRegisterWebAuthnCredentialHandler returns a RegisterWebAuthnCredentialHandler
+func NewRegisterWebAuthnCredentialHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *RegisterWebAuthnCredentialHandler {
+	return &RegisterWebAuthnCredentialHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (u *RegisterWebAuthnCredentialHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-register-webauthn-credential")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	request := &types.RegisterWebAuthnCredentialRequest{}
+	if ok := u.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	challenge, err := authn.PopWebAuthnChallenge(w, r, u.Config())
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "no webauthn challenge in session")
+		u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	rp, err := mfa.RelyingPartyFromServerURL(u.Config().ServerConf.ServerURL)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting webauthn relying party")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	clientDataJSON, err := mfa.DecodeBase64URL(request.ClientDataJSON)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid client data encoding")
+		u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	authData, err := mfa.DecodeBase64URL(request.AuthenticatorData)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid authenticator data encoding")
+		u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	publicKey, err := mfa.DecodeBase64URL(request.PublicKey)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid public key encoding")
+		u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	signCount, err := rp.VerifyRegistration(mfa.WebAuthnRegistration{
+		ClientDataJSON:    clientDataJSON,
+		AuthenticatorData: authData,
+		PublicKey:         publicKey,
+	}, challenge)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error verifying webauthn registration")
+		u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	hadMFA, err := mfa.EnabledMethods(u.Repo().MFA(), user.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error reading mfa methods")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	cred, err := u.Repo().MFA().CreateWebAuthnCredential(&models.WebAuthnCredential{
+		UserID:       user.ID,
+		Name:         request.Name,
+		CredentialID: request.CredentialID,
+		PublicKey:    publicKey,
+		SignCount:    signCount,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error saving webauthn credential")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// the first second factor for a user comes with a set of recovery codes
+	if len(hadMFA) == 0 {
+		codes, err := generateRecoveryCodes(u.Config(), user.ID)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error generating recovery codes")
+			u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		u.WriteResult(w, r, &types.RecoveryCodesResponse{RecoveryCodes: codes})
+		return
+	}
+
+	u.WriteResult(w, r, cred.ToWebAuthnCredentialType())
+}
+
+// DeleteWebAuthnCredentialHandler removes a WebAuthn credential from the current user. A valid TOTP code or
+// WebAuthn assertion is required.
+type DeleteWebAuthnCredentialHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewDeleteWebAuthnCredentialHandler returns a DeleteWebAuthnCredentialHandler
+func NewDeleteWebAuthnCredentialHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *DeleteWebAuthnCredentialHandler {
+	return &DeleteWebAuthnCredentialHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (u *DeleteWebAuthnCredentialHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-delete-webauthn-credential")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	credID, reqErr := requestutils.GetURLParamUint(r, types.URLParamWebAuthnCredentialID)
+	if reqErr != nil {
+		u.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	request := &types.ConfirmSecondFactorRequest{}
+	if ok := u.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	cred, err := u.Repo().MFA().ReadWebAuthnCredential(user.ID, credID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "webauthn credential not found")
+			u.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading webauthn credential")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := confirmSecondFactor(w, r, u.Config(), user.ID, request); err != nil {
+		if errors.Is(err, errInvalidSecondFactor) {
+			err = telemetry.Error(ctx, span, err, "invalid second factor")
+			u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusForbidden))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error verifying second factor")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := u.Repo().MFA().DeleteWebAuthnCredential(cred); err != nil {
+		err = telemetry.Error(ctx, span, err, "error deleting webauthn credential")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := deleteRecoveryCodesIfMFADisabled(u.Config(), user.ID); err != nil {
+		err = telemetry.Error(ctx, span, err, "error deleting recovery codes")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// confirmSecondFactor checks the TOTP code or WebAuthn assertion which confirms a change to the second factors of a
+// user. Recovery codes are not accepted, since a user who still has their other factors does not need one. It
+// returns errInvalidSecondFactor if verification fails.
+func confirmSecondFactor(
+	w http.ResponseWriter,
+	r *http.Request,
+	config *config.Config,
+	userID uint,
+	request *types.ConfirmSecondFactorRequest,
+) error {
+	switch {
+	case request.Code != "":
+		return verifyTOTPCode(config, userID, request.Code)
+	case request.WebAuthn != nil:
+		return verifyWebAuthnAssertion(w, r, config, userID, request.WebAuthn)
+	}
+
+	return errInvalidSecondFactor
+}
+
+// generateRecoveryCodes replaces the recovery codes for a user and returns the plaintext codes
+func generateRecoveryCodes(config *config.Config, userID uint) ([]string, error) {
+	codes, err := mfa.GenerateRecoveryCodes(mfa.RecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, 0, len(codes))
+
+	for _, code := range codes {
+		hashes = append(hashes, mfa.HashRecoveryCode(code))
+	}
+
+	if err := config.Repo.MFA().ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// deleteRecoveryCodesIfMFADisabled removes leftover recovery codes once a user has no second factors
+func deleteRecoveryCodesIfMFADisabled(config *config.Config, userID uint) error {
+	methods, err := mfa.EnabledMethods(config.Repo.MFA(), userID)
+	if err != nil {
+		return err
+	}
+
+	if len(methods) > 0 {
+		return nil
+	}
+
+	return config.Repo.MFA().DeleteRecoveryCodes(userID)
+}
@@ -0,0 +1,199 @@
+package user_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers/user"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/auth/mfa"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestRegenerateRecoveryCodesRequiresSecondFactor(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *types.ConfirmSecondFactorRequest
+	}{
+		{
+			name:    "no second factor",
+			request: &types.ConfirmSecondFactorRequest{},
+		},
+		{
+			name:    "invalid totp code",
+			request: &types.ConfirmSecondFactorRequest{Code: "000000"},
+		},
+		{
+			name: "webauthn assertion without a challenge",
+			request: &types.ConfirmSecondFactorRequest{
+				WebAuthn: &types.WebAuthnAssertionResponse{
+					CredentialID:      "credential",
+					ClientDataJSON:    "e30",
+					AuthenticatorData: "AA",
+					Signature:         "AA",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := apitest.LoadConfig(t)
+			testUser := createTestUserWithTOTP(t, config)
+
+			if err := config.Repo.MFA().ReplaceRecoveryCodes(testUser.ID, []string{mfa.HashRecoveryCode("existing")}); err != nil {
+				t.Fatal(err)
+			}
+
+			req, rr := apitest.GetRequestAndRecorder(
+				t,
+				string(types.HTTPVerbPost),
+				"/api/users/current/mfa/recovery_codes",
+				tt.request,
+			)
+			req = apitest.WithAuthenticatedUser(t, req, testUser)
+
+			handler := user.NewRegenerateRecoveryCodesHandler(
+				config,
+				shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+				shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+			)
+
+			handler.ServeHTTP(rr, req)
+
+			apitest.AssertResponseError(t, rr, http.StatusForbidden, &types.ExternalError{
+				Error: "invalid authentication code",
+			})
+
+			// the existing recovery codes are still valid
+			ok, err := config.Repo.MFA().UseRecoveryCode(testUser.ID, mfa.HashRecoveryCode("existing"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !ok {
+				t.Errorf("existing recovery code was replaced without a second factor")
+			}
+		})
+	}
+}
+
+func TestRegenerateRecoveryCodesWithTOTPCode(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	testUser := createTestUserWithTOTP(t, config)
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/users/current/mfa/recovery_codes",
+		&types.ConfirmSecondFactorRequest{Code: currentTOTPCode(t)},
+	)
+	req = apitest.WithAuthenticatedUser(t, req, testUser)
+
+	handler := user.NewRegenerateRecoveryCodesHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	codes, err := config.Repo.MFA().ListRecoveryCodes(testUser.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(codes) != mfa.RecoveryCodeCount {
+		t.Errorf("expected %d recovery codes, got %d", mfa.RecoveryCodeCount, len(codes))
+	}
+}
+
+func TestDeleteWebAuthnCredentialRequiresSecondFactor(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	testUser := createTestUserWithTOTP(t, config)
+
+	cred, err := config.Repo.MFA().CreateWebAuthnCredential(&models.WebAuthnCredential{
+		UserID:       testUser.ID,
+		Name:         "security key",
+		CredentialID: "credential",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deleteCredential := func(request *types.ConfirmSecondFactorRequest) *http.Response {
+		req, rr := apitest.GetRequestAndRecorder(
+			t,
+			string(types.HTTPVerbDelete),
+			fmt.Sprintf("/api/users/current/mfa/webauthn/%d", cred.ID),
+			request,
+		)
+		req = apitest.WithAuthenticatedUser(t, req, testUser)
+		req = apitest.WithURLParams(t, req, map[string]string{
+			string(types.URLParamWebAuthnCredentialID): fmt.Sprintf("%d", cred.ID),
+		})
+
+		handler := user.NewDeleteWebAuthnCredentialHandler(
+			config,
+			shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+			shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+		)
+
+		handler.ServeHTTP(rr, req)
+
+		return rr.Result()
+	}
+
+	for _, request := range []*types.ConfirmSecondFactorRequest{nil, {}, {Code: "000000"}} {
+		if res := deleteCredential(request); res.StatusCode != http.StatusForbidden {
+			t.Errorf("expected status %d without a valid second factor, got %d", http.StatusForbidden, res.StatusCode)
+		}
+
+		if _, err := config.Repo.MFA().ReadWebAuthnCredential(testUser.ID, cred.ID); err != nil {
+			t.Fatalf("credential was deleted without a second factor: %v", err)
+		}
+	}
+
+	if res := deleteCredential(&types.ConfirmSecondFactorRequest{Code: currentTOTPCode(t)}); res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d with a valid totp code, got %d", http.StatusOK, res.StatusCode)
+	}
+
+	if _, err := config.Repo.MFA().ReadWebAuthnCredential(testUser.ID, cred.ID); err == nil {
+		t.Errorf("credential was not deleted with a valid totp code")
+	}
+}
+
+func createTestUserWithTOTP(t *testing.T, config *config.Config) *models.User {
+	testUser := apitest.CreateTestUser(t, config, true)
+
+	_, err := config.Repo.MFA().CreateUserTOTP(&models.UserTOTP{
+		UserID:  testUser.ID,
+		Secret:  []byte(testTOTPSecret),
+		Enabled: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return testUser
+}
+
+func currentTOTPCode(t *testing.T) string {
+	code, err := mfa.GenerateTOTPCode(testTOTPSecret, mfa.TOTPStep(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return code
+}
@@ -0,0 +1,289 @@
+package webhook
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/api/utils"
+	"github.com/porter-dev/porter/internal/integrations/ci/gitlab"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// GitlabWebhookHandler handles webhooks sent to /api/webhooks/gitlab/{webhook_id}
+type GitlabWebhookHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewGitlabWebhookHandler returns a GitlabWebhookHandler
+func NewGitlabWebhookHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *GitlabWebhookHandler {
+	return &GitlabWebhookHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP handles merge request events of a GitLab preview environment. A preview deployment target is created when a
+// merge request is opened or pushed to, and deleted when it is closed or merged, and a note on the merge request is kept up to date.
+func (c *GitlabWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-gitlab-webhook")
+	defer span.End()
+
+	token := r.Header.Get(gitlab.HeaderToken)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(c.Config().ServerConf.GitlabIncomingWebhookSecret)) != 1 {
+		err := telemetry.Error(ctx, span, nil, "invalid gitlab webhook token")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusUnauthorized))
+		return
+	}
+
+	eventType := r.Header.Get(gitlab.HeaderEvent)
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "event-type", Value: eventType})
+
+	if eventType != gitlab.EventType_MergeRequest {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "event-processed", Value: false})
+		c.WriteResult(w, r, nil)
+		return
+	}
+
+	webhookID, reqErr := requestutils.GetURLParamString(r, types.URLParamWebhookID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, nil, "error parsing webhook id")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "webhook-id", Value: webhookID})
+
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error reading webhook payload")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	event, err := gitlab.ParseMergeRequestEvent(payload)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "could not parse webhook")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	mr := event.ObjectAttributes
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "mr-action-type", Value: mr.Action},
+		telemetry.AttributeKV{Key: "mr-iid", Value: mr.IID},
+		telemetry.AttributeKV{Key: "event-branch", Value: mr.SourceBranch},
+		telemetry.AttributeKV{Key: "gitlab-project", Value: event.Project.PathWithNamespace},
+	)
+
+	owner, name, err := gitlab.SplitProjectPath(event.Project.PathWithNamespace)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "invalid gitlab project path")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	env, err := c.Repo().Environment().ReadEnvironmentByWebhookIDOwnerRepoName(webhookID, owner, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "event-processed", Value: false})
+			c.WriteResult(w, r, nil)
+			return
+		}
+
+		err := telemetry.Error(ctx, span, err, "error reading environment")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if env.GitlabIntegrationID == 0 {
+		err := telemetry.Error(ctx, span, nil, "environment is not a gitlab preview environment")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "environment-id", Value: env.ID},
+		telemetry.AttributeKV{Key: "cluster-id", Value: env.ClusterID},
+		telemetry.AttributeKV{Key: "project-id", Value: env.ProjectID},
+	)
+
+	if (!event.IsOpen() && !event.IsClosed()) || !gitlab.PreviewBranchAllowed(env.ToEnvironmentType(), mr.SourceBranch) {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "event-processed", Value: false})
+		c.WriteResult(w, r, nil)
+		return
+	}
+
+	depl, err := c.Repo().Environment().ReadDeploymentByGitDetails(env.ID, owner, name, uint(mr.IID))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		err := telemetry.Error(ctx, span, err, "error reading deployment")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if err != nil || depl.ID == 0 {
+		if event.IsClosed() {
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "event-processed", Value: false})
+			c.WriteResult(w, r, nil)
+			return
+		}
+
+		depl, err = c.Repo().Environment().CreateDeployment(&models.Deployment{
+			EnvironmentID: env.ID,
+			Namespace:     utils.ValidDNSLabel(mr.SourceBranch),
+			Status:        types.DeploymentStatusCreating,
+			PullRequestID: uint(mr.IID),
+			PRName:        mr.Title,
+			RepoName:      name,
+			RepoOwner:     owner,
+			CommitSHA:     event.ShortSHA(),
+			PRBranchFrom:  mr.SourceBranch,
+			PRBranchInto:  mr.TargetBranch,
+		})
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "error creating deployment")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "deployment-id", Value: depl.ID})
+
+	var dashboardURL string
+
+	if event.IsClosed() {
+		if depl.Status == types.DeploymentStatusInactive {
+			c.WriteResult(w, r, nil)
+			return
+		}
+
+		if err := c.deletePreviewTarget(ctx, env, mr.SourceBranch); err != nil {
+			err := telemetry.Error(ctx, span, err, "error deleting deployment target")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		depl.Status = types.DeploymentStatusInactive
+	} else {
+		createTargetResp, err := c.Config().ClusterControlPlaneClient.CreateDeploymentTarget(ctx, connect.NewRequest(&porterv1.CreateDeploymentTargetRequest{
+			ProjectId: int64(env.ProjectID),
+			ClusterId: int64(env.ClusterID),
+			Name:      mr.SourceBranch,
+			Namespace: mr.SourceBranch,
+			IsPreview: true,
+			Metadata: &porterv1.DeploymentTargetMeta{
+				PullRequest: &porterv1.PullRequest{
+					Number:     int64(mr.IID),
+					Repository: event.Project.PathWithNamespace,
+					HeadRef:    mr.SourceBranch,
+				},
+			},
+		}))
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "error creating deployment target")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+		if createTargetResp == nil || createTargetResp.Msg == nil || createTargetResp.Msg.DeploymentTargetId == "" {
+			err := telemetry.Error(ctx, span, nil, "deployment target id is empty")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "deployment-target-id", Value: createTargetResp.Msg.DeploymentTargetId})
+
+		dashboardURL = fmt.Sprintf("%s/preview-environments/apps?target=%s", c.Config().ServerConf.ServerURL, createTargetResp.Msg.DeploymentTargetId)
+
+		// a reopened merge request is previewed from scratch, since its deployment target was deleted when it was closed
+		if depl.Status == types.DeploymentStatusInactive {
+			depl.Status = types.DeploymentStatusCreating
+		} else if depl.Status != types.DeploymentStatusCreating {
+			depl.Status = types.DeploymentStatusUpdating
+		}
+		depl.PRName = mr.Title
+		depl.CommitSHA = event.ShortSHA()
+		depl.MarkPushed(time.Now().UTC())
+	}
+
+	noteBody := gitlab.MergeRequestNoteBody(gitlab.MergeRequestNoteInput{
+		Status:       depl.Status,
+		CommitSHA:    depl.CommitSHA,
+		CommitURL:    fmt.Sprintf("%s/-/commit/%s", event.Project.WebURL, mr.LastCommit.ID),
+		DashboardURL: dashboardURL,
+		Subdomain:    depl.Subdomain,
+	})
+
+	// a merge request note which cannot be posted does not fail the webhook, so that GitLab does not retry the preview
+	if err := c.updateMergeRequestNote(env, depl, event.Project.PathWithNamespace, noteBody); err != nil {
+		_ = telemetry.Error(ctx, span, err, "error updating merge request note")
+	}
+
+	if _, err := c.Repo().Environment().UpdateDeployment(depl); err != nil {
+		err := telemetry.Error(ctx, span, err, "error updating deployment")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "event-processed", Value: true})
+
+	c.WriteResult(w, r, nil)
+}
+
+// deletePreviewTarget deletes the preview deployment target of a branch, if there is one
+func (c *GitlabWebhookHandler) deletePreviewTarget(ctx context.Context, env *models.Environment, branch string) error {
+	deploymentTarget, err := c.Repo().DeploymentTarget().DeploymentTargetBySelectorAndSelectorType(
+		env.ProjectID,
+		env.ClusterID,
+		utils.ValidDNSLabel(branch),
+		string(models.DeploymentTargetSelectorType_Namespace),
+	)
+	if err != nil {
+		return fmt.Errorf("error getting deployment target: %w", err)
+	}
+
+	if deploymentTarget.ID == uuid.Nil || !deploymentTarget.Preview {
+		return nil
+	}
+
+	_, err = c.Config().ClusterControlPlaneClient.DeleteDeploymentTarget(ctx, connect.NewRequest(&porterv1.DeleteDeploymentTargetRequest{
+		ProjectId:          int64(env.ProjectID),
+		DeploymentTargetId: deploymentTarget.ID.String(),
+	}))
+
+	return err
+}
+
+// updateMergeRequestNote posts or updates the note about the preview of a merge request, following the environment's comment settings
+func (c *GitlabWebhookHandler) updateMergeRequestNote(env *models.Environment, depl *models.Deployment, projectPath, body string) error {
+	gi, err := c.Repo().GitlabIntegration().ReadGitlabIntegration(env.ProjectID, env.GitlabIntegrationID)
+	if err != nil {
+		return fmt.Errorf("error reading gitlab integration: %w", err)
+	}
+
+	client, err := gitlab.NewClient(c.Config(), c.Repo(), gi, env.ProjectID, env.GitlabUserID)
+	if err != nil {
+		return fmt.Errorf("error getting gitlab client: %w", err)
+	}
+
+	noteID, err := gitlab.CreateOrUpdateMergeRequestNote(client, projectPath, int(depl.PullRequestID), depl.GitlabMRNoteID, env.NewCommentsDisabled, body)
+	if err != nil {
+		return err
+	}
+
+	depl.GitlabMRNoteID = noteID
+
+	return nil
+}
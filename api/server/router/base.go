@@ -9,6 +9,7 @@ import (
 	"github.com/porter-dev/porter/api/server/handlers/healthcheck"
 	"github.com/porter-dev/porter/api/server/handlers/metadata"
 	"github.com/porter-dev/porter/api/server/handlers/release"
+	"github.com/porter-dev/porter/api/server/handlers/status_page"
 	"github.com/porter-dev/porter/api/server/handlers/user"
 	"github.com/porter-dev/porter/api/server/handlers/webhook"
 	"github.com/porter-dev/porter/api/server/shared"
@@ -245,6 +246,53 @@ func GetBaseRoutes(
 		Router:   r,
 	})
 
+	// POST /api/login/mfa -> user.NewUserLoginMFAHandler
+	loginMFAEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/login/mfa",
+			},
+		},
+	)
+
+	loginMFAHandler := user.NewUserLoginMFAHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: loginMFAEndpoint,
+		Handler:  loginMFAHandler,
+		Router:   r,
+	})
+
+	// POST /api/login/mfa/webauthn/challenge -> user.NewUserLoginWebAuthnChallengeHandler
+	loginWebAuthnChallengeEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/login/mfa/webauthn/challenge",
+			},
+		},
+	)
+
+	loginWebAuthnChallengeHandler := user.NewUserLoginWebAuthnChallengeHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: loginWebAuthnChallengeEndpoint,
+		Handler:  loginWebAuthnChallengeHandler,
+		Router:   r,
+	})
+
 	// POST /api/cli/login/exchange -> user.NewCLILoginExchangeHandler
 	cliLoginExchangeEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -591,6 +639,33 @@ func GetBaseRoutes(
 		})
 	}
 
+	if config.ServerConf.EnableGitlab && config.ServerConf.GitlabIncomingWebhookSecret != "" {
+		// POST /api/webhooks/gitlab/{webhook_id} -> webhook.NewGitlabWebhookHandler
+		gitlabWebhookEndpoint := factory.NewAPIEndpoint(
+			&types.APIRequestMetadata{
+				Verb:   types.APIVerbCreate,
+				Method: types.HTTPVerbPost,
+				Path: &types.Path{
+					Parent:       basePath,
+					RelativePath: fmt.Sprintf("/webhooks/gitlab/{%s}", types.URLParamWebhookID),
+				},
+				Scopes: []types.PermissionScope{},
+			},
+		)
+
+		gitlabWebhookHandler := webhook.NewGitlabWebhookHandler(
+			config,
+			factory.GetDecoderValidator(),
+			factory.GetResultWriter(),
+		)
+
+		routes = append(routes, &router.Route{
+			Endpoint: gitlabWebhookEndpoint,
+			Handler:  gitlabWebhookHandler,
+			Router:   r,
+		})
+	}
+
 	// POST /api/webhooks/prometheusalerts/{project_id}/{cluster_id} -> webhook.NewPrometheusAlertsHandler
 	prometheusAlertWebhookEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -616,5 +691,79 @@ func GetBaseRoutes(
 		Router:   r,
 	})
 
+	// GET /api/status-pages/{status_page_slug} -> status_page.NewGetPublicStatusPageHandler
+	getPublicStatusPageEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/status-pages/{%s}", types.URLParamStatusPageSlug),
+			},
+			Scopes: []types.PermissionScope{},
+		},
+	)
+
+	getPublicStatusPageHandler := status_page.NewGetPublicStatusPageHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getPublicStatusPageEndpoint,
+		Handler:  getPublicStatusPageHandler,
+		Router:   r,
+	})
+
+	// GET /api/status-pages/{status_page_slug}/feed.rss -> status_page.NewGetPublicStatusPageFeedHandler
+	getPublicStatusPageRSSFeedEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/status-pages/{%s}/feed.rss", types.URLParamStatusPageSlug),
+			},
+			Scopes: []types.PermissionScope{},
+		},
+	)
+
+	getPublicStatusPageRSSFeedHandler := status_page.NewGetPublicStatusPageFeedHandler(
+		config,
+		factory.GetResultWriter(),
+		status_page.FeedFormat_RSS,
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getPublicStatusPageRSSFeedEndpoint,
+		Handler:  getPublicStatusPageRSSFeedHandler,
+		Router:   r,
+	})
+
+	// GET /api/status-pages/{status_page_slug}/feed.json -> status_page.NewGetPublicStatusPageFeedHandler
+	getPublicStatusPageJSONFeedEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/status-pages/{%s}/feed.json", types.URLParamStatusPageSlug),
+			},
+			Scopes: []types.PermissionScope{},
+		},
+	)
+
+	getPublicStatusPageJSONFeedHandler := status_page.NewGetPublicStatusPageFeedHandler(
+		config,
+		factory.GetResultWriter(),
+		status_page.FeedFormat_JSON,
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getPublicStatusPageJSONFeedEndpoint,
+		Handler:  getPublicStatusPageJSONFeedHandler,
+		Router:   r,
+	})
+
 	return routes
 }
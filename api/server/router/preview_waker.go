@@ -0,0 +1,20 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/porter-dev/porter/api/server/handlers/environment"
+	"github.com/porter-dev/porter/api/server/router/middleware"
+	"github.com/porter-dev/porter/api/server/shared/config"
+)
+
+// NewPreviewWakerRouter returns the router of the preview waker, which is the default backend of the ingresses of
+// sleeping preview deployments. Requests keep the host and path of the preview, so every request is handled by the
+// waker rather than being routed by path.
+func NewPreviewWakerRouter(config *config.Config) *chi.Mux {
+	r := chi.NewRouter()
+
+	r.Use(middleware.NewPanicMiddleware(config).Middleware)
+	r.Handle("/*", environment.NewPreviewWakerHandler(config))
+
+	return r
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/porter-dev/porter/api/server/handlers/policy"
 	"github.com/porter-dev/porter/api/server/handlers/project"
 	"github.com/porter-dev/porter/api/server/handlers/registry"
+	"github.com/porter-dev/porter/api/server/handlers/status_page"
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/server/shared/router"
@@ -868,6 +869,116 @@ func getProjectRoutes(
 		Router:   r,
 	})
 
+	// GET /api/projects/{project_id}/datastores/{datastore_name}/snapshots -> datastore.NewListDatastoreSnapshotsHandler
+	listDatastoreSnapshotsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/datastores/{%s}/snapshots", relPath, types.URLParamDatastoreName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	listDatastoreSnapshotsHandler := datastore.NewListDatastoreSnapshotsHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listDatastoreSnapshotsEndpoint,
+		Handler:  listDatastoreSnapshotsHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/datastores/{datastore_name}/snapshots -> datastore.NewCreateDatastoreSnapshotHandler
+	createDatastoreSnapshotEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/datastores/{%s}/snapshots", relPath, types.URLParamDatastoreName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	createDatastoreSnapshotHandler := datastore.NewCreateDatastoreSnapshotHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: createDatastoreSnapshotEndpoint,
+		Handler:  createDatastoreSnapshotHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/datastores/{datastore_name}/restore -> datastore.NewRestoreDatastoreHandler
+	restoreDatastoreEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/datastores/{%s}/restore", relPath, types.URLParamDatastoreName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	restoreDatastoreHandler := datastore.NewRestoreDatastoreHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: restoreDatastoreEndpoint,
+		Handler:  restoreDatastoreHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/datastores/{datastore_name}/clone -> datastore.NewCloneDatastoreHandler
+	cloneDatastoreEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/datastores/{%s}/clone", relPath, types.URLParamDatastoreName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	cloneDatastoreHandler := datastore.NewCloneDatastoreHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: cloneDatastoreEndpoint,
+		Handler:  cloneDatastoreHandler,
+		Router:   r,
+	})
+
 	// POST /api/projects/{project_id}/roles -> project.NewRoleUpdateHandler
 	updateRoleEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -1745,6 +1856,200 @@ func getProjectRoutes(
 		Router:   r,
 	})
 
+	// GET /api/projects/{project_id}/log_searches -> project.NewListLogSearchesHandler
+	listLogSearchesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/log_searches",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	listLogSearchesHandler := project.NewListLogSearchesHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listLogSearchesEndpoint,
+		Handler:  listLogSearchesHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/log_searches -> project.NewCreateLogSearchHandler
+	createLogSearchEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/log_searches",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	createLogSearchHandler := project.NewCreateLogSearchHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: createLogSearchEndpoint,
+		Handler:  createLogSearchHandler,
+		Router:   r,
+	})
+
+	// DELETE /api/projects/{project_id}/log_searches/{log_search_id} -> project.NewDeleteLogSearchHandler
+	deleteLogSearchEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/log_searches/{%s}", relPath, types.URLParamLogSearchID),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	deleteLogSearchHandler := project.NewDeleteLogSearchHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: deleteLogSearchEndpoint,
+		Handler:  deleteLogSearchHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/status-page -> status_page.NewGetStatusPageHandler
+	getStatusPageEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/status-page",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	getStatusPageHandler := status_page.NewGetStatusPageHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getStatusPageEndpoint,
+		Handler:  getStatusPageHandler,
+		Router:   r,
+	})
+
+	// PUT /api/projects/{project_id}/status-page -> status_page.NewUpdateStatusPageHandler
+	updateStatusPageEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPut,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/status-page",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	updateStatusPageHandler := status_page.NewUpdateStatusPageHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updateStatusPageEndpoint,
+		Handler:  updateStatusPageHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/status-page/incidents -> status_page.NewCreateIncidentHandler
+	createStatusPageIncidentEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/status-page/incidents",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	createStatusPageIncidentHandler := status_page.NewCreateIncidentHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: createStatusPageIncidentEndpoint,
+		Handler:  createStatusPageIncidentHandler,
+		Router:   r,
+	})
+
+	// PATCH /api/projects/{project_id}/status-page/incidents/{status_page_incident_id} -> status_page.NewUpdateIncidentHandler
+	updateStatusPageIncidentEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPatch,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/status-page/incidents/{%s}", relPath, types.URLParamStatusPageIncidentID),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	updateStatusPageIncidentHandler := status_page.NewUpdateIncidentHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updateStatusPageIncidentEndpoint,
+		Handler:  updateStatusPageIncidentHandler,
+		Router:   r,
+	})
+
 	// POST /api/projects/{project_id}/contract -> apiContract.NewAPIContractUpdateHandler
 	updateAPIContractEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -1912,6 +2217,34 @@ func getProjectRoutes(
 		Router:   r,
 	})
 
+	// POST /api/projects/{project_id}/mfa -> project.NewUpdateProjectMFAHandler
+	updateProjectMFAEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/mfa",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	updateProjectMFAHandler := project.NewUpdateProjectMFAHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updateProjectMFAEndpoint,
+		Handler:  updateProjectMFAHandler,
+		Router:   r,
+	})
+
 	// GET /api/projects/{project_id}/images -> project.ImagesHandler
 	imagesEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -381,9 +381,7 @@ func getUserRoutes(
 				Parent:       basePath,
 				RelativePath: "/integrations/github-app/oauth",
 			},
-			Scopes: []types.PermissionScope{
-				types.UserScope,
-			},
+			Scopes: []types.PermissionScope{types.UserScope},
 		},
 	)
 
@@ -497,5 +495,226 @@ func getUserRoutes(
 		Router:   r,
 	})
 
+	// GET /api/users/current/mfa -> user.NewGetMFAStatusHandler
+	getMFAStatusEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/users/current/mfa",
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+		},
+	)
+
+	getMFAStatusHandler := user.NewGetMFAStatusHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getMFAStatusEndpoint,
+		Handler:  getMFAStatusHandler,
+		Router:   r,
+	})
+
+	// POST /api/users/current/mfa/totp -> user.NewEnrollTOTPHandler
+	enrollTOTPEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/users/current/mfa/totp",
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+		},
+	)
+
+	enrollTOTPHandler := user.NewEnrollTOTPHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: enrollTOTPEndpoint,
+		Handler:  enrollTOTPHandler,
+		Router:   r,
+	})
+
+	// POST /api/users/current/mfa/totp/verify -> user.NewVerifyTOTPHandler
+	verifyTOTPEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/users/current/mfa/totp/verify",
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+		},
+	)
+
+	verifyTOTPHandler := user.NewVerifyTOTPHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: verifyTOTPEndpoint,
+		Handler:  verifyTOTPHandler,
+		Router:   r,
+	})
+
+	// POST /api/users/current/mfa/totp/disable -> user.NewDisableTOTPHandler
+	disableTOTPEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/users/current/mfa/totp/disable",
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+		},
+	)
+
+	disableTOTPHandler := user.NewDisableTOTPHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: disableTOTPEndpoint,
+		Handler:  disableTOTPHandler,
+		Router:   r,
+	})
+
+	// POST /api/users/current/mfa/recovery_codes -> user.NewRegenerateRecoveryCodesHandler
+	regenerateRecoveryCodesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/users/current/mfa/recovery_codes",
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+		},
+	)
+
+	regenerateRecoveryCodesHandler := user.NewRegenerateRecoveryCodesHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: regenerateRecoveryCodesEndpoint,
+		Handler:  regenerateRecoveryCodesHandler,
+		Router:   r,
+	})
+
+	// POST /api/users/current/mfa/webauthn/challenge -> user.NewWebAuthnRegisterChallengeHandler
+	webAuthnRegisterChallengeEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/users/current/mfa/webauthn/challenge",
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+		},
+	)
+
+	webAuthnRegisterChallengeHandler := user.NewWebAuthnRegisterChallengeHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: webAuthnRegisterChallengeEndpoint,
+		Handler:  webAuthnRegisterChallengeHandler,
+		Router:   r,
+	})
+
+	// POST /api/users/current/mfa/webauthn/confirm_challenge -> user.NewWebAuthnConfirmChallengeHandler
+	webAuthnConfirmChallengeEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/users/current/mfa/webauthn/confirm_challenge",
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+		},
+	)
+
+	webAuthnConfirmChallengeHandler := user.NewWebAuthnConfirmChallengeHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: webAuthnConfirmChallengeEndpoint,
+		Handler:  webAuthnConfirmChallengeHandler,
+		Router:   r,
+	})
+
+	// POST /api/users/current/mfa/webauthn -> user.NewRegisterWebAuthnCredentialHandler
+	registerWebAuthnCredentialEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/users/current/mfa/webauthn",
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+		},
+	)
+
+	registerWebAuthnCredentialHandler := user.NewRegisterWebAuthnCredentialHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: registerWebAuthnCredentialEndpoint,
+		Handler:  registerWebAuthnCredentialHandler,
+		Router:   r,
+	})
+
+	// DELETE /api/users/current/mfa/webauthn/{webauthn_credential_id} -> user.NewDeleteWebAuthnCredentialHandler
+	deleteWebAuthnCredentialEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/users/current/mfa/webauthn/{%s}", types.URLParamWebAuthnCredentialID),
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+		},
+	)
+
+	deleteWebAuthnCredentialHandler := user.NewDeleteWebAuthnCredentialHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: deleteWebAuthnCredentialEndpoint,
+		Handler:  deleteWebAuthnCredentialHandler,
+		Router:   r,
+	})
+
 	return routes
 }
@@ -40,6 +40,9 @@ type ServerConf struct {
 
 	GithubIncomingWebhookSecret string `env:"GITHUB_INCOMING_WEBHOOK_SECRET"`
 
+	// GitlabIncomingWebhookSecret is the token GitLab sends with merge request webhooks for preview environments
+	GitlabIncomingWebhookSecret string `env:"GITLAB_INCOMING_WEBHOOK_SECRET"`
+
 	GithubAppClientID      string `env:"GITHUB_APP_CLIENT_ID"`
 	GithubAppClientSecret  string `env:"GITHUB_APP_CLIENT_SECRET"`
 	GithubAppName          string `env:"GITHUB_APP_NAME"`
@@ -108,6 +111,10 @@ type ServerConf struct {
 	// ClusterControlPlane settings
 	ClusterControlPlaneAddress string `env:"CLUSTER_CONTROL_PLANE_ADDRESS"`
 
+	// DatastoreBackupProvider selects the provider used for datastore snapshots, restores and clones.
+	// Setting this to empty string will disable datastore backups
+	DatastoreBackupProvider string `env:"DATASTORE_BACKUP_PROVIDER"`
+
 	SegmentClientKey string `env:"SEGMENT_CLIENT_KEY"`
 
 	// DnsProvider controls which provider to use for dns (powerdns or cloudflare)
@@ -151,6 +158,10 @@ type ServerConf struct {
 	// imagePullSecrets into a kubernetes deployment (Porter application)
 	DisablePullSecretsInjection bool `env:"DISABLE_PULL_SECRETS_INJECTION,default=false"`
 
+	// PreviewWakerPort is the port of the preview waker, which wakes up sleeping preview deployments when their
+	// ingress receives a request. The waker is not started if it is not set.
+	PreviewWakerPort int `env:"PREVIEW_WAKER_PORT"`
+
 	// EnableAutoPreviewBranchDeploy is used to enable preview branch deployments automatically
 	// The default behaviour is to automatically create preview deployment against a deploy branch
 	EnableAutoPreviewBranchDeploy bool `env:"ENABLE_AUTO_PREVIEW_BRANCH_DEPLOY,default=true"`
@@ -179,6 +190,21 @@ type ServerConf struct {
 	OryApiKey  string `env:"ORY_API_KEY"`
 	// OryActionKey is the key used to authenticate api requests from Ory Actions to the Porter API
 	OryActionKey string `env:"ORY_ACTION_KEY"`
+
+	SecretReferenceConf SecretReferenceConf
+}
+
+// SecretReferenceConf configures how references to external secret managers in environment groups are resolved
+type SecretReferenceConf struct {
+	// SecretReferenceVaultServerURL is the Vault server which ref+vault:// references are read from. Vault references are disabled if unset.
+	SecretReferenceVaultServerURL string `env:"SECRET_REFERENCE_VAULT_SERVER_URL"`
+	// SecretReferenceVaultToken is a token with read access to the secrets under SecretReferenceVaultProjectsPath
+	SecretReferenceVaultToken string `env:"SECRET_REFERENCE_VAULT_TOKEN"`
+	// SecretReferenceVaultMount is the mount of the Vault KV v2 engine which holds each project's secrets
+	SecretReferenceVaultMount string `env:"SECRET_REFERENCE_VAULT_MOUNT,default=kv"`
+	// SecretReferenceVaultProjectsPath is the path within the mount under which each project's secrets are stored, as
+	// <mount>/data/<projects path>/<project id>/...
+	SecretReferenceVaultProjectsPath string `env:"SECRET_REFERENCE_VAULT_PROJECTS_PATH,default=projects"`
 }
 
 // DBConf is the database configuration: if generated from environment variables,
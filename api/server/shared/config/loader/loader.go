@@ -25,6 +25,7 @@ import (
 	"github.com/porter-dev/porter/internal/auth/sessionstore"
 	"github.com/porter-dev/porter/internal/auth/token"
 	"github.com/porter-dev/porter/internal/billing"
+	"github.com/porter-dev/porter/internal/datastore"
 	"github.com/porter-dev/porter/internal/features"
 	"github.com/porter-dev/porter/internal/helm/urlcache"
 	"github.com/porter-dev/porter/internal/integrations/cloudflare"
@@ -352,6 +353,15 @@ func (e *EnvConfigLoader) LoadConfig() (res *config.Config, err error) {
 		res.Logger.Info().Msg("Created CCP client")
 	}
 
+	switch sc.DatastoreBackupProvider {
+	case "":
+	case "fake":
+		// the fake provider keeps all snapshots in memory, so it should only be used for local development
+		res.DatastoreBackupProvider = datastore.NewFakeBackupProvider()
+	default:
+		return res, fmt.Errorf("unsupported datastore backup provider: %s", sc.DatastoreBackupProvider)
+	}
+
 	res.TelemetryConfig = telemetry.TracerConfig{
 		ServiceName:  sc.TelemetryName,
 		CollectorURL: sc.TelemetryCollectorURL,
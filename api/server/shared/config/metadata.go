@@ -16,6 +16,9 @@ type Metadata struct {
 	Version            string `json:"version"`
 	Gitlab             bool   `json:"gitlab"`
 
+	// DatastoreBackups is true if datastores can be snapshotted, restored and cloned
+	DatastoreBackups bool `json:"datastore_backups"`
+
 	DefaultAppHelmRepoURL   string `json:"default_app_helm_repo_url"`
 	DefaultAddonHelmRepoURL string `json:"default_addon_helm_repo_url"`
 }
@@ -32,6 +35,7 @@ func MetadataFromConf(sc *env.ServerConf, version string) *Metadata {
 		Analytics:               sc.SegmentClientKey != "",
 		Version:                 version,
 		Gitlab:                  sc.EnableGitlab,
+		DatastoreBackups:        sc.DatastoreBackupProvider != "",
 		DefaultAppHelmRepoURL:   sc.DefaultApplicationHelmRepoURL,
 		DefaultAddonHelmRepoURL: sc.DefaultAddonHelmRepoURL,
 	}
@@ -48,6 +48,23 @@ func (w *WebsocketSafeReadWriter) Write(data []byte) (int, error) {
 	return len(data), nil
 }
 
+// WriteBinary writes data to the websocket connection as a single binary message
+func (w *WebsocketSafeReadWriter) WriteBinary(data []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	err := w.conn.WriteMessage(websocket.BinaryMessage, data)
+	if err != nil {
+		if errOr(err, websocket.ErrCloseSent, syscall.EPIPE, syscall.ECONNRESET) {
+			// see WriteJSON; the reader will observe the closed connection and shut down
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
 func (w *WebsocketSafeReadWriter) ReadMessage() (messageType int, p []byte, err error) {
 	return w.conn.ReadMessage()
 }
@@ -0,0 +1,108 @@
+package types
+
+import "time"
+
+// AppCanaryResult is the outcome of a canary analysis
+type AppCanaryResult string
+
+const (
+	// AppCanaryResult_Progressing means that traffic is still being shifted to the canary
+	AppCanaryResult_Progressing AppCanaryResult = "progressing"
+	// AppCanaryResult_Promoted means that the canary passed every analysis step, so the new image can be rolled out
+	AppCanaryResult_Promoted AppCanaryResult = "promoted"
+	// AppCanaryResult_RolledBack means that all traffic was returned to the stable image, either because an analysis
+	// step failed or because the canary could not be run
+	AppCanaryResult_RolledBack AppCanaryResult = "rolled_back"
+)
+
+// AppCanaryStep is a percentage of traffic which is sent to the canary for a duration, after which the canary is
+// compared against the stable image
+type AppCanaryStep struct {
+	// Weight is the percentage of requests which are sent to the canary
+	Weight int `json:"weight" form:"required,min=1,max=100"`
+	// DurationSeconds is how long traffic is split before the canary is analyzed
+	DurationSeconds int `json:"duration_seconds" form:"required,min=30,max=3600"`
+}
+
+// AppCanaryAnalysis are the thresholds that the canary is compared against the stable image with. Zero values are
+// replaced by defaults.
+type AppCanaryAnalysis struct {
+	// MaxErrorRateIncrease is the number of percentage points that the 5xx rate of the canary may exceed the stable
+	// rate by. Defaults to 1
+	MaxErrorRateIncrease float64 `json:"max_error_rate_increase,omitempty" form:"omitempty,gte=0,lte=100"`
+	// MaxLatencyIncreasePercent is the percentage that the p95 latency of the canary may exceed the stable latency by.
+	// Defaults to 20
+	MaxLatencyIncreasePercent float64 `json:"max_latency_increase_percent,omitempty" form:"omitempty,gte=0"`
+	// MinRequests is the number of requests the canary must receive during a step for the step to be conclusive.
+	// Defaults to 20
+	MinRequests uint `json:"min_requests,omitempty"`
+}
+
+// AppCanarySpec declares how a new image of a web service is rolled out to a fraction of its traffic
+type AppCanarySpec struct {
+	ServiceName string            `json:"service_name" form:"required,max=255"`
+	Steps       []AppCanaryStep   `json:"steps" form:"required,min=1,max=10,dive"`
+	Analysis    AppCanaryAnalysis `json:"analysis"`
+}
+
+// AppCanaryMetrics are the ingress metrics of either the canary or the stable image over an analysis step
+type AppCanaryMetrics struct {
+	// Requests is the number of requests received during the step
+	Requests float64 `json:"requests"`
+	// ErrorRate is the fraction of requests which returned a 5xx status
+	ErrorRate float64 `json:"error_rate"`
+	// LatencyP95Ms is the 95th percentile request latency, or zero if there were no requests
+	LatencyP95Ms float64 `json:"latency_p95_ms"`
+}
+
+// AppCanaryStepResult is the result of the analysis at the end of a canary step
+type AppCanaryStepResult struct {
+	Weight      int              `json:"weight"`
+	StartedAt   time.Time        `json:"started_at"`
+	CompletedAt time.Time        `json:"completed_at"`
+	Canary      AppCanaryMetrics `json:"canary"`
+	Stable      AppCanaryMetrics `json:"stable"`
+	// Passed is false if the canary was worse than the stable image by more than the analysis thresholds
+	Passed bool `json:"passed"`
+	// Inconclusive is true if the canary did not receive enough requests to be compared. Inconclusive steps pass.
+	Inconclusive bool   `json:"inconclusive,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+// AppCanary is the progress of a canary of a web service, which is recorded as a CANARY porter app event
+type AppCanary struct {
+	// EventID is the ID of the porter app event that the canary is recorded in
+	EventID string `json:"event_id"`
+	// AppRevisionID is the revision which is rolled out if the canary is promoted
+	AppRevisionID string          `json:"app_revision_id,omitempty"`
+	Spec          AppCanarySpec   `json:"spec"`
+	Image         string          `json:"image"`
+	Result        AppCanaryResult `json:"result"`
+	// Reason explains why the canary was rolled back
+	Reason    string                `json:"reason,omitempty"`
+	Steps     []AppCanaryStepResult `json:"steps"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+// CreateAppCanariesRequest is the request object for the POST /apps/{porter_app_name}/canaries endpoint
+type CreateAppCanariesRequest struct {
+	DeploymentTargetID   string `json:"deployment_target_id"`
+	DeploymentTargetName string `json:"deployment_target_name"`
+	// AppRevisionID is the revision which is rolled out if every canary is promoted
+	AppRevisionID string `json:"app_revision_id"`
+	// ImageRepository and ImageTag identify the new image, which replaces the image of the stable deployment in the canary
+	ImageRepository string          `json:"image_repository" form:"required"`
+	ImageTag        string          `json:"image_tag" form:"required"`
+	Canaries        []AppCanarySpec `json:"canaries" form:"required,min=1,max=10,dive"`
+}
+
+// CreateAppCanariesResponse is the response object for the POST /apps/{porter_app_name}/canaries endpoint
+type CreateAppCanariesResponse struct {
+	Canaries []AppCanary `json:"canaries"`
+}
+
+// GetAppCanaryResponse is the response object for the GET /apps/{porter_app_name}/canaries/{porter_app_event_id} endpoint
+type GetAppCanaryResponse struct {
+	Canary AppCanary `json:"canary"`
+}
@@ -0,0 +1,49 @@
+package types
+
+import "time"
+
+// AppDashboardPanel is a single chart on an app dashboard
+type AppDashboardPanel struct {
+	// Title is the title of the panel
+	Title string `json:"title" form:"required,max=255"`
+	// Query is the PromQL expression charted by the panel. Every selector in the query is restricted to the app's namespace
+	Query string `json:"query" form:"required"`
+	// Unit is the unit of the values returned by the query, used to format them
+	Unit string `json:"unit,omitempty" form:"omitempty,oneof=none bytes seconds milliseconds percent cores requests_per_second"`
+}
+
+// AppDashboard is a saved set of metric panels for an app
+type AppDashboard struct {
+	ID        uint                `json:"id"`
+	Name      string              `json:"name"`
+	Panels    []AppDashboardPanel `json:"panels"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// CreateAppDashboardRequest is the request object for the POST /apps/{porter_app_name}/dashboards endpoint
+type CreateAppDashboardRequest struct {
+	Name   string              `json:"name" form:"required,max=255"`
+	Panels []AppDashboardPanel `json:"panels" form:"required,min=1,max=50,dive"`
+}
+
+// CreateAppDashboardResponse is the response object for the POST /apps/{porter_app_name}/dashboards endpoint
+type CreateAppDashboardResponse struct {
+	Dashboard AppDashboard `json:"dashboard"`
+}
+
+// ListAppDashboardsResponse is the response object for the GET /apps/{porter_app_name}/dashboards endpoint
+type ListAppDashboardsResponse struct {
+	Dashboards []AppDashboard `json:"dashboards"`
+}
+
+// UpdateAppDashboardRequest is the request object for the PUT /apps/{porter_app_name}/dashboards/{app_dashboard_id} endpoint
+type UpdateAppDashboardRequest struct {
+	Name   string              `json:"name" form:"required,max=255"`
+	Panels []AppDashboardPanel `json:"panels" form:"required,min=1,max=50,dive"`
+}
+
+// UpdateAppDashboardResponse is the response object for the PUT /apps/{porter_app_name}/dashboards/{app_dashboard_id} endpoint
+type UpdateAppDashboardResponse struct {
+	Dashboard AppDashboard `json:"dashboard"`
+}
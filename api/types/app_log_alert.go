@@ -0,0 +1,90 @@
+package types
+
+import "time"
+
+// AppLogAlertState is the state of a log alert rule as of its most recent evaluation
+type AppLogAlertState string
+
+const (
+	// AppLogAlertState_OK means that no more lines than the threshold matched the rule's query within its window
+	AppLogAlertState_OK AppLogAlertState = "ok"
+	// AppLogAlertState_Firing means that more lines than the threshold matched the rule's query within its window
+	AppLogAlertState_Firing AppLogAlertState = "firing"
+)
+
+// AppLogAlertRuleSpec declares an alert on the logs of an app, such as "more than 50 lines matching panic: in 5 minutes"
+type AppLogAlertRuleSpec struct {
+	// Name is the name of the rule, which is unique for the app
+	Name string `json:"name" form:"required,max=255"`
+	// ServiceName restricts the rule to the logs of a single service. Defaults to all services of the app
+	ServiceName string `json:"service_name,omitempty" form:"max=255"`
+	// Query is the log query which lines must match, such as `"panic:"` or `level:error -healthcheck`
+	Query string `json:"query" form:"required,max=2048"`
+	// Threshold is the number of matching lines within the window above which the rule fires
+	Threshold uint `json:"threshold" form:"max=10000"`
+	// WindowMinutes is the period over which matching lines are counted. Defaults to 5 minutes
+	WindowMinutes uint `json:"window_minutes,omitempty" form:"omitempty,min=1,max=60"`
+	// Channels are the names of the Slack channels to notify. Defaults to every Slack channel connected to the project
+	Channels []string `json:"channels,omitempty" form:"max=20,dive,max=255"`
+}
+
+// AppLogAlertRule is a log alert rule of an app, along with the result of its most recent evaluation
+type AppLogAlertRule struct {
+	AppLogAlertRuleSpec
+
+	ID                 uint             `json:"id"`
+	DeploymentTargetID string           `json:"deployment_target_id"`
+	LastState          AppLogAlertState `json:"last_state,omitempty"`
+	// LastMatchCount is the number of matching lines counted by the most recent evaluation, which stops counting once
+	// the threshold is exceeded
+	LastMatchCount uint       `json:"last_match_count"`
+	LastCheckedAt  *time.Time `json:"last_checked_at,omitempty"`
+	LastFiredAt    *time.Time `json:"last_fired_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// AppLogAlertNotification is the content of a notification sent when a log alert rule fires
+type AppLogAlertNotification struct {
+	AppName string          `json:"app_name"`
+	Rule    AppLogAlertRule `json:"rule"`
+	// MatchCount is the number of matching lines which were counted, which is at least the threshold plus one
+	MatchCount uint `json:"match_count"`
+	// SampleLines are the most recent matching lines
+	SampleLines []string  `json:"sample_lines"`
+	FiredAt     time.Time `json:"fired_at"`
+}
+
+// ListAppLogAlertRulesRequest is the request object for the GET /apps/{porter_app_name}/log-alerts endpoint
+type ListAppLogAlertRulesRequest struct {
+	DeploymentTargetID   string `schema:"deployment_target_id"`
+	DeploymentTargetName string `schema:"deployment_target_name"`
+}
+
+// ListAppLogAlertRulesResponse is the response object for the GET /apps/{porter_app_name}/log-alerts endpoint
+type ListAppLogAlertRulesResponse struct {
+	Rules []AppLogAlertRule `json:"rules"`
+}
+
+// CreateAppLogAlertRuleRequest is the request object for the POST /apps/{porter_app_name}/log-alerts endpoint
+type CreateAppLogAlertRuleRequest struct {
+	AppLogAlertRuleSpec
+
+	DeploymentTargetID   string `json:"deployment_target_id"`
+	DeploymentTargetName string `json:"deployment_target_name"`
+}
+
+// CreateAppLogAlertRuleResponse is the response object for the POST /apps/{porter_app_name}/log-alerts endpoint
+type CreateAppLogAlertRuleResponse struct {
+	Rule AppLogAlertRule `json:"rule"`
+}
+
+// UpdateAppLogAlertRuleRequest is the request object for the PUT /apps/{porter_app_name}/log-alerts/{app_log_alert_rule_id} endpoint
+type UpdateAppLogAlertRuleRequest struct {
+	AppLogAlertRuleSpec
+}
+
+// UpdateAppLogAlertRuleResponse is the response object for the PUT /apps/{porter_app_name}/log-alerts/{app_log_alert_rule_id} endpoint
+type UpdateAppLogAlertRuleResponse struct {
+	Rule AppLogAlertRule `json:"rule"`
+}
@@ -0,0 +1,114 @@
+package types
+
+import "time"
+
+const (
+	// AppSLOType_Availability is an objective on the percentage of requests which do not return a 5xx status
+	AppSLOType_Availability = "availability"
+	// AppSLOType_Latency is an objective on the percentage of requests which complete within a latency threshold
+	AppSLOType_Latency = "latency"
+)
+
+const (
+	// AppSLOSource_API is the source of SLOs declared through the API
+	AppSLOSource_API = "api"
+	// AppSLOSource_PorterYAML is the source of SLOs declared in porter.yaml, which are replaced on every apply
+	AppSLOSource_PorterYAML = "porter_yaml"
+)
+
+// AppSLOStatus is the state of an SLO's error budget at the time it was last evaluated
+type AppSLOStatus string
+
+const (
+	// AppSLOStatus_NoData means that the service has not received any requests during the SLO window
+	AppSLOStatus_NoData AppSLOStatus = "no_data"
+	// AppSLOStatus_OK means that the error budget is not burning faster than it is replenished
+	AppSLOStatus_OK AppSLOStatus = "ok"
+	// AppSLOStatus_SlowBurn means that the error budget will run out before the end of the window at the current rate
+	AppSLOStatus_SlowBurn AppSLOStatus = "slow_burn"
+	// AppSLOStatus_FastBurn means that a large fraction of the error budget is being consumed within hours
+	AppSLOStatus_FastBurn AppSLOStatus = "fast_burn"
+	// AppSLOStatus_Exhausted means that the error budget for the window has been used up
+	AppSLOStatus_Exhausted AppSLOStatus = "exhausted"
+)
+
+// AppSLOSpec declares a service level objective for a web service of an app
+type AppSLOSpec struct {
+	// Name is the name of the SLO, which is unique for the app
+	Name string `json:"name" form:"required,max=255"`
+	// ServiceName is the name of the web service that the SLO applies to
+	ServiceName string `json:"service_name" form:"required,max=255"`
+	// Type is the kind of objective, either availability or latency
+	Type string `json:"type" form:"required,oneof=availability latency"`
+	// Target is the percentage of requests which must meet the objective over the window, such as 99.9
+	Target float64 `json:"target" form:"required,gt=0,lt=100"`
+	// LatencyThresholdMs is the latency that requests must complete within. Required for latency objectives
+	LatencyThresholdMs uint `json:"latency_threshold_ms,omitempty"`
+	// WindowDays is the rolling window over which the objective is measured. Defaults to 30 days
+	WindowDays uint `json:"window_days,omitempty" form:"omitempty,min=1,max=90"`
+}
+
+// AppSLOBurnRate is the rate at which the error budget of an SLO was consumed over a pair of windows. A burn rate
+// of 1 consumes exactly the whole budget over the SLO window.
+type AppSLOBurnRate struct {
+	LongWindow  string  `json:"long_window"`
+	ShortWindow string  `json:"short_window"`
+	Threshold   float64 `json:"threshold"`
+	LongRate    float64 `json:"long_rate"`
+	ShortRate   float64 `json:"short_rate"`
+	// Firing is true when both the long and short window burn rates exceed the threshold
+	Firing bool `json:"firing"`
+}
+
+// AppSLOStatusEntry is the result of a single evaluation of an SLO
+type AppSLOStatusEntry struct {
+	Status AppSLOStatus `json:"status"`
+	// ErrorRatio is the fraction of requests which did not meet the objective over the SLO window
+	ErrorRatio float64 `json:"error_ratio"`
+	// BudgetRemaining is the fraction of the error budget which has not been consumed over the SLO window
+	BudgetRemaining float64          `json:"budget_remaining"`
+	BurnRates       []AppSLOBurnRate `json:"burn_rates"`
+	CheckedAt       time.Time        `json:"checked_at"`
+}
+
+// AppSLO is a service level objective of an app, along with its most recent status
+type AppSLO struct {
+	AppSLOSpec
+
+	ID                 uint               `json:"id"`
+	DeploymentTargetID string             `json:"deployment_target_id"`
+	Source             string             `json:"source"`
+	LastStatus         *AppSLOStatusEntry `json:"last_status,omitempty"`
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+}
+
+// ListAppSLOsResponse is the response object for the GET /apps/{porter_app_name}/slos endpoint
+type ListAppSLOsResponse struct {
+	SLOs []AppSLO `json:"slos"`
+}
+
+// UpdateAppSLOsRequest is the request object for the PUT /apps/{porter_app_name}/slos endpoint
+type UpdateAppSLOsRequest struct {
+	DeploymentTargetID   string `json:"deployment_target_id"`
+	DeploymentTargetName string `json:"deployment_target_name"`
+	// Source is the source of the SLOs. Only existing SLOs from the same source are replaced. Defaults to api
+	Source string       `json:"source" form:"omitempty,oneof=api porter_yaml"`
+	SLOs   []AppSLOSpec `json:"slos" form:"max=50,dive"`
+}
+
+// UpdateAppSLOsResponse is the response object for the PUT /apps/{porter_app_name}/slos endpoint
+type UpdateAppSLOsResponse struct {
+	SLOs []AppSLO `json:"slos"`
+}
+
+// ListAppSLOStatusesRequest is the request object for the GET /apps/{porter_app_name}/slos/{app_slo_id}/statuses endpoint
+type ListAppSLOStatusesRequest struct {
+	// Limit is the maximum number of statuses to return, most recent first. Defaults to 100
+	Limit int `schema:"limit" form:"omitempty,min=1,max=1000"`
+}
+
+// ListAppSLOStatusesResponse is the response object for the GET /apps/{porter_app_name}/slos/{app_slo_id}/statuses endpoint
+type ListAppSLOStatusesResponse struct {
+	Statuses []AppSLOStatusEntry `json:"statuses"`
+}
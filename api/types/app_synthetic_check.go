@@ -0,0 +1,94 @@
+package types
+
+import "time"
+
+// AppSyntheticCheckStatus is the outcome of the most recent run of a synthetic check
+type AppSyntheticCheckStatus string
+
+const (
+	// AppSyntheticCheckStatus_Pending means that the check has not run yet
+	AppSyntheticCheckStatus_Pending AppSyntheticCheckStatus = "pending"
+	// AppSyntheticCheckStatus_Passing means that the response matched every expectation of the check
+	AppSyntheticCheckStatus_Passing AppSyntheticCheckStatus = "passing"
+	// AppSyntheticCheckStatus_Warning means that the response matched, but the TLS certificate of the domain expires soon
+	AppSyntheticCheckStatus_Warning AppSyntheticCheckStatus = "warning"
+	// AppSyntheticCheckStatus_Failing means that the request failed or the response did not match the check
+	AppSyntheticCheckStatus_Failing AppSyntheticCheckStatus = "failing"
+)
+
+// AppSyntheticCheckSpec declares an HTTP check which is sent to a domain of a web service from outside the cluster
+type AppSyntheticCheckSpec struct {
+	// Name is the name of the check, which is unique for the app
+	Name string `json:"name" form:"required,max=255"`
+	// ServiceName is the name of the web service that the check applies to
+	ServiceName string `json:"service_name" form:"required,max=255"`
+	// Domain is the domain of the web service that the check is sent to. It must be a domain of the service in the current
+	// revision of the app
+	Domain string `json:"domain" form:"required,hostname"`
+	// Path is the path that is requested. Defaults to /
+	Path string `json:"path,omitempty" form:"omitempty,startswith=/,max=2048"`
+	// ExpectedStatus is the status code that the response must have. Defaults to 200
+	ExpectedStatus int `json:"expected_status,omitempty" form:"omitempty,min=100,max=599"`
+	// BodyRegex is a regular expression that the response body must match, if set
+	BodyRegex string `json:"body_regex,omitempty" form:"max=1024"`
+	// TLSExpiryWarningDays warns when the TLS certificate of the domain expires within this many days. Zero disables the warning
+	TLSExpiryWarningDays uint `json:"tls_expiry_warning_days,omitempty" form:"max=90"`
+	// IntervalSeconds is how often the check runs. Defaults to 60 seconds
+	IntervalSeconds uint `json:"interval_seconds,omitempty" form:"omitempty,min=30,max=3600"`
+}
+
+// AppSyntheticCheckResult is the result of a single run of a synthetic check
+type AppSyntheticCheckResult struct {
+	Status     AppSyntheticCheckStatus `json:"status"`
+	StatusCode int                     `json:"status_code,omitempty"`
+	LatencyMs  uint                    `json:"latency_ms"`
+	// Error is the reason that the check failed or warned, if it did
+	Error string `json:"error,omitempty"`
+	// TLSExpiresAt is when the TLS certificate of the domain expires, for https checks
+	TLSExpiresAt *time.Time `json:"tls_expires_at,omitempty"`
+	CheckedAt    time.Time  `json:"checked_at"`
+}
+
+// AppSyntheticCheck is a synthetic check of an app, along with its most recent result
+type AppSyntheticCheck struct {
+	AppSyntheticCheckSpec
+
+	ID                 uint                    `json:"id"`
+	DeploymentTargetID string                  `json:"deployment_target_id"`
+	Status             AppSyntheticCheckStatus `json:"status"`
+	// ConsecutiveFailures is the number of runs that have failed in a row
+	ConsecutiveFailures uint                     `json:"consecutive_failures"`
+	LastResult          *AppSyntheticCheckResult `json:"last_result,omitempty"`
+	// IncidentOpenedAt is when the current incident of the check was opened, if it has one
+	IncidentOpenedAt *time.Time `json:"incident_opened_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// ListAppSyntheticChecksResponse is the response object for the GET /apps/{porter_app_name}/synthetic-checks endpoint
+type ListAppSyntheticChecksResponse struct {
+	Checks []AppSyntheticCheck `json:"checks"`
+}
+
+// UpdateAppSyntheticChecksRequest is the request object for the PUT /apps/{porter_app_name}/synthetic-checks endpoint
+type UpdateAppSyntheticChecksRequest struct {
+	DeploymentTargetID   string                  `json:"deployment_target_id"`
+	DeploymentTargetName string                  `json:"deployment_target_name"`
+	Checks               []AppSyntheticCheckSpec `json:"checks" form:"max=50,dive"`
+}
+
+// UpdateAppSyntheticChecksResponse is the response object for the PUT /apps/{porter_app_name}/synthetic-checks endpoint
+type UpdateAppSyntheticChecksResponse struct {
+	Checks []AppSyntheticCheck `json:"checks"`
+}
+
+// ListAppSyntheticCheckResultsRequest is the request object for the GET /apps/{porter_app_name}/synthetic-checks/{app_synthetic_check_id}/results endpoint
+type ListAppSyntheticCheckResultsRequest struct {
+	// Limit is the maximum number of results to return, most recent first. Defaults to 100
+	Limit int `schema:"limit" form:"omitempty,min=1,max=1000"`
+}
+
+// ListAppSyntheticCheckResultsResponse is the response object for the GET /apps/{porter_app_name}/synthetic-checks/{app_synthetic_check_id}/results endpoint
+type ListAppSyntheticCheckResultsResponse struct {
+	Results []AppSyntheticCheckResult `json:"results"`
+}
@@ -0,0 +1,78 @@
+package types
+
+import "time"
+
+// AppTimelineEntryType is the source of an entry on an app timeline
+type AppTimelineEntryType string
+
+const (
+	// AppTimelineEntryType_Revision is an app revision being applied
+	AppTimelineEntryType_Revision AppTimelineEntryType = "revision"
+	// AppTimelineEntryType_HelmUpgrade is a helm release of the app being installed, upgraded or rolled back
+	AppTimelineEntryType_HelmUpgrade AppTimelineEntryType = "helm_upgrade"
+	// AppTimelineEntryType_KubernetesEvent is a kubernetes event, or a container termination, for a service of the app
+	AppTimelineEntryType_KubernetesEvent AppTimelineEntryType = "kubernetes_event"
+	// AppTimelineEntryType_Scaling is a horizontal pod autoscaler changing the number of instances of a service
+	AppTimelineEntryType_Scaling AppTimelineEntryType = "scaling"
+	// AppTimelineEntryType_Incident is an incident detected by the porter agent
+	AppTimelineEntryType_Incident AppTimelineEntryType = "incident"
+)
+
+// AppTimelineSeverity indicates whether a timeline entry is a problem
+type AppTimelineSeverity string
+
+const (
+	// AppTimelineSeverity_Normal is an entry which is part of the normal operation of an app
+	AppTimelineSeverity_Normal AppTimelineSeverity = "normal"
+	// AppTimelineSeverity_Warning is an entry which indicates a problem with the app
+	AppTimelineSeverity_Warning AppTimelineSeverity = "warning"
+)
+
+// AppTimelineChange is a change made by a revision, compared to the revision which was deployed before it
+type AppTimelineChange struct {
+	// ServiceName is the service which was changed, or empty if the change applies to the whole app
+	ServiceName string `json:"service_name,omitempty"`
+	// Description describes the change, such as "raised ram of web from 512MB to 1024MB"
+	Description string `json:"description"`
+}
+
+// AppTimelineEntry is a single entry on an app timeline
+type AppTimelineEntry struct {
+	Type     AppTimelineEntryType `json:"type"`
+	Severity AppTimelineSeverity  `json:"severity"`
+	Time     time.Time            `json:"time"`
+	// ServiceName is the service the entry relates to, or empty if it relates to the whole app
+	ServiceName string `json:"service_name,omitempty"`
+	// Reason is a short machine-readable reason, such as OOMKilled, BackOff or SuccessfulRescale
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+	// Count is the number of times a kubernetes event occurred
+	Count int32 `json:"count,omitempty"`
+	// RevisionNumber is set on revision entries
+	RevisionNumber uint64 `json:"revision_number,omitempty"`
+	// Changes are set on revision entries
+	Changes []AppTimelineChange `json:"changes,omitempty"`
+	// Hint links a warning to the revision that most likely caused it, such as
+	// "OOMKilled 3m after revision 42 lowered ram of web from 1024MB to 512MB"
+	Hint string `json:"hint,omitempty"`
+}
+
+// GetAppTimelineRequest is the request object for the GET /apps/{porter_app_name}/timeline endpoint
+type GetAppTimelineRequest struct {
+	DeploymentTargetID   string `schema:"deployment_target_id"`
+	DeploymentTargetName string `schema:"deployment_target_name"`
+	// ServiceName restricts the timeline to entries of a single service, along with entries for the whole app
+	ServiceName string `schema:"service_name"`
+	// StartRange is a unix timestamp, which defaults to one day before EndRange
+	StartRange uint `schema:"startrange"`
+	// EndRange is a unix timestamp, which defaults to now
+	EndRange uint `schema:"endrange"`
+}
+
+// GetAppTimelineResponse is the response object for the GET /apps/{porter_app_name}/timeline endpoint
+type GetAppTimelineResponse struct {
+	// Entries are ordered from oldest to newest
+	Entries []AppTimelineEntry `json:"entries"`
+	// Warnings describe sources which could not be read, so that a partial timeline is not mistaken for a quiet one
+	Warnings []string `json:"warnings,omitempty"`
+}
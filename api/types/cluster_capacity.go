@@ -0,0 +1,85 @@
+package types
+
+// CapacityToleration allows simulated pods to be scheduled onto nodes with a matching taint
+type CapacityToleration struct {
+	Key string `json:"key"`
+	// Operator is either Equal or Exists. Defaults to Equal
+	Operator string `json:"operator,omitempty" form:"omitempty,oneof=Equal Exists"`
+	Value    string `json:"value,omitempty"`
+	// Effect is the taint effect to tolerate. An empty effect tolerates every effect
+	Effect string `json:"effect,omitempty" form:"omitempty,oneof=NoSchedule PreferNoSchedule NoExecute"`
+}
+
+// CapacityPodGroup is a number of identical pods, such as the instances of a service, to simulate scheduling for
+type CapacityPodGroup struct {
+	// Name identifies the group in the simulation result, and is matched against service names when simulating an app
+	Name     string `json:"name" form:"required,max=255"`
+	Replicas int    `json:"replicas" form:"required,min=1,max=1000"`
+	// CPUMillis, MemoryBytes and GPU are the resources requested by each pod
+	CPUMillis   int64 `json:"cpu_millis" form:"min=0"`
+	MemoryBytes int64 `json:"memory_bytes" form:"min=0"`
+	GPU         int64 `json:"gpu,omitempty" form:"min=0"`
+	// NodeSelector and Tolerations constrain which nodes the pods can be scheduled on. If neither is set, the pods
+	// inherit the constraints of the deployed service with the same name, or are scheduled onto application nodes.
+	NodeSelector map[string]string    `json:"node_selector,omitempty"`
+	Tolerations  []CapacityToleration `json:"tolerations,omitempty" form:"dive"`
+}
+
+// SimulateClusterCapacityRequest is the request object for the POST /clusters/{cluster_id}/capacity/simulate endpoint
+type SimulateClusterCapacityRequest struct {
+	// AppName and DeploymentTargetID identify an app whose pods are replaced by the simulated pods. If set, the pods
+	// of the app are removed from the cluster before the simulated pods are scheduled.
+	AppName            string             `json:"app_name,omitempty"`
+	DeploymentTargetID string             `json:"deployment_target_id,omitempty"`
+	Pods               []CapacityPodGroup `json:"pods" form:"required,min=1,max=100,dive"`
+}
+
+// CapacityUtilization is the percentage of allocatable resources which are requested by pods
+type CapacityUtilization struct {
+	CPUPercent    float64 `json:"cpu_percent"`
+	MemoryPercent float64 `json:"memory_percent"`
+}
+
+// CapacityNode is the projected usage of a node once the simulated pods are scheduled
+type CapacityNode struct {
+	Name         string `json:"name"`
+	InstanceType string `json:"instance_type"`
+	// Extra is true for nodes which must be added to the cluster to fit the simulated pods
+	Extra                  bool  `json:"extra"`
+	CPUMillisAllocatable   int64 `json:"cpu_millis_allocatable"`
+	CPUMillisRequested     int64 `json:"cpu_millis_requested"`
+	MemoryBytesAllocatable int64 `json:"memory_bytes_allocatable"`
+	MemoryBytesRequested   int64 `json:"memory_bytes_requested"`
+	// SimulatedPods is the number of simulated pods scheduled onto the node
+	SimulatedPods int                 `json:"simulated_pods"`
+	Utilization   CapacityUtilization `json:"utilization"`
+}
+
+// CapacityUnschedulablePods are pods of a group which do not fit onto the current nodes of the cluster
+type CapacityUnschedulablePods struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+	// Reason summarizes why the current nodes could not fit the pods, in the style of the kubernetes scheduler
+	Reason string `json:"reason"`
+	// FitsExtraNodes is false if the pods would not fit even onto an empty node of any instance type in the cluster
+	FitsExtraNodes bool `json:"fits_extra_nodes"`
+}
+
+// CapacityExtraNodes is the number of nodes of an instance type which must be added to fit the simulated pods
+type CapacityExtraNodes struct {
+	InstanceType string `json:"instance_type"`
+	Count        int    `json:"count"`
+}
+
+// SimulateClusterCapacityResponse is the response object for the POST /clusters/{cluster_id}/capacity/simulate endpoint
+type SimulateClusterCapacityResponse struct {
+	// Fits is true if every simulated pod can be scheduled onto the current nodes
+	Fits              bool                        `json:"fits"`
+	UnschedulablePods []CapacityUnschedulablePods `json:"unschedulable_pods"`
+	ExtraNodes        []CapacityExtraNodes        `json:"extra_nodes"`
+	// CurrentUtilization is the utilization of the schedulable nodes before the simulation
+	CurrentUtilization CapacityUtilization `json:"current_utilization"`
+	// ProjectedUtilization is the utilization of the schedulable nodes and any extra nodes after the simulation
+	ProjectedUtilization CapacityUtilization `json:"projected_utilization"`
+	Nodes                []CapacityNode      `json:"nodes"`
+}
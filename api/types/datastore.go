@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // DatastoreType represents the type of the datastore
 type DatastoreType string
 
@@ -35,3 +37,75 @@ type DatastoreCredential struct {
 	Password     string `json:"password"`
 	DatabaseName string `json:"database_name"`
 }
+
+// DatastoreSnapshot is a point-in-time backup of a datastore
+type DatastoreSnapshot struct {
+	// ID is the provider-specific identifier of the snapshot
+	ID string `json:"id"`
+	// DatastoreName is the name of the datastore that the snapshot was taken from
+	DatastoreName string `json:"datastore_name"`
+	// Type is either AUTOMATED or ON_DEMAND
+	Type string `json:"type"`
+	// Status is the status of the snapshot
+	Status string `json:"status"`
+	// SizeGigabytes is the size of the snapshot, if known
+	SizeGigabytes int64 `json:"size_gigabytes,omitempty"`
+	// CreatedAt is the time the snapshot was taken
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DatastoreRestoreWindow is the range of times that a datastore can be restored to
+type DatastoreRestoreWindow struct {
+	Earliest time.Time `json:"earliest"`
+	Latest   time.Time `json:"latest"`
+}
+
+// ListDatastoreSnapshotsResponse is the response body for the list datastore snapshots endpoint
+type ListDatastoreSnapshotsResponse struct {
+	// Snapshots is the list of snapshots for the datastore, newest first
+	Snapshots []DatastoreSnapshot `json:"snapshots"`
+	// RestoreWindow is the range of times that the datastore can be restored to, if point-in-time restore is available
+	RestoreWindow *DatastoreRestoreWindow `json:"restore_window,omitempty"`
+}
+
+// CreateDatastoreSnapshotRequest is the request body for the create datastore snapshot endpoint
+type CreateDatastoreSnapshotRequest struct{}
+
+// CreateDatastoreSnapshotResponse is the response body for the create datastore snapshot endpoint
+type CreateDatastoreSnapshotResponse struct {
+	// Snapshot is the snapshot that was triggered
+	Snapshot DatastoreSnapshot `json:"snapshot"`
+}
+
+// RestoreDatastoreRequest is the request body for the restore datastore endpoint. Exactly one of
+// SnapshotID and PointInTime must be set.
+type RestoreDatastoreRequest struct {
+	// Name is the name of the new datastore that will be created from the restore
+	Name string `json:"name" form:"required"`
+	// SnapshotID is the snapshot to restore from
+	SnapshotID string `json:"snapshot_id,omitempty"`
+	// PointInTime is the time to restore to
+	PointInTime *time.Time `json:"point_in_time,omitempty"`
+}
+
+// RestoreDatastoreResponse is the response body for the restore datastore endpoint
+type RestoreDatastoreResponse struct {
+	// Name is the name of the datastore being created from the restore
+	Name string `json:"name"`
+}
+
+// CloneDatastoreRequest is the request body for the clone datastore endpoint
+type CloneDatastoreRequest struct {
+	// DeploymentTargetID is the id or name of the preview environment to clone the datastore into
+	DeploymentTargetID string `json:"deployment_target_id" form:"required"`
+	// Name is the name of the new datastore. Defaults to <source>-<preview environment name>
+	Name string `json:"name,omitempty"`
+}
+
+// CloneDatastoreResponse is the response body for the clone datastore endpoint
+type CloneDatastoreResponse struct {
+	// Name is the name of the datastore being created from the clone
+	Name string `json:"name"`
+	// DeploymentTargetID is the id of the preview environment that the clone belongs to
+	DeploymentTargetID string `json:"deployment_target_id"`
+}
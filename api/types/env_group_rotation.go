@@ -0,0 +1,117 @@
+package types
+
+import "time"
+
+// EnvGroupRotationHookType is how the new value of a rotated env group key is produced
+type EnvGroupRotationHookType string
+
+const (
+	// EnvGroupRotationHookType_Generator generates a random string as the new value
+	EnvGroupRotationHookType_Generator EnvGroupRotationHookType = "generator"
+	// EnvGroupRotationHookType_Job runs a job in the cluster which rotates the credential and writes the new value to /dev/termination-log
+	EnvGroupRotationHookType_Job EnvGroupRotationHookType = "job"
+)
+
+// EnvGroupRotationStatus is the outcome of the most recent rotation of an env group key
+type EnvGroupRotationStatus string
+
+const (
+	// EnvGroupRotationStatus_Succeeded means that a new version of the env group was created with the rotated value, and linked apps were rolled
+	EnvGroupRotationStatus_Succeeded EnvGroupRotationStatus = "succeeded"
+	// EnvGroupRotationStatus_Failed means that the value could not be rotated, or that linked apps could not be rolled once it was
+	EnvGroupRotationStatus_Failed EnvGroupRotationStatus = "failed"
+)
+
+// EnvGroupRotationGenerator configures the built-in generator, which produces a random string
+type EnvGroupRotationGenerator struct {
+	// Length is the number of characters of the generated value. Defaults to 32
+	Length uint `json:"length,omitempty" form:"omitempty,min=8,max=256"`
+	// Lower only uses lowercase letters, for values which are case-insensitive. Defaults to letters of both cases and digits
+	Lower bool `json:"lower,omitempty"`
+}
+
+// EnvGroupRotationJob configures a job which runs in the cluster to rotate a credential, such as changing a database password.
+// The job is passed the env group name and key as PORTER_ENV_GROUP_NAME and PORTER_ENV_GROUP_KEY, and must write the new value
+// to the file in PORTER_ROTATION_OUTPUT_FILE, which is /dev/termination-log. The value is never read from the job's logs, so it
+// must not be printed. It can be at most 4096 bytes.
+type EnvGroupRotationJob struct {
+	// Image is the image the job runs
+	Image string `json:"image" form:"required,max=1024"`
+	// Command is the command the job runs. Defaults to the entrypoint of the image
+	Command []string `json:"command,omitempty" form:"max=50,dive,max=4096"`
+	// TimeoutSeconds is how long the job may run before the rotation fails. Defaults to 5 minutes
+	TimeoutSeconds uint `json:"timeout_seconds,omitempty" form:"omitempty,min=30,max=3600"`
+}
+
+// EnvGroupRotationPolicySpec declares that a key of an env group is rotated on a schedule. Rotated keys are always stored as secrets.
+type EnvGroupRotationPolicySpec struct {
+	// Key is the env group key which is rotated
+	Key string `json:"key" form:"required,max=255"`
+	// IntervalHours is how often the key is rotated
+	IntervalHours uint `json:"interval_hours" form:"required,min=1,max=8760"`
+	// HookType is how the new value is produced
+	HookType EnvGroupRotationHookType `json:"hook_type" form:"required,oneof=generator job"`
+	// Generator configures the built-in generator, if HookType is generator
+	Generator *EnvGroupRotationGenerator `json:"generator,omitempty"`
+	// Job configures the rotation job, if HookType is job
+	Job *EnvGroupRotationJob `json:"job,omitempty"`
+	// Channels are the names of the Slack channels notified when a rotation fails. Defaults to every Slack channel connected to the project
+	Channels []string `json:"channels,omitempty" form:"max=20,dive,max=255"`
+}
+
+// EnvGroupRotationPolicy is a rotation policy of an env group key, along with the result of its most recent rotation
+type EnvGroupRotationPolicy struct {
+	EnvGroupRotationPolicySpec
+
+	ID           uint                   `json:"id"`
+	EnvGroupName string                 `json:"env_group_name"`
+	LastStatus   EnvGroupRotationStatus `json:"last_status,omitempty"`
+	LastError    string                 `json:"last_error,omitempty"`
+	// LastVersion is the env group version created by the most recent successful rotation
+	LastVersion     uint       `json:"last_version,omitempty"`
+	LastAttemptedAt *time.Time `json:"last_attempted_at,omitempty"`
+	LastRotatedAt   *time.Time `json:"last_rotated_at,omitempty"`
+	// NextRotationAt is when the key will next be rotated
+	NextRotationAt time.Time `json:"next_rotation_at"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// EnvGroupRotationFailureNotification is the content of a notification sent when an env group key cannot be rotated
+type EnvGroupRotationFailureNotification struct {
+	EnvGroupName string                 `json:"env_group_name"`
+	ClusterName  string                 `json:"cluster_name"`
+	Policy       EnvGroupRotationPolicy `json:"policy"`
+	Error        string                 `json:"error"`
+	FailedAt     time.Time              `json:"failed_at"`
+}
+
+// ListEnvGroupRotationPoliciesResponse is the response object for the GET /environment-groups/{env_group_name}/rotation-policies endpoint
+type ListEnvGroupRotationPoliciesResponse struct {
+	Policies []EnvGroupRotationPolicy `json:"policies"`
+}
+
+// CreateEnvGroupRotationPolicyRequest is the request object for the POST /environment-groups/{env_group_name}/rotation-policies endpoint
+type CreateEnvGroupRotationPolicyRequest struct {
+	EnvGroupRotationPolicySpec
+}
+
+// CreateEnvGroupRotationPolicyResponse is the response object for the POST /environment-groups/{env_group_name}/rotation-policies endpoint
+type CreateEnvGroupRotationPolicyResponse struct {
+	Policy EnvGroupRotationPolicy `json:"policy"`
+}
+
+// UpdateEnvGroupRotationPolicyRequest is the request object for the PUT /environment-groups/{env_group_name}/rotation-policies/{env_group_rotation_policy_id} endpoint
+type UpdateEnvGroupRotationPolicyRequest struct {
+	EnvGroupRotationPolicySpec
+}
+
+// UpdateEnvGroupRotationPolicyResponse is the response object for the PUT /environment-groups/{env_group_name}/rotation-policies/{env_group_rotation_policy_id} endpoint
+type UpdateEnvGroupRotationPolicyResponse struct {
+	Policy EnvGroupRotationPolicy `json:"policy"`
+}
+
+// RotateEnvGroupKeyResponse is the response object for the POST /environment-groups/{env_group_name}/rotation-policies/{env_group_rotation_policy_id}/rotate endpoint
+type RotateEnvGroupKeyResponse struct {
+	Policy EnvGroupRotationPolicy `json:"policy"`
+}
@@ -3,13 +3,14 @@ package types
 import "time"
 
 type Environment struct {
-	ID                uint     `json:"id"`
-	ProjectID         uint     `json:"project_id"`
-	ClusterID         uint     `json:"cluster_id"`
-	GitInstallationID uint     `json:"git_installation_id"`
-	GitRepoOwner      string   `json:"git_repo_owner"`
-	GitRepoName       string   `json:"git_repo_name"`
-	GitRepoBranches   []string `json:"git_repo_branches"`
+	ID                  uint     `json:"id"`
+	ProjectID           uint     `json:"project_id"`
+	ClusterID           uint     `json:"cluster_id"`
+	GitInstallationID   uint     `json:"git_installation_id"`
+	GitlabIntegrationID uint     `json:"gitlab_integration_id,omitempty"`
+	GitRepoOwner        string   `json:"git_repo_owner"`
+	GitRepoName         string   `json:"git_repo_name"`
+	GitRepoBranches     []string `json:"git_repo_branches"`
 
 	Name                 string            `json:"name"`
 	Mode                 string            `json:"mode"`
@@ -18,6 +19,38 @@ type Environment struct {
 	NewCommentsDisabled  bool              `json:"new_comments_disabled"`
 	NamespaceLabels      map[string]string `json:"namespace_labels,omitempty"`
 	GitDeployBranches    []string          `json:"git_deploy_branches"`
+
+	// Policy controls when the preview deployments of the environment are deleted or put to sleep
+	Policy *PreviewEnvironmentPolicy `json:"policy,omitempty"`
+}
+
+// PreviewEnvironmentPolicy controls the cost of the preview deployments of an environment. Zero values disable the
+// corresponding policy.
+type PreviewEnvironmentPolicy struct {
+	// TTLHours is how long a preview deployment is kept after the last push to its branch. If unset, the TTL of the
+	// Porter instance is used.
+	TTLHours uint `json:"ttl_hours,omitempty" form:"omitempty,min=1,max=720"`
+	// SleepAfterIdleHours scales a preview deployment to zero once its ingress has not received a request for this
+	// many hours. Sleeping deployments are woken by the next request.
+	SleepAfterIdleHours uint `json:"sleep_after_idle_hours,omitempty" form:"omitempty,min=1,max=168"`
+	// SleepSchedule scales preview deployments to zero during nights and weekends
+	SleepSchedule *PreviewSleepSchedule `json:"sleep_schedule,omitempty"`
+	// MaxConcurrentPreviews is the maximum number of preview deployments of the repository. When it is exceeded,
+	// the least recently used deployments are deleted.
+	MaxConcurrentPreviews uint `json:"max_concurrent_previews,omitempty" form:"omitempty,min=1,max=100"`
+}
+
+// PreviewSleepSchedule is a daily window during which preview deployments are asleep, such as 20 to 8
+type PreviewSleepSchedule struct {
+	// StartHour is the hour of the day at which preview deployments are put to sleep
+	StartHour uint `json:"start_hour" form:"max=23"`
+	// EndHour is the hour of the day at which preview deployments wake up. If it is before StartHour, the window
+	// spans midnight. If it equals StartHour, preview deployments only sleep on weekends.
+	EndHour uint `json:"end_hour" form:"max=23"`
+	// Weekends puts preview deployments to sleep for all of Saturday and Sunday
+	Weekends bool `json:"weekends"`
+	// Timezone is the IANA timezone of the schedule, such as America/New_York. Defaults to UTC.
+	Timezone string `json:"timezone,omitempty"`
 }
 
 type CreateEnvironmentRequest struct {
@@ -29,6 +62,17 @@ type CreateEnvironmentRequest struct {
 	GitDeployBranches  []string          `json:"git_deploy_branches"`
 }
 
+// CreateGitlabEnvironmentRequest is the request to preview the merge requests of a GitLab project
+type CreateGitlabEnvironmentRequest struct {
+	Name                string `json:"name" form:"required"`
+	GitlabIntegrationID uint   `json:"gitlab_integration_id" form:"required"`
+	// GitRepoPath is the path of the GitLab project, including its namespace
+	GitRepoPath        string   `json:"git_repo_path" form:"required"`
+	DisableNewComments bool     `json:"disable_new_comments"`
+	GitRepoBranches    []string `json:"git_repo_branches"`
+	GitDeployBranches  []string `json:"git_deploy_branches"`
+}
+
 type GitHubMetadata struct {
 	DeploymentID int64  `json:"gh_deployment_id"`
 	PRName       string `json:"gh_pr_name"`
@@ -64,6 +108,13 @@ type Deployment struct {
 	InstallationID     uint             `json:"gh_installation_id"`
 	LastWorkflowRunURL string           `json:"last_workflow_run_url"`
 	LastErrors         string           `json:"last_errors"`
+
+	// LastPushedAt is when a commit was last deployed to the preview, from which its TTL is counted
+	LastPushedAt *time.Time `json:"last_pushed_at,omitempty"`
+	// SleepingSince is set while the preview is scaled to zero by the sleep policies of its environment
+	SleepingSince *time.Time `json:"sleeping_since,omitempty"`
+	// SleepReason is why the preview was put to sleep, either idle or schedule
+	SleepReason string `json:"sleep_reason,omitempty"`
 }
 
 type CreateGHDeploymentRequest struct {
@@ -205,4 +256,7 @@ type UpdateEnvironmentSettingsRequest struct {
 	GitRepoBranches    []string          `json:"git_repo_branches"`
 	NamespaceLabels    map[string]string `json:"namespace_labels"`
 	GitDeployBranches  []string          `json:"git_deploy_branches"`
+
+	// Policy replaces the preview policy of the environment if it is set. An empty policy removes it.
+	Policy *PreviewEnvironmentPolicy `json:"policy"`
 }
@@ -0,0 +1,44 @@
+package types
+
+// ExecStream identifies the stream that a message on an exec or copy websocket belongs to. Every binary
+// message starts with a single ExecStream byte, followed by the payload for that stream.
+type ExecStream byte
+
+const (
+	// ExecStreamStdin carries data from the client to the stdin of the command
+	ExecStreamStdin ExecStream = 0
+	// ExecStreamStdout carries data from the stdout of the command to the client
+	ExecStreamStdout ExecStream = 1
+	// ExecStreamStderr carries data from the stderr of the command to the client
+	ExecStreamStderr ExecStream = 2
+	// ExecStreamExit carries an ExecExitMessage, and is the last message sent by the server
+	ExecStreamExit ExecStream = 3
+	// ExecStreamResize carries an ExecResizeMessage from the client when its terminal is resized
+	ExecStreamResize ExecStream = 4
+	// ExecStreamStdinClose is sent by the client with an empty payload once stdin has been exhausted
+	ExecStreamStdinClose ExecStream = 5
+)
+
+// ExecResizeMessage is the payload of an ExecStreamResize message
+type ExecResizeMessage struct {
+	Width  uint16 `json:"width"`
+	Height uint16 `json:"height"`
+}
+
+// ExecExitMessage is the payload of an ExecStreamExit message
+type ExecExitMessage struct {
+	// ExitCode is the exit code of the command, or -1 if the command could not be run
+	ExitCode int `json:"exit_code"`
+	// Error describes why the command could not be run, if applicable
+	Error string `json:"error,omitempty"`
+}
+
+// AppCopyDirection is the direction of a file copy to or from an app container
+type AppCopyDirection string
+
+const (
+	// AppCopyDirection_Upload copies a tar archive from the client into a directory in the container
+	AppCopyDirection_Upload AppCopyDirection = "upload"
+	// AppCopyDirection_Download copies a path in the container to the client as a tar archive
+	AppCopyDirection_Download AppCopyDirection = "download"
+)
@@ -76,6 +76,29 @@ type Infra struct {
 	// LatestOperation is the last operation that was run against this infra, if
 	// one exists
 	LatestOperation *Operation `json:"latest_operation"`
+
+	// Drift is the result of the last drift check, if the infra has been checked for drift
+	Drift *InfraDrift `json:"drift,omitempty"`
+}
+
+// InfraDrift is the result of a refresh-only plan, which compares the last-applied
+// configuration against the resources in the cloud provider
+type InfraDrift struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Drifted   bool      `json:"drifted"`
+
+	Resources []TerraformDriftedResource `json:"resources"`
+}
+
+// TerraformDriftedResource is a resource which was changed outside of Porter
+type TerraformDriftedResource struct {
+	Address      string `json:"address"`
+	ResourceType string `json:"resource_type"`
+	ResourceName string `json:"resource_name"`
+	Provider     string `json:"provider"`
+
+	// Action is how the resource was changed outside of Porter, such as "update" or "delete"
+	Action string `json:"action"`
 }
 
 type InfraCredentials struct {
@@ -119,6 +142,51 @@ type OperationMeta struct {
 	Status      string    `json:"status"`
 	Errored     bool      `json:"errored"`
 	Error       string    `json:"error"`
+
+	// Plan is the terraform plan for this operation, which is set once the plan has completed
+	Plan *TerraformPlan `json:"plan,omitempty"`
+
+	// ApprovedAt is the time that the plan was approved, if it has been approved
+	ApprovedAt *time.Time `json:"approved_at,omitempty"`
+}
+
+const (
+	// OperationStatusPlanning is the status of an operation while terraform plan is running
+	OperationStatusPlanning = "planning"
+
+	// OperationStatusAwaitingApproval is the status of an operation whose plan must be approved
+	// before it is applied
+	OperationStatusAwaitingApproval = "awaiting_approval"
+
+	// OperationStatusStarting is the status of an operation once its apply or destroy has started
+	OperationStatusStarting = "starting"
+
+	// OperationStatusCompleted is the status of an operation which has completed successfully
+	OperationStatusCompleted = "completed"
+)
+
+const (
+	// OperationTypeDriftCheck is an operation which runs a refresh-only plan to detect drift
+	OperationTypeDriftCheck = "drift_check"
+
+	// OperationTypeReconcile is an operation which re-applies the last-applied configuration to
+	// revert drift
+	OperationTypeReconcile = "reconcile"
+)
+
+// TerraformPlan is a summary of the changes that an operation will make once it is approved
+type TerraformPlan struct {
+	Add    int `json:"add"`
+	Change int `json:"change"`
+	Remove int `json:"remove"`
+
+	Resources []TerraformPlannedChange `json:"resources"`
+}
+
+// TerraformPlannedChange is a single resource change in a terraform plan
+type TerraformPlannedChange struct {
+	Address string `json:"address"`
+	Action  string `json:"action"`
 }
 
 type Operation struct {
@@ -0,0 +1,31 @@
+package types
+
+import "time"
+
+// LogSearch is a log search query saved for a project
+type LogSearch struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Query string `json:"query"`
+	// AppName is the app the search was saved for, or empty if the search applies to any app
+	AppName   string    `json:"app_name,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CreateLogSearchRequest is the request object for the POST /projects/{project_id}/log_searches endpoint
+type CreateLogSearchRequest struct {
+	Name    string `json:"name" form:"required,max=255"`
+	Query   string `json:"query" form:"required,max=2048"`
+	AppName string `json:"app_name,omitempty" form:"max=255"`
+}
+
+// CreateLogSearchResponse is the response object for the POST /projects/{project_id}/log_searches endpoint
+type CreateLogSearchResponse struct {
+	LogSearch LogSearch `json:"log_search"`
+}
+
+// ListLogSearchesResponse is the response object for the GET /projects/{project_id}/log_searches endpoint
+type ListLogSearchesResponse struct {
+	LogSearches []LogSearch `json:"log_searches"`
+}
@@ -0,0 +1,114 @@
+package types
+
+import "time"
+
+// URLParamWebAuthnCredentialID is the URL param for the id of a user's WebAuthn credential
+const URLParamWebAuthnCredentialID URLParam = "webauthn_credential_id"
+
+// MFAMethod is a second factor that can be used to complete a login
+type MFAMethod string
+
+const (
+	// MFAMethodTOTP is a time-based one-time password from an authenticator app
+	MFAMethodTOTP MFAMethod = "totp"
+
+	// MFAMethodWebAuthn is a WebAuthn security key or platform authenticator
+	MFAMethodWebAuthn MFAMethod = "webauthn"
+
+	// MFAMethodRecoveryCode is a single-use recovery code
+	MFAMethodRecoveryCode MFAMethod = "recovery_code"
+)
+
+// LoginMFARequiredResponse is returned from POST /api/login when the password is correct but the
+// user must complete a second factor through POST /api/login/mfa
+type LoginMFARequiredResponse struct {
+	MFARequired bool        `json:"mfa_required"`
+	Methods     []MFAMethod `json:"methods"`
+}
+
+// LoginMFARequest completes a login with a second factor. Exactly one of Code, RecoveryCode or
+// WebAuthn should be set.
+type LoginMFARequest struct {
+	Code         string                     `json:"code" form:"omitempty,numeric,len=6"`
+	RecoveryCode string                     `json:"recovery_code" form:"omitempty,max=32"`
+	WebAuthn     *WebAuthnAssertionResponse `json:"webauthn,omitempty"`
+}
+
+// WebAuthnChallengeResponse contains a challenge for a WebAuthn ceremony
+type WebAuthnChallengeResponse struct {
+	Challenge string `json:"challenge"`
+	RPID      string `json:"rp_id"`
+	RPName    string `json:"rp_name"`
+
+	// UserHandle is only set for registration ceremonies
+	UserHandle string `json:"user_handle,omitempty"`
+
+	// AllowCredentials is the list of credential ids that may be used for an assertion
+	AllowCredentials []string `json:"allow_credentials,omitempty"`
+
+	// ExcludeCredentials is the list of credential ids that are already registered for the user
+	ExcludeCredentials []string `json:"exclude_credentials,omitempty"`
+}
+
+// WebAuthnAssertionResponse is the base64url-encoded result of navigator.credentials.get()
+type WebAuthnAssertionResponse struct {
+	CredentialID      string `json:"credential_id" form:"required"`
+	ClientDataJSON    string `json:"client_data_json" form:"required"`
+	AuthenticatorData string `json:"authenticator_data" form:"required"`
+	Signature         string `json:"signature" form:"required"`
+}
+
+// RegisterWebAuthnCredentialRequest is the base64url-encoded result of navigator.credentials.create().
+// PublicKey is the value of AuthenticatorAttestationResponse.getPublicKey().
+type RegisterWebAuthnCredentialRequest struct {
+	Name              string `json:"name" form:"required,max=255"`
+	CredentialID      string `json:"credential_id" form:"required"`
+	ClientDataJSON    string `json:"client_data_json" form:"required"`
+	AuthenticatorData string `json:"authenticator_data" form:"required"`
+	PublicKey         string `json:"public_key" form:"required"`
+}
+
+// WebAuthnCredential is a registered WebAuthn credential
+type WebAuthnCredential struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// GetMFAStatusResponse describes the second factors configured for the current user
+type GetMFAStatusResponse struct {
+	Enabled                bool                 `json:"enabled"`
+	TOTPEnabled            bool                 `json:"totp_enabled"`
+	RecoveryCodesRemaining int                  `json:"recovery_codes_remaining"`
+	WebAuthnCredentials    []WebAuthnCredential `json:"webauthn_credentials"`
+}
+
+// EnrollTOTPResponse contains the secret for a pending TOTP enrollment
+type EnrollTOTPResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// VerifyTOTPRequest confirms a pending TOTP enrollment, or authorizes disabling TOTP
+type VerifyTOTPRequest struct {
+	Code string `json:"code" form:"required,numeric,len=6"`
+}
+
+// ConfirmSecondFactorRequest confirms a change to the second factors of the current user, so that a session alone
+// cannot regenerate recovery codes or remove a security key. Exactly one of Code or WebAuthn should be set.
+type ConfirmSecondFactorRequest struct {
+	Code     string                     `json:"code" form:"omitempty,numeric,len=6"`
+	WebAuthn *WebAuthnAssertionResponse `json:"webauthn,omitempty"`
+}
+
+// RecoveryCodesResponse contains a newly generated set of recovery codes. The codes are only
+// returned once.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// UpdateProjectMFARequest updates whether collaborators must have MFA enabled to access a project
+type UpdateProjectMFARequest struct {
+	RequireMFA bool `json:"require_mfa"`
+}
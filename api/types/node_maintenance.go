@@ -0,0 +1,68 @@
+package types
+
+import "time"
+
+// NodeMaintenanceEventType is the type of a progress event of a node maintenance operation
+type NodeMaintenanceEventType string
+
+const (
+	// NodeMaintenanceEventType_Cordoned means that new pods can no longer be scheduled onto the node
+	NodeMaintenanceEventType_Cordoned NodeMaintenanceEventType = "cordoned"
+	// NodeMaintenanceEventType_PodSkipped means that a pod is left on the node, such as a daemon set pod
+	NodeMaintenanceEventType_PodSkipped NodeMaintenanceEventType = "pod_skipped"
+	// NodeMaintenanceEventType_Evicting means that a pod is being evicted from the node
+	NodeMaintenanceEventType_Evicting NodeMaintenanceEventType = "evicting"
+	// NodeMaintenanceEventType_EvictionBlocked means that evicting a pod would violate its PodDisruptionBudget, so the
+	// eviction is retried until the budget allows it or the operation times out
+	NodeMaintenanceEventType_EvictionBlocked NodeMaintenanceEventType = "eviction_blocked"
+	// NodeMaintenanceEventType_Evicted means that an evicted pod has been deleted
+	NodeMaintenanceEventType_Evicted NodeMaintenanceEventType = "evicted"
+	// NodeMaintenanceEventType_Drained means that every pod which can be evicted has left the node
+	NodeMaintenanceEventType_Drained NodeMaintenanceEventType = "drained"
+	// NodeMaintenanceEventType_WaitingForPods means that a rotation is waiting for the evicted pods to be ready on
+	// other nodes before draining the next node
+	NodeMaintenanceEventType_WaitingForPods NodeMaintenanceEventType = "waiting_for_pods"
+	// NodeMaintenanceEventType_PodsReady means that the workloads of the evicted pods are ready on other nodes
+	NodeMaintenanceEventType_PodsReady NodeMaintenanceEventType = "pods_ready"
+	// NodeMaintenanceEventType_Completed is the last event of an operation which succeeded
+	NodeMaintenanceEventType_Completed NodeMaintenanceEventType = "completed"
+	// NodeMaintenanceEventType_Failed is the last event of an operation which failed or timed out
+	NodeMaintenanceEventType_Failed NodeMaintenanceEventType = "failed"
+)
+
+// NodeMaintenanceEvent is a progress event of a node maintenance operation, which is streamed over a websocket
+type NodeMaintenanceEvent struct {
+	Type      NodeMaintenanceEventType `json:"type"`
+	Time      time.Time                `json:"time"`
+	Node      string                   `json:"node,omitempty"`
+	Namespace string                   `json:"namespace,omitempty"`
+	Pod       string                   `json:"pod,omitempty"`
+	Message   string                   `json:"message,omitempty"`
+}
+
+// NodeSchedulingResponse is the response object for the /nodes/{node_name}/cordon and /nodes/{node_name}/uncordon
+// endpoints
+type NodeSchedulingResponse struct {
+	Name          string `json:"name"`
+	Unschedulable bool   `json:"unschedulable"`
+}
+
+// DrainNodeRequest is the request object for the /nodes/{node_name}/drain endpoint
+type DrainNodeRequest struct {
+	// TimeoutSeconds bounds how long the drain may take. Defaults to 10 minutes
+	TimeoutSeconds int `schema:"timeout_seconds" form:"omitempty,min=30,max=3600"`
+	// Force evicts pods which are not managed by a controller, and are therefore not recreated on another node
+	Force bool `schema:"force"`
+}
+
+// RotateNodesRequest is the request object for the /nodes/rotate endpoint
+type RotateNodesRequest struct {
+	// NodeNames are the nodes to rotate, in order. Exactly one of NodeNames and NodeSelector must be set
+	NodeNames []string `schema:"node_names" form:"omitempty,max=100"`
+	// NodeSelector is a label selector for the nodes to rotate, such as a node group label
+	NodeSelector string `schema:"node_selector"`
+	// TimeoutSeconds bounds how long each node may take to drain and for its app pods to be ready elsewhere. Defaults to
+	// 10 minutes
+	TimeoutSeconds int  `schema:"timeout_seconds" form:"omitempty,min=30,max=3600"`
+	Force          bool `schema:"force"`
+}
@@ -120,6 +120,12 @@ const (
 	PorterAppEventType_AppEvent PorterAppEventType = "APP_EVENT"
 	// PorterAppEventType_Notification represents a translation of the porter agent app event into the new notification format, which details everything that occurs while the app is running
 	PorterAppEventType_Notification PorterAppEventType = "NOTIFICATION"
+	// PorterAppEventType_Exec represents a command that was run in, or a file that was copied to or from, a running app container
+	PorterAppEventType_Exec PorterAppEventType = "EXEC"
+	// PorterAppEventType_Canary represents the analysis of a new image on a fraction of a web service's traffic, before the image is rolled out
+	PorterAppEventType_Canary PorterAppEventType = "CANARY"
+	// PorterAppEventType_Seed represents the seed jobs which were run against the addons of a preview environment
+	PorterAppEventType_Seed PorterAppEventType = "SEED"
 )
 
 // PorterAppEventStatus is an alias for a string that represents a Porter Stack Event Status
@@ -0,0 +1,94 @@
+package types
+
+import "time"
+
+// PreviewSeedStepType is the kind of work a preview seed step does
+type PreviewSeedStepType string
+
+const (
+	// PreviewSeedStepType_Job runs a job service of the app in the preview environment, such as loading a SQL dump
+	PreviewSeedStepType_Job PreviewSeedStepType = "job"
+	// PreviewSeedStepType_CloneDatastore clones a datastore, such as a sanitized copy of a parent database, into the
+	// preview environment
+	PreviewSeedStepType_CloneDatastore PreviewSeedStepType = "clone_datastore"
+)
+
+// PreviewSeedStep is a step which runs after the addons of a preview environment are ready. Steps run in order, and a
+// failed step stops the steps after it.
+type PreviewSeedStep struct {
+	Name string              `json:"name"`
+	Type PreviewSeedStepType `json:"type"`
+	// JobName is the job service which is run by job steps
+	JobName string `json:"job_name,omitempty"`
+	// RunCommand optionally overrides the run command of the job service
+	RunCommand string `json:"run_command,omitempty"`
+	// SourceDatastore is the datastore which is cloned by clone_datastore steps
+	SourceDatastore string `json:"source_datastore,omitempty"`
+	// DatastoreName is the name of the cloned datastore. Defaults to <source>-<preview environment name>
+	DatastoreName string `json:"datastore_name,omitempty"`
+	// TimeoutSeconds is how long the step may run before it is failed
+	TimeoutSeconds int `json:"timeout_seconds"`
+}
+
+// PreviewSeedStepStatus is the outcome of a preview seed step
+type PreviewSeedStepStatus string
+
+const (
+	// PreviewSeedStepStatus_Succeeded means that the step completed
+	PreviewSeedStepStatus_Succeeded PreviewSeedStepStatus = "succeeded"
+	// PreviewSeedStepStatus_Failed means that the step could not be started or exited with an error
+	PreviewSeedStepStatus_Failed PreviewSeedStepStatus = "failed"
+	// PreviewSeedStepStatus_TimedOut means that the step did not complete within its timeout
+	PreviewSeedStepStatus_TimedOut PreviewSeedStepStatus = "timed_out"
+	// PreviewSeedStepStatus_Skipped means that the step was not run because an earlier step did not succeed
+	PreviewSeedStepStatus_Skipped PreviewSeedStepStatus = "skipped"
+)
+
+// PreviewSeedStepResult is the result of running a preview seed step
+type PreviewSeedStepResult struct {
+	Name   string                `json:"name" form:"required,max=63"`
+	Type   PreviewSeedStepType   `json:"type" form:"required,oneof=job clone_datastore"`
+	Status PreviewSeedStepStatus `json:"status" form:"required,oneof=succeeded failed timed_out skipped"`
+	// Reason explains why the step did not succeed
+	Reason      string    `json:"reason,omitempty"`
+	StartedAt   time.Time `json:"started_at,omitempty"`
+	CompletedAt time.Time `json:"completed_at,omitempty"`
+}
+
+// PreviewSeed is a run of the seed steps of a preview environment, which is recorded as a SEED porter app event
+type PreviewSeed struct {
+	// EventID is the ID of the porter app event that the seed is recorded in
+	EventID   string `json:"event_id"`
+	CommitSHA string `json:"commit_sha,omitempty"`
+	// Reseed is true if the preview environment had already been seeded, and was seeded again on request
+	Reseed    bool                    `json:"reseed,omitempty"`
+	Succeeded bool                    `json:"succeeded"`
+	Steps     []PreviewSeedStepResult `json:"steps"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+// ReportPreviewSeedRequest is the request object for the POST /apps/{porter_app_name}/preview-seed endpoint
+type ReportPreviewSeedRequest struct {
+	DeploymentTargetID string `json:"deployment_target_id" form:"required"`
+	// PRNumber is the pull request that the preview environment was created for. If set, the result is commented on it
+	PRNumber  int                     `json:"pr_number"`
+	CommitSHA string                  `json:"commit_sha"`
+	Reseed    bool                    `json:"reseed"`
+	Steps     []PreviewSeedStepResult `json:"steps" form:"required,min=1,max=20,dive"`
+}
+
+// ReportPreviewSeedResponse is the response object for the POST /apps/{porter_app_name}/preview-seed endpoint
+type ReportPreviewSeedResponse struct {
+	Seed PreviewSeed `json:"seed"`
+}
+
+// GetPreviewSeedRequest is the request object for the GET /apps/{porter_app_name}/preview-seed endpoint
+type GetPreviewSeedRequest struct {
+	DeploymentTargetID string `schema:"deployment_target_id" form:"required"`
+}
+
+// GetPreviewSeedResponse is the response object for the GET /apps/{porter_app_name}/preview-seed endpoint
+type GetPreviewSeedResponse struct {
+	// Seed is the latest seed of the preview environment, or nil if it has never been seeded
+	Seed *PreviewSeed `json:"seed"`
+}
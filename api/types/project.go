@@ -63,6 +63,8 @@ type Project struct {
 	AdvancedRbacEnabled             bool    `json:"advanced_rbac_enabled"`
 	// ReferralCode is a unique code that can be shared to referr other users to Porter
 	ReferralCode string `json:"referral_code"`
+	// RequireMFA is true if collaborators must have multi-factor authentication enabled to access the project
+	RequireMFA bool `json:"require_mfa"`
 }
 
 // FeatureFlags is a struct that contains old feature flag representations
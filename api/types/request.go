@@ -66,6 +66,14 @@ const (
 	URLParamDeploymentTargetIdentifier URLParam = "deployment_target_identifier"
 	URLParamWebhookID                  URLParam = "webhook_id"
 	URLParamJobRunName                 URLParam = "job_run_name"
+	URLParamAppDashboardID             URLParam = "app_dashboard_id"
+	URLParamAppSLOID                   URLParam = "app_slo_id"
+	URLParamLogSearchID                URLParam = "log_search_id"
+	URLParamAppLogAlertRuleID          URLParam = "app_log_alert_rule_id"
+	URLParamEnvGroupRotationPolicyID   URLParam = "env_group_rotation_policy_id"
+	URLParamStatusPageSlug             URLParam = "status_page_slug"
+	URLParamStatusPageIncidentID       URLParam = "status_page_incident_id"
+	URLParamAppSyntheticCheckID        URLParam = "app_synthetic_check_id"
 )
 
 type Path struct {
@@ -95,6 +103,10 @@ type APIRequestMetadata struct {
 
 const RequestScopeCtxKey = "requestscopes"
 
+// MFAVerifiedAtCtxKey is the context key for when the user making a request completed a second factor, which is
+// only set for sessions and user tokens that were created by a login with multi-factor authentication
+const MFAVerifiedAtCtxKey = "mfaverifiedat"
+
 type RequestAction struct {
 	Verb     APIVerb
 	Resource NameOrUInt
@@ -0,0 +1,144 @@
+package types
+
+import "time"
+
+// StatusPageComponentType is the kind of resource that a component of a status page reports on
+type StatusPageComponentType string
+
+const (
+	// StatusPageComponentType_App is an app of the project. Its status is checked through its URL, if one is set.
+	StatusPageComponentType_App StatusPageComponentType = "app"
+	// StatusPageComponentType_Endpoint is a URL which is checked by the synthetic HTTP checker
+	StatusPageComponentType_Endpoint StatusPageComponentType = "endpoint"
+	// StatusPageComponentType_Cluster is a cluster of the project, whose status comes from its system services
+	StatusPageComponentType_Cluster StatusPageComponentType = "cluster"
+)
+
+// StatusPageIncidentStatus is the stage of a status page incident
+type StatusPageIncidentStatus string
+
+const (
+	// StatusPageIncidentStatus_Investigating is set when the cause of the incident is not yet known
+	StatusPageIncidentStatus_Investigating StatusPageIncidentStatus = "investigating"
+	// StatusPageIncidentStatus_Identified is set once the cause of the incident is known
+	StatusPageIncidentStatus_Identified StatusPageIncidentStatus = "identified"
+	// StatusPageIncidentStatus_Monitoring is set once a fix is in place and is being monitored
+	StatusPageIncidentStatus_Monitoring StatusPageIncidentStatus = "monitoring"
+	// StatusPageIncidentStatus_Resolved is set once the incident is over
+	StatusPageIncidentStatus_Resolved StatusPageIncidentStatus = "resolved"
+)
+
+// StatusPageComponent is a component of a status page
+type StatusPageComponent struct {
+	// ID is set to update an existing component, and is unset to create a new one
+	ID   uint                    `json:"id,omitempty"`
+	Name string                  `json:"name" form:"required,max=100"`
+	Type StatusPageComponentType `json:"type" form:"required,oneof=app endpoint cluster"`
+	// ClusterID is the cluster of an app or cluster component
+	ClusterID uint `json:"cluster_id,omitempty"`
+	// AppName is the name of the app of an app component
+	AppName string `json:"app_name,omitempty"`
+	// URL is checked by the synthetic HTTP checker. It is required for endpoint components and optional for apps.
+	URL string `json:"url,omitempty" form:"omitempty,url"`
+}
+
+// StatusPage is the configuration of the status page of a project
+type StatusPage struct {
+	// Slug identifies the status page in its public URL
+	Slug  string `json:"slug"`
+	Title string `json:"title"`
+	// Public pages can be viewed without logging in to Porter
+	Public     bool                  `json:"public"`
+	Components []StatusPageComponent `json:"components"`
+}
+
+// StatusPageIncident is an incident posted on a status page, either manually or by the synthetic HTTP checker
+type StatusPageIncident struct {
+	ID uint `json:"id"`
+	// ComponentID is the component affected by the incident, or zero if it affects the whole page
+	ComponentID uint                     `json:"component_id,omitempty"`
+	Title       string                   `json:"title"`
+	Message     string                   `json:"message,omitempty"`
+	Status      StatusPageIncidentStatus `json:"status"`
+	// Severity is either partial_failure or failure. Only failures count as downtime.
+	Severity ServiceStatus `json:"severity"`
+	// Automatic is set on incidents which were opened by the synthetic HTTP checker
+	Automatic  bool       `json:"automatic"`
+	StartedAt  time.Time  `json:"started_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// StatusPageDailyUptime is the uptime of a component over one day
+type StatusPageDailyUptime struct {
+	// Date is the day in UTC, formatted as YYYY-MM-DD
+	Date             string  `json:"date"`
+	UptimePercentage float64 `json:"uptime_percentage"`
+}
+
+// StatusPageComponentSummary is the current status and uptime history of a component of a status page
+type StatusPageComponentSummary struct {
+	ID     uint                    `json:"id"`
+	Name   string                  `json:"name"`
+	Type   StatusPageComponentType `json:"type"`
+	Status ServiceStatus           `json:"status"`
+	// UptimePercentage is the uptime over the history of the status page
+	UptimePercentage float64                 `json:"uptime_percentage"`
+	DailyUptime      []StatusPageDailyUptime `json:"daily_uptime"`
+	// LastCheckedAt is when the URL of the component was last checked
+	LastCheckedAt *time.Time `json:"last_checked_at,omitempty"`
+	// LatencyMs is the latency of the last check of the URL of the component
+	LatencyMs uint `json:"latency_ms,omitempty"`
+}
+
+// StatusPageSummary is the current status of every component of a status page, and its recent incidents
+type StatusPageSummary struct {
+	Slug  string `json:"slug"`
+	Title string `json:"title"`
+	// Status is the worst status of the components of the page
+	Status     ServiceStatus                `json:"status"`
+	Components []StatusPageComponentSummary `json:"components"`
+	Incidents  []StatusPageIncident         `json:"incidents"`
+}
+
+// GetStatusPageResponse is the response to GET /api/projects/{project_id}/status-page
+type GetStatusPageResponse struct {
+	// StatusPage is nil if the project has no status page
+	StatusPage *StatusPage        `json:"status_page,omitempty"`
+	Summary    *StatusPageSummary `json:"summary,omitempty"`
+}
+
+// UpdateStatusPageRequest creates or replaces the status page of a project. Components which are not in the request
+// are removed from the page.
+type UpdateStatusPageRequest struct {
+	Slug       string                `json:"slug" form:"required,max=63"`
+	Title      string                `json:"title" form:"required,max=100"`
+	Public     bool                  `json:"public"`
+	Components []StatusPageComponent `json:"components" form:"max=50,dive"`
+}
+
+// UpdateStatusPageResponse is the response to PUT /api/projects/{project_id}/status-page
+type UpdateStatusPageResponse struct {
+	StatusPage StatusPage `json:"status_page"`
+}
+
+// CreateStatusPageIncidentRequest posts an incident on the status page of a project
+type CreateStatusPageIncidentRequest struct {
+	// ComponentID is the affected component, or zero if the incident affects the whole page
+	ComponentID uint                     `json:"component_id"`
+	Title       string                   `json:"title" form:"required,max=200"`
+	Message     string                   `json:"message" form:"max=5000"`
+	Status      StatusPageIncidentStatus `json:"status" form:"omitempty,oneof=investigating identified monitoring resolved"`
+	Severity    ServiceStatus            `json:"severity" form:"required,oneof=partial_failure failure"`
+}
+
+// UpdateStatusPageIncidentRequest posts an update to an incident. Setting the status to resolved ends the incident.
+type UpdateStatusPageIncidentRequest struct {
+	Status  StatusPageIncidentStatus `json:"status" form:"required,oneof=investigating identified monitoring resolved"`
+	Message string                   `json:"message" form:"max=5000"`
+}
+
+// StatusPageIncidentResponse is the response to creating or updating a status page incident
+type StatusPageIncidentResponse struct {
+	Incident StatusPageIncident `json:"incident"`
+}
@@ -42,9 +42,25 @@ var (
 	appCpuMilli          int
 	appExistingPod       bool
 	appInteractive       bool
+	appLogsCSV           bool
+	appLogsGrep          string
+	appLogsJSON          bool
+	appLogsQuery         string
+	appLogsSaveSearch    string
+	appLogsSearch        string
+	appLogsSince         time.Duration
+	appMetricsDashboard  string
+	appMetricsQuery      string
+	appMetricsSince      time.Duration
+	appMetricsStep       string
 	appMemoryMi          int
 	appNamespace         string
+	appPodName           string
+	appStdin             bool
 	appTag               string
+	appTimelineService   string
+	appTimelineSince     time.Duration
+	appTTY               bool
 	appVerbose           bool
 	appWait              bool
 	deploymentTargetName string
@@ -299,15 +315,215 @@ in that it only updates the app, but does not attempt to build a new image.`,
 	appLogsCmd := &cobra.Command{
 		Use:   "logs [application]",
 		Args:  cobra.MinimumNArgs(1),
-		Short: "Streams the latest logs for an application.",
+		Short: "Streams the latest logs for an application, or searches its past logs.",
+		Long: fmt.Sprintf(`%s
+
+Streams the latest logs for an application. When any of --since, --grep, --query, --search,
+--save-search, --json or --csv is set, the logs of the given period are searched instead, from
+oldest to newest.
+
+Queries are a list of terms, all of which must match:
+
+  timeout                      lines containing "timeout"
+  "connection refused"         lines containing a phrase
+  /status=5\d\d/               lines matching a regular expression
+  -healthcheck                 lines not containing "healthcheck"
+  level:error, level:>=warn    lines with a severity, detected from JSON, logfmt or keywords
+  pod:web-7d9f*                lines from pods matching a glob
+  service:web, stream:stderr   lines from a service or an output stream
+  revision:42                  lines from a revision
+  user.id:123, status:>=500    JSON logs whose field matches a glob or a numeric comparison
+
+Examples:
+
+  %s
+  %s
+  %s`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter app logs\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app logs my-app --since 2h --grep 'timed out' --json"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app logs my-app --since 1d --query 'level:>=warn -healthcheck' --save-search warnings"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app logs my-app --since 30m --search warnings --csv > warnings.csv"),
+		),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return checkLoginAndRunWithConfig(cmd, cliConf, args, appLogs)
 		},
 	}
 	appLogsCmd.PersistentFlags().String("service", "", "the name of the service to get logs for")
+	appLogsCmd.Flags().DurationVar(&appLogsSince, "since", time.Hour, "how far back to search logs")
+	appLogsCmd.Flags().StringVar(&appLogsGrep, "grep", "", "only show lines matching a regular expression")
+	appLogsCmd.Flags().StringVar(&appLogsQuery, "query", "", "only show lines matching a log query")
+	appLogsCmd.Flags().StringVar(&appLogsSearch, "search", "", "only show lines matching the query of a saved search")
+	appLogsCmd.Flags().StringVar(&appLogsSaveSearch, "save-search", "", "save the query of this search for the project under a name")
+	appLogsCmd.Flags().BoolVar(&appLogsJSON, "json", false, "print logs as newline-delimited JSON")
+	appLogsCmd.Flags().BoolVar(&appLogsCSV, "csv", false, "print logs as CSV")
+	appLogsCmd.MarkFlagsMutuallyExclusive("json", "csv")
 
 	appCmd.AddCommand(appLogsCmd)
 
+	// appPortForwardCmd represents the "porter app port-forward" subcommand
+	appPortForwardCmd := &cobra.Command{
+		Use:   "port-forward [application] [service] [local:]remote",
+		Args:  cobra.ExactArgs(3),
+		Short: "Forwards a local port to a port on a service through the Porter API.",
+		Long: fmt.Sprintf(`%s
+
+Forwards connections on a local port to a healthy pod of an application service. Traffic is
+tunneled through the Porter API, so no kubeconfig is required. If the local port is omitted, the
+remote port is also used locally. For example:
+
+  %s
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter app port-forward\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app port-forward my-app worker 8081:8080"),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, appPortForward)
+		},
+	}
+
+	appCmd.AddCommand(appPortForwardCmd)
+
+	// appExecCmd represents the "porter app exec" subcommand
+	appExecCmd := &cobra.Command{
+		Use:   "exec [application] [service] -- COMMAND [args...]",
+		Args:  cobra.MinimumNArgs(3),
+		Short: "Runs a command in a running replica of a service.",
+		Long: fmt.Sprintf(`%s
+
+Runs a command in a running replica of an application service, rather than in an ephemeral copy
+as "porter app run" does. This makes it possible to inspect the in-memory state of a replica. A
+healthy replica is chosen unless --pod is set. Every session is recorded in the activity feed of
+the application. For example:
+
+  %s
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter app exec\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app exec my-app web -it -- /bin/sh"),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, appExec)
+		},
+	}
+
+	appExecCmd.Flags().StringVar(&appPodName, "pod", "", "name of the replica to run the command in")
+	appExecCmd.Flags().StringVarP(&appContainerName, "container", "c", "", "name of the container to run the command in")
+	appExecCmd.Flags().BoolVarP(&appStdin, "stdin", "i", false, "pass stdin to the command")
+	appExecCmd.Flags().BoolVarP(&appTTY, "tty", "t", false, "allocate a terminal for the command")
+
+	appCmd.AddCommand(appExecCmd)
+
+	// appCopyCmd represents the "porter app cp" subcommand
+	appCopyCmd := &cobra.Command{
+		Use:   "cp [application] [service] SOURCE DESTINATION",
+		Args:  cobra.ExactArgs(4),
+		Short: "Copies files to or from a running replica of a service.",
+		Long: fmt.Sprintf(`%s
+
+Copies a file or directory between the local machine and a running replica of an application
+service. Paths in the container must be absolute and prefixed with ":". A healthy replica is
+chosen unless --pod is set, and the container image must include tar. Every copy is recorded in
+the activity feed of the application. For example:
+
+  %s
+  %s
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter app cp\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app cp my-app web :/tmp/heap.pprof ./heap.pprof"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app cp my-app web ./fixtures :/app/fixtures"),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, appCopy)
+		},
+	}
+
+	appCopyCmd.Flags().StringVar(&appPodName, "pod", "", "name of the replica to copy to or from")
+	appCopyCmd.Flags().StringVarP(&appContainerName, "container", "c", "", "name of the container to copy to or from")
+
+	appCmd.AddCommand(appCopyCmd)
+
+	// appMetricsCmd represents the "porter app metrics" subcommand
+	appMetricsCmd := &cobra.Command{
+		Use:   "metrics [application]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Charts custom metrics for an application in the terminal.",
+		Long: fmt.Sprintf(`%s
+
+Runs a PromQL query against the metrics of an application and prints a sparkline for each
+series in the result. Every selector in the query is restricted to the namespace of the
+application, so only metrics exported by the application's deployment target are returned.
+Alternatively, charts every panel of a dashboard saved for the application. For example:
+
+  %s
+  %s
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter app metrics\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app metrics my-app --query 'sum(rate(orders_total[5m]))' --since 6h"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app metrics my-app --dashboard checkout"),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, appMetrics)
+		},
+	}
+
+	appMetricsCmd.Flags().StringVarP(&appMetricsQuery, "query", "q", "", "the PromQL query to chart")
+	appMetricsCmd.Flags().StringVar(&appMetricsDashboard, "dashboard", "", "the name of a saved dashboard to chart")
+	appMetricsCmd.Flags().DurationVar(&appMetricsSince, "since", time.Hour, "how far back to chart metrics")
+	appMetricsCmd.Flags().StringVar(&appMetricsStep, "step", "", "the query resolution, such as 1m (default chosen based on --since)")
+
+	appCmd.AddCommand(appMetricsCmd)
+
+	// appTimelineCmd represents the "porter app timeline" subcommand
+	appTimelineCmd := &cobra.Command{
+		Use:   "timeline [application]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Shows the revisions, kubernetes events, scaling events and incidents of an application in order.",
+		Long: fmt.Sprintf(`%s
+
+Merges the revisions, helm upgrades, kubernetes events (such as OOMKilled, BackOff and
+FailedScheduling), autoscaler scaling events and incidents of an application into a single
+timeline. Warnings which happen shortly after a revision changed the affected service include
+a hint naming the revision and its changes. For example:
+
+  %s
+  %s
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter app timeline\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app timeline my-app --since 6h"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app timeline my-app --service web"),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, appTimeline)
+		},
+	}
+
+	appTimelineCmd.Flags().StringVar(&appTimelineService, "service", "", "only show entries for this service, along with entries for the whole app")
+	appTimelineCmd.Flags().DurationVar(&appTimelineSince, "since", 24*time.Hour, "how far back to show the timeline")
+
+	appCmd.AddCommand(appTimelineCmd)
+
+	// appStatusCmd represents the "porter app status" subcommand
+	appStatusCmd := &cobra.Command{
+		Use:   "status [application]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Shows the synthetic checks of an application and the result of their most recent run.",
+		Long: fmt.Sprintf(`%s
+
+Shows the synthetic HTTP checks of the web services of an application. Each check is sent to
+a domain of a web service from outside the cluster, and is shown with its status, latency,
+status code, TLS certificate expiry and any open incident. For example:
+
+  %s
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter app status\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app status my-app"),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, appStatus)
+		},
+	}
+
+	appCmd.AddCommand(appStatusCmd)
+
 	return appCmd
 }
 
@@ -603,6 +819,42 @@ func appLogs(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client
 		serviceName = serviceFlag
 	}
 
+	var searchHistory bool
+	for _, flag := range []string{"since", "grep", "query", "search", "save-search", "json", "csv"} {
+		if cmd.Flags().Changed(flag) {
+			searchHistory = true
+		}
+	}
+
+	if searchHistory {
+		format := v2.LogOutputFormat_Text
+		switch {
+		case appLogsJSON:
+			format = v2.LogOutputFormat_JSON
+		case appLogsCSV:
+			format = v2.LogOutputFormat_CSV
+		}
+
+		err = v2.AppLogsSearch(ctx, v2.AppLogsSearchInput{
+			CLIConfig:            cliConfig,
+			Client:               client,
+			AppName:              appName,
+			DeploymentTargetName: deploymentTargetName,
+			ServiceName:          serviceName,
+			Query:                appLogsQuery,
+			Grep:                 appLogsGrep,
+			SavedSearch:          appLogsSearch,
+			SaveAs:               appLogsSaveSearch,
+			Since:                appLogsSince,
+			Format:               format,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to search app logs: %w", err)
+		}
+
+		return nil
+	}
+
 	err = v2.AppLogs(ctx, v2.AppLogsInput{
 		CLIConfig:            cliConfig,
 		Client:               client,
@@ -617,6 +869,169 @@ func appLogs(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client
 	return nil
 }
 
+func appPortForward(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, args []string) error {
+	appName := args[0]
+	if appName == "" {
+		return fmt.Errorf("app name must be specified")
+	}
+
+	serviceName := args[1]
+	if serviceName == "" {
+		return fmt.Errorf("service name must be specified")
+	}
+
+	localPort, remotePort, err := v2.ParsePortForwardPorts(args[2])
+	if err != nil {
+		return err
+	}
+
+	err = v2.AppPortForward(ctx, v2.AppPortForwardInput{
+		CLIConfig:            cliConfig,
+		Client:               client,
+		AppName:              appName,
+		ServiceName:          serviceName,
+		DeploymentTargetName: deploymentTargetName,
+		LocalPort:            localPort,
+		RemotePort:           remotePort,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to port-forward: %w", err)
+	}
+
+	return nil
+}
+
+func appExec(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, _ config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	// everything after "--" is the command, so flags for the command are not parsed by porter
+	if dash := cmd.ArgsLenAtDash(); dash != -1 && dash != 2 {
+		return fmt.Errorf("expected an application and a service before \"--\"")
+	}
+
+	appName := args[0]
+	if appName == "" {
+		return fmt.Errorf("app name must be specified")
+	}
+
+	serviceName := args[1]
+	if serviceName == "" {
+		return fmt.Errorf("service name must be specified")
+	}
+
+	exitCode, err := v2.AppExec(ctx, v2.AppExecInput{
+		CLIConfig:            cliConfig,
+		Client:               client,
+		AppName:              appName,
+		ServiceName:          serviceName,
+		DeploymentTargetName: deploymentTargetName,
+		PodName:              appPodName,
+		ContainerName:        appContainerName,
+		Command:              args[2:],
+		Stdin:                appStdin,
+		TTY:                  appTTY,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+
+	return nil
+}
+
+func appCopy(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, args []string) error {
+	appName := args[0]
+	if appName == "" {
+		return fmt.Errorf("app name must be specified")
+	}
+
+	serviceName := args[1]
+	if serviceName == "" {
+		return fmt.Errorf("service name must be specified")
+	}
+
+	err := v2.AppCopy(ctx, v2.AppCopyInput{
+		CLIConfig:            cliConfig,
+		Client:               client,
+		AppName:              appName,
+		ServiceName:          serviceName,
+		DeploymentTargetName: deploymentTargetName,
+		PodName:              appPodName,
+		ContainerName:        appContainerName,
+		Source:               args[2],
+		Destination:          args[3],
+	})
+	if err != nil {
+		return fmt.Errorf("failed to copy: %w", err)
+	}
+
+	return nil
+}
+
+func appMetrics(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, args []string) error {
+	appName := args[0]
+	if appName == "" {
+		return fmt.Errorf("app name must be specified")
+	}
+
+	err := v2.AppMetrics(ctx, v2.AppMetricsInput{
+		CLIConfig:            cliConfig,
+		Client:               client,
+		AppName:              appName,
+		DeploymentTargetName: deploymentTargetName,
+		Query:                appMetricsQuery,
+		Dashboard:            appMetricsDashboard,
+		Since:                appMetricsSince,
+		Step:                 appMetricsStep,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get metrics: %w", err)
+	}
+
+	return nil
+}
+
+func appTimeline(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, args []string) error {
+	appName := args[0]
+	if appName == "" {
+		return fmt.Errorf("app name must be specified")
+	}
+
+	err := v2.AppTimeline(ctx, v2.AppTimelineInput{
+		CLIConfig:            cliConfig,
+		Client:               client,
+		AppName:              appName,
+		DeploymentTargetName: deploymentTargetName,
+		ServiceName:          appTimelineService,
+		Since:                appTimelineSince,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get timeline: %w", err)
+	}
+
+	return nil
+}
+
+func appStatus(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, args []string) error {
+	appName := args[0]
+	if appName == "" {
+		return fmt.Errorf("app name must be specified")
+	}
+
+	err := v2.AppStatus(ctx, v2.AppStatusInput{
+		CLIConfig:            cliConfig,
+		Client:               client,
+		AppName:              appName,
+		DeploymentTargetName: deploymentTargetName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get app status: %w", err)
+	}
+
+	return nil
+}
+
 func appRun(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, ff config.FeatureFlags, _ *cobra.Command, args []string) error {
 	if jobName != "" {
 		if !ff.ValidateApplyV2Enabled {
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -42,12 +41,15 @@ import (
 )
 
 var (
-	porterYAML   string
+	porterYAML string
+	// porterYAMLs are the values of the --file flag of apply, which may be repeated or point to a directory of porter.yaml files
+	porterYAMLs  []string
 	previewApply bool
 	// pullImageBeforeBuild is a flag that determines whether to pull the docker image from a repo before building
 	pullImageBeforeBuild bool
 	predeploy            bool
 	exact                bool
+	reseed               bool
 )
 
 func registerCommand_Apply(cliConf config.CLIConfig) *cobra.Command {
@@ -77,9 +79,18 @@ applying a configuration:
   PORTER_SOURCE_REPO          The URL of the Helm charts registry
   PORTER_SOURCE_VERSION       The version of the Helm chart to use
   PORTER_TAG                  The Docker image tag to use (like the git commit hash)
+
+Multiple apps can be applied together by repeating --file, or by passing a directory of porter.yaml
+files. The apps are applied in the order of their dependencies, which are declared in requiredApps
+or referenced by env variables from another app. Apps which do not depend on each other are applied
+in parallel, each layer of apps must be healthy before the apps which depend on it are applied, and
+the apps which depend on an app that fails to apply are skipped. For example:
+
+  %s
 	`,
 			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter apply\":"),
 			color.New(color.FgGreen, color.Bold).Sprintf("porter apply -f porter.yaml"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter apply -f api.yaml -f frontend.yaml"),
 		),
 		Run: func(cmd *cobra.Command, args []string) {
 			err := checkLoginAndRunWithConfig(cmd, cliConf, args, apply)
@@ -93,28 +104,31 @@ applying a configuration:
 		},
 	}
 	// applyValidateCmd represents the "porter apply validate" command when called
-	// with a porter.yaml file as an argument
+	// with one or more porter.yaml files, or directories of them, as the --file flag
 	applyValidateCmd := &cobra.Command{
 		Use:   "validate",
-		Short: "Validates a porter.yaml",
-		Run: func(*cobra.Command, []string) {
-			err := applyValidate()
+		Short: "Validates each porter.yaml given with --file, including the porter.yaml files in directories",
+		Run: func(cmd *cobra.Command, _ []string) {
+			count, err := applyValidate(cmd.Context())
 
 			if err != nil {
 				_, _ = color.New(color.FgRed).Fprintf(os.Stderr, "Error: %s\n", err.Error())
 				os.Exit(1)
-			} else {
+			} else if count == 1 {
 				_, _ = color.New(color.FgGreen).Printf("The porter.yaml file is valid!\n")
+			} else {
+				_, _ = color.New(color.FgGreen).Printf("All %d porter.yaml files are valid!\n", count)
 			}
 		},
 	}
 
 	applyCmd.AddCommand(applyValidateCmd)
 
-	applyCmd.PersistentFlags().StringVarP(&porterYAML, "file", "f", "", "path to porter.yaml")
+	applyCmd.PersistentFlags().StringArrayVarP(&porterYAMLs, "file", "f", nil, "path to porter.yaml, or to a directory of porter.yaml files. Repeat to apply multiple apps in the order of their dependencies")
 	applyCmd.PersistentFlags().BoolVarP(&previewApply, "preview", "p", false, "apply as preview environment based on current git branch")
 	applyCmd.PersistentFlags().BoolVar(&pullImageBeforeBuild, "pull-before-build", false, "attempt to pull image from registry before building")
 	applyCmd.PersistentFlags().BoolVar(&predeploy, "predeploy", false, "run predeploy job before deploying the application")
+	applyCmd.PersistentFlags().BoolVar(&reseed, "reseed", false, "run the seed steps of a preview environment again, even if it has already been seeded (requires --preview)")
 	applyCmd.PersistentFlags().BoolVar(&exact, "exact", false, "apply the exact configuration as specified in the porter.yaml file (default is to merge with existing configuration)")
 	applyCmd.PersistentFlags().BoolVarP(
 		&appWait,
@@ -146,7 +160,28 @@ func appNameFromEnvironmentVariable() string {
 	return ""
 }
 
+// firstPorterYAML returns the first value of the --file flag of apply, which is the only value when a single app is applied
+func firstPorterYAML() string {
+	if len(porterYAMLs) == 0 {
+		return ""
+	}
+
+	return porterYAMLs[0]
+}
+
+// isMultiAppApply returns true if the --file flag of apply was repeated or points to a directory
+func isMultiAppApply() bool {
+	if len(porterYAMLs) > 1 {
+		return true
+	}
+
+	info, err := os.Stat(firstPorterYAML())
+	return err == nil && info.IsDir()
+}
+
 func apply(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, _ config.FeatureFlags, cmd *cobra.Command, _ []string) (err error) {
+	porterYAML = firstPorterYAML()
+
 	project, err := client.GetProject(ctx, cliConfig.Project)
 	if err != nil {
 		return fmt.Errorf("could not retrieve project from Porter API. Please contact support@porter.run")
@@ -184,6 +219,9 @@ func apply(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client ap
 		if previewApply && !project.PreviewEnvsEnabled {
 			return fmt.Errorf("preview environments are not enabled for this project. Please contact support@porter.run")
 		}
+		if reseed && !previewApply {
+			return fmt.Errorf("--reseed can only be used with --preview")
+		}
 
 		patchOperations := appV2.PatchOperationsFromFlagValues(appV2.PatchOperationsFromFlagValuesInput{
 			EnvGroups:       extraAppConfig.AttachEnvGroups,
@@ -209,7 +247,19 @@ func apply(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client ap
 			Exact:                       exact,
 			PatchOperations:             patchOperations,
 			SkipBuild:                   noBuild,
+			Reseed:                      reseed,
 		}
+		if isMultiAppApply() {
+			if appName != "" {
+				return fmt.Errorf("PORTER_APP_NAME cannot be set when applying multiple apps, since each porter.yaml must specify the name of its app")
+			}
+
+			return v2.ApplyApps(ctx, v2.ApplyAppsInput{
+				ApplyInput:      inp,
+				PorterYamlPaths: porterYAMLs,
+			})
+		}
+
 		err = v2.Apply(ctx, inp)
 		if err != nil {
 			return err
@@ -217,6 +267,10 @@ func apply(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client ap
 		return nil
 	}
 
+	if isMultiAppApply() {
+		return fmt.Errorf("applying multiple apps is only supported for projects using porter.yaml v2")
+	}
+
 	fileBytes, err := os.ReadFile(porterYAML) //nolint:errcheck,gosec // do not want to change logic of CLI. New linter error
 	if err != nil && appName == "" {
 		return fmt.Errorf("a valid porter.yaml file must be specified. Run porter apply --help for more information")
@@ -248,9 +302,8 @@ func apply(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client ap
 		}
 	} else if previewVersion.Version == "v1" {
 		if _, ok := os.LookupEnv("PORTER_VALIDATE_YAML"); ok {
-			err := applyValidate()
-			if err != nil {
-				return err
+			if validationErrors := validatePorterYAML(ctx, porterYAML); len(validationErrors) > 0 {
+				return fmt.Errorf("the following error(s) were found while validating the porter.yaml file:\n%s", formatValidationErrors(porterYAML, validationErrors))
 			}
 		}
 
@@ -391,25 +444,70 @@ func apply(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client ap
 	return
 }
 
-func applyValidate() error {
-	fileBytes, err := ioutil.ReadFile(porterYAML)
+// applyValidate validates every porter.yaml given with the --file flag, including the porter.yaml files in directories, and
+// returns the number of files which were validated
+func applyValidate(ctx context.Context) (int, error) {
+	if len(porterYAMLs) == 0 {
+		return 0, fmt.Errorf("a porter.yaml file must be specified with --file")
+	}
+
+	paths, err := v2.PorterYamlPaths(ctx, porterYAMLs)
 	if err != nil {
-		return fmt.Errorf("error reading porter.yaml: %w", err)
+		return 0, err
 	}
 
-	validationErrors := previewInt.Validate(string(fileBytes))
+	if len(paths) == 0 {
+		return 0, fmt.Errorf("no porter.yaml files found")
+	}
 
-	if len(validationErrors) > 0 {
-		errString := "the following error(s) were found while validating the porter.yaml file:"
+	var invalid []string
 
-		for _, err := range validationErrors {
-			errString += "\n- " + strings.ReplaceAll(err.Error(), "\n\n*", "\n  *")
+	for _, path := range paths {
+		validationErrors := validatePorterYAML(ctx, path)
+		if len(validationErrors) == 0 {
+			continue
 		}
 
-		return fmt.Errorf(errString)
+		invalid = append(invalid, formatValidationErrors(path, validationErrors))
 	}
 
-	return nil
+	if len(invalid) > 0 {
+		return len(paths), fmt.Errorf("the following error(s) were found while validating %d of %d porter.yaml files:\n%s", len(invalid), len(paths), strings.Join(invalid, "\n"))
+	}
+
+	return len(paths), nil
+}
+
+// formatValidationErrors lists the validation errors of a porter.yaml under its path
+func formatValidationErrors(path string, validationErrors []error) string {
+	errString := fmt.Sprintf("%s:", path)
+	for _, err := range validationErrors {
+		errString += "\n- " + strings.ReplaceAll(err.Error(), "\n\n*", "\n  *")
+	}
+
+	return errString
+}
+
+// validatePorterYAML returns the errors in a porter.yaml, which is validated according to its version
+func validatePorterYAML(ctx context.Context, path string) []error {
+	fileBytes, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return []error{fmt.Errorf("error reading porter.yaml: %w", err)}
+	}
+
+	var version struct {
+		Version string `yaml:"version"`
+	}
+
+	if err := yaml.Unmarshal(fileBytes, &version); err != nil {
+		return []error{fmt.Errorf("error unmarshaling porter.yaml: %w", err)}
+	}
+
+	if version.Version == "v2" {
+		return v2.ValidatePorterYaml(ctx, fileBytes)
+	}
+
+	return previewInt.Validate(string(fileBytes))
 }
 
 func hasDeploymentHookEnvVars() bool {
@@ -216,7 +216,27 @@ func loginManual(ctx context.Context, cliConf config.CLIConfig, client api.Clien
 		Password: pw,
 	})
 	if err != nil {
-		return err
+		if !errors.Is(err, api.ErrMFARequired) {
+			return err
+		}
+
+		code, err := utils.PromptPlaintext("Authentication code (or recovery code): ")
+		if err != nil {
+			return err
+		}
+
+		req := &types.LoginMFARequest{}
+		code = strings.TrimSpace(code)
+
+		if len(code) == 6 {
+			req.Code = code
+		} else {
+			req.RecoveryCode = code
+		}
+
+		if _, err := client.LoginMFA(ctx, req); err != nil {
+			return err
+		}
 	}
 
 	// set the token to empty since this is manual (cookie-based) login
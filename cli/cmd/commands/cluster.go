@@ -7,15 +7,25 @@ import (
 	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/fatih/color"
 	api "github.com/porter-dev/porter/api/client"
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/cli/cmd/config"
 	"github.com/porter-dev/porter/cli/cmd/utils"
+	v2 "github.com/porter-dev/porter/cli/cmd/v2"
 	"github.com/spf13/cobra"
 )
 
+var (
+	clusterCapacitySimulate string
+	clusterCapacityTarget   string
+	clusterNodeForce        bool
+	clusterNodeSelector     string
+	clusterNodeTimeout      time.Duration
+)
+
 func registerCommand_Cluster(cliConf config.CLIConfig) *cobra.Command {
 	clusterCmd := &cobra.Command{
 		Use:     "cluster",
@@ -67,9 +77,142 @@ func registerCommand_Cluster(cliConf config.CLIConfig) *cobra.Command {
 	}
 	clusterNamespaceCmd.AddCommand(clusterNamespaceListCmd)
 
+	clusterCapacityCmd := &cobra.Command{
+		Use:   "capacity",
+		Args:  cobra.NoArgs,
+		Short: "Simulates whether the services of a porter.yaml fit onto the nodes of the cluster",
+		Long: fmt.Sprintf(`%s
+
+Simulates scheduling the web and worker services of a porter.yaml onto the current nodes of
+the cluster, replacing the pods of the app if it is already deployed. Autoscaled services are
+simulated at their maximum instances. Node selectors and taints are taken into account, and
+the command reports the pods which would not fit, how many nodes of each instance type must be
+added to fit them, and the projected utilization of the cluster. For example:
+
+  %s
+
+The command exits with an error if any pod would not fit onto the current nodes.
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter cluster capacity\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter cluster capacity --simulate porter.yaml"),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, clusterCapacity)
+		},
+	}
+
+	clusterCapacityCmd.Flags().StringVar(&clusterCapacitySimulate, "simulate", "", "the path to the porter.yaml to simulate")
+	clusterCapacityCmd.Flags().StringVarP(&clusterCapacityTarget, "target", "x", "", "the name of the deployment target the app is applied to")
+
+	clusterCmd.AddCommand(clusterCapacityCmd)
+
+	clusterNodeCmd := &cobra.Command{
+		Use:     "node",
+		Aliases: []string{"nodes"},
+		Short:   "Commands that perform maintenance on the nodes of a cluster",
+	}
+	clusterCmd.AddCommand(clusterNodeCmd)
+
+	clusterNodeCordonCmd := &cobra.Command{
+		Use:   "cordon [node]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Stops new pods from being scheduled onto a node",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, clusterNodeMaintenance(v2.CordonNode))
+		},
+	}
+	clusterNodeCmd.AddCommand(clusterNodeCordonCmd)
+
+	clusterNodeUncordonCmd := &cobra.Command{
+		Use:   "uncordon [node]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Allows new pods to be scheduled onto a node again",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, clusterNodeMaintenance(v2.UncordonNode))
+		},
+	}
+	clusterNodeCmd.AddCommand(clusterNodeUncordonCmd)
+
+	clusterNodeDrainCmd := &cobra.Command{
+		Use:   "drain [node]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Cordons a node and evicts its pods, respecting PodDisruptionBudgets",
+		Long: fmt.Sprintf(`%s
+
+Cordons a node and evicts its pods. Evictions which would violate a PodDisruptionBudget are
+retried until the budget allows them or the drain times out. Daemon set pods are left on the
+node, and pods which are not managed by a controller are only evicted with --force, since they
+are not recreated on another node. For example:
+
+  %s
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter cluster node drain\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter cluster node drain ip-10-0-1-23.ec2.internal --timeout 15m"),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, clusterNodeMaintenance(v2.DrainNode))
+		},
+	}
+	clusterNodeCmd.AddCommand(clusterNodeDrainCmd)
+
+	clusterNodeRotateCmd := &cobra.Command{
+		Use:   "rotate [nodes...]",
+		Short: "Drains nodes one at a time, waiting for app pods to be ready elsewhere in between",
+		Long: fmt.Sprintf(`%s
+
+Cordons and drains the given nodes one at a time. After each node is drained, the app pods
+which were evicted from it must be ready on other nodes before the next node is drained.
+Drained nodes are left cordoned so that they can be removed from their node group. If a
+drain fails or is interrupted, the node being drained is uncordoned again.
+Nodes are given by name, or selected by label. For example:
+
+  %s
+  %s
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter cluster node rotate\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter cluster node rotate node-1 node-2"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter cluster node rotate --selector porter.run/workload-kind=application"),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, clusterNodeMaintenance(v2.RotateNodes))
+		},
+	}
+	clusterNodeRotateCmd.Flags().StringVarP(&clusterNodeSelector, "selector", "l", "", "a label selector for the nodes to rotate")
+	clusterNodeCmd.AddCommand(clusterNodeRotateCmd)
+
+	for _, cmd := range []*cobra.Command{clusterNodeDrainCmd, clusterNodeRotateCmd} {
+		cmd.Flags().DurationVar(&clusterNodeTimeout, "timeout", 0, "how long a drain, or each node of a rotation, may take (default 10m)")
+		cmd.Flags().BoolVar(&clusterNodeForce, "force", false, "evict pods which are not managed by a controller")
+	}
+
 	return clusterCmd
 }
 
+// clusterNodeMaintenance returns a command which runs a node maintenance operation on the nodes given as arguments
+func clusterNodeMaintenance(
+	operation func(context.Context, v2.NodeMaintenanceInput) error,
+) func(context.Context, *types.GetAuthenticatedUserResponse, api.Client, config.CLIConfig, config.FeatureFlags, *cobra.Command, []string) error {
+	return func(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, args []string) error {
+		return operation(ctx, v2.NodeMaintenanceInput{
+			CLIConfig:    cliConf,
+			Client:       client,
+			NodeNames:    args,
+			NodeSelector: clusterNodeSelector,
+			Timeout:      clusterNodeTimeout,
+			Force:        clusterNodeForce,
+		})
+	}
+}
+
+func clusterCapacity(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, _ []string) error {
+	return v2.ClusterCapacity(ctx, v2.ClusterCapacityInput{
+		CLIConfig:            cliConf,
+		Client:               client,
+		PorterYamlPath:       clusterCapacitySimulate,
+		DeploymentTargetName: clusterCapacityTarget,
+	})
+}
+
 func listClusters(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
 	resp, err := client.ListProjectClusters(ctx, cliConf.Project)
 	if err != nil {
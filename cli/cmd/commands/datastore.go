@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"text/tabwriter"
 	"time"
 
 	"github.com/briandowns/spinner"
@@ -21,7 +22,15 @@ import (
 	"k8s.io/client-go/transport/spdy"
 )
 
-var port int
+var (
+	port int
+
+	datastoreRestoreName        string
+	datastoreRestoreSnapshotID  string
+	datastoreRestorePointInTime string
+	datastoreCloneName          string
+	datastoreCloneTarget        string
+)
 
 const (
 	// Address_Localhost is the localhost address
@@ -56,6 +65,89 @@ func registerCommand_Datastore(cliConf config.CLIConfig) *cobra.Command {
 
 	datastoreCmd.AddCommand(datastoreConnectCmd)
 
+	datastoreBackupCmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Manages snapshots of a datastore.",
+	}
+
+	datastoreBackupListCmd := &cobra.Command{
+		Use:   "list <DATASTORE_NAME>",
+		Short: "Lists the snapshots of a datastore and the range of times it can be restored to.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkLoginAndRunWithConfig(cmd, cliConf, args, datastoreBackupList)
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+
+	datastoreBackupCreateCmd := &cobra.Command{
+		Use:   "create <DATASTORE_NAME>",
+		Short: "Triggers an on-demand snapshot of a datastore.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkLoginAndRunWithConfig(cmd, cliConf, args, datastoreBackupCreate)
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+
+	datastoreBackupCmd.AddCommand(datastoreBackupListCmd)
+	datastoreBackupCmd.AddCommand(datastoreBackupCreateCmd)
+	datastoreCmd.AddCommand(datastoreBackupCmd)
+
+	datastoreRestoreCmd := &cobra.Command{
+		Use:   "restore <DATASTORE_NAME>",
+		Short: "Restores a snapshot or point in time of a datastore into a new datastore.",
+		Long: fmt.Sprintf(`%s
+
+Restores a datastore into a new datastore, leaving the original datastore untouched. Either a
+snapshot id or a point in time must be provided. For example:
+
+  %s
+
+  %s
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter datastore restore\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter datastore restore my-db --name my-db-restored --snapshot my-db-1a2b3c4d"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter datastore restore my-db --name my-db-restored --point-in-time 2024-01-02T15:04:05Z"),
+		),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkLoginAndRunWithConfig(cmd, cliConf, args, datastoreRestore)
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+
+	datastoreRestoreCmd.Flags().StringVar(&datastoreRestoreName, "name", "", "the name of the new datastore")
+	datastoreRestoreCmd.Flags().StringVar(&datastoreRestoreSnapshotID, "snapshot", "", "the id of the snapshot to restore from")
+	datastoreRestoreCmd.Flags().StringVar(&datastoreRestorePointInTime, "point-in-time", "", "the time to restore to, in RFC 3339 format")
+	datastoreRestoreCmd.MarkFlagRequired("name") // nolint:errcheck,gosec
+
+	datastoreCmd.AddCommand(datastoreRestoreCmd)
+
+	datastoreCloneCmd := &cobra.Command{
+		Use:   "clone <DATASTORE_NAME>",
+		Short: "Clones a datastore into a new datastore for a preview environment.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkLoginAndRunWithConfig(cmd, cliConf, args, datastoreClone)
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+
+	datastoreCloneCmd.Flags().StringVar(&datastoreCloneTarget, "target", "", "the id or name of the preview environment to clone into")
+	datastoreCloneCmd.Flags().StringVar(&datastoreCloneName, "name", "", "the name of the new datastore (defaults to <DATASTORE_NAME>-<target>)")
+	datastoreCloneCmd.MarkFlagRequired("target") // nolint:errcheck,gosec
+
+	datastoreCmd.AddCommand(datastoreCloneCmd)
+
 	return datastoreCmd
 }
 
@@ -193,3 +285,95 @@ func printDatastoreConnectionInformation(datastoreType string, port int, credent
 	}
 	fmt.Println()
 }
+
+func datastoreBackupList(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, ff config.FeatureFlags, _ *cobra.Command, args []string) error {
+	if cliConf.Project == 0 {
+		return fmt.Errorf("project not set; please select a project with porter config set-project and try again")
+	}
+
+	resp, err := client.ListDatastoreSnapshots(ctx, cliConf.Project, args[0])
+	if err != nil {
+		return fmt.Errorf("could not list snapshots: %w", err)
+	}
+
+	if resp.RestoreWindow != nil {
+		fmt.Printf("Restorable from %s to %s\n\n", resp.RestoreWindow.Earliest.Format(time.RFC3339), resp.RestoreWindow.Latest.Format(time.RFC3339))
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 3, 8, 0, '\t', tabwriter.AlignRight)
+
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "ID", "TYPE", "STATUS", "CREATED") // nolint:errcheck,gosec
+
+	for _, snapshot := range resp.Snapshots {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", snapshot.ID, snapshot.Type, snapshot.Status, snapshot.CreatedAt.Format(time.RFC3339)) // nolint:errcheck,gosec
+	}
+
+	return w.Flush()
+}
+
+func datastoreBackupCreate(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, ff config.FeatureFlags, _ *cobra.Command, args []string) error {
+	if cliConf.Project == 0 {
+		return fmt.Errorf("project not set; please select a project with porter config set-project and try again")
+	}
+
+	resp, err := client.CreateDatastoreSnapshot(ctx, cliConf.Project, args[0])
+	if err != nil {
+		return fmt.Errorf("could not create snapshot: %w", err)
+	}
+
+	color.New(color.FgGreen).Printf("Triggered snapshot %s of datastore %s (status: %s)\n", resp.Snapshot.ID, args[0], resp.Snapshot.Status) // nolint:errcheck,gosec
+
+	return nil
+}
+
+func datastoreRestore(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, ff config.FeatureFlags, _ *cobra.Command, args []string) error {
+	if cliConf.Project == 0 {
+		return fmt.Errorf("project not set; please select a project with porter config set-project and try again")
+	}
+
+	if (datastoreRestoreSnapshotID == "") == (datastoreRestorePointInTime == "") {
+		return fmt.Errorf("exactly one of --snapshot and --point-in-time must be provided")
+	}
+
+	req := &types.RestoreDatastoreRequest{
+		Name:       datastoreRestoreName,
+		SnapshotID: datastoreRestoreSnapshotID,
+	}
+
+	if datastoreRestorePointInTime != "" {
+		pointInTime, err := time.Parse(time.RFC3339, datastoreRestorePointInTime)
+		if err != nil {
+			return fmt.Errorf("invalid --point-in-time: %w", err)
+		}
+
+		req.PointInTime = &pointInTime
+	}
+
+	resp, err := client.RestoreDatastore(ctx, cliConf.Project, args[0], req)
+	if err != nil {
+		return fmt.Errorf("could not restore datastore: %w", err)
+	}
+
+	color.New(color.FgGreen).Printf("Restoring datastore %s into new datastore %s\n", args[0], resp.Name) // nolint:errcheck,gosec
+
+	return nil
+}
+
+func datastoreClone(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, ff config.FeatureFlags, _ *cobra.Command, args []string) error {
+	if cliConf.Project == 0 {
+		return fmt.Errorf("project not set; please select a project with porter config set-project and try again")
+	}
+
+	resp, err := client.CloneDatastore(ctx, cliConf.Project, args[0], &types.CloneDatastoreRequest{
+		DeploymentTargetID: datastoreCloneTarget,
+		Name:               datastoreCloneName,
+	})
+	if err != nil {
+		return fmt.Errorf("could not clone datastore: %w", err)
+	}
+
+	color.New(color.FgGreen).Printf("Cloning datastore %s into new datastore %s for preview environment %s\n", args[0], resp.Name, resp.DeploymentTargetID) // nolint:errcheck,gosec
+
+	return nil
+}
@@ -109,6 +109,10 @@ When updating an environment group, all apps linked to the environment group wil
 	envCmd.AddCommand(setCommand)
 	envCmd.AddCommand(unsetCommand)
 
+	registerEnvGroupHistoryCommands(envCmd, cliConf)
+	registerEnvGroupImportCommands(envCmd, cliConf)
+	registerEnvGroupRotationCommands(envCmd, cliConf)
+
 	return envCmd
 }
 
@@ -0,0 +1,186 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/server/handlers/environment_groups"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/cli/cmd/config"
+	environmentgroups "github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/spf13/cobra"
+)
+
+// registerEnvGroupHistoryCommands adds the commands which inspect and restore versions of an environment group. The group is passed
+// as an argument, so these commands do not require the --app or --group flags of the other env commands.
+func registerEnvGroupHistoryCommands(envCmd *cobra.Command, cliConf config.CLIConfig) {
+	skipAppOrGroupCheck := func(cmd *cobra.Command, args []string) error {
+		return nil
+	}
+
+	historyCommand := &cobra.Command{
+		Use:               "history [group]",
+		Short:             "List the versions of an environment group",
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: skipAppOrGroupCheck,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, envGroupHistory)
+		},
+	}
+
+	diffCommand := &cobra.Command{
+		Use:   "diff [group]",
+		Short: "Show the keys which changed between two versions of an environment group",
+		Long: `Show the keys which changed between two versions of an environment group.
+
+By default, the latest version is compared with the version before it. Secret values are masked unless --show-secrets is used.`,
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: skipAppOrGroupCheck,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, envGroupDiff)
+		},
+	}
+	diffCommand.Flags().Int("from", 0, "the older version to compare (defaults to the version before --to)")
+	diffCommand.Flags().Int("to", 0, "the newer version to compare (defaults to the latest version)")
+	diffCommand.Flags().Bool("show-secrets", false, "show the values of secrets and files")
+
+	rollbackCommand := &cobra.Command{
+		Use:   "rollback [group]",
+		Short: "Roll an environment group back to an earlier version",
+		Long: `Roll an environment group back to an earlier version.
+
+The rollback creates a new version with the values of the earlier version, so it can itself be rolled back.
+Apps linked to the environment group keep their current values unless --redeploy is used.`,
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: skipAppOrGroupCheck,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, envGroupRollback)
+		},
+	}
+	rollbackCommand.Flags().Int("to", 0, "the version to restore")
+	rollbackCommand.Flags().Bool("redeploy", false, "re-deploy apps linked to the environment group after the rollback")
+	_ = rollbackCommand.MarkFlagRequired("to")
+
+	envCmd.AddCommand(historyCommand)
+	envCmd.AddCommand(diffCommand)
+	envCmd.AddCommand(rollbackCommand)
+}
+
+func envGroupHistory(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	resp, err := client.ListEnvGroupVersions(ctx, cliConf.Project, cliConf.Cluster, args[0])
+	if err != nil {
+		return fmt.Errorf("could not list env group versions: %w", err)
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 3, 8, 2, ' ', 0)
+
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "VERSION", "CREATED", "AUTHOR", "KEYS") // nolint:errcheck,gosec
+
+	for _, version := range resp.Versions {
+		author := version.Author
+		if author == "" {
+			author = "-"
+		}
+
+		keys := len(version.VariableKeys) + len(version.SecretKeys) + len(version.FileNames)
+		line := fmt.Sprintf("%d\t%s\t%s\t%d\n", version.Version, version.CreatedAt.Local().Format(time.RFC3339), author, keys)
+
+		if version.IsLatest {
+			color.New(color.FgGreen).Fprint(w, strings.TrimSuffix(line, "\n")+" (latest)\n") // nolint:errcheck,gosec
+			continue
+		}
+		fmt.Fprint(w, line) // nolint:errcheck,gosec
+	}
+
+	return w.Flush()
+}
+
+func envGroupDiff(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	fromVersion, err := cmd.Flags().GetInt("from")
+	if err != nil {
+		return fmt.Errorf("could not get from: %w", err)
+	}
+
+	toVersion, err := cmd.Flags().GetInt("to")
+	if err != nil {
+		return fmt.Errorf("could not get to: %w", err)
+	}
+
+	showSecrets, err := cmd.Flags().GetBool("show-secrets")
+	if err != nil {
+		return fmt.Errorf("could not get show-secrets: %w", err)
+	}
+
+	resp, err := client.DiffEnvGroupVersions(ctx, cliConf.Project, cliConf.Cluster, args[0], &environment_groups.DiffEnvGroupVersionsRequest{
+		FromVersion:   fromVersion,
+		ToVersion:     toVersion,
+		RevealSecrets: showSecrets,
+	})
+	if err != nil {
+		return fmt.Errorf("could not diff env group versions: %w", err)
+	}
+
+	fmt.Printf("Changes in environment group %s from version %d to version %d:\n", args[0], resp.FromVersion, resp.ToVersion) // nolint:errcheck,gosec
+
+	printEnvGroupChanges(resp.Changes)
+
+	return nil
+}
+
+// printEnvGroupChanges prints the keys which changed between two versions of an environment group
+func printEnvGroupChanges(changes []environmentgroups.EnvironmentGroupKeyDiff) {
+	if len(changes) == 0 {
+		fmt.Println("No changes") // nolint:errcheck,gosec
+		return
+	}
+
+	for _, change := range changes {
+		key := change.Key
+		if change.Kind != environmentgroups.EnvironmentGroupKeyKind_Variable {
+			key = fmt.Sprintf("%s (%s)", change.Key, change.Kind)
+		}
+
+		switch change.Change {
+		case environmentgroups.EnvironmentGroupKeyChange_Added:
+			color.New(color.FgGreen).Printf("+ %s=%s\n", key, change.NewValue) // nolint:errcheck,gosec
+		case environmentgroups.EnvironmentGroupKeyChange_Removed:
+			color.New(color.FgRed).Printf("- %s=%s\n", key, change.OldValue) // nolint:errcheck,gosec
+		case environmentgroups.EnvironmentGroupKeyChange_Changed:
+			color.New(color.FgYellow).Printf("~ %s: %s -> %s\n", key, change.OldValue, change.NewValue) // nolint:errcheck,gosec
+		}
+	}
+}
+
+func envGroupRollback(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	version, err := cmd.Flags().GetInt("to")
+	if err != nil {
+		return fmt.Errorf("could not get to: %w", err)
+	}
+
+	redeploy, err := cmd.Flags().GetBool("redeploy")
+	if err != nil {
+		return fmt.Errorf("could not get redeploy: %w", err)
+	}
+
+	resp, err := client.RollbackEnvGroup(ctx, cliConf.Project, cliConf.Cluster, args[0], &environment_groups.RollbackEnvGroupRequest{
+		Version:            version,
+		RedeployLinkedApps: redeploy,
+	})
+	if err != nil {
+		return fmt.Errorf("could not roll back env group: %w", err)
+	}
+
+	color.New(color.FgGreen).Printf("Rolled back environment group %s to version %d as version %d\n", args[0], resp.RestoredVersion, resp.Version) // nolint:errcheck,gosec
+	if redeploy {
+		color.New(color.FgGreen).Println("Re-deploying linked apps") // nolint:errcheck,gosec
+	}
+
+	return nil
+}
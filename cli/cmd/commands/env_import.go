@@ -0,0 +1,329 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/server/handlers/environment_groups"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/cli/cmd/config"
+	environmentgroups "github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// registerEnvGroupImportCommands adds the commands which import an environment group from a file and export it to a file. Like the history
+// commands, the group is passed as an argument rather than with the --group flag.
+func registerEnvGroupImportCommands(envCmd *cobra.Command, cliConf config.CLIConfig) {
+	skipAppOrGroupCheck := func(cmd *cobra.Command, args []string) error {
+		return nil
+	}
+
+	importCommand := &cobra.Command{
+		Use:   "import [group]",
+		Short: "Import variables, secrets and files into an environment group from a file",
+		Long: `Import variables, secrets and files into an environment group from a dotenv, json or yaml file.
+
+The environment group is created if it does not exist. The format is inferred from the file extension unless --format is used.
+In dotenv files, keys after a "# porter:secrets" line are imported as secrets. Json and yaml files contain "variables", "secrets"
+and "files" fields, or a flat object of keys and values. Use --secrets to import every key of a dotenv file or flat object as a secret.
+
+Files encrypted with SOPS are decrypted with the sops binary before they are uploaded, using the keys sops is configured with
+(for example, SOPS_AGE_KEY_FILE).
+
+Secrets and files whose value is masked ("********"), as in files exported without --include-secrets, keep their current values.
+
+The --mode flag controls how imported values are combined with existing values:
+  add        only add keys which do not exist yet
+  overwrite  add new keys and replace the values of existing keys (default)
+  prune      make the environment group equal to the file, removing keys which are not in it
+
+When updating an environment group, all apps linked to the environment group will be re-deployed, unless the --skip-redeploys flag is used.`,
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: skipAppOrGroupCheck,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, envGroupImport)
+		},
+	}
+	importCommand.Flags().StringP("file", "f", "", "the file to import, or - to read from stdin")
+	importCommand.Flags().String("format", "", "the format of the file: dotenv, json or yaml")
+	importCommand.Flags().String("mode", string(environmentgroups.EnvImportMode_Overwrite), "how imported values are combined with existing values: add, overwrite or prune")
+	importCommand.Flags().Bool("secrets", false, "import every key of a dotenv file or flat json or yaml object as a secret")
+	importCommand.Flags().Bool("dry-run", false, "show the changes the import would make without making them")
+	importCommand.Flags().Bool("skip-redeploys", false, "skip re-deploying apps linked to the environment group")
+	_ = importCommand.MarkFlagRequired("file")
+
+	exportCommand := &cobra.Command{
+		Use:   "export [group]",
+		Short: "Export the variables, secrets and files of an environment group to a file",
+		Long: `Export the variables, secrets and files of an environment group as a dotenv, json or yaml file.
+
+Secret values are masked unless --include-secrets is used. Masked values keep their current values when the file is imported again.
+Use --sops-age to encrypt the file with SOPS for an age recipient,
+so that it can be committed and imported later with "porter env import".`,
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: skipAppOrGroupCheck,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, envGroupExport)
+		},
+	}
+	exportCommand.Flags().StringP("file", "f", "", "the file to write to (defaults to stdout)")
+	exportCommand.Flags().String("format", "", "the format of the file: dotenv, json or yaml (defaults to the file extension, or dotenv)")
+	exportCommand.Flags().Int("version", 0, "the version to export (defaults to the latest version)")
+	exportCommand.Flags().Bool("include-secrets", false, "include the values of secrets and files")
+	exportCommand.Flags().String("sops-age", "", "encrypt the file with SOPS for this age recipient")
+
+	envCmd.AddCommand(importCommand)
+	envCmd.AddCommand(exportCommand)
+}
+
+func envGroupImport(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	file, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return fmt.Errorf("could not get file: %w", err)
+	}
+
+	formatFlag, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return fmt.Errorf("could not get format: %w", err)
+	}
+
+	mode, err := cmd.Flags().GetString("mode")
+	if err != nil {
+		return fmt.Errorf("could not get mode: %w", err)
+	}
+
+	allSecrets, err := cmd.Flags().GetBool("secrets")
+	if err != nil {
+		return fmt.Errorf("could not get secrets: %w", err)
+	}
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return fmt.Errorf("could not get dry-run: %w", err)
+	}
+
+	skipRedeploys, err := cmd.Flags().GetBool("skip-redeploys")
+	if err != nil {
+		return fmt.Errorf("could not get skip-redeploys: %w", err)
+	}
+
+	format, err := envFileFormat(formatFlag, file)
+	if err != nil {
+		return err
+	}
+
+	var contents []byte
+	if file == "-" {
+		contents, err = io.ReadAll(os.Stdin)
+	} else {
+		contents, err = os.ReadFile(filepath.Clean(file))
+	}
+	if err != nil {
+		return fmt.Errorf("could not read env file: %w", err)
+	}
+
+	if isSOPSEncrypted(contents, format) {
+		contents, err = sopsDecrypt(ctx, contents, format)
+		if err != nil {
+			return err
+		}
+	}
+
+	resp, err := client.ImportEnvGroup(ctx, cliConf.Project, cliConf.Cluster, args[0], &environment_groups.ImportEnvGroupRequest{
+		Format:            format,
+		Content:           string(contents),
+		Mode:              environmentgroups.EnvImportMode(mode),
+		AllSecrets:        allSecrets,
+		DryRun:            dryRun,
+		SkipAppAutoDeploy: skipRedeploys,
+	})
+	if err != nil {
+		return fmt.Errorf("could not import env group: %w", err)
+	}
+
+	if resp.DryRun {
+		fmt.Printf("Changes which importing %s would make to environment group %s:\n", file, args[0]) // nolint:errcheck,gosec
+		printEnvGroupChanges(resp.Changes)
+		return nil
+	}
+
+	printEnvGroupChanges(resp.Changes)
+	if len(resp.Changes) == 0 {
+		return nil
+	}
+
+	color.New(color.FgGreen).Printf("Imported %s into environment group %s as version %d\n", file, args[0], resp.Version) // nolint:errcheck,gosec
+
+	return nil
+}
+
+func envGroupExport(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	file, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return fmt.Errorf("could not get file: %w", err)
+	}
+
+	formatFlag, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return fmt.Errorf("could not get format: %w", err)
+	}
+
+	version, err := cmd.Flags().GetInt("version")
+	if err != nil {
+		return fmt.Errorf("could not get version: %w", err)
+	}
+
+	includeSecrets, err := cmd.Flags().GetBool("include-secrets")
+	if err != nil {
+		return fmt.Errorf("could not get include-secrets: %w", err)
+	}
+
+	ageRecipient, err := cmd.Flags().GetString("sops-age")
+	if err != nil {
+		return fmt.Errorf("could not get sops-age: %w", err)
+	}
+
+	format := environmentgroups.EnvFileFormat_Dotenv
+	if formatFlag != "" || (file != "" && file != "-") {
+		format, err = envFileFormat(formatFlag, file)
+		if err != nil {
+			return err
+		}
+	}
+
+	resp, err := client.ExportEnvGroup(ctx, cliConf.Project, cliConf.Cluster, args[0], &environment_groups.ExportEnvGroupRequest{
+		Format:         format,
+		Version:        version,
+		IncludeSecrets: includeSecrets,
+	})
+	if err != nil {
+		return fmt.Errorf("could not export env group: %w", err)
+	}
+
+	contents := []byte(resp.Content)
+	if ageRecipient != "" {
+		contents, err = sopsEncrypt(ctx, contents, format, ageRecipient)
+		if err != nil {
+			return err
+		}
+	}
+
+	if file == "" || file == "-" {
+		_, err = os.Stdout.Write(contents)
+		return err
+	}
+
+	err = os.WriteFile(filepath.Clean(file), contents, 0o600)
+	if err != nil {
+		return fmt.Errorf("could not write env file: %w", err)
+	}
+
+	color.New(color.FgGreen).Fprintf(os.Stderr, "Exported version %d of environment group %s to %s\n", resp.Version, args[0], file) // nolint:errcheck,gosec
+	if !includeSecrets {
+		color.New(color.FgYellow).Fprintln(os.Stderr, "Secret values are masked, use --include-secrets to export them") // nolint:errcheck,gosec
+	}
+
+	return nil
+}
+
+// envFileFormat returns the format set by the --format flag, or infers it from the extension of the file
+func envFileFormat(formatFlag string, file string) (environmentgroups.EnvFileFormat, error) {
+	if formatFlag != "" {
+		switch format := environmentgroups.EnvFileFormat(strings.ToLower(formatFlag)); format {
+		case environmentgroups.EnvFileFormat_Dotenv, environmentgroups.EnvFileFormat_JSON, environmentgroups.EnvFileFormat_YAML:
+			return format, nil
+		default:
+			return "", fmt.Errorf("unsupported format %q, must be one of dotenv, json or yaml", formatFlag)
+		}
+	}
+
+	base := strings.ToLower(filepath.Base(file))
+	switch {
+	case strings.HasSuffix(base, ".json"):
+		return environmentgroups.EnvFileFormat_JSON, nil
+	case strings.HasSuffix(base, ".yaml"), strings.HasSuffix(base, ".yml"):
+		return environmentgroups.EnvFileFormat_YAML, nil
+	case strings.HasSuffix(base, ".env"), strings.HasPrefix(base, ".env"):
+		return environmentgroups.EnvFileFormat_Dotenv, nil
+	}
+
+	return "", errors.New("could not infer the format of the file from its name, please set --format")
+}
+
+// isSOPSEncrypted returns true if an env file was encrypted by SOPS, which stores its metadata under a top-level sops key, or in sops_
+// prefixed keys for dotenv files
+func isSOPSEncrypted(contents []byte, format environmentgroups.EnvFileFormat) bool {
+	if format == environmentgroups.EnvFileFormat_Dotenv {
+		scanner := bufio.NewScanner(bytes.NewReader(contents))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if strings.HasPrefix(line, "sops_mac=") || strings.HasPrefix(line, "sops_version=") {
+				return true
+			}
+		}
+		return false
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(contents, &doc); err != nil {
+		return false
+	}
+
+	_, ok := doc["sops"]
+	return ok
+}
+
+// sopsDecrypt decrypts an env file with the sops binary
+func sopsDecrypt(ctx context.Context, contents []byte, format environmentgroups.EnvFileFormat) ([]byte, error) {
+	return runSOPS(ctx, contents, format, "--decrypt")
+}
+
+// sopsEncrypt encrypts an env file for an age recipient with the sops binary
+func sopsEncrypt(ctx context.Context, contents []byte, format environmentgroups.EnvFileFormat, ageRecipient string) ([]byte, error) {
+	return runSOPS(ctx, contents, format, "--encrypt", "--age", ageRecipient)
+}
+
+// runSOPS runs sops on a temporary copy of an env file, since not every version of sops can read from stdin
+func runSOPS(ctx context.Context, contents []byte, format environmentgroups.EnvFileFormat, sopsArgs ...string) ([]byte, error) {
+	if _, err := exec.LookPath("sops"); err != nil {
+		return nil, errors.New("the sops binary is required to encrypt and decrypt env files, see https://github.com/getsops/sops")
+	}
+
+	tmp, err := os.CreateTemp("", "porter-env-*")
+	if err != nil {
+		return nil, fmt.Errorf("could not create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // nolint:errcheck
+
+	_, err = tmp.Write(contents)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not write temporary file: %w", err)
+	}
+
+	sopsArgs = append(sopsArgs, "--input-type", string(format), "--output-type", string(format), tmp.Name())
+
+	var stdout, stderr bytes.Buffer
+	sopsCmd := exec.CommandContext(ctx, "sops", sopsArgs...) // nolint:gosec
+	sopsCmd.Stdout = &stdout
+	sopsCmd.Stderr = &stderr
+
+	if err := sopsCmd.Run(); err != nil {
+		return nil, fmt.Errorf("sops %s failed: %w: %s", sopsArgs[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,309 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/cli/cmd/config"
+	"github.com/spf13/cobra"
+)
+
+// registerEnvGroupRotationCommands adds the commands which manage the rotation policies of the keys of an environment group. Like the
+// history commands, the group is passed as an argument rather than with the --group flag.
+func registerEnvGroupRotationCommands(envCmd *cobra.Command, cliConf config.CLIConfig) {
+	skipAppOrGroupCheck := func(cmd *cobra.Command, args []string) error {
+		return nil
+	}
+
+	rotationCommand := &cobra.Command{
+		Use:   "rotation",
+		Short: "Manage scheduled rotation of environment group secrets",
+		Long: `Manage scheduled rotation of environment group secrets.
+
+A rotation policy rotates a key of an environment group on an interval. The new value is either a random string from the
+built-in generator, or the value written by a rotation job which runs in the cluster. Rotation jobs are passed the
+environment group name and key as PORTER_ENV_GROUP_NAME and PORTER_ENV_GROUP_KEY, and should change the credential (for
+example, a database password) before writing its new value to the file in PORTER_ROTATION_OUTPUT_FILE
+(/dev/termination-log). Values are never read from job logs, so rotation jobs should not print them.
+
+Each rotation creates a new version of the environment group, after which the apps linked to it are re-deployed. Rotated
+keys are always stored as secrets. Failed rotations are retried, and are notified to the project's Slack channels.`,
+		PersistentPreRunE: skipAppOrGroupCheck,
+	}
+
+	listCommand := &cobra.Command{
+		Use:               "list [group]",
+		Short:             "List the rotation policies of an environment group",
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: skipAppOrGroupCheck,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, envGroupRotationList)
+		},
+	}
+
+	setCommand := &cobra.Command{
+		Use:   "set [group]",
+		Short: "Create or update the rotation policy of a key of an environment group",
+		Long: `Create or update the rotation policy of a key of an environment group.
+
+Use --job-image to rotate the key with a job, and otherwise the built-in generator is used. The key is first rotated one
+interval after its policy is created; use "porter env rotation run" to rotate it now.`,
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: skipAppOrGroupCheck,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, envGroupRotationSet)
+		},
+	}
+	setCommand.Flags().String("key", "", "the key to rotate")
+	setCommand.Flags().Duration("every", 30*24*time.Hour, "how often the key is rotated, in whole hours")
+	setCommand.Flags().Uint("length", 0, "the length of generated values (defaults to 32)")
+	setCommand.Flags().Bool("lower", false, "only use lowercase letters in generated values")
+	setCommand.Flags().String("job-image", "", "the image of the job which rotates the key")
+	setCommand.Flags().StringSlice("job-command", nil, "the command of the rotation job (defaults to the entrypoint of the image)")
+	setCommand.Flags().Duration("job-timeout", 0, "how long the rotation job may run (defaults to 5 minutes)")
+	setCommand.Flags().StringSlice("channels", nil, "the Slack channels notified of failed rotations (defaults to every channel)")
+	_ = setCommand.MarkFlagRequired("key")
+
+	deleteCommand := &cobra.Command{
+		Use:               "delete [group]",
+		Short:             "Delete the rotation policy of a key of an environment group",
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: skipAppOrGroupCheck,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, envGroupRotationDelete)
+		},
+	}
+	deleteCommand.Flags().String("key", "", "the key whose rotation policy is deleted")
+	_ = deleteCommand.MarkFlagRequired("key")
+
+	runCommand := &cobra.Command{
+		Use:               "run [group]",
+		Short:             "Rotate a key of an environment group now",
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: skipAppOrGroupCheck,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, envGroupRotationRun)
+		},
+	}
+	runCommand.Flags().String("key", "", "the key to rotate")
+	_ = runCommand.MarkFlagRequired("key")
+
+	rotationCommand.AddCommand(listCommand)
+	rotationCommand.AddCommand(setCommand)
+	rotationCommand.AddCommand(deleteCommand)
+	rotationCommand.AddCommand(runCommand)
+
+	envCmd.AddCommand(rotationCommand)
+}
+
+func envGroupRotationList(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	resp, err := client.ListEnvGroupRotationPolicies(ctx, cliConf.Project, cliConf.Cluster, args[0])
+	if err != nil {
+		return fmt.Errorf("could not list env group rotation policies: %w", err)
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 3, 8, 2, ' ', 0)
+
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", "KEY", "EVERY", "HOOK", "LAST ROTATED", "NEXT ROTATION", "STATUS") // nolint:errcheck,gosec
+
+	for _, policy := range resp.Policies {
+		hook := string(policy.HookType)
+		if policy.Job != nil {
+			hook = fmt.Sprintf("job (%s)", policy.Job.Image)
+		}
+
+		lastRotated := "-"
+		if policy.LastRotatedAt != nil {
+			lastRotated = policy.LastRotatedAt.Local().Format(time.RFC3339)
+		}
+
+		status := string(policy.LastStatus)
+		if status == "" {
+			status = "-"
+		}
+
+		line := fmt.Sprintf("%s\t%dh\t%s\t%s\t%s\t%s\n", policy.Key, policy.IntervalHours, hook, lastRotated, policy.NextRotationAt.Local().Format(time.RFC3339), status)
+
+		if policy.LastStatus == types.EnvGroupRotationStatus_Failed {
+			color.New(color.FgRed).Fprint(w, line) // nolint:errcheck,gosec
+			continue
+		}
+		fmt.Fprint(w, line) // nolint:errcheck,gosec
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, policy := range resp.Policies {
+		if policy.LastStatus == types.EnvGroupRotationStatus_Failed {
+			color.New(color.FgRed).Printf("\n%s: %s\n", policy.Key, policy.LastError) // nolint:errcheck,gosec
+		}
+	}
+
+	return nil
+}
+
+func envGroupRotationSet(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	key, err := cmd.Flags().GetString("key")
+	if err != nil {
+		return fmt.Errorf("could not get key: %w", err)
+	}
+
+	every, err := cmd.Flags().GetDuration("every")
+	if err != nil {
+		return fmt.Errorf("could not get every: %w", err)
+	}
+	if every < time.Hour || every%time.Hour != 0 {
+		return fmt.Errorf("--every must be a whole number of hours, such as 24h or 720h")
+	}
+
+	length, err := cmd.Flags().GetUint("length")
+	if err != nil {
+		return fmt.Errorf("could not get length: %w", err)
+	}
+
+	lower, err := cmd.Flags().GetBool("lower")
+	if err != nil {
+		return fmt.Errorf("could not get lower: %w", err)
+	}
+
+	jobImage, err := cmd.Flags().GetString("job-image")
+	if err != nil {
+		return fmt.Errorf("could not get job-image: %w", err)
+	}
+
+	jobCommand, err := cmd.Flags().GetStringSlice("job-command")
+	if err != nil {
+		return fmt.Errorf("could not get job-command: %w", err)
+	}
+
+	jobTimeout, err := cmd.Flags().GetDuration("job-timeout")
+	if err != nil {
+		return fmt.Errorf("could not get job-timeout: %w", err)
+	}
+
+	channels, err := cmd.Flags().GetStringSlice("channels")
+	if err != nil {
+		return fmt.Errorf("could not get channels: %w", err)
+	}
+
+	spec := types.EnvGroupRotationPolicySpec{
+		Key:           key,
+		IntervalHours: uint(every.Hours()),
+		HookType:      types.EnvGroupRotationHookType_Generator,
+		Channels:      channels,
+	}
+
+	if jobImage != "" {
+		spec.HookType = types.EnvGroupRotationHookType_Job
+		spec.Job = &types.EnvGroupRotationJob{
+			Image:          jobImage,
+			Command:        jobCommand,
+			TimeoutSeconds: uint(jobTimeout.Seconds()),
+		}
+	} else {
+		spec.Generator = &types.EnvGroupRotationGenerator{
+			Length: length,
+			Lower:  lower,
+		}
+	}
+
+	existing, err := envGroupRotationPolicyByKey(ctx, client, cliConf, args[0], key)
+	if err != nil {
+		return err
+	}
+
+	var policy types.EnvGroupRotationPolicy
+	if existing == nil {
+		resp, err := client.CreateEnvGroupRotationPolicy(ctx, cliConf.Project, cliConf.Cluster, args[0], &types.CreateEnvGroupRotationPolicyRequest{
+			EnvGroupRotationPolicySpec: spec,
+		})
+		if err != nil {
+			return fmt.Errorf("could not create env group rotation policy: %w", err)
+		}
+		policy = resp.Policy
+	} else {
+		resp, err := client.UpdateEnvGroupRotationPolicy(ctx, cliConf.Project, cliConf.Cluster, args[0], existing.ID, &types.UpdateEnvGroupRotationPolicyRequest{
+			EnvGroupRotationPolicySpec: spec,
+		})
+		if err != nil {
+			return fmt.Errorf("could not update env group rotation policy: %w", err)
+		}
+		policy = resp.Policy
+	}
+
+	color.New(color.FgGreen).Printf("Key %s of environment group %s will next be rotated at %s\n", key, args[0], policy.NextRotationAt.Local().Format(time.RFC3339)) // nolint:errcheck,gosec
+
+	return nil
+}
+
+func envGroupRotationDelete(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	key, err := cmd.Flags().GetString("key")
+	if err != nil {
+		return fmt.Errorf("could not get key: %w", err)
+	}
+
+	policy, err := envGroupRotationPolicyByKey(ctx, client, cliConf, args[0], key)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return fmt.Errorf("key %s of environment group %s has no rotation policy", key, args[0])
+	}
+
+	if err := client.DeleteEnvGroupRotationPolicy(ctx, cliConf.Project, cliConf.Cluster, args[0], policy.ID); err != nil {
+		return fmt.Errorf("could not delete env group rotation policy: %w", err)
+	}
+
+	color.New(color.FgGreen).Printf("Deleted the rotation policy of key %s of environment group %s\n", key, args[0]) // nolint:errcheck,gosec
+
+	return nil
+}
+
+func envGroupRotationRun(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	key, err := cmd.Flags().GetString("key")
+	if err != nil {
+		return fmt.Errorf("could not get key: %w", err)
+	}
+
+	policy, err := envGroupRotationPolicyByKey(ctx, client, cliConf, args[0], key)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return fmt.Errorf("key %s of environment group %s has no rotation policy", key, args[0])
+	}
+
+	resp, err := client.RotateEnvGroupKey(ctx, cliConf.Project, cliConf.Cluster, args[0], policy.ID)
+	if err != nil {
+		return fmt.Errorf("could not rotate env group key: %w", err)
+	}
+
+	color.New(color.FgGreen).Printf("Rotated key %s of environment group %s as version %d\n", key, args[0], resp.Policy.LastVersion) // nolint:errcheck,gosec
+
+	return nil
+}
+
+// envGroupRotationPolicyByKey returns the rotation policy of a key of an environment group, or nil if it has none
+func envGroupRotationPolicyByKey(ctx context.Context, client api.Client, cliConf config.CLIConfig, envGroupName, key string) (*types.EnvGroupRotationPolicy, error) {
+	resp, err := client.ListEnvGroupRotationPolicies(ctx, cliConf.Project, cliConf.Cluster, envGroupName)
+	if err != nil {
+		return nil, fmt.Errorf("could not list env group rotation policies: %w", err)
+	}
+
+	for _, policy := range resp.Policies {
+		if policy.Key == key {
+			return &policy, nil
+		}
+	}
+
+	return nil, nil
+}
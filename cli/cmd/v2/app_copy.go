@@ -0,0 +1,271 @@
+package v2
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/cli/cmd/config"
+)
+
+// remotePathPrefix marks an argument to `porter app cp` as a path in the app container
+const remotePathPrefix = ":"
+
+// AppCopyInput is the input for the AppCopy function
+type AppCopyInput struct {
+	// CLIConfig is the CLI configuration
+	CLIConfig config.CLIConfig
+	// Client is the Porter API client
+	Client api.Client
+	// DeploymentTargetName is the name of deployment target where the app is deployed
+	DeploymentTargetName string
+	// AppName is the name of the app to copy to or from
+	AppName string
+	// ServiceName is the name of the service to copy to or from
+	ServiceName string
+	// PodName is the name of the replica to copy to or from. If empty, a healthy replica is chosen by the server
+	PodName string
+	// ContainerName is the name of the container to copy to or from. If empty, the pod's default container is used
+	ContainerName string
+	// Source is the path to copy from. Paths in the container are prefixed with ":"
+	Source string
+	// Destination is the path to copy to. Paths in the container are prefixed with ":"
+	Destination string
+}
+
+// AppCopy copies a file or directory between the local machine and a running replica of an app service.
+// Exactly one of the source and destination must be a path in the container, prefixed with ":".
+func AppCopy(ctx context.Context, inp AppCopyInput) error {
+	remoteSource := strings.HasPrefix(inp.Source, remotePathPrefix)
+	remoteDestination := strings.HasPrefix(inp.Destination, remotePathPrefix)
+
+	switch {
+	case remoteSource && !remoteDestination:
+		return appCopyDownload(ctx, inp, strings.TrimPrefix(inp.Source, remotePathPrefix), inp.Destination)
+	case !remoteSource && remoteDestination:
+		return appCopyUpload(ctx, inp, inp.Source, strings.TrimPrefix(inp.Destination, remotePathPrefix))
+	default:
+		return errors.New("exactly one of the source and destination must be a path in the container, prefixed with \":\"")
+	}
+}
+
+// appCopyUpload archives the local path and extracts it in the container, so that remotePath is a copy of localPath
+func appCopyUpload(ctx context.Context, inp AppCopyInput, localPath, remotePath string) error {
+	if !path.IsAbs(remotePath) {
+		return fmt.Errorf("container path %s must be absolute", remotePath)
+	}
+
+	remotePath = path.Clean(remotePath)
+	if remotePath == "/" {
+		return errors.New("cannot copy to the root directory of the container")
+	}
+
+	if _, err := os.Stat(localPath); err != nil {
+		return fmt.Errorf("error reading local path: %w", err)
+	}
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		pw.CloseWithError(writeTar(pw, localPath, path.Base(remotePath))) // nolint:errcheck,gosec
+	}()
+
+	// closing the reader stops the archive from being written if the stream fails
+	defer pr.Close() // nolint:errcheck
+
+	return streamCopy(ctx, inp, types.AppCopyDirection_Upload, path.Dir(remotePath), execStreams{
+		Stdin:  pr,
+		Stdout: io.Discard,
+		Stderr: os.Stderr,
+	})
+}
+
+// appCopyDownload archives the path in the container and extracts it locally, so that localPath is a copy of remotePath
+func appCopyDownload(ctx context.Context, inp AppCopyInput, remotePath, localPath string) error {
+	pr, pw := io.Pipe()
+
+	extractErr := make(chan error, 1)
+
+	go func() {
+		err := readTar(pr, localPath)
+		// closing the reader unblocks the stream if extraction failed before the end of the archive
+		pr.CloseWithError(err) // nolint:errcheck,gosec
+		extractErr <- err
+	}()
+
+	err := streamCopy(ctx, inp, types.AppCopyDirection_Download, remotePath, execStreams{
+		Stdout: pw,
+		Stderr: os.Stderr,
+	})
+	pw.CloseWithError(err) // nolint:errcheck,gosec
+
+	if exErr := <-extractErr; exErr != nil && err == nil {
+		return fmt.Errorf("error extracting files: %w", exErr)
+	}
+
+	return err
+}
+
+// streamCopy runs a copy on the server and waits for it to complete
+func streamCopy(ctx context.Context, inp AppCopyInput, direction types.AppCopyDirection, remotePath string, streams execStreams) error {
+	conn, err := inp.Client.AppCopyStream(ctx, api.AppCopyInput{
+		ProjectID:            inp.CLIConfig.Project,
+		ClusterID:            inp.CLIConfig.Cluster,
+		AppName:              inp.AppName,
+		ServiceName:          inp.ServiceName,
+		DeploymentTargetName: inp.DeploymentTargetName,
+		PodName:              inp.PodName,
+		ContainerName:        inp.ContainerName,
+		Path:                 remotePath,
+		Direction:            direction,
+	})
+	if err != nil {
+		return fmt.Errorf("error connecting to copy stream: %w", err)
+	}
+	defer conn.Close() // nolint:errcheck
+
+	exitCode, err := streamExec(ctx, conn, streams)
+	if err != nil {
+		return err
+	}
+
+	if exitCode != 0 {
+		return fmt.Errorf("tar exited with code %d in the container", exitCode)
+	}
+
+	return nil
+}
+
+// writeTar writes the file or directory at localPath to w as a tar archive, with its contents rooted at name
+func writeTar(w io.Writer, localPath, name string) error {
+	tw := tar.NewWriter(w)
+
+	err := filepath.Walk(localPath, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(localPath, file)
+		if err != nil {
+			return err
+		}
+
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err = os.Readlink(file)
+			if err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+
+		header.Name = path.Join(name, filepath.ToSlash(rel))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(filepath.Clean(file))
+		if err != nil {
+			return err
+		}
+		defer f.Close() // nolint:errcheck
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// readTar extracts a tar archive with a single top-level entry to localPath, so that the top-level entry is
+// renamed to localPath. Entries which would be extracted outside of localPath are rejected.
+func readTar(r io.Reader, localPath string) error {
+	tr := tar.NewReader(r)
+
+	var root string
+
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return err
+		}
+
+		name := path.Clean(header.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("archive entry %s is outside of the copied path", header.Name)
+		}
+
+		top, rest, _ := strings.Cut(name, "/")
+		if root == "" {
+			root = top
+		}
+
+		if top != root {
+			return fmt.Errorf("archive entry %s is outside of the copied path", header.Name)
+		}
+
+		target := filepath.Join(localPath, filepath.FromSlash(rest))
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil { // nolint:gosec
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil { // nolint:gosec
+				return err
+			}
+
+			f, err := os.OpenFile(filepath.Clean(target), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, header.FileInfo().Mode().Perm())
+			if err != nil {
+				return err
+			}
+
+			_, err = io.Copy(f, tr) // nolint:gosec
+			closeErr := f.Close()
+
+			if err != nil {
+				return err
+			}
+
+			if closeErr != nil {
+				return closeErr
+			}
+		default:
+			// symlinks and other special files are skipped, since they may point outside of the copied path
+		}
+	}
+
+	if root == "" {
+		return errors.New("no files were copied")
+	}
+
+	return nil
+}
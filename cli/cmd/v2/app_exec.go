@@ -0,0 +1,225 @@
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/cli/cmd/config"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/kubectl/pkg/util/term"
+)
+
+// execBufferSize is the maximum number of bytes read from stdin before being sent to the server
+const execBufferSize = 32 * 1024
+
+// AppExecInput is the input for the AppExec function
+type AppExecInput struct {
+	// CLIConfig is the CLI configuration
+	CLIConfig config.CLIConfig
+	// Client is the Porter API client
+	Client api.Client
+	// DeploymentTargetName is the name of deployment target where the app is deployed
+	DeploymentTargetName string
+	// AppName is the name of the app to exec into
+	AppName string
+	// ServiceName is the name of the service to exec into
+	ServiceName string
+	// PodName is the name of the replica to exec into. If empty, a healthy replica is chosen by the server
+	PodName string
+	// ContainerName is the name of the container to exec into. If empty, the pod's default container is used
+	ContainerName string
+	// Command is the command to run
+	Command []string
+	// Stdin forwards the local stdin to the command
+	Stdin bool
+	// TTY allocates a terminal for the command
+	TTY bool
+}
+
+// AppExec runs a command in a running replica of an app service through the Porter API, and returns the
+// exit code of the command
+func AppExec(ctx context.Context, inp AppExecInput) (int, error) {
+	conn, err := inp.Client.AppExecStream(ctx, api.AppExecInput{
+		ProjectID:            inp.CLIConfig.Project,
+		ClusterID:            inp.CLIConfig.Cluster,
+		AppName:              inp.AppName,
+		ServiceName:          inp.ServiceName,
+		DeploymentTargetName: inp.DeploymentTargetName,
+		PodName:              inp.PodName,
+		ContainerName:        inp.ContainerName,
+		Command:              inp.Command,
+		TTY:                  inp.TTY,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error connecting to exec stream: %w", err)
+	}
+	defer conn.Close() // nolint:errcheck
+
+	streams := execStreams{
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+
+	if inp.Stdin {
+		streams.Stdin = os.Stdin
+	}
+
+	if !inp.TTY {
+		return streamExec(ctx, conn, streams)
+	}
+
+	t := term.TTY{
+		In:  os.Stdin,
+		Out: os.Stdout,
+		Raw: inp.Stdin,
+	}
+	streams.SizeQueue = t.MonitorSize(t.GetSize())
+
+	var exitCode int
+	err = t.Safe(func() error {
+		exitCode, err = streamExec(ctx, conn, streams)
+		return err
+	})
+
+	return exitCode, err
+}
+
+// execStreams are the local streams connected to a command running in an app container
+type execStreams struct {
+	// Stdin is sent to the command. If nil, the command's stdin is closed immediately
+	Stdin io.Reader
+	// Stdout receives the stdout of the command, and the stderr of the command when a terminal is allocated
+	Stdout io.Writer
+	// Stderr receives the stderr of the command
+	Stderr io.Writer
+	// SizeQueue reports local terminal resizes, if a terminal is allocated
+	SizeQueue remotecommand.TerminalSizeQueue
+}
+
+// execConn serializes writes to an exec websocket, since stdin and resize messages are sent concurrently
+type execConn struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+func (c *execConn) send(stream types.ExecStream, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.conn.WriteMessage(websocket.BinaryMessage, append([]byte{byte(stream)}, payload...))
+}
+
+// streamExec proxies the local streams over an exec websocket until the server sends the exit message of
+// the command, and returns the exit code
+func streamExec(ctx context.Context, conn *websocket.Conn, streams execStreams) (int, error) {
+	ec := &execConn{conn: conn}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	// closing the websocket unblocks the reader below on shutdown
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close() // nolint:errcheck,gosec
+		case <-done:
+		}
+	}()
+
+	go func() {
+		if streams.Stdin == nil {
+			ec.send(types.ExecStreamStdinClose, nil) // nolint:errcheck,gosec
+			return
+		}
+
+		buf := make([]byte, execBufferSize)
+
+		for {
+			n, err := streams.Stdin.Read(buf)
+			if n > 0 {
+				if writeErr := ec.send(types.ExecStreamStdin, buf[:n]); writeErr != nil {
+					return
+				}
+			}
+
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					ec.send(types.ExecStreamStdinClose, nil) // nolint:errcheck,gosec
+				}
+
+				return
+			}
+		}
+	}()
+
+	if streams.SizeQueue != nil {
+		go func() {
+			for {
+				size := streams.SizeQueue.Next()
+				if size == nil {
+					return
+				}
+
+				payload, err := json.Marshal(types.ExecResizeMessage{Width: size.Width, Height: size.Height})
+				if err != nil {
+					continue
+				}
+
+				if err := ec.send(types.ExecStreamResize, payload); err != nil {
+					return
+				}
+			}
+		}()
+	}
+
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+
+			return 0, fmt.Errorf("exec stream closed before the command exited: %w", err)
+		}
+
+		// the server only sends text messages to report an error before the command is started
+		if messageType == websocket.TextMessage {
+			return 0, errors.New(strings.TrimSpace(string(data)))
+		}
+
+		if len(data) == 0 {
+			continue
+		}
+
+		switch types.ExecStream(data[0]) {
+		case types.ExecStreamStdout:
+			if _, err := streams.Stdout.Write(data[1:]); err != nil {
+				return 0, fmt.Errorf("error writing stdout: %w", err)
+			}
+		case types.ExecStreamStderr:
+			if _, err := streams.Stderr.Write(data[1:]); err != nil {
+				return 0, fmt.Errorf("error writing stderr: %w", err)
+			}
+		case types.ExecStreamExit:
+			exit := types.ExecExitMessage{}
+			if err := json.Unmarshal(data[1:], &exit); err != nil {
+				return 0, fmt.Errorf("error reading exit message: %w", err)
+			}
+
+			if exit.Error != "" {
+				return exit.ExitCode, errors.New(exit.Error)
+			}
+
+			return exit.ExitCode, nil
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/server/handlers/porter_app"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/cli/cmd/config"
+	porter_app_internal "github.com/porter-dev/porter/internal/porter_app"
+)
+
+// LogOutputFormat is the format in which searched logs are printed
+type LogOutputFormat string
+
+const (
+	// LogOutputFormat_Text prints each log with its timestamp and service
+	LogOutputFormat_Text LogOutputFormat = "text"
+	// LogOutputFormat_JSON prints each log as a JSON object on its own line
+	LogOutputFormat_JSON LogOutputFormat = "json"
+	// LogOutputFormat_CSV prints logs as CSV with a header row
+	LogOutputFormat_CSV LogOutputFormat = "csv"
+)
+
+// logSearchPageSize is the number of logs requested from the search endpoint at a time
+const logSearchPageSize = 1000
+
+// AppLogsSearchInput is the input for the AppLogsSearch function
+type AppLogsSearchInput struct {
+	// CLIConfig is the CLI configuration
+	CLIConfig config.CLIConfig
+	// Client is the Porter API client
+	Client api.Client
+	// DeploymentTargetName is the name of deployment target where the app is deployed
+	DeploymentTargetName string
+	// AppName is the name of the app to search the logs of
+	AppName string
+	// ServiceName is an optional service name filter
+	ServiceName string
+	// Query is a log query, such as `level:>=warn -healthcheck`
+	Query string
+	// Grep is a regular expression which matching lines must contain
+	Grep string
+	// SavedSearch is the name of a saved search whose query is combined with the other filters
+	SavedSearch string
+	// SaveAs saves the combined query under this name before searching
+	SaveAs string
+	// Since is how far back the search goes
+	Since time.Duration
+	// Format is the format in which logs are printed
+	Format LogOutputFormat
+}
+
+// AppLogsSearch pages through the logs of an app over a time range, from oldest to newest, printing the logs which
+// match the query
+func AppLogsSearch(ctx context.Context, inp AppLogsSearchInput) error {
+	if inp.Since <= 0 {
+		return errors.New("--since must be positive")
+	}
+
+	var terms []string
+	if inp.ServiceName != "" && inp.ServiceName != ServiceName_AllServices {
+		terms = append(terms, fmt.Sprintf("service:%s", inp.ServiceName))
+	}
+
+	if inp.SavedSearch != "" {
+		savedQuery, err := savedLogSearchQuery(ctx, inp.Client, inp.CLIConfig.Project, inp.SavedSearch)
+		if err != nil {
+			return err
+		}
+		terms = append(terms, savedQuery)
+	}
+
+	if inp.Query != "" {
+		terms = append(terms, inp.Query)
+	}
+	if inp.Grep != "" {
+		terms = append(terms, fmt.Sprintf("/%s/", strings.ReplaceAll(inp.Grep, "/", `\/`)))
+	}
+
+	query := strings.Join(terms, " ")
+
+	if inp.SaveAs != "" {
+		_, err := inp.Client.CreateLogSearch(ctx, inp.CLIConfig.Project, &types.CreateLogSearchRequest{
+			Name:    inp.SaveAs,
+			Query:   query,
+			AppName: inp.AppName,
+		})
+		if err != nil {
+			return fmt.Errorf("error saving log search: %w", err)
+		}
+
+		color.New(color.FgGreen).Fprintf(os.Stderr, "Saved log search %s: %s\n", inp.SaveAs, query) // nolint:errcheck,gosec
+	}
+
+	end := time.Now().UTC()
+	req := &porter_app.AppLogSearchRequest{
+		DeploymentTargetName: inp.DeploymentTargetName,
+		Query:                query,
+		StartRange:           end.Add(-inp.Since),
+		EndRange:             end,
+		Limit:                logSearchPageSize,
+		Direction:            porter_app_internal.LogSearchDirection_Forward,
+	}
+
+	header := true
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		resp, err := inp.Client.SearchAppLogs(ctx, inp.CLIConfig.Project, inp.CLIConfig.Cluster, inp.AppName, req)
+		if err != nil {
+			return fmt.Errorf("error searching app logs: %w", err)
+		}
+
+		if err := printSearchedLogs(resp.Logs, inp.Format, header); err != nil {
+			return err
+		}
+		header = false
+
+		// the search continues from a (time, offset) cursor, since many logs can share a timestamp
+		if resp.ContinueTime == nil || resp.ContinueTime.Before(req.StartRange) {
+			return nil
+		}
+		if resp.ContinueTime.Equal(req.StartRange) && uint(resp.ContinueOffset) <= req.Offset {
+			return nil
+		}
+		req.StartRange = *resp.ContinueTime
+		req.Offset = uint(resp.ContinueOffset)
+	}
+}
+
+// savedLogSearchQuery returns the query of a saved log search of a project
+func savedLogSearchQuery(ctx context.Context, client api.Client, projectID uint, name string) (string, error) {
+	resp, err := client.ListLogSearches(ctx, projectID)
+	if err != nil {
+		return "", fmt.Errorf("error listing saved log searches: %w", err)
+	}
+
+	var names []string
+	for _, search := range resp.LogSearches {
+		if search.Name == name {
+			return search.Query, nil
+		}
+		names = append(names, search.Name)
+	}
+
+	if len(names) == 0 {
+		return "", fmt.Errorf("saved log search %s not found: the project has no saved log searches", name)
+	}
+
+	return "", fmt.Errorf("saved log search %s not found, available searches are: %s", name, strings.Join(names, ", "))
+}
+
+// printSearchedLogs prints a page of searched logs to stdout
+func printSearchedLogs(logs []porter_app_internal.StructuredLog, format LogOutputFormat, header bool) error {
+	switch format {
+	case LogOutputFormat_JSON:
+		return porter_app_internal.WriteLogsNDJSON(os.Stdout, logs)
+	case LogOutputFormat_CSV:
+		return porter_app_internal.WriteLogsCSV(os.Stdout, logs, header)
+	}
+
+	for _, log := range logs {
+		if _, err := fmt.Fprintf(os.Stdout, "%s %s %s\n", log.Timestamp.Local().Format(time.RFC3339), log.ServiceName, log.Line); err != nil {
+			return nil
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,233 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/cli/cmd/config"
+	"github.com/porter-dev/porter/internal/kubernetes/prometheus"
+)
+
+// sparklineWidth is the maximum number of characters in a sparkline
+const sparklineWidth = 60
+
+// sparklineBlocks are the characters used to draw a sparkline, from lowest to highest
+var sparklineBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// AppMetricsInput is the input for the AppMetrics function
+type AppMetricsInput struct {
+	// CLIConfig is the CLI configuration
+	CLIConfig config.CLIConfig
+	// Client is the Porter API client
+	Client api.Client
+	// DeploymentTargetName is the name of deployment target where the app is deployed
+	DeploymentTargetName string
+	// AppName is the name of the app to query metrics for
+	AppName string
+	// Query is a PromQL expression to chart. Exactly one of Query and Dashboard must be set
+	Query string
+	// Dashboard is the name of a saved dashboard whose panels are charted
+	Dashboard string
+	// Since is how far back metrics are charted
+	Since time.Duration
+	// Step is the query resolution, such as 1m. If empty, the server chooses a step based on Since
+	Step string
+}
+
+// AppMetrics charts the results of a PromQL query, or of each panel of a saved dashboard, as sparklines
+func AppMetrics(ctx context.Context, inp AppMetricsInput) error {
+	if (inp.Query == "") == (inp.Dashboard == "") {
+		return errors.New("exactly one of --query and --dashboard must be set")
+	}
+
+	if inp.Since <= 0 {
+		return errors.New("--since must be positive")
+	}
+
+	if inp.Query != "" {
+		return printMetricsPanel(ctx, inp, types.AppDashboardPanel{Query: inp.Query})
+	}
+
+	resp, err := inp.Client.ListAppDashboards(ctx, inp.CLIConfig.Project, inp.CLIConfig.Cluster, inp.AppName)
+	if err != nil {
+		return fmt.Errorf("error listing dashboards: %w", err)
+	}
+
+	for _, dashboard := range resp.Dashboards {
+		if dashboard.Name != inp.Dashboard {
+			continue
+		}
+
+		for i, panel := range dashboard.Panels {
+			if i > 0 {
+				fmt.Println()
+			}
+
+			color.New(color.FgBlue, color.Bold).Println(panel.Title) // nolint:errcheck,gosec
+
+			if err := printMetricsPanel(ctx, inp, panel); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("dashboard %s not found for app %s", inp.Dashboard, inp.AppName)
+}
+
+// printMetricsPanel runs the query of a panel and prints a sparkline for each series in the result
+func printMetricsPanel(ctx context.Context, inp AppMetricsInput, panel types.AppDashboardPanel) error {
+	end := time.Now()
+	start := end.Add(-inp.Since)
+
+	resp, err := inp.Client.QueryAppMetrics(ctx, api.QueryAppMetricsInput{
+		ProjectID:            inp.CLIConfig.Project,
+		ClusterID:            inp.CLIConfig.Cluster,
+		AppName:              inp.AppName,
+		DeploymentTargetName: inp.DeploymentTargetName,
+		Query:                panel.Query,
+		StartRange:           uint(start.Unix()),
+		EndRange:             uint(end.Unix()),
+		Resolution:           inp.Step,
+	})
+	if err != nil {
+		return fmt.Errorf("error querying metrics: %w", err)
+	}
+
+	if len(resp.Series) == 0 {
+		fmt.Println("no data")
+		return nil
+	}
+
+	names := make([]string, len(resp.Series))
+	nameWidth := 0
+	for i, series := range resp.Series {
+		names[i] = seriesName(series)
+		if len(names[i]) > nameWidth {
+			nameWidth = len(names[i])
+		}
+	}
+
+	for i, series := range resp.Series {
+		values := make([]float64, len(series.Values))
+		for j, v := range series.Values {
+			values[j] = v.Value
+		}
+
+		if len(values) == 0 {
+			fmt.Printf("%-*s  no data\n", nameWidth, names[i])
+			continue
+		}
+
+		lo, hi := values[0], values[0]
+		for _, v := range values {
+			lo = math.Min(lo, v)
+			hi = math.Max(hi, v)
+		}
+
+		fmt.Printf("%-*s  %s  min %s  max %s  last %s\n",
+			nameWidth, names[i],
+			color.New(color.FgGreen).Sprint(sparkline(values, sparklineWidth)),
+			formatMetricValue(lo, panel.Unit),
+			formatMetricValue(hi, panel.Unit),
+			formatMetricValue(values[len(values)-1], panel.Unit),
+		)
+	}
+
+	return nil
+}
+
+// seriesName formats the labels of a series like a PromQL selector. The namespace label is omitted, since every
+// series is in the namespace of the app.
+func seriesName(series prometheus.Series) string {
+	labels := make([]string, 0, len(series.Labels))
+	for name, value := range series.Labels {
+		if name == "__name__" || name == "namespace" {
+			continue
+		}
+		labels = append(labels, fmt.Sprintf("%s=%q", name, value))
+	}
+	sort.Strings(labels)
+
+	name := series.Labels["__name__"]
+	if len(labels) == 0 && name == "" {
+		return "{}"
+	}
+
+	if len(labels) == 0 {
+		return name
+	}
+
+	return fmt.Sprintf("%s{%s}", name, strings.Join(labels, ", "))
+}
+
+// sparkline draws values as a line of block characters, averaging neighbouring values if there are more than width
+func sparkline(values []float64, width int) string {
+	if len(values) > width {
+		buckets := make([]float64, width)
+		for i := range buckets {
+			from := i * len(values) / width
+			to := (i + 1) * len(values) / width
+
+			sum := 0.0
+			for _, v := range values[from:to] {
+				sum += v
+			}
+			buckets[i] = sum / float64(to-from)
+		}
+		values = buckets
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+
+	var sb strings.Builder
+	for _, v := range values {
+		idx := 0
+		if hi > lo {
+			idx = int((v - lo) / (hi - lo) * float64(len(sparklineBlocks)-1))
+		}
+		sb.WriteRune(sparklineBlocks[idx])
+	}
+
+	return sb.String()
+}
+
+// formatMetricValue formats a value in the given panel unit
+func formatMetricValue(v float64, unit string) string {
+	switch unit {
+	case "bytes":
+		const k = 1024.0
+		suffixes := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+		i := 0
+		for math.Abs(v) >= k && i < len(suffixes)-1 {
+			v /= k
+			i++
+		}
+		return fmt.Sprintf("%.1f%s", v, suffixes[i])
+	case "seconds":
+		return time.Duration(v * float64(time.Second)).Round(time.Millisecond).String()
+	case "milliseconds":
+		return time.Duration(v * float64(time.Millisecond)).Round(time.Millisecond).String()
+	case "percent":
+		return fmt.Sprintf("%.1f%%", v)
+	case "cores":
+		return fmt.Sprintf("%.3f cores", v)
+	case "requests_per_second":
+		return fmt.Sprintf("%.2f req/s", v)
+	default:
+		return fmt.Sprintf("%.4g", v)
+	}
+}
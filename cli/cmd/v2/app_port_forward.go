@@ -0,0 +1,222 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/fatih/color"
+	"github.com/gorilla/websocket"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/cli/cmd/config"
+)
+
+// portForwardBufferSize is the maximum number of bytes read from a local connection before being sent to the server
+const portForwardBufferSize = 32 * 1024
+
+// AppPortForwardInput is the input for the AppPortForward function
+type AppPortForwardInput struct {
+	// CLIConfig is the CLI configuration
+	CLIConfig config.CLIConfig
+	// Client is the Porter API client
+	Client api.Client
+	// DeploymentTargetName is the name of deployment target where the app is deployed
+	DeploymentTargetName string
+	// AppName is the name of the app to forward to
+	AppName string
+	// ServiceName is the name of the service to forward to
+	ServiceName string
+	// LocalPort is the port to listen on locally
+	LocalPort int
+	// RemotePort is the port on the service's pods that connections are forwarded to
+	RemotePort int
+}
+
+// ParsePortForwardPorts parses a port specification of the form [local:]remote. If the local port is
+// omitted, the remote port is used for both.
+func ParsePortForwardPorts(spec string) (int, int, error) {
+	localSpec, remoteSpec, found := strings.Cut(spec, ":")
+	if !found {
+		remoteSpec = localSpec
+	}
+
+	local, err := parsePort(localSpec)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid local port: %w", err)
+	}
+
+	remote, err := parsePort(remoteSpec)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid remote port: %w", err)
+	}
+
+	return local, remote, nil
+}
+
+func parsePort(s string) (int, error) {
+	port, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+
+	if port < 1 || port > 65535 {
+		return 0, fmt.Errorf("port %d is out of range", port)
+	}
+
+	return port, nil
+}
+
+// AppPortForward listens on a local port and forwards every connection to a port on a pod of an app service
+// through the Porter API. Each local connection is carried by its own websocket, so any number of
+// connections can be open at once.
+func AppPortForward(ctx context.Context, inp AppPortForwardInput) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	listener, err := net.Listen("tcp", net.JoinHostPort("localhost", strconv.Itoa(inp.LocalPort)))
+	if err != nil {
+		return fmt.Errorf("error listening on local port %d: %w", inp.LocalPort, err)
+	}
+
+	termChan := make(chan os.Signal, 1)
+	signal.Notify(termChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(termChan)
+
+	go func() {
+		select {
+		case <-termChan:
+			color.New(color.FgYellow).Println("Shutdown signal received, closing port-forward") // nolint:errcheck,gosec
+		case <-ctx.Done():
+		}
+
+		cancel()
+		listener.Close() // nolint:errcheck,gosec
+	}()
+
+	color.New(color.FgGreen).Printf("Forwarding from %s -> %s/%s:%d [CTRL-C to exit]\n", listener.Addr().String(), inp.AppName, inp.ServiceName, inp.RemotePort) // nolint:errcheck,gosec
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		localConn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return fmt.Errorf("error accepting local connection: %w", err)
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer localConn.Close() // nolint:errcheck
+
+			err := forwardConnection(ctx, inp, localConn)
+			if err != nil {
+				color.New(color.FgRed).Fprintf(os.Stderr, "error forwarding connection from %s: %s\n", localConn.RemoteAddr().String(), err.Error()) // nolint:errcheck,gosec
+			}
+		}()
+	}
+}
+
+// forwardConnection copies data between a single local connection and a new port-forward websocket until
+// either side closes
+func forwardConnection(ctx context.Context, inp AppPortForwardInput, localConn net.Conn) error {
+	conn, err := inp.Client.AppPortForwardStream(ctx, api.AppPortForwardInput{
+		ProjectID:            inp.CLIConfig.Project,
+		ClusterID:            inp.CLIConfig.Cluster,
+		AppName:              inp.AppName,
+		ServiceName:          inp.ServiceName,
+		DeploymentTargetName: inp.DeploymentTargetName,
+		Port:                 inp.RemotePort,
+	})
+	if err != nil {
+		return fmt.Errorf("error connecting to port-forward stream: %w", err)
+	}
+	defer conn.Close() // nolint:errcheck
+
+	done := make(chan struct{})
+	defer close(done)
+
+	// closing both connections unblocks the readers below on shutdown
+	go func() {
+		select {
+		case <-ctx.Done():
+			localConn.Close() // nolint:errcheck,gosec
+			conn.Close()      // nolint:errcheck,gosec
+		case <-done:
+		}
+	}()
+
+	readErr := make(chan error, 1)
+
+	go func() {
+		defer localConn.Close() // nolint:errcheck
+
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+					err = nil
+				}
+
+				readErr <- err
+				return
+			}
+
+			// the server only sends text messages to report an error
+			if messageType == websocket.TextMessage {
+				readErr <- errors.New(strings.TrimSpace(string(data)))
+				return
+			}
+
+			if _, err := localConn.Write(data); err != nil {
+				readErr <- err
+				return
+			}
+		}
+	}()
+
+	buf := make([]byte, portForwardBufferSize)
+
+	for {
+		n, err := localConn.Read(buf)
+		if n > 0 {
+			if writeErr := conn.WriteMessage(websocket.BinaryMessage, buf[:n]); writeErr != nil {
+				return fmt.Errorf("error writing to port-forward stream: %w", writeErr)
+			}
+		}
+
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")) // nolint:errcheck,gosec
+			}
+
+			break
+		}
+	}
+
+	// if the local side closed first, the server will close the websocket once the pod connection is done
+	err = <-readErr
+	if err != nil && ctx.Err() == nil && !errors.Is(err, net.ErrClosed) {
+		var closeErr *websocket.CloseError
+		if errors.As(err, &closeErr) {
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/cli/cmd/config"
+)
+
+// AppStatusInput is the input for the AppStatus function
+type AppStatusInput struct {
+	// CLIConfig is the CLI configuration
+	CLIConfig config.CLIConfig
+	// Client is the Porter API client
+	Client api.Client
+	// DeploymentTargetName is the name of deployment target where the app is deployed
+	DeploymentTargetName string
+	// AppName is the name of the app
+	AppName string
+}
+
+// AppStatus prints the synthetic checks of an app, along with the result of their most recent run
+func AppStatus(ctx context.Context, inp AppStatusInput) error {
+	resp, err := inp.Client.ListAppSyntheticChecks(ctx, inp.CLIConfig.Project, inp.CLIConfig.Cluster, inp.AppName, "", inp.DeploymentTargetName)
+	if err != nil {
+		return fmt.Errorf("error listing synthetic checks: %w", err)
+	}
+
+	if len(resp.Checks) == 0 {
+		fmt.Println("no synthetic checks found")
+		return nil
+	}
+
+	for _, check := range resp.Checks {
+		printSyntheticCheck(check)
+	}
+
+	return nil
+}
+
+// printSyntheticCheck prints a synthetic check with its status, followed by the details of its most recent result
+func printSyntheticCheck(check types.AppSyntheticCheck) {
+	line := fmt.Sprintf("%-8s %-24s %-16s https://%s%s", check.Status, check.Name, check.ServiceName, check.Domain, check.Path)
+
+	switch check.Status {
+	case types.AppSyntheticCheckStatus_Passing:
+		color.New(color.FgGreen).Println(line) // nolint:errcheck,gosec
+	case types.AppSyntheticCheckStatus_Warning:
+		color.New(color.FgYellow).Println(line) // nolint:errcheck,gosec
+	case types.AppSyntheticCheckStatus_Failing:
+		color.New(color.FgRed).Println(line) // nolint:errcheck,gosec
+	default:
+		fmt.Println(line)
+	}
+
+	if check.LastResult == nil {
+		return
+	}
+
+	result := check.LastResult
+
+	fmt.Printf("         last checked %s, latency %dms", result.CheckedAt.Local().Format(time.DateTime), result.LatencyMs)
+	if result.StatusCode != 0 {
+		fmt.Printf(", status code %d", result.StatusCode)
+	}
+	if result.TLSExpiresAt != nil {
+		fmt.Printf(", certificate expires %s", result.TLSExpiresAt.Local().Format(time.DateOnly))
+	}
+	fmt.Println()
+
+	if result.Error != "" {
+		fmt.Printf("         %s\n", result.Error)
+	}
+	if check.IncidentOpenedAt != nil {
+		color.New(color.FgRed).Printf("         incident open since %s (%d consecutive failures)\n", check.IncidentOpenedAt.Local().Format(time.DateTime), check.ConsecutiveFailures) // nolint:errcheck,gosec
+	}
+}
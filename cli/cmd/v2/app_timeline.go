@@ -0,0 +1,98 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/cli/cmd/config"
+)
+
+// AppTimelineInput is the input for the AppTimeline function
+type AppTimelineInput struct {
+	// CLIConfig is the CLI configuration
+	CLIConfig config.CLIConfig
+	// Client is the Porter API client
+	Client api.Client
+	// DeploymentTargetName is the name of deployment target where the app is deployed
+	DeploymentTargetName string
+	// AppName is the name of the app
+	AppName string
+	// ServiceName restricts the timeline to a single service, along with entries for the whole app
+	ServiceName string
+	// Since is how far back the timeline goes
+	Since time.Duration
+}
+
+// AppTimeline prints the revisions, helm upgrades, kubernetes events, scaling events and incidents of an app in the
+// order they happened, along with hints linking warnings to the revisions that likely caused them
+func AppTimeline(ctx context.Context, inp AppTimelineInput) error {
+	if inp.Since <= 0 {
+		return errors.New("--since must be positive")
+	}
+
+	end := time.Now()
+	start := end.Add(-inp.Since)
+
+	resp, err := inp.Client.GetAppTimeline(ctx, inp.CLIConfig.Project, inp.CLIConfig.Cluster, inp.AppName, &types.GetAppTimelineRequest{
+		DeploymentTargetName: inp.DeploymentTargetName,
+		ServiceName:          inp.ServiceName,
+		StartRange:           uint(start.Unix()),
+		EndRange:             uint(end.Unix()),
+	})
+	if err != nil {
+		return fmt.Errorf("error getting app timeline: %w", err)
+	}
+
+	for _, warning := range resp.Warnings {
+		color.New(color.FgYellow).Printf("Warning: %s\n", warning) // nolint:errcheck,gosec
+	}
+
+	if len(resp.Entries) == 0 {
+		fmt.Println("no timeline entries found")
+		return nil
+	}
+
+	for _, entry := range resp.Entries {
+		printTimelineEntry(entry)
+	}
+
+	return nil
+}
+
+// printTimelineEntry prints a single timeline entry, followed by its hint if it has one
+func printTimelineEntry(entry types.AppTimelineEntry) {
+	subject := "app"
+	if entry.ServiceName != "" {
+		subject = entry.ServiceName
+	}
+
+	line := fmt.Sprintf("[%s] %-16s %-12s %s", entry.Time.Local().Format(time.DateTime), entry.Type, subject, entry.Reason)
+	if entry.Message != "" {
+		line = fmt.Sprintf("%s: %s", line, entry.Message)
+	}
+	if entry.Count > 1 {
+		line = fmt.Sprintf("%s (x%d)", line, entry.Count)
+	}
+
+	switch {
+	case entry.Severity == types.AppTimelineSeverity_Warning:
+		color.New(color.FgRed).Println(line) // nolint:errcheck,gosec
+	case entry.Type == types.AppTimelineEntryType_Revision:
+		color.New(color.FgGreen).Println(line) // nolint:errcheck,gosec
+	default:
+		fmt.Println(line)
+	}
+
+	for _, change := range entry.Changes {
+		fmt.Printf("    - %s\n", change.Description)
+	}
+
+	if entry.Hint != "" {
+		color.New(color.FgYellow).Printf("    hint: %s\n", entry.Hint) // nolint:errcheck,gosec
+	}
+}
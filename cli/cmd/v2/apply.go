@@ -52,6 +52,31 @@ type ApplyInput struct {
 	PatchOperations []v2.PatchOperation
 	// SkipBuild is true when Apply should skip the build step
 	SkipBuild bool
+	// Reseed is true when the seed steps of a preview environment should run even if it has already been seeded
+	Reseed bool
+}
+
+// ValidatePorterYaml returns the errors in a v2 porter.yaml which would fail an apply before the app is updated
+func ValidatePorterYaml(ctx context.Context, porterYaml []byte) []error {
+	var errs []error
+
+	if _, err := v2.AppProtoFromYaml(ctx, porterYaml); err != nil {
+		errs = append(errs, fmt.Errorf("error parsing porter yaml: %w", err))
+	}
+
+	if _, err := v2.AppSLOsFromYaml(ctx, porterYaml); err != nil {
+		errs = append(errs, fmt.Errorf("error parsing slos from porter yaml: %w", err))
+	}
+
+	if _, err := v2.AppCanariesFromYaml(ctx, porterYaml); err != nil {
+		errs = append(errs, fmt.Errorf("error parsing canaries from porter yaml: %w", err))
+	}
+
+	if _, err := v2.AppPreviewSeedFromYaml(ctx, porterYaml); err != nil {
+		errs = append(errs, fmt.Errorf("error parsing preview seed from porter yaml: %w", err))
+	}
+
+	return errs
 }
 
 // Apply implements the functionality of the `porter apply` command for validate apply v2 projects
@@ -89,6 +114,13 @@ func Apply(ctx context.Context, inp ApplyInput) error {
 			return fmt.Errorf("error parsing PORTER_PR_NUMBER to int: %w", err)
 		}
 		prNumber = parsedPRInt
+	} else if os.Getenv("CI_MERGE_REQUEST_IID") != "" {
+		// set by GitLab CI in merge request pipelines
+		parsedPRInt, err := strconv.Atoi(os.Getenv("CI_MERGE_REQUEST_IID"))
+		if err != nil {
+			return fmt.Errorf("error parsing CI_MERGE_REQUEST_IID to int: %w", err)
+		}
+		prNumber = parsedPRInt
 	}
 
 	deploymentTargetID, err := deploymentTargetFromConfig(ctx, client, cliConf.Project, cliConf.Cluster, inp.PreviewApply, prNumber)
@@ -112,6 +144,10 @@ func Apply(ctx context.Context, inp ApplyInput) error {
 	}
 
 	var b64YAML string
+	var slos []types.AppSLOSpec
+	var canaries []types.AppCanarySpec
+	var seedSteps []types.PreviewSeedStep
+	var previewAddons []string
 	if porterYamlExists {
 		porterYaml, err := os.ReadFile(filepath.Clean(inp.PorterYamlPath))
 		if err != nil {
@@ -120,6 +156,27 @@ func Apply(ctx context.Context, inp ApplyInput) error {
 
 		b64YAML = base64.StdEncoding.EncodeToString(porterYaml)
 		color.New(color.FgGreen).Printf("Using Porter YAML at path: %s\n", inp.PorterYamlPath) // nolint:errcheck,gosec
+
+		// SLOs are parsed before the app is updated so that invalid objectives fail the apply early
+		slos, err = v2.AppSLOsFromYaml(ctx, porterYaml)
+		if err != nil {
+			return fmt.Errorf("error parsing slos from porter yaml: %w", err)
+		}
+
+		canaries, err = v2.AppCanariesFromYaml(ctx, porterYaml)
+		if err != nil {
+			return fmt.Errorf("error parsing canaries from porter yaml: %w", err)
+		}
+
+		seedSteps, err = v2.AppPreviewSeedFromYaml(ctx, porterYaml)
+		if err != nil {
+			return fmt.Errorf("error parsing preview seed from porter yaml: %w", err)
+		}
+
+		previewAddons, err = v2.AppPreviewAddonNamesFromYaml(ctx, porterYaml)
+		if err != nil {
+			return fmt.Errorf("error parsing preview addons from porter yaml: %w", err)
+		}
 	}
 
 	var commitSHA string
@@ -244,6 +301,26 @@ func Apply(ctx context.Context, inp ApplyInput) error {
 			return buildError
 		}
 
+		// the revision is rolled out as soon as the build is reported as successful, so canaries run first. A rolled
+		// back canary fails the revision, so that the new image is never rolled out.
+		if len(canaries) > 0 && !inp.PreviewApply {
+			err = runCanaries(ctx, runCanariesInput{
+				Client:             client,
+				ProjectID:          cliConf.Project,
+				ClusterID:          cliConf.Cluster,
+				AppName:            appName,
+				DeploymentTargetID: deploymentTargetID,
+				AppRevisionID:      updateResp.AppRevisionId,
+				ImageRepository:    buildSettings.Image.Repository,
+				ImageTag:           commitSHA,
+				Canaries:           canaries,
+			})
+			if err != nil {
+				buildError = fmt.Errorf("error running canaries: %w", err)
+				return buildError
+			}
+		}
+
 		_, err = client.UpdateRevisionStatus(ctx, cliConf.Project, cliConf.Cluster, appName, updateResp.AppRevisionId, models.AppRevisionStatus_BuildSuccessful)
 		if err != nil {
 			buildError = fmt.Errorf("error updating revision status post build: %w", err)
@@ -256,6 +333,8 @@ func Apply(ctx context.Context, inp ApplyInput) error {
 		buildMetadata["end_time"] = time.Now().UTC()
 		_ = updateExistingEvent(ctx, client, appName, cliConf.Project, cliConf.Cluster, deploymentTargetID, types.PorterAppEventType_Build, eventID, types.PorterAppEventStatus_Success, buildMetadata)
 		buildFinished = true
+	} else if len(canaries) > 0 && !inp.PreviewApply {
+		color.New(color.FgYellow).Printf("Warning: canaries only run for images built during apply, so the new revision is rolled out without a canary\n") // nolint:errcheck,gosec
 	}
 
 	color.New(color.FgGreen).Printf("Deploying new revision %s for app %s...\n", updateResp.AppRevisionId, appName) // nolint:errcheck,gosec
@@ -318,14 +397,69 @@ func Apply(ctx context.Context, inp ApplyInput) error {
 
 	color.New(color.FgGreen).Printf("Successfully applied new revision %s\n", updateResp.AppRevisionId) // nolint:errcheck,gosec
 
-	if inp.WaitForSuccessfulDeployment {
-		return waitForAppRevisionStatus(ctx, waitForAppRevisionStatusInput{
+	// preview environments are not expected to meet objectives, so their SLOs are not synced
+	if slos != nil && !inp.PreviewApply {
+		_, err := client.UpdateAppSLOs(ctx, cliConf.Project, cliConf.Cluster, appName, &types.UpdateAppSLOsRequest{
+			DeploymentTargetID: deploymentTargetID,
+			Source:             types.AppSLOSource_PorterYAML,
+			SLOs:               slos,
+		})
+		if err != nil {
+			// the new revision has already been deployed, so a failure to sync SLOs does not fail the apply
+			color.New(color.FgYellow).Printf("Warning: could not update SLOs from porter yaml: %s\n", err.Error()) // nolint:errcheck,gosec
+		} else if len(slos) > 0 {
+			color.New(color.FgGreen).Printf("Updated %d SLOs from porter yaml\n", len(slos)) // nolint:errcheck,gosec
+		}
+	}
+
+	seed := len(seedSteps) > 0 && inp.PreviewApply
+
+	// seed steps run against the deployed revision, so it is always waited for before seeding
+	if inp.WaitForSuccessfulDeployment || seed {
+		err := waitForAppRevisionStatus(ctx, waitForAppRevisionStatusInput{
 			ProjectID:  cliConf.Project,
 			ClusterID:  cliConf.Cluster,
 			AppName:    appName,
 			RevisionID: updateResp.AppRevisionId,
 			Client:     client,
 		})
+		if err != nil {
+			if seed {
+				return fmt.Errorf("preview environment was not seeded: %w", err)
+			}
+			return err
+		}
+	}
+
+	if seed {
+		// seed steps run once the addons of the preview environment are ready, since they usually load data into them
+		err := waitForPreviewAddons(ctx, waitForPreviewAddonsInput{
+			Client:             client,
+			ProjectID:          cliConf.Project,
+			ClusterID:          cliConf.Cluster,
+			DeploymentTargetID: deploymentTargetID,
+			AddonNames:         previewAddons,
+		})
+		if err != nil {
+			return fmt.Errorf("preview environment was not seeded: %w", err)
+		}
+
+		err = seedPreview(ctx, seedPreviewInput{
+			Client:             client,
+			ProjectID:          cliConf.Project,
+			ClusterID:          cliConf.Cluster,
+			AppName:            appName,
+			DeploymentTargetID: deploymentTargetID,
+			PRNumber:           prNumber,
+			CommitSHA:          commitSHA,
+			Steps:              seedSteps,
+			Reseed:             inp.Reseed,
+		})
+		if err != nil {
+			return fmt.Errorf("error seeding preview environment: %w", err)
+		}
+	} else if inp.Reseed {
+		color.New(color.FgYellow).Printf("Warning: no seed steps are declared in the previews section of the porter yaml, so there is nothing to re-seed\n") // nolint:errcheck,gosec
 	}
 
 	return nil
@@ -368,6 +502,8 @@ func deploymentTargetFromConfig(ctx context.Context, client api.Client, projectI
 			branchName = os.Getenv("GITHUB_HEAD_REF")
 		} else if os.Getenv("GITHUB_REF_NAME") != "" {
 			branchName = os.Getenv("GITHUB_REF_NAME")
+		} else if os.Getenv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME") != "" {
+			branchName = os.Getenv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME")
 		} else if branch, err := git.CurrentBranch(); err == nil {
 			branchName = branch
 		}
@@ -381,6 +517,8 @@ func deploymentTargetFromConfig(ctx context.Context, client api.Client, projectI
 			repository = os.Getenv("PORTER_REPO_NAME")
 		} else if os.Getenv("GITHUB_REPOSITORY") != "" {
 			repository = os.Getenv("GITHUB_REPOSITORY")
+		} else if os.Getenv("CI_PROJECT_PATH") != "" {
+			repository = os.Getenv("CI_PROJECT_PATH")
 		}
 
 		targetResp, err := client.CreateDeploymentTarget(ctx, projectID, &types.CreateDeploymentTargetRequest{
@@ -0,0 +1,196 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	v2 "github.com/porter-dev/porter/internal/porter_app/v2"
+)
+
+// ApplyAppsInput is the input for the ApplyApps function
+type ApplyAppsInput struct {
+	// ApplyInput holds the options which are used to apply each app. Its PorterYamlPath and AppName are set from the porter.yaml files
+	ApplyInput
+	// PorterYamlPaths are the paths to the porter.yaml files of the apps, or to directories containing them
+	PorterYamlPaths []string
+}
+
+// ApplyApps applies multiple apps in the order of their dependencies, which are declared in requiredApps or referenced by env
+// variables from another app. Apps which do not depend on each other are applied in parallel, and each layer of apps must be
+// healthy before the apps which depend on them are applied. If an app fails to apply, the apps which depend on it are skipped.
+func ApplyApps(ctx context.Context, inp ApplyAppsInput) error {
+	paths, err := PorterYamlPaths(ctx, inp.PorterYamlPaths)
+	if err != nil {
+		return err
+	}
+
+	if len(paths) == 0 {
+		return errors.New("no porter yaml files found")
+	}
+
+	apps := make([]v2.AppDependencies, 0, len(paths))
+	pathsByApp := make(map[string]string, len(paths))
+	dependsOn := make(map[string][]string, len(paths))
+
+	for _, path := range paths {
+		porterYaml, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return fmt.Errorf("could not read porter yaml file %s: %w", path, err)
+		}
+
+		app, err := v2.AppDependenciesFromYaml(ctx, porterYaml)
+		if err != nil {
+			return fmt.Errorf("error reading dependencies from porter yaml %s: %w", path, err)
+		}
+
+		if existing, ok := pathsByApp[app.Name]; ok {
+			return fmt.Errorf("app %s is declared in both %s and %s", app.Name, existing, path)
+		}
+
+		apps = append(apps, app)
+		pathsByApp[app.Name] = path
+		dependsOn[app.Name] = app.DependsOn
+	}
+
+	layers, err := v2.DeployLayers(apps)
+	if err != nil {
+		return fmt.Errorf("error ordering apps: %w", err)
+	}
+
+	color.New(color.FgGreen).Printf("Applying %d apps in %d layers:\n", len(apps), len(layers)) // nolint:errcheck,gosec
+	for i, layer := range layers {
+		fmt.Printf("  %d. %s\n", i+1, strings.Join(layer, ", "))
+	}
+
+	// failed holds the reason each app was not applied, including apps which were skipped because of a failed dependency
+	failed := make(map[string]error)
+
+	for i, layer := range layers {
+		var toApply []string
+
+		for _, name := range layer {
+			if dep := failedDependency(dependsOn[name], failed); dep != "" {
+				failed[name] = fmt.Errorf("skipped because %s was not applied", dep)
+				color.New(color.FgYellow).Printf("Skipping app %s because %s was not applied\n", name, dep) // nolint:errcheck,gosec
+				continue
+			}
+
+			toApply = append(toApply, name)
+		}
+
+		var (
+			mu sync.Mutex
+			wg sync.WaitGroup
+		)
+
+		for _, name := range toApply {
+			applyInp := inp.ApplyInput
+			applyInp.AppName = name
+			applyInp.PorterYamlPath = pathsByApp[name]
+			// every layer but the last must be healthy before the apps which depend on it are applied
+			applyInp.WaitForSuccessfulDeployment = inp.WaitForSuccessfulDeployment || i < len(layers)-1
+
+			wg.Add(1)
+
+			go func(name string, applyInp ApplyInput) {
+				defer wg.Done()
+
+				err := Apply(ctx, applyInp)
+				if err == nil {
+					return
+				}
+
+				color.New(color.FgRed).Printf("Error applying app %s: %s\n", name, err.Error()) // nolint:errcheck,gosec
+
+				mu.Lock()
+				failed[name] = err
+				mu.Unlock()
+			}(name, applyInp)
+		}
+
+		wg.Wait()
+	}
+
+	if len(failed) == 0 {
+		color.New(color.FgGreen).Printf("Successfully applied %d apps\n", len(apps)) // nolint:errcheck,gosec
+		return nil
+	}
+
+	names := make([]string, 0, len(failed))
+	for name := range failed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	errs := make([]string, 0, len(names))
+	for _, name := range names {
+		errs = append(errs, fmt.Sprintf("%s: %s", name, failed[name].Error()))
+	}
+
+	return fmt.Errorf("%d of %d apps were not applied:\n  %s", len(failed), len(apps), strings.Join(errs, "\n  "))
+}
+
+// failedDependency returns the first of the given dependencies which was not applied, or an empty string if none failed
+func failedDependency(dependsOn []string, failed map[string]error) string {
+	for _, dep := range dependsOn {
+		if _, ok := failed[dep]; ok {
+			return dep
+		}
+	}
+
+	return ""
+}
+
+// PorterYamlPaths expands directories in the given paths to the porter.yaml files they contain. Yaml files in a directory which
+// are not v2 porter.yaml files with an app name, such as CI or kubernetes manifests, are skipped with a warning. Files which are
+// given directly are always kept, so that they fail with an error if they are not valid.
+func PorterYamlPaths(ctx context.Context, paths []string) ([]string, error) {
+	var expanded []string
+
+	for _, path := range paths {
+		info, err := os.Stat(filepath.Clean(path))
+		if err != nil {
+			return nil, fmt.Errorf("error checking porter yaml path %s: %w", path, err)
+		}
+
+		if !info.IsDir() {
+			expanded = append(expanded, path)
+			continue
+		}
+
+		entries, err := os.ReadDir(filepath.Clean(path))
+		if err != nil {
+			return nil, fmt.Errorf("error reading porter yaml directory %s: %w", path, err)
+		}
+
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+				continue
+			}
+
+			file := filepath.Join(path, entry.Name())
+
+			porterYaml, err := os.ReadFile(filepath.Clean(file))
+			if err != nil {
+				return nil, fmt.Errorf("could not read porter yaml file %s: %w", file, err)
+			}
+
+			if _, err := v2.AppDependenciesFromYaml(ctx, porterYaml); err != nil {
+				color.New(color.FgYellow).Printf("Warning: skipping %s, which is not a v2 porter.yaml with an app name: %s\n", file, err.Error()) // nolint:errcheck,gosec
+				continue
+			}
+
+			expanded = append(expanded, file)
+		}
+	}
+
+	return expanded, nil
+}
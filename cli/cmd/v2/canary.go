@@ -0,0 +1,140 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+)
+
+const (
+	// canaryPollFrequency is how often the progress of running canaries is checked
+	canaryPollFrequency = 15 * time.Second
+	// canaryWaitMargin is added to the duration of the longest canary, to allow for the canary pods to start
+	canaryWaitMargin = 20 * time.Minute
+)
+
+// runCanariesInput is the input for the runCanaries function
+type runCanariesInput struct {
+	Client             api.Client
+	ProjectID          uint
+	ClusterID          uint
+	AppName            string
+	DeploymentTargetID string
+	AppRevisionID      string
+	ImageRepository    string
+	ImageTag           string
+	Canaries           []types.AppCanarySpec
+}
+
+// runCanaries sends a fraction of the traffic of each web service with a canary to the new image, and prints the
+// analysis of each step until every canary is promoted or rolled back. An error is returned if any canary was
+// rolled back, in which case the new image must not be rolled out.
+func runCanaries(ctx context.Context, inp runCanariesInput) error {
+	resp, err := inp.Client.CreateAppCanaries(ctx, inp.ProjectID, inp.ClusterID, inp.AppName, &types.CreateAppCanariesRequest{
+		DeploymentTargetID: inp.DeploymentTargetID,
+		AppRevisionID:      inp.AppRevisionID,
+		ImageRepository:    inp.ImageRepository,
+		ImageTag:           inp.ImageTag,
+		Canaries:           inp.Canaries,
+	})
+	if err != nil {
+		return fmt.Errorf("error starting canaries: %w", err)
+	}
+
+	if len(resp.Canaries) == 0 {
+		color.New(color.FgYellow).Printf("No services with canaries are deployed yet, so the new image is rolled out without a canary\n") // nolint:errcheck,gosec
+		return nil
+	}
+
+	var longest time.Duration
+	pending := make(map[string]types.AppCanary, len(resp.Canaries))
+	printedSteps := make(map[string]int, len(resp.Canaries))
+
+	for _, canary := range resp.Canaries {
+		var duration time.Duration
+		plan := make([]string, 0, len(canary.Spec.Steps))
+		for _, step := range canary.Spec.Steps {
+			stepDuration := time.Duration(step.DurationSeconds) * time.Second
+			duration += stepDuration
+			plan = append(plan, fmt.Sprintf("%d%% for %s", step.Weight, stepDuration))
+		}
+
+		if duration > longest {
+			longest = duration
+		}
+
+		color.New(color.FgGreen).Printf("Starting canary of %s for service %s: %s\n", canary.Image, canary.Spec.ServiceName, strings.Join(plan, ", ")) // nolint:errcheck,gosec
+		pending[canary.EventID] = canary
+	}
+
+	deadline := time.Now().Add(longest + canaryWaitMargin)
+	var rolledBack []string
+
+	for len(pending) > 0 {
+		if time.Now().After(deadline) {
+			return errors.New("timed out waiting for canaries to complete")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(canaryPollFrequency):
+		}
+
+		for eventID, canary := range pending {
+			resp, err := inp.Client.GetAppCanary(ctx, inp.ProjectID, inp.ClusterID, inp.AppName, eventID)
+			if err != nil {
+				return fmt.Errorf("error getting canary of service %s: %w", canary.Spec.ServiceName, err)
+			}
+
+			for _, step := range resp.Canary.Steps[printedSteps[eventID]:] {
+				printCanaryStep(canary.Spec.ServiceName, step)
+			}
+			printedSteps[eventID] = len(resp.Canary.Steps)
+
+			switch resp.Canary.Result {
+			case types.AppCanaryResult_Promoted:
+				color.New(color.FgGreen).Printf("Canary of service %s was promoted\n", canary.Spec.ServiceName) // nolint:errcheck,gosec
+				delete(pending, eventID)
+			case types.AppCanaryResult_RolledBack:
+				color.New(color.FgRed).Printf("Canary of service %s was rolled back: %s\n", canary.Spec.ServiceName, resp.Canary.Reason) // nolint:errcheck,gosec
+				rolledBack = append(rolledBack, fmt.Sprintf("%s (%s)", canary.Spec.ServiceName, resp.Canary.Reason))
+				delete(pending, eventID)
+			}
+		}
+	}
+
+	if len(rolledBack) > 0 {
+		return fmt.Errorf("canaries were rolled back for services: %s", strings.Join(rolledBack, ", "))
+	}
+
+	return nil
+}
+
+// printCanaryStep prints the comparison between the canary and stable image at the end of a step
+func printCanaryStep(serviceName string, step types.AppCanaryStepResult) {
+	outcome := color.New(color.FgGreen).Sprint("passed")
+	switch {
+	case !step.Passed:
+		outcome = color.New(color.FgRed).Sprint("failed")
+	case step.Inconclusive:
+		outcome = color.New(color.FgYellow).Sprint("inconclusive")
+	}
+
+	fmt.Printf("[%s] %d%% of traffic: canary %.0f requests, %.2f%% errors, p95 %.0fms; stable %.0f requests, %.2f%% errors, p95 %.0fms: %s\n",
+		serviceName, step.Weight,
+		step.Canary.Requests, step.Canary.ErrorRate*100, step.Canary.LatencyP95Ms,
+		step.Stable.Requests, step.Stable.ErrorRate*100, step.Stable.LatencyP95Ms,
+		outcome,
+	)
+
+	if step.Reason != "" {
+		fmt.Printf("[%s] %s\n", serviceName, step.Reason)
+	}
+}
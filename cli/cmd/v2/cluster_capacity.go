@@ -0,0 +1,146 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/cli/cmd/config"
+	v2 "github.com/porter-dev/porter/internal/porter_app/v2"
+)
+
+// ClusterCapacityInput is the input for the ClusterCapacity function
+type ClusterCapacityInput struct {
+	// CLIConfig is the CLI configuration
+	CLIConfig config.CLIConfig
+	// Client is the Porter API client
+	Client api.Client
+	// PorterYamlPath is the path to the porter.yaml whose services are simulated
+	PorterYamlPath string
+	// DeploymentTargetName is the name of the deployment target the app is applied to. If empty, the default deployment
+	// target of the cluster is used
+	DeploymentTargetName string
+}
+
+// ClusterCapacity simulates scheduling the services of a porter.yaml onto the current nodes of the cluster, replacing
+// the pods of the app if it is already deployed. An error is returned if any pod would not fit onto the current nodes.
+func ClusterCapacity(ctx context.Context, inp ClusterCapacityInput) error {
+	if inp.PorterYamlPath == "" {
+		return errors.New("--simulate must be set to the path of a porter.yaml")
+	}
+
+	porterYaml, err := os.ReadFile(filepath.Clean(inp.PorterYamlPath))
+	if err != nil {
+		return fmt.Errorf("error reading porter.yaml: %w", err)
+	}
+
+	appName, pods, err := v2.AppCapacityFromYaml(ctx, porterYaml)
+	if err != nil {
+		return fmt.Errorf("error parsing porter.yaml: %w", err)
+	}
+
+	if len(pods) == 0 {
+		color.New(color.FgYellow).Printf("No web or worker services with instances were found in %s\n", inp.PorterYamlPath) // nolint:errcheck,gosec
+		return nil
+	}
+
+	deploymentTargetID, err := capacityDeploymentTargetID(ctx, inp)
+	if err != nil {
+		return err
+	}
+
+	resp, err := inp.Client.SimulateClusterCapacity(ctx, inp.CLIConfig.Project, inp.CLIConfig.Cluster, &types.SimulateClusterCapacityRequest{
+		AppName:            appName,
+		DeploymentTargetID: deploymentTargetID,
+		Pods:               pods,
+	})
+	if err != nil {
+		return fmt.Errorf("error simulating cluster capacity: %w", err)
+	}
+
+	podCount := 0
+	for _, pod := range pods {
+		podCount += pod.Replicas
+	}
+
+	color.New(color.FgGreen).Printf("Simulated scheduling %d pods of %d services of app %s\n\n", podCount, len(pods), appName) // nolint:errcheck,gosec
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintf(w, "NODE\tINSTANCE TYPE\tNEW PODS\tCPU\tMEMORY\n") // nolint:errcheck,gosec
+	for _, node := range resp.Nodes {
+		name := node.Name
+		if node.Extra {
+			name = fmt.Sprintf("%s (extra)", name)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%d\t%.1f%% of %s\t%.1f%% of %s\n", // nolint:errcheck,gosec
+			name, node.InstanceType, node.SimulatedPods,
+			node.Utilization.CPUPercent, formatCPUMillis(node.CPUMillisAllocatable),
+			node.Utilization.MemoryPercent, formatMemoryBytes(node.MemoryBytesAllocatable),
+		)
+	}
+	w.Flush() // nolint:errcheck,gosec
+
+	fmt.Printf("\nCPU requested:    %.1f%% -> %.1f%%\n", resp.CurrentUtilization.CPUPercent, resp.ProjectedUtilization.CPUPercent)
+	fmt.Printf("Memory requested: %.1f%% -> %.1f%%\n\n", resp.CurrentUtilization.MemoryPercent, resp.ProjectedUtilization.MemoryPercent)
+
+	if resp.Fits {
+		color.New(color.FgGreen).Println("All pods fit onto the current nodes") // nolint:errcheck,gosec
+		return nil
+	}
+
+	for _, unschedulable := range resp.UnschedulablePods {
+		color.New(color.FgRed).Printf("%d pods of %s do not fit onto the current nodes: %s\n", unschedulable.Count, unschedulable.Name, unschedulable.Reason) // nolint:errcheck,gosec
+		if !unschedulable.FitsExtraNodes {
+			color.New(color.FgRed).Printf("Some pods of %s do not fit onto an empty node of any instance type in the cluster\n", unschedulable.Name) // nolint:errcheck,gosec
+		}
+	}
+
+	for _, extra := range resp.ExtraNodes {
+		color.New(color.FgYellow).Printf("Extra nodes needed: %d x %s\n", extra.Count, extra.InstanceType) // nolint:errcheck,gosec
+	}
+
+	return errors.New("pods do not fit onto the current nodes of the cluster")
+}
+
+// capacityDeploymentTargetID returns the id of the deployment target that the simulated app is applied to
+func capacityDeploymentTargetID(ctx context.Context, inp ClusterCapacityInput) (string, error) {
+	if inp.DeploymentTargetName == "" {
+		resp, err := inp.Client.DefaultDeploymentTarget(ctx, inp.CLIConfig.Project, inp.CLIConfig.Cluster)
+		if err != nil {
+			return "", fmt.Errorf("error getting default deployment target: %w", err)
+		}
+
+		return resp.DeploymentTargetID, nil
+	}
+
+	resp, err := inp.Client.ListDeploymentTargets(ctx, inp.CLIConfig.Project, true)
+	if err != nil {
+		return "", fmt.Errorf("error listing deployment targets: %w", err)
+	}
+
+	for _, target := range resp.DeploymentTargets {
+		if target.Name == inp.DeploymentTargetName && target.ClusterID == inp.CLIConfig.Cluster {
+			return target.ID.String(), nil
+		}
+	}
+
+	return "", fmt.Errorf("deployment target %s not found in cluster %d", inp.DeploymentTargetName, inp.CLIConfig.Cluster)
+}
+
+// formatCPUMillis formats millicores as cores
+func formatCPUMillis(millis int64) string {
+	return fmt.Sprintf("%.2f cores", float64(millis)/1000)
+}
+
+// formatMemoryBytes formats bytes as gibibytes
+func formatMemoryBytes(bytes int64) string {
+	return fmt.Sprintf("%.1fGi", float64(bytes)/(1024*1024*1024))
+}
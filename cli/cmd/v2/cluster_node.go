@@ -0,0 +1,151 @@
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/gorilla/websocket"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/cli/cmd/config"
+)
+
+// NodeMaintenanceInput is the input for the node maintenance functions
+type NodeMaintenanceInput struct {
+	// CLIConfig is the CLI configuration
+	CLIConfig config.CLIConfig
+	// Client is the Porter API client
+	Client api.Client
+	// NodeNames are the nodes to operate on. Drains, cordons and uncordons take exactly one node
+	NodeNames []string
+	// NodeSelector is a label selector for the nodes to rotate, which may be used instead of NodeNames
+	NodeSelector string
+	// Timeout bounds a drain, or each node of a rotation. If zero, the server default is used
+	Timeout time.Duration
+	// Force evicts pods which are not managed by a controller
+	Force bool
+}
+
+// CordonNode stops new pods from being scheduled onto a node
+func CordonNode(ctx context.Context, inp NodeMaintenanceInput) error {
+	if len(inp.NodeNames) != 1 {
+		return errors.New("exactly one node must be specified")
+	}
+
+	if _, err := inp.Client.CordonNode(ctx, inp.CLIConfig.Project, inp.CLIConfig.Cluster, inp.NodeNames[0]); err != nil {
+		return fmt.Errorf("error cordoning node: %w", err)
+	}
+
+	color.New(color.FgGreen).Printf("Cordoned node %s\n", inp.NodeNames[0]) // nolint:errcheck,gosec
+	return nil
+}
+
+// UncordonNode allows new pods to be scheduled onto a node again
+func UncordonNode(ctx context.Context, inp NodeMaintenanceInput) error {
+	if len(inp.NodeNames) != 1 {
+		return errors.New("exactly one node must be specified")
+	}
+
+	if _, err := inp.Client.UncordonNode(ctx, inp.CLIConfig.Project, inp.CLIConfig.Cluster, inp.NodeNames[0]); err != nil {
+		return fmt.Errorf("error uncordoning node: %w", err)
+	}
+
+	color.New(color.FgGreen).Printf("Uncordoned node %s\n", inp.NodeNames[0]) // nolint:errcheck,gosec
+	return nil
+}
+
+// DrainNode cordons a node and evicts its pods, printing the progress of the drain
+func DrainNode(ctx context.Context, inp NodeMaintenanceInput) error {
+	if len(inp.NodeNames) != 1 {
+		return errors.New("exactly one node must be specified")
+	}
+
+	conn, err := inp.Client.DrainNodeStream(ctx, inp.CLIConfig.Project, inp.CLIConfig.Cluster, inp.NodeNames[0], &types.DrainNodeRequest{
+		TimeoutSeconds: int(inp.Timeout.Seconds()),
+		Force:          inp.Force,
+	})
+	if err != nil {
+		return fmt.Errorf("error starting drain: %w", err)
+	}
+	defer conn.Close() // nolint:errcheck
+
+	color.New(color.FgGreen).Printf("Draining node %s...\n", inp.NodeNames[0]) // nolint:errcheck,gosec
+
+	return printNodeMaintenanceEvents(conn)
+}
+
+// RotateNodes drains nodes one at a time, waiting for their app pods to be ready on other nodes in between, and
+// prints the progress of the rotation
+func RotateNodes(ctx context.Context, inp NodeMaintenanceInput) error {
+	if (len(inp.NodeNames) == 0) == (inp.NodeSelector == "") {
+		return errors.New("either node names or --selector must be specified")
+	}
+
+	conn, err := inp.Client.RotateNodesStream(ctx, inp.CLIConfig.Project, inp.CLIConfig.Cluster, &types.RotateNodesRequest{
+		NodeNames:      inp.NodeNames,
+		NodeSelector:   inp.NodeSelector,
+		TimeoutSeconds: int(inp.Timeout.Seconds()),
+		Force:          inp.Force,
+	})
+	if err != nil {
+		return fmt.Errorf("error starting rotation: %w", err)
+	}
+	defer conn.Close() // nolint:errcheck
+
+	color.New(color.FgGreen).Println("Rotating nodes...") // nolint:errcheck,gosec
+
+	return printNodeMaintenanceEvents(conn)
+}
+
+// printNodeMaintenanceEvents prints the events of a node maintenance operation until it completes or fails. Closing
+// the CLI does not stop the operation, which continues on the server until it completes or times out.
+func printNodeMaintenanceEvents(conn *websocket.Conn) error {
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("connection to the server was lost, the operation may still be running: %w", err)
+		}
+
+		var event types.NodeMaintenanceEvent
+		if err := json.Unmarshal(message, &event); err != nil {
+			// silently skip messages which are not events
+			continue
+		}
+
+		switch event.Type {
+		case types.NodeMaintenanceEventType_Completed:
+			color.New(color.FgGreen).Println("Completed") // nolint:errcheck,gosec
+			return nil
+		case types.NodeMaintenanceEventType_Failed:
+			return errors.New(event.Message)
+		}
+
+		printNodeMaintenanceEvent(event)
+	}
+}
+
+// printNodeMaintenanceEvent prints a single progress event
+func printNodeMaintenanceEvent(event types.NodeMaintenanceEvent) {
+	target := event.Node
+	if event.Pod != "" {
+		target = fmt.Sprintf("%s: pod %s/%s", event.Node, event.Namespace, event.Pod)
+	}
+
+	line := fmt.Sprintf("[%s] %s %s", event.Time.Local().Format(time.Kitchen), target, event.Type)
+	if event.Message != "" {
+		line = fmt.Sprintf("%s (%s)", line, event.Message)
+	}
+
+	switch event.Type {
+	case types.NodeMaintenanceEventType_EvictionBlocked:
+		color.New(color.FgYellow).Println(line) // nolint:errcheck,gosec
+	case types.NodeMaintenanceEventType_Drained, types.NodeMaintenanceEventType_PodsReady:
+		color.New(color.FgGreen).Println(line) // nolint:errcheck,gosec
+	default:
+		fmt.Println(line)
+	}
+}
@@ -0,0 +1,380 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/datastore"
+	"github.com/porter-dev/porter/internal/models"
+	porter_app_internal "github.com/porter-dev/porter/internal/porter_app"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// seedPollFrequency is how often the progress of a seed step is checked
+	seedPollFrequency = 10 * time.Second
+	// seedAddonsTimeout is how long the addons of a preview environment may take to become ready before it is seeded
+	seedAddonsTimeout = 15 * time.Minute
+)
+
+// waitForPreviewAddonsInput is the input for the waitForPreviewAddons function
+type waitForPreviewAddonsInput struct {
+	Client             api.Client
+	ProjectID          uint
+	ClusterID          uint
+	DeploymentTargetID string
+	AddonNames         []string
+}
+
+// waitForPreviewAddons waits until every pod of each addon of a preview environment is ready, so that seed steps do
+// not run against addons which are still starting. An error is returned if an addon is not ready within seedAddonsTimeout.
+func waitForPreviewAddons(ctx context.Context, inp waitForPreviewAddonsInput) error {
+	if len(inp.AddonNames) == 0 {
+		return nil
+	}
+
+	targets, err := inp.Client.ListDeploymentTargets(ctx, inp.ProjectID, true)
+	if err != nil {
+		return fmt.Errorf("error getting preview environment: %w", err)
+	}
+
+	var namespace string
+	for _, target := range targets.DeploymentTargets {
+		if target.ID.String() == inp.DeploymentTargetID {
+			namespace = target.Namespace
+			break
+		}
+	}
+	if namespace == "" {
+		return fmt.Errorf("unable to find namespace of preview environment %s", inp.DeploymentTargetID)
+	}
+
+	color.New(color.FgGreen).Printf("Waiting up to %s for %d addons of the preview environment to be ready...\n", seedAddonsTimeout, len(inp.AddonNames)) // nolint:errcheck,gosec
+
+	ctx, cancel := context.WithTimeout(ctx, seedAddonsTimeout)
+	defer cancel()
+
+	for {
+		var notReady string
+
+		for _, name := range inp.AddonNames {
+			// an addon which has not been installed yet has no release, so errors are retried until the timeout
+			pods, err := inp.Client.GetK8sAllPods(ctx, inp.ProjectID, inp.ClusterID, namespace, name)
+			if err != nil || !addonPodsReady(*pods) {
+				notReady = name
+				break
+			}
+		}
+
+		if notReady == "" {
+			color.New(color.FgGreen).Printf("All addons of the preview environment are ready\n") // nolint:errcheck,gosec
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("addon %s was not ready within %s: %w", notReady, seedAddonsTimeout, ctx.Err())
+		case <-time.After(seedPollFrequency):
+		}
+	}
+}
+
+// addonPodsReady returns true if an addon has at least one pod, and all of its pods which are not completed or
+// terminating are running and ready
+func addonPodsReady(pods []v1.Pod) bool {
+	var ready int
+
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == v1.PodSucceeded {
+			continue
+		}
+
+		if pod.Status.Phase != v1.PodRunning {
+			return false
+		}
+
+		var podReady bool
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == v1.PodReady {
+				podReady = condition.Status == v1.ConditionTrue
+			}
+		}
+		if !podReady {
+			return false
+		}
+		ready++
+	}
+
+	return ready > 0
+}
+
+// seedPreviewInput is the input for the seedPreview function
+type seedPreviewInput struct {
+	Client             api.Client
+	ProjectID          uint
+	ClusterID          uint
+	AppName            string
+	DeploymentTargetID string
+	PRNumber           int
+	CommitSHA          string
+	Steps              []types.PreviewSeedStep
+	// Reseed runs the steps even if the preview environment has already been seeded
+	Reseed bool
+}
+
+// seedPreview runs the seed steps of a preview environment in order, and reports their results so that they are
+// commented on the pull request. A preview environment is only seeded once unless a re-seed is requested, or the
+// previous seed did not succeed. An error is returned if any step did not succeed.
+func seedPreview(ctx context.Context, inp seedPreviewInput) error {
+	previous, err := inp.Client.GetPreviewSeed(ctx, inp.ProjectID, inp.ClusterID, inp.AppName, inp.DeploymentTargetID)
+	if err != nil {
+		return fmt.Errorf("error getting previous seed: %w", err)
+	}
+
+	// clones are not replaced when re-seeding, since the datastore which was cloned into the preview already exists
+	cloned := make(map[string]bool)
+
+	if previous.Seed != nil {
+		if previous.Seed.Succeeded && !inp.Reseed {
+			color.New(color.FgGreen).Printf("Preview environment was already seeded at %s, use --reseed to seed it again\n", previous.Seed.CreatedAt.Format(time.RFC1123)) // nolint:errcheck,gosec
+			return nil
+		}
+
+		for _, step := range previous.Seed.Steps {
+			if step.Type == types.PreviewSeedStepType_CloneDatastore &&
+				(step.Status == types.PreviewSeedStepStatus_Succeeded || step.Status == types.PreviewSeedStepStatus_TimedOut) {
+				cloned[step.Name] = true
+			}
+		}
+	}
+
+	if err := checkSeedClonesEnabled(ctx, inp, cloned); err != nil {
+		return err
+	}
+
+	color.New(color.FgGreen).Printf("Seeding preview environment with %d steps...\n", len(inp.Steps)) // nolint:errcheck,gosec
+
+	results := make([]types.PreviewSeedStepResult, 0, len(inp.Steps))
+	var failed *types.PreviewSeedStepResult
+
+	for _, step := range inp.Steps {
+		result := types.PreviewSeedStepResult{
+			Name: step.Name,
+			Type: step.Type,
+		}
+
+		switch {
+		case failed != nil:
+			result.Status = types.PreviewSeedStepStatus_Skipped
+			result.Reason = fmt.Sprintf("step %s did not succeed", failed.Name)
+		case cloned[step.Name]:
+			result.Status = types.PreviewSeedStepStatus_Skipped
+			result.Reason = "the datastore was already cloned into the preview environment"
+		default:
+			color.New(color.FgGreen).Printf("Running seed step %s (timeout %ds)...\n", step.Name, step.TimeoutSeconds) // nolint:errcheck,gosec
+
+			result.StartedAt = time.Now().UTC()
+			result.Status, result.Reason = runSeedStep(ctx, inp, step)
+			result.CompletedAt = time.Now().UTC()
+
+			// an interrupted apply does not report a partial seed, so the seed runs again on the next apply
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+
+		switch result.Status {
+		case types.PreviewSeedStepStatus_Succeeded:
+			color.New(color.FgGreen).Printf("Seed step %s succeeded\n", step.Name) // nolint:errcheck,gosec
+		case types.PreviewSeedStepStatus_Skipped:
+			color.New(color.FgYellow).Printf("Skipped seed step %s: %s\n", step.Name, result.Reason) // nolint:errcheck,gosec
+		default:
+			color.New(color.FgRed).Printf("Seed step %s %s: %s\n", step.Name, result.Status, result.Reason) // nolint:errcheck,gosec
+		}
+
+		results = append(results, result)
+		if failed == nil && (result.Status == types.PreviewSeedStepStatus_Failed || result.Status == types.PreviewSeedStepStatus_TimedOut) {
+			failed = &results[len(results)-1]
+		}
+	}
+
+	_, err = inp.Client.ReportPreviewSeed(ctx, inp.ProjectID, inp.ClusterID, inp.AppName, &types.ReportPreviewSeedRequest{
+		DeploymentTargetID: inp.DeploymentTargetID,
+		PRNumber:           inp.PRNumber,
+		CommitSHA:          inp.CommitSHA,
+		Reseed:             previous.Seed != nil,
+		Steps:              results,
+	})
+	if err != nil {
+		// the seed has already run, so a failure to report it only means that it runs again on the next apply
+		color.New(color.FgYellow).Printf("Warning: could not report preview seed: %s\n", err.Error()) // nolint:errcheck,gosec
+	}
+
+	if failed != nil {
+		return fmt.Errorf("seed step %s %s: %s", failed.Name, failed.Status, failed.Reason)
+	}
+
+	color.New(color.FgGreen).Printf("Successfully seeded preview environment\n") // nolint:errcheck,gosec
+
+	return nil
+}
+
+// checkSeedClonesEnabled returns an error if any datastore still has to be cloned into the preview environment, but
+// the Porter instance cannot clone datastores. This is checked before any step runs, so that jobs which expect the
+// clone to exist are not run against an incomplete preview environment.
+func checkSeedClonesEnabled(ctx context.Context, inp seedPreviewInput, cloned map[string]bool) error {
+	var cloneStep string
+
+	for _, step := range inp.Steps {
+		if step.Type == types.PreviewSeedStepType_CloneDatastore && !cloned[step.Name] {
+			cloneStep = step.Name
+			break
+		}
+	}
+
+	if cloneStep == "" {
+		return nil
+	}
+
+	metadata, err := inp.Client.GetPorterInstanceMetadata(ctx)
+	if err != nil {
+		return fmt.Errorf("error checking if datastores can be cloned: %w", err)
+	}
+
+	if !metadata.DatastoreBackups {
+		return fmt.Errorf("seed step %s clones a datastore, but %w", cloneStep, datastore.ErrBackupsNotEnabled)
+	}
+
+	return nil
+}
+
+// runSeedStep runs a single seed step until it completes or its timeout is reached, and returns its status and the
+// reason that it did not succeed
+func runSeedStep(ctx context.Context, inp seedPreviewInput, step types.PreviewSeedStep) (types.PreviewSeedStepStatus, string) {
+	stepCtx, cancel := context.WithTimeout(ctx, time.Duration(step.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	var err error
+	switch step.Type {
+	case types.PreviewSeedStepType_Job:
+		err = runSeedJob(stepCtx, inp, step)
+	case types.PreviewSeedStepType_CloneDatastore:
+		err = runSeedClone(stepCtx, inp, step)
+	default:
+		err = fmt.Errorf("unknown seed step type %s", step.Type)
+	}
+
+	if err == nil {
+		return types.PreviewSeedStepStatus_Succeeded, ""
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		return types.PreviewSeedStepStatus_TimedOut, fmt.Sprintf("did not complete within %d seconds", step.TimeoutSeconds)
+	}
+
+	return types.PreviewSeedStepStatus_Failed, err.Error()
+}
+
+// runSeedJob runs a job service of the app in the preview environment and waits for it to exit. The job run is
+// canceled if it does not exit before the context is done.
+func runSeedJob(ctx context.Context, inp seedPreviewInput, step types.PreviewSeedStep) error {
+	resp, err := inp.Client.RunAppJobWithOverrides(ctx, api.RunAppJobWithOverridesInput{
+		ProjectID:          inp.ProjectID,
+		ClusterID:          inp.ClusterID,
+		AppName:            inp.AppName,
+		JobName:            step.JobName,
+		DeploymentTargetID: inp.DeploymentTargetID,
+		RunCommand:         step.RunCommand,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to run job %s: %w", step.JobName, err)
+	}
+
+	defer func() {
+		if ctx.Err() == nil {
+			return
+		}
+
+		// the job run outlives the seed step, so it is canceled on a context which is not done
+		_, _ = inp.Client.CancelAppJobRun(context.Background(), api.CancelAppJobInput{ // nolint:contextcheck
+			ProjectID:          inp.ProjectID,
+			ClusterID:          inp.ClusterID,
+			AppName:            inp.AppName,
+			JobName:            resp.JobRunName,
+			DeploymentTargetID: inp.DeploymentTargetID,
+		})
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(seedPollFrequency):
+		}
+
+		statusResp, err := inp.Client.RunAppJobStatus(ctx, api.RunAppJobStatusInput{
+			ProjectID:          inp.ProjectID,
+			ClusterID:          inp.ClusterID,
+			AppName:            inp.AppName,
+			DeploymentTargetID: inp.DeploymentTargetID,
+			ServiceName:        step.JobName,
+			JobRunID:           resp.JobRunID,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("unable to get status of job %s: %w", step.JobName, err)
+		}
+
+		switch statusResp.Status {
+		case porter_app_internal.InstanceStatusDescriptor_Succeeded:
+			return nil
+		case porter_app_internal.InstanceStatusDescriptor_Failed:
+			return fmt.Errorf("job %s exited with a non-zero exit code", step.JobName)
+		case porter_app_internal.InstanceStatusDescriptor_Unknown:
+			return fmt.Errorf("job %s is in an unknown state", step.JobName)
+		}
+	}
+}
+
+// runSeedClone clones a datastore into the preview environment and waits for the clone to become available
+func runSeedClone(ctx context.Context, inp seedPreviewInput, step types.PreviewSeedStep) error {
+	resp, err := inp.Client.CloneDatastore(ctx, inp.ProjectID, step.SourceDatastore, &types.CloneDatastoreRequest{
+		DeploymentTargetID: inp.DeploymentTargetID,
+		Name:               step.DatastoreName,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to clone datastore %s: %w", step.SourceDatastore, err)
+	}
+
+	color.New(color.FgGreen).Printf("Waiting for datastore %s to be cloned into %s...\n", step.SourceDatastore, resp.Name) // nolint:errcheck,gosec
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(seedPollFrequency):
+		}
+
+		datastoreResp, err := inp.Client.GetDatastore(ctx, inp.ProjectID, resp.Name)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("unable to get status of datastore %s: %w", resp.Name, err)
+		}
+
+		switch models.DatastoreStatus(datastoreResp.Datastore.Status) {
+		case models.DatastoreStatus_Available:
+			return nil
+		case models.DatastoreStatus_AwaitingDeletion:
+			return fmt.Errorf("datastore %s was deleted before the clone completed", resp.Name)
+		}
+	}
+}
@@ -100,6 +100,23 @@ func main() {
 			config.Logger.Info().Msg("Shutting down PorterAPI server")
 			return nil
 		})
+
+		if config.ServerConf.PreviewWakerPort != 0 {
+			w := server.PorterAPIServer{
+				Port:       config.ServerConf.PreviewWakerPort,
+				Router:     router.NewPreviewWakerRouter(config),
+				ServerConf: config.ServerConf,
+			}
+
+			g.Go(func() error {
+				config.Logger.Info().Msgf("Starting preview waker on port %d", config.ServerConf.PreviewWakerPort)
+				if err := w.ListenAndServe(ctx); err != nil && err != http.ErrServerClosed {
+					return fmt.Errorf("preview waker failed: %s", err.Error())
+				}
+				config.Logger.Info().Msg("Shutting down preview waker")
+				return nil
+			})
+		}
 	}
 
 	termFunc := func() error {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -10,7 +11,9 @@ import (
 
 	"github.com/porter-dev/porter/internal/adapter"
 	"github.com/porter-dev/porter/provisioner/integrations/redis_stream"
+	"github.com/porter-dev/porter/provisioner/integrations/storage"
 	"github.com/porter-dev/porter/provisioner/server/config"
+	"github.com/porter-dev/porter/provisioner/server/drift"
 	"github.com/porter-dev/porter/provisioner/server/router"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
@@ -26,7 +29,10 @@ var Version string = "dev-ce"
 
 func main() {
 	var versionFlag bool
+	var migrateFrom, migrateTo string
 	flag.BoolVar(&versionFlag, "version", false, "print version and exit")
+	flag.StringVar(&migrateFrom, "migrate-storage-from", "", "storage backend to copy state and logs from, then exit")
+	flag.StringVar(&migrateTo, "migrate-storage-to", "", "storage backend to copy state and logs to, then exit")
 	flag.Parse()
 
 	// Exit safely when version is used
@@ -40,6 +46,14 @@ func main() {
 		log.Fatal("Environment loading failed: ", err)
 	}
 
+	if migrateFrom != "" || migrateTo != "" {
+		if err := migrateStorage(envConf, migrateFrom, migrateTo); err != nil {
+			log.Fatal("Storage migration failed: ", err)
+		}
+
+		os.Exit(0)
+	}
+
 	config, err := config.GetConfig(envConf)
 	if err != nil {
 		log.Fatal("Config loading failed: ", err)
@@ -61,6 +75,8 @@ func main() {
 		go redis_stream.GlobalStreamListener(redis, config, config.Repo, nil, errorChan)
 	}
 
+	go drift.StartScheduler(context.Background(), config)
+
 	appRouter := router.NewAPIRouter(config)
 
 	// if config.RedisConf.Enabled {
@@ -110,3 +126,38 @@ func main() {
 		config.Logger.Fatal().Err(err).Msg("Server startup failed")
 	}
 }
+
+// migrateStorage copies the state and logs for all infras between two storage backends
+func migrateStorage(envConf *config.EnvConf, from, to string) error {
+	if from == "" || to == "" {
+		return fmt.Errorf("both --migrate-storage-from and --migrate-storage-to must be set")
+	}
+
+	if from == to {
+		return fmt.Errorf("cannot migrate storage backend %s to itself", from)
+	}
+
+	db, err := adapter.New(envConf.DBConf)
+	if err != nil {
+		return err
+	}
+
+	fromStorage, err := config.NewStorageManager(from, envConf, db)
+	if err != nil {
+		return fmt.Errorf("could not load source storage backend: %w", err)
+	}
+
+	toStorage, err := config.NewStorageManager(to, envConf, db)
+	if err != nil {
+		return fmt.Errorf("could not load destination storage backend: %w", err)
+	}
+
+	res, err := storage.Migrate(db, fromStorage, toStorage)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("migrated %d files for %d infras from %s to %s\n", res.Files, res.Infras, from, to)
+
+	return nil
+}
@@ -66,6 +66,16 @@ type GetGitlabCredentialData struct {
 	Data     *credentials.GitlabCredential `json:"data"`
 }
 
+type ReadSecretDataResponse struct {
+	*VaultGetResponse
+	Data *ReadSecretDataData `json:"data"`
+}
+
+type ReadSecretDataData struct {
+	Metadata *VaultMetadata         `json:"metadata"`
+	Data     map[string]interface{} `json:"data"`
+}
+
 type CreatePolicyRequest struct {
 	Policy string `json:"policy"`
 }
@@ -216,6 +216,29 @@ func (c *Client) getGitlabCredentialPath(giIntegration *integrations.GitlabInteg
 	)
 }
 
+// ReadSecretData reads the data of a secret in a KV v2 engine, where the path segments include the mount and data
+// segment, such as kv, data, projects, 1, payments. Each segment is escaped, so that a segment can never be read by
+// vault as more than one segment.
+func (c *Client) ReadSecretData(pathSegments []string) (map[string]interface{}, error) {
+	resp := &ReadSecretDataResponse{}
+
+	escapedSegments := make([]string, 0, len(pathSegments))
+	for _, segment := range pathSegments {
+		escapedSegments = append(escapedSegments, url.PathEscape(segment))
+	}
+
+	err := c.getEscapedRequest("/v1/"+strings.Join(escapedSegments, "/"), resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Data == nil || resp.Data.Data == nil {
+		return nil, fmt.Errorf("secret %s has no data", strings.Join(pathSegments, "/"))
+	}
+
+	return resp.Data.Data, nil
+}
+
 const readOnlyPolicyTemplate = `path "%s" {
   capabilities = ["read"]
 }`
@@ -271,6 +294,28 @@ func (c *Client) getRequest(path string, dst interface{}) error {
 
 	reqURL.Path = path
 
+	return c.doGetRequest(reqURL, dst)
+}
+
+// getEscapedRequest is like getRequest, but the path is already escaped and is sent as is
+func (c *Client) getEscapedRequest(escapedPath string, dst interface{}) error {
+	reqURL, err := url.Parse(c.serverURL)
+	if err != nil {
+		return err
+	}
+
+	path, err := url.PathUnescape(escapedPath)
+	if err != nil {
+		return err
+	}
+
+	reqURL.Path = path
+	reqURL.RawPath = escapedPath
+
+	return c.doGetRequest(reqURL, dst)
+}
+
+func (c *Client) doGetRequest(reqURL *url.URL, dst interface{}) error {
 	req, err := http.NewRequest(
 		"GET",
 		reqURL.String(),
@@ -0,0 +1,58 @@
+package mfa
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// EnabledMethods returns the second factors that a user has configured. A user has MFA enabled if
+// the returned list is non-empty.
+func EnabledMethods(repo repository.MFARepository, userID uint) ([]types.MFAMethod, error) {
+	methods := make([]types.MFAMethod, 0)
+
+	totp, err := repo.ReadUserTOTP(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("error reading totp enrollment: %w", err)
+	}
+
+	if err == nil && totp.Enabled {
+		methods = append(methods, types.MFAMethodTOTP)
+	}
+
+	creds, err := repo.ListWebAuthnCredentials(userID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing webauthn credentials: %w", err)
+	}
+
+	if len(creds) > 0 {
+		methods = append(methods, types.MFAMethodWebAuthn)
+	}
+
+	if len(methods) > 0 {
+		methods = append(methods, types.MFAMethodRecoveryCode)
+	}
+
+	return methods, nil
+}
+
+// RelyingPartyFromServerURL derives the WebAuthn relying party from the public URL of the Porter server
+func RelyingPartyFromServerURL(serverURL string) (RelyingParty, error) {
+	parsed, err := url.Parse(serverURL)
+	if err != nil {
+		return RelyingParty{}, fmt.Errorf("invalid server url: %w", err)
+	}
+
+	if parsed.Hostname() == "" {
+		return RelyingParty{}, fmt.Errorf("server url %s has no host", serverURL)
+	}
+
+	return RelyingParty{
+		ID:     parsed.Hostname(),
+		Origin: fmt.Sprintf("%s://%s", parsed.Scheme, parsed.Host),
+	}, nil
+}
@@ -0,0 +1,195 @@
+package mfa_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/auth/mfa"
+)
+
+// rfc6238Secret is the base32 encoding of the ASCII secret "12345678901234567890" used by the
+// RFC 6238 SHA1 test vectors
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateTOTPCodeRFCVectors(t *testing.T) {
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range vectors {
+		code, err := mfa.GenerateTOTPCode(rfc6238Secret, mfa.TOTPStep(time.Unix(unix, 0)))
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+
+		if code != expected {
+			t.Errorf("code at %d: expected %s, got %s", unix, expected, code)
+		}
+	}
+}
+
+func TestValidateTOTPCode(t *testing.T) {
+	secret, err := mfa.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	now := time.Now()
+	step := mfa.TOTPStep(now)
+
+	code, err := mfa.GenerateTOTPCode(secret, step-1)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	gotStep, ok := mfa.ValidateTOTPCode(secret, code, now, 0)
+	if !ok || gotStep != step-1 {
+		t.Fatalf("expected code from previous step to be accepted")
+	}
+
+	if _, ok := mfa.ValidateTOTPCode(secret, code, now, gotStep); ok {
+		t.Errorf("expected replayed code to be rejected")
+	}
+
+	if _, ok := mfa.ValidateTOTPCode(secret, code, now.Add(5*mfa.TOTPPeriod), 0); ok {
+		t.Errorf("expected expired code to be rejected")
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := mfa.GenerateRecoveryCodes(mfa.RecoveryCodeCount)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(codes) != mfa.RecoveryCodeCount {
+		t.Fatalf("expected %d codes, got %d", mfa.RecoveryCodeCount, len(codes))
+	}
+
+	seen := make(map[string]bool)
+
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Errorf("unexpected recovery code format %s", code)
+		}
+
+		if seen[code] {
+			t.Errorf("duplicate recovery code %s", code)
+		}
+
+		seen[code] = true
+	}
+
+	if mfa.HashRecoveryCode(codes[0]) != mfa.HashRecoveryCode(" "+codes[0]+" ") {
+		t.Errorf("expected recovery code hash to ignore surrounding whitespace")
+	}
+}
+
+func TestWebAuthnAssertion(t *testing.T) {
+	rp := mfa.RelyingParty{
+		ID:     "dashboard.porter.run",
+		Origin: "https://dashboard.porter.run",
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	challenge, err := mfa.NewWebAuthnChallenge()
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	regClientData := mustMarshalClientData(t, "webauthn.create", challenge, rp.Origin)
+
+	signCount, err := rp.VerifyRegistration(mfa.WebAuthnRegistration{
+		ClientDataJSON:    regClientData,
+		AuthenticatorData: authenticatorData(rp.ID, 0),
+		PublicKey:         pub,
+	}, challenge)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	assertClientData := mustMarshalClientData(t, "webauthn.get", challenge, rp.Origin)
+	authData := authenticatorData(rp.ID, 1)
+	clientDataHash := sha256.Sum256(assertClientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	assertion := mfa.WebAuthnAssertion{
+		ClientDataJSON:    assertClientData,
+		AuthenticatorData: authData,
+		Signature:         sig,
+	}
+
+	newCount, err := rp.VerifyAssertion(assertion, challenge, pub, signCount)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if newCount != 1 {
+		t.Errorf("expected sign count 1, got %d", newCount)
+	}
+
+	if _, err := rp.VerifyAssertion(assertion, challenge, pub, newCount); err != mfa.ErrWebAuthnCounterRegression {
+		t.Errorf("expected counter regression error, got %v", err)
+	}
+
+	if _, err := rp.VerifyAssertion(assertion, "other-challenge", pub, signCount); err != mfa.ErrWebAuthnChallengeMismatch {
+		t.Errorf("expected challenge mismatch error, got %v", err)
+	}
+
+	assertion.Signature[len(assertion.Signature)-1] ^= 0xff
+
+	if _, err := rp.VerifyAssertion(assertion, challenge, pub, signCount); err == nil {
+		t.Errorf("expected tampered signature to be rejected")
+	}
+}
+
+func mustMarshalClientData(t *testing.T, typ, challenge, origin string) []byte {
+	t.Helper()
+
+	res, err := json.Marshal(map[string]string{
+		"type":      typ,
+		"challenge": challenge,
+		"origin":    origin,
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	return res
+}
+
+func authenticatorData(rpID string, signCount uint32) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+
+	res := append([]byte{}, rpIDHash[:]...)
+	res = append(res, 0x01)
+
+	count := make([]byte, 4)
+	binary.BigEndian.PutUint32(count, signCount)
+
+	return append(res, count...)
+}
@@ -0,0 +1,203 @@
+package mfa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	webAuthnTypeCreate = "webauthn.create"
+	webAuthnTypeGet    = "webauthn.get"
+
+	// authenticator data is at least rpIdHash (32 bytes) + flags (1 byte) + signCount (4 bytes)
+	minAuthenticatorDataLength = 37
+
+	flagUserPresent = 0x01
+)
+
+var (
+	// ErrWebAuthnChallengeMismatch is returned when the signed challenge does not match the one issued
+	ErrWebAuthnChallengeMismatch = errors.New("webauthn challenge does not match")
+
+	// ErrWebAuthnOriginMismatch is returned when the client data origin does not match the relying party origin
+	ErrWebAuthnOriginMismatch = errors.New("webauthn origin does not match")
+
+	// ErrWebAuthnInvalidSignature is returned when an assertion signature cannot be verified
+	ErrWebAuthnInvalidSignature = errors.New("webauthn signature is invalid")
+
+	// ErrWebAuthnCounterRegression is returned when an authenticator reports a signature counter that
+	// did not increase, which indicates that the credential may have been cloned
+	ErrWebAuthnCounterRegression = errors.New("webauthn signature counter did not increase")
+)
+
+// RelyingParty identifies this Porter instance to WebAuthn authenticators
+type RelyingParty struct {
+	// ID is the relying party ID, which is the effective domain of the server
+	ID string
+
+	// Origin is the full origin (scheme, host and port) that the browser reports in client data
+	Origin string
+}
+
+// clientData is the subset of CollectedClientData that is verified by the server
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// NewWebAuthnChallenge returns a random base64url-encoded challenge
+func NewWebAuthnChallenge() (string, error) {
+	challenge := make([]byte, 32)
+
+	if _, err := rand.Read(challenge); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(challenge), nil
+}
+
+// WebAuthnUserHandle returns the opaque user handle that is registered with an authenticator for a user
+func WebAuthnUserHandle(userID uint) string {
+	handle := make([]byte, 8)
+	binary.BigEndian.PutUint64(handle, uint64(userID))
+
+	return base64.RawURLEncoding.EncodeToString(handle)
+}
+
+// DecodeBase64URL decodes a base64url value sent by the browser, with or without padding
+func DecodeBase64URL(val string) ([]byte, error) {
+	if res, err := base64.RawURLEncoding.DecodeString(val); err == nil {
+		return res, nil
+	}
+
+	return base64.URLEncoding.DecodeString(val)
+}
+
+// WebAuthnRegistration is the result of a navigator.credentials.create() ceremony, as sent by the dashboard.
+// The public key is the SubjectPublicKeyInfo returned by AuthenticatorAttestationResponse.getPublicKey(),
+// so the attestation object does not need to be parsed by the server.
+type WebAuthnRegistration struct {
+	ClientDataJSON    []byte
+	AuthenticatorData []byte
+	PublicKey         []byte
+}
+
+// VerifyRegistration checks a new credential against the issued challenge and returns its
+// initial signature counter
+func (rp RelyingParty) VerifyRegistration(reg WebAuthnRegistration, challenge string) (uint32, error) {
+	if err := rp.verifyClientData(reg.ClientDataJSON, webAuthnTypeCreate, challenge); err != nil {
+		return 0, err
+	}
+
+	if _, err := x509.ParsePKIXPublicKey(reg.PublicKey); err != nil {
+		return 0, fmt.Errorf("invalid webauthn public key: %w", err)
+	}
+
+	return rp.verifyAuthenticatorData(reg.AuthenticatorData)
+}
+
+// WebAuthnAssertion is the result of a navigator.credentials.get() ceremony
+type WebAuthnAssertion struct {
+	ClientDataJSON    []byte
+	AuthenticatorData []byte
+	Signature         []byte
+}
+
+// VerifyAssertion verifies that the assertion was signed by the credential with the given public key
+// for the issued challenge. It returns the new signature counter, which the caller should persist.
+func (rp RelyingParty) VerifyAssertion(assertion WebAuthnAssertion, challenge string, publicKey []byte, storedSignCount uint32) (uint32, error) {
+	if err := rp.verifyClientData(assertion.ClientDataJSON, webAuthnTypeGet, challenge); err != nil {
+		return 0, err
+	}
+
+	signCount, err := rp.verifyAuthenticatorData(assertion.AuthenticatorData)
+	if err != nil {
+		return 0, err
+	}
+
+	// authenticators which do not implement a counter always report zero
+	if (signCount != 0 || storedSignCount != 0) && signCount <= storedSignCount {
+		return 0, ErrWebAuthnCounterRegression
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(publicKey)
+	if err != nil {
+		return 0, fmt.Errorf("invalid webauthn public key: %w", err)
+	}
+
+	clientDataHash := sha256.Sum256(assertion.ClientDataJSON)
+	signed := append(append([]byte{}, assertion.AuthenticatorData...), clientDataHash[:]...)
+	digest := sha256.Sum256(signed)
+
+	switch key := pub.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], assertion.Signature) {
+			return 0, ErrWebAuthnInvalidSignature
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], assertion.Signature); err != nil {
+			return 0, ErrWebAuthnInvalidSignature
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, signed, assertion.Signature) {
+			return 0, ErrWebAuthnInvalidSignature
+		}
+	default:
+		return 0, fmt.Errorf("unsupported webauthn public key type %T", pub)
+	}
+
+	return signCount, nil
+}
+
+func (rp RelyingParty) verifyClientData(raw []byte, expectedType, challenge string) error {
+	data := &clientData{}
+
+	if err := json.Unmarshal(raw, data); err != nil {
+		return fmt.Errorf("invalid webauthn client data: %w", err)
+	}
+
+	if data.Type != expectedType {
+		return fmt.Errorf("unexpected webauthn client data type %s", data.Type)
+	}
+
+	if challenge == "" || subtle.ConstantTimeCompare([]byte(data.Challenge), []byte(challenge)) != 1 {
+		return ErrWebAuthnChallengeMismatch
+	}
+
+	if data.Origin != rp.Origin {
+		return ErrWebAuthnOriginMismatch
+	}
+
+	return nil
+}
+
+func (rp RelyingParty) verifyAuthenticatorData(authData []byte) (uint32, error) {
+	if len(authData) < minAuthenticatorDataLength {
+		return 0, fmt.Errorf("webauthn authenticator data is too short")
+	}
+
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+
+	if !bytes.Equal(authData[:32], rpIDHash[:]) {
+		return 0, fmt.Errorf("webauthn relying party id does not match")
+	}
+
+	if authData[32]&flagUserPresent == 0 {
+		return 0, fmt.Errorf("webauthn user presence flag not set")
+	}
+
+	return binary.BigEndian.Uint32(authData[33:37]), nil
+}
@@ -29,6 +29,8 @@ type Token struct {
 	// Additional fields that may or may not be set
 	TokenID string `json:"token_id"`
 	Secret  string `json:"secret"`
+	// MFAVerifiedAt is when the user completed a second factor in the login that the token was issued from
+	MFAVerifiedAt *time.Time `json:"mfa_verified_at"`
 }
 
 func GetTokenForUser(userID uint) (*Token, error) {
@@ -81,7 +83,7 @@ func GetStoredTokenForAPI(userID, projID uint, tokenID, secret string) (*Token,
 }
 
 func (t *Token) EncodeToken(conf *TokenGeneratorConf) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	claims := jwt.MapClaims{
 		"sub_kind":   t.SubKind,
 		"sub":        t.Sub,
 		"iby":        t.IBy,
@@ -89,7 +91,13 @@ func (t *Token) EncodeToken(conf *TokenGeneratorConf) (string, error) {
 		"project_id": t.ProjectID,
 		"token_id":   t.TokenID,
 		"secret":     t.Secret,
-	})
+	}
+
+	if t.MFAVerifiedAt != nil {
+		claims["mfa_verified_at"] = fmt.Sprintf("%d", t.MFAVerifiedAt.Unix())
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	// Sign and get the complete encoded token as a string using the secret
 	return token.SignedString([]byte(conf.TokenSecret))
@@ -152,6 +160,16 @@ func GetTokenFromEncoded(tokenString string, conf *TokenGeneratorConf) (*Token,
 			}
 		}
 
+		if mfaVerifiedAtInter, ok := claims["mfa_verified_at"]; ok {
+			mfaVerifiedAtUnix, err := strconv.ParseInt(fmt.Sprintf("%v", mfaVerifiedAtInter), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid mfa_verified_at claim: %v", err)
+			}
+
+			mfaVerifiedAt := time.Unix(mfaVerifiedAtUnix, 0)
+			res.MFAVerifiedAt = &mfaVerifiedAt
+		}
+
 		cancelTokens := func(userId string, lastIssueTime time.Time, res *Token) error {
 			timeAsUTC := lastIssueTime.UTC()
 			if res.Sub == userId && res.IAt.UTC().Before(timeAsUTC) {
@@ -46,3 +46,45 @@ func TestGetAndEncodeTokenForUser(t *testing.T) {
 		t.Error(diff)
 	}
 }
+
+func TestEncodeTokenWithMFAVerifiedAt(t *testing.T) {
+	conf := &token.TokenGeneratorConf{
+		TokenSecret: "fakesecret",
+	}
+
+	tok, err := token.GetTokenForUser(1)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	tokString, err := tok.EncodeToken(conf)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	gotToken, err := token.GetTokenFromEncoded(tokString, conf)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if gotToken.MFAVerifiedAt != nil {
+		t.Fatalf("token issued without a second factor should not be verified\n")
+	}
+
+	verifiedAt := time.Unix(time.Now().Unix(), 0)
+	tok.MFAVerifiedAt = &verifiedAt
+
+	tokString, err = tok.EncodeToken(conf)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	gotToken, err = token.GetTokenFromEncoded(tokString, conf)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if gotToken.MFAVerifiedAt == nil || !gotToken.MFAVerifiedAt.Equal(verifiedAt) {
+		t.Fatalf("expected mfa verified at %v, got %v\n", verifiedAt, gotToken.MFAVerifiedAt)
+	}
+}
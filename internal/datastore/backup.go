@@ -0,0 +1,125 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SnapshotType describes how a snapshot was created
+type SnapshotType string
+
+const (
+	// SnapshotType_Automated is a snapshot taken on the provider's backup schedule
+	SnapshotType_Automated SnapshotType = "AUTOMATED"
+	// SnapshotType_OnDemand is a snapshot that was requested by a user
+	SnapshotType_OnDemand SnapshotType = "ON_DEMAND"
+)
+
+// SnapshotStatus is the status of a snapshot
+type SnapshotStatus string
+
+const (
+	// SnapshotStatus_Creating is the status for a snapshot that is still being taken
+	SnapshotStatus_Creating SnapshotStatus = "CREATING"
+	// SnapshotStatus_Available is the status for a snapshot that can be restored from
+	SnapshotStatus_Available SnapshotStatus = "AVAILABLE"
+	// SnapshotStatus_Failed is the status for a snapshot that could not be taken
+	SnapshotStatus_Failed SnapshotStatus = "FAILED"
+)
+
+var (
+	// ErrBackupsNotEnabled is returned when no backup provider is configured for the Porter instance
+	ErrBackupsNotEnabled = errors.New("datastore snapshots, restores and clones are not enabled on this Porter instance")
+	// ErrBackupsNotSupported is returned when a provider cannot back up a datastore of the given type
+	ErrBackupsNotSupported = errors.New("backups are not supported for this datastore")
+	// ErrRestoreTimeOutOfRange is returned when a restore is requested outside of the restorable window
+	ErrRestoreTimeOutOfRange = errors.New("requested restore time is outside of the restorable window")
+)
+
+// Snapshot is a point-in-time backup of a datastore
+type Snapshot struct {
+	// ID is the provider-specific identifier of the snapshot
+	ID string `json:"id"`
+	// DatastoreName is the name of the datastore that the snapshot was taken from
+	DatastoreName string `json:"datastore_name"`
+	// Type describes how the snapshot was created
+	Type SnapshotType `json:"type"`
+	// Status is the status of the snapshot
+	Status SnapshotStatus `json:"status"`
+	// SizeGigabytes is the size of the snapshot, if known
+	SizeGigabytes int64 `json:"size_gigabytes,omitempty"`
+	// CreatedAtUTC is the time the snapshot was taken
+	CreatedAtUTC time.Time `json:"created_at"`
+}
+
+// RestoreWindow is the range of times that a datastore can be restored to
+type RestoreWindow struct {
+	// EarliestUTC is the earliest time that can be restored to
+	EarliestUTC time.Time `json:"earliest"`
+	// LatestUTC is the latest time that can be restored to
+	LatestUTC time.Time `json:"latest"`
+}
+
+// Contains returns true if t falls within the restore window
+func (w RestoreWindow) Contains(t time.Time) bool {
+	return !t.Before(w.EarliestUTC) && !t.After(w.LatestUTC)
+}
+
+// BackupTarget identifies the datastore that a backup operation applies to
+type BackupTarget struct {
+	// ProjectID is the ID of the project that the datastore belongs to
+	ProjectID uint
+	// DatastoreID is the ID of the datastore
+	DatastoreID uuid.UUID
+	// Name is the name of the datastore
+	Name string
+	// Type is the type of the datastore, e.g. RDS
+	Type string
+	// Engine is the engine of the datastore, e.g. POSTGRES
+	Engine string
+	// CloudProviderCredentialIdentifier is the credential used to access the datastore's cloud account
+	CloudProviderCredentialIdentifier string
+}
+
+// RestoreInput is the input to BackupProvider.Restore
+type RestoreInput struct {
+	// Source is the datastore to restore from
+	Source BackupTarget
+	// TargetID is the ID of the new datastore that will be created from the restore
+	TargetID uuid.UUID
+	// TargetName is the name of the new datastore that will be created from the restore
+	TargetName string
+	// SnapshotID is the snapshot to restore from. If empty, PointInTimeUTC is used instead
+	SnapshotID string
+	// PointInTimeUTC is the time to restore to. Ignored if SnapshotID is set
+	PointInTimeUTC time.Time
+}
+
+// CloneInput is the input to BackupProvider.Clone
+type CloneInput struct {
+	// Source is the datastore to clone
+	Source BackupTarget
+	// TargetID is the ID of the new datastore that will be created from the clone
+	TargetID uuid.UUID
+	// TargetName is the name of the new datastore that will be created from the clone
+	TargetName string
+	// DeploymentTargetID is the ID of the preview environment that the clone is created for
+	DeploymentTargetID uuid.UUID
+}
+
+// BackupProvider manages the backup lifecycle of datastores for a specific backend
+type BackupProvider interface {
+	// ListSnapshots returns all snapshots for a datastore, newest first
+	ListSnapshots(ctx context.Context, target BackupTarget) ([]Snapshot, error)
+	// CreateSnapshot triggers an on-demand snapshot of a datastore
+	CreateSnapshot(ctx context.Context, target BackupTarget) (Snapshot, error)
+	// RestoreWindow returns the range of times that a datastore can be restored to
+	RestoreWindow(ctx context.Context, target BackupTarget) (RestoreWindow, error)
+	// Restore creates a new datastore from a snapshot or point in time of an existing datastore
+	Restore(ctx context.Context, input RestoreInput) error
+	// Clone creates a new datastore from the latest state of an existing datastore
+	Clone(ctx context.Context, input CloneInput) error
+}
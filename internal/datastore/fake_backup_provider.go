@@ -0,0 +1,149 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FakeBackupProvider is an in-memory BackupProvider for use in tests and local development.
+// Snapshots are available as soon as they are created, and every datastore can be restored
+// to any time between its first snapshot and now.
+type FakeBackupProvider struct {
+	mu sync.Mutex
+
+	// Now returns the current time, and can be overridden in tests
+	Now func() time.Time
+
+	snapshots map[uuid.UUID][]Snapshot
+
+	// Restores records every successful restore, in order
+	Restores []RestoreInput
+	// Clones records every successful clone, in order
+	Clones []CloneInput
+}
+
+// NewFakeBackupProvider returns an empty FakeBackupProvider
+func NewFakeBackupProvider() *FakeBackupProvider {
+	return &FakeBackupProvider{
+		Now:       time.Now,
+		snapshots: make(map[uuid.UUID][]Snapshot),
+	}
+}
+
+// AddSnapshot seeds a snapshot for the given datastore
+func (p *FakeBackupProvider) AddSnapshot(datastoreID uuid.UUID, snapshot Snapshot) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.snapshots[datastoreID] = append(p.snapshots[datastoreID], snapshot)
+}
+
+// ListSnapshots returns all snapshots for a datastore, newest first
+func (p *FakeBackupProvider) ListSnapshots(ctx context.Context, target BackupTarget) ([]Snapshot, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	snapshots := make([]Snapshot, len(p.snapshots[target.DatastoreID]))
+	copy(snapshots, p.snapshots[target.DatastoreID])
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAtUTC.After(snapshots[j].CreatedAtUTC)
+	})
+
+	return snapshots, nil
+}
+
+// CreateSnapshot records an available on-demand snapshot of a datastore
+func (p *FakeBackupProvider) CreateSnapshot(ctx context.Context, target BackupTarget) (Snapshot, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	snapshot := Snapshot{
+		ID:            fmt.Sprintf("%s-%s", target.Name, uuid.New().String()[:8]),
+		DatastoreName: target.Name,
+		Type:          SnapshotType_OnDemand,
+		Status:        SnapshotStatus_Available,
+		CreatedAtUTC:  p.Now().UTC(),
+	}
+
+	p.snapshots[target.DatastoreID] = append(p.snapshots[target.DatastoreID], snapshot)
+
+	return snapshot, nil
+}
+
+// RestoreWindow returns the range between the datastore's oldest snapshot and now
+func (p *FakeBackupProvider) RestoreWindow(ctx context.Context, target BackupTarget) (RestoreWindow, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.restoreWindow(target.DatastoreID)
+}
+
+// Restore validates the restore point and records the restore
+func (p *FakeBackupProvider) Restore(ctx context.Context, input RestoreInput) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if input.SnapshotID != "" {
+		if _, ok := p.findSnapshot(input.Source.DatastoreID, input.SnapshotID); !ok {
+			return fmt.Errorf("snapshot %s not found for datastore %s", input.SnapshotID, input.Source.Name)
+		}
+	} else {
+		window, err := p.restoreWindow(input.Source.DatastoreID)
+		if err != nil {
+			return err
+		}
+
+		if !window.Contains(input.PointInTimeUTC) {
+			return ErrRestoreTimeOutOfRange
+		}
+	}
+
+	p.Restores = append(p.Restores, input)
+
+	return nil
+}
+
+// Clone records the clone
+func (p *FakeBackupProvider) Clone(ctx context.Context, input CloneInput) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.Clones = append(p.Clones, input)
+
+	return nil
+}
+
+func (p *FakeBackupProvider) restoreWindow(datastoreID uuid.UUID) (RestoreWindow, error) {
+	snapshots := p.snapshots[datastoreID]
+	if len(snapshots) == 0 {
+		return RestoreWindow{}, fmt.Errorf("no snapshots exist for datastore %s", datastoreID)
+	}
+
+	earliest := snapshots[0].CreatedAtUTC
+	for _, snapshot := range snapshots[1:] {
+		if snapshot.CreatedAtUTC.Before(earliest) {
+			earliest = snapshot.CreatedAtUTC
+		}
+	}
+
+	return RestoreWindow{
+		EarliestUTC: earliest,
+		LatestUTC:   p.Now().UTC(),
+	}, nil
+}
+
+func (p *FakeBackupProvider) findSnapshot(datastoreID uuid.UUID, snapshotID string) (Snapshot, bool) {
+	for _, snapshot := range p.snapshots[datastoreID] {
+		if snapshot.ID == snapshotID {
+			return snapshot, true
+		}
+	}
+
+	return Snapshot{}, false
+}
@@ -0,0 +1,127 @@
+package datastore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/internal/datastore"
+)
+
+func TestFakeBackupProviderSnapshots(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+
+	provider := datastore.NewFakeBackupProvider()
+	provider.Now = func() time.Time { return now }
+
+	target := datastore.BackupTarget{DatastoreID: uuid.New(), Name: "production"}
+
+	provider.AddSnapshot(target.DatastoreID, datastore.Snapshot{
+		ID:           "automated-1",
+		Type:         datastore.SnapshotType_Automated,
+		Status:       datastore.SnapshotStatus_Available,
+		CreatedAtUTC: now.Add(-24 * time.Hour),
+	})
+
+	created, err := provider.CreateSnapshot(ctx, target)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if created.Type != datastore.SnapshotType_OnDemand || created.DatastoreName != "production" {
+		t.Errorf("unexpected on-demand snapshot: %+v", created)
+	}
+
+	snapshots, err := provider.ListSnapshots(ctx, target)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(snapshots) != 2 || snapshots[0].ID != created.ID || snapshots[1].ID != "automated-1" {
+		t.Fatalf("expected snapshots newest first, got %+v", snapshots)
+	}
+
+	other, err := provider.ListSnapshots(ctx, datastore.BackupTarget{DatastoreID: uuid.New()})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(other) != 0 {
+		t.Errorf("expected no snapshots for another datastore, got %d", len(other))
+	}
+}
+
+func TestFakeBackupProviderRestore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+
+	provider := datastore.NewFakeBackupProvider()
+	provider.Now = func() time.Time { return now }
+
+	source := datastore.BackupTarget{DatastoreID: uuid.New(), Name: "production"}
+
+	if _, err := provider.RestoreWindow(ctx, source); err == nil {
+		t.Errorf("expected no restore window before the first snapshot")
+	}
+
+	provider.AddSnapshot(source.DatastoreID, datastore.Snapshot{
+		ID:           "automated-1",
+		CreatedAtUTC: now.Add(-time.Hour),
+	})
+
+	window, err := provider.RestoreWindow(ctx, source)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if !window.EarliestUTC.Equal(now.Add(-time.Hour)) || !window.LatestUTC.Equal(now) {
+		t.Errorf("unexpected restore window: %+v", window)
+	}
+
+	err = provider.Restore(ctx, datastore.RestoreInput{
+		Source:         source,
+		TargetName:     "production-restored",
+		PointInTimeUTC: now.Add(-2 * time.Hour),
+	})
+	if !errors.Is(err, datastore.ErrRestoreTimeOutOfRange) {
+		t.Errorf("expected out of range error, got %v", err)
+	}
+
+	err = provider.Restore(ctx, datastore.RestoreInput{
+		Source:     source,
+		TargetName: "production-restored",
+		SnapshotID: "does-not-exist",
+	})
+	if err == nil {
+		t.Errorf("expected error restoring from a missing snapshot")
+	}
+
+	err = provider.Restore(ctx, datastore.RestoreInput{
+		Source:         source,
+		TargetName:     "production-restored",
+		PointInTimeUTC: now.Add(-30 * time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	err = provider.Clone(ctx, datastore.CloneInput{
+		Source:             source,
+		TargetName:         "production-pr-1",
+		DeploymentTargetID: uuid.New(),
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(provider.Restores) != 1 || provider.Restores[0].TargetName != "production-restored" {
+		t.Errorf("expected one recorded restore, got %+v", provider.Restores)
+	}
+
+	if len(provider.Clones) != 1 || provider.Clones[0].TargetName != "production-pr-1" {
+		t.Errorf("expected one recorded clone, got %+v", provider.Clones)
+	}
+}
@@ -6,9 +6,7 @@ import (
 	"net/url"
 	"strings"
 
-	"github.com/porter-dev/porter/api/server/shared/commonutils"
 	"github.com/porter-dev/porter/api/server/shared/config"
-	"github.com/porter-dev/porter/internal/oauth"
 	"github.com/porter-dev/porter/internal/repository"
 	"github.com/xanzy/go-gitlab"
 	"gopkg.in/yaml.v2"
@@ -300,26 +298,7 @@ func (g *GitlabCI) getClient() (*gitlab.Client, error) {
 		return nil, err
 	}
 
-	giOAuthInt, err := g.Repo.GitlabAppOAuthIntegration().ReadGitlabAppOAuthIntegration(g.UserID, g.ProjectID, g.IntegrationID)
-	if err != nil {
-		return nil, err
-	}
-
-	oauthInt, err := g.Repo.OAuthIntegration().ReadOAuthIntegration(g.ProjectID, giOAuthInt.OAuthIntegrationID)
-	if err != nil {
-		return nil, err
-	}
-
-	accessToken, _, err := oauth.GetAccessToken(
-		oauthInt.SharedOAuthModel,
-		commonutils.GetGitlabOAuthConf(g.PorterConf, gi),
-		oauth.MakeUpdateGitlabAppOAuthIntegrationFunction(g.ProjectID, giOAuthInt, g.Repo),
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	client, err := gitlab.NewOAuthClient(accessToken, gitlab.WithBaseURL(gi.InstanceURL))
+	client, err := NewClient(g.PorterConf, g.Repo, gi, g.ProjectID, g.UserID)
 	if err != nil {
 		return nil, err
 	}
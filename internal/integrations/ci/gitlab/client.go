@@ -0,0 +1,39 @@
+package gitlab
+
+import (
+	"github.com/porter-dev/porter/api/server/shared/commonutils"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/oauth"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/xanzy/go-gitlab"
+)
+
+// NewClient returns a GitLab client for an integration, authorized as the given user
+func NewClient(
+	porterConf *config.Config,
+	repo repository.Repository,
+	gi *ints.GitlabIntegration,
+	projectID, userID uint,
+) (*gitlab.Client, error) {
+	giOAuthInt, err := repo.GitlabAppOAuthIntegration().ReadGitlabAppOAuthIntegration(userID, projectID, gi.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	oauthInt, err := repo.OAuthIntegration().ReadOAuthIntegration(projectID, giOAuthInt.OAuthIntegrationID)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, _, err := oauth.GetAccessToken(
+		oauthInt.SharedOAuthModel,
+		commonutils.GetGitlabOAuthConf(porterConf, gi),
+		oauth.MakeUpdateGitlabAppOAuthIntegrationFunction(projectID, giOAuthInt, repo),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return gitlab.NewOAuthClient(accessToken, gitlab.WithBaseURL(gi.InstanceURL))
+}
@@ -0,0 +1,229 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/xanzy/go-gitlab"
+)
+
+const (
+	// HeaderEvent is the header in which GitLab sends the type of a webhook event
+	HeaderEvent = "X-Gitlab-Event"
+	// HeaderToken is the header in which GitLab sends the secret token of a webhook
+	HeaderToken = "X-Gitlab-Token"
+
+	// EventType_MergeRequest is the type of merge request webhook events
+	EventType_MergeRequest = "Merge Request Hook"
+)
+
+const (
+	MergeRequestAction_Open   = "open"
+	MergeRequestAction_Reopen = "reopen"
+	MergeRequestAction_Update = "update"
+	MergeRequestAction_Close  = "close"
+	MergeRequestAction_Merge  = "merge"
+)
+
+// MergeRequestEvent is the part of a GitLab merge request webhook event used by preview environments
+type MergeRequestEvent struct {
+	ObjectKind       string                      `json:"object_kind"`
+	Project          MergeRequestEventProject    `json:"project"`
+	ObjectAttributes MergeRequestEventAttributes `json:"object_attributes"`
+}
+
+// MergeRequestEventProject is the project of a merge request webhook event
+type MergeRequestEventProject struct {
+	ID                int    `json:"id"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	WebURL            string `json:"web_url"`
+}
+
+// MergeRequestEventAttributes are the attributes of the merge request of a webhook event
+type MergeRequestEventAttributes struct {
+	// IID is the number of the merge request within its project
+	IID          int    `json:"iid"`
+	Title        string `json:"title"`
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+	Action       string `json:"action"`
+	URL          string `json:"url"`
+	// OldRev is only set on updates which push new commits
+	OldRev     string `json:"oldrev"`
+	LastCommit struct {
+		ID string `json:"id"`
+	} `json:"last_commit"`
+}
+
+// ParseMergeRequestEvent parses the payload of a merge request webhook event
+func ParseMergeRequestEvent(payload []byte) (*MergeRequestEvent, error) {
+	event := &MergeRequestEvent{}
+
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, fmt.Errorf("error parsing merge request event: %w", err)
+	}
+
+	if event.ObjectKind != "merge_request" {
+		return nil, fmt.Errorf("unexpected object kind %q in merge request event", event.ObjectKind)
+	}
+
+	if event.Project.PathWithNamespace == "" || event.ObjectAttributes.IID == 0 || event.ObjectAttributes.SourceBranch == "" {
+		return nil, errors.New("merge request event is missing its project, number or source branch")
+	}
+
+	return event, nil
+}
+
+// IsOpen returns true if the event opens a merge request or pushes new commits to it
+func (e *MergeRequestEvent) IsOpen() bool {
+	switch e.ObjectAttributes.Action {
+	case MergeRequestAction_Open, MergeRequestAction_Reopen:
+		return true
+	case MergeRequestAction_Update:
+		// updates to the title, description or labels of a merge request do not change its preview
+		return e.ObjectAttributes.OldRev != ""
+	default:
+		return false
+	}
+}
+
+// IsClosed returns true if the event closes or merges a merge request
+func (e *MergeRequestEvent) IsClosed() bool {
+	switch e.ObjectAttributes.Action {
+	case MergeRequestAction_Close, MergeRequestAction_Merge:
+		return true
+	default:
+		return false
+	}
+}
+
+// ShortSHA returns the abbreviated SHA of the last commit of the merge request
+func (e *MergeRequestEvent) ShortSHA() string {
+	sha := e.ObjectAttributes.LastCommit.ID
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+
+	return sha
+}
+
+// SplitProjectPath splits the path of a GitLab project into its namespace, which may contain subgroups, and its name
+func SplitProjectPath(path string) (string, string, error) {
+	path = strings.Trim(path, "/")
+
+	idx := strings.LastIndex(path, "/")
+	if idx <= 0 || idx == len(path)-1 {
+		return "", "", fmt.Errorf("project path %q is not in the format <namespace>/<project>", path)
+	}
+
+	return path[:idx], path[idx+1:], nil
+}
+
+// PreviewWebhookURL returns the URL to which GitLab sends the merge request events of a preview environment
+func PreviewWebhookURL(serverURL, webhookID string) string {
+	return fmt.Sprintf("%s/api/webhooks/gitlab/%s", serverURL, webhookID)
+}
+
+// PreviewBranchAllowed returns true if a merge request from the given source branch should be previewed, using the same
+// branch filters as GitHub preview environments: when branches are set only those are previewed, and otherwise branches
+// which are deployed on their own are skipped to avoid a double deploy.
+func PreviewBranchAllowed(env *types.Environment, branch string) bool {
+	if len(env.GitRepoBranches) > 0 {
+		for _, br := range env.GitRepoBranches {
+			if br == branch {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, br := range env.GitDeployBranches {
+		if br == branch {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MergeRequestNoteInput is the content of the note posted to a merge request about its preview
+type MergeRequestNoteInput struct {
+	Status       types.DeploymentStatus
+	CommitSHA    string
+	CommitURL    string
+	DashboardURL string
+	// Subdomain is the URL of the preview, if it is known
+	Subdomain string
+}
+
+// MergeRequestNoteBody renders the note posted to a merge request about its preview
+func MergeRequestNoteBody(inp MergeRequestNoteInput) string {
+	body := "## Porter Preview Environments\n"
+
+	sha := fmt.Sprintf("`%s`", inp.CommitSHA)
+	if inp.CommitURL != "" {
+		sha = fmt.Sprintf("[`%s`](%s)", inp.CommitSHA, inp.CommitURL)
+	}
+
+	switch inp.Status {
+	case types.DeploymentStatusInactive:
+		body += "🗑️ The preview environment for this merge request has been deleted."
+		return body
+	case types.DeploymentStatusCreated:
+		body += fmt.Sprintf("✅ The latest SHA (%s) has been successfully deployed", sha)
+	case types.DeploymentStatusFailed, types.DeploymentStatusTimedOut:
+		body += fmt.Sprintf("❌ The latest SHA (%s) could not be deployed", sha)
+	default:
+		body += fmt.Sprintf("⏳ The latest SHA (%s) is being deployed", sha)
+	}
+
+	if inp.Subdomain != "" {
+		body += fmt.Sprintf(" to %s", inp.Subdomain)
+	}
+
+	body += "."
+
+	if inp.DashboardURL != "" {
+		body += fmt.Sprintf("\n\n[View the preview environment on Porter](%s)", inp.DashboardURL)
+	}
+
+	return body
+}
+
+// CreateOrUpdateMergeRequestNote posts a note to a merge request and returns its ID. When new notes are disabled, the
+// existing note is updated instead, and a new note is only posted if there is none or it was deleted.
+func CreateOrUpdateMergeRequestNote(
+	client *gitlab.Client,
+	projectPath string,
+	mergeRequestIID, noteID int,
+	newNotesDisabled bool,
+	body string,
+) (int, error) {
+	if newNotesDisabled && noteID != 0 {
+		note, resp, err := client.Notes.UpdateMergeRequestNote(projectPath, mergeRequestIID, noteID, &gitlab.UpdateMergeRequestNoteOptions{
+			Body: gitlab.String(body),
+		})
+		if err == nil {
+			return note.ID, nil
+		}
+
+		// the note may have been deleted, in which case a new one is posted
+		if resp == nil || resp.StatusCode != http.StatusNotFound {
+			return noteID, fmt.Errorf("error updating merge request note: %w", err)
+		}
+	}
+
+	note, _, err := client.Notes.CreateMergeRequestNote(projectPath, mergeRequestIID, &gitlab.CreateMergeRequestNoteOptions{
+		Body: gitlab.String(body),
+	})
+	if err != nil {
+		return noteID, fmt.Errorf("error creating merge request note: %w", err)
+	}
+
+	return note.ID, nil
+}
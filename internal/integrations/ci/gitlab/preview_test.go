@@ -0,0 +1,104 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMergeRequestEvent(t *testing.T) {
+	payload := []byte(`{
+		"object_kind": "merge_request",
+		"project": {"id": 15, "path_with_namespace": "acme/backend/api", "web_url": "https://gitlab.com/acme/backend/api"},
+		"object_attributes": {
+			"iid": 42,
+			"title": "Add billing",
+			"source_branch": "feature/billing",
+			"target_branch": "main",
+			"action": "update",
+			"oldrev": "1111111111111111111111111111111111111111",
+			"last_commit": {"id": "da1560886d4f094c3e6c9ef40349f7d38b5d27d7"}
+		}
+	}`)
+
+	event, err := ParseMergeRequestEvent(payload)
+	assert.NoError(t, err)
+	assert.Equal(t, "acme/backend/api", event.Project.PathWithNamespace)
+	assert.Equal(t, 42, event.ObjectAttributes.IID)
+	assert.Equal(t, "feature/billing", event.ObjectAttributes.SourceBranch)
+	assert.Equal(t, "da15608", event.ShortSHA())
+	assert.True(t, event.IsOpen())
+	assert.False(t, event.IsClosed())
+
+	// updates which do not push commits do not change the preview
+	event.ObjectAttributes.OldRev = ""
+	assert.False(t, event.IsOpen())
+
+	event.ObjectAttributes.Action = MergeRequestAction_Merge
+	assert.True(t, event.IsClosed())
+
+	_, err = ParseMergeRequestEvent([]byte(`{"object_kind": "push"}`))
+	assert.Error(t, err)
+
+	_, err = ParseMergeRequestEvent([]byte(`{"object_kind": "merge_request", "object_attributes": {"iid": 1}}`))
+	assert.Error(t, err)
+}
+
+func TestSplitProjectPath(t *testing.T) {
+	owner, name, err := SplitProjectPath("acme/backend/api")
+	assert.NoError(t, err)
+	assert.Equal(t, "acme/backend", owner)
+	assert.Equal(t, "api", name)
+
+	owner, name, err = SplitProjectPath("/acme/api/")
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", owner)
+	assert.Equal(t, "api", name)
+
+	_, _, err = SplitProjectPath("api")
+	assert.Error(t, err)
+}
+
+func TestPreviewBranchAllowed(t *testing.T) {
+	env := &types.Environment{}
+	assert.True(t, PreviewBranchAllowed(env, "feature"))
+
+	// branches which are deployed on their own are not previewed
+	env.GitDeployBranches = []string{"staging"}
+	assert.False(t, PreviewBranchAllowed(env, "staging"))
+	assert.True(t, PreviewBranchAllowed(env, "feature"))
+
+	// when branches are set, only those are previewed
+	env.GitRepoBranches = []string{"feature", "staging"}
+	assert.True(t, PreviewBranchAllowed(env, "feature"))
+	assert.True(t, PreviewBranchAllowed(env, "staging"))
+	assert.False(t, PreviewBranchAllowed(env, "other"))
+}
+
+func TestMergeRequestNoteBody(t *testing.T) {
+	body := MergeRequestNoteBody(MergeRequestNoteInput{
+		Status:       types.DeploymentStatusCreating,
+		CommitSHA:    "da15608",
+		CommitURL:    "https://gitlab.com/acme/api/-/commit/da15608",
+		DashboardURL: "https://dashboard.porter.run/preview-environments/apps?target=abc",
+	})
+	assert.Equal(t, "## Porter Preview Environments\n"+
+		"⏳ The latest SHA ([`da15608`](https://gitlab.com/acme/api/-/commit/da15608)) is being deployed.\n\n"+
+		"[View the preview environment on Porter](https://dashboard.porter.run/preview-environments/apps?target=abc)", body)
+
+	body = MergeRequestNoteBody(MergeRequestNoteInput{
+		Status:    types.DeploymentStatusCreated,
+		CommitSHA: "da15608",
+		Subdomain: "https://feature.acme.dev",
+	})
+	assert.Equal(t, "## Porter Preview Environments\n"+
+		"✅ The latest SHA (`da15608`) has been successfully deployed to https://feature.acme.dev.", body)
+
+	body = MergeRequestNoteBody(MergeRequestNoteInput{
+		Status:       types.DeploymentStatusInactive,
+		CommitSHA:    "da15608",
+		DashboardURL: "https://dashboard.porter.run",
+	})
+	assert.Equal(t, "## Porter Preview Environments\n🗑️ The preview environment for this merge request has been deleted.", body)
+}
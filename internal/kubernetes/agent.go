@@ -60,6 +60,9 @@ type Agent struct {
 
 	// context is used here as a workaround since RESTClientGetter and kubernetes.Interface do not support contexts
 	context context.Context
+
+	// newExecExecutor replaces the executor used by ExecPod, and is only set on testing agents
+	newExecExecutor func(opts ExecPodOptions) (remotecommand.Executor, error)
 }
 
 type Message struct {
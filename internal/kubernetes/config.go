@@ -31,6 +31,7 @@ import (
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/tools/remotecommand"
 	"k8s.io/client-go/util/homedir"
 
 	ints "github.com/porter-dev/porter/internal/models/integrations"
@@ -224,6 +225,15 @@ func GetAgentTesting(objects ...runtime.Object) *Agent {
 	return &agent
 }
 
+// GetExecAgentTesting creates a new testing Agent which runs the commands of ExecPod with the executor returned by
+// newExecutor, since the fake clientset cannot open streams to pods
+func GetExecAgentTesting(newExecutor func(opts ExecPodOptions) (remotecommand.Executor, error), objects ...runtime.Object) *Agent {
+	agent := GetAgentTesting(objects...)
+	agent.newExecExecutor = newExecutor
+
+	return agent
+}
+
 // OutOfClusterConfig is the set of parameters required for an out-of-cluster connection.
 // This implements RESTClientGetter
 type OutOfClusterConfig struct {
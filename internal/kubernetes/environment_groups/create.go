@@ -15,7 +15,8 @@ import (
 // CreateOrUpdateBaseEnvironmentGroup creates a new environment group in the porter-env-group namespace. If porter-env-group does not exist, it will be created.
 // If no existing environmentGroup exists by this name, a new one will be created as version 1, denoted by the label "porter.run/environment-group-version: 1".
 // If an environmentGroup already exists by this name, a new version will be created, and the label will be updated to reflect the new version.
-// Providing the Version field to this function will be ignored in order to not accidentally overwrite versions
+// Providing the Version field to this function will be ignored in order to not accidentally overwrite versions. The Author field is recorded
+// on the new version.
 func CreateOrUpdateBaseEnvironmentGroup(ctx context.Context, a *kubernetes.Agent, environmentGroup EnvironmentGroup, additionalLabels map[string]string) error {
 	ctx, span := telemetry.NewSpan(ctx, "create-environment-group")
 	defer span.End()
@@ -64,6 +65,7 @@ func CreateOrUpdateBaseEnvironmentGroup(ctx context.Context, a *kubernetes.Agent
 		SecretVariables: environmentGroup.SecretVariables,
 		Version:         latestEnvironmentGroup.Version + 1,
 		CreatedAtUTC:    environmentGroup.CreatedAtUTC,
+		Author:          environmentGroup.Author,
 	}
 
 	err = createVersionedEnvironmentGroupInNamespace(ctx, a, newEnvironmentGroup, Namespace_EnvironmentGroups, additionalLabels)
@@ -138,6 +140,9 @@ func createVersionedEnvironmentGroupInNamespace(ctx context.Context, a *kubernet
 	for k, v := range additionalLabels {
 		configMap.Labels[k] = v
 	}
+	if environmentGroup.Author != "" {
+		configMap.Annotations = map[string]string{AnnotationKey_EnvironmentGroupAuthor: environmentGroup.Author}
+	}
 
 	err := createConfigMapWithVersion(ctx, a, configMap, environmentGroup.Version)
 	if err != nil {
@@ -164,6 +169,9 @@ func createVersionedEnvironmentGroupInNamespace(ctx context.Context, a *kubernet
 	for k, v := range additionalLabels {
 		secret.Labels[k] = v
 	}
+	if environmentGroup.Author != "" {
+		secret.Annotations = map[string]string{AnnotationKey_EnvironmentGroupAuthor: environmentGroup.Author}
+	}
 
 	err = createSecretWithVersion(ctx, a, secret, environmentGroup.Version)
 	if err != nil {
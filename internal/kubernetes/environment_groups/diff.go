@@ -0,0 +1,109 @@
+package environment_groups
+
+import "sort"
+
+// EnvironmentGroupKeyKind is the kind of value stored under a key of an environment group
+type EnvironmentGroupKeyKind string
+
+const (
+	// EnvironmentGroupKeyKind_Variable is a non-secret variable
+	EnvironmentGroupKeyKind_Variable EnvironmentGroupKeyKind = "variable"
+	// EnvironmentGroupKeyKind_Secret is a secret variable
+	EnvironmentGroupKeyKind_Secret EnvironmentGroupKeyKind = "secret"
+	// EnvironmentGroupKeyKind_File is a file, whose contents are stored as a secret
+	EnvironmentGroupKeyKind_File EnvironmentGroupKeyKind = "file"
+)
+
+// EnvironmentGroupKeyChange is how a key changed between two versions of an environment group
+type EnvironmentGroupKeyChange string
+
+const (
+	// EnvironmentGroupKeyChange_Added means the key only exists in the newer version
+	EnvironmentGroupKeyChange_Added EnvironmentGroupKeyChange = "added"
+	// EnvironmentGroupKeyChange_Removed means the key only exists in the older version
+	EnvironmentGroupKeyChange_Removed EnvironmentGroupKeyChange = "removed"
+	// EnvironmentGroupKeyChange_Changed means the key exists in both versions with different values
+	EnvironmentGroupKeyChange_Changed EnvironmentGroupKeyChange = "changed"
+)
+
+// EnvironmentGroupKeyDiff is the change to a single key between two versions of an environment group
+type EnvironmentGroupKeyDiff struct {
+	Key    string                    `json:"key"`
+	Kind   EnvironmentGroupKeyKind   `json:"kind"`
+	Change EnvironmentGroupKeyChange `json:"change"`
+	// OldValue is empty when the key was added. Secret values are masked unless revealed.
+	OldValue string `json:"old_value,omitempty"`
+	// NewValue is empty when the key was removed. Secret values are masked unless revealed.
+	NewValue string `json:"new_value,omitempty"`
+}
+
+// DiffEnvironmentGroups returns the keys which differ between two versions of an environment group, ordered by kind and
+// then by key. Secret and file values are replaced with EnvGroupSecretDummyValue unless revealSecrets is set, so the
+// versions must contain the true secret values for changed secrets to be detected.
+func DiffEnvironmentGroups(from, to EnvironmentGroup, revealSecrets bool) []EnvironmentGroupKeyDiff {
+	fromFiles := make(map[string]string)
+	for _, file := range from.Files {
+		fromFiles[file.Name] = file.Contents
+	}
+	toFiles := make(map[string]string)
+	for _, file := range to.Files {
+		toFiles[file.Name] = file.Contents
+	}
+
+	var diffs []EnvironmentGroupKeyDiff
+	diffs = append(diffs, diffValues(EnvironmentGroupKeyKind_Variable, from.Variables, to.Variables, true)...)
+	diffs = append(diffs, diffValues(EnvironmentGroupKeyKind_Secret, from.SecretVariables, to.SecretVariables, revealSecrets)...)
+	diffs = append(diffs, diffValues(EnvironmentGroupKeyKind_File, fromFiles, toFiles, revealSecrets)...)
+
+	return diffs
+}
+
+// diffValues returns the keys which differ between two sets of values of the same kind, ordered by key
+func diffValues(kind EnvironmentGroupKeyKind, from, to map[string]string, reveal bool) []EnvironmentGroupKeyDiff {
+	display := func(value string) string {
+		if reveal {
+			return value
+		}
+		return EnvGroupSecretDummyValue
+	}
+
+	var diffs []EnvironmentGroupKeyDiff
+
+	for key, oldValue := range from {
+		newValue, ok := to[key]
+		switch {
+		case !ok:
+			diffs = append(diffs, EnvironmentGroupKeyDiff{
+				Key:      key,
+				Kind:     kind,
+				Change:   EnvironmentGroupKeyChange_Removed,
+				OldValue: display(oldValue),
+			})
+		case newValue != oldValue:
+			diffs = append(diffs, EnvironmentGroupKeyDiff{
+				Key:      key,
+				Kind:     kind,
+				Change:   EnvironmentGroupKeyChange_Changed,
+				OldValue: display(oldValue),
+				NewValue: display(newValue),
+			})
+		}
+	}
+
+	for key, newValue := range to {
+		if _, ok := from[key]; !ok {
+			diffs = append(diffs, EnvironmentGroupKeyDiff{
+				Key:      key,
+				Kind:     kind,
+				Change:   EnvironmentGroupKeyChange_Added,
+				NewValue: display(newValue),
+			})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Key < diffs[j].Key
+	})
+
+	return diffs
+}
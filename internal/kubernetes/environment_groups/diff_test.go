@@ -0,0 +1,39 @@
+package environment_groups
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffEnvironmentGroups(t *testing.T) {
+	from := EnvironmentGroup{
+		Name:            "shared",
+		Version:         1,
+		Variables:       map[string]string{"LOG_LEVEL": "info", "REGION": "us-east-1", "OLD_FLAG": "true"},
+		SecretVariables: map[string]string{"DB_PASSWORD": "hunter2", "API_KEY": "abc"},
+		Files:           []EnvGroupFile{{Name: "ca.pem", Contents: "old cert"}},
+	}
+	to := EnvironmentGroup{
+		Name:            "shared",
+		Version:         2,
+		Variables:       map[string]string{"LOG_LEVEL": "debug", "REGION": "us-east-1", "NEW_FLAG": "false"},
+		SecretVariables: map[string]string{"DB_PASSWORD": "correct-horse", "API_KEY": "abc"},
+		Files:           []EnvGroupFile{{Name: "ca.pem", Contents: "new cert"}},
+	}
+
+	masked := DiffEnvironmentGroups(from, to, false)
+	assert.Equal(t, []EnvironmentGroupKeyDiff{
+		{Key: "LOG_LEVEL", Kind: EnvironmentGroupKeyKind_Variable, Change: EnvironmentGroupKeyChange_Changed, OldValue: "info", NewValue: "debug"},
+		{Key: "NEW_FLAG", Kind: EnvironmentGroupKeyKind_Variable, Change: EnvironmentGroupKeyChange_Added, NewValue: "false"},
+		{Key: "OLD_FLAG", Kind: EnvironmentGroupKeyKind_Variable, Change: EnvironmentGroupKeyChange_Removed, OldValue: "true"},
+		{Key: "DB_PASSWORD", Kind: EnvironmentGroupKeyKind_Secret, Change: EnvironmentGroupKeyChange_Changed, OldValue: EnvGroupSecretDummyValue, NewValue: EnvGroupSecretDummyValue},
+		{Key: "ca.pem", Kind: EnvironmentGroupKeyKind_File, Change: EnvironmentGroupKeyChange_Changed, OldValue: EnvGroupSecretDummyValue, NewValue: EnvGroupSecretDummyValue},
+	}, masked)
+
+	revealed := DiffEnvironmentGroups(from, to, true)
+	assert.Equal(t, "hunter2", revealed[3].OldValue)
+	assert.Equal(t, "correct-horse", revealed[3].NewValue)
+
+	assert.Empty(t, DiffEnvironmentGroups(to, to, false))
+}
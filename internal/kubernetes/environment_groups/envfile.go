@@ -0,0 +1,490 @@
+package environment_groups
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// EnvFileFormat is a file format which environment groups can be imported from and exported to
+type EnvFileFormat string
+
+const (
+	// EnvFileFormat_Dotenv is a .env file of KEY=value lines. Keys after a "# porter:secrets" line are secrets. Files are not supported.
+	EnvFileFormat_Dotenv EnvFileFormat = "dotenv"
+	// EnvFileFormat_JSON is a JSON object with variables, secrets and files fields, or a flat object of keys and values
+	EnvFileFormat_JSON EnvFileFormat = "json"
+	// EnvFileFormat_YAML is the YAML equivalent of EnvFileFormat_JSON
+	EnvFileFormat_YAML EnvFileFormat = "yaml"
+)
+
+// EnvImportMode is how imported values are combined with the existing values of an environment group
+type EnvImportMode string
+
+const (
+	// EnvImportMode_Add only adds keys which do not exist yet, leaving existing values unchanged
+	EnvImportMode_Add EnvImportMode = "add"
+	// EnvImportMode_Overwrite adds new keys and replaces the values of existing keys
+	EnvImportMode_Overwrite EnvImportMode = "overwrite"
+	// EnvImportMode_Prune makes the environment group equal to the imported values, removing keys which are not imported
+	EnvImportMode_Prune EnvImportMode = "prune"
+)
+
+// dotenvSecretsMarker is the comment which starts the secrets section of a dotenv file
+const dotenvSecretsMarker = "# porter:secrets"
+
+// ErrInvalidEnvFile is returned when an env file cannot be parsed
+var ErrInvalidEnvFile = errors.New("invalid env file")
+
+// envKeyRegex matches keys which can be stored in a configmap or secret
+var envKeyRegex = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// envFileDocument is the structure of JSON and YAML env files
+type envFileDocument struct {
+	Variables map[string]string `json:"variables,omitempty"`
+	Secrets   map[string]string `json:"secrets,omitempty"`
+	Files     []EnvGroupFile    `json:"files,omitempty"`
+}
+
+// ParseEnvFileInput is the input for ParseEnvFile
+type ParseEnvFileInput struct {
+	// Format is the format of the contents
+	Format EnvFileFormat
+	// Contents are the decrypted contents of the file
+	Contents []byte
+	// AllSecrets stores every key of a dotenv or flat file as a secret, as is common for files exported from other platforms
+	AllSecrets bool
+}
+
+// ParseEnvFile parses an env file into the variables, secret variables and files of an environment group
+func ParseEnvFile(inp ParseEnvFileInput) (EnvironmentGroup, error) {
+	var eg EnvironmentGroup
+
+	var doc envFileDocument
+	var err error
+
+	switch inp.Format {
+	case EnvFileFormat_Dotenv:
+		doc, err = parseDotenv(inp.Contents, inp.AllSecrets)
+	case EnvFileFormat_JSON:
+		doc, err = parseStructuredEnvFile(inp.Contents, inp.AllSecrets)
+	case EnvFileFormat_YAML:
+		var contents []byte
+		contents, err = yaml.YAMLToJSON(inp.Contents)
+		if err != nil {
+			return eg, fmt.Errorf("%w: %s", ErrInvalidEnvFile, err.Error())
+		}
+		doc, err = parseStructuredEnvFile(contents, inp.AllSecrets)
+	default:
+		return eg, fmt.Errorf("%w: unsupported format %q, must be one of dotenv, json or yaml", ErrInvalidEnvFile, inp.Format)
+	}
+	if err != nil {
+		return eg, err
+	}
+
+	seen := make(map[string]string)
+	check := func(key, kind string) error {
+		if !envKeyRegex.MatchString(key) {
+			return fmt.Errorf("%w: %s %q may only contain letters, digits, '-', '_' and '.'", ErrInvalidEnvFile, kind, key)
+		}
+		if other, ok := seen[key]; ok {
+			return fmt.Errorf("%w: %q is both a %s and a %s", ErrInvalidEnvFile, key, other, kind)
+		}
+		seen[key] = kind
+		return nil
+	}
+
+	for k := range doc.Variables {
+		if err := check(k, "variable"); err != nil {
+			return eg, err
+		}
+	}
+	for k := range doc.Secrets {
+		if err := check(k, "secret"); err != nil {
+			return eg, err
+		}
+	}
+	for _, file := range doc.Files {
+		if !envKeyRegex.MatchString(file.Name) {
+			return eg, fmt.Errorf("%w: file name %q may only contain letters, digits, '-', '_' and '.'", ErrInvalidEnvFile, file.Name)
+		}
+	}
+
+	eg.Variables = doc.Variables
+	eg.SecretVariables = doc.Secrets
+	eg.Files = doc.Files
+
+	return eg, nil
+}
+
+// parseStructuredEnvFile parses a JSON env file. Objects without any of the variables, secrets or files fields are treated as a flat
+// object of keys and values.
+func parseStructuredEnvFile(contents []byte, allSecrets bool) (envFileDocument, error) {
+	var doc envFileDocument
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(contents, &raw); err != nil {
+		return doc, fmt.Errorf("%w: %s", ErrInvalidEnvFile, err.Error())
+	}
+
+	_, hasVariables := raw["variables"]
+	_, hasSecrets := raw["secrets"]
+	_, hasFiles := raw["files"]
+
+	if !hasVariables && !hasSecrets && !hasFiles {
+		values, err := stringValues(raw)
+		if err != nil {
+			return doc, err
+		}
+
+		if allSecrets {
+			doc.Secrets = values
+		} else {
+			doc.Variables = values
+		}
+		return doc, nil
+	}
+
+	for key, value := range raw {
+		switch key {
+		case "variables", "secrets":
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(value, &fields); err != nil {
+				return doc, fmt.Errorf("%w: %s must be an object: %s", ErrInvalidEnvFile, key, err.Error())
+			}
+
+			values, err := stringValues(fields)
+			if err != nil {
+				return doc, err
+			}
+
+			if key == "secrets" || allSecrets {
+				doc.Secrets = mergeValues(doc.Secrets, values)
+			} else {
+				doc.Variables = mergeValues(doc.Variables, values)
+			}
+		case "files":
+			if err := json.Unmarshal(value, &doc.Files); err != nil {
+				return doc, fmt.Errorf("%w: files must be a list of objects with name and contents: %s", ErrInvalidEnvFile, err.Error())
+			}
+		default:
+			return doc, fmt.Errorf("%w: unknown field %q, expected variables, secrets or files", ErrInvalidEnvFile, key)
+		}
+	}
+
+	return doc, nil
+}
+
+// stringValues converts the scalar values of an object to strings, since every environment variable is a string
+func stringValues(fields map[string]json.RawMessage) (map[string]string, error) {
+	values := make(map[string]string)
+
+	for key, raw := range fields {
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			values[key] = s
+			continue
+		}
+
+		trimmed := bytes.TrimSpace(raw)
+		if len(trimmed) == 0 || trimmed[0] == '{' || trimmed[0] == '[' || string(trimmed) == "null" {
+			return nil, fmt.Errorf("%w: value of %q must be a string, number or boolean", ErrInvalidEnvFile, key)
+		}
+
+		// numbers and booleans are kept as written
+		values[key] = string(trimmed)
+	}
+
+	return values, nil
+}
+
+func mergeValues(dst, src map[string]string) map[string]string {
+	if dst == nil {
+		dst = make(map[string]string)
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+// parseDotenv parses KEY=value lines. Values may be single quoted, which is literal, or double quoted, which supports escapes and may span
+// lines. Lines starting with # are comments, and keys after a "# porter:secrets" comment are secrets.
+func parseDotenv(contents []byte, allSecrets bool) (envFileDocument, error) {
+	doc := envFileDocument{
+		Variables: make(map[string]string),
+		Secrets:   make(map[string]string),
+	}
+
+	inSecrets := allSecrets
+	lines := strings.Split(strings.ReplaceAll(string(contents), "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimSpace(lines[i])
+
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			if strings.EqualFold(line, dotenvSecretsMarker) {
+				inSecrets = true
+			}
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		eq := strings.Index(line, "=")
+		if eq <= 0 {
+			return doc, fmt.Errorf("%w: line %d is not KEY=value", ErrInvalidEnvFile, lineNumber)
+		}
+
+		key := strings.TrimSpace(line[:eq])
+		rest := strings.TrimLeft(line[eq+1:], " \t")
+
+		var value string
+		switch {
+		case strings.HasPrefix(rest, `"`):
+			// double quoted values may continue on the following lines until the closing quote
+			raw := rest[1:]
+			for {
+				end := closingQuote(raw)
+				if end >= 0 {
+					value = unescapeDotenv(raw[:end])
+					break
+				}
+				i++
+				if i >= len(lines) {
+					return doc, fmt.Errorf("%w: unterminated quoted value for %s on line %d", ErrInvalidEnvFile, key, lineNumber)
+				}
+				raw += "\n" + lines[i]
+			}
+		case strings.HasPrefix(rest, "'"):
+			end := strings.Index(rest[1:], "'")
+			if end < 0 {
+				return doc, fmt.Errorf("%w: unterminated quoted value for %s on line %d", ErrInvalidEnvFile, key, lineNumber)
+			}
+			value = rest[1 : end+1]
+		default:
+			value = rest
+			if idx := strings.Index(value, " #"); idx >= 0 {
+				value = value[:idx]
+			}
+			value = strings.TrimSpace(value)
+		}
+
+		if inSecrets {
+			doc.Secrets[key] = value
+		} else {
+			doc.Variables[key] = value
+		}
+	}
+
+	return doc, nil
+}
+
+// closingQuote returns the index of the first unescaped double quote, or -1
+func closingQuote(s string) int {
+	escaped := false
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			return i
+		}
+	}
+	return -1
+}
+
+func unescapeDotenv(s string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		if !escaped {
+			if r == '\\' {
+				escaped = true
+				continue
+			}
+			b.WriteRune(r)
+			continue
+		}
+
+		escaped = false
+		switch r {
+		case 'n':
+			b.WriteRune('\n')
+		case 'r':
+			b.WriteRune('\r')
+		case 't':
+			b.WriteRune('\t')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// FormatEnvFile writes the variables, secret variables and files of an environment group in the given format, with keys in order
+func FormatEnvFile(eg EnvironmentGroup, format EnvFileFormat) ([]byte, error) {
+	doc := envFileDocument{
+		Variables: eg.Variables,
+		Secrets:   eg.SecretVariables,
+		Files:     eg.Files,
+	}
+
+	switch format {
+	case EnvFileFormat_Dotenv:
+		if len(eg.Files) > 0 {
+			return nil, errors.New("environment groups with files cannot be exported as dotenv, use json or yaml instead")
+		}
+		return formatDotenv(doc), nil
+	case EnvFileFormat_JSON:
+		out, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(out, '\n'), nil
+	case EnvFileFormat_YAML:
+		return yaml.Marshal(doc)
+	default:
+		return nil, fmt.Errorf("unsupported format %q, must be one of dotenv, json or yaml", format)
+	}
+}
+
+func formatDotenv(doc envFileDocument) []byte {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+
+	writeValues := func(values map[string]string) {
+		keys := make([]string, 0, len(values))
+		for k := range values {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			fmt.Fprintf(w, "%s=%s\n", k, quoteDotenv(values[k])) // nolint:errcheck
+		}
+	}
+
+	writeValues(doc.Variables)
+	if len(doc.Secrets) > 0 {
+		if len(doc.Variables) > 0 {
+			w.WriteString("\n") // nolint:errcheck
+		}
+		w.WriteString(dotenvSecretsMarker + "\n") // nolint:errcheck
+		writeValues(doc.Secrets)
+	}
+
+	w.Flush() // nolint:errcheck
+	return buf.Bytes()
+}
+
+// quoteDotenv double quotes a value if it would not be read back unchanged without quotes
+func quoteDotenv(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n\r\"'#\\") {
+		return value
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// MergeEnvironmentGroup returns the values an environment group will have once the imported values are combined with its current values.
+// Secrets and files are masked with EnvGroupSecretDummyValue in exported env files unless their values are revealed, so an imported secret
+// or file whose value is the mask keeps its current value. Masked values of keys which do not exist yet are rejected.
+func MergeEnvironmentGroup(current, imported EnvironmentGroup, mode EnvImportMode) (EnvironmentGroup, error) {
+	merged := EnvironmentGroup{
+		Name:            current.Name,
+		Type:            current.Type,
+		Variables:       make(map[string]string),
+		SecretVariables: make(map[string]string),
+	}
+
+	switch mode {
+	case EnvImportMode_Add, EnvImportMode_Overwrite:
+		for k, v := range current.Variables {
+			merged.Variables[k] = v
+		}
+		for k, v := range current.SecretVariables {
+			merged.SecretVariables[k] = v
+		}
+		merged.Files = append(merged.Files, current.Files...)
+	case EnvImportMode_Prune:
+	default:
+		return merged, fmt.Errorf("unsupported import mode %q, must be one of add, overwrite or prune", mode)
+	}
+
+	exists := func(key string) bool {
+		_, isVariable := merged.Variables[key]
+		_, isSecret := merged.SecretVariables[key]
+		return isVariable || isSecret
+	}
+
+	// a key which moves between variables and secrets is replaced, so that it is never stored as both
+	for k, v := range imported.Variables {
+		if mode == EnvImportMode_Add && exists(k) {
+			continue
+		}
+		delete(merged.SecretVariables, k)
+		merged.Variables[k] = v
+	}
+	for k, v := range imported.SecretVariables {
+		if mode == EnvImportMode_Add && exists(k) {
+			continue
+		}
+		if v == EnvGroupSecretDummyValue {
+			currentValue, ok := current.SecretVariables[k]
+			if !ok {
+				return merged, fmt.Errorf("%w: secret %q is masked, but has no current value to keep", ErrInvalidEnvFile, k)
+			}
+			v = currentValue
+		}
+		delete(merged.Variables, k)
+		merged.SecretVariables[k] = v
+	}
+
+	for _, file := range imported.Files {
+		if file.Contents == EnvGroupSecretDummyValue {
+			found := false
+			for _, existing := range current.Files {
+				if existing.Name == file.Name {
+					file.Contents = existing.Contents
+					found = true
+					break
+				}
+			}
+			if !found {
+				return merged, fmt.Errorf("%w: file %q is masked, but has no current contents to keep", ErrInvalidEnvFile, file.Name)
+			}
+		}
+
+		idx := -1
+		for i, existing := range merged.Files {
+			if existing.Name == file.Name {
+				idx = i
+				break
+			}
+		}
+
+		switch {
+		case idx < 0:
+			merged.Files = append(merged.Files, file)
+		case mode != EnvImportMode_Add:
+			merged.Files[idx] = file
+		}
+	}
+
+	return merged, nil
+}
@@ -0,0 +1,218 @@
+package environment_groups
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/telemetry"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+// ErrEnvironmentGroupVersionNotFound is returned when a version of an environment group does not exist in the porter-env-group namespace
+var ErrEnvironmentGroupVersionNotFound = errors.New("environment group version not found")
+
+// ErrAmbiguousEnvironmentGroupVersion is returned when concurrent changes created several versions of an environment group, so a version
+// cannot be attributed to a single change
+var ErrAmbiguousEnvironmentGroupVersion = errors.New("environment group version cannot be attributed to a single change")
+
+// BaseEnvironmentGroupVersions returns every version of an environment group stored in the porter-env-group namespace, newest first.
+// Secret values are replaced with a dummy value, as in ListEnvironmentGroups.
+func BaseEnvironmentGroupVersions(ctx context.Context, a *kubernetes.Agent, environmentGroupName string) ([]EnvironmentGroup, error) {
+	ctx, span := telemetry.NewSpan(ctx, "base-env-group-versions")
+	defer span.End()
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "environment-group-name", Value: environmentGroupName})
+
+	if environmentGroupName == "" {
+		return nil, telemetry.Error(ctx, span, nil, "environment group name cannot be empty")
+	}
+
+	versions, err := ListEnvironmentGroups(ctx, a, WithEnvironmentGroupName(environmentGroupName), WithNamespace(Namespace_EnvironmentGroups))
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "unable to list base environment groups")
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version > versions[j].Version
+	})
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "version-count", Value: len(versions)})
+
+	return versions, nil
+}
+
+// BaseEnvironmentGroupVersionWithSecrets returns a single version of an environment group stored in the porter-env-group namespace, including
+// its true secret values. This is only intended for recreating an old version, and its secret values must never be returned to the user.
+func BaseEnvironmentGroupVersionWithSecrets(ctx context.Context, a *kubernetes.Agent, environmentGroupName string, version int) (EnvironmentGroup, error) {
+	ctx, span := telemetry.NewSpan(ctx, "base-env-group-version-with-secrets")
+	defer span.End()
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "environment-group-name", Value: environmentGroupName},
+		telemetry.AttributeKV{Key: "environment-group-version", Value: version},
+	)
+
+	return baseEnvironmentGroupVersion(ctx, a, environmentGroupName, version)
+}
+
+// baseEnvironmentGroupVersion returns a single version of an environment group, including its true secret values
+func baseEnvironmentGroupVersion(ctx context.Context, a *kubernetes.Agent, environmentGroupName string, version int) (EnvironmentGroup, error) {
+	var eg EnvironmentGroup
+
+	if environmentGroupName == "" {
+		return eg, errors.New("environment group name cannot be empty")
+	}
+	if version <= 0 {
+		return eg, fmt.Errorf("%w: versions start at 1", ErrEnvironmentGroupVersionNotFound)
+	}
+
+	versions, err := listEnvironmentGroups(ctx, a,
+		WithEnvironmentGroupName(environmentGroupName),
+		WithEnvironmentGroupVersion(version),
+		WithNamespace(Namespace_EnvironmentGroups),
+	)
+	if err != nil {
+		return eg, fmt.Errorf("unable to list base environment groups: %w", err)
+	}
+
+	if len(versions) != 1 {
+		return eg, fmt.Errorf("%w: version %d of %s", ErrEnvironmentGroupVersionNotFound, version, environmentGroupName)
+	}
+
+	return versions[0], nil
+}
+
+// DiffBaseEnvironmentGroupVersionsInput is the input for DiffBaseEnvironmentGroupVersions
+type DiffBaseEnvironmentGroupVersionsInput struct {
+	// Name is the name of the environment group
+	Name string
+	// FromVersion is the older version being compared
+	FromVersion int
+	// ToVersion is the newer version being compared
+	ToVersion int
+	// RevealSecrets includes secret and file values in the diff, rather than a dummy value
+	RevealSecrets bool
+}
+
+// DiffBaseEnvironmentGroupVersions returns the keys which differ between two versions of an environment group stored in the porter-env-group namespace.
+// Secrets are compared by their true values, but only included in the diff if RevealSecrets is set.
+func DiffBaseEnvironmentGroupVersions(ctx context.Context, a *kubernetes.Agent, inp DiffBaseEnvironmentGroupVersionsInput) ([]EnvironmentGroupKeyDiff, error) {
+	ctx, span := telemetry.NewSpan(ctx, "diff-base-env-group-versions")
+	defer span.End()
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "environment-group-name", Value: inp.Name},
+		telemetry.AttributeKV{Key: "from-version", Value: inp.FromVersion},
+		telemetry.AttributeKV{Key: "to-version", Value: inp.ToVersion},
+		telemetry.AttributeKV{Key: "reveal-secrets", Value: inp.RevealSecrets},
+	)
+
+	from, err := baseEnvironmentGroupVersion(ctx, a, inp.Name, inp.FromVersion)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "unable to get from version")
+	}
+
+	to, err := baseEnvironmentGroupVersion(ctx, a, inp.Name, inp.ToVersion)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "unable to get to version")
+	}
+
+	return DiffEnvironmentGroups(from, to, inp.RevealSecrets), nil
+}
+
+// SetBaseEnvironmentGroupAuthorInput is the input for SetBaseEnvironmentGroupAuthor
+type SetBaseEnvironmentGroupAuthorInput struct {
+	// Name is the name of the environment group
+	Name string
+	// PreviousVersion is the latest version of the environment group before the author created a version, or 0 if the environment
+	// group did not exist
+	PreviousVersion int
+	// Author is recorded as the author of the version created after PreviousVersion
+	Author string
+}
+
+// SetBaseEnvironmentGroupAuthor records the author of the version of an environment group which was created after PreviousVersion on its
+// configmap and secrets. Versions are created by the cluster control plane, which does not return the version it created, so the author
+// is recorded right after the version is created. Versions are numbered in the order they are created, so the version is only attributed
+// to the author if it is the only version newer than PreviousVersion. If concurrent changes created other versions, ErrAmbiguousEnvironmentGroupVersion
+// is returned rather than attributing a version to the wrong author. A version which already has an author is left unchanged.
+func SetBaseEnvironmentGroupAuthor(ctx context.Context, a *kubernetes.Agent, inp SetBaseEnvironmentGroupAuthorInput) error {
+	ctx, span := telemetry.NewSpan(ctx, "set-base-env-group-author")
+	defer span.End()
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "environment-group-name", Value: inp.Name},
+		telemetry.AttributeKV{Key: "previous-version", Value: inp.PreviousVersion},
+	)
+
+	if inp.Author == "" {
+		return nil
+	}
+
+	versions, err := ListEnvironmentGroups(ctx, a, WithEnvironmentGroupName(inp.Name), WithNamespace(Namespace_EnvironmentGroups))
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "unable to list base environment groups")
+	}
+
+	var created []EnvironmentGroup
+	for _, version := range versions {
+		if version.Version > inp.PreviousVersion {
+			created = append(created, version)
+		}
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "created-version-count", Value: len(created)})
+
+	if len(created) == 0 {
+		return telemetry.Error(ctx, span, nil, "no environment group version was created")
+	}
+	if len(created) > 1 {
+		return telemetry.Error(ctx, span, ErrAmbiguousEnvironmentGroupVersion, "more than one environment group version was created")
+	}
+
+	version := created[0]
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "environment-group-version", Value: version.Version})
+
+	if version.Author != "" {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				AnnotationKey_EnvironmentGroupAuthor: inp.Author,
+			},
+		},
+	})
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "unable to marshal author patch")
+	}
+
+	listOptions := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s=%d", LabelKey_EnvironmentGroupName, inp.Name, LabelKey_EnvironmentGroupVersion, version.Version),
+	}
+
+	configMaps, err := a.Clientset.CoreV1().ConfigMaps(Namespace_EnvironmentGroups).List(ctx, listOptions)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "unable to list environment group configmaps")
+	}
+	for _, cm := range configMaps.Items {
+		_, err := a.Clientset.CoreV1().ConfigMaps(Namespace_EnvironmentGroups).Patch(ctx, cm.Name, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			return telemetry.Error(ctx, span, err, "unable to annotate environment group configmap")
+		}
+	}
+
+	secrets, err := a.Clientset.CoreV1().Secrets(Namespace_EnvironmentGroups).List(ctx, listOptions)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "unable to list environment group secrets")
+	}
+	for _, secret := range secrets.Items {
+		_, err := a.Clientset.CoreV1().Secrets(Namespace_EnvironmentGroups).Patch(ctx, secret.Name, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			return telemetry.Error(ctx, span, err, "unable to annotate environment group secret")
+		}
+	}
+
+	return nil
+}
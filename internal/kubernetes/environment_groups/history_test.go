@@ -0,0 +1,100 @@
+package environment_groups
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// historyTestVersion returns the configmap and secret of a version of an environment group
+func historyTestVersion(name string, version int, author string) []runtime.Object {
+	meta := metav1.ObjectMeta{
+		Name:      name + "." + strconv.Itoa(version),
+		Namespace: Namespace_EnvironmentGroups,
+		Labels: map[string]string{
+			LabelKey_EnvironmentGroupName:    name,
+			LabelKey_EnvironmentGroupVersion: strconv.Itoa(version),
+		},
+	}
+	if author != "" {
+		meta.Annotations = map[string]string{AnnotationKey_EnvironmentGroupAuthor: author}
+	}
+
+	return []runtime.Object{
+		&v1.ConfigMap{ObjectMeta: meta, Data: map[string]string{"LOG_LEVEL": "info"}},
+		&v1.Secret{ObjectMeta: *meta.DeepCopy(), Data: map[string][]byte{"DB_PASSWORD": []byte("hunter2")}},
+	}
+}
+
+// historyTestAuthors returns the author of each version of an environment group
+func historyTestAuthors(t *testing.T, agent *kubernetes.Agent, name string) map[int]string {
+	t.Helper()
+
+	versions, err := BaseEnvironmentGroupVersions(context.Background(), agent, name)
+	assert.NoError(t, err)
+
+	authors := make(map[int]string)
+	for _, version := range versions {
+		authors[version.Version] = version.Author
+	}
+
+	return authors
+}
+
+func TestSetBaseEnvironmentGroupAuthor(t *testing.T) {
+	var objects []runtime.Object
+	objects = append(objects, historyTestVersion("shared", 1, "first@example.com")...)
+	objects = append(objects, historyTestVersion("shared", 2, "")...)
+	agent := kubernetes.GetAgentTesting(objects...)
+
+	err := SetBaseEnvironmentGroupAuthor(context.Background(), agent, SetBaseEnvironmentGroupAuthorInput{
+		Name:            "shared",
+		PreviousVersion: 1,
+		Author:          "second@example.com",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]string{1: "first@example.com", 2: "second@example.com"}, historyTestAuthors(t, agent, "shared"))
+
+	secret, err := agent.Clientset.CoreV1().Secrets(Namespace_EnvironmentGroups).Get(context.Background(), "shared.2", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "second@example.com", secret.Annotations[AnnotationKey_EnvironmentGroupAuthor], "the secret of the version should be annotated as well")
+
+	err = SetBaseEnvironmentGroupAuthor(context.Background(), agent, SetBaseEnvironmentGroupAuthorInput{
+		Name:            "shared",
+		PreviousVersion: 1,
+		Author:          "someone-else@example.com",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "second@example.com", historyTestAuthors(t, agent, "shared")[2], "a version which already has an author should be left unchanged")
+}
+
+func TestSetBaseEnvironmentGroupAuthor_concurrentVersions(t *testing.T) {
+	var objects []runtime.Object
+	objects = append(objects, historyTestVersion("shared", 1, "")...)
+	objects = append(objects, historyTestVersion("shared", 2, "")...)
+	objects = append(objects, historyTestVersion("shared", 3, "")...)
+	agent := kubernetes.GetAgentTesting(objects...)
+
+	// versions 2 and 3 were both created after version 1, so neither can be attributed to the author
+	err := SetBaseEnvironmentGroupAuthor(context.Background(), agent, SetBaseEnvironmentGroupAuthorInput{
+		Name:            "shared",
+		PreviousVersion: 1,
+		Author:          "first@example.com",
+	})
+	assert.True(t, errors.Is(err, ErrAmbiguousEnvironmentGroupVersion), "expected an ambiguous version error, got %v", err)
+	assert.Equal(t, map[int]string{1: "", 2: "", 3: ""}, historyTestAuthors(t, agent, "shared"))
+
+	err = SetBaseEnvironmentGroupAuthor(context.Background(), agent, SetBaseEnvironmentGroupAuthorInput{
+		Name:            "shared",
+		PreviousVersion: 3,
+		Author:          "first@example.com",
+	})
+	assert.Error(t, err, "no version was created after the latest version")
+}
@@ -34,6 +34,10 @@ const (
 
 	// LabelKey_AppName is the label key for the app name
 	LabelKey_AppName = "porter.run/app-name"
+
+	// AnnotationKey_EnvironmentGroupAuthor is the annotation key for the email of the user who created a version of an environment group.
+	// Annotations are used since emails are not valid label values.
+	AnnotationKey_EnvironmentGroupAuthor = "porter.run/environment-group-author"
 )
 
 // EnvGroupFile is a struct that contains information about a file associated with the env group
@@ -62,6 +66,8 @@ type EnvironmentGroup struct {
 	CreatedAtUTC time.Time `json:"created_at,omitempty"`
 	// DefaultAppEnvironment is a boolean value that determines whether or not this environment group is the default environment group for an app
 	DefaultAppEnvironment bool `json:"default_app_environment"`
+	// Author is the email of the user who created this version, if it is known
+	Author string `json:"author,omitempty"`
 }
 
 type environmentGroupOptions struct {
@@ -192,6 +198,7 @@ func listEnvironmentGroups(ctx context.Context, a *kubernetes.Agent, listOpts ..
 			SecretVariables:       envGroupSet[cm.Name].SecretVariables,
 			CreatedAtUTC:          cm.CreationTimestamp.Time.UTC(),
 			DefaultAppEnvironment: cm.Labels[LabelKey_DefaultAppEnvironment] == "true",
+			Author:                cm.Annotations[AnnotationKey_EnvironmentGroupAuthor],
 		}
 	}
 
@@ -252,6 +259,7 @@ func listEnvironmentGroups(ctx context.Context, a *kubernetes.Agent, listOpts ..
 				Files:                 files,
 				CreatedAtUTC:          secret.CreationTimestamp.Time.UTC(),
 				DefaultAppEnvironment: secret.Labels[LabelKey_DefaultAppEnvironment] == "true",
+				Author:                envGroupSet[versionedName].Author,
 			}
 		} else {
 			envGroupSet[versionedName] = EnvironmentGroup{
@@ -263,6 +271,7 @@ func listEnvironmentGroups(ctx context.Context, a *kubernetes.Agent, listOpts ..
 				Files:                 envGroupSet[versionedName].Files,
 				CreatedAtUTC:          secret.CreationTimestamp.Time.UTC(),
 				DefaultAppEnvironment: secret.Labels[LabelKey_DefaultAppEnvironment] == "true",
+				Author:                envGroupSet[versionedName].Author,
 			}
 		}
 	}
@@ -0,0 +1,266 @@
+package environment_groups
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/secretref"
+	"github.com/porter-dev/porter/internal/telemetry"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// AnnotationKey_SecretReferenceStatus is the annotation on a synced secret holding the resolution status of each secret reference, as JSON
+	AnnotationKey_SecretReferenceStatus = "porter.run/secret-reference-status"
+	// AnnotationKey_SecretReferenceNextRefresh is the annotation on a synced secret holding the time its secret references should be resolved again
+	AnnotationKey_SecretReferenceNextRefresh = "porter.run/secret-reference-next-refresh"
+)
+
+// SecretReferenceNamespaceStatus is the resolution status of the secret references of an environment group synced to a namespace
+type SecretReferenceNamespaceStatus struct {
+	Namespace string `json:"namespace"`
+	Version   int    `json:"version"`
+	// NextRefreshAt is when the references will be resolved again
+	NextRefreshAt *time.Time `json:"next_refresh_at,omitempty"`
+	// Keys are the status of each reference
+	Keys []secretref.KeyStatus `json:"keys"`
+}
+
+// resolveSecretReferencesForSync resolves the references of an environment group which is being synced to a new namespace. Every reference must
+// resolve, since there is no earlier value to fall back on.
+func resolveSecretReferencesForSync(ctx context.Context, resolver *secretref.Resolver, refs map[string]secretref.Reference, eg EnvironmentGroup) (EnvironmentGroup, secretref.ResolveOutput, error) {
+	out := resolver.Resolve(ctx, secretref.ResolveInput{References: refs})
+
+	if failed := out.Failed(); len(failed) > 0 {
+		var messages []string
+		for _, status := range failed {
+			messages = append(messages, fmt.Sprintf("%s: %s", status.Key, status.Error))
+		}
+		return eg, out, fmt.Errorf("unable to resolve secret references: %s", strings.Join(messages, "; "))
+	}
+
+	resolved := eg
+	resolved.SecretVariables = make(map[string]string)
+	for k, v := range eg.SecretVariables {
+		resolved.SecretVariables[k] = v
+	}
+	for k, v := range out.Values {
+		resolved.SecretVariables[k] = v
+	}
+
+	return resolved, out, nil
+}
+
+// secretReferenceRefreshDue returns true if the references of a synced secret should be resolved again
+func secretReferenceRefreshDue(secret v1.Secret, now time.Time) bool {
+	nextRefresh, err := time.Parse(time.RFC3339, secret.Annotations[AnnotationKey_SecretReferenceNextRefresh])
+	if err != nil {
+		return true
+	}
+	return !now.Before(nextRefresh)
+}
+
+// refreshSecretReferences resolves the references of a synced secret again and updates its values in place. References which fail to resolve
+// keep their current value. Pods read the new values when they next start.
+func refreshSecretReferences(ctx context.Context, a *kubernetes.Agent, resolver *secretref.Resolver, secret v1.Secret, refs map[string]secretref.Reference) error {
+	previousValues := make(map[string]string)
+	for key := range refs {
+		if value, ok := secret.Data[key]; ok {
+			previousValues[key] = string(value)
+		}
+	}
+
+	var previousStatuses []secretref.KeyStatus
+	if raw := secret.Annotations[AnnotationKey_SecretReferenceStatus]; raw != "" {
+		// a malformed status only loses the time each key was last resolved
+		_ = json.Unmarshal([]byte(raw), &previousStatuses)
+	}
+
+	out := resolver.Resolve(ctx, secretref.ResolveInput{
+		References:       refs,
+		PreviousValues:   previousValues,
+		PreviousStatuses: previousStatuses,
+	})
+
+	return patchSecretReferences(ctx, a, secret.Namespace, secret.Name, out, true)
+}
+
+// patchSecretReferences records the resolution status on a synced secret, and writes the resolved values if writeValues is set
+func patchSecretReferences(ctx context.Context, a *kubernetes.Agent, namespace string, name string, out secretref.ResolveOutput, writeValues bool) error {
+	status, err := json.Marshal(out.Statuses)
+	if err != nil {
+		return fmt.Errorf("unable to marshal secret reference status: %w", err)
+	}
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				AnnotationKey_SecretReferenceStatus:      string(status),
+				AnnotationKey_SecretReferenceNextRefresh: out.NextRefresh.Format(time.RFC3339),
+			},
+		},
+	}
+
+	if writeValues {
+		data := make(map[string]string)
+		for k, v := range out.Values {
+			data[k] = base64.StdEncoding.EncodeToString([]byte(v))
+		}
+		patch["data"] = data
+	}
+
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("unable to marshal secret reference patch: %w", err)
+	}
+
+	_, err = a.Clientset.CoreV1().Secrets(namespace).Patch(ctx, name, k8stypes.MergePatchType, patchBytes, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to patch synced secret: %w", err)
+	}
+
+	return nil
+}
+
+// syncedSecrets returns the secrets of an environment group which have been synced to app namespaces with resolved secret references. If environmentGroupName
+// is empty, the secrets of every environment group are returned.
+func syncedSecrets(ctx context.Context, a *kubernetes.Agent, environmentGroupName string) ([]v1.Secret, error) {
+	selector := fmt.Sprintf("%s=true,%s", LabelKey_PorterManaged, LabelKey_EnvironmentGroupName)
+	if environmentGroupName != "" {
+		selector = fmt.Sprintf("%s=true,%s=%s", LabelKey_PorterManaged, LabelKey_EnvironmentGroupName, environmentGroupName)
+	}
+
+	secrets, err := a.Clientset.CoreV1().Secrets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list environment group secrets: %w", err)
+	}
+
+	var synced []v1.Secret
+	for _, secret := range secrets.Items {
+		if secret.Namespace == Namespace_EnvironmentGroups {
+			continue
+		}
+		if _, ok := secret.Annotations[AnnotationKey_SecretReferenceNextRefresh]; !ok {
+			continue
+		}
+		synced = append(synced, secret)
+	}
+
+	return synced, nil
+}
+
+// RefreshDueSecretReferences resolves the secret references of every synced environment group in a cluster whose refresh interval has passed,
+// and returns the number of secrets which were refreshed. A failure to refresh one secret does not stop the others from being refreshed.
+func RefreshDueSecretReferences(ctx context.Context, a *kubernetes.Agent, resolver *secretref.Resolver) (int, error) {
+	ctx, span := telemetry.NewSpan(ctx, "refresh-due-secret-references")
+	defer span.End()
+
+	secrets, err := syncedSecrets(ctx, a, "")
+	if err != nil {
+		return 0, telemetry.Error(ctx, span, err, "unable to list synced secrets")
+	}
+
+	now := time.Now().UTC()
+	refreshed := 0
+
+	var errs []error
+	for _, secret := range secrets {
+		if !secretReferenceRefreshDue(secret, now) {
+			continue
+		}
+
+		name := secret.Labels[LabelKey_EnvironmentGroupName]
+		version, err := strconv.Atoi(secret.Labels[LabelKey_EnvironmentGroupVersion])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s/%s: invalid environment group version label", secret.Namespace, secret.Name))
+			continue
+		}
+
+		// the synced secret holds the resolved values, so the references are read from the base version
+		base, err := baseEnvironmentGroupVersion(ctx, a, name, version)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s/%s: %w", secret.Namespace, secret.Name, err))
+			continue
+		}
+
+		refs, err := secretref.ParseReferences(base.SecretVariables)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s/%s: %w", secret.Namespace, secret.Name, err))
+			continue
+		}
+
+		if err := refreshSecretReferences(ctx, a, resolver, secret, refs); err != nil {
+			errs = append(errs, fmt.Errorf("%s/%s: %w", secret.Namespace, secret.Name, err))
+			continue
+		}
+		refreshed++
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "synced-secret-count", Value: len(secrets)},
+		telemetry.AttributeKV{Key: "refreshed-count", Value: refreshed},
+		telemetry.AttributeKV{Key: "error-count", Value: len(errs)},
+	)
+
+	if len(errs) > 0 {
+		return refreshed, telemetry.Error(ctx, span, errors.Join(errs...), "unable to refresh some secret references")
+	}
+
+	return refreshed, nil
+}
+
+// SecretReferenceStatuses returns the resolution status of the secret references of an environment group in each namespace it is synced to
+func SecretReferenceStatuses(ctx context.Context, a *kubernetes.Agent, environmentGroupName string) ([]SecretReferenceNamespaceStatus, error) {
+	ctx, span := telemetry.NewSpan(ctx, "secret-reference-statuses")
+	defer span.End()
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "environment-group-name", Value: environmentGroupName})
+
+	if environmentGroupName == "" {
+		return nil, telemetry.Error(ctx, span, nil, "environment group name cannot be empty")
+	}
+
+	secrets, err := syncedSecrets(ctx, a, environmentGroupName)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "unable to list synced secrets")
+	}
+
+	var statuses []SecretReferenceNamespaceStatus
+	for _, secret := range secrets {
+		status := SecretReferenceNamespaceStatus{
+			Namespace: secret.Namespace,
+		}
+
+		status.Version, _ = strconv.Atoi(secret.Labels[LabelKey_EnvironmentGroupVersion])
+
+		if nextRefresh, err := time.Parse(time.RFC3339, secret.Annotations[AnnotationKey_SecretReferenceNextRefresh]); err == nil {
+			status.NextRefreshAt = &nextRefresh
+		}
+
+		if raw := secret.Annotations[AnnotationKey_SecretReferenceStatus]; raw != "" {
+			if err := json.Unmarshal([]byte(raw), &status.Keys); err != nil {
+				return nil, telemetry.Error(ctx, span, err, "unable to parse secret reference status")
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Namespace != statuses[j].Namespace {
+			return statuses[i].Namespace < statuses[j].Namespace
+		}
+		return statuses[i].Version > statuses[j].Version
+	})
+
+	return statuses, nil
+}
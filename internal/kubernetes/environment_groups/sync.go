@@ -3,9 +3,12 @@ package environment_groups
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/secretref"
 	"github.com/porter-dev/porter/internal/telemetry"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // SyncLatestVersionToNamespaceInput contains all information required to sync an environment group from the porter-env-group namespace
@@ -13,6 +16,9 @@ import (
 type SyncLatestVersionToNamespaceInput struct {
 	BaseEnvironmentGroupName string
 	TargetNamespace          string
+	// SecretReferenceResolver resolves secret variables which reference an external secret manager, such as ref+vault://kv/data/projects/1/payments#DB_PASSWORD.
+	// If nil, references are copied to the target namespace as-is.
+	SecretReferenceResolver *secretref.Resolver
 }
 
 // SyncLatestVersionToNamespaceOutput returns the literal configmap name (as opposed to the environment group name) which can be used in kubernetes manifests
@@ -59,17 +65,57 @@ func SyncLatestVersionToNamespace(ctx context.Context, a *kubernetes.Agent, inp
 		return output, telemetry.Error(ctx, span, err, "unable to get environement group in target namespace")
 	}
 
+	var refs map[string]secretref.Reference
+	if inp.SecretReferenceResolver != nil {
+		refs, err = secretref.ParseReferences(baseEnvironmentGroup.SecretVariables)
+		if err != nil {
+			return output, telemetry.Error(ctx, span, err, "unable to parse secret references")
+		}
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "secret-reference-count", Value: len(refs)})
+
+	versionedName := fmt.Sprintf("%s.%d", baseEnvironmentGroup.Name, baseEnvironmentGroup.Version)
+
 	if targetEnvironmentGroup.Name == baseEnvironmentGroup.Name && targetEnvironmentGroup.Version == baseEnvironmentGroup.Version {
+		if len(refs) > 0 {
+			secret, err := a.Clientset.CoreV1().Secrets(inp.TargetNamespace).Get(ctx, versionedName, metav1.GetOptions{})
+			if err != nil {
+				return output, telemetry.Error(ctx, span, err, "unable to get synced environment group secret")
+			}
+
+			if secretReferenceRefreshDue(*secret, time.Now().UTC()) {
+				err = refreshSecretReferences(ctx, a, inp.SecretReferenceResolver, *secret, refs)
+				if err != nil {
+					return output, telemetry.Error(ctx, span, err, "unable to refresh secret references")
+				}
+			}
+		}
+
 		return SyncLatestVersionToNamespaceOutput{
-			EnvironmentGroupVersionedName: fmt.Sprintf("%s.%d", baseEnvironmentGroup.Name, baseEnvironmentGroup.Version),
+			EnvironmentGroupVersionedName: versionedName,
 		}, nil
 	}
 
+	var resolved secretref.ResolveOutput
+	if len(refs) > 0 {
+		baseEnvironmentGroup, resolved, err = resolveSecretReferencesForSync(ctx, inp.SecretReferenceResolver, refs, baseEnvironmentGroup)
+		if err != nil {
+			return output, telemetry.Error(ctx, span, err, "unable to resolve secret references")
+		}
+	}
+
 	targetConfigmapName, err := createEnvironmentGroupInTargetNamespace(ctx, a, inp.TargetNamespace, baseEnvironmentGroup, additionalLabels)
 	if err != nil {
 		return output, telemetry.Error(ctx, span, err, "unable to create environment group in target namespace")
 	}
 
+	if len(refs) > 0 {
+		err = patchSecretReferences(ctx, a, inp.TargetNamespace, targetConfigmapName, resolved, false)
+		if err != nil {
+			return output, telemetry.Error(ctx, span, err, "unable to record secret reference status")
+		}
+	}
+
 	output = SyncLatestVersionToNamespaceOutput{
 		EnvironmentGroupVersionedName: targetConfigmapName,
 	}
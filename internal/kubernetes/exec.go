@@ -0,0 +1,202 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/shared/websocket"
+	"github.com/porter-dev/porter/api/types"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+	"k8s.io/kubectl/pkg/scheme"
+)
+
+// ExecPodOptions are the options for running a command in an existing pod
+type ExecPodOptions struct {
+	Namespace string
+	PodName   string
+	Container string
+	Command   []string
+	TTY       bool
+}
+
+// ExecPod runs a command in a running container, proxying stdin, stdout, stderr and terminal resizes over the
+// websocket using the framing described by types.ExecStream. The command is stopped if the websocket is closed.
+// The exit code of the command is sent to the client as the final message and returned.
+func (a *Agent) ExecPod(ctx context.Context, opts ExecPodOptions, rw *websocket.WebsocketSafeReadWriter) (int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	exitCode, err := a.execPod(ctx, cancel, opts, rw)
+
+	exitMessage := types.ExecExitMessage{
+		ExitCode: exitCode,
+	}
+
+	if err != nil {
+		exitMessage.Error = err.Error()
+	}
+
+	if payload, marshalErr := json.Marshal(exitMessage); marshalErr == nil {
+		rw.WriteBinary(append([]byte{byte(types.ExecStreamExit)}, payload...)) // nolint:errcheck,gosec
+	}
+
+	rw.Close() // nolint:errcheck,gosec
+
+	return exitCode, err
+}
+
+func (a *Agent) execPod(ctx context.Context, cancel context.CancelFunc, opts ExecPodOptions, rw *websocket.WebsocketSafeReadWriter) (int, error) {
+	executor, err := a.execExecutor(opts)
+	if err != nil {
+		return -1, err
+	}
+
+	stdinReader, stdinWriter := io.Pipe()
+	sizeQueue := &execSizeQueue{
+		ctx:   ctx,
+		sizes: make(chan remotecommand.TerminalSize, 1),
+	}
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				// TODO: add method to alert on panic
+				return
+			}
+		}()
+
+		defer stdinWriter.Close() // nolint:errcheck
+
+		for {
+			_, data, err := rw.ReadMessage()
+			if err != nil {
+				// the client has gone away, so stop the command
+				cancel()
+				return
+			}
+
+			if len(data) == 0 {
+				continue
+			}
+
+			switch types.ExecStream(data[0]) {
+			case types.ExecStreamStdin:
+				if _, err := stdinWriter.Write(data[1:]); err != nil {
+					return
+				}
+			case types.ExecStreamStdinClose:
+				stdinWriter.Close() // nolint:errcheck,gosec
+			case types.ExecStreamResize:
+				size := types.ExecResizeMessage{}
+				if err := json.Unmarshal(data[1:], &size); err == nil {
+					sizeQueue.push(remotecommand.TerminalSize{Width: size.Width, Height: size.Height})
+				}
+			}
+		}
+	}()
+
+	streamOpts := remotecommand.StreamOptions{
+		Stdin:  stdinReader,
+		Stdout: &execStreamWriter{rw: rw, stream: types.ExecStreamStdout},
+		Tty:    opts.TTY,
+	}
+
+	if opts.TTY {
+		streamOpts.TerminalSizeQueue = sizeQueue
+	} else {
+		streamOpts.Stderr = &execStreamWriter{rw: rw, stream: types.ExecStreamStderr}
+	}
+
+	err = executor.StreamWithContext(ctx, streamOpts)
+	if err != nil {
+		var exitErr exec.CodeExitError
+		if goerrors.As(err, &exitErr) {
+			return exitErr.ExitStatus(), nil
+		}
+
+		return -1, fmt.Errorf("error running command in pod %s: %w", opts.PodName, err)
+	}
+
+	return 0, nil
+}
+
+// execExecutor returns the executor that runs the command in the pod. Test agents can replace it, since the fake
+// clientset cannot open a stream to a pod.
+func (a *Agent) execExecutor(opts ExecPodOptions) (remotecommand.Executor, error) {
+	if a.newExecExecutor != nil {
+		return a.newExecExecutor(opts)
+	}
+
+	restConf, err := a.RESTClientGetter.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	req := a.Clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(opts.Namespace).
+		Name(opts.PodName).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: opts.Container,
+			Command:   opts.Command,
+			Stdin:     true,
+			Stdout:    true,
+			// a tty merges stderr into stdout
+			Stderr: !opts.TTY,
+			TTY:    opts.TTY,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(restConf, http.MethodPost, req.URL())
+	if err != nil {
+		return nil, fmt.Errorf("error creating executor for pod %s: %w", opts.PodName, err)
+	}
+
+	return executor, nil
+}
+
+// execStreamWriter writes everything it receives to the websocket as messages on a single stream
+type execStreamWriter struct {
+	rw     *websocket.WebsocketSafeReadWriter
+	stream types.ExecStream
+}
+
+func (w *execStreamWriter) Write(p []byte) (int, error) {
+	if err := w.rw.WriteBinary(append([]byte{byte(w.stream)}, p...)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// execSizeQueue implements remotecommand.TerminalSizeQueue for resize messages received from the client.
+// Only the most recent size is kept, since intermediate sizes are stale by the time they are applied.
+type execSizeQueue struct {
+	ctx   context.Context
+	sizes chan remotecommand.TerminalSize
+}
+
+func (q *execSizeQueue) push(size remotecommand.TerminalSize) {
+	select {
+	case <-q.sizes:
+	default:
+	}
+
+	q.sizes <- size
+}
+
+// Next returns the next terminal size, or nil once the command has finished
+func (q *execSizeQueue) Next() *remotecommand.TerminalSize {
+	select {
+	case size := <-q.sizes:
+		return &size
+	case <-q.ctx.Done():
+		return nil
+	}
+}
@@ -0,0 +1,320 @@
+package kubernetes_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gorillaws "github.com/gorilla/websocket"
+	"github.com/porter-dev/porter/api/server/shared/websocket"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+)
+
+// fakeExecutor runs stream in place of a command in a pod
+type fakeExecutor struct {
+	stream func(ctx context.Context, opts remotecommand.StreamOptions) error
+}
+
+func (e *fakeExecutor) Stream(opts remotecommand.StreamOptions) error {
+	return e.stream(context.Background(), opts)
+}
+
+func (e *fakeExecutor) StreamWithContext(ctx context.Context, opts remotecommand.StreamOptions) error {
+	return e.stream(ctx, opts)
+}
+
+type execResult struct {
+	exitCode int
+	err      error
+}
+
+// execOutput is everything the client received on an exec websocket
+type execOutput struct {
+	stdout string
+	stderr string
+	exit   types.ExecExitMessage
+}
+
+// startExecSession runs ExecPod with executor behind a websocket, and returns the client end of the websocket and
+// a channel that receives the result of ExecPod
+func startExecSession(t *testing.T, executor *fakeExecutor, opts kubernetes.ExecPodOptions) (*gorillaws.Conn, <-chan execResult) {
+	t.Helper()
+
+	agent := kubernetes.GetExecAgentTesting(func(kubernetes.ExecPodOptions) (remotecommand.Executor, error) {
+		return executor, nil
+	})
+	results := make(chan execResult, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := &websocket.Upgrader{
+			WSUpgrader: &gorillaws.Upgrader{
+				CheckOrigin: func(r *http.Request) bool { return true },
+			},
+		}
+
+		_, _, rw, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("error upgrading connection: %v", err)
+			return
+		}
+
+		exitCode, err := agent.ExecPod(context.Background(), opts, rw)
+		results <- execResult{exitCode: exitCode, err: err}
+	}))
+	t.Cleanup(server.Close)
+
+	conn, _, err := gorillaws.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("error dialing websocket: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() }) // nolint:errcheck
+
+	return conn, results
+}
+
+func sendExecMessage(t *testing.T, conn *gorillaws.Conn, stream types.ExecStream, payload []byte) {
+	t.Helper()
+
+	if err := conn.WriteMessage(gorillaws.BinaryMessage, append([]byte{byte(stream)}, payload...)); err != nil {
+		t.Fatalf("error writing message: %v", err)
+	}
+}
+
+// readExecOutput reads messages from the websocket until the exit message is received
+func readExecOutput(t *testing.T, conn *gorillaws.Conn) execOutput {
+	t.Helper()
+
+	var output execOutput
+
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second)) // nolint:errcheck,gosec
+
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("error reading message before exit: %v", err)
+		}
+
+		if messageType != gorillaws.BinaryMessage || len(data) == 0 {
+			t.Fatalf("expected a binary message with a stream byte, got type %d: %q", messageType, data)
+		}
+
+		switch types.ExecStream(data[0]) {
+		case types.ExecStreamStdout:
+			output.stdout += string(data[1:])
+		case types.ExecStreamStderr:
+			output.stderr += string(data[1:])
+		case types.ExecStreamExit:
+			if err := json.Unmarshal(data[1:], &output.exit); err != nil {
+				t.Fatalf("error decoding exit message: %v", err)
+			}
+
+			return output
+		default:
+			t.Fatalf("unexpected stream %d from server", data[0])
+		}
+	}
+}
+
+func waitForExecResult(t *testing.T, results <-chan execResult) execResult {
+	t.Helper()
+
+	select {
+	case result := <-results:
+		return result
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for exec session to finish")
+	}
+
+	return execResult{}
+}
+
+func TestExecPodStreams(t *testing.T) {
+	executor := &fakeExecutor{
+		stream: func(ctx context.Context, opts remotecommand.StreamOptions) error {
+			if opts.Tty || opts.TerminalSizeQueue != nil || opts.Stderr == nil {
+				return errors.New("expected separate stdout and stderr streams without a tty")
+			}
+
+			stdin, err := io.ReadAll(opts.Stdin)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(opts.Stdout, "out:%s", stdin) // nolint:errcheck
+			fmt.Fprint(opts.Stderr, "warning")        // nolint:errcheck
+
+			return nil
+		},
+	}
+
+	conn, results := startExecSession(t, executor, kubernetes.ExecPodOptions{
+		Namespace: "default",
+		PodName:   "web-0",
+		Container: "web",
+		Command:   []string{"cat"},
+	})
+
+	sendExecMessage(t, conn, types.ExecStreamStdin, []byte("hello "))
+	sendExecMessage(t, conn, types.ExecStreamStdin, []byte("world"))
+	sendExecMessage(t, conn, types.ExecStreamStdinClose, nil)
+
+	output := readExecOutput(t, conn)
+
+	if output.stdout != "out:hello world" {
+		t.Errorf("expected stdin to be echoed on stdout, got %q", output.stdout)
+	}
+
+	if output.stderr != "warning" {
+		t.Errorf("expected stderr to be sent on its own stream, got %q", output.stderr)
+	}
+
+	if output.exit != (types.ExecExitMessage{ExitCode: 0}) {
+		t.Errorf("expected a successful exit message, got %+v", output.exit)
+	}
+
+	result := waitForExecResult(t, results)
+	if result.exitCode != 0 || result.err != nil {
+		t.Errorf("expected exit code 0 and no error, got %d and %v", result.exitCode, result.err)
+	}
+}
+
+func TestExecPodResize(t *testing.T) {
+	executor := &fakeExecutor{
+		stream: func(ctx context.Context, opts remotecommand.StreamOptions) error {
+			if !opts.Tty || opts.TerminalSizeQueue == nil || opts.Stderr != nil {
+				return errors.New("expected a terminal size queue and no stderr stream with a tty")
+			}
+
+			size := opts.TerminalSizeQueue.Next()
+			if size == nil {
+				return errors.New("expected a terminal size")
+			}
+
+			fmt.Fprintf(opts.Stdout, "%dx%d", size.Width, size.Height) // nolint:errcheck
+
+			return nil
+		},
+	}
+
+	conn, results := startExecSession(t, executor, kubernetes.ExecPodOptions{
+		Namespace: "default",
+		PodName:   "web-0",
+		Container: "web",
+		Command:   []string{"sh"},
+		TTY:       true,
+	})
+
+	resize, err := json.Marshal(types.ExecResizeMessage{Width: 120, Height: 40})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sendExecMessage(t, conn, types.ExecStreamResize, resize)
+
+	output := readExecOutput(t, conn)
+
+	if output.stdout != "120x40" {
+		t.Errorf("expected the terminal to be resized, got %q", output.stdout)
+	}
+
+	if output.exit != (types.ExecExitMessage{ExitCode: 0}) {
+		t.Errorf("expected a successful exit message, got %+v", output.exit)
+	}
+
+	waitForExecResult(t, results)
+}
+
+func TestExecPodExitCode(t *testing.T) {
+	tests := map[string]struct {
+		err         error
+		expExitCode int
+		expError    string
+	}{
+		"command exits with non-zero code": {
+			err:         exec.CodeExitError{Err: errors.New("command terminated with exit code 3"), Code: 3},
+			expExitCode: 3,
+		},
+		"command cannot be run": {
+			err:         errors.New("container web is not running"),
+			expExitCode: -1,
+			expError:    "error running command in pod web-0: container web is not running",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			executor := &fakeExecutor{
+				stream: func(ctx context.Context, opts remotecommand.StreamOptions) error {
+					return tc.err
+				},
+			}
+
+			conn, results := startExecSession(t, executor, kubernetes.ExecPodOptions{
+				Namespace: "default",
+				PodName:   "web-0",
+				Container: "web",
+				Command:   []string{"false"},
+			})
+
+			output := readExecOutput(t, conn)
+
+			if output.exit != (types.ExecExitMessage{ExitCode: tc.expExitCode, Error: tc.expError}) {
+				t.Errorf("expected exit code %d and error %q, got %+v", tc.expExitCode, tc.expError, output.exit)
+			}
+
+			result := waitForExecResult(t, results)
+			if result.exitCode != tc.expExitCode {
+				t.Errorf("expected exit code %d to be returned, got %d", tc.expExitCode, result.exitCode)
+			}
+
+			if (result.err != nil) != (tc.expError != "") {
+				t.Errorf("expected error %q to be returned, got %v", tc.expError, result.err)
+			}
+		})
+	}
+}
+
+func TestExecPodClientDisconnect(t *testing.T) {
+	started := make(chan struct{})
+
+	executor := &fakeExecutor{
+		stream: func(ctx context.Context, opts remotecommand.StreamOptions) error {
+			close(started)
+
+			// a long-running command only stops once the session is canceled
+			<-ctx.Done()
+
+			return ctx.Err()
+		},
+	}
+
+	conn, results := startExecSession(t, executor, kubernetes.ExecPodOptions{
+		Namespace: "default",
+		PodName:   "web-0",
+		Container: "web",
+		Command:   []string{"sleep", "infinity"},
+	})
+
+	select {
+	case <-started:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for command to start")
+	}
+
+	conn.Close() // nolint:errcheck,gosec
+
+	result := waitForExecResult(t, results)
+	if result.exitCode != -1 || result.err == nil {
+		t.Errorf("expected the command to be stopped when the client disconnects, got %d and %v", result.exitCode, result.err)
+	}
+}
@@ -0,0 +1,516 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// LabelKey_WorkloadKind is the label set on the nodes of each porter node group, such as application or monitoring
+	LabelKey_WorkloadKind = "porter.run/workload-kind"
+	// WorkloadKind_Application is the workload kind of the nodes that porter apps are scheduled onto
+	WorkloadKind_Application = "application"
+
+	resourceNvidiaGPU corev1.ResourceName = "nvidia.com/gpu"
+)
+
+// CapacityRequest is a number of identical pods to schedule in a capacity simulation
+type CapacityRequest struct {
+	Name        string
+	Replicas    int
+	CPUMillis   int64
+	MemoryBytes int64
+	GPU         int64
+	// NodeSelector and Tolerations constrain the nodes the pods can be scheduled on. If neither is set and the cluster
+	// has application nodes, the pods are only scheduled onto application nodes, like porter apps.
+	NodeSelector map[string]string
+	Tolerations  []corev1.Toleration
+}
+
+// SimulateCapacityInput is the state of a cluster and the pods to schedule onto it
+type SimulateCapacityInput struct {
+	Nodes []corev1.Node
+	// Pods are the non-terminated pods of the cluster
+	Pods []corev1.Pod
+	// Replaced selects the pods which are removed before the simulated pods are scheduled, such as the current pods of an
+	// app that is being resized. If nil, no pods are removed.
+	Replaced labels.Selector
+	Requests []CapacityRequest
+}
+
+// SimulateClusterCapacity simulates scheduling the requested pods onto the current nodes of a cluster
+func SimulateClusterCapacity(ctx context.Context, clientset kubernetes.Interface, replaced labels.Selector, requests []CapacityRequest) (types.SimulateClusterCapacityResponse, error) {
+	nodeList, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return types.SimulateClusterCapacityResponse{}, fmt.Errorf("error listing nodes: %w", err)
+	}
+
+	podList, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+	})
+	if err != nil {
+		return types.SimulateClusterCapacityResponse{}, fmt.Errorf("error listing pods: %w", err)
+	}
+
+	return SimulateCapacity(SimulateCapacityInput{
+		Nodes:    nodeList.Items,
+		Pods:     podList.Items,
+		Replaced: replaced,
+		Requests: requests,
+	}), nil
+}
+
+// simNode is the allocatable and requested resources of a node during a simulation
+type simNode struct {
+	name         string
+	instanceType string
+	labels       map[string]string
+	taints       []corev1.Taint
+	schedulable  bool
+	extra        bool
+
+	allocCPU, allocMemory, allocGPU, allocPods int64
+	reqCPU, reqMemory, reqGPU, pods            int64
+
+	// daemonSetCPU, daemonSetMemory, daemonSetGPU and daemonSetPods are the requests of the daemon set pods on the
+	// node, which every extra node of the same instance type also runs
+	daemonSetCPU, daemonSetMemory, daemonSetGPU, daemonSetPods int64
+
+	simulatedPods int
+}
+
+// simPod is a single pod of a capacity request
+type simPod struct {
+	request *CapacityRequest
+	group   int
+}
+
+// SimulateCapacity schedules the requested pods onto the schedulable nodes of a cluster, and determines how many nodes
+// of each instance type must be added to fit the pods which do not fit.
+//
+// Pods are placed in decreasing order of their requests. On the current nodes, each pod is placed onto the least
+// allocated node that fits it, like the default kubernetes scheduler. Pods which do not fit are bin-packed onto extra
+// nodes, which are copies of an existing node of a compatible instance type including its daemon set pods, like the
+// cluster autoscaler. Node selectors, NoSchedule and NoExecute taints, and the pod limit of each node are accounted
+// for, but node affinity and pod (anti-)affinity are not.
+func SimulateCapacity(inp SimulateCapacityInput) types.SimulateClusterCapacityResponse {
+	nodes := make([]*simNode, 0, len(inp.Nodes))
+	nodesByName := make(map[string]*simNode, len(inp.Nodes))
+	hasApplicationNodes := false
+
+	for _, node := range inp.Nodes {
+		allocatable := node.Status.Capacity
+		if len(node.Status.Allocatable) > 0 {
+			allocatable = node.Status.Allocatable
+		}
+
+		gpu := allocatable[resourceNvidiaGPU]
+
+		sn := &simNode{
+			name:         node.Name,
+			instanceType: nodeInstanceType(node),
+			labels:       node.Labels,
+			taints:       node.Spec.Taints,
+			schedulable:  !node.Spec.Unschedulable && nodeReady(node),
+			allocCPU:     allocatable.Cpu().MilliValue(),
+			allocMemory:  allocatable.Memory().Value(),
+			allocGPU:     gpu.Value(),
+			allocPods:    allocatable.Pods().Value(),
+		}
+
+		nodes = append(nodes, sn)
+		nodesByName[node.Name] = sn
+
+		if node.Labels[LabelKey_WorkloadKind] == WorkloadKind_Application {
+			hasApplicationNodes = true
+		}
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].name < nodes[j].name })
+
+	var currentCPU, currentMemory int64
+
+	for i := range inp.Pods {
+		pod := &inp.Pods[i]
+
+		node, ok := nodesByName[pod.Spec.NodeName]
+		if !ok {
+			continue
+		}
+
+		reqs, _ := podRequestsAndLimits(pod)
+		cpu, memory, gpu := reqs.Cpu().MilliValue(), reqs.Memory().Value(), reqs[resourceNvidiaGPU]
+
+		if node.schedulable {
+			currentCPU += cpu
+			currentMemory += memory
+		}
+
+		if isDaemonSetPod(pod) {
+			node.daemonSetCPU += cpu
+			node.daemonSetMemory += memory
+			node.daemonSetGPU += gpu.Value()
+			node.daemonSetPods++
+		}
+
+		if inp.Replaced != nil && inp.Replaced.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+
+		node.reqCPU += cpu
+		node.reqMemory += memory
+		node.reqGPU += gpu.Value()
+		node.pods++
+	}
+
+	requests := make([]CapacityRequest, len(inp.Requests))
+	copy(requests, inp.Requests)
+
+	var pods []simPod
+	for i := range requests {
+		if len(requests[i].NodeSelector) == 0 && len(requests[i].Tolerations) == 0 && hasApplicationNodes {
+			requests[i].NodeSelector = map[string]string{LabelKey_WorkloadKind: WorkloadKind_Application}
+		}
+
+		for r := 0; r < requests[i].Replicas; r++ {
+			pods = append(pods, simPod{request: &requests[i], group: i})
+		}
+	}
+
+	sort.SliceStable(pods, func(i, j int) bool {
+		if pods[i].request.CPUMillis != pods[j].request.CPUMillis {
+			return pods[i].request.CPUMillis > pods[j].request.CPUMillis
+		}
+		return pods[i].request.MemoryBytes > pods[j].request.MemoryBytes
+	})
+
+	unschedulable := make([]int, len(requests))
+	failures := make([]map[string]int, len(requests))
+	var pending []simPod
+
+	for _, pod := range pods {
+		node, reasons := leastAllocatedNode(nodes, pod.request)
+		if node == nil {
+			unschedulable[pod.group]++
+			if failures[pod.group] == nil {
+				failures[pod.group] = reasons
+			}
+			pending = append(pending, pod)
+			continue
+		}
+
+		node.place(pod.request)
+	}
+
+	extraNodes, unplaceable := placeOnExtraNodes(nodes, pending)
+
+	res := types.SimulateClusterCapacityResponse{
+		Fits:              len(pending) == 0,
+		UnschedulablePods: []types.CapacityUnschedulablePods{},
+		ExtraNodes:        []types.CapacityExtraNodes{},
+		Nodes:             []types.CapacityNode{},
+	}
+
+	for i, count := range unschedulable {
+		if count == 0 {
+			continue
+		}
+
+		res.UnschedulablePods = append(res.UnschedulablePods, types.CapacityUnschedulablePods{
+			Name:           requests[i].Name,
+			Count:          count,
+			Reason:         schedulingFailureMessage(nodes, failures[i]),
+			FitsExtraNodes: unplaceable[i] == 0,
+		})
+	}
+
+	extraCounts := make(map[string]int)
+	var allocatableCPU, allocatableMemory, requestedCPU, requestedMemory int64
+	var currentAllocatableCPU, currentAllocatableMemory int64
+
+	for _, node := range append(nodes, extraNodes...) {
+		if !node.schedulable {
+			continue
+		}
+
+		if node.extra {
+			extraCounts[node.instanceType]++
+		} else {
+			currentAllocatableCPU += node.allocCPU
+			currentAllocatableMemory += node.allocMemory
+		}
+
+		allocatableCPU += node.allocCPU
+		allocatableMemory += node.allocMemory
+		requestedCPU += node.reqCPU
+		requestedMemory += node.reqMemory
+
+		res.Nodes = append(res.Nodes, types.CapacityNode{
+			Name:                   node.name,
+			InstanceType:           node.instanceType,
+			Extra:                  node.extra,
+			CPUMillisAllocatable:   node.allocCPU,
+			CPUMillisRequested:     node.reqCPU,
+			MemoryBytesAllocatable: node.allocMemory,
+			MemoryBytesRequested:   node.reqMemory,
+			SimulatedPods:          node.simulatedPods,
+			Utilization:            utilization(node.reqCPU, node.allocCPU, node.reqMemory, node.allocMemory),
+		})
+	}
+
+	res.CurrentUtilization = utilization(currentCPU, currentAllocatableCPU, currentMemory, currentAllocatableMemory)
+	res.ProjectedUtilization = utilization(requestedCPU, allocatableCPU, requestedMemory, allocatableMemory)
+
+	for instanceType, count := range extraCounts {
+		res.ExtraNodes = append(res.ExtraNodes, types.CapacityExtraNodes{InstanceType: instanceType, Count: count})
+	}
+	sort.Slice(res.ExtraNodes, func(i, j int) bool { return res.ExtraNodes[i].InstanceType < res.ExtraNodes[j].InstanceType })
+
+	return res
+}
+
+// leastAllocatedNode returns the schedulable node which fits the pod and has the lowest allocation once the pod is
+// placed. If no node fits the pod, the number of nodes which failed for each reason is returned instead.
+func leastAllocatedNode(nodes []*simNode, req *CapacityRequest) (*simNode, map[string]int) {
+	var best *simNode
+	var bestScore float64
+	reasons := make(map[string]int)
+
+	for _, node := range nodes {
+		if !node.schedulable {
+			continue
+		}
+
+		if reason := node.fitFailure(req); reason != "" {
+			reasons[reason]++
+			continue
+		}
+
+		score := fraction(node.reqCPU+req.CPUMillis, node.allocCPU) + fraction(node.reqMemory+req.MemoryBytes, node.allocMemory)
+		if best == nil || score < bestScore {
+			best = node
+			bestScore = score
+		}
+	}
+
+	return best, reasons
+}
+
+// placeOnExtraNodes bin-packs the pods onto as few extra nodes as possible. Each pod is placed onto the first extra
+// node that fits it, or else onto a new extra node of the instance type with the most current nodes which fit it. The
+// number of pods of each group which do not fit onto an empty node of any instance type is also returned.
+func placeOnExtraNodes(nodes []*simNode, pods []simPod) ([]*simNode, map[int]int) {
+	templates := make(map[string]*simNode)
+	poolSizes := make(map[string]int)
+
+	for _, node := range nodes {
+		if node.instanceType == "" {
+			continue
+		}
+
+		poolSizes[node.instanceType]++
+		if _, ok := templates[node.instanceType]; !ok {
+			templates[node.instanceType] = node
+		}
+	}
+
+	instanceTypes := make([]string, 0, len(templates))
+	for instanceType := range templates {
+		instanceTypes = append(instanceTypes, instanceType)
+	}
+
+	sort.Slice(instanceTypes, func(i, j int) bool {
+		if poolSizes[instanceTypes[i]] != poolSizes[instanceTypes[j]] {
+			return poolSizes[instanceTypes[i]] > poolSizes[instanceTypes[j]]
+		}
+		return instanceTypes[i] < instanceTypes[j]
+	})
+
+	var extraNodes []*simNode
+	unplaceable := make(map[int]int)
+
+PODS:
+	for _, pod := range pods {
+		for _, node := range extraNodes {
+			if node.fitFailure(pod.request) == "" {
+				node.place(pod.request)
+				continue PODS
+			}
+		}
+
+		for _, instanceType := range instanceTypes {
+			node := templates[instanceType].extraNode(len(extraNodes) + 1)
+			if node.fitFailure(pod.request) == "" {
+				node.place(pod.request)
+				extraNodes = append(extraNodes, node)
+				continue PODS
+			}
+		}
+
+		unplaceable[pod.group]++
+	}
+
+	return extraNodes, unplaceable
+}
+
+// extraNode returns an empty schedulable copy of the node, running only the node's daemon set pods
+func (n *simNode) extraNode(index int) *simNode {
+	return &simNode{
+		name:         fmt.Sprintf("extra-%s-%d", n.instanceType, index),
+		instanceType: n.instanceType,
+		labels:       n.labels,
+		taints:       n.taints,
+		schedulable:  true,
+		extra:        true,
+		allocCPU:     n.allocCPU,
+		allocMemory:  n.allocMemory,
+		allocGPU:     n.allocGPU,
+		allocPods:    n.allocPods,
+		reqCPU:       n.daemonSetCPU,
+		reqMemory:    n.daemonSetMemory,
+		reqGPU:       n.daemonSetGPU,
+		pods:         n.daemonSetPods,
+	}
+}
+
+// fitFailure returns why a pod of the request cannot be placed onto the node, or an empty string if it fits
+func (n *simNode) fitFailure(req *CapacityRequest) string {
+	for key, value := range req.NodeSelector {
+		if nodeValue, ok := n.labels[key]; !ok || nodeValue != value {
+			return "node(s) didn't match node selector"
+		}
+	}
+
+	for i := range n.taints {
+		taint := &n.taints[i]
+		if taint.Effect != corev1.TaintEffectNoSchedule && taint.Effect != corev1.TaintEffectNoExecute {
+			continue
+		}
+
+		if !toleratesTaint(req.Tolerations, taint) {
+			return "node(s) had untolerated taint"
+		}
+	}
+
+	switch {
+	case n.allocPods > 0 && n.pods+1 > n.allocPods:
+		return "too many pods"
+	case n.reqCPU+req.CPUMillis > n.allocCPU:
+		return "insufficient cpu"
+	case n.reqMemory+req.MemoryBytes > n.allocMemory:
+		return "insufficient memory"
+	case n.reqGPU+req.GPU > n.allocGPU:
+		return fmt.Sprintf("insufficient %s", resourceNvidiaGPU)
+	}
+
+	return ""
+}
+
+// place adds the requests of a pod to the node
+func (n *simNode) place(req *CapacityRequest) {
+	n.reqCPU += req.CPUMillis
+	n.reqMemory += req.MemoryBytes
+	n.reqGPU += req.GPU
+	n.pods++
+	n.simulatedPods++
+}
+
+// schedulingFailureMessage summarizes why pods could not be scheduled, like the FailedScheduling events of the
+// kubernetes scheduler
+func schedulingFailureMessage(nodes []*simNode, reasons map[string]int) string {
+	schedulable := 0
+	for _, node := range nodes {
+		if node.schedulable {
+			schedulable++
+		}
+	}
+
+	counts := make([]string, 0, len(reasons))
+	for reason := range reasons {
+		counts = append(counts, reason)
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if reasons[counts[i]] != reasons[counts[j]] {
+			return reasons[counts[i]] > reasons[counts[j]]
+		}
+		return counts[i] < counts[j]
+	})
+
+	for i, reason := range counts {
+		counts[i] = fmt.Sprintf("%d %s", reasons[reason], reason)
+	}
+
+	if len(counts) == 0 {
+		return fmt.Sprintf("0/%d nodes are available", schedulable)
+	}
+
+	return fmt.Sprintf("0/%d nodes are available: %s", schedulable, strings.Join(counts, ", "))
+}
+
+// toleratesTaint returns true if any of the tolerations tolerates the taint
+func toleratesTaint(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nodeReady returns true if the node has a true Ready condition
+func nodeReady(node corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+// nodeInstanceType returns the instance type of a node from its well-known labels
+func nodeInstanceType(node corev1.Node) string {
+	if instanceType, ok := node.Labels[corev1.LabelInstanceTypeStable]; ok {
+		return instanceType
+	}
+
+	return node.Labels[corev1.LabelInstanceType]
+}
+
+// isDaemonSetPod returns true if the pod is controlled by a daemon set
+func isDaemonSetPod(pod *corev1.Pod) bool {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller != nil && *ref.Controller && ref.Kind == "DaemonSet" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// utilization returns the percentage of allocatable cpu and memory which is requested
+func utilization(cpu, allocatableCPU, memory, allocatableMemory int64) types.CapacityUtilization {
+	return types.CapacityUtilization{
+		CPUPercent:    fraction(cpu, allocatableCPU) * 100,
+		MemoryPercent: fraction(memory, allocatableMemory) * 100,
+	}
+}
+
+// fraction divides requested by allocatable, returning zero if nothing is allocatable
+func fraction(requested, allocatable int64) float64 {
+	if allocatable == 0 {
+		return 0
+	}
+
+	return float64(requested) / float64(allocatable)
+}
@@ -0,0 +1,192 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const gib = 1024 * 1024 * 1024
+
+func capacityTestNode(name, instanceType string, cpu, memory string, nodeLabels map[string]string, taints ...corev1.Taint) corev1.Node {
+	allNodeLabels := map[string]string{corev1.LabelInstanceTypeStable: instanceType}
+	for key, value := range nodeLabels {
+		allNodeLabels[key] = value
+	}
+
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: allNodeLabels},
+		Spec:       corev1.NodeSpec{Taints: taints},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func capacityTestPod(name, nodeName, cpu, memory string, podLabels map[string]string, daemonSet bool) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: podLabels},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(cpu),
+						corev1.ResourceMemory: resource.MustParse(memory),
+					},
+				},
+			}},
+		},
+	}
+
+	if daemonSet {
+		controller := true
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "agent", Controller: &controller}}
+	}
+
+	return pod
+}
+
+func TestSimulateCapacity(t *testing.T) {
+	appLabels := map[string]string{LabelKey_WorkloadKind: WorkloadKind_Application}
+	monitoringTaint := corev1.Taint{Key: LabelKey_WorkloadKind, Value: "monitoring", Effect: corev1.TaintEffectNoSchedule}
+
+	nodes := []corev1.Node{
+		capacityTestNode("app-1", "t3.medium", "2", "4Gi", appLabels),
+		capacityTestNode("app-2", "t3.medium", "2", "4Gi", appLabels),
+		capacityTestNode("monitoring-1", "t3.large", "2", "8Gi", map[string]string{LabelKey_WorkloadKind: "monitoring"}, monitoringTaint),
+	}
+
+	pods := []corev1.Pod{
+		capacityTestPod("agent-1", "app-1", "100m", "128Mi", nil, true),
+		capacityTestPod("agent-2", "app-2", "100m", "128Mi", nil, true),
+		capacityTestPod("web-old", "app-1", "1", "1Gi", map[string]string{"porter.run/app-name": "api"}, false),
+		capacityTestPod("other", "app-2", "900m", "1Gi", map[string]string{"porter.run/app-name": "other"}, false),
+	}
+
+	tests := []struct {
+		name             string
+		requests         []CapacityRequest
+		replaced         labels.Selector
+		fits             bool
+		unschedulable    []string
+		fitsExtraNodes   bool
+		extraNodes       []string
+		extraNodeCounts  []int
+		projectedCPU     float64
+		scheduledOnNodes map[string]int
+	}{
+		{
+			name:             "fits on current nodes",
+			requests:         []CapacityRequest{{Name: "web", Replicas: 2, CPUMillis: 500, MemoryBytes: gib}},
+			fits:             true,
+			projectedCPU:     (2100 + 1000) / 6000.0 * 100,
+			scheduledOnNodes: map[string]int{"app-1": 1, "app-2": 1},
+		},
+		{
+			name:             "replaced pods free capacity",
+			requests:         []CapacityRequest{{Name: "web", Replicas: 1, CPUMillis: 1800, MemoryBytes: gib}},
+			replaced:         labels.SelectorFromSet(labels.Set{"porter.run/app-name": "api"}),
+			fits:             true,
+			projectedCPU:     (1100 + 1800) / 6000.0 * 100,
+			scheduledOnNodes: map[string]int{"app-1": 1},
+		},
+		{
+			name:             "pods which do not fit require extra nodes of the application instance type",
+			requests:         []CapacityRequest{{Name: "web", Replicas: 4, CPUMillis: 1000, MemoryBytes: gib}},
+			fits:             false,
+			unschedulable:    []string{"web"},
+			fitsExtraNodes:   true,
+			extraNodes:       []string{"t3.medium"},
+			extraNodeCounts:  []int{3},
+			projectedCPU:     (2100 + 1000 + 3*1100) / 12000.0 * 100,
+			scheduledOnNodes: map[string]int{"app-2": 1},
+		},
+		{
+			name: "tolerations allow pods onto tainted nodes",
+			requests: []CapacityRequest{{
+				Name:         "loki",
+				Replicas:     1,
+				CPUMillis:    1500,
+				MemoryBytes:  4 * gib,
+				NodeSelector: map[string]string{LabelKey_WorkloadKind: "monitoring"},
+				Tolerations:  []corev1.Toleration{{Key: LabelKey_WorkloadKind, Operator: corev1.TolerationOpEqual, Value: "monitoring", Effect: corev1.TaintEffectNoSchedule}},
+			}},
+			fits:             true,
+			projectedCPU:     (2100 + 1500) / 6000.0 * 100,
+			scheduledOnNodes: map[string]int{"monitoring-1": 1},
+		},
+		{
+			name:           "pods larger than any instance type do not fit extra nodes",
+			requests:       []CapacityRequest{{Name: "huge", Replicas: 1, CPUMillis: 8000, MemoryBytes: gib}},
+			fits:           false,
+			unschedulable:  []string{"huge"},
+			fitsExtraNodes: false,
+			projectedCPU:   2100 / 6000.0 * 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := SimulateCapacity(SimulateCapacityInput{
+				Nodes:    nodes,
+				Pods:     pods,
+				Replaced: tt.replaced,
+				Requests: tt.requests,
+			})
+
+			assert.Equal(t, tt.fits, res.Fits)
+			assert.InDelta(t, 2100/6000.0*100, res.CurrentUtilization.CPUPercent, 0.01)
+			assert.InDelta(t, tt.projectedCPU, res.ProjectedUtilization.CPUPercent, 0.01)
+
+			assert.Len(t, res.UnschedulablePods, len(tt.unschedulable))
+			for i, name := range tt.unschedulable {
+				assert.Equal(t, name, res.UnschedulablePods[i].Name)
+				assert.Equal(t, tt.fitsExtraNodes, res.UnschedulablePods[i].FitsExtraNodes)
+				assert.Contains(t, res.UnschedulablePods[i].Reason, "0/3 nodes are available")
+			}
+
+			assert.Len(t, res.ExtraNodes, len(tt.extraNodes))
+			for i, instanceType := range tt.extraNodes {
+				assert.Equal(t, instanceType, res.ExtraNodes[i].InstanceType)
+				assert.Equal(t, tt.extraNodeCounts[i], res.ExtraNodes[i].Count)
+			}
+
+			for _, node := range res.Nodes {
+				if count, ok := tt.scheduledOnNodes[node.Name]; ok {
+					assert.Equal(t, count, node.SimulatedPods, node.Name)
+				} else if !node.Extra {
+					assert.Equal(t, 0, node.SimulatedPods, node.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestSimulateCapacitySkipsUnschedulableNodes(t *testing.T) {
+	cordoned := capacityTestNode("cordoned", "t3.medium", "4", "8Gi", nil)
+	cordoned.Spec.Unschedulable = true
+
+	notReady := capacityTestNode("not-ready", "t3.medium", "4", "8Gi", nil)
+	notReady.Status.Conditions[0].Status = corev1.ConditionFalse
+
+	res := SimulateCapacity(SimulateCapacityInput{
+		Nodes:    []corev1.Node{cordoned, notReady, capacityTestNode("ready", "t3.small", "1", "2Gi", nil)},
+		Requests: []CapacityRequest{{Name: "web", Replicas: 1, CPUMillis: 2000, MemoryBytes: gib}},
+	})
+
+	assert.False(t, res.Fits)
+	assert.Len(t, res.UnschedulablePods, 1)
+	assert.Equal(t, "0/1 nodes are available: 1 insufficient cpu", res.UnschedulablePods[0].Reason)
+	assert.Equal(t, []types.CapacityExtraNodes{{InstanceType: "t3.medium", Count: 1}}, res.ExtraNodes)
+}
@@ -0,0 +1,382 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// mirrorPodAnnotation is set on the api server's copies of static pods, which cannot be evicted
+const mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+// uncordonTimeout bounds how long uncordoning a node which was not drained may take
+const uncordonTimeout = 30 * time.Second
+
+// maintenancePollInterval is how often evictions blocked by a PodDisruptionBudget are retried, and how often evicted
+// pods and their workloads are checked
+var maintenancePollInterval = 5 * time.Second
+
+// DrainOptions are the options for draining a node
+type DrainOptions struct {
+	// Timeout bounds how long the drain may take
+	Timeout time.Duration
+	// Force evicts pods which are not managed by a controller. Such pods are not recreated once evicted.
+	Force bool
+	// Progress is called with each progress event. It may be called concurrently.
+	Progress func(types.NodeMaintenanceEvent)
+}
+
+// RotateOptions are the options for rotating nodes
+type RotateOptions struct {
+	// DrainOptions apply to each node. The timeout bounds both draining the node and waiting for its pods to be ready
+	// elsewhere.
+	DrainOptions
+	// ReadySelector selects the evicted pods whose workloads must be ready on other nodes before the next node is
+	// drained. If nil, the workloads of every evicted pod must be ready.
+	ReadySelector labels.Selector
+}
+
+// SetNodeSchedulable cordons the node if schedulable is false, or uncordons it if schedulable is true
+func SetNodeSchedulable(ctx context.Context, clientset kubernetes.Interface, name string, schedulable bool) (*corev1.Node, error) {
+	node, err := clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if node.Spec.Unschedulable == !schedulable {
+		return node, nil
+	}
+
+	node.Spec.Unschedulable = !schedulable
+
+	return clientset.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+}
+
+// DrainNode cordons a node and evicts its pods, returning the evicted pods once they have been deleted. Evictions
+// respect PodDisruptionBudgets: an eviction which would violate a budget is retried until it is allowed or the drain
+// times out. Daemon set pods, mirror pods and completed pods are left on the node. Pods which are not managed by a
+// controller fail the drain before anything is evicted, unless the drain is forced.
+func DrainNode(ctx context.Context, clientset kubernetes.Interface, name string, opts DrainOptions) ([]corev1.Pod, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	progress := opts.progress()
+
+	if _, err := SetNodeSchedulable(ctx, clientset, name, false); err != nil {
+		return nil, fmt.Errorf("error cordoning node %s: %w", name, err)
+	}
+	progress(types.NodeMaintenanceEvent{Type: types.NodeMaintenanceEventType_Cordoned, Node: name})
+
+	podList, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing pods on node %s: %w", name, err)
+	}
+
+	var evict []corev1.Pod
+	var unmanaged []string
+
+	for _, pod := range podList.Items {
+		if pod.Spec.NodeName != name {
+			continue
+		}
+
+		if reason := skipEvictionReason(pod); reason != "" {
+			progress(types.NodeMaintenanceEvent{
+				Type:      types.NodeMaintenanceEventType_PodSkipped,
+				Node:      name,
+				Namespace: pod.Namespace,
+				Pod:       pod.Name,
+				Message:   reason,
+			})
+			continue
+		}
+
+		if metav1.GetControllerOf(&pod) == nil && !opts.Force {
+			unmanaged = append(unmanaged, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+		}
+
+		evict = append(evict, pod)
+	}
+
+	if len(unmanaged) > 0 {
+		return nil, fmt.Errorf("pods on node %s are not managed by a controller and would not be recreated, force the drain to evict them: %s", name, strings.Join(unmanaged, ", "))
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	for i := range evict {
+		pod := evict[i]
+		g.Go(func() error {
+			return evictPod(gctx, clientset, name, pod, progress)
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	progress(types.NodeMaintenanceEvent{
+		Type:    types.NodeMaintenanceEventType_Drained,
+		Node:    name,
+		Message: fmt.Sprintf("evicted %d pods", len(evict)),
+	})
+
+	return evict, nil
+}
+
+// RotateNodes drains the nodes one at a time, so that only the pods of a single node are rescheduled at once. Each
+// node is only cordoned right before it is drained, so that the pods evicted from it can be scheduled onto the nodes
+// which are drained later when there is no spare capacity. After each node is drained, the workloads of its evicted
+// pods must be ready on other nodes before the next node is drained. Drained nodes are left cordoned, so that they can
+// be removed from their node group.
+//
+// If a drain fails or ctx is cancelled during a drain, the node is uncordoned again unless it was already cordoned
+// before the rotation, and the returned error says whether it was.
+func RotateNodes(ctx context.Context, clientset kubernetes.Interface, names []string, opts RotateOptions) error {
+	for _, name := range names {
+		if err := rotateNode(ctx, clientset, name, opts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rotateNode drains a node and waits for the workloads of its evicted pods to be ready elsewhere
+func rotateNode(ctx context.Context, clientset kubernetes.Interface, name string, opts RotateOptions) error {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	progress := opts.progress()
+
+	node, err := clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting node %s: %w", name, err)
+	}
+	wasCordoned := node.Spec.Unschedulable
+
+	evicted, err := DrainNode(ctx, clientset, name, opts.DrainOptions)
+	if err != nil {
+		if wasCordoned {
+			return fmt.Errorf("%w; node %s was cordoned before the rotation, so it was left cordoned", err, name)
+		}
+
+		return uncordonUndrainedNode(clientset, name, err)
+	}
+
+	var waitFor []corev1.Pod
+	for _, pod := range evicted {
+		if opts.ReadySelector == nil || opts.ReadySelector.Matches(labels.Set(pod.Labels)) {
+			waitFor = append(waitFor, pod)
+		}
+	}
+
+	progress(types.NodeMaintenanceEvent{
+		Type:    types.NodeMaintenanceEventType_WaitingForPods,
+		Node:    name,
+		Message: fmt.Sprintf("waiting for the workloads of %d evicted pods to be ready", len(waitFor)),
+	})
+
+	if err := waitForWorkloadsReady(ctx, clientset, waitFor); err != nil {
+		return fmt.Errorf("error waiting for pods evicted from node %s to be ready: %w", name, err)
+	}
+
+	progress(types.NodeMaintenanceEvent{Type: types.NodeMaintenanceEventType_PodsReady, Node: name})
+
+	return nil
+}
+
+// uncordonUndrainedNode uncordons a node whose drain failed, and adds whether the node was uncordoned to the error
+// of the drain. The node is uncordoned on its own context, since the drain may have failed because ctx was cancelled.
+func uncordonUndrainedNode(clientset kubernetes.Interface, name string, drainErr error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), uncordonTimeout)
+	defer cancel()
+
+	if _, err := SetNodeSchedulable(ctx, clientset, name, true); err != nil {
+		return fmt.Errorf("%w; node %s was not drained, but could not be uncordoned and must be uncordoned manually: %v", drainErr, name, err)
+	}
+
+	return fmt.Errorf("%w; node %s was not drained, so it was uncordoned", drainErr, name)
+}
+
+// progress returns a progress callback which is safe to call concurrently, and which sets the time of each event
+func (o DrainOptions) progress() func(types.NodeMaintenanceEvent) {
+	var mu sync.Mutex
+
+	return func(event types.NodeMaintenanceEvent) {
+		if o.Progress == nil {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		event.Time = time.Now().UTC()
+		o.Progress(event)
+	}
+}
+
+// skipEvictionReason returns why a pod is left on a drained node, or an empty string if the pod should be evicted
+func skipEvictionReason(pod corev1.Pod) string {
+	if _, ok := pod.Annotations[mirrorPodAnnotation]; ok {
+		return "mirror pods cannot be evicted"
+	}
+
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return "pod has completed"
+	}
+
+	if controller := metav1.GetControllerOf(&pod); controller != nil && controller.Kind == "DaemonSet" {
+		return "daemon set pods run on every node"
+	}
+
+	return ""
+}
+
+// evictPod evicts a pod, retrying while the eviction is blocked by a PodDisruptionBudget, and waits for the pod to
+// be deleted
+func evictPod(ctx context.Context, clientset kubernetes.Interface, node string, pod corev1.Pod, progress func(types.NodeMaintenanceEvent)) error {
+	event := types.NodeMaintenanceEvent{Node: node, Namespace: pod.Namespace, Pod: pod.Name}
+
+	event.Type = types.NodeMaintenanceEventType_Evicting
+	progress(event)
+
+	blocked := false
+
+	for {
+		err := clientset.PolicyV1().Evictions(pod.Namespace).Evict(ctx, &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		})
+		if err == nil || k8serrors.IsNotFound(err) {
+			break
+		}
+
+		if !k8serrors.IsTooManyRequests(err) {
+			return fmt.Errorf("error evicting pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+
+		if !blocked {
+			blocked = true
+			event.Type = types.NodeMaintenanceEventType_EvictionBlocked
+			event.Message = err.Error()
+			progress(event)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out evicting pod %s/%s, which is blocked by a PodDisruptionBudget", pod.Namespace, pod.Name)
+		case <-time.After(maintenancePollInterval):
+		}
+	}
+
+	for {
+		current, err := clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) || (err == nil && current.UID != pod.UID) {
+			break
+		}
+		if err != nil && ctx.Err() == nil {
+			return fmt.Errorf("error getting evicted pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for evicted pod %s/%s to be deleted", pod.Namespace, pod.Name)
+		case <-time.After(maintenancePollInterval):
+		}
+	}
+
+	event.Type = types.NodeMaintenanceEventType_Evicted
+	event.Message = ""
+	progress(event)
+
+	return nil
+}
+
+// waitForWorkloadsReady waits until the replica sets and stateful sets which controlled the pods have as many ready
+// replicas as they desire. Pods of other workloads are not waited for.
+func waitForWorkloadsReady(ctx context.Context, clientset kubernetes.Interface, pods []corev1.Pod) error {
+	type workload struct {
+		kind, namespace, name string
+	}
+
+	pending := make(map[workload]bool)
+	for i := range pods {
+		controller := metav1.GetControllerOf(&pods[i])
+		if controller == nil || (controller.Kind != "ReplicaSet" && controller.Kind != "StatefulSet") {
+			continue
+		}
+
+		pending[workload{kind: controller.Kind, namespace: pods[i].Namespace, name: controller.Name}] = true
+	}
+
+	for len(pending) > 0 {
+		for w := range pending {
+			var ready bool
+
+			switch w.kind {
+			case "ReplicaSet":
+				rs, err := clientset.AppsV1().ReplicaSets(w.namespace).Get(ctx, w.name, metav1.GetOptions{})
+				if k8serrors.IsNotFound(err) {
+					// the replica set was replaced by a new revision of its deployment
+					ready = true
+					break
+				}
+				if err != nil {
+					return fmt.Errorf("error getting replica set %s/%s: %w", w.namespace, w.name, err)
+				}
+				ready = replicasReady(rs.Spec.Replicas, rs.Status.ReadyReplicas)
+			case "StatefulSet":
+				sts, err := clientset.AppsV1().StatefulSets(w.namespace).Get(ctx, w.name, metav1.GetOptions{})
+				if k8serrors.IsNotFound(err) {
+					ready = true
+					break
+				}
+				if err != nil {
+					return fmt.Errorf("error getting stateful set %s/%s: %w", w.namespace, w.name, err)
+				}
+				ready = replicasReady(sts.Spec.Replicas, sts.Status.ReadyReplicas)
+			}
+
+			if ready {
+				delete(pending, w)
+			}
+		}
+
+		if len(pending) == 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			names := make([]string, 0, len(pending))
+			for w := range pending {
+				names = append(names, fmt.Sprintf("%s %s/%s", strings.ToLower(w.kind), w.namespace, w.name))
+			}
+			return fmt.Errorf("timed out waiting for workloads to be ready: %s", strings.Join(names, ", "))
+		case <-time.After(maintenancePollInterval):
+		}
+	}
+
+	return nil
+}
+
+// replicasReady returns true if the number of ready replicas is at least the desired number, which defaults to one
+func replicasReady(desired *int32, ready int32) bool {
+	if desired == nil {
+		return ready >= 1
+	}
+
+	return ready >= *desired
+}
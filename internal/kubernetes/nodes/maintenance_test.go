@@ -0,0 +1,315 @@
+package nodes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func maintenanceTestNode(name string) *corev1.Node {
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+}
+
+func maintenanceTestPod(name, nodeName, controllerKind, controllerName string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       k8stypes.UID("uid-" + name),
+			Labels:    map[string]string{"porter.run/app-name": "example-app"},
+		},
+		Spec:   corev1.PodSpec{NodeName: nodeName},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	if controllerKind != "" {
+		controller := true
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: controllerKind, Name: controllerName, Controller: &controller}}
+	}
+
+	return pod
+}
+
+func maintenanceTestReplicaSet(name string, replicas, ready int32) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       appsv1.ReplicaSetSpec{Replicas: &replicas},
+		Status:     appsv1.ReplicaSetStatus{ReadyReplicas: ready},
+	}
+}
+
+// maintenanceTestClientset returns a fake clientset where evictions delete the evicted pod, after the first
+// blockedEvictions evictions are rejected as if they violated a PodDisruptionBudget
+func maintenanceTestClientset(t *testing.T, blockedEvictions int, objects ...runtime.Object) *fake.Clientset {
+	t.Helper()
+
+	previousInterval := maintenancePollInterval
+	maintenancePollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { maintenancePollInterval = previousInterval })
+
+	clientset, ok := kubernetes.GetAgentTesting(objects...).Clientset.(*fake.Clientset)
+	if !ok {
+		t.Fatalf("expected testing agent to use a fake clientset")
+	}
+
+	clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+
+		if blockedEvictions > 0 {
+			blockedEvictions--
+			return true, nil, k8serrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+		}
+
+		eviction, _ := action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction)
+
+		return true, nil, clientset.Tracker().Delete(corev1.SchemeGroupVersion.WithResource("pods"), action.GetNamespace(), eviction.Name)
+	})
+
+	return clientset
+}
+
+// recordEvents returns a progress callback which records the types of events, along with the recorded types
+func recordEvents() (func(types.NodeMaintenanceEvent), *[]string) {
+	var events []string
+
+	return func(event types.NodeMaintenanceEvent) {
+		events = append(events, string(event.Type)+" "+event.Node+" "+event.Pod)
+	}, &events
+}
+
+func TestDrainNode(t *testing.T) {
+	mirror := maintenanceTestPod("kube-proxy", "node-1", "", "")
+	mirror.Annotations = map[string]string{mirrorPodAnnotation: "hash"}
+
+	clientset := maintenanceTestClientset(t, 0,
+		maintenanceTestNode("node-1"),
+		maintenanceTestPod("web", "node-1", "ReplicaSet", "web-abc"),
+		maintenanceTestPod("agent", "node-1", "DaemonSet", "agent"),
+		mirror,
+		maintenanceTestPod("worker", "node-2", "ReplicaSet", "worker-abc"),
+	)
+
+	progress, events := recordEvents()
+
+	evicted, err := DrainNode(context.Background(), clientset, "node-1", DrainOptions{Timeout: 5 * time.Second, Progress: progress})
+	assert.NoError(t, err)
+	assert.Len(t, evicted, 1)
+	assert.Equal(t, "web", evicted[0].Name)
+
+	node, err := clientset.CoreV1().Nodes().Get(context.Background(), "node-1", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.True(t, node.Spec.Unschedulable, "drained node should be cordoned")
+
+	_, err = clientset.CoreV1().Pods("default").Get(context.Background(), "web", metav1.GetOptions{})
+	assert.True(t, k8serrors.IsNotFound(err), "evicted pod should be deleted")
+
+	for _, name := range []string{"agent", "kube-proxy", "worker"} {
+		_, err = clientset.CoreV1().Pods("default").Get(context.Background(), name, metav1.GetOptions{})
+		assert.NoError(t, err, "pod %s should not be evicted", name)
+	}
+
+	assert.Equal(t, "cordoned node-1 ", (*events)[0])
+	assert.Contains(t, *events, "evicted node-1 web")
+	assert.Contains(t, *events, "pod_skipped node-1 agent")
+	assert.Contains(t, *events, "pod_skipped node-1 kube-proxy")
+	assert.Equal(t, "drained node-1 ", (*events)[len(*events)-1])
+}
+
+func TestDrainNode_unmanagedPods(t *testing.T) {
+	clientset := maintenanceTestClientset(t, 0,
+		maintenanceTestNode("node-1"),
+		maintenanceTestPod("bare", "node-1", "", ""),
+		maintenanceTestPod("web", "node-1", "ReplicaSet", "web-abc"),
+	)
+
+	_, err := DrainNode(context.Background(), clientset, "node-1", DrainOptions{Timeout: 5 * time.Second})
+	assert.ErrorContains(t, err, "default/bare")
+
+	_, err = clientset.CoreV1().Pods("default").Get(context.Background(), "web", metav1.GetOptions{})
+	assert.NoError(t, err, "no pods should be evicted when the drain is refused")
+
+	evicted, err := DrainNode(context.Background(), clientset, "node-1", DrainOptions{Timeout: 5 * time.Second, Force: true})
+	assert.NoError(t, err)
+	assert.Len(t, evicted, 2)
+}
+
+func TestDrainNode_podDisruptionBudget(t *testing.T) {
+	clientset := maintenanceTestClientset(t, 2,
+		maintenanceTestNode("node-1"),
+		maintenanceTestPod("web", "node-1", "ReplicaSet", "web-abc"),
+	)
+
+	progress, events := recordEvents()
+
+	_, err := DrainNode(context.Background(), clientset, "node-1", DrainOptions{Timeout: 5 * time.Second, Progress: progress})
+	assert.NoError(t, err, "blocked evictions should be retried")
+	assert.Contains(t, *events, "eviction_blocked node-1 web")
+	assert.Contains(t, *events, "evicted node-1 web")
+
+	clientset = maintenanceTestClientset(t, 1000,
+		maintenanceTestNode("node-1"),
+		maintenanceTestPod("web", "node-1", "ReplicaSet", "web-abc"),
+	)
+
+	_, err = DrainNode(context.Background(), clientset, "node-1", DrainOptions{Timeout: 100 * time.Millisecond})
+	assert.ErrorContains(t, err, "PodDisruptionBudget")
+}
+
+func TestRotateNodes(t *testing.T) {
+	clientset := maintenanceTestClientset(t, 0,
+		maintenanceTestNode("node-1"),
+		maintenanceTestNode("node-2"),
+		maintenanceTestNode("node-3"),
+		maintenanceTestPod("web-1", "node-1", "ReplicaSet", "web-abc"),
+		maintenanceTestPod("web-2", "node-2", "ReplicaSet", "web-abc"),
+		maintenanceTestReplicaSet("web-abc", 2, 2),
+	)
+
+	progress, events := recordEvents()
+
+	err := RotateNodes(context.Background(), clientset, []string{"node-1", "node-2"}, RotateOptions{
+		DrainOptions: DrainOptions{Timeout: 5 * time.Second, Progress: progress},
+	})
+	assert.NoError(t, err)
+
+	for name, cordoned := range map[string]bool{"node-1": true, "node-2": true, "node-3": false} {
+		node, err := clientset.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, cordoned, node.Spec.Unschedulable, name)
+	}
+
+	index := func(event string) int {
+		for i, e := range *events {
+			if e == event {
+				return i
+			}
+		}
+		t.Fatalf("expected event %q, got %v", event, *events)
+		return -1
+	}
+
+	assert.Less(t, index("pods_ready node-1 "), index("cordoned node-2 "), "each node should only be cordoned right before it is drained")
+	assert.Less(t, index("pods_ready node-1 "), index("evicting node-2 web-2"), "pods should be ready before the next node is drained")
+}
+
+func TestRotateNodes_drainFailed(t *testing.T) {
+	preCordoned := maintenanceTestNode("node-3")
+	preCordoned.Spec.Unschedulable = true
+
+	clientset := maintenanceTestClientset(t, 0,
+		maintenanceTestNode("node-1"),
+		maintenanceTestNode("node-2"),
+		preCordoned,
+		maintenanceTestNode("node-4"),
+		maintenanceTestPod("web-1", "node-1", "ReplicaSet", "web-abc"),
+		maintenanceTestPod("bare", "node-2", "", ""),
+		maintenanceTestReplicaSet("web-abc", 1, 1),
+	)
+
+	err := RotateNodes(context.Background(), clientset, []string{"node-1", "node-2", "node-4"}, RotateOptions{
+		DrainOptions: DrainOptions{Timeout: 5 * time.Second},
+	})
+	assert.ErrorContains(t, err, "default/bare")
+	assert.ErrorContains(t, err, "node node-2 was not drained, so it was uncordoned")
+
+	for name, cordoned := range map[string]bool{"node-1": true, "node-2": false, "node-3": true, "node-4": false} {
+		node, err := clientset.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, cordoned, node.Spec.Unschedulable, name)
+	}
+
+	clientset = maintenanceTestClientset(t, 0,
+		preCordoned,
+		maintenanceTestPod("bare", "node-3", "", ""),
+	)
+
+	err = RotateNodes(context.Background(), clientset, []string{"node-3"}, RotateOptions{
+		DrainOptions: DrainOptions{Timeout: 5 * time.Second},
+	})
+	assert.ErrorContains(t, err, "node node-3 was cordoned before the rotation, so it was left cordoned")
+
+	node, err := clientset.CoreV1().Nodes().Get(context.Background(), "node-3", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.True(t, node.Spec.Unschedulable, "nodes cordoned before the rotation should stay cordoned")
+}
+
+func TestRotateNodes_cancelled(t *testing.T) {
+	clientset := maintenanceTestClientset(t, 1000,
+		maintenanceTestNode("node-1"),
+		maintenanceTestNode("node-2"),
+		maintenanceTestPod("web-1", "node-1", "ReplicaSet", "web-abc"),
+		maintenanceTestPod("web-2", "node-2", "ReplicaSet", "web-abc"),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	progress := func(event types.NodeMaintenanceEvent) {
+		// the client goes away while an eviction is blocked by a PodDisruptionBudget
+		if event.Type == types.NodeMaintenanceEventType_EvictionBlocked {
+			cancel()
+		}
+	}
+
+	err := RotateNodes(ctx, clientset, []string{"node-1", "node-2"}, RotateOptions{
+		DrainOptions: DrainOptions{Timeout: 5 * time.Second, Progress: progress},
+	})
+	assert.ErrorContains(t, err, "node node-1 was not drained, so it was uncordoned")
+
+	for _, name := range []string{"node-1", "node-2"} {
+		node, err := clientset.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.False(t, node.Spec.Unschedulable, "%s should not be left cordoned", name)
+	}
+}
+
+func TestRotateNodes_podsNotReady(t *testing.T) {
+	clientset := maintenanceTestClientset(t, 0,
+		maintenanceTestNode("node-1"),
+		maintenanceTestNode("node-2"),
+		maintenanceTestPod("web-1", "node-1", "ReplicaSet", "web-abc"),
+		maintenanceTestPod("web-2", "node-2", "ReplicaSet", "web-abc"),
+		maintenanceTestReplicaSet("web-abc", 2, 1),
+	)
+
+	err := RotateNodes(context.Background(), clientset, []string{"node-1", "node-2"}, RotateOptions{
+		DrainOptions: DrainOptions{Timeout: 200 * time.Millisecond},
+	})
+	assert.ErrorContains(t, err, "replicaset default/web-abc")
+
+	_, err = clientset.CoreV1().Pods("default").Get(context.Background(), "web-2", metav1.GetOptions{})
+	assert.NoError(t, err, "the next node should not be drained while pods are not ready")
+
+	node, err := clientset.CoreV1().Nodes().Get(context.Background(), "node-2", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.False(t, node.Spec.Unschedulable, "the next node should not be cordoned while pods are not ready")
+}
+
+func TestSetNodeSchedulable(t *testing.T) {
+	clientset := maintenanceTestClientset(t, 0, maintenanceTestNode("node-1"))
+
+	node, err := SetNodeSchedulable(context.Background(), clientset, "node-1", false)
+	assert.NoError(t, err)
+	assert.True(t, node.Spec.Unschedulable)
+
+	node, err = SetNodeSchedulable(context.Background(), clientset, "node-1", true)
+	assert.NoError(t, err)
+	assert.False(t, node.Spec.Unschedulable)
+
+	_, err = SetNodeSchedulable(context.Background(), clientset, "missing", true)
+	assert.True(t, k8serrors.IsNotFound(err))
+}
@@ -0,0 +1,176 @@
+package kubernetes
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/porter-dev/porter/api/server/shared/websocket"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// portForwardBufferSize is the maximum number of bytes read from the pod before being written to the websocket
+const portForwardBufferSize = 32 * 1024
+
+// SelectPortForwardPod returns the pod that a port-forward should connect to. Only pods which are running,
+// ready and not terminating are considered, and the longest-running of those is returned so that repeated
+// connections land on the same pod.
+func SelectPortForwardPod(pods []v1.Pod) (*v1.Pod, error) {
+	healthy := make([]v1.Pod, 0)
+
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == v1.PodReady && condition.Status == v1.ConditionTrue {
+				healthy = append(healthy, pod)
+				break
+			}
+		}
+	}
+
+	if len(healthy) == 0 {
+		return nil, fmt.Errorf("no healthy pods found")
+	}
+
+	sort.SliceStable(healthy, func(i, j int) bool {
+		if healthy[i].CreationTimestamp.Equal(&healthy[j].CreationTimestamp) {
+			return healthy[i].Name < healthy[j].Name
+		}
+
+		return healthy[i].CreationTimestamp.Before(&healthy[j].CreationTimestamp)
+	})
+
+	return &healthy[0], nil
+}
+
+// PortForwardPod proxies a single TCP connection to a port on a pod over the websocket. Binary messages
+// read from the websocket are written to the pod, and data read from the pod is written back to the
+// websocket as binary messages. The websocket is closed when either side of the connection closes.
+func (a *Agent) PortForwardPod(namespace, name string, port int, rw *websocket.WebsocketSafeReadWriter) error {
+	restConf, err := a.RESTClientGetter.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(restConf)
+	if err != nil {
+		return err
+	}
+
+	req := a.Clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(name).
+		SubResource("portforward")
+
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+
+	streamConn, _, err := dialer.Dial(portforward.PortForwardProtocolV1Name)
+	if err != nil {
+		return fmt.Errorf("error upgrading connection to pod %s: %w", name, err)
+	}
+	defer streamConn.Close()
+
+	// every port-forward request is made up of an error stream and a data stream which share a request id
+	headers := http.Header{}
+	headers.Set(v1.StreamType, v1.StreamTypeError)
+	headers.Set(v1.PortHeader, strconv.Itoa(port))
+	headers.Set(v1.PortForwardRequestIDHeader, "0")
+
+	errorStream, err := streamConn.CreateStream(headers)
+	if err != nil {
+		return fmt.Errorf("error creating error stream for pod %s: %w", name, err)
+	}
+
+	// the error stream is only read from
+	errorStream.Close() // nolint:errcheck,gosec
+
+	headers.Set(v1.StreamType, v1.StreamTypeData)
+
+	dataStream, err := streamConn.CreateStream(headers)
+	if err != nil {
+		return fmt.Errorf("error creating data stream for pod %s: %w", name, err)
+	}
+
+	// buffered so that the goroutines which do not report the first error can still exit
+	errorchan := make(chan error, 3)
+
+	go func() {
+		message, err := io.ReadAll(errorStream)
+		if err != nil {
+			errorchan <- fmt.Errorf("error reading from error stream for pod %s: %w", name, err)
+			return
+		}
+
+		if len(message) != 0 {
+			errorchan <- fmt.Errorf("error forwarding port %d to pod %s: %s", port, name, string(message))
+		}
+	}()
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				// TODO: add method to alert on panic
+				return
+			}
+		}()
+
+		// listens for data from the client, and for the websocket closing handshake
+		for {
+			_, data, err := rw.ReadMessage()
+			if err != nil {
+				errorchan <- nil
+				return
+			}
+
+			if _, err := dataStream.Write(data); err != nil {
+				errorchan <- err
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				// TODO: add method to alert on panic
+				return
+			}
+		}()
+
+		buf := make([]byte, portForwardBufferSize)
+
+		for {
+			n, err := dataStream.Read(buf)
+			if n > 0 {
+				if writeErr := rw.WriteBinary(buf[:n]); writeErr != nil {
+					errorchan <- writeErr
+					return
+				}
+			}
+
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+
+				errorchan <- err
+				return
+			}
+		}
+	}()
+
+	err = <-errorchan
+
+	rw.Close()         // nolint:errcheck,gosec
+	dataStream.Close() // nolint:errcheck,gosec
+
+	return err
+}
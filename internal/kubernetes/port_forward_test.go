@@ -0,0 +1,60 @@
+package kubernetes_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func portForwardTestPod(name string, created time.Time, phase v1.PodPhase, ready bool) v1.Pod {
+	readyStatus := v1.ConditionFalse
+	if ready {
+		readyStatus = v1.ConditionTrue
+	}
+
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Status: v1.PodStatus{
+			Phase: phase,
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodReady, Status: readyStatus},
+			},
+		},
+	}
+}
+
+func TestSelectPortForwardPod(t *testing.T) {
+	now := time.Now()
+	deleted := metav1.NewTime(now)
+
+	terminating := portForwardTestPod("terminating", now.Add(-3*time.Hour), v1.PodRunning, true)
+	terminating.DeletionTimestamp = &deleted
+
+	pods := []v1.Pod{
+		portForwardTestPod("pending", now.Add(-4*time.Hour), v1.PodPending, false),
+		terminating,
+		portForwardTestPod("not-ready", now.Add(-2*time.Hour), v1.PodRunning, false),
+		portForwardTestPod("newer", now.Add(-time.Minute), v1.PodRunning, true),
+		portForwardTestPod("older", now.Add(-time.Hour), v1.PodRunning, true),
+	}
+
+	pod, err := kubernetes.SelectPortForwardPod(pods)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if pod.Name != "older" {
+		t.Errorf("expected oldest healthy pod to be selected, got %s", pod.Name)
+	}
+
+	if _, err := kubernetes.SelectPortForwardPod(pods[:3]); err == nil {
+		t.Errorf("expected error when no pods are healthy")
+	}
+}
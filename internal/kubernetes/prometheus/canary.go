@@ -0,0 +1,217 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/telemetry"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// DefaultCanaryMaxErrorRateIncrease is the default number of percentage points that the 5xx rate of a canary may
+	// exceed the stable rate by
+	DefaultCanaryMaxErrorRateIncrease = 1
+	// DefaultCanaryMaxLatencyIncreasePercent is the default percentage that the p95 latency of a canary may exceed the
+	// stable latency by
+	DefaultCanaryMaxLatencyIncreasePercent = 20
+	// DefaultCanaryMinRequests is the default number of requests a canary must receive for a step to be conclusive
+	DefaultCanaryMinRequests = 20
+)
+
+// canaryLatencyTolerance is the smallest increase in p95 latency which fails a canary. Quantiles are interpolated
+// from histogram buckets, so smaller differences between fast services are not meaningful.
+const canaryLatencyTolerance = 25 * time.Millisecond
+
+// CanaryAnalysisWithDefaults returns the analysis thresholds with zero values replaced by their defaults
+func CanaryAnalysisWithDefaults(analysis types.AppCanaryAnalysis) types.AppCanaryAnalysis {
+	if analysis.MaxErrorRateIncrease == 0 {
+		analysis.MaxErrorRateIncrease = DefaultCanaryMaxErrorRateIncrease
+	}
+
+	if analysis.MaxLatencyIncreasePercent == 0 {
+		analysis.MaxLatencyIncreasePercent = DefaultCanaryMaxLatencyIncreasePercent
+	}
+
+	if analysis.MinRequests == 0 {
+		analysis.MinRequests = DefaultCanaryMinRequests
+	}
+
+	return analysis
+}
+
+// CanaryAnalysisOpts are the options for comparing a canary against the stable image of a web service
+type CanaryAnalysisOpts struct {
+	// Namespace is the namespace of both ingresses
+	Namespace string
+	// StableIngress is the name of the ingress which routes to the stable image
+	StableIngress string
+	// CanaryIngress is the name of the nginx canary ingress which routes to the canary
+	CanaryIngress string
+	// Window is how far back requests are compared, which is usually the duration of the step
+	Window time.Duration
+	// Analysis are the thresholds that the canary is compared with. Zero values are replaced by defaults.
+	Analysis types.AppCanaryAnalysis
+}
+
+// AnalyzeCanary compares the error rate and latency of a canary against the stable image of a web service over a
+// window. The weight and times of the returned step are left for the caller to set.
+func AnalyzeCanary(
+	ctx context.Context,
+	clientset kubernetes.Interface,
+	service *v1.Service,
+	opts CanaryAnalysisOpts,
+) (types.AppCanaryStepResult, error) {
+	ctx, span := telemetry.NewSpan(ctx, "analyze-canary")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "namespace", Value: opts.Namespace},
+		telemetry.AttributeKV{Key: "stable-ingress", Value: opts.StableIngress},
+		telemetry.AttributeKV{Key: "canary-ingress", Value: opts.CanaryIngress},
+		telemetry.AttributeKV{Key: "window", Value: opts.Window.String()},
+	)
+
+	if len(service.Spec.Ports) == 0 {
+		return types.AppCanaryStepResult{}, telemetry.Error(ctx, span, nil, "prometheus service has no exposed ports to query")
+	}
+
+	query := func(query string) (float64, bool, error) {
+		return queryPrometheusInstant(ctx, clientset, service, query)
+	}
+
+	res, err := analyzeCanary(opts, query)
+	if err != nil {
+		return res, telemetry.Error(ctx, span, err, "error analyzing canary")
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "passed", Value: res.Passed},
+		telemetry.AttributeKV{Key: "inconclusive", Value: res.Inconclusive},
+	)
+
+	return res, nil
+}
+
+// analyzeCanary compares a canary against the stable image, using query to read metrics
+func analyzeCanary(opts CanaryAnalysisOpts, query sloInstantQuery) (types.AppCanaryStepResult, error) {
+	res := types.AppCanaryStepResult{}
+
+	if opts.Namespace == "" || opts.StableIngress == "" || opts.CanaryIngress == "" {
+		return res, fmt.Errorf("namespace, stable ingress and canary ingress are required")
+	}
+
+	if opts.Window <= 0 {
+		return res, fmt.Errorf("window must be positive")
+	}
+
+	analysis := CanaryAnalysisWithDefaults(opts.Analysis)
+
+	stable, err := canaryMetrics(opts.Namespace, opts.StableIngress, opts.Window, query)
+	if err != nil {
+		return res, fmt.Errorf("error reading stable metrics: %w", err)
+	}
+	res.Stable = stable
+
+	canary, err := canaryMetrics(opts.Namespace, opts.CanaryIngress, opts.Window, query)
+	if err != nil {
+		return res, fmt.Errorf("error reading canary metrics: %w", err)
+	}
+	res.Canary = canary
+
+	res.Passed = true
+
+	if canary.Requests < float64(analysis.MinRequests) {
+		res.Inconclusive = true
+		res.Reason = fmt.Sprintf("canary received %.0f requests, fewer than the %d required to compare it", canary.Requests, analysis.MinRequests)
+		return res, nil
+	}
+
+	if (canary.ErrorRate-stable.ErrorRate)*100 > analysis.MaxErrorRateIncrease {
+		res.Passed = false
+		res.Reason = fmt.Sprintf(
+			"canary error rate of %.2f%% exceeds the stable error rate of %.2f%% by more than %g percentage points",
+			canary.ErrorRate*100, stable.ErrorRate*100, analysis.MaxErrorRateIncrease,
+		)
+		return res, nil
+	}
+
+	// the latency is only compared if the stable image received requests to compare against
+	if stable.LatencyP95Ms > 0 {
+		allowed := stable.LatencyP95Ms * (1 + analysis.MaxLatencyIncreasePercent/100)
+		tolerance := float64(canaryLatencyTolerance / time.Millisecond)
+
+		if canary.LatencyP95Ms > allowed && canary.LatencyP95Ms-stable.LatencyP95Ms > tolerance {
+			res.Passed = false
+			res.Reason = fmt.Sprintf(
+				"canary p95 latency of %.0fms exceeds the stable p95 latency of %.0fms by more than %g%%",
+				canary.LatencyP95Ms, stable.LatencyP95Ms, analysis.MaxLatencyIncreasePercent,
+			)
+		}
+	}
+
+	return res, nil
+}
+
+// canaryMetrics reads the number of requests, error rate and p95 latency of an ingress over a window
+func canaryMetrics(namespace, ingress string, window time.Duration, query sloInstantQuery) (types.AppCanaryMetrics, error) {
+	res := types.AppCanaryMetrics{}
+
+	opts := SLOQueryOpts{
+		Namespace: namespace,
+		Ingress:   ingress,
+		Type:      types.AppSLOType_Availability,
+	}
+
+	rate, found, err := query(nginxRateSum("nginx_ingress_controller_requests", "", opts, window))
+	if err != nil {
+		return res, fmt.Errorf("error querying request rate: %w", err)
+	}
+
+	if !found {
+		return res, nil
+	}
+
+	res.Requests = rate * window.Seconds()
+
+	errorRatioQuery, err := SLOErrorRatioQuery(opts, window)
+	if err != nil {
+		return res, err
+	}
+
+	errorRatio, _, err := query(errorRatioQuery)
+	if err != nil {
+		return res, fmt.Errorf("error querying error rate: %w", err)
+	}
+	res.ErrorRate = math.Max(0, errorRatio)
+
+	latency, found, err := query(canaryLatencyQuery(opts, window))
+	if err != nil {
+		return res, fmt.Errorf("error querying latency: %w", err)
+	}
+
+	if found {
+		res.LatencyP95Ms = latency * 1000
+	}
+
+	return res, nil
+}
+
+// canaryLatencyQuery returns a PromQL expression for the p95 request latency of an ingress in seconds, like the
+// nginx:latency-histogram metric of the app metrics dashboard
+func canaryLatencyQuery(opts SLOQueryOpts, window time.Duration) string {
+	var queries []string
+	for _, namespaceLabel := range []string{"exported_namespace", "namespace"} {
+		queries = append(queries, fmt.Sprintf(
+			`sum by (le) (rate(nginx_ingress_controller_request_duration_seconds_bucket{%s="%s",ingress="%s"}[%s]))`,
+			namespaceLabel, opts.Namespace, opts.Ingress, promDuration(window),
+		))
+	}
+
+	return fmt.Sprintf("histogram_quantile(0.95, (%s))", strings.Join(queries, " OR "))
+}
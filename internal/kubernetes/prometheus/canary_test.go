@@ -0,0 +1,163 @@
+package prometheus
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_canaryLatencyQuery(t *testing.T) {
+	query := canaryLatencyQuery(SLOQueryOpts{Namespace: "app-namespace", Ingress: "app-web-canary"}, 5*time.Minute)
+	assert.Equal(t,
+		`histogram_quantile(0.95, (sum by (le) (rate(nginx_ingress_controller_request_duration_seconds_bucket{exported_namespace="app-namespace",ingress="app-web-canary"}[5m])) OR sum by (le) (rate(nginx_ingress_controller_request_duration_seconds_bucket{namespace="app-namespace",ingress="app-web-canary"}[5m]))))`,
+		query,
+	)
+}
+
+func Test_CanaryAnalysisWithDefaults(t *testing.T) {
+	assert.Equal(t, types.AppCanaryAnalysis{
+		MaxErrorRateIncrease:      DefaultCanaryMaxErrorRateIncrease,
+		MaxLatencyIncreasePercent: DefaultCanaryMaxLatencyIncreasePercent,
+		MinRequests:               DefaultCanaryMinRequests,
+	}, CanaryAnalysisWithDefaults(types.AppCanaryAnalysis{}))
+
+	analysis := types.AppCanaryAnalysis{MaxErrorRateIncrease: 0.5, MaxLatencyIncreasePercent: 50, MinRequests: 100}
+	assert.Equal(t, analysis, CanaryAnalysisWithDefaults(analysis))
+}
+
+// ingressMetrics are the values returned by a fake prometheus for an ingress
+type ingressMetrics struct {
+	rate       float64
+	errorRatio float64
+	p95        float64
+}
+
+func Test_analyzeCanary(t *testing.T) {
+	opts := CanaryAnalysisOpts{
+		Namespace:     "app-namespace",
+		StableIngress: "app-web",
+		CanaryIngress: "app-web-canary",
+		Window:        5 * time.Minute,
+	}
+
+	// metricsByIngress returns a query function which returns the given metrics for each ingress
+	metricsByIngress := func(metrics map[string]ingressMetrics) sloInstantQuery {
+		return func(query string) (float64, bool, error) {
+			for ingress, m := range metrics {
+				if !strings.Contains(query, `ingress="`+ingress+`"`) {
+					continue
+				}
+
+				switch {
+				case strings.HasPrefix(query, "histogram_quantile"):
+					return m.p95, m.p95 > 0, nil
+				case strings.Contains(query, `status=~"5.."`):
+					return m.errorRatio, true, nil
+				default:
+					return m.rate, true, nil
+				}
+			}
+			return 0, false, nil
+		}
+	}
+
+	tests := []struct {
+		name         string
+		metrics      map[string]ingressMetrics
+		passed       bool
+		inconclusive bool
+		reason       string
+	}{
+		{
+			"canary without requests is inconclusive",
+			map[string]ingressMetrics{"app-web": {rate: 10, p95: 0.1}},
+			true,
+			true,
+			"canary received 0 requests",
+		},
+		{
+			"canary matching stable passes",
+			map[string]ingressMetrics{
+				"app-web":        {rate: 9, errorRatio: 0.001, p95: 0.2},
+				"app-web-canary": {rate: 1, errorRatio: 0.005, p95: 0.22},
+			},
+			true,
+			false,
+			"",
+		},
+		{
+			"canary with more errors fails",
+			map[string]ingressMetrics{
+				"app-web":        {rate: 9, errorRatio: 0.001, p95: 0.2},
+				"app-web-canary": {rate: 1, errorRatio: 0.05, p95: 0.2},
+			},
+			false,
+			false,
+			"canary error rate of 5.00%",
+		},
+		{
+			"slower canary fails",
+			map[string]ingressMetrics{
+				"app-web":        {rate: 9, p95: 0.2},
+				"app-web-canary": {rate: 1, p95: 0.5},
+			},
+			false,
+			false,
+			"canary p95 latency of 500ms",
+		},
+		{
+			"small latency increase of a fast service passes",
+			map[string]ingressMetrics{
+				"app-web":        {rate: 9, p95: 0.01},
+				"app-web-canary": {rate: 1, p95: 0.02},
+			},
+			true,
+			false,
+			"",
+		},
+		{
+			"latency is not compared without stable requests",
+			map[string]ingressMetrics{
+				"app-web-canary": {rate: 1, p95: 2},
+			},
+			true,
+			false,
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := analyzeCanary(opts, metricsByIngress(tt.metrics))
+			assert.Nil(t, err, "expected nil, got %v", err)
+			assert.Equal(t, tt.passed, res.Passed)
+			assert.Equal(t, tt.inconclusive, res.Inconclusive)
+			if tt.reason == "" {
+				assert.Empty(t, res.Reason)
+			} else {
+				assert.Contains(t, res.Reason, tt.reason)
+			}
+		})
+	}
+}
+
+func Test_analyzeCanary_requestsOverWindow(t *testing.T) {
+	opts := CanaryAnalysisOpts{
+		Namespace:     "app-namespace",
+		StableIngress: "app-web",
+		CanaryIngress: "app-web-canary",
+		Window:        2 * time.Minute,
+	}
+
+	res, err := analyzeCanary(opts, func(query string) (float64, bool, error) {
+		if strings.HasPrefix(query, "histogram_quantile") || strings.Contains(query, `status=~"5.."`) {
+			return 0, true, nil
+		}
+		return 0.5, true, nil
+	})
+	assert.Nil(t, err, "expected nil, got %v", err)
+	assert.InDelta(t, 60, res.Canary.Requests, 1e-9)
+	assert.InDelta(t, 60, res.Stable.Requests, 1e-9)
+}
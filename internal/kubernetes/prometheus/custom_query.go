@@ -0,0 +1,173 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/porter-dev/porter/internal/telemetry"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// CustomQueryOpts are the options for a user-defined PromQL range query
+type CustomQueryOpts struct {
+	// Query is the PromQL expression to evaluate
+	Query string
+	// Namespace is enforced on every selector in the query, so that only series from the namespace are returned
+	Namespace string
+	// StartRange is the start time (in unix timestamp) for prometheus results
+	StartRange uint
+	// EndRange is the end time (in unix timestamp) for prometheus results
+	EndRange uint
+	// Resolution is the query step, such as 1m
+	Resolution string
+}
+
+// Series is a single time series returned by a custom query
+type Series struct {
+	Labels map[string]string `json:"labels"`
+	Values []SeriesValue     `json:"values"`
+}
+
+// SeriesValue is a single sample of a time series
+type SeriesValue struct {
+	// Date is the unix timestamp of the sample
+	Date  int64   `json:"date"`
+	Value float64 `json:"value"`
+}
+
+type promRawMatrixQuery struct {
+	Data struct {
+		Result []struct {
+			Metric map[string]string `json:"metric"`
+			Values [][]interface{}   `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// QueryPrometheusCustom runs a user-defined PromQL range query, restricted to series in opts.Namespace
+func QueryPrometheusCustom(
+	ctx context.Context,
+	clientset kubernetes.Interface,
+	service *v1.Service,
+	opts CustomQueryOpts,
+) ([]Series, error) {
+	ctx, span := telemetry.NewSpan(ctx, "query-prometheus-custom")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "query", Value: opts.Query},
+		telemetry.AttributeKV{Key: "namespace", Value: opts.Namespace},
+		telemetry.AttributeKV{Key: "start-range", Value: opts.StartRange},
+		telemetry.AttributeKV{Key: "end-range", Value: opts.EndRange},
+		telemetry.AttributeKV{Key: "resolution", Value: opts.Resolution},
+	)
+
+	if len(service.Spec.Ports) == 0 {
+		return nil, telemetry.Error(ctx, span, nil, "prometheus service has no exposed ports to query")
+	}
+
+	if opts.Namespace == "" {
+		return nil, telemetry.Error(ctx, span, nil, "namespace is required for custom queries")
+	}
+
+	query, err := EnforceLabels(opts.Query, map[string]string{"namespace": opts.Namespace})
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "invalid query")
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "enforced-query", Value: query})
+
+	queryParams := map[string]string{
+		"query": query,
+		"start": fmt.Sprintf("%d", opts.StartRange),
+		"end":   fmt.Sprintf("%d", opts.EndRange),
+		"step":  opts.Resolution,
+	}
+
+	resp := clientset.CoreV1().Services(service.Namespace).ProxyGet(
+		"http",
+		service.Name,
+		fmt.Sprintf("%d", service.Spec.Ports[0].Port),
+		"/api/v1/query_range",
+		queryParams,
+	)
+
+	rawQuery, err := resp.DoRaw(ctx)
+	if err != nil {
+		// in this case, it's very likely that prometheus doesn't contain any data for the given labels
+		if strings.Contains(err.Error(), "rejected our request for an unknown reason") {
+			return []Series{}, nil
+		}
+
+		return nil, telemetry.Error(ctx, span, err, "failed to get raw query")
+	}
+
+	series, err := parseMatrixQuery(rawQuery)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "failed to parse query")
+	}
+
+	return series, nil
+}
+
+// parseMatrixQuery parses the result of a prometheus range query. Samples which are not finite are dropped,
+// since they cannot be represented in JSON.
+func parseMatrixQuery(rawQuery []byte) ([]Series, error) {
+	raw := &promRawMatrixQuery{}
+
+	if err := json.Unmarshal(rawQuery, raw); err != nil {
+		return nil, err
+	}
+
+	res := make([]Series, 0, len(raw.Data.Result))
+
+	for _, result := range raw.Data.Result {
+		series := Series{
+			Labels: result.Metric,
+			Values: make([]SeriesValue, 0, len(result.Values)),
+		}
+
+		if series.Labels == nil {
+			series.Labels = map[string]string{}
+		}
+
+		for _, values := range result.Values {
+			if len(values) != 2 {
+				return nil, fmt.Errorf("unexpected sample with %d values", len(values))
+			}
+
+			date, ok := values[0].(float64)
+			if !ok {
+				return nil, fmt.Errorf("unexpected sample timestamp %v", values[0])
+			}
+
+			rawValue, ok := values[1].(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected sample value %v", values[1])
+			}
+
+			value, err := strconv.ParseFloat(rawValue, 64)
+			if err != nil {
+				return nil, err
+			}
+
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				continue
+			}
+
+			series.Values = append(series.Values, SeriesValue{
+				Date:  int64(date),
+				Value: value,
+			})
+		}
+
+		res = append(res, series)
+	}
+
+	return res, nil
+}
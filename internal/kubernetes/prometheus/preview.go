@@ -0,0 +1,61 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/internal/telemetry"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// NamespaceRequestCount returns the number of requests that the ingresses in a namespace received over a window,
+// which is used to put idle preview deployments to sleep. found is false if there are no ingress metrics for the
+// namespace over the window, which is the case when its ingresses received no requests.
+func NamespaceRequestCount(
+	ctx context.Context,
+	clientset kubernetes.Interface,
+	service *v1.Service,
+	namespace string,
+	window time.Duration,
+) (float64, bool, error) {
+	ctx, span := telemetry.NewSpan(ctx, "namespace-request-count")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "namespace", Value: namespace},
+		telemetry.AttributeKV{Key: "window", Value: window.String()},
+	)
+
+	if len(service.Spec.Ports) == 0 {
+		return 0, false, telemetry.Error(ctx, span, nil, "prometheus service has no exposed ports to query")
+	}
+
+	count, found, err := queryPrometheusInstant(ctx, clientset, service, namespaceRequestCountQuery(namespace, window))
+	if err != nil {
+		return 0, false, telemetry.Error(ctx, span, err, "error querying namespace request count")
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "found", Value: found},
+		telemetry.AttributeKV{Key: "count", Value: count},
+	)
+
+	return count, found, nil
+}
+
+// namespaceRequestCountQuery returns a PromQL expression for the number of requests to the ingresses of a namespace
+// over a window
+func namespaceRequestCountQuery(namespace string, window time.Duration) string {
+	var queries []string
+	for _, namespaceLabel := range []string{"exported_namespace", "namespace"} {
+		queries = append(queries, fmt.Sprintf(
+			`sum(increase(nginx_ingress_controller_requests{%s="%s"}[%s]))`,
+			namespaceLabel, namespace, promDuration(window),
+		))
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(queries, " OR "))
+}
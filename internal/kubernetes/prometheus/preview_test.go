@@ -0,0 +1,15 @@
+package prometheus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_namespaceRequestCountQuery(t *testing.T) {
+	assert.Equal(t,
+		`(sum(increase(nginx_ingress_controller_requests{exported_namespace="pr-42-api"}[4h])) OR sum(increase(nginx_ingress_controller_requests{namespace="pr-42-api"}[4h])))`,
+		namespaceRequestCountQuery("pr-42-api", 4*time.Hour),
+	)
+}
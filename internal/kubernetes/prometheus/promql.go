@@ -0,0 +1,307 @@
+package prometheus
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// promqlTokenKind is the kind of a token in a PromQL expression
+type promqlTokenKind int
+
+const (
+	promqlIdentifier promqlTokenKind = iota
+	promqlString
+	promqlNumber
+	promqlLeftBrace
+	promqlRightBrace
+	promqlLeftParen
+	promqlRightParen
+	promqlRange
+	promqlOperator
+)
+
+// promqlToken is a token in a PromQL expression, along with its position in the expression
+type promqlToken struct {
+	kind  promqlTokenKind
+	text  string
+	start int
+	end   int
+}
+
+// promqlAggregations are the aggregation operators, which may be followed by a grouping clause before their arguments
+var promqlAggregations = map[string]bool{
+	"sum": true, "min": true, "max": true, "avg": true, "group": true, "stddev": true, "stdvar": true,
+	"count": true, "count_values": true, "bottomk": true, "topk": true, "quantile": true,
+	"limitk": true, "limit_ratio": true,
+}
+
+// promqlGroupingKeywords are keywords which are followed by a parenthesized list of label names. Anywhere else, they
+// are metric names.
+var promqlGroupingKeywords = map[string]bool{
+	"by": true, "without": true, "on": true, "ignoring": true,
+}
+
+// promqlInfixKeywords are keywords which can only follow an operand. Anywhere else, they are metric names.
+var promqlInfixKeywords = map[string]bool{
+	"and": true, "or": true, "unless": true, "atan2": true, "offset": true,
+}
+
+// EnforceLabels adds an equality matcher for each of the given labels to every vector selector in a PromQL
+// expression, including the selectors of range vectors and subqueries. Since Prometheus requires all matchers of a
+// selector to match, the expression can only ever select series with the given label values, regardless of any
+// matchers it already contains.
+//
+// Keywords such as `by` or `offset` are only treated as keywords where Prometheus accepts them, so that metrics with
+// the same names are still restricted.
+func EnforceLabels(query string, labels map[string]string) (string, error) {
+	if strings.TrimSpace(query) == "" {
+		return "", errors.New("query cannot be empty")
+	}
+
+	if len(labels) == 0 {
+		return "", errors.New("at least one label must be enforced")
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	matchers := make([]string, 0, len(names))
+	for _, name := range names {
+		matchers = append(matchers, fmt.Sprintf("%s=%s", name, strconv.Quote(labels[name])))
+	}
+	enforced := strings.Join(matchers, ",")
+
+	tokens, err := lexPromQL(query)
+	if err != nil {
+		return "", err
+	}
+
+	type insertion struct {
+		pos  int
+		text string
+	}
+	var insertions []insertion
+
+	// injectIntoBraces adds the enforced matchers to the selector whose opening brace is tokens[i], and returns the
+	// index of the closing brace
+	injectIntoBraces := func(i int) (int, error) {
+		for j := i + 1; j < len(tokens); j++ {
+			if tokens[j].kind == promqlLeftBrace {
+				return 0, fmt.Errorf("unexpected \"{\" at position %d", tokens[j].start)
+			}
+
+			if tokens[j].kind != promqlRightBrace {
+				continue
+			}
+
+			text := enforced
+			if j-1 > i && tokens[j-1].text != "," {
+				text = "," + enforced
+			}
+
+			insertions = append(insertions, insertion{pos: tokens[j].start, text: text})
+			return j, nil
+		}
+
+		return 0, fmt.Errorf("unclosed \"{\" at position %d", tokens[i].start)
+	}
+
+	// prevOperand is true if the previous token ended an operand, and prevVectorMatching is true if it closed the
+	// label list of an `on` or `ignoring` clause
+	prevOperand, prevVectorMatching := false, false
+
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		operand, vectorMatching := false, false
+
+		switch tok.kind {
+		case promqlLeftBrace:
+			end, err := injectIntoBraces(i)
+			if err != nil {
+				return "", err
+			}
+			i = end
+			operand = true
+		case promqlRightBrace:
+			return "", fmt.Errorf("unexpected \"}\" at position %d", tok.start)
+		case promqlString, promqlNumber, promqlRightParen, promqlRange:
+			operand = true
+		case promqlIdentifier:
+			next := promqlTokenKind(-1)
+			if i+1 < len(tokens) {
+				next = tokens[i+1].kind
+			}
+
+			lower := strings.ToLower(tok.text)
+
+			switch {
+			case promqlGroupingKeywords[lower] && next == promqlLeftParen:
+				end, err := skipParens(tokens, i+1)
+				if err != nil {
+					return "", err
+				}
+				i = end
+				vectorMatching = lower == "on" || lower == "ignoring"
+			case (lower == "group_left" || lower == "group_right") && prevVectorMatching:
+				// the label list of group_left and group_right is optional
+				if next == promqlLeftParen {
+					end, err := skipParens(tokens, i+1)
+					if err != nil {
+						return "", err
+					}
+					i = end
+				}
+			case promqlInfixKeywords[lower] && prevOperand:
+			case lower == "bool" && i > 0 && tokens[i-1].kind == promqlOperator && strings.ContainsAny(tokens[i-1].text, "=<>"):
+			case lower == "inf" || lower == "nan":
+				operand = true
+			case next == promqlLeftParen:
+				// a function call or an aggregation, whose arguments are handled by the loop
+			case promqlAggregations[lower] && next == promqlIdentifier && promqlGroupingKeywords[strings.ToLower(tokens[i+1].text)]:
+			case next == promqlLeftBrace:
+				end, err := injectIntoBraces(i + 1)
+				if err != nil {
+					return "", err
+				}
+				i = end
+				operand = true
+			default:
+				insertions = append(insertions, insertion{pos: tok.end, text: "{" + enforced + "}"})
+				operand = true
+			}
+		}
+
+		prevOperand, prevVectorMatching = operand, vectorMatching
+	}
+
+	if len(insertions) == 0 {
+		return "", errors.New("query must contain at least one metric selector")
+	}
+
+	var sb strings.Builder
+	prev := 0
+	for _, ins := range insertions {
+		sb.WriteString(query[prev:ins.pos])
+		sb.WriteString(ins.text)
+		prev = ins.pos
+	}
+	sb.WriteString(query[prev:])
+
+	return sb.String(), nil
+}
+
+// skipParens returns the index of the parenthesis that closes the one at tokens[i]
+func skipParens(tokens []promqlToken, i int) (int, error) {
+	depth := 0
+	for j := i; j < len(tokens); j++ {
+		switch tokens[j].kind {
+		case promqlLeftParen:
+			depth++
+		case promqlRightParen:
+			depth--
+			if depth == 0 {
+				return j, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("unclosed \"(\" at position %d", tokens[i].start)
+}
+
+// lexPromQL splits a PromQL expression into tokens. It only distinguishes the tokens needed to find vector
+// selectors, and leaves full validation of the expression to Prometheus.
+func lexPromQL(query string) ([]promqlToken, error) {
+	var tokens []promqlToken
+
+	for i := 0; i < len(query); {
+		c := query[i]
+		start := i
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case c == '#':
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '"' || c == '\'' || c == '`':
+			i++
+			for i < len(query) && query[i] != c {
+				if query[i] == '\\' && c != '`' {
+					i++
+				}
+				i++
+			}
+
+			if i >= len(query) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+
+			i++
+			tokens = append(tokens, promqlToken{kind: promqlString, text: query[start:i], start: start, end: i})
+		case isPromQLIdentifierStart(c) || c == ':':
+			for i < len(query) && (isPromQLIdentifierStart(query[i]) || isDigit(query[i]) || query[i] == ':') {
+				i++
+			}
+			tokens = append(tokens, promqlToken{kind: promqlIdentifier, text: query[start:i], start: start, end: i})
+		case isDigit(c) || c == '.':
+			for i < len(query) {
+				ch := query[i]
+				// the sign of an exponent, as in 1e-3
+				if (ch == '+' || ch == '-') && (query[i-1] == 'e' || query[i-1] == 'E') && !strings.HasPrefix(query[start:i], "0x") {
+					i++
+					continue
+				}
+
+				if !isDigit(ch) && !isPromQLIdentifierStart(ch) && ch != '.' {
+					break
+				}
+				i++
+			}
+			tokens = append(tokens, promqlToken{kind: promqlNumber, text: query[start:i], start: start, end: i})
+		case c == '[':
+			end := strings.IndexByte(query[i:], ']')
+			if end == -1 {
+				return nil, fmt.Errorf("unclosed \"[\" at position %d", start)
+			}
+
+			i += end + 1
+			tokens = append(tokens, promqlToken{kind: promqlRange, text: query[start:i], start: start, end: i})
+		case c == '{':
+			i++
+			tokens = append(tokens, promqlToken{kind: promqlLeftBrace, text: "{", start: start, end: i})
+		case c == '}':
+			i++
+			tokens = append(tokens, promqlToken{kind: promqlRightBrace, text: "}", start: start, end: i})
+		case c == '(':
+			i++
+			tokens = append(tokens, promqlToken{kind: promqlLeftParen, text: "(", start: start, end: i})
+		case c == ')':
+			i++
+			tokens = append(tokens, promqlToken{kind: promqlRightParen, text: ")", start: start, end: i})
+		case strings.IndexByte("+-*/%^=!<>~,@", c) != -1:
+			i++
+			tokens = append(tokens, promqlToken{kind: promqlOperator, text: query[start:i], start: start, end: i})
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", c, start)
+		}
+	}
+
+	return tokens, nil
+}
+
+func isPromQLIdentifierStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
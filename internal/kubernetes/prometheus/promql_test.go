@@ -0,0 +1,163 @@
+package prometheus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_EnforceLabels(t *testing.T) {
+	labels := map[string]string{"namespace": "app-namespace"}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+		wantErr  bool
+	}{
+		{
+			"bare metric",
+			`orders_total`,
+			`orders_total{namespace="app-namespace"}`,
+			false,
+		},
+		{
+			"metric with matchers and range",
+			`rate(http_requests_total{code=~"5.."}[5m])`,
+			`rate(http_requests_total{code=~"5..",namespace="app-namespace"}[5m])`,
+			false,
+		},
+		{
+			"existing namespace matcher is still restricted",
+			`up{namespace=~".+"}`,
+			`up{namespace=~".+",namespace="app-namespace"}`,
+			false,
+		},
+		{
+			"selector without metric name",
+			`{__name__=~"orders_.*",}`,
+			`{__name__=~"orders_.*",namespace="app-namespace"}`,
+			false,
+		},
+		{
+			"selector matching every metric",
+			`{__name__=~".+"}`,
+			`{__name__=~".+",namespace="app-namespace"}`,
+			false,
+		},
+		{
+			"empty matchers",
+			`orders_total{}`,
+			`orders_total{namespace="app-namespace"}`,
+			false,
+		},
+		{
+			"aggregation with grouping before arguments",
+			`sum by (pod, le) (rate(latency_bucket[1m]))`,
+			`sum by (pod, le) (rate(latency_bucket{namespace="app-namespace"}[1m]))`,
+			false,
+		},
+		{
+			"aggregation with grouping after arguments",
+			`histogram_quantile(0.95, sum(rate(latency_bucket[5m])) without (instance))`,
+			`histogram_quantile(0.95, sum(rate(latency_bucket{namespace="app-namespace"}[5m])) without (instance))`,
+			false,
+		},
+		{
+			"binary operation with vector matching",
+			`errors_total / on(job) group_left(version) requests_total > bool 1e-3`,
+			`errors_total{namespace="app-namespace"} / on(job) group_left(version) requests_total{namespace="app-namespace"} > bool 1e-3`,
+			false,
+		},
+		{
+			"vector matching without group labels",
+			`errors_total / ignoring(code) group_left requests_total`,
+			`errors_total{namespace="app-namespace"} / ignoring(code) group_left requests_total{namespace="app-namespace"}`,
+			false,
+		},
+		{
+			"keywords used as metric names",
+			`sum(by) + up`,
+			`sum(by{namespace="app-namespace"}) + up{namespace="app-namespace"}`,
+			false,
+		},
+		{
+			"keywords used as metric names in range vectors and binary operations",
+			`rate(offset[5m]) / without`,
+			`rate(offset{namespace="app-namespace"}[5m]) / without{namespace="app-namespace"}`,
+			false,
+		},
+		{
+			"binary operators used as metric names",
+			`sum(or) and bool unless group_left`,
+			`sum(or{namespace="app-namespace"}) and bool{namespace="app-namespace"} unless group_left{namespace="app-namespace"}`,
+			false,
+		},
+		{
+			"recording rule with offset and subquery",
+			`max_over_time(job:orders:rate5m[1h:5m] offset 1d) or vector(0)`,
+			`max_over_time(job:orders:rate5m{namespace="app-namespace"}[1h:5m] offset 1d) or vector(0)`,
+			false,
+		},
+		{
+			"nested subqueries",
+			`max_over_time(rate(orders_total[5m])[1h:1m])`,
+			`max_over_time(rate(orders_total{namespace="app-namespace"}[5m])[1h:1m])`,
+			false,
+		},
+		{
+			"at and offset modifiers",
+			`orders_total @ 1609746000 offset 5m - orders_total @ start()`,
+			`orders_total{namespace="app-namespace"} @ 1609746000 offset 5m - orders_total{namespace="app-namespace"} @ start()`,
+			false,
+		},
+		{
+			"at modifier on a range vector",
+			`increase(orders_total[1h] @ end())`,
+			`increase(orders_total{namespace="app-namespace"}[1h] @ end())`,
+			false,
+		},
+		{
+			"strings containing braces",
+			`label_replace(orders_total, "dst", "}{", "src", "(.*)")`,
+			`label_replace(orders_total{namespace="app-namespace"}, "dst", "}{", "src", "(.*)")`,
+			false,
+		},
+		{
+			"no metric selector",
+			`vector(1)`,
+			"",
+			true,
+		},
+		{
+			"unclosed selector",
+			`orders_total{code="200"`,
+			"",
+			true,
+		},
+		{
+			"unterminated string",
+			`orders_total{code="200}`,
+			"",
+			true,
+		},
+		{
+			"empty query",
+			` `,
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := EnforceLabels(tt.query, labels)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.Nil(t, err, "expected nil, got %v", err)
+			assert.Equal(t, tt.expected, query, "got %s, want %s", query, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,349 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/telemetry"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// SLOBurnRateWindow is a pair of windows over which the burn rate of an error budget is alerted on. The long window
+// ensures that a significant part of the budget was consumed, and the short window ensures that the alert stops
+// firing soon after the budget stops burning.
+type SLOBurnRateWindow struct {
+	Long  time.Duration
+	Short time.Duration
+	// BudgetConsumed is the fraction of the budget which must be consumed over the long window for the alert to fire
+	BudgetConsumed float64
+	// Fast is true for windows which detect budgets burning within hours, rather than days
+	Fast bool
+}
+
+// SLOBurnRateWindows are the windows that every SLO is alerted on: 2% of the budget consumed within an hour or 5%
+// within six hours is a fast burn, and 10% within three days is a slow burn
+var SLOBurnRateWindows = []SLOBurnRateWindow{
+	{Long: time.Hour, Short: 5 * time.Minute, BudgetConsumed: 0.02, Fast: true},
+	{Long: 6 * time.Hour, Short: 30 * time.Minute, BudgetConsumed: 0.05, Fast: true},
+	{Long: 3 * 24 * time.Hour, Short: 6 * time.Hour, BudgetConsumed: 0.1, Fast: false},
+}
+
+// nginxLatencyBuckets are the default upper bounds, in seconds, of the ingress-nginx request duration histogram
+var nginxLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// SLOQueryOpts identify the requests of a web service that an SLO is measured on
+type SLOQueryOpts struct {
+	// Namespace is the namespace of the service's ingress
+	Namespace string
+	// Ingress is the name of the service's ingress
+	Ingress string
+	// Type is the kind of objective, one of types.AppSLOType_Availability or types.AppSLOType_Latency
+	Type string
+	// LatencyThreshold is the latency that requests must complete within, for latency objectives
+	LatencyThreshold time.Duration
+}
+
+// SLOEvaluationOpts are the options for evaluating an SLO
+type SLOEvaluationOpts struct {
+	SLOQueryOpts
+
+	// Target is the percentage of requests which must meet the objective, such as 99.9
+	Target float64
+	// Window is the rolling window over which the objective is measured
+	Window time.Duration
+}
+
+// SLOErrorRatioQuery returns a PromQL expression for the fraction of requests to a service which did not meet the
+// objective over the window. The expression has no result if the service received no requests.
+func SLOErrorRatioQuery(opts SLOQueryOpts, window time.Duration) (string, error) {
+	if opts.Namespace == "" || opts.Ingress == "" {
+		return "", fmt.Errorf("namespace and ingress are required")
+	}
+
+	total := nginxRateSum("nginx_ingress_controller_requests", "", opts, window)
+
+	switch opts.Type {
+	case types.AppSLOType_Availability:
+		failed := nginxRateSum("nginx_ingress_controller_requests", `status=~"5.."`, opts, window)
+		return fmt.Sprintf(`(%s OR on() vector(0)) / %s`, failed, total), nil
+	case types.AppSLOType_Latency:
+		if opts.LatencyThreshold <= 0 {
+			return "", fmt.Errorf("latency threshold must be positive for latency objectives")
+		}
+
+		withinThreshold := nginxRateSum(
+			"nginx_ingress_controller_request_duration_seconds_bucket",
+			fmt.Sprintf(`le=~"%s"`, latencyBucketMatcher(opts.LatencyThreshold)),
+			opts,
+			window,
+		)
+		count := nginxRateSum("nginx_ingress_controller_request_duration_seconds_count", "", opts, window)
+		return fmt.Sprintf(`1 - %s / %s`, withinThreshold, count), nil
+	default:
+		return "", fmt.Errorf("unsupported SLO type %s", opts.Type)
+	}
+}
+
+// nginxRateSum returns the per-second rate of an ingress-nginx metric for an ingress. We recently changed the way
+// labels are read into prometheus, which has removed the 'exported_' prepended to certain labels, so both are queried.
+func nginxRateSum(metric, matchers string, opts SLOQueryOpts, window time.Duration) string {
+	if matchers != "" {
+		matchers += ","
+	}
+
+	var queries []string
+	for _, namespaceLabel := range []string{"exported_namespace", "namespace"} {
+		queries = append(queries, fmt.Sprintf(
+			`sum(rate(%s{%s%s="%s",ingress="%s"}[%s]))`,
+			metric, matchers, namespaceLabel, opts.Namespace, opts.Ingress, promDuration(window),
+		))
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(queries, " OR "))
+}
+
+// latencyBucketMatcher returns a regex for the histogram bucket which counts requests within the threshold. Since
+// buckets have fixed bounds, the largest bucket that does not exceed the threshold is used, so that requests are never
+// counted as fast when they were not. The bound may be formatted with or without a decimal point.
+func latencyBucketMatcher(threshold time.Duration) string {
+	bound := nginxLatencyBuckets[0]
+	for _, b := range nginxLatencyBuckets {
+		if b <= threshold.Seconds() {
+			bound = b
+		}
+	}
+
+	formatted := strconv.FormatFloat(bound, 'f', -1, 64)
+	if !strings.Contains(formatted, ".") {
+		return fmt.Sprintf("%s|%s.0", formatted, formatted)
+	}
+
+	return formatted
+}
+
+// promDuration formats a duration in the largest PromQL unit which represents it exactly
+func promDuration(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+}
+
+// sloInstantQuery evaluates a PromQL expression to a single value. found is false if the expression has no result.
+type sloInstantQuery func(query string) (value float64, found bool, err error)
+
+// EvaluateSLO computes the error budget and burn rates of an SLO from the ingress metrics of its service
+func EvaluateSLO(
+	ctx context.Context,
+	clientset kubernetes.Interface,
+	service *v1.Service,
+	opts SLOEvaluationOpts,
+) (types.AppSLOStatusEntry, error) {
+	ctx, span := telemetry.NewSpan(ctx, "evaluate-slo")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "namespace", Value: opts.Namespace},
+		telemetry.AttributeKV{Key: "ingress", Value: opts.Ingress},
+		telemetry.AttributeKV{Key: "type", Value: opts.Type},
+		telemetry.AttributeKV{Key: "target", Value: opts.Target},
+		telemetry.AttributeKV{Key: "window", Value: opts.Window.String()},
+	)
+
+	if len(service.Spec.Ports) == 0 {
+		return types.AppSLOStatusEntry{}, telemetry.Error(ctx, span, nil, "prometheus service has no exposed ports to query")
+	}
+
+	query := func(query string) (float64, bool, error) {
+		return queryPrometheusInstant(ctx, clientset, service, query)
+	}
+
+	res, err := evaluateSLO(opts, query)
+	if err != nil {
+		return res, telemetry.Error(ctx, span, err, "error evaluating slo")
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "status", Value: string(res.Status)},
+		telemetry.AttributeKV{Key: "error-ratio", Value: res.ErrorRatio},
+		telemetry.AttributeKV{Key: "budget-remaining", Value: res.BudgetRemaining},
+	)
+
+	return res, nil
+}
+
+// evaluateSLO computes the error budget and burn rates of an SLO, using query to read error ratios
+func evaluateSLO(opts SLOEvaluationOpts, query sloInstantQuery) (types.AppSLOStatusEntry, error) {
+	res := types.AppSLOStatusEntry{
+		Status:          types.AppSLOStatus_NoData,
+		BudgetRemaining: 1,
+		BurnRates:       []types.AppSLOBurnRate{},
+		CheckedAt:       time.Now().UTC(),
+	}
+
+	if opts.Target <= 0 || opts.Target >= 100 {
+		return res, fmt.Errorf("target must be between 0 and 100, got %v", opts.Target)
+	}
+
+	if opts.Window <= 0 {
+		return res, fmt.Errorf("window must be positive")
+	}
+
+	budget := 1 - opts.Target/100
+
+	errorRatio := func(window time.Duration) (float64, bool, error) {
+		q, err := SLOErrorRatioQuery(opts.SLOQueryOpts, window)
+		if err != nil {
+			return 0, false, err
+		}
+
+		ratio, found, err := query(q)
+		if err != nil {
+			return 0, false, fmt.Errorf("error querying error ratio over %s: %w", promDuration(window), err)
+		}
+
+		return math.Max(0, ratio), found, nil
+	}
+
+	ratio, found, err := errorRatio(opts.Window)
+	if err != nil {
+		return res, err
+	}
+
+	if !found {
+		return res, nil
+	}
+
+	res.Status = types.AppSLOStatus_OK
+	res.ErrorRatio = ratio
+	res.BudgetRemaining = 1 - ratio/budget
+
+	var fastBurn, slowBurn bool
+
+	for _, w := range SLOBurnRateWindows {
+		// a window is only meaningful if it is shorter than the SLO window
+		if w.Long >= opts.Window {
+			continue
+		}
+
+		longRatio, _, err := errorRatio(w.Long)
+		if err != nil {
+			return res, err
+		}
+
+		shortRatio, _, err := errorRatio(w.Short)
+		if err != nil {
+			return res, err
+		}
+
+		burnRate := types.AppSLOBurnRate{
+			LongWindow:  promDuration(w.Long),
+			ShortWindow: promDuration(w.Short),
+			Threshold:   w.BudgetConsumed * float64(opts.Window) / float64(w.Long),
+			LongRate:    longRatio / budget,
+			ShortRate:   shortRatio / budget,
+		}
+		burnRate.Firing = burnRate.LongRate > burnRate.Threshold && burnRate.ShortRate > burnRate.Threshold
+
+		if burnRate.Firing && w.Fast {
+			fastBurn = true
+		} else if burnRate.Firing {
+			slowBurn = true
+		}
+
+		res.BurnRates = append(res.BurnRates, burnRate)
+	}
+
+	switch {
+	case res.BudgetRemaining <= 0:
+		res.Status = types.AppSLOStatus_Exhausted
+	case fastBurn:
+		res.Status = types.AppSLOStatus_FastBurn
+	case slowBurn:
+		res.Status = types.AppSLOStatus_SlowBurn
+	}
+
+	return res, nil
+}
+
+type promRawVectorQuery struct {
+	Data struct {
+		Result []struct {
+			Value []interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// queryPrometheusInstant evaluates a PromQL expression at the current time, and returns the first finite value
+func queryPrometheusInstant(
+	ctx context.Context,
+	clientset kubernetes.Interface,
+	service *v1.Service,
+	query string,
+) (float64, bool, error) {
+	resp := clientset.CoreV1().Services(service.Namespace).ProxyGet(
+		"http",
+		service.Name,
+		fmt.Sprintf("%d", service.Spec.Ports[0].Port),
+		"/api/v1/query",
+		map[string]string{"query": query},
+	)
+
+	rawQuery, err := resp.DoRaw(ctx)
+	if err != nil {
+		// in this case, it's very likely that prometheus doesn't contain any data for the given labels
+		if strings.Contains(err.Error(), "rejected our request for an unknown reason") {
+			return 0, false, nil
+		}
+
+		return 0, false, err
+	}
+
+	return parseVectorQuery(rawQuery)
+}
+
+// parseVectorQuery parses the result of a prometheus instant query, and returns the first finite value
+func parseVectorQuery(rawQuery []byte) (float64, bool, error) {
+	raw := &promRawVectorQuery{}
+
+	if err := json.Unmarshal(rawQuery, raw); err != nil {
+		return 0, false, err
+	}
+
+	for _, result := range raw.Data.Result {
+		if len(result.Value) != 2 {
+			return 0, false, fmt.Errorf("unexpected sample with %d values", len(result.Value))
+		}
+
+		rawValue, ok := result.Value[1].(string)
+		if !ok {
+			return 0, false, fmt.Errorf("unexpected sample value %v", result.Value[1])
+		}
+
+		value, err := strconv.ParseFloat(rawValue, 64)
+		if err != nil {
+			return 0, false, err
+		}
+
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+
+		return value, true, nil
+	}
+
+	return 0, false, nil
+}
@@ -0,0 +1,190 @@
+package prometheus
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SLOErrorRatioQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     SLOQueryOpts
+		window   time.Duration
+		expected string
+		wantErr  bool
+	}{
+		{
+			"availability",
+			SLOQueryOpts{Namespace: "app-namespace", Ingress: "app-web", Type: types.AppSLOType_Availability},
+			time.Hour,
+			`((sum(rate(nginx_ingress_controller_requests{status=~"5..",exported_namespace="app-namespace",ingress="app-web"}[1h])) OR sum(rate(nginx_ingress_controller_requests{status=~"5..",namespace="app-namespace",ingress="app-web"}[1h]))) OR on() vector(0)) / (sum(rate(nginx_ingress_controller_requests{exported_namespace="app-namespace",ingress="app-web"}[1h])) OR sum(rate(nginx_ingress_controller_requests{namespace="app-namespace",ingress="app-web"}[1h])))`,
+			false,
+		},
+		{
+			"latency threshold is rounded down to a bucket bound",
+			SLOQueryOpts{Namespace: "app-namespace", Ingress: "app-web", Type: types.AppSLOType_Latency, LatencyThreshold: 300 * time.Millisecond},
+			30 * 24 * time.Hour,
+			`1 - (sum(rate(nginx_ingress_controller_request_duration_seconds_bucket{le=~"0.25",exported_namespace="app-namespace",ingress="app-web"}[30d])) OR sum(rate(nginx_ingress_controller_request_duration_seconds_bucket{le=~"0.25",namespace="app-namespace",ingress="app-web"}[30d]))) / (sum(rate(nginx_ingress_controller_request_duration_seconds_count{exported_namespace="app-namespace",ingress="app-web"}[30d])) OR sum(rate(nginx_ingress_controller_request_duration_seconds_count{namespace="app-namespace",ingress="app-web"}[30d])))`,
+			false,
+		},
+		{
+			"latency without threshold",
+			SLOQueryOpts{Namespace: "app-namespace", Ingress: "app-web", Type: types.AppSLOType_Latency},
+			time.Hour,
+			"",
+			true,
+		},
+		{
+			"unsupported type",
+			SLOQueryOpts{Namespace: "app-namespace", Ingress: "app-web", Type: "throughput"},
+			time.Hour,
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := SLOErrorRatioQuery(tt.opts, tt.window)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.Nil(t, err, "expected nil, got %v", err)
+			assert.Equal(t, tt.expected, query, "got %s, want %s", query, tt.expected)
+		})
+	}
+}
+
+func Test_latencyBucketMatcher(t *testing.T) {
+	assert.Equal(t, "0.005", latencyBucketMatcher(time.Millisecond))
+	assert.Equal(t, "0.1", latencyBucketMatcher(100*time.Millisecond))
+	assert.Equal(t, "0.25", latencyBucketMatcher(300*time.Millisecond))
+	assert.Equal(t, "1|1.0", latencyBucketMatcher(2*time.Second))
+	assert.Equal(t, "10|10.0", latencyBucketMatcher(time.Minute))
+}
+
+func Test_promDuration(t *testing.T) {
+	assert.Equal(t, "5m", promDuration(5*time.Minute))
+	assert.Equal(t, "6h", promDuration(6*time.Hour))
+	assert.Equal(t, "3d", promDuration(72*time.Hour))
+	assert.Equal(t, "90m", promDuration(90*time.Minute))
+	assert.Equal(t, "45s", promDuration(45*time.Second))
+}
+
+func Test_evaluateSLO(t *testing.T) {
+	opts := SLOEvaluationOpts{
+		SLOQueryOpts: SLOQueryOpts{Namespace: "app-namespace", Ingress: "app-web", Type: types.AppSLOType_Availability},
+		Target:       99.9,
+		Window:       30 * 24 * time.Hour,
+	}
+
+	// errorRatios returns a query function which returns the given error ratio for each window
+	errorRatios := func(ratios map[string]float64) sloInstantQuery {
+		return func(query string) (float64, bool, error) {
+			for window, ratio := range ratios {
+				if strings.Contains(query, "["+window+"]") {
+					return ratio, true, nil
+				}
+			}
+			return 0, false, nil
+		}
+	}
+
+	tests := []struct {
+		name            string
+		ratios          map[string]float64
+		status          types.AppSLOStatus
+		budgetRemaining float64
+		firing          []bool
+	}{
+		{
+			"no requests",
+			map[string]float64{},
+			types.AppSLOStatus_NoData,
+			1,
+			nil,
+		},
+		{
+			"healthy",
+			map[string]float64{"30d": 0.0002, "1h": 0.0001, "5m": 0, "6h": 0.0001, "30m": 0.0001, "3d": 0.0002},
+			types.AppSLOStatus_OK,
+			0.8,
+			[]bool{false, false, false},
+		},
+		{
+			"fast burn on the one hour window",
+			map[string]float64{"30d": 0.0005, "1h": 0.02, "5m": 0.03, "6h": 0.004, "30m": 0.01, "3d": 0.0005},
+			types.AppSLOStatus_FastBurn,
+			0.5,
+			[]bool{true, false, false},
+		},
+		{
+			"fast burn which has stopped",
+			map[string]float64{"30d": 0.0005, "1h": 0.02, "5m": 0, "6h": 0.004, "30m": 0, "3d": 0.0005},
+			types.AppSLOStatus_OK,
+			0.5,
+			[]bool{false, false, false},
+		},
+		{
+			"slow burn",
+			map[string]float64{"30d": 0.0008, "1h": 0.002, "5m": 0.002, "6h": 0.002, "30m": 0.002, "3d": 0.002},
+			types.AppSLOStatus_SlowBurn,
+			0.2,
+			[]bool{false, false, true},
+		},
+		{
+			"exhausted",
+			map[string]float64{"30d": 0.002, "1h": 0.02, "5m": 0.03, "6h": 0.01, "30m": 0.01, "3d": 0.005},
+			types.AppSLOStatus_Exhausted,
+			-1,
+			[]bool{true, true, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := evaluateSLO(opts, errorRatios(tt.ratios))
+			assert.Nil(t, err, "expected nil, got %v", err)
+			assert.Equal(t, tt.status, res.Status)
+			assert.InDelta(t, tt.budgetRemaining, res.BudgetRemaining, 1e-9)
+
+			firing := make([]bool, 0, len(res.BurnRates))
+			for _, burnRate := range res.BurnRates {
+				firing = append(firing, burnRate.Firing)
+			}
+			if tt.firing == nil {
+				assert.Empty(t, firing)
+			} else {
+				assert.Equal(t, tt.firing, firing)
+			}
+		})
+	}
+}
+
+func Test_evaluateSLO_skipsWindowsLongerThanSLO(t *testing.T) {
+	opts := SLOEvaluationOpts{
+		SLOQueryOpts: SLOQueryOpts{Namespace: "app-namespace", Ingress: "app-web", Type: types.AppSLOType_Availability},
+		Target:       99,
+		Window:       24 * time.Hour,
+	}
+
+	res, err := evaluateSLO(opts, func(string) (float64, bool, error) { return 0, true, nil })
+	assert.Nil(t, err, "expected nil, got %v", err)
+	assert.Len(t, res.BurnRates, 2)
+	assert.InDelta(t, 0.02*24, res.BurnRates[0].Threshold, 1e-9)
+}
+
+func Test_parseVectorQuery(t *testing.T) {
+	value, found, err := parseVectorQuery([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000.5,"NaN"]},{"metric":{},"value":[1700000000.5,"0.25"]}]}}`))
+	assert.Nil(t, err, "expected nil, got %v", err)
+	assert.True(t, found)
+	assert.Equal(t, 0.25, value)
+
+	_, found, err = parseVectorQuery([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	assert.Nil(t, err, "expected nil, got %v", err)
+	assert.False(t, found)
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// AppDashboard is a saved set of metric panels for a porter app
+type AppDashboard struct {
+	gorm.Model
+
+	// ProjectID is the ID of the project that the app belongs to
+	ProjectID uint
+	// ClusterID is the ID of the cluster that the app belongs to
+	ClusterID uint
+	// PorterAppID is the ID of the app that the dashboard belongs to
+	PorterAppID uint `gorm:"index"`
+	// Name is the name of the dashboard, which is unique for the app
+	Name string
+	// Panels is the json-encoded list of types.AppDashboardPanel shown on the dashboard
+	Panels []byte
+}
+
+// ToAppDashboardType generates an external types.AppDashboard to be shared over REST
+func (d *AppDashboard) ToAppDashboardType() (types.AppDashboard, error) {
+	res := types.AppDashboard{
+		ID:        d.ID,
+		Name:      d.Name,
+		Panels:    []types.AppDashboardPanel{},
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+
+	if len(d.Panels) == 0 {
+		return res, nil
+	}
+
+	if err := json.Unmarshal(d.Panels, &res.Panels); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
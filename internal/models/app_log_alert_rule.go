@@ -0,0 +1,77 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// AppLogAlertRule is an alert on the number of log lines of a porter app which match a query within a window
+type AppLogAlertRule struct {
+	gorm.Model
+
+	// ProjectID is the ID of the project that the app belongs to
+	ProjectID uint
+	// ClusterID is the ID of the cluster that the app belongs to
+	ClusterID uint `gorm:"index"`
+	// PorterAppID is the ID of the app that the rule belongs to
+	PorterAppID uint `gorm:"index"`
+	// DeploymentTargetID is the ID of the deployment target whose logs are evaluated
+	DeploymentTargetID string
+	// Namespace is the namespace of the deployment target, which the logs of the app are read from
+	Namespace string
+	// Name is the name of the rule, which is unique for the app in the deployment target
+	Name string
+	// ServiceName is the service whose logs are evaluated, or empty for every service of the app
+	ServiceName string
+	// Query is the log query, in the syntax parsed by porter_app.ParseLogQuery
+	Query string
+	// Threshold is the number of matching lines within the window above which the rule fires
+	Threshold uint
+	// WindowMinutes is the period over which matching lines are counted
+	WindowMinutes uint
+	// Channels is the json-encoded list of Slack channel names to notify, where an empty list notifies every channel
+	Channels []byte
+
+	// LastState is the state of the rule as of its most recent evaluation
+	LastState types.AppLogAlertState
+	// LastMatchCount is the number of matching lines counted by the most recent evaluation
+	LastMatchCount uint
+	// LastCheckedAt is the time of the most recent evaluation
+	LastCheckedAt *time.Time
+	// LastFiredAt is the time the rule last sent a notification
+	LastFiredAt *time.Time
+}
+
+// ToAppLogAlertRuleType generates an external types.AppLogAlertRule to be shared over REST
+func (r *AppLogAlertRule) ToAppLogAlertRuleType() (types.AppLogAlertRule, error) {
+	res := types.AppLogAlertRule{
+		AppLogAlertRuleSpec: types.AppLogAlertRuleSpec{
+			Name:          r.Name,
+			ServiceName:   r.ServiceName,
+			Query:         r.Query,
+			Threshold:     r.Threshold,
+			WindowMinutes: r.WindowMinutes,
+		},
+		ID:                 r.ID,
+		DeploymentTargetID: r.DeploymentTargetID,
+		LastState:          r.LastState,
+		LastMatchCount:     r.LastMatchCount,
+		LastCheckedAt:      r.LastCheckedAt,
+		LastFiredAt:        r.LastFiredAt,
+		CreatedAt:          r.CreatedAt,
+		UpdatedAt:          r.UpdatedAt,
+	}
+
+	if len(r.Channels) == 0 {
+		return res, nil
+	}
+
+	if err := json.Unmarshal(r.Channels, &res.Channels); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// AppSLO is a service level objective for a web service of a porter app in a deployment target
+type AppSLO struct {
+	gorm.Model
+
+	// ProjectID is the ID of the project that the app belongs to
+	ProjectID uint
+	// ClusterID is the ID of the cluster that the app belongs to
+	ClusterID uint `gorm:"index"`
+	// PorterAppID is the ID of the app that the SLO belongs to
+	PorterAppID uint `gorm:"index"`
+	// DeploymentTargetID is the ID of the deployment target that the SLO is measured in
+	DeploymentTargetID string
+	// Namespace is the namespace of the deployment target, which the ingress metrics of the service are read from
+	Namespace string
+	// ServiceName is the name of the web service that the SLO applies to
+	ServiceName string
+	// Name is the name of the SLO, which is unique for the app in the deployment target
+	Name string
+	// Source is where the SLO was declared, one of types.AppSLOSource_API or types.AppSLOSource_PorterYAML
+	Source string
+	// Type is the kind of objective, one of types.AppSLOType_Availability or types.AppSLOType_Latency
+	Type string
+	// Target is the percentage of requests which must meet the objective over the window
+	Target float64
+	// LatencyThresholdMs is the latency that requests must complete within, for latency objectives
+	LatencyThresholdMs uint
+	// WindowDays is the rolling window over which the objective is measured
+	WindowDays uint
+
+	// LastStatus is the status of the SLO as of its most recent evaluation
+	LastStatus types.AppSLOStatus
+	// LastCheckedAt is the time of the most recent evaluation
+	LastCheckedAt *time.Time
+	// LastNotifiedStatus is the status that was last notified, so that each escalation is only notified once
+	LastNotifiedStatus types.AppSLOStatus
+}
+
+// ToAppSLOType generates an external types.AppSLO to be shared over REST. The last status is not included, since it
+// is stored separately.
+func (s *AppSLO) ToAppSLOType() types.AppSLO {
+	return types.AppSLO{
+		AppSLOSpec: types.AppSLOSpec{
+			Name:               s.Name,
+			ServiceName:        s.ServiceName,
+			Type:               s.Type,
+			Target:             s.Target,
+			LatencyThresholdMs: s.LatencyThresholdMs,
+			WindowDays:         s.WindowDays,
+		},
+		ID:                 s.ID,
+		DeploymentTargetID: s.DeploymentTargetID,
+		Source:             s.Source,
+		CreatedAt:          s.CreatedAt,
+		UpdatedAt:          s.UpdatedAt,
+	}
+}
+
+// AppSLOStatus is the result of a single evaluation of an SLO
+type AppSLOStatus struct {
+	gorm.Model
+
+	// AppSLOID is the ID of the SLO that was evaluated
+	AppSLOID uint `gorm:"index"`
+	// Status is the state of the error budget
+	Status types.AppSLOStatus
+	// ErrorRatio is the fraction of requests which did not meet the objective over the SLO window
+	ErrorRatio float64
+	// BudgetRemaining is the fraction of the error budget which has not been consumed over the SLO window
+	BudgetRemaining float64
+	// BurnRates is the json-encoded list of types.AppSLOBurnRate computed during the evaluation
+	BurnRates []byte
+	// CheckedAt is the time of the evaluation
+	CheckedAt time.Time
+}
+
+// ToAppSLOStatusEntryType generates an external types.AppSLOStatusEntry to be shared over REST
+func (s *AppSLOStatus) ToAppSLOStatusEntryType() (types.AppSLOStatusEntry, error) {
+	res := types.AppSLOStatusEntry{
+		Status:          s.Status,
+		ErrorRatio:      s.ErrorRatio,
+		BudgetRemaining: s.BudgetRemaining,
+		BurnRates:       []types.AppSLOBurnRate{},
+		CheckedAt:       s.CheckedAt,
+	}
+
+	if len(s.BurnRates) == 0 {
+		return res, nil
+	}
+
+	if err := json.Unmarshal(s.BurnRates, &res.BurnRates); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// AppSyntheticCheck is an HTTP check which is sent to a domain of a web service of a porter app from outside the cluster
+type AppSyntheticCheck struct {
+	gorm.Model
+
+	// ProjectID is the ID of the project that the app belongs to
+	ProjectID uint
+	// ClusterID is the ID of the cluster that the app belongs to
+	ClusterID uint `gorm:"index"`
+	// PorterAppID is the ID of the app that the check belongs to
+	PorterAppID uint `gorm:"index"`
+	// DeploymentTargetID is the ID of the deployment target that the checked service runs in
+	DeploymentTargetID string
+	// Namespace is the namespace of the deployment target, which is reported in the incidents of the check
+	Namespace string
+	// ServiceName is the name of the web service that the check applies to
+	ServiceName string
+	// Name is the name of the check, which is unique for the app in the deployment target
+	Name string
+
+	Domain               string
+	Path                 string
+	ExpectedStatus       int
+	BodyRegex            string
+	TLSExpiryWarningDays uint
+	IntervalSeconds      uint
+
+	// LastStatus is the status of the check as of its most recent run
+	LastStatus types.AppSyntheticCheckStatus
+	// LastCheckedAt is the time of the most recent run
+	LastCheckedAt *time.Time
+	// NextCheckAt is when the check is next due to run
+	NextCheckAt time.Time `gorm:"index"`
+	// ConsecutiveFailures is the number of runs that have failed in a row
+	ConsecutiveFailures uint
+	// IncidentOpenedAt is when the current incident of the check was notified, and is unset once it is resolved
+	IncidentOpenedAt *time.Time
+}
+
+// ToAppSyntheticCheckType generates an external types.AppSyntheticCheck to be shared over REST. The last result is not
+// included, since it is stored separately.
+func (c *AppSyntheticCheck) ToAppSyntheticCheckType() types.AppSyntheticCheck {
+	status := c.LastStatus
+	if status == "" {
+		status = types.AppSyntheticCheckStatus_Pending
+	}
+
+	return types.AppSyntheticCheck{
+		AppSyntheticCheckSpec: types.AppSyntheticCheckSpec{
+			Name:                 c.Name,
+			ServiceName:          c.ServiceName,
+			Domain:               c.Domain,
+			Path:                 c.Path,
+			ExpectedStatus:       c.ExpectedStatus,
+			BodyRegex:            c.BodyRegex,
+			TLSExpiryWarningDays: c.TLSExpiryWarningDays,
+			IntervalSeconds:      c.IntervalSeconds,
+		},
+		ID:                  c.ID,
+		DeploymentTargetID:  c.DeploymentTargetID,
+		Status:              status,
+		ConsecutiveFailures: c.ConsecutiveFailures,
+		IncidentOpenedAt:    c.IncidentOpenedAt,
+		CreatedAt:           c.CreatedAt,
+		UpdatedAt:           c.UpdatedAt,
+	}
+}
+
+// AppSyntheticCheckResult is the result of a single run of a synthetic check
+type AppSyntheticCheckResult struct {
+	gorm.Model
+
+	// AppSyntheticCheckID is the ID of the check that was run
+	AppSyntheticCheckID uint `gorm:"index"`
+	Status              types.AppSyntheticCheckStatus
+	StatusCode          int
+	LatencyMs           uint
+	Error               string
+	TLSExpiresAt        *time.Time
+	CheckedAt           time.Time
+}
+
+// ToAppSyntheticCheckResultType generates an external types.AppSyntheticCheckResult to be shared over REST
+func (r *AppSyntheticCheckResult) ToAppSyntheticCheckResultType() types.AppSyntheticCheckResult {
+	return types.AppSyntheticCheckResult{
+		Status:       r.Status,
+		StatusCode:   r.StatusCode,
+		LatencyMs:    r.LatencyMs,
+		Error:        r.Error,
+		TLSExpiresAt: r.TLSExpiresAt,
+		CheckedAt:    r.CheckedAt,
+	}
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// EnvGroupRotationPolicy rotates the value of a key of an environment group on a schedule
+type EnvGroupRotationPolicy struct {
+	gorm.Model
+
+	// ProjectID is the ID of the project that the environment group belongs to
+	ProjectID uint
+	// ClusterID is the ID of the cluster that the environment group belongs to
+	ClusterID uint `gorm:"index"`
+	// EnvGroupName is the name of the environment group
+	EnvGroupName string
+	// Key is the key of the environment group which is rotated, and is unique for the environment group
+	Key string
+	// IntervalHours is how often the key is rotated
+	IntervalHours uint
+	// HookType is how the new value is produced
+	HookType types.EnvGroupRotationHookType
+
+	// GeneratorLength is the number of characters of generated values
+	GeneratorLength uint
+	// GeneratorLower restricts generated values to lowercase letters
+	GeneratorLower bool
+
+	// JobImage is the image of the rotation job
+	JobImage string
+	// JobCommand is the json-encoded command of the rotation job
+	JobCommand []byte
+	// JobTimeoutSeconds is how long the rotation job may run
+	JobTimeoutSeconds uint
+
+	// Channels is the json-encoded list of Slack channel names notified of failures, where an empty list notifies every channel
+	Channels []byte
+
+	// NextRotationAt is when the key is next rotated
+	NextRotationAt time.Time `gorm:"index"`
+	// LastStatus is the outcome of the most recent rotation
+	LastStatus types.EnvGroupRotationStatus
+	// LastError is the error of the most recent rotation, if it failed
+	LastError string
+	// LastVersion is the environment group version created by the most recent successful rotation
+	LastVersion uint
+	// LastAttemptedAt is the time of the most recent rotation
+	LastAttemptedAt *time.Time
+	// LastRotatedAt is the time the value was last changed
+	LastRotatedAt *time.Time
+}
+
+// ToEnvGroupRotationPolicyType generates an external types.EnvGroupRotationPolicy to be shared over REST
+func (p *EnvGroupRotationPolicy) ToEnvGroupRotationPolicyType() (types.EnvGroupRotationPolicy, error) {
+	res := types.EnvGroupRotationPolicy{
+		EnvGroupRotationPolicySpec: types.EnvGroupRotationPolicySpec{
+			Key:           p.Key,
+			IntervalHours: p.IntervalHours,
+			HookType:      p.HookType,
+		},
+		ID:              p.ID,
+		EnvGroupName:    p.EnvGroupName,
+		LastStatus:      p.LastStatus,
+		LastError:       p.LastError,
+		LastVersion:     p.LastVersion,
+		LastAttemptedAt: p.LastAttemptedAt,
+		LastRotatedAt:   p.LastRotatedAt,
+		NextRotationAt:  p.NextRotationAt,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+	}
+
+	switch p.HookType {
+	case types.EnvGroupRotationHookType_Generator:
+		res.Generator = &types.EnvGroupRotationGenerator{
+			Length: p.GeneratorLength,
+			Lower:  p.GeneratorLower,
+		}
+	case types.EnvGroupRotationHookType_Job:
+		res.Job = &types.EnvGroupRotationJob{
+			Image:          p.JobImage,
+			TimeoutSeconds: p.JobTimeoutSeconds,
+		}
+
+		if len(p.JobCommand) > 0 {
+			if err := json.Unmarshal(p.JobCommand, &res.Job.Command); err != nil {
+				return res, err
+			}
+		}
+	}
+
+	if len(p.Channels) == 0 {
+		return res, nil
+	}
+
+	if err := json.Unmarshal(p.Channels, &res.Channels); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
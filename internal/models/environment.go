@@ -2,6 +2,7 @@ package models
 
 import (
 	"strings"
+	"time"
 
 	"github.com/porter-dev/porter/api/types"
 	"gorm.io/gorm"
@@ -32,6 +33,76 @@ type Environment struct {
 	WebhookID string `gorm:"unique"`
 
 	GithubWebhookID int64
+
+	// GitlabIntegrationID is set for environments which preview the merge requests of a GitLab
+	// project, in which case GitRepoOwner is the project's namespace and GitInstallationID is unset
+	GitlabIntegrationID uint
+
+	// GitlabUserID is the user whose GitLab authorization is used to manage the project webhook
+	// and the merge request notes
+	GitlabUserID uint
+
+	GitlabWebhookID int
+
+	// PreviewTTLHours overrides the TTL of the Porter instance for the deployments of this environment,
+	// counted from the last push
+	PreviewTTLHours uint
+	// SleepAfterIdleHours scales deployments to zero once their ingress has been idle for this many hours
+	SleepAfterIdleHours uint
+	// SleepScheduleEnabled scales deployments to zero between SleepStartHour and SleepEndHour, and on
+	// weekends if SleepOnWeekends is set
+	SleepScheduleEnabled bool
+	SleepStartHour       uint
+	SleepEndHour         uint
+	SleepOnWeekends      bool
+	SleepTimezone        string
+	// MaxConcurrentPreviews evicts the least recently used deployments once there are more than this many
+	MaxConcurrentPreviews uint
+}
+
+// PreviewPolicy returns the preview policy of the environment, or nil if no policy is set
+func (e *Environment) PreviewPolicy() *types.PreviewEnvironmentPolicy {
+	policy := &types.PreviewEnvironmentPolicy{
+		TTLHours:              e.PreviewTTLHours,
+		SleepAfterIdleHours:   e.SleepAfterIdleHours,
+		MaxConcurrentPreviews: e.MaxConcurrentPreviews,
+	}
+
+	if e.SleepScheduleEnabled {
+		policy.SleepSchedule = &types.PreviewSleepSchedule{
+			StartHour: e.SleepStartHour,
+			EndHour:   e.SleepEndHour,
+			Weekends:  e.SleepOnWeekends,
+			Timezone:  e.SleepTimezone,
+		}
+	}
+
+	if policy.TTLHours == 0 && policy.SleepAfterIdleHours == 0 && policy.SleepSchedule == nil && policy.MaxConcurrentPreviews == 0 {
+		return nil
+	}
+
+	return policy
+}
+
+// SetPreviewPolicy replaces the preview policy of the environment. A nil policy removes it.
+func (e *Environment) SetPreviewPolicy(policy *types.PreviewEnvironmentPolicy) {
+	if policy == nil {
+		policy = &types.PreviewEnvironmentPolicy{}
+	}
+
+	e.PreviewTTLHours = policy.TTLHours
+	e.SleepAfterIdleHours = policy.SleepAfterIdleHours
+	e.MaxConcurrentPreviews = policy.MaxConcurrentPreviews
+
+	e.SleepScheduleEnabled = policy.SleepSchedule != nil
+	e.SleepStartHour, e.SleepEndHour, e.SleepOnWeekends, e.SleepTimezone = 0, 0, false, ""
+
+	if policy.SleepSchedule != nil {
+		e.SleepStartHour = policy.SleepSchedule.StartHour
+		e.SleepEndHour = policy.SleepSchedule.EndHour
+		e.SleepOnWeekends = policy.SleepSchedule.Weekends
+		e.SleepTimezone = policy.SleepSchedule.Timezone
+	}
 }
 
 func getGitRepoBranches(branches string) []string {
@@ -54,12 +125,13 @@ func getGitRepoBranches(branches string) []string {
 
 func (e *Environment) ToEnvironmentType() *types.Environment {
 	env := &types.Environment{
-		ID:                e.Model.ID,
-		ProjectID:         e.ProjectID,
-		ClusterID:         e.ClusterID,
-		GitInstallationID: e.GitInstallationID,
-		GitRepoOwner:      e.GitRepoOwner,
-		GitRepoName:       e.GitRepoName,
+		ID:                  e.Model.ID,
+		ProjectID:           e.ProjectID,
+		ClusterID:           e.ClusterID,
+		GitInstallationID:   e.GitInstallationID,
+		GitlabIntegrationID: e.GitlabIntegrationID,
+		GitRepoOwner:        e.GitRepoOwner,
+		GitRepoName:         e.GitRepoName,
 
 		NewCommentsDisabled: e.NewCommentsDisabled,
 		NamespaceLabels:     make(map[string]string),
@@ -97,6 +169,8 @@ func (e *Environment) ToEnvironmentType() *types.Environment {
 		}
 	}
 
+	env.Policy = e.PreviewPolicy()
+
 	return env
 }
 
@@ -110,6 +184,7 @@ type Deployment struct {
 	PullRequestID  uint
 	GHDeploymentID int64
 	GHPRCommentID  int64
+	GitlabMRNoteID int
 	PRName         string
 	RepoName       string
 	RepoOwner      string
@@ -117,6 +192,18 @@ type Deployment struct {
 	PRBranchFrom   string
 	PRBranchInto   string
 	LastErrors     string
+
+	// LastPushedAt is when a commit was last deployed, from which the TTL of the deployment is counted
+	LastPushedAt *time.Time
+	// LastWokenAt is when the deployment was last woken up by a request after sleeping
+	LastWokenAt *time.Time
+	// SleepingSince is set while the deployment is scaled to zero by the sleep policies of its environment
+	SleepingSince *time.Time
+	// SleepReason is why the deployment was put to sleep, either idle or schedule
+	SleepReason string
+	// SleepingHosts is a comma-separated list of the ingress hosts which are routed to the preview waker while
+	// the deployment is asleep or waking up
+	SleepingHosts string
 }
 
 func (d *Deployment) ToDeploymentType() *types.Deployment {
@@ -141,9 +228,31 @@ func (d *Deployment) ToDeploymentType() *types.Deployment {
 		PullRequestID:  d.PullRequestID,
 		GitHubMetadata: ghMetadata,
 		LastErrors:     d.LastErrors,
+		LastPushedAt:   d.LastPushedAt,
+		SleepingSince:  d.SleepingSince,
+		SleepReason:    d.SleepReason,
 	}
 }
 
+// MarkPushed records that a commit was deployed, which restarts the TTL of the deployment. A sleeping deployment is
+// scaled back up by the deploy, so it is no longer considered asleep.
+func (d *Deployment) MarkPushed(now time.Time) {
+	d.LastPushedAt = &now
+	d.SleepingSince = nil
+	d.SleepReason = ""
+}
+
+// HasSleepingHost returns true if requests to the host are routed to the preview waker
+func (d *Deployment) HasSleepingHost(host string) bool {
+	for _, h := range strings.Split(d.SleepingHosts, ",") {
+		if h != "" && strings.EqualFold(h, host) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (d *Deployment) IsBranchDeploy() bool {
 	return d.PullRequestID == 0 && d.PRBranchFrom != "" && d.PRBranchInto != "" && d.PRBranchFrom == d.PRBranchInto
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -66,6 +67,13 @@ type Infra struct {
 
 	Database Database
 
+	// The JSON-encoded list of resources which drifted from the last-applied configuration,
+	// as of the last drift check
+	DriftedResources []byte
+
+	// The time that the last drift check completed
+	DriftCheckedAt *time.Time
+
 	// ------------------------------------------------------------------
 	// All fields below this line are encrypted before storage
 	// ------------------------------------------------------------------
@@ -85,6 +93,16 @@ type Operation struct {
 	Error           string
 	TemplateVersion string
 
+	// The JSON-encoded terraform plan for this operation, set once the plan has completed
+	Plan []byte
+
+	// The time that the plan was approved, after which the operation is applied
+	ApprovedAt *time.Time
+
+	// The checksum of the terraform state that the saved plan was created against. The plan is stale, and
+	// cannot be approved, once the state no longer matches.
+	PlanStateChecksum string
+
 	// ------------------------------------------------------------------
 	// All fields below this line are encrypted before storage
 	// ------------------------------------------------------------------
@@ -102,7 +120,25 @@ func (o *Operation) ToOperationMetaType() *types.OperationMeta {
 		Status:      o.Status,
 		Errored:     o.Errored,
 		Error:       o.Error,
+		Plan:        o.GetPlan(),
+		ApprovedAt:  o.ApprovedAt,
+	}
+}
+
+// GetPlan returns the decoded terraform plan for the operation, or nil if the operation
+// has not been planned
+func (o *Operation) GetPlan() *types.TerraformPlan {
+	if len(o.Plan) == 0 {
+		return nil
+	}
+
+	plan := &types.TerraformPlan{}
+
+	if err := json.Unmarshal(o.Plan, plan); err != nil {
+		return nil
 	}
+
+	return plan
 }
 
 func (o *Operation) ToOperationType() (*types.Operation, error) {
@@ -184,7 +220,31 @@ func (i *Infra) ToInfraType() *types.Infra {
 		AWSIntegrationID: i.AWSIntegrationID,
 		DOIntegrationID:  i.DOIntegrationID,
 		GCPIntegrationID: i.GCPIntegrationID,
+		Drift:            i.GetDrift(),
+	}
+}
+
+// GetDrift returns the result of the last drift check for the infra, or nil if the infra
+// has not been checked for drift
+func (i *Infra) GetDrift() *types.InfraDrift {
+	if i.DriftCheckedAt == nil {
+		return nil
+	}
+
+	drift := &types.InfraDrift{
+		CheckedAt: *i.DriftCheckedAt,
+		Resources: make([]types.TerraformDriftedResource, 0),
 	}
+
+	if len(i.DriftedResources) > 0 {
+		if err := json.Unmarshal(i.DriftedResources, &drift.Resources); err != nil {
+			return nil
+		}
+	}
+
+	drift.Drifted = len(drift.Resources) > 0
+
+	return drift
 }
 
 // GetID returns the unique id for this infra
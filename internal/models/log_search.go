@@ -0,0 +1,32 @@
+package models
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// LogSearch is a log search query saved for reuse across a project
+type LogSearch struct {
+	gorm.Model
+
+	// ProjectID is the ID of the project that the search belongs to
+	ProjectID uint `gorm:"index"`
+	// Name is the name of the search, which is unique for the project
+	Name string
+	// Query is the log query, in the syntax parsed by porter_app.ParseLogQuery
+	Query string
+	// AppName is the name of the app the search was saved for, or empty if the search applies to any app
+	AppName string
+}
+
+// ToLogSearchType generates an external types.LogSearch to be shared over REST
+func (s *LogSearch) ToLogSearchType() types.LogSearch {
+	return types.LogSearch{
+		ID:        s.ID,
+		Name:      s.Name,
+		Query:     s.Query,
+		AppName:   s.AppName,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// UserTOTP stores a user's TOTP enrollment for multi-factor authentication
+type UserTOTP struct {
+	gorm.Model
+
+	UserID uint `gorm:"unique"`
+
+	// Secret is the base32-encoded TOTP secret, encrypted before storage
+	Secret []byte
+
+	// Enabled is set once the user has confirmed the enrollment with a valid code
+	Enabled bool
+
+	// LastUsedStep is the last TOTP time step that was accepted, used to prevent code replay
+	LastUsedStep int64
+}
+
+// MFARecoveryCode is a single-use code that can be used in place of a second factor
+type MFARecoveryCode struct {
+	gorm.Model
+
+	UserID uint `gorm:"index"`
+
+	// CodeHash is the sha256 hash of the recovery code
+	CodeHash string `gorm:"index"`
+
+	UsedAt *time.Time
+}
+
+// WebAuthnCredential is a security key or platform authenticator registered by a user
+type WebAuthnCredential struct {
+	gorm.Model
+
+	UserID uint `gorm:"index"`
+
+	Name string
+
+	// CredentialID is the base64url-encoded credential id assigned by the authenticator
+	CredentialID string `gorm:"unique"`
+
+	// PublicKey is the DER-encoded SubjectPublicKeyInfo of the credential
+	PublicKey []byte
+
+	SignCount  uint32
+	LastUsedAt *time.Time
+}
+
+// ToWebAuthnCredentialType generates an external types.WebAuthnCredential to be shared over REST
+func (c *WebAuthnCredential) ToWebAuthnCredentialType() types.WebAuthnCredential {
+	return types.WebAuthnCredential{
+		ID:         c.ID,
+		Name:       c.Name,
+		CreatedAt:  c.CreatedAt,
+		LastUsedAt: c.LastUsedAt,
+	}
+}
@@ -223,6 +223,10 @@ type Project struct {
 	AdvancedInfraEnabled bool `gorm:"default:false"`
 	AdvancedRbacEnabled  bool `gorm:"default:false"`
 
+	// RequireMFA blocks collaborators that do not have multi-factor authentication enabled
+	// from accessing the project
+	RequireMFA bool `gorm:"default:false"`
+
 	// ReferralCode is a unique code that can be shared to referr other users to Porter
 	ReferralCode string
 
@@ -334,6 +338,7 @@ func (p *Project) ToProjectType(launchDarklyClient *features.Client) types.Proje
 		SandboxEnabled:                  p.EnableSandbox,
 		AdvancedRbacEnabled:             p.GetFeatureFlag(AdvancedRbacEnabled, launchDarklyClient),
 		ReferralCode:                    p.ReferralCode,
+		RequireMFA:                      p.RequireMFA,
 	}
 }
 
@@ -0,0 +1,15 @@
+package models
+
+import "gorm.io/gorm"
+
+// ProvisionerFile is a file stored by the provisioner's postgres storage backend, such as
+// the terraform state or operation logs for an infra
+type ProvisionerFile struct {
+	gorm.Model
+
+	// Key is the infra's unique name followed by the file name
+	Key string `gorm:"unique"`
+
+	// Data is the file contents, which are encrypted for state files
+	Data []byte
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// StatusPage is the status page of a project, which reports the status and uptime of the components that the project
+// chooses to expose
+type StatusPage struct {
+	gorm.Model
+
+	// ProjectID is the ID of the project that the status page belongs to. Each project has at most one status page.
+	ProjectID uint `gorm:"uniqueIndex"`
+	// Slug identifies the status page in its public URL, and is unique across projects
+	Slug  string `gorm:"uniqueIndex"`
+	Title string
+	// Public pages can be viewed without logging in to Porter
+	Public bool
+}
+
+// StatusPageComponent is a component of a status page, along with the state of its synthetic HTTP check
+type StatusPageComponent struct {
+	gorm.Model
+
+	StatusPageID uint `gorm:"index"`
+	// Position orders the components on the page
+	Position  int
+	Name      string
+	Type      types.StatusPageComponentType
+	ClusterID uint
+	AppName   string
+	URL       string
+
+	// LastCheckedAt is when the URL was last checked
+	LastCheckedAt *time.Time
+	// LastLatencyMs is the latency of the last check
+	LastLatencyMs uint
+	// LastCheckError is the reason that the last check failed, if it did
+	LastCheckError string
+	// ConsecutiveFailures is the number of checks that have failed in a row
+	ConsecutiveFailures uint
+}
+
+// ToStatusPageComponentType generates an external types.StatusPageComponent to be shared over REST
+func (c *StatusPageComponent) ToStatusPageComponentType() types.StatusPageComponent {
+	return types.StatusPageComponent{
+		ID:        c.ID,
+		Name:      c.Name,
+		Type:      c.Type,
+		ClusterID: c.ClusterID,
+		AppName:   c.AppName,
+		URL:       c.URL,
+	}
+}
+
+// ToStatusPageType generates an external types.StatusPage to be shared over REST
+func (p *StatusPage) ToStatusPageType(components []*StatusPageComponent) types.StatusPage {
+	res := types.StatusPage{
+		Slug:       p.Slug,
+		Title:      p.Title,
+		Public:     p.Public,
+		Components: make([]types.StatusPageComponent, 0, len(components)),
+	}
+
+	for _, component := range components {
+		res.Components = append(res.Components, component.ToStatusPageComponentType())
+	}
+
+	return res
+}
+
+// StatusPageIncident is an incident posted on a status page. Incidents with a failure severity are the windows of
+// downtime from which the uptime of components is computed.
+type StatusPageIncident struct {
+	gorm.Model
+
+	StatusPageID uint `gorm:"index"`
+	// ComponentID is the affected component, or zero if the incident affects the whole page
+	ComponentID uint `gorm:"index"`
+	Title       string
+	Message     string
+	Status      types.StatusPageIncidentStatus
+	Severity    types.ServiceStatus
+	// Automatic is set on incidents which were opened by the synthetic HTTP checker
+	Automatic  bool
+	StartedAt  time.Time
+	ResolvedAt *time.Time
+}
+
+// ToStatusPageIncidentType generates an external types.StatusPageIncident to be shared over REST
+func (i *StatusPageIncident) ToStatusPageIncidentType() types.StatusPageIncident {
+	return types.StatusPageIncident{
+		ID:          i.ID,
+		ComponentID: i.ComponentID,
+		Title:       i.Title,
+		Message:     i.Message,
+		Status:      i.Status,
+		Severity:    i.Severity,
+		Automatic:   i.Automatic,
+		StartedAt:   i.StartedAt,
+		UpdatedAt:   i.UpdatedAt,
+		ResolvedAt:  i.ResolvedAt,
+	}
+}
@@ -0,0 +1,26 @@
+package notifier
+
+import "github.com/porter-dev/porter/api/types"
+
+// AppLogAlertNotifier sends a notification when more lines than the threshold of a log alert rule match its query
+type AppLogAlertNotifier interface {
+	NotifyLogAlert(notification *types.AppLogAlertNotification, url string) error
+}
+
+type MultiAppLogAlertNotifier struct {
+	notifiers []AppLogAlertNotifier
+}
+
+func NewMultiAppLogAlertNotifier(notifiers ...AppLogAlertNotifier) AppLogAlertNotifier {
+	return &MultiAppLogAlertNotifier{notifiers}
+}
+
+func (m *MultiAppLogAlertNotifier) NotifyLogAlert(notification *types.AppLogAlertNotification, url string) error {
+	for _, n := range m.notifiers {
+		if err := n.NotifyLogAlert(notification, url); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
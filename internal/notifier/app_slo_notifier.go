@@ -0,0 +1,27 @@
+package notifier
+
+import "github.com/porter-dev/porter/api/types"
+
+// AppSLONotifier sends a notification when the error budget of an app's SLO is burning
+// faster than it can sustain, or has been exhausted
+type AppSLONotifier interface {
+	NotifySLOBurn(appName string, slo *types.AppSLO, url string) error
+}
+
+type MultiAppSLONotifier struct {
+	notifiers []AppSLONotifier
+}
+
+func NewMultiAppSLONotifier(notifiers ...AppSLONotifier) AppSLONotifier {
+	return &MultiAppSLONotifier{notifiers}
+}
+
+func (m *MultiAppSLONotifier) NotifySLOBurn(appName string, slo *types.AppSLO, url string) error {
+	for _, n := range m.notifiers {
+		if err := n.NotifySLOBurn(appName, slo, url); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package notifier
+
+import "github.com/porter-dev/porter/api/types"
+
+// EnvGroupRotationNotifier sends a notification when a key of an environment group cannot be rotated
+type EnvGroupRotationNotifier interface {
+	NotifyRotationFailure(notification *types.EnvGroupRotationFailureNotification, url string) error
+}
+
+type MultiEnvGroupRotationNotifier struct {
+	notifiers []EnvGroupRotationNotifier
+}
+
+func NewMultiEnvGroupRotationNotifier(notifiers ...EnvGroupRotationNotifier) EnvGroupRotationNotifier {
+	return &MultiEnvGroupRotationNotifier{notifiers}
+}
+
+func (m *MultiEnvGroupRotationNotifier) NotifyRotationFailure(notification *types.EnvGroupRotationFailureNotification, url string) error {
+	for _, n := range m.notifiers {
+		if err := n.NotifyRotationFailure(notification, url); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package notifier
+
+import "github.com/porter-dev/porter/api/types"
+
+// InfraDriftNotifier sends a notification when the resources for an infra were changed
+// outside of Porter
+type InfraDriftNotifier interface {
+	NotifyDrift(infra *types.Infra, drift *types.InfraDrift, url string) error
+}
+
+type MultiInfraDriftNotifier struct {
+	notifiers []InfraDriftNotifier
+}
+
+func NewMultiInfraDriftNotifier(notifiers ...InfraDriftNotifier) InfraDriftNotifier {
+	return &MultiInfraDriftNotifier{notifiers}
+}
+
+func (m *MultiInfraDriftNotifier) NotifyDrift(infra *types.Infra, drift *types.InfraDrift, url string) error {
+	for _, n := range m.notifiers {
+		if err := n.NotifyDrift(infra, drift, url); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
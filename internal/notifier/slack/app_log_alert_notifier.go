@@ -0,0 +1,90 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models/integrations"
+)
+
+type AppLogAlertNotifier struct {
+	slackInts []*integrations.SlackIntegration
+}
+
+func NewAppLogAlertNotifier(slackInts ...*integrations.SlackIntegration) *AppLogAlertNotifier {
+	return &AppLogAlertNotifier{
+		slackInts: slackInts,
+	}
+}
+
+func (s *AppLogAlertNotifier) NotifyLogAlert(notification *types.AppLogAlertNotification, url string) error {
+	rule := notification.Rule
+
+	topSectionMarkdwn := fmt.Sprintf(
+		":rotating_light: The log alert %s of your application %s is firing: more than %d %s matched in the last %d %s. <%s|View the logs.>",
+		"`"+rule.Name+"`",
+		"`"+notification.AppName+"`",
+		rule.Threshold,
+		pluralize(int(rule.Threshold), "line", "lines"),
+		rule.WindowMinutes,
+		pluralize(int(rule.WindowMinutes), "minute", "minutes"),
+		url,
+	)
+
+	service := "all services"
+	if rule.ServiceName != "" {
+		service = "`" + rule.ServiceName + "`"
+	}
+
+	res := []*SlackBlock{
+		getMarkdownBlock(topSectionMarkdwn),
+		getDividerBlock(),
+		getMarkdownBlock(fmt.Sprintf("*Service:* %s", service)),
+		getMarkdownBlock(fmt.Sprintf("*Query:* %s", "`"+rule.Query+"`")),
+		getMarkdownBlock(fmt.Sprintf(
+			"*Fired at:* <!date^%d^ {date_num} {time_secs}| %s>",
+			notification.FiredAt.Unix(),
+			notification.FiredAt.Format("2006-01-02 15:04:05 UTC"),
+		)),
+	}
+
+	if len(notification.SampleLines) > 0 {
+		// sample lines are shown in a code block, which a line containing a fence would end early
+		lines := make([]string, 0, len(notification.SampleLines))
+		for _, line := range notification.SampleLines {
+			lines = append(lines, strings.ReplaceAll(line, "```", "'''"))
+		}
+
+		res = append(res,
+			getMarkdownBlock("*Most recent matching lines:*"),
+			getMarkdownBlock(fmt.Sprintf("```\n%s\n```", strings.Join(lines, "\n"))),
+		)
+	}
+
+	slackPayload := &SlackPayload{
+		Blocks: res,
+	}
+
+	payload, err := json.Marshal(slackPayload)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{
+		Timeout: time.Second * 5,
+	}
+
+	for _, slackInt := range s.slackInts {
+		_, err := client.Post(string(slackInt.Webhook), "application/json", bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
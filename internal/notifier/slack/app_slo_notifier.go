@@ -0,0 +1,114 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models/integrations"
+)
+
+type AppSLONotifier struct {
+	slackInts []*integrations.SlackIntegration
+}
+
+func NewAppSLONotifier(slackInts ...*integrations.SlackIntegration) *AppSLONotifier {
+	return &AppSLONotifier{
+		slackInts: slackInts,
+	}
+}
+
+func (s *AppSLONotifier) NotifySLOBurn(appName string, slo *types.AppSLO, url string) error {
+	status := slo.LastStatus
+	if status == nil {
+		return fmt.Errorf("slo %s has not been evaluated", slo.Name)
+	}
+
+	var summary string
+
+	switch status.Status {
+	case types.AppSLOStatus_Exhausted:
+		summary = "has exhausted its error budget"
+	case types.AppSLOStatus_FastBurn:
+		summary = "is burning its error budget fast"
+	case types.AppSLOStatus_SlowBurn:
+		summary = "is burning its error budget faster than it can sustain"
+	default:
+		return nil
+	}
+
+	topSectionMarkdwn := fmt.Sprintf(
+		":rotating_light: The SLO %s of your application %s %s. <%s|View the application.>",
+		"`"+slo.Name+"`",
+		"`"+appName+"`",
+		summary,
+		url,
+	)
+
+	objective := fmt.Sprintf("%g%% of requests without a 5xx status", slo.Target)
+	if slo.Type == types.AppSLOType_Latency {
+		objective = fmt.Sprintf("%g%% of requests within %dms", slo.Target, slo.LatencyThresholdMs)
+	}
+
+	burnRates := make([]string, 0, len(status.BurnRates))
+
+	for _, burnRate := range status.BurnRates {
+		firing := ""
+		if burnRate.Firing {
+			firing = " (firing)"
+		}
+
+		burnRates = append(burnRates, fmt.Sprintf(
+			"%s/%s: %.1fx/%.1fx, threshold %.1fx%s",
+			burnRate.LongWindow,
+			burnRate.ShortWindow,
+			burnRate.LongRate,
+			burnRate.ShortRate,
+			burnRate.Threshold,
+			firing,
+		))
+	}
+
+	res := []*SlackBlock{
+		getMarkdownBlock(topSectionMarkdwn),
+		getDividerBlock(),
+		getMarkdownBlock(fmt.Sprintf("*Service:* %s", "`"+slo.ServiceName+"`")),
+		getMarkdownBlock(fmt.Sprintf("*Objective:* %s over %d %s", objective, slo.WindowDays, pluralize(int(slo.WindowDays), "day", "days"))),
+		getMarkdownBlock(fmt.Sprintf("*Error budget remaining:* %.1f%%", status.BudgetRemaining*100)),
+		getMarkdownBlock(fmt.Sprintf(
+			"*Checked at:* <!date^%d^ {date_num} {time_secs}| %s>",
+			status.CheckedAt.Unix(),
+			status.CheckedAt.Format("2006-01-02 15:04:05 UTC"),
+		)),
+	}
+
+	if len(burnRates) > 0 {
+		res = append(res, getMarkdownBlock(fmt.Sprintf("```\n%s\n```", strings.Join(burnRates, "\n"))))
+	}
+
+	slackPayload := &SlackPayload{
+		Blocks: res,
+	}
+
+	payload, err := json.Marshal(slackPayload)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{
+		Timeout: time.Second * 5,
+	}
+
+	for _, slackInt := range s.slackInts {
+		_, err := client.Post(string(slackInt.Webhook), "application/json", bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
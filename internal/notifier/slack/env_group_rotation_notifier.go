@@ -0,0 +1,85 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models/integrations"
+)
+
+type EnvGroupRotationNotifier struct {
+	slackInts []*integrations.SlackIntegration
+}
+
+func NewEnvGroupRotationNotifier(slackInts ...*integrations.SlackIntegration) *EnvGroupRotationNotifier {
+	return &EnvGroupRotationNotifier{
+		slackInts: slackInts,
+	}
+}
+
+func (s *EnvGroupRotationNotifier) NotifyRotationFailure(notification *types.EnvGroupRotationFailureNotification, url string) error {
+	policy := notification.Policy
+
+	topSectionMarkdwn := fmt.Sprintf(
+		":warning: The key %s of the environment group %s in cluster %s could not be rotated. <%s|View the environment group.>",
+		"`"+policy.Key+"`",
+		"`"+notification.EnvGroupName+"`",
+		"`"+notification.ClusterName+"`",
+		url,
+	)
+
+	hook := "random string generator"
+	if policy.HookType == types.EnvGroupRotationHookType_Job && policy.Job != nil {
+		hook = fmt.Sprintf("job running %s", "`"+policy.Job.Image+"`")
+	}
+
+	lastRotated := "never"
+	if policy.LastRotatedAt != nil {
+		lastRotated = fmt.Sprintf(
+			"<!date^%d^ {date_num} {time_secs}| %s>",
+			policy.LastRotatedAt.Unix(),
+			policy.LastRotatedAt.Format("2006-01-02 15:04:05 UTC"),
+		)
+	}
+
+	res := []*SlackBlock{
+		getMarkdownBlock(topSectionMarkdwn),
+		getDividerBlock(),
+		getMarkdownBlock(fmt.Sprintf("*Rotation hook:* %s", hook)),
+		getMarkdownBlock(fmt.Sprintf("*Last rotated:* %s", lastRotated)),
+		getMarkdownBlock(fmt.Sprintf(
+			"*Failed at:* <!date^%d^ {date_num} {time_secs}| %s>",
+			notification.FailedAt.Unix(),
+			notification.FailedAt.Format("2006-01-02 15:04:05 UTC"),
+		)),
+		// the error is shown in a code block, which a fence would end early
+		getMarkdownBlock(fmt.Sprintf("*Error:*\n```\n%s\n```", strings.ReplaceAll(notification.Error, "```", "'''"))),
+	}
+
+	slackPayload := &SlackPayload{
+		Blocks: res,
+	}
+
+	payload, err := json.Marshal(slackPayload)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{
+		Timeout: time.Second * 5,
+	}
+
+	for _, slackInt := range s.slackInts {
+		_, err := client.Post(string(slackInt.Webhook), "application/json", bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models/integrations"
+)
+
+type InfraDriftNotifier struct {
+	slackInts []*integrations.SlackIntegration
+}
+
+func NewInfraDriftNotifier(slackInts ...*integrations.SlackIntegration) *InfraDriftNotifier {
+	return &InfraDriftNotifier{
+		slackInts: slackInts,
+	}
+}
+
+func (s *InfraDriftNotifier) NotifyDrift(infra *types.Infra, drift *types.InfraDrift, url string) error {
+	res := []*SlackBlock{}
+
+	topSectionMarkdwn := fmt.Sprintf(
+		":warning: %d %s in your %s infrastructure %s changed outside of Porter. <%s|Review and reconcile the drift.>",
+		len(drift.Resources),
+		pluralize(len(drift.Resources), "resource", "resources"),
+		"`"+string(infra.Kind)+"`",
+		pluralize(len(drift.Resources), "was", "were"),
+		url,
+	)
+
+	resources := make([]string, 0, len(drift.Resources))
+
+	for _, resource := range drift.Resources {
+		resources = append(resources, fmt.Sprintf("%s (%s)", resource.Address, resource.Action))
+	}
+
+	res = append(
+		res,
+		getMarkdownBlock(topSectionMarkdwn),
+		getDividerBlock(),
+		getMarkdownBlock(fmt.Sprintf("*Name:* %s", "`"+infra.Name+"`")),
+		getMarkdownBlock(fmt.Sprintf(
+			"*Checked at:* <!date^%d^ {date_num} {time_secs}| %s>",
+			drift.CheckedAt.Unix(),
+			drift.CheckedAt.Format("2006-01-02 15:04:05 UTC"),
+		)),
+		getMarkdownBlock(fmt.Sprintf("```\n%s\n```", strings.Join(resources, "\n"))),
+	)
+
+	slackPayload := &SlackPayload{
+		Blocks: res,
+	}
+
+	payload, err := json.Marshal(slackPayload)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{
+		Timeout: time.Second * 5,
+	}
+
+	for _, slackInt := range s.slackInts {
+		_, err := client.Post(string(slackInt.Webhook), "application/json", bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func pluralize(count int, singular, plural string) string {
+	if count == 1 {
+		return singular
+	}
+
+	return plural
+}
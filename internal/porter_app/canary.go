@@ -0,0 +1,510 @@
+package porter_app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/telemetry"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+const (
+	// LabelKey_Canary is the label key set on every resource of a canary, so that canaries are never mistaken for
+	// the stable resources of a service
+	LabelKey_Canary = "porter.run/canary"
+
+	// canarySuffix is appended to the names of the stable resources, and to the values of the stable service selector
+	canarySuffix = "-canary"
+
+	nginxCanaryAnnotation       = "nginx.ingress.kubernetes.io/canary"
+	nginxCanaryWeightAnnotation = "nginx.ingress.kubernetes.io/canary-weight"
+	ingressClassAnnotation      = "kubernetes.io/ingress.class"
+
+	// maxLabelValueLength is the maximum length of a kubernetes label value
+	maxLabelValueLength = 63
+
+	// canaryResourcesMetadataKey is the key of the canary resources in the metadata of a CANARY app event
+	canaryResourcesMetadataKey = "resources"
+
+	// CanaryHeartbeatInterval is how often a running canary updates its app event, even while it waits for a step
+	CanaryHeartbeatInterval = time.Minute
+	// CanaryOrphanedAfter is how long a progressing canary can go without updating its app event before it is
+	// considered orphaned, e.g. because the process running it was restarted, and is rolled back
+	CanaryOrphanedAfter = 5 * CanaryHeartbeatInterval
+)
+
+// ErrNoStableDeployment is returned by CreateCanary when a service has not been deployed yet, so there is nothing to
+// compare a canary against
+var ErrNoStableDeployment = errors.New("service has no running deployment")
+
+// CanaryResources are the kubernetes resources which run a canary of a web service alongside its stable deployment
+type CanaryResources struct {
+	Namespace string `json:"namespace"`
+	// DeploymentName is the name of the canary deployment
+	DeploymentName string `json:"deployment_name"`
+	// ServiceName is the name of the kubernetes service which selects the canary pods
+	ServiceName string `json:"service_name"`
+	// IngressName is the name of the nginx canary ingress which splits traffic to the canary
+	IngressName string `json:"ingress_name"`
+	// StableServiceName is the name of the kubernetes service which selects the stable pods
+	StableServiceName string `json:"stable_service_name"`
+	// StableIngressName is the name of the ingress which routes traffic to the stable pods
+	StableIngressName string `json:"stable_ingress_name"`
+}
+
+// CanaryEventMetadata encodes a canary and its resources as the metadata of its CANARY app event. The resources are
+// stored with the canary so that they can still be deleted if the process running the canary stops.
+func CanaryEventMetadata(canary types.AppCanary, resources CanaryResources) (map[string]any, error) {
+	metadata := make(map[string]any)
+	if err := remarshal(canary, &metadata); err != nil {
+		return nil, fmt.Errorf("error encoding canary: %w", err)
+	}
+
+	encodedResources := make(map[string]any)
+	if err := remarshal(resources, &encodedResources); err != nil {
+		return nil, fmt.Errorf("error encoding canary resources: %w", err)
+	}
+	metadata[canaryResourcesMetadataKey] = encodedResources
+
+	return metadata, nil
+}
+
+// CanaryFromEventMetadata decodes a canary and its resources from the metadata of its CANARY app event
+func CanaryFromEventMetadata(metadata map[string]any) (types.AppCanary, CanaryResources, error) {
+	canary := types.AppCanary{}
+	resources := CanaryResources{}
+
+	if err := remarshal(metadata, &canary); err != nil {
+		return canary, resources, fmt.Errorf("error decoding canary: %w", err)
+	}
+
+	if encodedResources, ok := metadata[canaryResourcesMetadataKey]; ok {
+		if err := remarshal(encodedResources, &resources); err != nil {
+			return canary, resources, fmt.Errorf("error decoding canary resources: %w", err)
+		}
+	}
+
+	return canary, resources, nil
+}
+
+// remarshal converts between two representations of the same JSON value
+func remarshal(from any, to any) error {
+	by, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(by, to)
+}
+
+// CreateCanaryInput is the input to CreateCanary
+type CreateCanaryInput struct {
+	Namespace          string
+	AppName            string
+	ServiceName        string
+	DeploymentTargetID string
+	// ImageRepository and ImageTag replace the image of the stable deployment in the canary
+	ImageRepository string
+	ImageTag        string
+}
+
+// CreateCanary creates a single replica deployment of a web service which runs a new image, along with a kubernetes
+// service which selects its pods. No traffic is sent to the canary until SetCanaryWeight is called. Canary resources
+// left over from an earlier canary of the same service are replaced.
+func CreateCanary(ctx context.Context, clientset k8s.Interface, inp CreateCanaryInput) (CanaryResources, error) {
+	ctx, span := telemetry.NewSpan(ctx, "create-canary")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "namespace", Value: inp.Namespace},
+		telemetry.AttributeKV{Key: "app-name", Value: inp.AppName},
+		telemetry.AttributeKV{Key: "service-name", Value: inp.ServiceName},
+		telemetry.AttributeKV{Key: "image-repository", Value: inp.ImageRepository},
+		telemetry.AttributeKV{Key: "image-tag", Value: inp.ImageTag},
+	)
+
+	res := CanaryResources{Namespace: inp.Namespace}
+
+	if inp.Namespace == "" || inp.AppName == "" || inp.ServiceName == "" || inp.DeploymentTargetID == "" {
+		return res, telemetry.Error(ctx, span, nil, "namespace, app name, service name and deployment target id are required")
+	}
+	if inp.ImageRepository == "" || inp.ImageTag == "" {
+		return res, telemetry.Error(ctx, span, nil, "image repository and tag are required")
+	}
+
+	selector := fmt.Sprintf(
+		"%s=%s,%s=%s,%s=%s,!%s",
+		LabelKey_DeploymentTargetID, inp.DeploymentTargetID,
+		LabelKey_AppName, inp.AppName,
+		LabelKey_ServiceName, inp.ServiceName,
+		LabelKey_Canary,
+	)
+
+	deployments, err := clientset.AppsV1().Deployments(inp.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return res, telemetry.Error(ctx, span, err, "error listing deployments")
+	}
+	if len(deployments.Items) == 0 {
+		return res, ErrNoStableDeployment
+	}
+	if len(deployments.Items) != 1 {
+		return res, telemetry.Error(ctx, span, nil, fmt.Sprintf("expected one deployment for service %s, found %d", inp.ServiceName, len(deployments.Items)))
+	}
+	stable := deployments.Items[0]
+
+	services, err := clientset.CoreV1().Services(inp.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return res, telemetry.Error(ctx, span, err, "error listing services")
+	}
+
+	var stableService *v1.Service
+	for i := range services.Items {
+		if selectsLabels(services.Items[i].Spec.Selector, stable.Spec.Template.Labels) {
+			stableService = &services.Items[i]
+			break
+		}
+	}
+	if stableService == nil {
+		return res, telemetry.Error(ctx, span, nil, fmt.Sprintf("no kubernetes service selects the pods of service %s", inp.ServiceName))
+	}
+
+	stableIngress, err := stableIngressForService(ctx, clientset, inp.Namespace, stableService.Name)
+	if err != nil {
+		return res, telemetry.Error(ctx, span, err, "error finding ingress of service")
+	}
+
+	res.DeploymentName = stable.Name + canarySuffix
+	res.ServiceName = stableService.Name + canarySuffix
+	res.IngressName = stableIngress.Name + canarySuffix
+	res.StableServiceName = stableService.Name
+	res.StableIngressName = stableIngress.Name
+
+	if err := DeleteCanary(ctx, clientset, res); err != nil {
+		return res, telemetry.Error(ctx, span, err, "error deleting previous canary")
+	}
+
+	canary, err := canaryDeployment(stable, stableService.Spec.Selector, res.DeploymentName, inp.ImageRepository, inp.ImageTag)
+	if err != nil {
+		return res, telemetry.Error(ctx, span, err, "error generating canary deployment")
+	}
+
+	if _, err := clientset.AppsV1().Deployments(inp.Namespace).Create(ctx, canary, metav1.CreateOptions{}); err != nil {
+		return res, telemetry.Error(ctx, span, err, "error creating canary deployment")
+	}
+
+	service := canaryService(stableService, res.ServiceName)
+	if _, err := clientset.CoreV1().Services(inp.Namespace).Create(ctx, service, metav1.CreateOptions{}); err != nil {
+		return res, telemetry.Error(ctx, span, err, "error creating canary service")
+	}
+
+	return res, nil
+}
+
+// WaitForCanaryReady waits until a replica of the canary deployment is available
+func WaitForCanaryReady(ctx context.Context, clientset k8s.Interface, res CanaryResources, timeout time.Duration) error {
+	ctx, span := telemetry.NewSpan(ctx, "wait-for-canary-ready")
+	defer span.End()
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+		deployment, err := clientset.AppsV1().Deployments(res.Namespace).Get(ctx, res.DeploymentName, metav1.GetOptions{})
+		if err != nil {
+			return telemetry.Error(ctx, span, err, "error getting canary deployment")
+		}
+
+		if deployment.Status.AvailableReplicas > 0 {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return telemetry.Error(ctx, span, nil, fmt.Sprintf("canary did not become available within %s", timeout))
+		}
+
+		select {
+		case <-ctx.Done():
+			return telemetry.Error(ctx, span, ctx.Err(), "context cancelled while waiting for canary")
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// SetCanaryWeight sends a percentage of the traffic of the stable ingress to the canary, by creating or updating an
+// nginx canary ingress which mirrors the rules of the stable ingress
+func SetCanaryWeight(ctx context.Context, clientset k8s.Interface, res CanaryResources, weight int) error {
+	ctx, span := telemetry.NewSpan(ctx, "set-canary-weight")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "ingress", Value: res.IngressName},
+		telemetry.AttributeKV{Key: "weight", Value: weight},
+	)
+
+	if weight < 0 || weight > 100 {
+		return telemetry.Error(ctx, span, nil, "weight must be between 0 and 100")
+	}
+
+	ingresses := clientset.NetworkingV1().Ingresses(res.Namespace)
+
+	ingress, err := ingresses.Get(ctx, res.IngressName, metav1.GetOptions{})
+	if err == nil {
+		if ingress.Annotations == nil {
+			ingress.Annotations = make(map[string]string)
+		}
+		ingress.Annotations[nginxCanaryWeightAnnotation] = strconv.Itoa(weight)
+
+		if _, err := ingresses.Update(ctx, ingress, metav1.UpdateOptions{}); err != nil {
+			return telemetry.Error(ctx, span, err, "error updating canary ingress")
+		}
+
+		return nil
+	}
+	if !k8serrors.IsNotFound(err) {
+		return telemetry.Error(ctx, span, err, "error getting canary ingress")
+	}
+
+	stable, err := ingresses.Get(ctx, res.StableIngressName, metav1.GetOptions{})
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error getting stable ingress")
+	}
+
+	if _, err := ingresses.Create(ctx, canaryIngress(stable, res, weight), metav1.CreateOptions{}); err != nil {
+		return telemetry.Error(ctx, span, err, "error creating canary ingress")
+	}
+
+	return nil
+}
+
+// DeleteCanary deletes the resources of a canary, starting with the ingress so that traffic returns to the stable
+// pods before the canary pods are removed. Resources which do not exist are ignored.
+func DeleteCanary(ctx context.Context, clientset k8s.Interface, res CanaryResources) error {
+	ctx, span := telemetry.NewSpan(ctx, "delete-canary")
+	defer span.End()
+
+	var errs []error
+
+	if res.IngressName != "" {
+		err := clientset.NetworkingV1().Ingresses(res.Namespace).Delete(ctx, res.IngressName, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("error deleting canary ingress: %w", err))
+		}
+	}
+
+	if res.ServiceName != "" {
+		err := clientset.CoreV1().Services(res.Namespace).Delete(ctx, res.ServiceName, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("error deleting canary service: %w", err))
+		}
+	}
+
+	if res.DeploymentName != "" {
+		err := clientset.AppsV1().Deployments(res.Namespace).Delete(ctx, res.DeploymentName, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("error deleting canary deployment: %w", err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return telemetry.Error(ctx, span, errors.Join(errs...), "error deleting canary")
+	}
+
+	return nil
+}
+
+// stableIngressForService returns the ingress which routes to a kubernetes service, ignoring canary ingresses
+func stableIngressForService(ctx context.Context, clientset k8s.Interface, namespace, serviceName string) (*netv1.Ingress, error) {
+	ingresses, err := clientset.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range ingresses.Items {
+		ingress := &ingresses.Items[i]
+		if ingress.Annotations[nginxCanaryAnnotation] == "true" {
+			continue
+		}
+
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Service != nil && path.Backend.Service.Name == serviceName {
+					return ingress, nil
+				}
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no ingress routes to service %s", serviceName)
+}
+
+// canaryDeployment returns a single replica copy of the stable deployment running the new image. The pods are
+// relabeled so that they are selected by neither the stable service nor the stable deployment.
+func canaryDeployment(stable appsv1.Deployment, stableSelector map[string]string, name, imageRepository, imageTag string) (*appsv1.Deployment, error) {
+	spec := stable.Spec.DeepCopy()
+
+	// the canary pods must match neither the stable service nor the stable deployment
+	relabeled := make(map[string]string)
+	for k, v := range stableSelector {
+		relabeled[k] = v
+	}
+	for k, v := range stable.Spec.Selector.MatchLabels {
+		relabeled[k] = v
+	}
+
+	replicas := int32(1)
+	spec.Replicas = &replicas
+	spec.Selector = &metav1.LabelSelector{
+		MatchLabels: canaryLabels(stable.Spec.Selector.MatchLabels, relabeled),
+	}
+	spec.Template.Labels = canaryLabels(stable.Spec.Template.Labels, relabeled)
+
+	var replaced bool
+	for i, container := range spec.Template.Spec.Containers {
+		if imageRepositoryFromImage(container.Image) == imageRepository {
+			spec.Template.Spec.Containers[i].Image = fmt.Sprintf("%s:%s", imageRepository, imageTag)
+			replaced = true
+		}
+	}
+
+	// images may be pushed to a repository under a different name than the one they are pulled from
+	if !replaced && len(spec.Template.Spec.Containers) == 1 {
+		spec.Template.Spec.Containers[0].Image = fmt.Sprintf("%s:%s", imageRepository, imageTag)
+		replaced = true
+	}
+
+	if !replaced {
+		return nil, fmt.Errorf("no container of deployment %s runs an image from %s", stable.Name, imageRepository)
+	}
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: stable.Namespace,
+			Labels:    canaryLabels(stable.Labels, nil),
+		},
+		Spec: *spec,
+	}, nil
+}
+
+// canaryService returns a copy of the stable service which selects the canary pods
+func canaryService(stable *v1.Service, name string) *v1.Service {
+	ports := make([]v1.ServicePort, 0, len(stable.Spec.Ports))
+	for _, port := range stable.Spec.Ports {
+		ports = append(ports, v1.ServicePort{
+			Name:       port.Name,
+			Protocol:   port.Protocol,
+			Port:       port.Port,
+			TargetPort: port.TargetPort,
+		})
+	}
+
+	return &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: stable.Namespace,
+			Labels:    canaryLabels(stable.Labels, nil),
+		},
+		Spec: v1.ServiceSpec{
+			Type:     v1.ServiceTypeClusterIP,
+			Selector: canaryLabels(stable.Spec.Selector, stable.Spec.Selector),
+			Ports:    ports,
+		},
+	}
+}
+
+// canaryIngress returns an nginx canary ingress with the rules of the stable ingress, routing to the canary service
+func canaryIngress(stable *netv1.Ingress, res CanaryResources, weight int) *netv1.Ingress {
+	spec := netv1.IngressSpec{
+		IngressClassName: stable.Spec.IngressClassName,
+	}
+
+	for _, rule := range stable.Spec.Rules {
+		rule := *rule.DeepCopy()
+		if rule.HTTP != nil {
+			for i, path := range rule.HTTP.Paths {
+				if path.Backend.Service != nil && path.Backend.Service.Name == res.StableServiceName {
+					rule.HTTP.Paths[i].Backend.Service.Name = res.ServiceName
+				}
+			}
+		}
+		spec.Rules = append(spec.Rules, rule)
+	}
+
+	annotations := map[string]string{
+		nginxCanaryAnnotation:       "true",
+		nginxCanaryWeightAnnotation: strconv.Itoa(weight),
+	}
+	if class, ok := stable.Annotations[ingressClassAnnotation]; ok {
+		annotations[ingressClassAnnotation] = class
+	}
+
+	return &netv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        res.IngressName,
+			Namespace:   stable.Namespace,
+			Labels:      canaryLabels(stable.Labels, nil),
+			Annotations: annotations,
+		},
+		Spec: spec,
+	}
+}
+
+// canaryLabels copies labels with the canary label added. The values of keys in the stable selector are suffixed, so
+// that the stable selector no longer matches.
+func canaryLabels(labels, stableSelector map[string]string) map[string]string {
+	res := map[string]string{LabelKey_Canary: "true"}
+
+	for k, v := range labels {
+		if _, ok := stableSelector[k]; ok {
+			if len(v)+len(canarySuffix) > maxLabelValueLength {
+				v = v[:maxLabelValueLength-len(canarySuffix)]
+			}
+			v += canarySuffix
+		}
+		res[k] = v
+	}
+
+	return res
+}
+
+// selectsLabels returns true if every key of a non-empty selector matches the labels
+func selectsLabels(selector, labels map[string]string) bool {
+	if len(selector) == 0 {
+		return false
+	}
+
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// imageRepositoryFromImage strips the tag or digest from an image reference
+func imageRepositoryFromImage(image string) string {
+	if i := strings.Index(image, "@"); i != -1 {
+		image = image[:i]
+	}
+
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+
+	return image
+}
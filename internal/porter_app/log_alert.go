@@ -0,0 +1,116 @@
+package porter_app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+const (
+	// DefaultLogAlertWindow is the window of a log alert rule which does not set one
+	DefaultLogAlertWindow = 5 * time.Minute
+	// LogAlertRepeatInterval is how often a rule which stays firing is notified again
+	LogAlertRepeatInterval = time.Hour
+	// logAlertSampleSize is the number of matching lines attached to a notification
+	logAlertSampleSize = 5
+	// logAlertSampleLineLength is the length at which sample lines are truncated
+	logAlertSampleLineLength = 500
+	// maxPodSelectorPrefixLength is the longest pod name prefix matched by a pod selector, since pod names are
+	// truncated before their generated suffix
+	maxPodSelectorPrefixLength = 58
+)
+
+// LogAlertPodSelector returns the pod selector matching the pods of a service of an app, or of every service of the
+// app if serviceName is empty
+func LogAlertPodSelector(appName, serviceName string) string {
+	prefix := fmt.Sprintf("%s-", appName)
+	if serviceName != "" {
+		prefix = fmt.Sprintf("%s-%s-", appName, serviceName)
+	}
+
+	if len(prefix) > maxPodSelectorPrefixLength {
+		prefix = prefix[:maxPodSelectorPrefixLength]
+	}
+
+	return fmt.Sprintf("%s.*", prefix)
+}
+
+// EvaluateLogAlertRuleInput is the input for EvaluateLogAlertRule
+type EvaluateLogAlertRuleInput struct {
+	// Query is the parsed query of the rule
+	Query LogQuery
+	// Threshold is the number of matching lines above which the rule fires
+	Threshold uint
+	// Window is the period, ending at Now, over which matching lines are counted
+	Window time.Duration
+	// Now is the end of the window
+	Now time.Time
+	// Fetch reads the logs which the rule applies to
+	Fetch LogFetcher
+}
+
+// EvaluateLogAlertRuleResult is the result of evaluating a log alert rule
+type EvaluateLogAlertRuleResult struct {
+	// MatchCount is the number of matching lines, which stops at one more than the threshold
+	MatchCount uint
+	// Firing is true if more lines than the threshold matched
+	Firing bool
+	// SampleLines are the most recent matching lines, newest first
+	SampleLines []string
+}
+
+// EvaluateLogAlertRule counts the lines matching the query of a log alert rule within its window. Counting stops once
+// the threshold is exceeded, since the rule fires regardless of how many more lines match.
+func EvaluateLogAlertRule(ctx context.Context, inp EvaluateLogAlertRuleInput) (EvaluateLogAlertRuleResult, error) {
+	var res EvaluateLogAlertRuleResult
+
+	window := inp.Window
+	if window <= 0 {
+		window = DefaultLogAlertWindow
+	}
+
+	search, err := SearchLogs(ctx, SearchLogsInput{
+		Query:     inp.Query,
+		Start:     inp.Now.Add(-window),
+		End:       inp.Now,
+		Direction: LogSearchDirection_Backward,
+		Limit:     int(inp.Threshold) + 1,
+		Fetch:     inp.Fetch,
+	})
+	if err != nil {
+		return res, err
+	}
+
+	res.MatchCount = uint(len(search.Logs))
+	res.Firing = res.MatchCount > inp.Threshold
+
+	for _, log := range search.Logs {
+		if len(res.SampleLines) == logAlertSampleSize {
+			break
+		}
+
+		line := log.Line
+		if len(line) > logAlertSampleLineLength {
+			line = line[:logAlertSampleLineLength] + "..."
+		}
+		res.SampleLines = append(res.SampleLines, line)
+	}
+
+	return res, nil
+}
+
+// ShouldNotifyLogAlert returns true if a rule in the given state should send a notification. A rule is notified when
+// it starts firing, and again every LogAlertRepeatInterval for as long as it keeps firing.
+func ShouldNotifyLogAlert(lastState types.AppLogAlertState, lastFiredAt *time.Time, firing bool, now time.Time) bool {
+	if !firing {
+		return false
+	}
+
+	if lastState != types.AppLogAlertState_Firing || lastFiredAt == nil {
+		return true
+	}
+
+	return !now.Before(lastFiredAt.Add(LogAlertRepeatInterval))
+}
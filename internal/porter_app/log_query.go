@@ -0,0 +1,636 @@
+package porter_app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LogLevel is the severity of a log line, as detected from its content
+type LogLevel string
+
+const (
+	// LogLevel_Debug is the level of debug and trace logs
+	LogLevel_Debug LogLevel = "debug"
+	// LogLevel_Info is the level of informational logs
+	LogLevel_Info LogLevel = "info"
+	// LogLevel_Warn is the level of warnings
+	LogLevel_Warn LogLevel = "warn"
+	// LogLevel_Error is the level of errors
+	LogLevel_Error LogLevel = "error"
+	// LogLevel_Fatal is the level of fatal errors and panics
+	LogLevel_Fatal LogLevel = "fatal"
+)
+
+// logLevelRank orders levels from least to most severe, so that levels can be compared in queries such as level:>=warn
+var logLevelRank = map[LogLevel]int{
+	LogLevel_Debug: 1,
+	LogLevel_Info:  2,
+	LogLevel_Warn:  3,
+	LogLevel_Error: 4,
+	LogLevel_Fatal: 5,
+}
+
+// logLevelAliases maps the level names used by common logging libraries to a LogLevel
+var logLevelAliases = map[string]LogLevel{
+	"trace":       LogLevel_Debug,
+	"debug":       LogLevel_Debug,
+	"info":        LogLevel_Info,
+	"information": LogLevel_Info,
+	"notice":      LogLevel_Info,
+	"warn":        LogLevel_Warn,
+	"warning":     LogLevel_Warn,
+	"err":         LogLevel_Error,
+	"error":       LogLevel_Error,
+	"fatal":       LogLevel_Fatal,
+	"panic":       LogLevel_Fatal,
+	"crit":        LogLevel_Fatal,
+	"critical":    LogLevel_Fatal,
+	"alert":       LogLevel_Fatal,
+	"emergency":   LogLevel_Fatal,
+}
+
+// jsonLogLevelKeys are the fields which hold the level of JSON logs, in order of preference
+var jsonLogLevelKeys = []string{"level", "severity", "lvl", "log.level"}
+
+var (
+	logfmtLevelRegex  = regexp.MustCompile(`(?i)\b(?:level|lvl|severity)=["']?([a-z]+)`)
+	keywordLevelRegex = regexp.MustCompile(`\b(TRACE|DEBUG|INFO|NOTICE|WARN|WARNING|ERROR|FATAL|PANIC|CRITICAL)\b`)
+)
+
+// ParseLogLevel converts a level name, such as "warning" or "ERR", to a LogLevel
+func ParseLogLevel(name string) (LogLevel, error) {
+	level, ok := logLevelAliases[strings.ToLower(name)]
+	if !ok {
+		return "", fmt.Errorf("unknown log level %q", name)
+	}
+
+	return level, nil
+}
+
+// DetectLogLevel returns the level of a log line, or an empty level if none can be found. The level is read from the
+// level field of JSON logs, then from a logfmt level= pair, and finally from an uppercase keyword such as ERROR.
+func DetectLogLevel(line string) LogLevel {
+	if fields, ok := parseJSONLog(line); ok {
+		return jsonLogLevel(fields)
+	}
+
+	if match := logfmtLevelRegex.FindStringSubmatch(line); match != nil {
+		if level, err := ParseLogLevel(match[1]); err == nil {
+			return level
+		}
+	}
+
+	if match := keywordLevelRegex.FindString(line); match != "" {
+		level, _ := ParseLogLevel(match)
+		return level
+	}
+
+	return ""
+}
+
+// jsonLogLevel returns the level of a parsed JSON log. Numeric levels follow the bunyan and pino convention, where 30 is
+// info and 50 is error.
+func jsonLogLevel(fields map[string]any) LogLevel {
+	for _, key := range jsonLogLevelKeys {
+		value, ok := fields[key]
+		if !ok {
+			continue
+		}
+
+		switch v := value.(type) {
+		case string:
+			if level, err := ParseLogLevel(v); err == nil {
+				return level
+			}
+		case float64:
+			switch {
+			case v >= 60:
+				return LogLevel_Fatal
+			case v >= 50:
+				return LogLevel_Error
+			case v >= 40:
+				return LogLevel_Warn
+			case v >= 30:
+				return LogLevel_Info
+			default:
+				return LogLevel_Debug
+			}
+		}
+	}
+
+	return ""
+}
+
+// parseJSONLog parses a log line which is a JSON object
+func parseJSONLog(line string) (map[string]any, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "{") {
+		return nil, false
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(trimmed), &fields); err != nil {
+		return nil, false
+	}
+
+	return fields, true
+}
+
+// logQueryTermKind is the kind of filter applied by a term of a log query
+type logQueryTermKind int
+
+const (
+	logQueryTermKind_Text logQueryTermKind = iota
+	logQueryTermKind_Regex
+	logQueryTermKind_Field
+	logQueryTermKind_Level
+	logQueryTermKind_Pod
+	logQueryTermKind_Service
+	logQueryTermKind_Revision
+	logQueryTermKind_Stream
+)
+
+// logQueryOp is the comparison made by a field or level term
+type logQueryOp string
+
+const (
+	logQueryOp_Equal        logQueryOp = ""
+	logQueryOp_Greater      logQueryOp = ">"
+	logQueryOp_GreaterEqual logQueryOp = ">="
+	logQueryOp_Less         logQueryOp = "<"
+	logQueryOp_LessEqual    logQueryOp = "<="
+	logQueryOp_Exists       logQueryOp = "exists"
+)
+
+// logQueryTerm is a single filter of a log query
+type logQueryTerm struct {
+	kind   logQueryTermKind
+	negate bool
+	op     logQueryOp
+	// value is the text of text terms, and the value of all other terms other than regexes
+	value string
+	// path is the path of a field term, split on dots
+	path []string
+	// pattern is the compiled regex of regex terms, or the compiled glob of field and pod terms
+	pattern *regexp.Regexp
+	number  float64
+	level   LogLevel
+	// revisionNumber is set on revision terms which refer to a revision by number rather than by id
+	revisionNumber uint64
+}
+
+// LogQuery is a parsed log search query. A query is a list of whitespace-separated terms, all of which must match:
+//
+//	timeout                      lines containing "timeout"
+//	"connection refused"         lines containing a phrase
+//	/status=5\d\d/               lines matching a regular expression
+//	-healthcheck                 lines not containing "healthcheck"
+//	level:error, level:>=warn    lines with a severity, detected from JSON, logfmt or keywords
+//	pod:web-7d9f*                lines from pods matching a glob
+//	service:web, stream:stderr   lines from a service or an output stream
+//	revision:42                  lines from a revision, by number or id
+//	user.id:123, status:>=500    JSON logs whose field matches a glob or a numeric comparison
+//	user.id:*                    JSON logs which have a field
+type LogQuery struct {
+	// Raw is the query as written
+	Raw   string
+	terms []logQueryTerm
+}
+
+// reservedLogQueryKeys are the keys which filter on log metadata rather than on JSON fields
+var reservedLogQueryKeys = map[string]logQueryTermKind{
+	"level":    logQueryTermKind_Level,
+	"severity": logQueryTermKind_Level,
+	"pod":      logQueryTermKind_Pod,
+	"service":  logQueryTermKind_Service,
+	"revision": logQueryTermKind_Revision,
+	"stream":   logQueryTermKind_Stream,
+}
+
+// logQueryKeyRegex matches the key of a key:value term
+var logQueryKeyRegex = regexp.MustCompile(`^[A-Za-z_@][A-Za-z0-9_.@-]*$`)
+
+// ParseLogQuery parses a log search query. An empty query matches every log.
+func ParseLogQuery(query string) (LogQuery, error) {
+	parsed := LogQuery{Raw: query}
+
+	tokens, err := tokenizeLogQuery(query)
+	if err != nil {
+		return parsed, err
+	}
+
+	for _, token := range tokens {
+		term, err := parseLogQueryTerm(token)
+		if err != nil {
+			return parsed, err
+		}
+
+		parsed.terms = append(parsed.terms, term)
+	}
+
+	return parsed, nil
+}
+
+// logQueryToken is a single term of a query before it is parsed
+type logQueryToken struct {
+	text   string
+	negate bool
+	regex  bool
+	// quoted is set when any part of the token was quoted, in which case it is never treated as a key:value term
+	// unless the quotes only surround the value
+	quoted bool
+	// key is the part of the token before the first unquoted colon, if any
+	key    string
+	hasKey bool
+}
+
+// tokenizeLogQuery splits a query on whitespace outside of quotes and regexes
+func tokenizeLogQuery(query string) ([]logQueryToken, error) {
+	var tokens []logQueryToken
+
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		if runes[i] == ' ' || runes[i] == '\t' || runes[i] == '\n' {
+			i++
+			continue
+		}
+
+		token := logQueryToken{}
+		if runes[i] == '-' && i+1 < len(runes) && runes[i+1] != ' ' {
+			token.negate = true
+			i++
+		}
+
+		if runes[i] == '/' {
+			end := i + 1
+			var pattern strings.Builder
+			for ; end < len(runes) && runes[end] != '/'; end++ {
+				if runes[end] == '\\' && end+1 < len(runes) && runes[end+1] == '/' {
+					end++
+				}
+				pattern.WriteRune(runes[end])
+			}
+			if end >= len(runes) {
+				return nil, errors.New("unterminated regular expression in query")
+			}
+
+			token.regex = true
+			token.text = pattern.String()
+			tokens = append(tokens, token)
+			i = end + 1
+			continue
+		}
+
+		var text strings.Builder
+		inQuotes := false
+	scan:
+		for ; i < len(runes); i++ {
+			r := runes[i]
+			switch {
+			case r == '\\' && inQuotes && i+1 < len(runes) && runes[i+1] == '"':
+				text.WriteRune('"')
+				i++
+			case r == '"':
+				inQuotes = !inQuotes
+				token.quoted = true
+			case !inQuotes && (r == ' ' || r == '\t' || r == '\n'):
+				break scan
+			case !inQuotes && r == ':' && !token.hasKey && !token.quoted:
+				token.key = text.String()
+				token.hasKey = true
+				text.Reset()
+			default:
+				text.WriteRune(r)
+			}
+		}
+		if inQuotes {
+			return nil, errors.New("unterminated quote in query")
+		}
+
+		token.text = text.String()
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
+
+// parseLogQueryTerm converts a token to a term
+func parseLogQueryTerm(token logQueryToken) (logQueryTerm, error) {
+	term := logQueryTerm{negate: token.negate}
+
+	if token.regex {
+		pattern, err := regexp.Compile(token.text)
+		if err != nil {
+			return term, fmt.Errorf("invalid regular expression /%s/: %w", token.text, err)
+		}
+
+		term.kind = logQueryTermKind_Regex
+		term.pattern = pattern
+		return term, nil
+	}
+
+	// terms such as "http://" or ":8080" are searched as text rather than treated as a key
+	if !token.hasKey || !logQueryKeyRegex.MatchString(token.key) || strings.HasPrefix(token.text, "//") {
+		term.kind = logQueryTermKind_Text
+		term.value = token.text
+		if token.hasKey {
+			term.value = token.key + ":" + token.text
+		}
+		if term.value == "" {
+			return term, errors.New("empty term in query")
+		}
+		return term, nil
+	}
+
+	key := token.key
+	op, value := splitLogQueryOp(token.text)
+	if token.quoted {
+		op, value = logQueryOp_Equal, token.text
+	}
+
+	kind, reserved := reservedLogQueryKeys[strings.ToLower(key)]
+	if !reserved {
+		kind = logQueryTermKind_Field
+	}
+	term.kind = kind
+	term.op = op
+	term.value = value
+
+	if value == "" && op != logQueryOp_Exists {
+		return term, fmt.Errorf("missing value for %s", key)
+	}
+	if op != logQueryOp_Equal && kind != logQueryTermKind_Field && kind != logQueryTermKind_Level {
+		return term, fmt.Errorf("%s only supports exact matches", key)
+	}
+
+	switch kind {
+	case logQueryTermKind_Level:
+		level, err := ParseLogLevel(value)
+		if err != nil {
+			return term, err
+		}
+		term.level = level
+	case logQueryTermKind_Pod:
+		term.pattern = globRegex(value)
+	case logQueryTermKind_Revision:
+		if number, err := strconv.ParseUint(value, 10, 64); err == nil {
+			term.revisionNumber = number
+			term.value = ""
+		}
+	case logQueryTermKind_Field:
+		term.path = strings.Split(key, ".")
+		switch op {
+		case logQueryOp_Equal:
+			term.pattern = globRegex(value)
+		case logQueryOp_Exists:
+		default:
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return term, fmt.Errorf("%s%s requires a number", key, op)
+			}
+			term.number = number
+		}
+	}
+
+	return term, nil
+}
+
+// splitLogQueryOp splits the comparison from the value of a key:value term
+func splitLogQueryOp(value string) (logQueryOp, string) {
+	if value == "*" {
+		return logQueryOp_Exists, ""
+	}
+
+	for _, op := range []logQueryOp{logQueryOp_GreaterEqual, logQueryOp_LessEqual, logQueryOp_Greater, logQueryOp_Less} {
+		if strings.HasPrefix(value, string(op)) {
+			return op, strings.TrimPrefix(value, string(op))
+		}
+	}
+
+	return logQueryOp_Equal, value
+}
+
+// globRegex compiles a glob, where * matches any sequence of characters, to an anchored regex
+func globRegex(glob string) *regexp.Regexp {
+	parts := strings.Split(glob, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
+
+// Match returns true if a log matches every term of the query
+func (q LogQuery) Match(log StructuredLog) bool {
+	var (
+		fields       map[string]any
+		fieldsParsed bool
+		isJSON       bool
+	)
+
+	for _, term := range q.terms {
+		var matched bool
+
+		switch term.kind {
+		case logQueryTermKind_Text:
+			matched = strings.Contains(log.Line, term.value)
+		case logQueryTermKind_Regex:
+			matched = term.pattern.MatchString(log.Line)
+		case logQueryTermKind_Pod:
+			matched = term.pattern.MatchString(log.PodName)
+		case logQueryTermKind_Service:
+			matched = log.ServiceName == term.value
+		case logQueryTermKind_Stream:
+			matched = log.OutputStream == term.value
+		case logQueryTermKind_Revision:
+			matched = term.value != "" && log.AppRevisionID == term.value
+		case logQueryTermKind_Level:
+			level := log.Level
+			if level == "" {
+				level = DetectLogLevel(log.Line)
+			}
+			matched = level != "" && compareLogLevels(level, term.op, term.level)
+		case logQueryTermKind_Field:
+			if !fieldsParsed {
+				fields, isJSON = parseJSONLog(log.Line)
+				fieldsParsed = true
+			}
+			matched = isJSON && term.matchField(fields)
+		}
+
+		if matched == term.negate {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compareLogLevels compares the level of a log against the level of a term
+func compareLogLevels(level LogLevel, op logQueryOp, target LogLevel) bool {
+	rank, want := logLevelRank[level], logLevelRank[target]
+
+	switch op {
+	case logQueryOp_Greater:
+		return rank > want
+	case logQueryOp_GreaterEqual:
+		return rank >= want
+	case logQueryOp_Less:
+		return rank < want
+	case logQueryOp_LessEqual:
+		return rank <= want
+	default:
+		return rank == want
+	}
+}
+
+// matchField matches a field term against the fields of a JSON log
+func (t logQueryTerm) matchField(fields map[string]any) bool {
+	value, ok := jsonLogField(fields, t.path)
+	if !ok {
+		return false
+	}
+
+	switch t.op {
+	case logQueryOp_Exists:
+		return true
+	case logQueryOp_Equal:
+		return t.pattern.MatchString(jsonLogFieldString(value))
+	}
+
+	var number float64
+	switch v := value.(type) {
+	case float64:
+		number = v
+	case string:
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return false
+		}
+		number = parsed
+	default:
+		return false
+	}
+
+	switch t.op {
+	case logQueryOp_Greater:
+		return number > t.number
+	case logQueryOp_GreaterEqual:
+		return number >= t.number
+	case logQueryOp_Less:
+		return number < t.number
+	case logQueryOp_LessEqual:
+		return number <= t.number
+	}
+
+	return false
+}
+
+// jsonLogField reads a nested field of a JSON log. Keys which themselves contain dots, such as "log.level", are matched
+// before nested objects.
+func jsonLogField(fields map[string]any, path []string) (any, bool) {
+	for i := len(path); i > 0; i-- {
+		value, ok := fields[strings.Join(path[:i], ".")]
+		if !ok {
+			continue
+		}
+		if i == len(path) {
+			return value, true
+		}
+
+		nested, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		return jsonLogField(nested, path[i:])
+	}
+
+	return nil, false
+}
+
+// jsonLogFieldString formats a JSON value for glob matching
+func jsonLogFieldString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return "null"
+	default:
+		by, _ := json.Marshal(v)
+		return string(by)
+	}
+}
+
+// SearchParam returns text which every matching log contains, so that the search can be narrowed before logs are
+// filtered by the query
+func (q LogQuery) SearchParam() string {
+	for _, term := range q.terms {
+		if term.kind == logQueryTermKind_Text && !term.negate {
+			return term.value
+		}
+	}
+
+	return ""
+}
+
+// ServiceName returns the service every matching log belongs to, or an empty string if the query allows any service
+func (q LogQuery) ServiceName() string {
+	for _, term := range q.terms {
+		if term.kind == logQueryTermKind_Service && !term.negate {
+			return term.value
+		}
+	}
+
+	return ""
+}
+
+// AppRevisionID returns the revision id every matching log belongs to, or an empty string if the query allows any
+// revision. Revisions referred to by number must be resolved with ResolveRevisionNumbers first.
+func (q LogQuery) AppRevisionID() string {
+	for _, term := range q.terms {
+		if term.kind == logQueryTermKind_Revision && !term.negate && term.value != "" {
+			return term.value
+		}
+	}
+
+	return ""
+}
+
+// RevisionNumbers returns the revision numbers referred to by the query, which must be resolved to ids with
+// ResolveRevisionNumbers before the query can match them
+func (q LogQuery) RevisionNumbers() []uint64 {
+	var numbers []uint64
+	for _, term := range q.terms {
+		if term.kind == logQueryTermKind_Revision && term.revisionNumber != 0 {
+			numbers = append(numbers, term.revisionNumber)
+		}
+	}
+
+	return numbers
+}
+
+// ResolveRevisionNumbers sets the revision ids of terms which refer to a revision by number, given the ids of the
+// revisions of the app keyed by number
+func (q *LogQuery) ResolveRevisionNumbers(revisionIDs map[uint64]string) error {
+	for i, term := range q.terms {
+		if term.kind != logQueryTermKind_Revision || term.revisionNumber == 0 {
+			continue
+		}
+
+		id, ok := revisionIDs[term.revisionNumber]
+		if !ok {
+			return fmt.Errorf("revision %d not found", term.revisionNumber)
+		}
+		q.terms[i].value = id
+	}
+
+	return nil
+}
@@ -0,0 +1,282 @@
+package porter_app
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"time"
+)
+
+const (
+	// LogSearchDirection_Forward searches from the start of a range towards its end
+	LogSearchDirection_Forward = "forward"
+	// LogSearchDirection_Backward searches from the end of a range towards its start
+	LogSearchDirection_Backward = "backward"
+)
+
+const (
+	defaultLogSearchPageSize   = 1000
+	defaultLogSearchMaxScanned = 100000
+)
+
+// LogFetchInput is a page of logs requested by SearchLogs
+type LogFetchInput struct {
+	Start     time.Time
+	End       time.Time
+	Limit     uint
+	Direction string
+}
+
+// LogFetcher reads a page of logs in a time range, such as from the porter agent. Logs may be returned in any order, but
+// when the limit is reached the page must hold the logs nearest the start of the range when reading forward, or the end
+// of the range when reading backward.
+type LogFetcher func(ctx context.Context, inp LogFetchInput) ([]StructuredLog, error)
+
+// SearchLogsInput is the input to SearchLogs
+type SearchLogsInput struct {
+	Query LogQuery
+	// Start and End bound the search, where End is exclusive
+	Start time.Time
+	End   time.Time
+	// Offset is the number of logs at the boundary of the range which were already read, and is set from the
+	// ContinueOffset of a previous search. The boundary is Start when searching forward, and the nanosecond before End
+	// when searching backward.
+	Offset int
+	// Direction is either LogSearchDirection_Forward or LogSearchDirection_Backward, and defaults to backward
+	Direction string
+	// Limit is the maximum number of matching logs to return
+	Limit int
+	// PageSize is the number of logs read from the fetcher at a time
+	PageSize uint
+	// MaxScanned is the number of logs after which the search stops, even if fewer than Limit logs have matched
+	MaxScanned int
+	Fetch      LogFetcher
+}
+
+// SearchLogsResult is the result of SearchLogs
+type SearchLogsResult struct {
+	// Logs are the matching logs, ordered in the direction of the search
+	Logs []StructuredLog
+	// ContinueTime is set when the range was not fully searched. It is the start of the remaining range when searching
+	// forward, and the end of the remaining range when searching backward.
+	ContinueTime *time.Time
+	// ContinueOffset is the number of logs at the boundary of the remaining range which were already read, since
+	// several logs can share a timestamp. It is passed as the Offset of the continued search.
+	ContinueOffset int
+	// Scanned is the number of logs which were read
+	Scanned int
+}
+
+// SearchLogs pages through the logs in a time range and returns those which match a query. Since the query is applied
+// after logs are fetched, a search for rare lines may read many pages; MaxScanned bounds the work done by a single call,
+// and the returned continue time and offset allow the search to be resumed.
+//
+// Many logs can share a timestamp, so the logs at a timestamp are always read from a single page: when a page is full,
+// the logs at its last timestamp are left for the next page, which starts at that timestamp.
+func SearchLogs(ctx context.Context, inp SearchLogsInput) (SearchLogsResult, error) {
+	var res SearchLogsResult
+
+	if inp.Fetch == nil {
+		return res, errors.New("no log fetcher provided")
+	}
+	if !inp.End.After(inp.Start) {
+		return res, errors.New("end of range must be after start of range")
+	}
+	if inp.Offset < 0 {
+		return res, errors.New("offset must not be negative")
+	}
+
+	direction := inp.Direction
+	if direction == "" {
+		direction = LogSearchDirection_Backward
+	}
+	if direction != LogSearchDirection_Forward && direction != LogSearchDirection_Backward {
+		return res, errors.New("direction must be forward or backward")
+	}
+	forward := direction == LogSearchDirection_Forward
+
+	pageSize := inp.PageSize
+	if pageSize == 0 {
+		pageSize = defaultLogSearchPageSize
+	}
+	maxScanned := inp.MaxScanned
+	if maxScanned == 0 {
+		maxScanned = defaultLogSearchMaxScanned
+	}
+
+	start, end, skip := inp.Start, inp.End, inp.Offset
+	limit := pageSize
+	for {
+		page, err := inp.Fetch(ctx, LogFetchInput{
+			Start:     start,
+			End:       end,
+			Limit:     limit,
+			Direction: direction,
+		})
+		if err != nil {
+			return res, err
+		}
+
+		sortLogs(page, forward)
+
+		full := uint(len(page)) >= limit
+		if full {
+			// the limit may have cut off some of the logs at the last timestamp of the page, so they are read with the
+			// next page. If every log of the page has the same timestamp, the page is read again with a larger limit.
+			complete := len(page)
+			for complete > 0 && page[complete-1].Timestamp.Equal(page[len(page)-1].Timestamp) {
+				complete--
+			}
+			if complete == 0 {
+				limit *= 2
+				continue
+			}
+
+			page = page[:complete]
+		}
+		limit = pageSize
+
+		// the logs at the boundary of the range which were read by a previous search are skipped. Since every log at
+		// a timestamp is in the page, and they are sorted, they are the first logs of the page.
+		boundary := start
+		if !forward {
+			boundary = end.Add(-time.Nanosecond)
+		}
+
+		var lastTimestamp time.Time
+		atLastTimestamp := 0
+
+		for i, log := range page {
+			if i > 0 && log.Timestamp.Equal(lastTimestamp) {
+				atLastTimestamp++
+			} else {
+				lastTimestamp = log.Timestamp
+				atLastTimestamp = 1
+			}
+
+			if i < skip && log.Timestamp.Equal(boundary) {
+				continue
+			}
+
+			res.Scanned++
+			if !inp.Query.Match(log) {
+				continue
+			}
+
+			res.Logs = append(res.Logs, log)
+			if inp.Limit > 0 && len(res.Logs) >= inp.Limit {
+				res.ContinueTime, res.ContinueOffset = continueCursor(log.Timestamp, atLastTimestamp, forward)
+				return res, nil
+			}
+		}
+
+		if !full {
+			return res, nil
+		}
+
+		// the next page starts at the first timestamp which was left unread
+		skip = 0
+		next := page[len(page)-1].Timestamp
+		if forward {
+			start = next.Add(time.Nanosecond)
+		} else {
+			end = next
+		}
+
+		if res.Scanned >= maxScanned {
+			continueTime := end
+			if forward {
+				continueTime = start
+			}
+			res.ContinueTime = &continueTime
+			return res, nil
+		}
+	}
+}
+
+// continueCursor returns the cursor which continues a search after the given number of logs at a timestamp were read:
+// a search forward starts at the timestamp, and a search backward ends just after it
+func continueCursor(timestamp time.Time, read int, forward bool) (*time.Time, int) {
+	if !forward {
+		timestamp = timestamp.Add(time.Nanosecond)
+	}
+
+	return &timestamp, read
+}
+
+// sortLogs sorts logs in the direction of a search. Logs with the same timestamp are ordered by their other fields, so
+// that the offset of a cursor refers to the same logs whichever order the fetcher returned them in.
+func sortLogs(logs []StructuredLog, forward bool) {
+	sort.SliceStable(logs, func(i, j int) bool {
+		a, b := logs[i], logs[j]
+		if !forward {
+			a, b = b, a
+		}
+
+		if !a.Timestamp.Equal(b.Timestamp) {
+			return a.Timestamp.Before(b.Timestamp)
+		}
+
+		for _, fields := range [][2]string{
+			{a.PodName, b.PodName},
+			{a.ServiceName, b.ServiceName},
+			{a.OutputStream, b.OutputStream},
+			{a.Line, b.Line},
+		} {
+			if fields[0] != fields[1] {
+				return fields[0] < fields[1]
+			}
+		}
+
+		return false
+	})
+}
+
+// logCSVHeader is the header row of logs exported as CSV
+var logCSVHeader = []string{"timestamp", "service_name", "pod_name", "level", "output_stream", "app_revision_id", "job_run_id", "line"}
+
+// WriteLogsCSV writes logs as CSV, with a header row if header is set
+func WriteLogsCSV(w io.Writer, logs []StructuredLog, header bool) error {
+	writer := csv.NewWriter(w)
+
+	if header {
+		if err := writer.Write(logCSVHeader); err != nil {
+			return err
+		}
+	}
+
+	for _, log := range logs {
+		err := writer.Write([]string{
+			log.Timestamp.UTC().Format(time.RFC3339Nano),
+			log.ServiceName,
+			log.PodName,
+			string(log.Level),
+			log.OutputStream,
+			log.AppRevisionID,
+			log.JobRunID,
+			log.Line,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteLogsNDJSON writes logs as newline-delimited JSON, with one log per line
+func WriteLogsNDJSON(w io.Writer, logs []StructuredLog) error {
+	encoder := json.NewEncoder(w)
+
+	for _, log := range logs {
+		if err := encoder.Encode(log); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
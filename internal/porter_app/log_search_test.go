@@ -0,0 +1,164 @@
+package porter_app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+)
+
+// fakeLogFetcher serves a page of logs in a range. Logs which share a timestamp are returned in reverse order of their
+// lines, so that a search cannot rely on the order of the fetcher.
+func fakeLogFetcher(logs []StructuredLog) LogFetcher {
+	return func(ctx context.Context, inp LogFetchInput) ([]StructuredLog, error) {
+		var page []StructuredLog
+		for _, log := range logs {
+			if !log.Timestamp.Before(inp.Start) && log.Timestamp.Before(inp.End) {
+				page = append(page, log)
+			}
+		}
+
+		forward := inp.Direction == LogSearchDirection_Forward
+		sort.SliceStable(page, func(i, j int) bool {
+			if page[i].Timestamp.Equal(page[j].Timestamp) {
+				return page[i].Line > page[j].Line
+			}
+			if forward {
+				return page[i].Timestamp.Before(page[j].Timestamp)
+			}
+			return page[i].Timestamp.After(page[j].Timestamp)
+		})
+
+		if uint(len(page)) > inp.Limit {
+			page = page[:inp.Limit]
+		}
+
+		return page, nil
+	}
+}
+
+// testLogsWithSharedTimestamps returns logs where several lines share the timestamps at which pages and searches stop
+func testLogsWithSharedTimestamps(start time.Time) []StructuredLog {
+	var logs []StructuredLog
+	add := func(offset time.Duration, count int) {
+		for i := 0; i < count; i++ {
+			logs = append(logs, StructuredLog{
+				Timestamp: start.Add(offset),
+				Line:      fmt.Sprintf("line %d at %s", i, offset),
+			})
+		}
+	}
+
+	add(0, 1)
+	add(time.Second, 5)
+	add(2*time.Second, 1)
+	add(3*time.Second, 3)
+	add(4*time.Second, 4)
+
+	return logs
+}
+
+func searchAllLogs(t *testing.T, inp SearchLogsInput) []StructuredLog {
+	t.Helper()
+
+	var logs []StructuredLog
+	for i := 0; i < 100; i++ {
+		res, err := SearchLogs(context.Background(), inp)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		logs = append(logs, res.Logs...)
+		if res.ContinueTime == nil {
+			return logs
+		}
+
+		if inp.Direction == LogSearchDirection_Forward {
+			inp.Start = *res.ContinueTime
+		} else {
+			inp.End = *res.ContinueTime
+		}
+		inp.Offset = res.ContinueOffset
+	}
+
+	t.Fatal("search did not finish")
+	return nil
+}
+
+func TestSearchLogsSharedTimestamps(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	logs := testLogsWithSharedTimestamps(start)
+
+	tests := map[string]struct {
+		direction  string
+		pageSize   uint
+		limit      int
+		maxScanned int
+	}{
+		"forward, pages smaller than the shared timestamps": {
+			direction: LogSearchDirection_Forward,
+			pageSize:  2,
+		},
+		"backward, pages smaller than the shared timestamps": {
+			direction: LogSearchDirection_Backward,
+			pageSize:  2,
+		},
+		"forward, searches stop within a shared timestamp": {
+			direction: LogSearchDirection_Forward,
+			pageSize:  3,
+			limit:     2,
+		},
+		"backward, searches stop within a shared timestamp": {
+			direction: LogSearchDirection_Backward,
+			pageSize:  3,
+			limit:     2,
+		},
+		"forward, searches stop after scanning a page": {
+			direction:  LogSearchDirection_Forward,
+			pageSize:   2,
+			maxScanned: 1,
+		},
+		"backward, searches stop after scanning a page": {
+			direction:  LogSearchDirection_Backward,
+			pageSize:   2,
+			maxScanned: 1,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := searchAllLogs(t, SearchLogsInput{
+				Start:      start,
+				End:        start.Add(time.Minute),
+				Direction:  tc.direction,
+				Limit:      tc.limit,
+				PageSize:   tc.pageSize,
+				MaxScanned: tc.maxScanned,
+				Fetch:      fakeLogFetcher(logs),
+			})
+
+			if len(got) != len(logs) {
+				t.Fatalf("expected %d logs, got %d", len(logs), len(got))
+			}
+
+			seen := make(map[string]bool)
+			for i, log := range got {
+				if seen[log.Line] {
+					t.Errorf("log %q was returned more than once", log.Line)
+				}
+				seen[log.Line] = true
+
+				if i == 0 {
+					continue
+				}
+
+				prev := got[i-1].Timestamp
+				if tc.direction == LogSearchDirection_Forward && log.Timestamp.Before(prev) ||
+					tc.direction == LogSearchDirection_Backward && log.Timestamp.After(prev) {
+					t.Errorf("log %q is out of order", log.Line)
+				}
+			}
+		})
+	}
+}
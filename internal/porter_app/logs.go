@@ -17,6 +17,8 @@ type StructuredLog struct {
 	AppInstanceID      string    `json:"app_instance_id"`
 	JobName            string    `json:"job_name,omitempty"`
 	JobRunID           string    `json:"job_run_id,omitempty"`
+	PodName            string    `json:"pod_name,omitempty"`
+	Level              LogLevel  `json:"level,omitempty"`
 }
 
 const (
@@ -44,6 +46,8 @@ func AgentLogToStructuredLog(rawLogs []types.LogLine) []StructuredLog {
 			JobName:            log.Metadata.RawLabels[lokiLabel_JobRunName],
 			JobRunID:           log.Metadata.RawLabels[lokiLabel_ControllerUID],
 			AppInstanceID:      log.Metadata.RawLabels[lokiLabel_AppInstanceID],
+			PodName:            log.Metadata.PodName,
+			Level:              DetectLogLevel(log.Line),
 		}
 
 		if log.Timestamp != nil {
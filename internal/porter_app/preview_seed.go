@@ -0,0 +1,62 @@
+package porter_app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// PreviewSeedCommentBody renders the pull request comment which reports the seed of a preview environment
+func PreviewSeedCommentBody(seed types.PreviewSeed, dashboardURL string) string {
+	body := "## Porter Preview Environments\n"
+
+	action := "seeded"
+	if seed.Reseed {
+		action = "re-seeded"
+	}
+
+	if seed.Succeeded {
+		body += fmt.Sprintf("🌱 The preview environment has been %s.\n", action)
+	} else {
+		body += fmt.Sprintf("❌ The preview environment could not be %s.\n", action)
+	}
+
+	body += "\n| Step | Status |\n| --- | --- |\n"
+
+	for _, step := range seed.Steps {
+		status := previewSeedStepStatusText(step)
+		if step.Reason != "" {
+			// reasons are written in a table cell, so they are kept to a single line
+			reason := strings.NewReplacer("|", "\\|", "\n", " ").Replace(step.Reason)
+			status = fmt.Sprintf("%s: %s", status, reason)
+		}
+
+		body += fmt.Sprintf("| `%s` | %s |\n", step.Name, status)
+	}
+
+	if dashboardURL != "" {
+		body += fmt.Sprintf("\nApp details available in the [Porter Dashboard](%s)", dashboardURL)
+	}
+
+	return body
+}
+
+func previewSeedStepStatusText(step types.PreviewSeedStepResult) string {
+	switch step.Status {
+	case types.PreviewSeedStepStatus_Succeeded:
+		if !step.StartedAt.IsZero() && step.CompletedAt.After(step.StartedAt) {
+			return fmt.Sprintf("✅ succeeded in %s", step.CompletedAt.Sub(step.StartedAt).Round(time.Second))
+		}
+		return "✅ succeeded"
+	case types.PreviewSeedStepStatus_Failed:
+		return "❌ failed"
+	case types.PreviewSeedStepStatus_TimedOut:
+		return "⏱️ timed out"
+	case types.PreviewSeedStepStatus_Skipped:
+		return "⏭️ skipped"
+	default:
+		return string(step.Status)
+	}
+}
@@ -0,0 +1,292 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/porter_app"
+	v2 "github.com/porter-dev/porter/internal/porter_app/v2"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// stableWebService returns the resources of a deployed web service named web of the app example-app
+func stableWebService() (*appsv1.Deployment, *v1.Service, *netv1.Ingress) {
+	labels := map[string]string{
+		porter_app.LabelKey_AppName:            "example-app",
+		porter_app.LabelKey_ServiceName:        "web",
+		porter_app.LabelKey_DeploymentTargetID: "11111111-2222-3333-4444-555555555555",
+	}
+	selector := map[string]string{"app.kubernetes.io/instance": "example-app-web"}
+
+	podLabels := map[string]string{"app.kubernetes.io/instance": "example-app-web"}
+	for k, v := range labels {
+		podLabels[k] = v
+	}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-app-web", Namespace: "default", Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{Name: "web", Image: "registry.example.com/example-app:abc123"},
+						{Name: "sidecar", Image: "registry.example.com/proxy:1.0"},
+					},
+				},
+			},
+		},
+	}
+
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-app-web", Namespace: "default", Labels: labels},
+		Spec: v1.ServiceSpec{
+			Selector: selector,
+			Ports:    []v1.ServicePort{{Name: "http", Port: 80}},
+		},
+	}
+
+	pathType := netv1.PathTypePrefix
+	className := "nginx"
+	ingress := &netv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-app-web", Namespace: "default", Labels: labels},
+		Spec: netv1.IngressSpec{
+			IngressClassName: &className,
+			Rules: []netv1.IngressRule{
+				{
+					Host: "example.com",
+					IngressRuleValue: netv1.IngressRuleValue{
+						HTTP: &netv1.HTTPIngressRuleValue{
+							Paths: []netv1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: &pathType,
+									Backend: netv1.IngressBackend{
+										Service: &netv1.IngressServiceBackend{
+											Name: "example-app-web",
+											Port: netv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return deployment, service, ingress
+}
+
+func TestCanary(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	deployment, service, ingress := stableWebService()
+	clientset := fake.NewSimpleClientset(deployment, service, ingress)
+
+	res, err := porter_app.CreateCanary(ctx, clientset, porter_app.CreateCanaryInput{
+		Namespace:          "default",
+		AppName:            "example-app",
+		ServiceName:        "web",
+		DeploymentTargetID: "11111111-2222-3333-4444-555555555555",
+		ImageRepository:    "registry.example.com/example-app",
+		ImageTag:           "def456",
+	})
+	is.NoErr(err) // canary should be created next to the stable deployment
+
+	is.Equal(res, porter_app.CanaryResources{
+		Namespace:         "default",
+		DeploymentName:    "example-app-web-canary",
+		ServiceName:       "example-app-web-canary",
+		IngressName:       "example-app-web-canary",
+		StableServiceName: "example-app-web",
+		StableIngressName: "example-app-web",
+	})
+
+	canary, err := clientset.AppsV1().Deployments("default").Get(ctx, res.DeploymentName, metav1.GetOptions{})
+	is.NoErr(err)
+	is.Equal(*canary.Spec.Replicas, int32(1))
+	is.Equal(canary.Spec.Template.Spec.Containers[0].Image, "registry.example.com/example-app:def456") // new image replaces the app image
+	is.Equal(canary.Spec.Template.Spec.Containers[1].Image, "registry.example.com/proxy:1.0")          // other containers are unchanged
+	is.Equal(canary.Spec.Template.Labels["app.kubernetes.io/instance"], "example-app-web-canary")      // stable selector no longer matches
+	is.Equal(canary.Spec.Template.Labels[porter_app.LabelKey_Canary], "true")
+
+	canaryService, err := clientset.CoreV1().Services("default").Get(ctx, res.ServiceName, metav1.GetOptions{})
+	is.NoErr(err)
+	for k, v := range canaryService.Spec.Selector {
+		is.Equal(canary.Spec.Template.Labels[k], v) // canary service selects the canary pods
+	}
+
+	err = porter_app.SetCanaryWeight(ctx, clientset, res, 10)
+	is.NoErr(err)
+
+	canaryIngress, err := clientset.NetworkingV1().Ingresses("default").Get(ctx, res.IngressName, metav1.GetOptions{})
+	is.NoErr(err)
+	is.Equal(canaryIngress.Annotations["nginx.ingress.kubernetes.io/canary"], "true")
+	is.Equal(canaryIngress.Annotations["nginx.ingress.kubernetes.io/canary-weight"], "10")
+	is.Equal(canaryIngress.Spec.Rules[0].Host, "example.com")
+	is.Equal(canaryIngress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name, res.ServiceName) // canary ingress routes to the canary service
+
+	err = porter_app.SetCanaryWeight(ctx, clientset, res, 50)
+	is.NoErr(err)
+
+	canaryIngress, err = clientset.NetworkingV1().Ingresses("default").Get(ctx, res.IngressName, metav1.GetOptions{})
+	is.NoErr(err)
+	is.Equal(canaryIngress.Annotations["nginx.ingress.kubernetes.io/canary-weight"], "50")
+
+	stableIngress, err := clientset.NetworkingV1().Ingresses("default").Get(ctx, res.StableIngressName, metav1.GetOptions{})
+	is.NoErr(err)
+	is.Equal(stableIngress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name, "example-app-web") // stable ingress is unchanged
+
+	err = porter_app.DeleteCanary(ctx, clientset, res)
+	is.NoErr(err)
+
+	_, err = clientset.AppsV1().Deployments("default").Get(ctx, res.DeploymentName, metav1.GetOptions{})
+	is.True(k8serrors.IsNotFound(err)) // canary deployment should be deleted
+	_, err = clientset.CoreV1().Services("default").Get(ctx, res.ServiceName, metav1.GetOptions{})
+	is.True(k8serrors.IsNotFound(err)) // canary service should be deleted
+	_, err = clientset.NetworkingV1().Ingresses("default").Get(ctx, res.IngressName, metav1.GetOptions{})
+	is.True(k8serrors.IsNotFound(err)) // canary ingress should be deleted
+
+	_, err = clientset.AppsV1().Deployments("default").Get(ctx, deployment.Name, metav1.GetOptions{})
+	is.NoErr(err) // stable deployment should not be deleted
+}
+
+func TestCreateCanary_replacesPreviousCanary(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	deployment, service, ingress := stableWebService()
+	clientset := fake.NewSimpleClientset(deployment, service, ingress)
+
+	inp := porter_app.CreateCanaryInput{
+		Namespace:          "default",
+		AppName:            "example-app",
+		ServiceName:        "web",
+		DeploymentTargetID: "11111111-2222-3333-4444-555555555555",
+		ImageRepository:    "registry.example.com/example-app",
+		ImageTag:           "def456",
+	}
+
+	res, err := porter_app.CreateCanary(ctx, clientset, inp)
+	is.NoErr(err)
+	is.NoErr(porter_app.SetCanaryWeight(ctx, clientset, res, 10))
+
+	inp.ImageTag = "ghi789"
+	_, err = porter_app.CreateCanary(ctx, clientset, inp)
+	is.NoErr(err) // canary resources left over from an earlier canary should be replaced
+
+	canary, err := clientset.AppsV1().Deployments("default").Get(ctx, res.DeploymentName, metav1.GetOptions{})
+	is.NoErr(err)
+	is.Equal(canary.Spec.Template.Spec.Containers[0].Image, "registry.example.com/example-app:ghi789")
+
+	_, err = clientset.NetworkingV1().Ingresses("default").Get(ctx, res.IngressName, metav1.GetOptions{})
+	is.True(k8serrors.IsNotFound(err)) // no traffic should be sent to the new canary until its weight is set
+}
+
+func TestAppCanariesFromYaml(t *testing.T) {
+	is := is.New(t)
+
+	porterYaml, err := os.ReadFile("../testdata/v2_input_canary.yaml")
+	is.NoErr(err) // no error expected reading test file
+
+	got, err := v2.AppCanariesFromYaml(context.Background(), porterYaml)
+	is.NoErr(err) // canaries on web services should be parsed without issues
+
+	is.Equal(got, []types.AppCanarySpec{
+		{
+			ServiceName: "example-web",
+			Steps: []types.AppCanaryStep{
+				{Weight: 10, DurationSeconds: 300},
+				{Weight: 50, DurationSeconds: 600},
+			},
+			Analysis: types.AppCanaryAnalysis{
+				MaxErrorRateIncrease:      0.5,
+				MaxLatencyIncreasePercent: 30,
+			},
+		},
+	})
+}
+
+func TestAppCanariesFromYaml_InvalidStep(t *testing.T) {
+	is := is.New(t)
+
+	porterYaml := []byte(`
+version: v2
+name: test-app
+services:
+  - name: example-web
+    type: web
+    run: node index.js
+    port: 8080
+    canary:
+      steps:
+        - weight: 10
+          durationSeconds: 5
+`)
+
+	_, err := v2.AppCanariesFromYaml(context.Background(), porterYaml)
+	is.True(err != nil) // steps must last long enough to be analyzed
+}
+
+func TestCreateCanary_noStableDeployment(t *testing.T) {
+	is := is.New(t)
+
+	_, err := porter_app.CreateCanary(context.Background(), fake.NewSimpleClientset(), porter_app.CreateCanaryInput{
+		Namespace:          "default",
+		AppName:            "example-app",
+		ServiceName:        "web",
+		DeploymentTargetID: "11111111-2222-3333-4444-555555555555",
+		ImageRepository:    "registry.example.com/example-app",
+		ImageTag:           "def456",
+	})
+	is.True(errors.Is(err, porter_app.ErrNoStableDeployment)) // services which are not deployed yet have nothing to compare against
+}
+
+func TestCanaryEventMetadata(t *testing.T) {
+	is := is.New(t)
+
+	canary := types.AppCanary{
+		AppRevisionID: "revision-1",
+		Image:         "registry.example.com/example-app:def456",
+		Result:        types.AppCanaryResult_Progressing,
+		Steps:         []types.AppCanaryStepResult{{Weight: 10, Passed: true}},
+	}
+	resources := porter_app.CanaryResources{
+		Namespace:         "default",
+		DeploymentName:    "example-app-web-canary",
+		ServiceName:       "example-app-web-canary",
+		IngressName:       "example-app-web-canary",
+		StableServiceName: "example-app-web",
+		StableIngressName: "example-app-web",
+	}
+
+	metadata, err := porter_app.CanaryEventMetadata(canary, resources)
+	is.NoErr(err)
+
+	// the metadata is stored as JSON, so it is decoded from its JSON representation
+	by, err := json.Marshal(metadata)
+	is.NoErr(err)
+	stored := make(map[string]any)
+	is.NoErr(json.Unmarshal(by, &stored))
+
+	decodedCanary, decodedResources, err := porter_app.CanaryFromEventMetadata(stored)
+	is.NoErr(err)
+	is.Equal(decodedCanary.AppRevisionID, canary.AppRevisionID)
+	is.Equal(decodedCanary.Result, canary.Result)
+	is.Equal(decodedCanary.Steps, canary.Steps)
+	is.Equal(decodedResources, resources) // resources are recorded so that orphaned canaries can be deleted
+}
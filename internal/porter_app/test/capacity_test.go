@@ -0,0 +1,37 @@
+package test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/porter-dev/porter/api/types"
+	v2 "github.com/porter-dev/porter/internal/porter_app/v2"
+)
+
+func TestAppCapacityFromYaml(t *testing.T) {
+	is := is.New(t)
+
+	porterYaml, err := os.ReadFile("../testdata/v2_input_no_build_no_env.yaml")
+	is.NoErr(err) // no error expected reading test file
+
+	appName, got, err := v2.AppCapacityFromYaml(context.Background(), porterYaml)
+	is.NoErr(err) // services should be converted to pod requests without issues
+
+	is.Equal(appName, "test-app")
+	is.Equal(got, []types.CapacityPodGroup{
+		{Name: "example-web", Replicas: 3, CPUMillis: 100, MemoryBytes: 256 * 1024 * 1024},
+		{Name: "example-wkr", Replicas: 1, CPUMillis: 100, MemoryBytes: 256 * 1024 * 1024},
+	}) // autoscaled services are counted at their max instances, and jobs are skipped
+}
+
+func TestAppCapacityFromYaml_v1(t *testing.T) {
+	is := is.New(t)
+
+	porterYaml, err := os.ReadFile("../testdata/v1_input_no_build_no_image.yaml")
+	is.NoErr(err) // no error expected reading test file
+
+	_, _, err = v2.AppCapacityFromYaml(context.Background(), porterYaml)
+	is.True(err != nil) // only v2 porter yaml files can be simulated
+}
@@ -0,0 +1,120 @@
+package test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	v2 "github.com/porter-dev/porter/internal/porter_app/v2"
+)
+
+func TestAppDependenciesFromYaml(t *testing.T) {
+	is := is.New(t)
+
+	porterYaml := `
+version: v2
+name: api
+services:
+  - name: web
+    type: web
+    run: node index.js
+    port: 8080
+requiredApps:
+  - name: db-proxy
+  - name: auth
+  - name: billing
+    fromTarget: production
+env:
+  - key: AUTH_URL
+    from:
+      source: app
+      name: auth
+      value: internal_domain
+      service: web
+  - key: SEARCH_URL
+    from:
+      source: app
+      name: search
+      value: public_domain
+      service: web
+  - key: LOG_LEVEL
+    value: info
+`
+
+	got, err := v2.AppDependenciesFromYaml(context.Background(), []byte(porterYaml))
+	is.NoErr(err) // required apps and env variables from other apps should be parsed without issues
+
+	is.Equal(got, v2.AppDependencies{
+		Name:      "api",
+		DependsOn: []string{"auth", "db-proxy", "search"},
+	})
+}
+
+func TestAppDependenciesFromYaml_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"not v2": `
+version: v1stack
+name: api
+`,
+		"no name": `
+version: v2
+services:
+  - name: web
+    type: web
+    run: node index.js
+`,
+	}
+
+	for name, porterYaml := range tests {
+		t.Run(name, func(t *testing.T) {
+			is := is.New(t)
+
+			_, err := v2.AppDependenciesFromYaml(context.Background(), []byte(porterYaml))
+			is.True(err != nil) // an app without a name cannot be ordered
+		})
+	}
+}
+
+func TestDeployLayers(t *testing.T) {
+	is := is.New(t)
+
+	got, err := v2.DeployLayers([]v2.AppDependencies{
+		{Name: "frontend", DependsOn: []string{"api", "auth"}},
+		{Name: "api", DependsOn: []string{"auth", "db-proxy", "external"}},
+		{Name: "auth", DependsOn: []string{"db-proxy"}},
+		{Name: "db-proxy"},
+		{Name: "worker"},
+	})
+	is.NoErr(err) // apps without a cycle should be ordered
+
+	is.Equal(got, [][]string{
+		{"db-proxy", "worker"},
+		{"auth"},
+		{"api"},
+		{"frontend"},
+	})
+}
+
+func TestDeployLayers_Cycle(t *testing.T) {
+	is := is.New(t)
+
+	_, err := v2.DeployLayers([]v2.AppDependencies{
+		{Name: "frontend", DependsOn: []string{"api"}},
+		{Name: "api", DependsOn: []string{"auth"}},
+		{Name: "auth", DependsOn: []string{"api"}},
+		{Name: "worker"},
+	})
+	is.True(err != nil) // a dependency cycle cannot be ordered
+	is.True(strings.Contains(err.Error(), "api -> auth -> api"))
+}
+
+func TestDeployLayers_Duplicate(t *testing.T) {
+	is := is.New(t)
+
+	_, err := v2.DeployLayers([]v2.AppDependencies{
+		{Name: "api"},
+		{Name: "api"},
+	})
+	is.True(err != nil) // an app cannot be deployed twice in the same apply
+}
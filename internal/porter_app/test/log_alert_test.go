@@ -0,0 +1,91 @@
+package test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/porter_app"
+)
+
+func TestLogAlertPodSelector(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(porter_app.LogAlertPodSelector("example-app", "web"), "example-app-web-.*")
+	is.Equal(porter_app.LogAlertPodSelector("example-app", ""), "example-app-.*")
+
+	selector := porter_app.LogAlertPodSelector(strings.Repeat("a", 40), strings.Repeat("b", 40))
+	is.Equal(len(strings.TrimSuffix(selector, ".*")), 58) // long prefixes are truncated like pod names
+}
+
+func TestEvaluateLogAlertRule(t *testing.T) {
+	is := is.New(t)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// one panic every ten seconds over the last ten minutes
+	fetcher := &pagedLogFetcher{}
+	for i := 600; i > 0; i-- {
+		line := "INFO ok"
+		if i%10 == 0 {
+			line = "panic: nil map " + strings.Repeat("x", 600)
+		}
+		fetcher.logs = append(fetcher.logs, porter_app.StructuredLog{
+			Timestamp: now.Add(-time.Duration(i) * time.Second),
+			Line:      line,
+		})
+	}
+
+	query, err := porter_app.ParseLogQuery(`"panic:"`)
+	is.NoErr(err)
+
+	// 30 panics in the default five minute window
+	res, err := porter_app.EvaluateLogAlertRule(context.Background(), porter_app.EvaluateLogAlertRuleInput{
+		Query:     query,
+		Threshold: 10,
+		Now:       now,
+		Fetch:     fetcher.fetch,
+	})
+	is.NoErr(err)
+	is.True(res.Firing)
+	is.Equal(res.MatchCount, uint(11)) // counting stops past the threshold
+	is.Equal(len(res.SampleLines), 5)
+	is.True(strings.HasSuffix(res.SampleLines[0], "...")) // long lines are truncated
+
+	res, err = porter_app.EvaluateLogAlertRule(context.Background(), porter_app.EvaluateLogAlertRuleInput{
+		Query:     query,
+		Threshold: 30,
+		Now:       now,
+		Fetch:     fetcher.fetch,
+	})
+	is.NoErr(err)
+	is.True(!res.Firing)
+	is.Equal(res.MatchCount, uint(30))
+
+	res, err = porter_app.EvaluateLogAlertRule(context.Background(), porter_app.EvaluateLogAlertRuleInput{
+		Query:     query,
+		Threshold: 30,
+		Window:    10 * time.Minute,
+		Now:       now,
+		Fetch:     fetcher.fetch,
+	})
+	is.NoErr(err)
+	is.True(res.Firing)
+}
+
+func TestShouldNotifyLogAlert(t *testing.T) {
+	is := is.New(t)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	recently := now.Add(-10 * time.Minute)
+	longAgo := now.Add(-2 * time.Hour)
+
+	is.True(!porter_app.ShouldNotifyLogAlert(types.AppLogAlertState_OK, nil, false, now))
+	is.True(porter_app.ShouldNotifyLogAlert("", nil, true, now))                                   // first evaluation
+	is.True(porter_app.ShouldNotifyLogAlert(types.AppLogAlertState_OK, &recently, true, now))      // started firing again
+	is.True(!porter_app.ShouldNotifyLogAlert(types.AppLogAlertState_Firing, &recently, true, now)) // already notified
+	is.True(porter_app.ShouldNotifyLogAlert(types.AppLogAlertState_Firing, &longAgo, true, now))   // repeat notification
+}
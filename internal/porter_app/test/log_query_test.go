@@ -0,0 +1,260 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/porter-dev/porter/internal/porter_app"
+)
+
+func TestDetectLogLevel(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		line string
+		want porter_app.LogLevel
+	}{
+		{`{"level":"warning","msg":"slow query"}`, porter_app.LogLevel_Warn},
+		{`{"level":50,"msg":"request failed"}`, porter_app.LogLevel_Error},
+		{`{"severity":"INFO"}`, porter_app.LogLevel_Info},
+		{`time=2024-01-01T00:00:00Z level=debug msg="cache miss"`, porter_app.LogLevel_Debug},
+		{`2024/01/01 00:00:00 PANIC: runtime error`, porter_app.LogLevel_Fatal},
+		{`GET /healthz 200`, ""},
+		{`{"msg":"no level"}`, ""},
+	}
+
+	for _, tt := range tests {
+		is.Equal(porter_app.DetectLogLevel(tt.line), tt.want) // tt.line
+	}
+}
+
+func TestParseLogQuery_Errors(t *testing.T) {
+	is := is.New(t)
+
+	for _, query := range []string{
+		`/unterminated`,
+		`"unterminated`,
+		`/[a-/`,
+		`level:loud`,
+		`status:>=fast`,
+		`pod:>web`,
+		`service:`,
+	} {
+		_, err := porter_app.ParseLogQuery(query)
+		is.True(err != nil) // query should not parse
+	}
+}
+
+func TestLogQuery_Match(t *testing.T) {
+	is := is.New(t)
+
+	jsonLog := porter_app.StructuredLog{
+		Line:          `{"level":"error","msg":"request failed","status":503,"user":{"id":"u-123"},"duration":"1.5"}`,
+		ServiceName:   "web",
+		PodName:       "example-app-web-7d9f-abc",
+		OutputStream:  "stdout",
+		AppRevisionID: "rev-2",
+	}
+	textLog := porter_app.StructuredLog{
+		Line:          `WARN connection refused to http://db:5432`,
+		ServiceName:   "worker",
+		PodName:       "example-app-worker-5c4b-def",
+		OutputStream:  "stderr",
+		AppRevisionID: "rev-1",
+	}
+
+	tests := []struct {
+		query     string
+		matchJSON bool
+		matchText bool
+	}{
+		{``, true, true},
+		{`request`, true, false},
+		{`"connection refused"`, false, true},
+		{`-healthcheck`, true, true},
+		{`-request`, false, true},
+		{`/status":5\d\d/`, true, false},
+		{`/http:\/\/db/`, false, true},
+		{`http://db`, false, true},
+		{`level:error`, true, false},
+		{`level:>=warn`, true, true},
+		{`level:<error`, false, true},
+		{`-level:debug`, true, true},
+		{`pod:example-app-web-*`, true, false},
+		{`service:worker`, false, true},
+		{`stream:stderr`, false, true},
+		{`revision:rev-2`, true, false},
+		{`status:503`, true, false},
+		{`status:5*`, true, false},
+		{`status:>=500 status:<600`, true, false},
+		{`status:>600`, false, false},
+		{`duration:>1`, true, false},
+		{`user.id:u-*`, true, false},
+		{`user.id:*`, true, false},
+		{`-user.id:*`, false, true},
+		{`msg:"request failed"`, true, false},
+		{`level:error service:web -timeout`, true, false},
+	}
+
+	for _, tt := range tests {
+		query, err := porter_app.ParseLogQuery(tt.query)
+		is.NoErr(err)
+
+		is.Equal(query.Match(jsonLog), tt.matchJSON) // tt.query against the JSON log
+		is.Equal(query.Match(textLog), tt.matchText) // tt.query against the text log
+	}
+}
+
+func TestLogQuery_Pushdown(t *testing.T) {
+	is := is.New(t)
+
+	query, err := porter_app.ParseLogQuery(`-debug service:web "connection refused" revision:42 level:error`)
+	is.NoErr(err)
+
+	is.Equal(query.SearchParam(), "connection refused")
+	is.Equal(query.ServiceName(), "web")
+	is.Equal(query.AppRevisionID(), "") // revision numbers must be resolved first
+	is.Equal(query.RevisionNumbers(), []uint64{42})
+
+	err = query.ResolveRevisionNumbers(map[uint64]string{41: "rev-41"})
+	is.True(err != nil) // revision 42 does not exist
+
+	err = query.ResolveRevisionNumbers(map[uint64]string{42: "rev-42"})
+	is.NoErr(err)
+	is.Equal(query.AppRevisionID(), "rev-42")
+	is.True(query.Match(porter_app.StructuredLog{
+		Line:          "ERROR connection refused",
+		ServiceName:   "web",
+		AppRevisionID: "rev-42",
+	}))
+}
+
+// pagedLogFetcher serves logs one page at a time, recording the ranges which were requested
+type pagedLogFetcher struct {
+	logs     []porter_app.StructuredLog
+	requests []porter_app.LogFetchInput
+}
+
+func (f *pagedLogFetcher) fetch(_ context.Context, inp porter_app.LogFetchInput) ([]porter_app.StructuredLog, error) {
+	f.requests = append(f.requests, inp)
+
+	var page []porter_app.StructuredLog
+	if inp.Direction == porter_app.LogSearchDirection_Forward {
+		for i := 0; i < len(f.logs) && uint(len(page)) < inp.Limit; i++ {
+			if !f.logs[i].Timestamp.Before(inp.Start) && f.logs[i].Timestamp.Before(inp.End) {
+				page = append(page, f.logs[i])
+			}
+		}
+		return page, nil
+	}
+
+	for i := len(f.logs) - 1; i >= 0 && uint(len(page)) < inp.Limit; i-- {
+		if !f.logs[i].Timestamp.Before(inp.Start) && f.logs[i].Timestamp.Before(inp.End) {
+			page = append(page, f.logs[i])
+		}
+	}
+	return page, nil
+}
+
+func TestSearchLogs(t *testing.T) {
+	is := is.New(t)
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// one error every ten lines
+	fetcher := &pagedLogFetcher{}
+	for i := 0; i < 100; i++ {
+		line := "INFO ok"
+		if i%10 == 0 {
+			line = "ERROR failed"
+		}
+		fetcher.logs = append(fetcher.logs, porter_app.StructuredLog{
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			Line:      line,
+		})
+	}
+
+	query, err := porter_app.ParseLogQuery("level:error")
+	is.NoErr(err)
+
+	res, err := porter_app.SearchLogs(context.Background(), porter_app.SearchLogsInput{
+		Query:     query,
+		Start:     start,
+		End:       start.Add(time.Hour),
+		Direction: porter_app.LogSearchDirection_Forward,
+		PageSize:  15,
+		Fetch:     fetcher.fetch,
+	})
+	is.NoErr(err)
+	is.Equal(len(res.Logs), 10)
+	is.Equal(res.Scanned, 100)
+	is.Equal(res.ContinueTime, nil)    // the whole range was searched
+	is.Equal(len(fetcher.requests), 7) // six full pages and a partial one
+	is.Equal(fetcher.requests[1].Start, start.Add(14*time.Second+time.Nanosecond))
+
+	// a limited backward search can be resumed from its continue time
+	fetcher.requests = nil
+	res, err = porter_app.SearchLogs(context.Background(), porter_app.SearchLogsInput{
+		Query:    query,
+		Start:    start,
+		End:      start.Add(time.Hour),
+		Limit:    3,
+		PageSize: 15,
+		Fetch:    fetcher.fetch,
+	})
+	is.NoErr(err)
+	is.Equal(len(res.Logs), 3)
+	is.Equal(res.Logs[0].Timestamp, start.Add(90*time.Second)) // newest first
+	is.Equal(*res.ContinueTime, start.Add(70*time.Second))
+
+	res, err = porter_app.SearchLogs(context.Background(), porter_app.SearchLogsInput{
+		Query:    query,
+		Start:    start,
+		End:      *res.ContinueTime,
+		PageSize: 15,
+		Fetch:    fetcher.fetch,
+	})
+	is.NoErr(err)
+	is.Equal(len(res.Logs), 7)
+
+	// the scan limit stops the search with a continue time
+	res, err = porter_app.SearchLogs(context.Background(), porter_app.SearchLogsInput{
+		Query:      query,
+		Start:      start,
+		End:        start.Add(time.Hour),
+		Direction:  porter_app.LogSearchDirection_Forward,
+		PageSize:   15,
+		MaxScanned: 30,
+		Fetch:      fetcher.fetch,
+	})
+	is.NoErr(err)
+	is.Equal(len(res.Logs), 3)
+	is.Equal(res.Scanned, 30)
+	is.Equal(*res.ContinueTime, start.Add(29*time.Second+time.Nanosecond))
+}
+
+func TestWriteLogs(t *testing.T) {
+	is := is.New(t)
+
+	logs := []porter_app.StructuredLog{{
+		Timestamp:   time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		Line:        `ERROR "quoted", with comma`,
+		ServiceName: "web",
+		PodName:     "web-abc",
+		Level:       porter_app.LogLevel_Error,
+	}}
+
+	var csvOut bytes.Buffer
+	err := porter_app.WriteLogsCSV(&csvOut, logs, true)
+	is.NoErr(err)
+	is.Equal(csvOut.String(), "timestamp,service_name,pod_name,level,output_stream,app_revision_id,job_run_id,line\n"+
+		`2024-01-01T12:00:00Z,web,web-abc,error,,,,"ERROR ""quoted"", with comma"`+"\n")
+
+	var ndjsonOut bytes.Buffer
+	err = porter_app.WriteLogsNDJSON(&ndjsonOut, append(logs, logs...))
+	is.NoErr(err)
+	is.Equal(bytes.Count(ndjsonOut.Bytes(), []byte("\n")), 2)
+}
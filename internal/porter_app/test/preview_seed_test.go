@@ -0,0 +1,162 @@
+package test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/porter_app"
+	v2 "github.com/porter-dev/porter/internal/porter_app/v2"
+)
+
+func TestAppPreviewSeedFromYaml(t *testing.T) {
+	is := is.New(t)
+
+	porterYaml, err := os.ReadFile("../testdata/v2_input_preview_seed.yaml")
+	is.NoErr(err) // no error expected reading test file
+
+	got, err := v2.AppPreviewSeedFromYaml(context.Background(), porterYaml)
+	is.NoErr(err) // seed steps which run jobs of the app or its previews should be parsed without issues
+
+	is.Equal(got, []types.PreviewSeedStep{
+		{
+			Name:            "clone-staging",
+			Type:            types.PreviewSeedStepType_CloneDatastore,
+			SourceDatastore: "staging-db",
+			TimeoutSeconds:  1800,
+		},
+		{
+			Name:           "load-dump",
+			Type:           types.PreviewSeedStepType_Job,
+			JobName:        "load-fixtures",
+			RunCommand:     "./load.sh s3://fixtures/dump.sql",
+			TimeoutSeconds: 900,
+		},
+		{
+			Name:           "migrate",
+			Type:           types.PreviewSeedStepType_Job,
+			JobName:        "migrate",
+			TimeoutSeconds: 600,
+		},
+	})
+}
+
+func TestAppPreviewSeedFromYaml_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"not a job": `
+version: v2
+name: test-app
+services:
+  - name: example-web
+    type: web
+    run: node index.js
+previews:
+  seed:
+    - name: seed
+      job: example-web
+`,
+		"unknown job": `
+version: v2
+name: test-app
+previews:
+  seed:
+    - name: seed
+      job: load-fixtures
+`,
+		"job and clone": `
+version: v2
+name: test-app
+services:
+  - name: load-fixtures
+    type: job
+    run: ./load.sh
+previews:
+  seed:
+    - name: seed
+      job: load-fixtures
+      cloneDatastore:
+        from: staging-db
+`,
+		"duplicate name": `
+version: v2
+name: test-app
+previews:
+  seed:
+    - name: seed
+      cloneDatastore:
+        from: staging-db
+    - name: seed
+      cloneDatastore:
+        from: other-db
+`,
+		"timeout too long": `
+version: v2
+name: test-app
+previews:
+  seed:
+    - name: seed
+      timeoutSeconds: 7200
+      cloneDatastore:
+        from: staging-db
+`,
+	}
+
+	for name, porterYaml := range tests {
+		t.Run(name, func(t *testing.T) {
+			is := is.New(t)
+
+			_, err := v2.AppPreviewSeedFromYaml(context.Background(), []byte(porterYaml))
+			is.True(err != nil) // invalid seed steps should fail to parse
+		})
+	}
+}
+
+func TestAppPreviewSeedFromYaml_V1(t *testing.T) {
+	is := is.New(t)
+
+	porterYaml, err := os.ReadFile("../testdata/v1_input_no_build_no_image.yaml")
+	is.NoErr(err) // no error expected reading test file
+
+	got, err := v2.AppPreviewSeedFromYaml(context.Background(), porterYaml)
+	is.NoErr(err)         // v1 porter yaml should not fail to parse
+	is.Equal(len(got), 0) // v1 porter yaml does not support seeding
+}
+
+func TestPreviewSeedCommentBody(t *testing.T) {
+	is := is.New(t)
+
+	started := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	body := porter_app.PreviewSeedCommentBody(types.PreviewSeed{
+		Succeeded: false,
+		Steps: []types.PreviewSeedStepResult{
+			{Name: "clone-staging", Status: types.PreviewSeedStepStatus_Succeeded, StartedAt: started, CompletedAt: started.Add(95 * time.Second)},
+			{Name: "load-dump", Status: types.PreviewSeedStepStatus_TimedOut, Reason: "did not complete within 900 seconds"},
+			{Name: "migrate", Status: types.PreviewSeedStepStatus_Skipped, Reason: "step load-dump did not succeed"},
+		},
+	}, "https://dashboard.porter.run/preview-environments/apps/test-app?target=abc")
+
+	is.Equal(body, "## Porter Preview Environments\n"+
+		"❌ The preview environment could not be seeded.\n"+
+		"\n| Step | Status |\n| --- | --- |\n"+
+		"| `clone-staging` | ✅ succeeded in 1m35s |\n"+
+		"| `load-dump` | ⏱️ timed out: did not complete within 900 seconds |\n"+
+		"| `migrate` | ⏭️ skipped: step load-dump did not succeed |\n"+
+		"\nApp details available in the [Porter Dashboard](https://dashboard.porter.run/preview-environments/apps/test-app?target=abc)")
+
+	body = porter_app.PreviewSeedCommentBody(types.PreviewSeed{
+		Reseed:    true,
+		Succeeded: true,
+		Steps: []types.PreviewSeedStepResult{
+			{Name: "load-dump", Status: types.PreviewSeedStepStatus_Succeeded, Reason: "a | b\nc"},
+		},
+	}, "")
+
+	is.Equal(body, "## Porter Preview Environments\n"+
+		"🌱 The preview environment has been re-seeded.\n"+
+		"\n| Step | Status |\n| --- | --- |\n"+
+		"| `load-dump` | ✅ succeeded: a \\| b c |\n")
+}
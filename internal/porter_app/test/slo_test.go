@@ -0,0 +1,68 @@
+package test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/porter-dev/porter/api/types"
+	v2 "github.com/porter-dev/porter/internal/porter_app/v2"
+)
+
+func TestAppSLOsFromYaml(t *testing.T) {
+	is := is.New(t)
+
+	porterYaml, err := os.ReadFile("../testdata/v2_input_slos.yaml")
+	is.NoErr(err) // no error expected reading test file
+
+	got, err := v2.AppSLOsFromYaml(context.Background(), porterYaml)
+	is.NoErr(err) // slos on web services should be parsed without issues
+
+	is.Equal(got, []types.AppSLOSpec{
+		{
+			Name:        "example-web-availability",
+			ServiceName: "example-web",
+			Type:        types.AppSLOType_Availability,
+			Target:      99.9,
+		},
+		{
+			Name:               "checkout-latency",
+			ServiceName:        "example-web",
+			Type:               types.AppSLOType_Latency,
+			Target:             95,
+			LatencyThresholdMs: 300,
+			WindowDays:         7,
+		},
+	})
+}
+
+func TestAppSLOsFromYaml_NonWebService(t *testing.T) {
+	is := is.New(t)
+
+	porterYaml := []byte(`
+version: v2
+name: test-app
+services:
+  - name: example-wkr
+    type: worker
+    run: echo 'work'
+    slos:
+      - type: availability
+        target: 99.9
+`)
+
+	_, err := v2.AppSLOsFromYaml(context.Background(), porterYaml)
+	is.True(err != nil) // slos are only supported on web services
+}
+
+func TestAppSLOsFromYaml_V1(t *testing.T) {
+	is := is.New(t)
+
+	porterYaml, err := os.ReadFile("../testdata/v1_input_no_build_no_image.yaml")
+	is.NoErr(err) // no error expected reading test file
+
+	got, err := v2.AppSLOsFromYaml(context.Background(), porterYaml)
+	is.NoErr(err)         // v1 porter yaml should not fail to parse
+	is.Equal(len(got), 0) // v1 porter yaml does not support slos
+}
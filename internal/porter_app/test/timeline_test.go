@@ -0,0 +1,263 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	v2 "github.com/porter-dev/porter/internal/porter_app/v2"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// timelineApp returns a version of an app with a web and a worker service
+func timelineApp(tag string, webRamMegabytes int, workerInstances int32) v2.PorterApp {
+	return v2.PorterApp{
+		Name:  "example-app",
+		Image: &v2.Image{Repository: "registry.example.com/example-app", Tag: tag},
+		Services: []v2.Service{
+			{Name: "web", Type: v2.ServiceType_Web, RamMegabytes: webRamMegabytes, CpuCores: 0.5, Port: 8080},
+			{Name: "worker", Type: v2.ServiceType_Worker, RamMegabytes: 512, CpuCores: 0.25, Instances: &workerInstances},
+		},
+	}
+}
+
+func TestRevisionTimelineEntries(t *testing.T) {
+	is := is.New(t)
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	entries := porter_app.RevisionTimelineEntries([]porter_app.TimelineRevision{
+		{RevisionNumber: 3, Status: models.AppRevisionStatus_InstallSuccessful, CreatedAt: start.Add(2 * time.Hour), App: timelineApp("v2", 256, 4)},
+		{RevisionNumber: 1, Status: models.AppRevisionStatus_InstallSuccessful, CreatedAt: start, App: timelineApp("v1", 1024, 2)},
+		{RevisionNumber: 2, Status: models.AppRevisionStatus_BuildFailed, CreatedAt: start.Add(time.Hour), App: timelineApp("v2", 512, 2)},
+	})
+	is.Equal(len(entries), 3)
+
+	is.Equal(entries[0].RevisionNumber, uint64(1))
+	is.Equal(entries[0].Changes, []types.AppTimelineChange{
+		{ServiceName: "web", Description: "added service web"},
+		{ServiceName: "worker", Description: "added service worker"},
+	})
+
+	is.Equal(entries[1].Severity, types.AppTimelineSeverity_Warning) // failed revisions are warnings
+
+	// revision 3 is compared against revision 1, since revision 2 was never deployed
+	is.Equal(entries[2].Changes, []types.AppTimelineChange{
+		{Description: "changed image tag from v1 to v2"},
+		{ServiceName: "web", Description: "lowered ram of web from 1024MB to 256MB"},
+		{ServiceName: "worker", Description: "raised instances of worker from 2 to 4"},
+	})
+}
+
+func TestBuildTimeline(t *testing.T) {
+	is := is.New(t)
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	revisions := porter_app.RevisionTimelineEntries([]porter_app.TimelineRevision{
+		{RevisionNumber: 41, Status: models.AppRevisionStatus_InstallSuccessful, CreatedAt: start, App: timelineApp("v1", 1024, 2)},
+		{RevisionNumber: 42, Status: models.AppRevisionStatus_InstallSuccessful, CreatedAt: start.Add(time.Hour), App: timelineApp("v1", 512, 2)},
+		{RevisionNumber: 43, Status: models.AppRevisionStatus_InstallSuccessful, CreatedAt: start.Add(time.Hour + time.Minute), App: timelineApp("v1", 512, 3)},
+	})
+
+	entries := append(revisions,
+		types.AppTimelineEntry{
+			Type:        types.AppTimelineEntryType_KubernetesEvent,
+			Severity:    types.AppTimelineSeverity_Warning,
+			Time:        start.Add(time.Hour + 3*time.Minute),
+			ServiceName: "web",
+			Reason:      "OOMKilled",
+		},
+		types.AppTimelineEntry{
+			Type:        types.AppTimelineEntryType_KubernetesEvent,
+			Severity:    types.AppTimelineSeverity_Warning,
+			Time:        start.Add(5 * time.Hour),
+			ServiceName: "web",
+			Reason:      "BackOff",
+		},
+		types.AppTimelineEntry{
+			Type:        types.AppTimelineEntryType_Scaling,
+			Severity:    types.AppTimelineSeverity_Normal,
+			Time:        start.Add(time.Hour + 2*time.Minute),
+			ServiceName: "worker",
+			Reason:      "SuccessfulRescale",
+		},
+	)
+
+	timeline := porter_app.BuildTimeline(entries, porter_app.TimelineFilter{})
+	is.Equal(len(timeline), 6)
+
+	for i := 1; i < len(timeline); i++ {
+		is.True(!timeline[i].Time.Before(timeline[i-1].Time)) // entries are ordered from oldest to newest
+	}
+
+	// revision 43 is more recent, but did not change web
+	is.Equal(timeline[4].Hint, "OOMKilled 3m after revision 42 lowered ram of web from 1024MB to 512MB")
+	// warnings long after a revision are not attributed to it
+	is.Equal(timeline[5].Hint, "")
+	// only warnings are attributed to revisions
+	is.Equal(timeline[3].Hint, "")
+
+	filtered := porter_app.BuildTimeline(entries, porter_app.TimelineFilter{
+		ServiceName: "web",
+		Start:       start.Add(30 * time.Minute),
+		End:         start.Add(2 * time.Hour),
+	})
+	is.Equal(len(filtered), 3) // revisions 42 and 43, and the OOMKilled event
+	is.Equal(filtered[2].Hint, "OOMKilled 3m after revision 42 lowered ram of web from 1024MB to 512MB")
+}
+
+func TestKubernetesTimelineEntries(t *testing.T) {
+	is := is.New(t)
+
+	deploymentTargetID := "11111111-2222-3333-4444-555555555555"
+	labels := func(serviceName string) map[string]string {
+		return map[string]string{
+			porter_app.LabelKey_AppName:            "example-app",
+			porter_app.LabelKey_ServiceName:        serviceName,
+			porter_app.LabelKey_DeploymentTargetID: deploymentTargetID,
+		}
+	}
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	event := func(name, kind, objectName, eventType, reason string) *v1.Event {
+		return &v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+			InvolvedObject: v1.ObjectReference{Kind: kind, Name: objectName, Namespace: "default"},
+			Type:           eventType,
+			Reason:         reason,
+			LastTimestamp:  metav1.NewTime(now),
+			Count:          2,
+		}
+	}
+
+	clientset := fake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "example-app-web", Namespace: "default", Labels: labels("web")}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "example-app-web-admin", Namespace: "default", Labels: labels("web-admin")}},
+		&autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "example-app-web-hpa", Namespace: "default"},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "example-app-web"},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "example-app-web-abc-123", Namespace: "default", Labels: labels("web")},
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{{
+					Name: "web",
+					LastTerminationState: v1.ContainerState{
+						Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", FinishedAt: metav1.NewTime(now)},
+					},
+				}},
+			},
+		},
+		event("backoff", "Pod", "example-app-web-admin-def-456", v1.EventTypeWarning, "BackOff"),
+		event("rescale", "HorizontalPodAutoscaler", "example-app-web-hpa", v1.EventTypeNormal, "SuccessfulRescale"),
+		event("pulled", "Pod", "example-app-web-abc-123", v1.EventTypeNormal, "Pulled"),
+		event("other-app", "Pod", "other-app-web-abc-123", v1.EventTypeWarning, "FailedScheduling"),
+	)
+
+	entries, err := porter_app.KubernetesTimelineEntries(context.Background(), clientset, porter_app.KubernetesTimelineInput{
+		Namespace:          "default",
+		AppName:            "example-app",
+		DeploymentTargetID: deploymentTargetID,
+		Incidents: []*types.IncidentMeta{{
+			InvolvedObjectKind: types.InvolvedObjectDeployment,
+			InvolvedObjectName: "example-app-web",
+			Status:             types.IncidentStatusActive,
+			Summary:            "The application is crashing",
+			CreatedAt:          now,
+		}},
+	})
+	is.NoErr(err)
+
+	byReason := make(map[string]types.AppTimelineEntry)
+	for _, entry := range entries {
+		byReason[entry.Reason] = entry
+	}
+	is.Equal(len(byReason), 4) // normal events other than rescales, and events of other apps, are skipped
+
+	is.Equal(byReason["BackOff"].ServiceName, "web-admin") // pods are matched to the longest deployment name
+	is.Equal(byReason["BackOff"].Count, int32(2))
+	is.Equal(byReason["SuccessfulRescale"].Type, types.AppTimelineEntryType_Scaling)
+	is.Equal(byReason["SuccessfulRescale"].ServiceName, "web")
+	is.Equal(byReason["OOMKilled"].ServiceName, "web")
+	is.Equal(byReason["OOMKilled"].Severity, types.AppTimelineSeverity_Warning)
+	is.Equal(byReason["active"].Type, types.AppTimelineEntryType_Incident)
+	is.Equal(byReason["active"].ServiceName, "web")
+}
+
+func TestKubernetesTimelineEntriesFromAgent(t *testing.T) {
+	is := is.New(t)
+
+	deploymentTargetID := "11111111-2222-3333-4444-555555555555"
+	labels := map[string]string{
+		porter_app.LabelKey_AppName:            "example-app",
+		porter_app.LabelKey_ServiceName:        "web",
+		porter_app.LabelKey_DeploymentTargetID: deploymentTargetID,
+	}
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	// kubernetes has long since dropped this event, but the porter agent still has it
+	historical := v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "backoff", Namespace: "default"},
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "example-app-web-abc-123", Namespace: "default"},
+		Type:           v1.EventTypeWarning,
+		Reason:         "BackOff",
+		LastTimestamp:  metav1.NewTime(now.Add(-20 * time.Hour)),
+	}
+
+	historicalJSON, err := json.Marshal(historical)
+	is.NoErr(err)
+
+	lines := []types.KubernetesEventLine{
+		{Timestamp: &now, Event: string(historicalJSON)},
+		{Timestamp: &now, Event: "not an event"},
+	}
+
+	clientset := fake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "example-app-web", Namespace: "default", Labels: labels}},
+		// events which the cluster holds are not read when the porter agent is installed
+		&v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "unhealthy", Namespace: "default"},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "example-app-web-abc-123", Namespace: "default"},
+			Type:           v1.EventTypeWarning,
+			Reason:         "Unhealthy",
+			LastTimestamp:  metav1.NewTime(now),
+		},
+	)
+
+	input := porter_app.KubernetesTimelineInput{
+		Namespace:          "default",
+		AppName:            "example-app",
+		DeploymentTargetID: deploymentTargetID,
+		FromAgent:          true,
+		HistoricalEvents:   porter_app.AgentKubernetesEvents(lines),
+	}
+
+	entries, err := porter_app.KubernetesTimelineEntries(context.Background(), clientset, input)
+	is.NoErr(err)
+	is.Equal(len(entries), 1) // lines which are not events are skipped
+	is.Equal(entries[0].Reason, "BackOff")
+	is.Equal(entries[0].ServiceName, "web")
+	is.True(entries[0].Time.Equal(now.Add(-20 * time.Hour)))
+
+	// without the porter agent, the events which the cluster holds are read instead
+	input.FromAgent = false
+	input.HistoricalEvents = nil
+
+	entries, err = porter_app.KubernetesTimelineEntries(context.Background(), clientset, input)
+	is.NoErr(err)
+	is.Equal(len(entries), 1)
+	is.Equal(entries[0].Reason, "Unhealthy")
+}
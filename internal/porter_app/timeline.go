@@ -0,0 +1,603 @@
+package porter_app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	v2 "github.com/porter-dev/porter/internal/porter_app/v2"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/release"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+const (
+	// timelineCorrelationWindow is how long after a revision is applied that a warning may be attributed to it
+	timelineCorrelationWindow = time.Hour
+	// maxHintChanges is the number of changes of a revision listed in a hint
+	maxHintChanges = 3
+
+	reasonOOMKilled         = "OOMKilled"
+	reasonSuccessfulRescale = "SuccessfulRescale"
+)
+
+// TimelineRevision is a revision of an app, as shown on its timeline
+type TimelineRevision struct {
+	RevisionNumber uint64
+	Status         models.AppRevisionStatus
+	CreatedAt      time.Time
+	App            v2.PorterApp
+}
+
+// TimelineRevisionFromProto converts a revision returned by the cluster control plane to a timeline revision
+func TimelineRevisionFromProto(appRevision *porterv1.AppRevision) (TimelineRevision, error) {
+	var revision TimelineRevision
+
+	if appRevision == nil || appRevision.App == nil {
+		return revision, errors.New("app revision is nil")
+	}
+
+	app, err := v2.AppFromProto(appRevision.App)
+	if err != nil {
+		return revision, fmt.Errorf("error converting revision %d to app: %w", appRevision.RevisionNumber, err)
+	}
+
+	// unknown statuses are kept as AppRevisionStatus_Unknown, as in EncodedRevisionFromProto
+	status, _ := appRevisionStatusFromProto(appRevision.Status)
+
+	return TimelineRevision{
+		RevisionNumber: appRevision.RevisionNumber,
+		Status:         status,
+		CreatedAt:      appRevision.CreatedAt.AsTime(),
+		App:            app,
+	}, nil
+}
+
+// failedRevisionStatuses are the statuses of revisions which were never rolled out
+var failedRevisionStatuses = map[models.AppRevisionStatus]bool{
+	models.AppRevisionStatus_BuildCanceled:   true,
+	models.AppRevisionStatus_BuildFailed:     true,
+	models.AppRevisionStatus_PredeployFailed: true,
+	models.AppRevisionStatus_InstallFailed:   true,
+}
+
+// RevisionTimelineEntries returns an entry for each revision, along with the changes it made. Changes are compared
+// against the last revision that was deployed before it, since revisions which failed were never rolled out.
+func RevisionTimelineEntries(revisions []TimelineRevision) []types.AppTimelineEntry {
+	sorted := make([]TimelineRevision, len(revisions))
+	copy(sorted, revisions)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].RevisionNumber < sorted[j].RevisionNumber })
+
+	var deployed *v2.PorterApp
+	entries := make([]types.AppTimelineEntry, 0, len(sorted))
+
+	for i := range sorted {
+		revision := sorted[i]
+
+		entry := types.AppTimelineEntry{
+			Type:           types.AppTimelineEntryType_Revision,
+			Severity:       types.AppTimelineSeverity_Normal,
+			Time:           revision.CreatedAt,
+			Reason:         string(revision.Status),
+			Message:        fmt.Sprintf("revision %d applied", revision.RevisionNumber),
+			RevisionNumber: revision.RevisionNumber,
+			Changes:        RevisionChanges(deployed, revision.App),
+		}
+
+		if failedRevisionStatuses[revision.Status] {
+			entry.Severity = types.AppTimelineSeverity_Warning
+			entry.Message = fmt.Sprintf("revision %d failed with status %s", revision.RevisionNumber, revision.Status)
+		}
+
+		if revision.Status == models.AppRevisionStatus_InstallSuccessful {
+			deployed = &sorted[i].App
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// RevisionChanges describes the changes between two versions of an app. If prev is nil, every service of curr is
+// described as added.
+func RevisionChanges(prev *v2.PorterApp, curr v2.PorterApp) []types.AppTimelineChange {
+	var changes []types.AppTimelineChange
+
+	if prev == nil {
+		for _, service := range curr.Services {
+			changes = append(changes, types.AppTimelineChange{ServiceName: service.Name, Description: fmt.Sprintf("added service %s", service.Name)})
+		}
+
+		return changes
+	}
+
+	if prevTag, currTag := imageTag(prev.Image), imageTag(curr.Image); prevTag != currTag {
+		changes = append(changes, types.AppTimelineChange{Description: fmt.Sprintf("changed image tag from %s to %s", prevTag, currTag)})
+	}
+
+	prevEnvGroups := make(map[string]bool)
+	for _, name := range prev.EnvGroups {
+		prevEnvGroups[name] = true
+	}
+	currEnvGroups := make(map[string]bool)
+	for _, name := range curr.EnvGroups {
+		currEnvGroups[name] = true
+		if !prevEnvGroups[name] {
+			changes = append(changes, types.AppTimelineChange{Description: fmt.Sprintf("attached env group %s", name)})
+		}
+	}
+	for _, name := range prev.EnvGroups {
+		if !currEnvGroups[name] {
+			changes = append(changes, types.AppTimelineChange{Description: fmt.Sprintf("detached env group %s", name)})
+		}
+	}
+
+	prevServices := make(map[string]v2.Service)
+	for _, service := range prev.Services {
+		prevServices[service.Name] = service
+	}
+	currServices := make(map[string]bool)
+
+	for _, service := range curr.Services {
+		currServices[service.Name] = true
+
+		prevService, ok := prevServices[service.Name]
+		if !ok {
+			changes = append(changes, types.AppTimelineChange{ServiceName: service.Name, Description: fmt.Sprintf("added service %s", service.Name)})
+			continue
+		}
+
+		changes = append(changes, serviceChanges(prevService, service)...)
+	}
+
+	for _, service := range prev.Services {
+		if !currServices[service.Name] {
+			changes = append(changes, types.AppTimelineChange{ServiceName: service.Name, Description: fmt.Sprintf("removed service %s", service.Name)})
+		}
+	}
+
+	return changes
+}
+
+// serviceChanges describes the changes to the settings of a service which affect how it runs
+func serviceChanges(prev, curr v2.Service) []types.AppTimelineChange {
+	var changes []types.AppTimelineChange
+
+	change := func(description string) {
+		changes = append(changes, types.AppTimelineChange{ServiceName: curr.Name, Description: description})
+	}
+
+	scalar := func(field string, from, to float64, unit string) {
+		if from == to {
+			return
+		}
+
+		verb := "raised"
+		if to < from {
+			verb = "lowered"
+		}
+
+		change(fmt.Sprintf("%s %s of %s from %s%s to %s%s", verb, field, curr.Name, formatTimelineNumber(from), unit, formatTimelineNumber(to), unit))
+	}
+
+	switch {
+	case prev.Instances != nil && curr.Instances != nil:
+		scalar("instances", float64(*prev.Instances), float64(*curr.Instances), "")
+	case prev.Instances != nil || curr.Instances != nil:
+		change(fmt.Sprintf("changed instances of %s", curr.Name))
+	}
+
+	scalar("cpu", float64(prev.CpuCores), float64(curr.CpuCores), " cores")
+	scalar("ram", float64(prev.RamMegabytes), float64(curr.RamMegabytes), "MB")
+
+	prevAutoscaling, currAutoscaling := v2.AutoScaling{}, v2.AutoScaling{}
+	if prev.Autoscaling != nil {
+		prevAutoscaling = *prev.Autoscaling
+	}
+	if curr.Autoscaling != nil {
+		currAutoscaling = *curr.Autoscaling
+	}
+
+	switch {
+	case !prevAutoscaling.Enabled && currAutoscaling.Enabled:
+		change(fmt.Sprintf("enabled autoscaling of %s", curr.Name))
+	case prevAutoscaling.Enabled && !currAutoscaling.Enabled:
+		change(fmt.Sprintf("disabled autoscaling of %s", curr.Name))
+	case currAutoscaling.Enabled:
+		scalar("min instances", float64(prevAutoscaling.MinInstances), float64(currAutoscaling.MinInstances), "")
+		scalar("max instances", float64(prevAutoscaling.MaxInstances), float64(currAutoscaling.MaxInstances), "")
+		scalar("autoscaling cpu threshold", float64(prevAutoscaling.CpuThresholdPercent), float64(currAutoscaling.CpuThresholdPercent), "%")
+		scalar("autoscaling memory threshold", float64(prevAutoscaling.MemoryThresholdPercent), float64(currAutoscaling.MemoryThresholdPercent), "%")
+	}
+
+	if stringValue(prev.Run) != stringValue(curr.Run) {
+		change(fmt.Sprintf("changed run command of %s", curr.Name))
+	}
+
+	if prev.Port != curr.Port {
+		change(fmt.Sprintf("changed port of %s from %d to %d", curr.Name, prev.Port, curr.Port))
+	}
+
+	if !reflect.DeepEqual(prev.HealthCheck, curr.HealthCheck) {
+		change(fmt.Sprintf("changed health check of %s", curr.Name))
+	}
+
+	return changes
+}
+
+// HelmTimelineEntries returns an entry for each version of the helm release of an app
+func HelmTimelineEntries(releases []*release.Release) []types.AppTimelineEntry {
+	entries := make([]types.AppTimelineEntry, 0, len(releases))
+
+	for _, rel := range releases {
+		if rel == nil || rel.Info == nil {
+			continue
+		}
+
+		entry := types.AppTimelineEntry{
+			Type:     types.AppTimelineEntryType_HelmUpgrade,
+			Severity: types.AppTimelineSeverity_Normal,
+			Time:     rel.Info.LastDeployed.Time,
+			Reason:   string(rel.Info.Status),
+			Message:  fmt.Sprintf("helm release version %d: %s", rel.Version, rel.Info.Description),
+		}
+
+		if rel.Info.Status == release.StatusFailed {
+			entry.Severity = types.AppTimelineSeverity_Warning
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// KubernetesTimelineInput is the input to KubernetesTimelineEntries
+type KubernetesTimelineInput struct {
+	Namespace          string
+	AppName            string
+	DeploymentTargetID string
+	// Incidents are incidents detected by the porter agent for the app, which are attributed to services by their
+	// involved object
+	Incidents []*types.IncidentMeta
+	// FromAgent is set when the porter agent is installed, in which case the events of the app are HistoricalEvents
+	// rather than the events which the cluster still holds
+	FromAgent bool
+	// HistoricalEvents are the events of the app stored by the porter agent, such as those returned by
+	// AgentKubernetesEvents
+	HistoricalEvents []v1.Event
+}
+
+// KubernetesTimelineEntries returns entries for the warning events of the kubernetes objects of an app, the scaling
+// events of their autoscalers, containers which were OOMKilled and incidents. Kubernetes only keeps events for a
+// limited time, an hour by default, so events are listed from the cluster only when the porter agent, which stores
+// them for longer, is not installed.
+func KubernetesTimelineEntries(ctx context.Context, clientset k8s.Interface, inp KubernetesTimelineInput) ([]types.AppTimelineEntry, error) {
+	ctx, span := telemetry.NewSpan(ctx, "kubernetes-timeline-entries")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "namespace", Value: inp.Namespace},
+		telemetry.AttributeKV{Key: "app-name", Value: inp.AppName},
+		telemetry.AttributeKV{Key: "deployment-target-id", Value: inp.DeploymentTargetID},
+	)
+
+	selector := fmt.Sprintf("%s=%s,%s=%s", LabelKey_AppName, inp.AppName, LabelKey_DeploymentTargetID, inp.DeploymentTargetID)
+
+	deployments, err := clientset.AppsV1().Deployments(inp.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing deployments")
+	}
+
+	workloads := appWorkloads{
+		deployments: make(map[string]string),
+		autoscalers: make(map[string]string),
+	}
+	for _, deployment := range deployments.Items {
+		workloads.deployments[deployment.Name] = deployment.Labels[LabelKey_ServiceName]
+	}
+
+	autoscalers, err := clientset.AutoscalingV2().HorizontalPodAutoscalers(inp.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing horizontal pod autoscalers")
+	}
+	for _, hpa := range autoscalers.Items {
+		if hpa.Spec.ScaleTargetRef.Kind != "Deployment" {
+			continue
+		}
+		if serviceName, ok := workloads.deployments[hpa.Spec.ScaleTargetRef.Name]; ok {
+			workloads.autoscalers[hpa.Name] = serviceName
+		}
+	}
+
+	var entries []types.AppTimelineEntry
+
+	events := inp.HistoricalEvents
+	if !inp.FromAgent {
+		eventList, err := clientset.CoreV1().Events(inp.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error listing events")
+		}
+
+		events = eventList.Items
+	}
+
+	for _, event := range events {
+		serviceName, ok := workloads.serviceName(event.InvolvedObject.Kind, event.InvolvedObject.Name)
+		if !ok {
+			continue
+		}
+
+		entry := types.AppTimelineEntry{
+			Type:        types.AppTimelineEntryType_KubernetesEvent,
+			Severity:    types.AppTimelineSeverity_Warning,
+			Time:        eventTime(event),
+			ServiceName: serviceName,
+			Reason:      event.Reason,
+			Message:     event.Message,
+			Count:       event.Count,
+		}
+
+		switch {
+		case event.Type == v1.EventTypeWarning:
+		case event.InvolvedObject.Kind == "HorizontalPodAutoscaler" && event.Reason == reasonSuccessfulRescale:
+			entry.Type = types.AppTimelineEntryType_Scaling
+			entry.Severity = types.AppTimelineSeverity_Normal
+		default:
+			// normal events are mostly routine scheduling and image pulls, which would drown out everything else
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	// OOMKilled is a container status rather than an event, so it is read from the app's pods
+	pods, err := clientset.CoreV1().Pods(inp.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing pods")
+	}
+
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			for _, terminated := range []*v1.ContainerStateTerminated{status.State.Terminated, status.LastTerminationState.Terminated} {
+				if terminated == nil || terminated.Reason != reasonOOMKilled {
+					continue
+				}
+
+				entries = append(entries, types.AppTimelineEntry{
+					Type:        types.AppTimelineEntryType_KubernetesEvent,
+					Severity:    types.AppTimelineSeverity_Warning,
+					Time:        terminated.FinishedAt.Time,
+					ServiceName: pod.Labels[LabelKey_ServiceName],
+					Reason:      reasonOOMKilled,
+					Message:     fmt.Sprintf("container %s of pod %s was killed for exceeding its memory limit", status.Name, pod.Name),
+				})
+			}
+		}
+	}
+
+	for _, incident := range inp.Incidents {
+		if incident == nil {
+			continue
+		}
+
+		serviceName, _ := workloads.serviceName(string(incident.InvolvedObjectKind), incident.InvolvedObjectName)
+
+		entries = append(entries, types.AppTimelineEntry{
+			Type:        types.AppTimelineEntryType_Incident,
+			Severity:    types.AppTimelineSeverity_Warning,
+			Time:        incident.CreatedAt,
+			ServiceName: serviceName,
+			Reason:      string(incident.Status),
+			Message:     incident.Summary,
+		})
+	}
+
+	return entries, nil
+}
+
+// AgentKubernetesEvents decodes the kubernetes events stored by the porter agent, skipping lines which are not events
+func AgentKubernetesEvents(lines []types.KubernetesEventLine) []v1.Event {
+	var events []v1.Event
+
+	for _, line := range lines {
+		var event v1.Event
+		if err := json.Unmarshal([]byte(line.Event), &event); err != nil {
+			continue
+		}
+
+		if eventTime(event).IsZero() && line.Timestamp != nil {
+			event.LastTimestamp = metav1.NewTime(*line.Timestamp)
+		}
+
+		events = append(events, event)
+	}
+
+	return events
+}
+
+// appWorkloads maps the kubernetes objects of an app to the services they belong to
+type appWorkloads struct {
+	// deployments maps deployment names to service names
+	deployments map[string]string
+	// autoscalers maps horizontal pod autoscaler names to service names
+	autoscalers map[string]string
+}
+
+// serviceName returns the service that a kubernetes object belongs to. Pods and replica sets are matched by the name
+// of their deployment, since their events outlive them and they cannot be listed once deleted.
+func (w appWorkloads) serviceName(kind, name string) (string, bool) {
+	switch strings.ToLower(kind) {
+	case "deployment":
+		serviceName, ok := w.deployments[name]
+		return serviceName, ok
+	case "horizontalpodautoscaler":
+		serviceName, ok := w.autoscalers[name]
+		return serviceName, ok
+	case "pod", "replicaset":
+		longest := ""
+		for deploymentName := range w.deployments {
+			if strings.HasPrefix(name, deploymentName+"-") && len(deploymentName) > len(longest) {
+				longest = deploymentName
+			}
+		}
+
+		if longest == "" {
+			return "", false
+		}
+
+		return w.deployments[longest], true
+	}
+
+	return "", false
+}
+
+// eventTime returns the time an event last occurred
+func eventTime(event v1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	}
+
+	return event.CreationTimestamp.Time
+}
+
+// TimelineFilter restricts the entries of a timeline
+type TimelineFilter struct {
+	// ServiceName keeps entries of a single service, along with entries for the whole app
+	ServiceName string
+	Start       time.Time
+	End         time.Time
+}
+
+// BuildTimeline orders entries from oldest to newest, adds causality hints to warnings, and applies the filter.
+// Hints are added before filtering, so that a warning can be attributed to a revision outside of the time range.
+func BuildTimeline(entries []types.AppTimelineEntry, filter TimelineFilter) []types.AppTimelineEntry {
+	sorted := make([]types.AppTimelineEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	addTimelineHints(sorted)
+
+	timeline := make([]types.AppTimelineEntry, 0, len(sorted))
+	for _, entry := range sorted {
+		if !filter.Start.IsZero() && entry.Time.Before(filter.Start) {
+			continue
+		}
+		if !filter.End.IsZero() && entry.Time.After(filter.End) {
+			continue
+		}
+		if filter.ServiceName != "" && entry.ServiceName != "" && entry.ServiceName != filter.ServiceName {
+			continue
+		}
+
+		timeline = append(timeline, entry)
+	}
+
+	return timeline
+}
+
+// addTimelineHints attributes each warning to the most recent revision applied shortly before it which changed the
+// warning's service. Entries must be ordered from oldest to newest.
+func addTimelineHints(entries []types.AppTimelineEntry) {
+	var revisions []types.AppTimelineEntry
+
+	for i, entry := range entries {
+		if entry.Type == types.AppTimelineEntryType_Revision {
+			// failed revisions were never rolled out, so they cannot have caused anything
+			if entry.Severity == types.AppTimelineSeverity_Normal {
+				revisions = append(revisions, entry)
+			}
+			continue
+		}
+
+		if entry.Severity != types.AppTimelineSeverity_Warning {
+			continue
+		}
+
+		for j := len(revisions) - 1; j >= 0; j-- {
+			revision := revisions[j]
+
+			elapsed := entry.Time.Sub(revision.Time)
+			if elapsed > timelineCorrelationWindow {
+				break
+			}
+
+			var descriptions []string
+			for _, change := range revision.Changes {
+				if change.ServiceName == "" || entry.ServiceName == "" || change.ServiceName == entry.ServiceName {
+					descriptions = append(descriptions, change.Description)
+				}
+			}
+			if len(descriptions) == 0 {
+				continue
+			}
+
+			if len(descriptions) > maxHintChanges {
+				descriptions = append(descriptions[:maxHintChanges], fmt.Sprintf("%d more changes", len(descriptions)-maxHintChanges))
+			}
+
+			entries[i].Hint = fmt.Sprintf(
+				"%s %s after revision %d %s",
+				entry.Reason, formatTimelineDuration(elapsed), revision.RevisionNumber, strings.Join(descriptions, ", "),
+			)
+
+			break
+		}
+	}
+}
+
+// formatTimelineDuration formats a duration at the precision used in hints, such as 45s, 3m or 1h5m
+func formatTimelineDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) - hours*60
+	if minutes == 0 {
+		return fmt.Sprintf("%dh", hours)
+	}
+
+	return fmt.Sprintf("%dh%dm", hours, minutes)
+}
+
+// formatTimelineNumber formats a resource amount without trailing zeros
+func formatTimelineNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 32)
+}
+
+func imageTag(image *v2.Image) string {
+	if image == nil {
+		return ""
+	}
+
+	return image.Tag
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
@@ -0,0 +1,101 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// Canary declares that new images of a web service are first sent a fraction of its traffic, and only rolled out if
+// they perform as well as the current image
+type Canary struct {
+	// Steps are the percentages of traffic that are shifted to the canary in turn, each followed by an analysis
+	Steps []CanaryStep `yaml:"steps"`
+	// Analysis are the thresholds that the canary is compared against the current image with
+	Analysis CanaryAnalysis `yaml:"analysis,omitempty"`
+}
+
+// CanaryStep sends a percentage of traffic to the canary for a duration
+type CanaryStep struct {
+	Weight          int `yaml:"weight"`
+	DurationSeconds int `yaml:"durationSeconds"`
+}
+
+// CanaryAnalysis are the thresholds of a canary analysis. Unset thresholds use the defaults.
+type CanaryAnalysis struct {
+	// MaxErrorRateIncrease is the number of percentage points that the 5xx rate of the canary may exceed the current rate by
+	MaxErrorRateIncrease float64 `yaml:"maxErrorRateIncrease,omitempty"`
+	// MaxLatencyIncreasePercent is the percentage that the p95 latency of the canary may exceed the current latency by
+	MaxLatencyIncreasePercent float64 `yaml:"maxLatencyIncreasePercent,omitempty"`
+	// MinRequests is the number of requests the canary must receive for a step to be conclusive
+	MinRequests uint `yaml:"minRequests,omitempty"`
+}
+
+const (
+	maxCanarySteps               = 10
+	minCanaryStepDurationSeconds = 30
+	maxCanaryStepDurationSeconds = 3600
+)
+
+// AppCanariesFromYaml returns the canaries declared on the services of a v2 Porter YAML file, or nil if the file is
+// not v2. Canaries in the previews section are ignored, since preview environments receive little traffic to analyze.
+func AppCanariesFromYaml(ctx context.Context, porterYamlBytes []byte) ([]types.AppCanarySpec, error) {
+	ctx, span := telemetry.NewSpan(ctx, "v2-app-canaries-from-yaml")
+	defer span.End()
+
+	porterYaml, err := v2PorterYamlFromBytes(porterYamlBytes)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error unmarshaling porter yaml")
+	}
+
+	if porterYaml == nil {
+		return nil, nil
+	}
+
+	canaries := []types.AppCanarySpec{}
+
+	for _, service := range porterYaml.Services {
+		if service.Canary == nil {
+			continue
+		}
+
+		if protoEnumFromType(service.Name, service) != porterv1.ServiceType_SERVICE_TYPE_WEB {
+			return nil, telemetry.Error(ctx, span, nil, fmt.Sprintf("canaries can only be declared on web services, but service %s is not a web service", service.Name))
+		}
+
+		if len(service.Canary.Steps) == 0 || len(service.Canary.Steps) > maxCanarySteps {
+			return nil, telemetry.Error(ctx, span, nil, fmt.Sprintf("canary of service %s must have between 1 and %d steps", service.Name, maxCanarySteps))
+		}
+
+		spec := types.AppCanarySpec{
+			ServiceName: service.Name,
+			Analysis: types.AppCanaryAnalysis{
+				MaxErrorRateIncrease:      service.Canary.Analysis.MaxErrorRateIncrease,
+				MaxLatencyIncreasePercent: service.Canary.Analysis.MaxLatencyIncreasePercent,
+				MinRequests:               service.Canary.Analysis.MinRequests,
+			},
+		}
+
+		for _, step := range service.Canary.Steps {
+			if step.Weight < 1 || step.Weight > 100 {
+				return nil, telemetry.Error(ctx, span, nil, fmt.Sprintf("canary step weights of service %s must be between 1 and 100", service.Name))
+			}
+
+			if step.DurationSeconds < minCanaryStepDurationSeconds || step.DurationSeconds > maxCanaryStepDurationSeconds {
+				return nil, telemetry.Error(ctx, span, nil, fmt.Sprintf("canary step durations of service %s must be between %d and %d seconds", service.Name, minCanaryStepDurationSeconds, maxCanaryStepDurationSeconds))
+			}
+
+			spec.Steps = append(spec.Steps, types.AppCanaryStep{
+				Weight:          step.Weight,
+				DurationSeconds: step.DurationSeconds,
+			})
+		}
+
+		canaries = append(canaries, spec)
+	}
+
+	return canaries, nil
+}
@@ -0,0 +1,62 @@
+package v2
+
+import (
+	"context"
+	"math"
+
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// AppCapacityFromYaml returns the name of the app in a v2 Porter YAML file, along with the pods that its web and
+// worker services request. Autoscaled services are counted at their maximum number of instances, so that the
+// simulation covers the worst case. Job services are not included, since their pods only run for a short time.
+func AppCapacityFromYaml(ctx context.Context, porterYamlBytes []byte) (string, []types.CapacityPodGroup, error) {
+	ctx, span := telemetry.NewSpan(ctx, "v2-app-capacity-from-yaml")
+	defer span.End()
+
+	porterYaml, err := v2PorterYamlFromBytes(porterYamlBytes)
+	if err != nil {
+		return "", nil, telemetry.Error(ctx, span, err, "error unmarshaling porter yaml")
+	}
+
+	if porterYaml == nil {
+		return "", nil, telemetry.Error(ctx, span, nil, "capacity can only be simulated for v2 porter yaml files")
+	}
+
+	pods := []types.CapacityPodGroup{}
+
+	for _, service := range porterYaml.Services {
+		if protoEnumFromType(service.Name, service) == porterv1.ServiceType_SERVICE_TYPE_JOB {
+			continue
+		}
+
+		replicas := 1
+		if service.Instances != nil {
+			replicas = int(*service.Instances)
+		}
+		if service.Autoscaling != nil && service.Autoscaling.Enabled && service.Autoscaling.MaxInstances > replicas {
+			replicas = service.Autoscaling.MaxInstances
+		}
+
+		if replicas == 0 {
+			continue
+		}
+
+		gpu := int64(math.Ceil(float64(service.GpuCoresNvidia)))
+		if service.GPU != nil && service.GPU.Enabled {
+			gpu = int64(service.GPU.GpuCoresNvidia)
+		}
+
+		pods = append(pods, types.CapacityPodGroup{
+			Name:        service.Name,
+			Replicas:    replicas,
+			CPUMillis:   int64(math.Round(float64(service.CpuCores) * 1000)),
+			MemoryBytes: int64(service.RamMegabytes) * 1024 * 1024,
+			GPU:         gpu,
+		})
+	}
+
+	return porterYaml.Name, pods, nil
+}
@@ -0,0 +1,180 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// AppDependencies is an app in a Porter YAML file, along with the apps that it needs to be deployed first
+type AppDependencies struct {
+	// Name is the name of the app
+	Name string
+	// DependsOn are the names of the apps which are declared in requiredApps or referenced by env variables from another app
+	DependsOn []string
+}
+
+// AppDependenciesFromYaml returns the name of the app in a v2 Porter YAML file, along with the apps that it depends on.
+// Required apps from another deployment target are not dependencies, since they are not deployed alongside the app.
+func AppDependenciesFromYaml(ctx context.Context, porterYamlBytes []byte) (AppDependencies, error) {
+	ctx, span := telemetry.NewSpan(ctx, "v2-app-dependencies-from-yaml")
+	defer span.End()
+
+	var deps AppDependencies
+
+	porterYaml, err := v2PorterYamlFromBytes(porterYamlBytes)
+	if err != nil {
+		return deps, telemetry.Error(ctx, span, err, "error unmarshaling porter yaml")
+	}
+
+	if porterYaml == nil {
+		return deps, telemetry.Error(ctx, span, nil, "porter yaml must be version v2")
+	}
+
+	if porterYaml.Name == "" {
+		return deps, telemetry.Error(ctx, span, nil, "porter yaml must specify the name of the app")
+	}
+
+	deps.Name = porterYaml.Name
+
+	seen := make(map[string]bool)
+	addDependency := func(name string) {
+		if name == "" || name == deps.Name || seen[name] {
+			return
+		}
+
+		seen[name] = true
+		deps.DependsOn = append(deps.DependsOn, name)
+	}
+
+	for _, requiredApp := range porterYaml.RequiredApps {
+		if requiredApp.FromTarget != "" {
+			continue
+		}
+
+		addDependency(requiredApp.Name)
+	}
+
+	for _, envVar := range porterYaml.Env {
+		if envVar.Source != EnvVariableSource_FromApp || !envVar.FromApp.IsSet {
+			continue
+		}
+
+		addDependency(envVar.FromApp.Value.AppName)
+	}
+
+	sort.Strings(deps.DependsOn)
+
+	return deps, nil
+}
+
+// DeployLayers orders apps so that every app is deployed after the apps that it depends on. Apps in the same layer do
+// not depend on each other, so they can be deployed in parallel once the previous layers are healthy. Dependencies on
+// apps which are not in the list are ignored, since those apps are not part of the deploy. An error is returned if the
+// dependencies contain a cycle.
+func DeployLayers(apps []AppDependencies) ([][]string, error) {
+	dependsOn := make(map[string][]string, len(apps))
+	for _, app := range apps {
+		if _, ok := dependsOn[app.Name]; ok {
+			return nil, fmt.Errorf("app %s is declared more than once", app.Name)
+		}
+
+		dependsOn[app.Name] = nil
+	}
+
+	remaining := make(map[string]int, len(apps))
+	dependents := make(map[string][]string, len(apps))
+
+	for _, app := range apps {
+		for _, dep := range app.DependsOn {
+			if _, ok := dependsOn[dep]; !ok {
+				continue
+			}
+
+			dependsOn[app.Name] = append(dependsOn[app.Name], dep)
+			dependents[dep] = append(dependents[dep], app.Name)
+			remaining[app.Name]++
+		}
+	}
+
+	var layers [][]string
+
+	var layer []string
+	for _, app := range apps {
+		if remaining[app.Name] == 0 {
+			layer = append(layer, app.Name)
+		}
+	}
+
+	deployed := 0
+
+	for len(layer) > 0 {
+		sort.Strings(layer)
+		layers = append(layers, layer)
+		deployed += len(layer)
+
+		var next []string
+		for _, name := range layer {
+			for _, dependent := range dependents[name] {
+				remaining[dependent]--
+				if remaining[dependent] == 0 {
+					next = append(next, dependent)
+				}
+			}
+		}
+
+		layer = next
+	}
+
+	if deployed < len(apps) {
+		return nil, fmt.Errorf("apps have a dependency cycle: %s", strings.Join(dependencyCycle(dependsOn, remaining), " -> "))
+	}
+
+	return layers, nil
+}
+
+// dependencyCycle returns a cycle among the apps which could not be ordered, starting and ending with the same app
+func dependencyCycle(dependsOn map[string][]string, remaining map[string]int) []string {
+	var unordered []string
+	for name, count := range remaining {
+		if count > 0 {
+			unordered = append(unordered, name)
+		}
+	}
+
+	sort.Strings(unordered)
+
+	// every unordered app depends on another unordered app, so following those dependencies must revisit an app
+	path := []string{}
+	index := make(map[string]int)
+
+	name := unordered[0]
+	for {
+		if i, ok := index[name]; ok {
+			return append(path[i:], name)
+		}
+
+		index[name] = len(path)
+		path = append(path, name)
+
+		deps := dependsOn[name]
+		sort.Strings(deps)
+
+		next := ""
+		for _, dep := range deps {
+			if remaining[dep] > 0 {
+				next = dep
+				break
+			}
+		}
+
+		if next == "" {
+			return path
+		}
+
+		name = next
+	}
+}
@@ -0,0 +1,169 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// SeedStep is a step which seeds the addons of a preview environment with data. Each step either runs a job service of
+// the app, or clones a datastore into the preview environment.
+type SeedStep struct {
+	Name string `yaml:"name"`
+	// Job is the job service which is run in the preview environment
+	Job string `yaml:"job,omitempty"`
+	// Run optionally overrides the run command of the job, for example to pass the URL of a SQL dump
+	Run string `yaml:"run,omitempty"`
+	// CloneDatastore clones a datastore, such as a sanitized copy of a parent database, into the preview environment
+	CloneDatastore *SeedCloneDatastore `yaml:"cloneDatastore,omitempty"`
+	// TimeoutSeconds is how long the step may run before it is failed
+	TimeoutSeconds int `yaml:"timeoutSeconds,omitempty"`
+}
+
+// SeedCloneDatastore is a datastore which is cloned into a preview environment
+type SeedCloneDatastore struct {
+	From string `yaml:"from"`
+	// Name is the name of the clone. Defaults to <from>-<preview environment name>
+	Name string `yaml:"name,omitempty"`
+}
+
+const (
+	maxSeedSteps                   = 20
+	defaultSeedJobTimeoutSeconds   = 600
+	defaultSeedCloneTimeoutSeconds = 1800
+	maxSeedStepTimeoutSeconds      = 3600
+)
+
+// AppPreviewSeedFromYaml returns the seed steps declared in the previews section of a v2 Porter YAML file, in the
+// order they are run, or nil if the file is not v2
+func AppPreviewSeedFromYaml(ctx context.Context, porterYamlBytes []byte) ([]types.PreviewSeedStep, error) {
+	ctx, span := telemetry.NewSpan(ctx, "v2-app-preview-seed-from-yaml")
+	defer span.End()
+
+	porterYaml, err := v2PorterYamlFromBytes(porterYamlBytes)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error unmarshaling porter yaml")
+	}
+
+	if porterYaml == nil || porterYaml.Previews == nil || len(porterYaml.Previews.Seed) == 0 {
+		return nil, nil
+	}
+
+	if len(porterYaml.Previews.Seed) > maxSeedSteps {
+		return nil, telemetry.Error(ctx, span, nil, fmt.Sprintf("previews may have at most %d seed steps", maxSeedSteps))
+	}
+
+	// services in the previews section override the services of the app with the same name, and inherit their type
+	// if it is not set
+	jobs := make(map[string]bool)
+	for _, service := range porterYaml.Services {
+		jobs[service.Name] = protoEnumFromType(service.Name, service) == porterv1.ServiceType_SERVICE_TYPE_JOB
+	}
+	for _, service := range porterYaml.Previews.Services {
+		serviceType := protoEnumFromType(service.Name, service)
+		if serviceType != porterv1.ServiceType_SERVICE_TYPE_UNSPECIFIED {
+			jobs[service.Name] = serviceType == porterv1.ServiceType_SERVICE_TYPE_JOB
+		} else if _, ok := jobs[service.Name]; !ok {
+			jobs[service.Name] = false
+		}
+	}
+
+	steps := []types.PreviewSeedStep{}
+	names := make(map[string]bool)
+
+	for _, step := range porterYaml.Previews.Seed {
+		if step.Name == "" {
+			return nil, telemetry.Error(ctx, span, nil, "seed steps must have a name")
+		}
+		if names[step.Name] {
+			return nil, telemetry.Error(ctx, span, nil, fmt.Sprintf("seed step %s is declared more than once", step.Name))
+		}
+		names[step.Name] = true
+
+		if step.TimeoutSeconds < 0 || step.TimeoutSeconds > maxSeedStepTimeoutSeconds {
+			return nil, telemetry.Error(ctx, span, nil, fmt.Sprintf("timeout of seed step %s must be between 1 and %d seconds", step.Name, maxSeedStepTimeoutSeconds))
+		}
+
+		spec := types.PreviewSeedStep{
+			Name:           step.Name,
+			TimeoutSeconds: step.TimeoutSeconds,
+		}
+
+		switch {
+		case step.Job != "" && step.CloneDatastore != nil:
+			return nil, telemetry.Error(ctx, span, nil, fmt.Sprintf("seed step %s must either run a job or clone a datastore, but not both", step.Name))
+		case step.Job != "":
+			isJob, ok := jobs[step.Job]
+			if !ok {
+				return nil, telemetry.Error(ctx, span, nil, fmt.Sprintf("seed step %s runs job %s, which is not a service of the app", step.Name, step.Job))
+			}
+			if !isJob {
+				return nil, telemetry.Error(ctx, span, nil, fmt.Sprintf("seed step %s runs service %s, which is not a job", step.Name, step.Job))
+			}
+
+			spec.Type = types.PreviewSeedStepType_Job
+			spec.JobName = step.Job
+			spec.RunCommand = step.Run
+			if spec.TimeoutSeconds == 0 {
+				spec.TimeoutSeconds = defaultSeedJobTimeoutSeconds
+			}
+		case step.CloneDatastore != nil:
+			if step.CloneDatastore.From == "" {
+				return nil, telemetry.Error(ctx, span, nil, fmt.Sprintf("seed step %s must set the datastore to clone from", step.Name))
+			}
+			if step.Run != "" {
+				return nil, telemetry.Error(ctx, span, nil, fmt.Sprintf("seed step %s clones a datastore, so it cannot set a run command", step.Name))
+			}
+
+			spec.Type = types.PreviewSeedStepType_CloneDatastore
+			spec.SourceDatastore = step.CloneDatastore.From
+			spec.DatastoreName = step.CloneDatastore.Name
+			if spec.TimeoutSeconds == 0 {
+				spec.TimeoutSeconds = defaultSeedCloneTimeoutSeconds
+			}
+		default:
+			return nil, telemetry.Error(ctx, span, nil, fmt.Sprintf("seed step %s must either run a job or clone a datastore", step.Name))
+		}
+
+		steps = append(steps, spec)
+	}
+
+	return steps, nil
+}
+
+// AppPreviewAddonNamesFromYaml returns the names of the addons deployed in a preview environment by a v2 Porter YAML
+// file, which are the addons of the app and the addons of the previews section, or nil if the file is not v2
+func AppPreviewAddonNamesFromYaml(ctx context.Context, porterYamlBytes []byte) ([]string, error) {
+	ctx, span := telemetry.NewSpan(ctx, "v2-app-preview-addon-names-from-yaml")
+	defer span.End()
+
+	porterYaml, err := v2PorterYamlFromBytes(porterYamlBytes)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error unmarshaling porter yaml")
+	}
+
+	if porterYaml == nil {
+		return nil, nil
+	}
+
+	addons := porterYaml.Addons
+	if porterYaml.Previews != nil {
+		// addons in the previews section override the addons of the app with the same name
+		addons = append(addons, porterYaml.Previews.Addons...)
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, addon := range addons {
+		if addon.Name == "" || seen[addon.Name] {
+			continue
+		}
+		seen[addon.Name] = true
+		names = append(names, addon.Name)
+	}
+
+	return names, nil
+}
@@ -0,0 +1,93 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gopkg.in/yaml.v2"
+)
+
+// SLO is a service level objective for a web service, such as 99.9% of requests without a 5xx status
+// or 95% of requests within 300ms
+type SLO struct {
+	// Name defaults to the service name followed by the type of the objective
+	Name string `yaml:"name,omitempty"`
+	// Type is either availability or latency
+	Type string `yaml:"type" validate:"required,oneof=availability latency"`
+	// Target is the percentage of requests which must meet the objective over the window
+	Target float64 `yaml:"target" validate:"required,gt=0,lt=100"`
+	// LatencyThresholdMs is the latency that requests must complete within for latency objectives
+	LatencyThresholdMs uint `yaml:"latencyThresholdMs,omitempty"`
+	// WindowDays is the rolling window over which the objective is measured, which defaults to 30 days
+	WindowDays uint `yaml:"windowDays,omitempty"`
+}
+
+// AppSLOsFromYaml returns the SLOs declared on the services of a v2 Porter YAML file, or nil if the file is not v2.
+// SLOs in the previews section are ignored, since preview environments are not expected to meet objectives.
+func AppSLOsFromYaml(ctx context.Context, porterYamlBytes []byte) ([]types.AppSLOSpec, error) {
+	ctx, span := telemetry.NewSpan(ctx, "v2-app-slos-from-yaml")
+	defer span.End()
+
+	porterYaml, err := v2PorterYamlFromBytes(porterYamlBytes)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error unmarshaling porter yaml")
+	}
+
+	if porterYaml == nil {
+		return nil, nil
+	}
+
+	slos := []types.AppSLOSpec{}
+
+	for _, service := range porterYaml.Services {
+		if len(service.SLOs) == 0 {
+			continue
+		}
+
+		if protoEnumFromType(service.Name, service) != porterv1.ServiceType_SERVICE_TYPE_WEB {
+			return nil, telemetry.Error(ctx, span, nil, fmt.Sprintf("slos can only be declared on web services, but service %s is not a web service", service.Name))
+		}
+
+		for _, slo := range service.SLOs {
+			name := slo.Name
+			if name == "" {
+				name = fmt.Sprintf("%s-%s", service.Name, slo.Type)
+			}
+
+			slos = append(slos, types.AppSLOSpec{
+				Name:               name,
+				ServiceName:        service.Name,
+				Type:               slo.Type,
+				Target:             slo.Target,
+				LatencyThresholdMs: slo.LatencyThresholdMs,
+				WindowDays:         slo.WindowDays,
+			})
+		}
+	}
+
+	return slos, nil
+}
+
+// v2PorterYamlFromBytes unmarshals a Porter YAML file, or returns nil if the file is not v2
+func v2PorterYamlFromBytes(porterYamlBytes []byte) (*PorterYAML, error) {
+	version := struct {
+		Version string `yaml:"version"`
+	}{}
+	if err := yaml.Unmarshal(porterYamlBytes, &version); err != nil {
+		return nil, err
+	}
+
+	if version.Version != "v2" {
+		return nil, nil
+	}
+
+	porterYaml := &PorterYAML{}
+	if err := yaml.Unmarshal(porterYamlBytes, porterYaml); err != nil {
+		return nil, err
+	}
+
+	return porterYaml, nil
+}
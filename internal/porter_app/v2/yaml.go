@@ -122,7 +122,14 @@ type PorterAppWithAddons struct {
 // PorterYAML represents all the possible fields in a Porter YAML file
 type PorterYAML struct {
 	PorterAppWithAddons `yaml:",inline"`
-	Previews            *PorterAppWithAddons `yaml:"previews,omitempty"`
+	Previews            *Previews `yaml:"previews,omitempty"`
+}
+
+// Previews is the definition of a porter app in preview environments, which overrides the app definition
+type Previews struct {
+	PorterAppWithAddons `yaml:",inline"`
+	// Seed are the steps which are run in order once the addons of a preview environment are ready
+	Seed []SeedStep `yaml:"seed,omitempty"`
 }
 
 // Addon represents an addon that should be installed alongside a Porter app
@@ -191,6 +198,8 @@ type Service struct {
 	IngressAnnotations            map[string]string `yaml:"ingressAnnotations,omitempty" validate:"excluded_unless=Type web"`
 	DisableTLS                    *bool             `yaml:"disableTLS,omitempty" validate:"excluded_unless=Type web"`
 	Sleep                         *bool             `yaml:"sleep,omitempty" validate:"excluded_unless=Type job"`
+	SLOs                          []SLO             `yaml:"slos,omitempty" validate:"excluded_unless=Type web"`
+	Canary                        *Canary           `yaml:"canary,omitempty" validate:"excluded_unless=Type web"`
 }
 
 // AutoScaling represents the autoscaling settings for web services
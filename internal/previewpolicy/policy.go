@@ -0,0 +1,221 @@
+package previewpolicy
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+const (
+	// MaxTTLHours is the longest TTL that an environment may set, matching the TTL of the Porter instance
+	MaxTTLHours = 720
+	// MaxSleepAfterIdleHours is the longest idle period that an environment may set
+	MaxSleepAfterIdleHours = 168
+	// MaxConcurrentPreviews is the largest limit on the number of preview deployments that an environment may set
+	MaxConcurrentPreviews = 100
+
+	// WakeGracePeriod is how long a deployment which was woken by a request stays awake, even if it is within
+	// the sleep schedule of its environment
+	WakeGracePeriod = time.Hour
+)
+
+// SleepReason is the reason that a deployment is put to sleep
+type SleepReason string
+
+const (
+	// SleepReason_Idle is set when the ingress of the deployment has not received a request within the idle period
+	SleepReason_Idle SleepReason = "idle"
+	// SleepReason_Schedule is set when the sleep schedule of the environment is in effect
+	SleepReason_Schedule SleepReason = "schedule"
+)
+
+// ValidatePolicy returns an error if the preview policy of an environment is not valid
+func ValidatePolicy(policy *types.PreviewEnvironmentPolicy) error {
+	if policy == nil {
+		return nil
+	}
+
+	if policy.TTLHours > MaxTTLHours {
+		return fmt.Errorf("ttl must be at most %d hours", MaxTTLHours)
+	}
+
+	if policy.SleepAfterIdleHours > MaxSleepAfterIdleHours {
+		return fmt.Errorf("idle period must be at most %d hours", MaxSleepAfterIdleHours)
+	}
+
+	if policy.MaxConcurrentPreviews > MaxConcurrentPreviews {
+		return fmt.Errorf("max concurrent previews must be at most %d", MaxConcurrentPreviews)
+	}
+
+	if schedule := policy.SleepSchedule; schedule != nil {
+		if schedule.StartHour > 23 || schedule.EndHour > 23 {
+			return errors.New("sleep schedule hours must be between 0 and 23")
+		}
+
+		if schedule.StartHour == schedule.EndHour && !schedule.Weekends {
+			return errors.New("sleep schedule must either have a daily window or sleep on weekends")
+		}
+
+		if _, err := time.LoadLocation(schedule.Timezone); err != nil {
+			return fmt.Errorf("invalid sleep schedule timezone %s", schedule.Timezone)
+		}
+	}
+
+	return nil
+}
+
+// TTL returns how long the deployments of an environment are kept after their last push. The TTL of the Porter
+// instance is used if the policy does not set one, and zero means that deployments are never deleted.
+func TTL(policy *types.PreviewEnvironmentPolicy, defaultTTL time.Duration) time.Duration {
+	if policy != nil && policy.TTLHours > 0 {
+		return time.Duration(policy.TTLHours) * time.Hour
+	}
+
+	return defaultTTL
+}
+
+// LastPushed returns when a commit was last deployed to a deployment. Deployments created before pushes were
+// recorded fall back to when they were created.
+func LastPushed(depl *models.Deployment) time.Time {
+	if depl.LastPushedAt != nil {
+		return *depl.LastPushedAt
+	}
+
+	return depl.CreatedAt
+}
+
+// LastUsed returns when a deployment was last pushed to or woken up by a request
+func LastUsed(depl *models.Deployment) time.Time {
+	lastUsed := LastPushed(depl)
+
+	if depl.LastWokenAt != nil && depl.LastWokenAt.After(lastUsed) {
+		lastUsed = *depl.LastWokenAt
+	}
+
+	return lastUsed
+}
+
+// Expired returns true if a deployment has not been pushed to within the TTL
+func Expired(depl *models.Deployment, ttl time.Duration, now time.Time) bool {
+	return ttl > 0 && LastPushed(depl).Add(ttl).Before(now)
+}
+
+// EvictionCandidates returns the least recently used deployments which must be deleted for an environment to have
+// at most max active deployments, ordered from least recently used. Inactive deployments and branch deploys do not
+// count towards the limit, and a max of zero disables eviction.
+func EvictionCandidates(max uint, depls []*models.Deployment) []*models.Deployment {
+	if max == 0 {
+		return nil
+	}
+
+	active := make([]*models.Deployment, 0, len(depls))
+	for _, depl := range depls {
+		if depl.Status != types.DeploymentStatusInactive && !depl.IsBranchDeploy() {
+			active = append(active, depl)
+		}
+	}
+
+	if uint(len(active)) <= max {
+		return nil
+	}
+
+	sort.SliceStable(active, func(i, j int) bool {
+		return LastUsed(active[i]).Before(LastUsed(active[j]))
+	})
+
+	return active[:uint(len(active))-max]
+}
+
+// InSleepWindow returns true if the sleep schedule is in effect at the given time, in the timezone of the schedule
+func InSleepWindow(schedule *types.PreviewSleepSchedule, now time.Time) (bool, error) {
+	if schedule == nil {
+		return false, nil
+	}
+
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return false, fmt.Errorf("invalid sleep schedule timezone %s: %w", schedule.Timezone, err)
+	}
+
+	local := now.In(loc)
+
+	if schedule.Weekends && (local.Weekday() == time.Saturday || local.Weekday() == time.Sunday) {
+		return true, nil
+	}
+
+	hour := uint(local.Hour())
+	start, end := schedule.StartHour, schedule.EndHour
+
+	switch {
+	case start == end:
+		return false, nil
+	case start < end:
+		return hour >= start && hour < end, nil
+	default:
+		// the window spans midnight, such as from 20 to 8
+		return hour >= start || hour < end, nil
+	}
+}
+
+// IdleCheckDue returns true if a deployment has been up for longer than the idle period of its environment, so
+// that its ingress traffic should be checked. Deployments which were recently pushed to or woken up are not checked,
+// since they have not had a chance to receive traffic.
+func IdleCheckDue(policy *types.PreviewEnvironmentPolicy, depl *models.Deployment, now time.Time) bool {
+	if policy == nil || policy.SleepAfterIdleHours == 0 || depl.SleepingSince != nil {
+		return false
+	}
+
+	return LastUsed(depl).Add(time.Duration(policy.SleepAfterIdleHours) * time.Hour).Before(now)
+}
+
+// ShouldSleep returns the reason that an awake deployment should be put to sleep, or an empty reason if it should
+// stay awake. idle is whether the ingress of the deployment has received no requests within the idle period.
+func ShouldSleep(policy *types.PreviewEnvironmentPolicy, depl *models.Deployment, idle bool, now time.Time) (SleepReason, error) {
+	if policy == nil || depl.SleepingSince != nil || depl.Status == types.DeploymentStatusInactive {
+		return "", nil
+	}
+
+	if idle && IdleCheckDue(policy, depl, now) {
+		return SleepReason_Idle, nil
+	}
+
+	if policy.SleepSchedule != nil && LastUsed(depl).Add(WakeGracePeriod).Before(now) {
+		inWindow, err := InSleepWindow(policy.SleepSchedule, now)
+		if err != nil {
+			return "", err
+		}
+
+		if inWindow {
+			return SleepReason_Schedule, nil
+		}
+	}
+
+	return "", nil
+}
+
+// ShouldWake returns true if a sleeping deployment should be woken up without waiting for a request, because the
+// sleep schedule which put it to sleep has ended, or its environment no longer has the policy which put it to sleep
+func ShouldWake(policy *types.PreviewEnvironmentPolicy, depl *models.Deployment, now time.Time) (bool, error) {
+	if depl.SleepingSince == nil {
+		return false, nil
+	}
+
+	if SleepReason(depl.SleepReason) == SleepReason_Idle {
+		return policy == nil || policy.SleepAfterIdleHours == 0, nil
+	}
+
+	if policy == nil || policy.SleepSchedule == nil {
+		return true, nil
+	}
+
+	inWindow, err := InSleepWindow(policy.SleepSchedule, now)
+	if err != nil {
+		return false, err
+	}
+
+	return !inWindow, nil
+}
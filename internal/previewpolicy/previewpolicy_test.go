@@ -0,0 +1,267 @@
+package previewpolicy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValidatePolicy(t *testing.T) {
+	assert.NoError(t, ValidatePolicy(nil))
+	assert.NoError(t, ValidatePolicy(&types.PreviewEnvironmentPolicy{
+		TTLHours:              72,
+		SleepAfterIdleHours:   2,
+		MaxConcurrentPreviews: 5,
+		SleepSchedule:         &types.PreviewSleepSchedule{StartHour: 20, EndHour: 8, Timezone: "America/New_York"},
+	}))
+	assert.NoError(t, ValidatePolicy(&types.PreviewEnvironmentPolicy{
+		SleepSchedule: &types.PreviewSleepSchedule{Weekends: true},
+	}))
+
+	assert.Error(t, ValidatePolicy(&types.PreviewEnvironmentPolicy{TTLHours: 721}))
+	assert.Error(t, ValidatePolicy(&types.PreviewEnvironmentPolicy{SleepAfterIdleHours: 169}))
+	assert.Error(t, ValidatePolicy(&types.PreviewEnvironmentPolicy{MaxConcurrentPreviews: 101}))
+	assert.Error(t, ValidatePolicy(&types.PreviewEnvironmentPolicy{
+		SleepSchedule: &types.PreviewSleepSchedule{StartHour: 24, EndHour: 8},
+	}))
+	assert.Error(t, ValidatePolicy(&types.PreviewEnvironmentPolicy{
+		SleepSchedule: &types.PreviewSleepSchedule{StartHour: 8, EndHour: 8},
+	}))
+	assert.Error(t, ValidatePolicy(&types.PreviewEnvironmentPolicy{
+		SleepSchedule: &types.PreviewSleepSchedule{StartHour: 20, EndHour: 8, Timezone: "Mars/Olympus"},
+	}))
+}
+
+func TestExpired(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	pushed := now.Add(-30 * time.Hour)
+
+	depl := &models.Deployment{}
+	depl.CreatedAt = now.Add(-100 * time.Hour)
+
+	// deployments which were never pushed to count from their creation
+	assert.True(t, Expired(depl, TTL(nil, 72*time.Hour), now))
+
+	depl.LastPushedAt = &pushed
+	assert.False(t, Expired(depl, TTL(nil, 72*time.Hour), now))
+	assert.True(t, Expired(depl, TTL(&types.PreviewEnvironmentPolicy{TTLHours: 24}, 72*time.Hour), now))
+
+	// waking a deployment does not extend its TTL
+	woken := now.Add(-time.Hour)
+	depl.LastWokenAt = &woken
+	assert.True(t, Expired(depl, 24*time.Hour, now))
+
+	assert.False(t, Expired(depl, 0, now))
+}
+
+func TestEvictionCandidates(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	deployment := func(id uint, status types.DeploymentStatus, pushedHoursAgo int, wokenHoursAgo int) *models.Deployment {
+		depl := &models.Deployment{Status: status}
+		depl.ID = id
+		pushed := now.Add(-time.Duration(pushedHoursAgo) * time.Hour)
+		depl.LastPushedAt = &pushed
+		if wokenHoursAgo > 0 {
+			woken := now.Add(-time.Duration(wokenHoursAgo) * time.Hour)
+			depl.LastWokenAt = &woken
+		}
+		return depl
+	}
+
+	depls := []*models.Deployment{
+		deployment(1, types.DeploymentStatusCreated, 10, 0),
+		deployment(2, types.DeploymentStatusCreated, 50, 1),
+		deployment(3, types.DeploymentStatusInactive, 100, 0),
+		deployment(4, types.DeploymentStatusFailed, 20, 0),
+		deployment(5, types.DeploymentStatusCreated, 5, 0),
+		deployment(6, types.DeploymentStatusCreated, 200, 0),
+	}
+	depls[5].PRBranchFrom, depls[5].PRBranchInto = "staging", "staging"
+
+	assert.Nil(t, EvictionCandidates(0, depls))
+	assert.Nil(t, EvictionCandidates(4, depls))
+
+	var ids []uint
+	for _, depl := range EvictionCandidates(2, depls) {
+		ids = append(ids, depl.ID)
+	}
+	assert.Equal(t, []uint{4, 1}, ids)
+}
+
+func TestInSleepWindow(t *testing.T) {
+	// 2024-01-10 is a Wednesday
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, 1, day, hour, 30, 0, 0, time.UTC)
+	}
+
+	overnight := &types.PreviewSleepSchedule{StartHour: 20, EndHour: 8}
+
+	for _, tc := range []struct {
+		schedule *types.PreviewSleepSchedule
+		now      time.Time
+		want     bool
+	}{
+		{overnight, at(10, 21), true},
+		{overnight, at(10, 3), true},
+		{overnight, at(10, 8), false},
+		{overnight, at(10, 12), false},
+		{overnight, at(13, 12), false},
+		{&types.PreviewSleepSchedule{StartHour: 1, EndHour: 6}, at(10, 2), true},
+		{&types.PreviewSleepSchedule{StartHour: 1, EndHour: 6}, at(10, 6), false},
+		{&types.PreviewSleepSchedule{Weekends: true}, at(13, 12), true},
+		{&types.PreviewSleepSchedule{Weekends: true}, at(14, 12), true},
+		{&types.PreviewSleepSchedule{Weekends: true}, at(15, 12), false},
+		// 12:30 UTC is 7:30 in New York, which is within the window
+		{&types.PreviewSleepSchedule{StartHour: 20, EndHour: 8, Timezone: "America/New_York"}, at(10, 12), true},
+		{nil, at(10, 3), false},
+	} {
+		got, err := InSleepWindow(tc.schedule, tc.now)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.want, got, "schedule %+v at %s", tc.schedule, tc.now)
+	}
+}
+
+func TestShouldSleepAndWake(t *testing.T) {
+	now := time.Date(2024, 1, 10, 22, 0, 0, 0, time.UTC)
+	pushed := now.Add(-5 * time.Hour)
+
+	depl := &models.Deployment{Status: types.DeploymentStatusCreated, LastPushedAt: &pushed}
+
+	policy := &types.PreviewEnvironmentPolicy{SleepAfterIdleHours: 4}
+
+	reason, err := ShouldSleep(policy, depl, true, now)
+	assert.NoError(t, err)
+	assert.Equal(t, SleepReason_Idle, reason)
+
+	reason, err = ShouldSleep(policy, depl, false, now)
+	assert.NoError(t, err)
+	assert.Equal(t, SleepReason(""), reason)
+
+	// a deployment which was recently woken up is not idle yet
+	woken := now.Add(-time.Hour)
+	depl.LastWokenAt = &woken
+	assert.False(t, IdleCheckDue(policy, depl, now))
+
+	policy = &types.PreviewEnvironmentPolicy{SleepSchedule: &types.PreviewSleepSchedule{StartHour: 20, EndHour: 8}}
+
+	// the deployment was woken up within the grace period
+	woken = now.Add(-30 * time.Minute)
+	reason, err = ShouldSleep(policy, depl, false, now)
+	assert.NoError(t, err)
+	assert.Equal(t, SleepReason(""), reason)
+
+	woken = now.Add(-2 * time.Hour)
+	reason, err = ShouldSleep(policy, depl, false, now)
+	assert.NoError(t, err)
+	assert.Equal(t, SleepReason_Schedule, reason)
+
+	sleeping := now.Add(-time.Hour)
+	depl.SleepingSince = &sleeping
+	depl.SleepReason = string(SleepReason_Schedule)
+
+	wake, err := ShouldWake(policy, depl, now)
+	assert.NoError(t, err)
+	assert.False(t, wake)
+
+	wake, err = ShouldWake(policy, depl, now.Add(11*time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, wake)
+
+	// deployments are woken up when the policy which put them to sleep is removed
+	wake, err = ShouldWake(nil, depl, now)
+	assert.NoError(t, err)
+	assert.True(t, wake)
+
+	depl.SleepReason = string(SleepReason_Idle)
+	wake, err = ShouldWake(&types.PreviewEnvironmentPolicy{SleepAfterIdleHours: 4}, depl, now.Add(24*time.Hour))
+	assert.NoError(t, err)
+	assert.False(t, wake)
+}
+
+func TestSleepAndWake(t *testing.T) {
+	ctx := context.Background()
+	namespace := "pr-42-api"
+	replicas := int32(3)
+
+	clientset := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-web", Namespace: namespace},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		},
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-web", Namespace: namespace},
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{{Host: "pr-42.previews.acme.dev"}},
+			},
+		},
+	)
+
+	_, err := Sleep(ctx, clientset, namespace, WakerBackend{})
+	assert.Error(t, err)
+
+	hosts, err := Sleep(ctx, clientset, namespace, WakerBackend{Host: "waker.porter.run", Port: 8081})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pr-42.previews.acme.dev"}, hosts)
+
+	service, err := clientset.CoreV1().Services(namespace).Get(ctx, WakerServiceName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, v1.ServiceTypeExternalName, service.Spec.Type)
+	assert.Equal(t, "waker.porter.run", service.Spec.ExternalName)
+	assert.Equal(t, int32(8081), service.Spec.Ports[0].Port)
+
+	ingress, err := clientset.NetworkingV1().Ingresses(namespace).Get(ctx, "api-web", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, WakerServiceName, ingress.Annotations["nginx.ingress.kubernetes.io/default-backend"])
+
+	deployment, err := clientset.AppsV1().Deployments(namespace).Get(ctx, "api-web", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), *deployment.Spec.Replicas)
+	assert.Equal(t, "3", deployment.Annotations[AnnotationKey_SleepReplicas])
+
+	// sleeping again does not overwrite the replicas that the deployment is woken up with
+	_, err = Sleep(ctx, clientset, namespace, WakerBackend{Host: "waker.porter.run", Port: 8081})
+	assert.NoError(t, err)
+
+	removed, err := RemoveWakerBackend(ctx, clientset, namespace)
+	assert.NoError(t, err)
+	assert.False(t, removed)
+
+	assert.NoError(t, Wake(ctx, clientset, namespace))
+
+	deployment, err = clientset.AppsV1().Deployments(namespace).Get(ctx, "api-web", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), *deployment.Spec.Replicas)
+	assert.NotContains(t, deployment.Annotations, AnnotationKey_SleepReplicas)
+
+	// the waker backend is kept until the deployment is ready
+	removed, err = RemoveWakerBackend(ctx, clientset, namespace)
+	assert.NoError(t, err)
+	assert.False(t, removed)
+
+	deployment.Status.UpdatedReplicas = 3
+	deployment.Status.AvailableReplicas = 3
+	_, err = clientset.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+
+	removed, err = RemoveWakerBackend(ctx, clientset, namespace)
+	assert.NoError(t, err)
+	assert.True(t, removed)
+
+	ingress, err = clientset.NetworkingV1().Ingresses(namespace).Get(ctx, "api-web", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NotContains(t, ingress.Annotations, "nginx.ingress.kubernetes.io/default-backend")
+	assert.NotContains(t, ingress.Annotations, AnnotationKey_SleepDefaultBackend)
+
+	_, err = clientset.CoreV1().Services(namespace).Get(ctx, WakerServiceName, metav1.GetOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,254 @@
+package previewpolicy
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// WakerServiceName is the name of the service which routes the requests of sleeping deployments to the preview
+	// waker of the Porter instance
+	WakerServiceName = "porter-preview-waker"
+
+	// AnnotationKey_SleepReplicas is set on sleeping deployments to the number of replicas they are woken up with
+	AnnotationKey_SleepReplicas = "porter.run/sleep-replicas"
+	// AnnotationKey_SleepDefaultBackend is set on ingresses routed to the waker to their original default backend,
+	// which is empty if they did not have one
+	AnnotationKey_SleepDefaultBackend = "porter.run/sleep-default-backend"
+
+	// annotationKey_DefaultBackend is the service that ingress-nginx routes requests to when the service of an
+	// ingress has no endpoints, which is the case while a deployment is scaled to zero
+	annotationKey_DefaultBackend = "nginx.ingress.kubernetes.io/default-backend"
+)
+
+// WakerBackend is the address of the preview waker, which wakes up sleeping deployments when their ingress
+// receives a request
+type WakerBackend struct {
+	Host string
+	Port int32
+}
+
+// Sleep scales the deployments in a namespace to zero, and routes the requests to their ingresses to the preview
+// waker until they are woken up. It returns the hosts of the ingresses, by which the waker finds the deployment.
+func Sleep(ctx context.Context, clientset kubernetes.Interface, namespace string, waker WakerBackend) ([]string, error) {
+	if waker.Host == "" {
+		return nil, fmt.Errorf("no preview waker host is set")
+	}
+
+	if err := applyWakerService(ctx, clientset, namespace, waker); err != nil {
+		return nil, err
+	}
+
+	ingresses, err := clientset.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing ingresses: %w", err)
+	}
+
+	var hosts []string
+
+	for i := range ingresses.Items {
+		ingress := &ingresses.Items[i]
+
+		for _, rule := range ingress.Spec.Rules {
+			if rule.Host != "" {
+				hosts = append(hosts, rule.Host)
+			}
+		}
+
+		if _, ok := ingress.Annotations[AnnotationKey_SleepDefaultBackend]; ok {
+			continue
+		}
+
+		if ingress.Annotations == nil {
+			ingress.Annotations = make(map[string]string)
+		}
+
+		ingress.Annotations[AnnotationKey_SleepDefaultBackend] = ingress.Annotations[annotationKey_DefaultBackend]
+		ingress.Annotations[annotationKey_DefaultBackend] = WakerServiceName
+
+		if _, err := clientset.NetworkingV1().Ingresses(namespace).Update(ctx, ingress, metav1.UpdateOptions{}); err != nil {
+			return nil, fmt.Errorf("error routing ingress %s to the preview waker: %w", ingress.Name, err)
+		}
+	}
+
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing deployments: %w", err)
+	}
+
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+
+		if replicas == 0 {
+			continue
+		}
+
+		if deployment.Annotations == nil {
+			deployment.Annotations = make(map[string]string)
+		}
+
+		deployment.Annotations[AnnotationKey_SleepReplicas] = strconv.Itoa(int(replicas))
+		zero := int32(0)
+		deployment.Spec.Replicas = &zero
+
+		if _, err := clientset.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{}); err != nil {
+			return nil, fmt.Errorf("error scaling deployment %s to zero: %w", deployment.Name, err)
+		}
+	}
+
+	return hosts, nil
+}
+
+// Wake scales the sleeping deployments in a namespace back to their replicas. Ingresses stay routed to the waker, which
+// only receives requests until the deployments are ready, and are restored by RemoveWakerBackend.
+func Wake(ctx context.Context, clientset kubernetes.Interface, namespace string) error {
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error listing deployments: %w", err)
+	}
+
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+
+		rawReplicas, ok := deployment.Annotations[AnnotationKey_SleepReplicas]
+		if !ok {
+			continue
+		}
+
+		replicas, err := strconv.Atoi(rawReplicas)
+		if err != nil || replicas < 1 {
+			replicas = 1
+		}
+
+		// deployments which were scaled up by a deploy while asleep keep their replicas
+		if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == 0 {
+			r := int32(replicas)
+			deployment.Spec.Replicas = &r
+		}
+
+		delete(deployment.Annotations, AnnotationKey_SleepReplicas)
+
+		if _, err := clientset.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("error waking deployment %s: %w", deployment.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// RemoveWakerBackend restores the ingresses in a namespace and deletes the waker service once every deployment in the
+// namespace is ready, so that no requests are routed to the waker while the deployments are waking up. It returns
+// true once the waker backend is removed.
+func RemoveWakerBackend(ctx context.Context, clientset kubernetes.Interface, namespace string) (bool, error) {
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return false, fmt.Errorf("error listing deployments: %w", err)
+	}
+
+	for _, deployment := range deployments.Items {
+		if _, asleep := deployment.Annotations[AnnotationKey_SleepReplicas]; asleep || !deploymentReady(deployment) {
+			return false, nil
+		}
+	}
+
+	ingresses, err := clientset.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return false, fmt.Errorf("error listing ingresses: %w", err)
+	}
+
+	for i := range ingresses.Items {
+		ingress := &ingresses.Items[i]
+
+		defaultBackend, ok := ingress.Annotations[AnnotationKey_SleepDefaultBackend]
+		if !ok {
+			continue
+		}
+
+		if defaultBackend == "" {
+			delete(ingress.Annotations, annotationKey_DefaultBackend)
+		} else {
+			ingress.Annotations[annotationKey_DefaultBackend] = defaultBackend
+		}
+		delete(ingress.Annotations, AnnotationKey_SleepDefaultBackend)
+
+		if _, err := clientset.NetworkingV1().Ingresses(namespace).Update(ctx, ingress, metav1.UpdateOptions{}); err != nil {
+			return false, fmt.Errorf("error restoring ingress %s: %w", ingress.Name, err)
+		}
+	}
+
+	err = clientset.CoreV1().Services(namespace).Delete(ctx, WakerServiceName, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return false, fmt.Errorf("error deleting preview waker service: %w", err)
+	}
+
+	return true, nil
+}
+
+// applyWakerService creates or updates the ExternalName service which points to the preview waker
+func applyWakerService(ctx context.Context, clientset kubernetes.Interface, namespace string, waker WakerBackend) error {
+	port := waker.Port
+	if port == 0 {
+		port = 80
+	}
+
+	spec := v1.ServiceSpec{
+		Type:         v1.ServiceTypeExternalName,
+		ExternalName: waker.Host,
+		Ports: []v1.ServicePort{
+			{Name: "http", Port: port, Protocol: v1.ProtocolTCP},
+		},
+	}
+
+	existing, err := clientset.CoreV1().Services(namespace).Get(ctx, WakerServiceName, metav1.GetOptions{})
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("error getting preview waker service: %w", err)
+		}
+
+		_, err = clientset.CoreV1().Services(namespace).Create(ctx, &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      WakerServiceName,
+				Namespace: namespace,
+				Labels:    map[string]string{"app.kubernetes.io/managed-by": "porter"},
+			},
+			Spec: spec,
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("error creating preview waker service: %w", err)
+		}
+
+		return nil
+	}
+
+	existing.Spec.Type = spec.Type
+	existing.Spec.ExternalName = spec.ExternalName
+	existing.Spec.Ports = spec.Ports
+
+	if _, err := clientset.CoreV1().Services(namespace).Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating preview waker service: %w", err)
+	}
+
+	return nil
+}
+
+// deploymentReady returns true if all replicas of a deployment are updated and available
+func deploymentReady(deployment appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	return deployment.Status.UpdatedReplicas >= replicas && deployment.Status.AvailableReplicas >= replicas
+}
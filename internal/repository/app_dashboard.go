@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// AppDashboardRepository represents the set of queries on the AppDashboard model
+type AppDashboardRepository interface {
+	CreateAppDashboard(dashboard *models.AppDashboard) (*models.AppDashboard, error)
+	ReadAppDashboard(porterAppID, dashboardID uint) (*models.AppDashboard, error)
+	ReadAppDashboardByName(porterAppID uint, name string) (*models.AppDashboard, error)
+	ListAppDashboards(porterAppID uint) ([]*models.AppDashboard, error)
+	UpdateAppDashboard(dashboard *models.AppDashboard) (*models.AppDashboard, error)
+	DeleteAppDashboard(dashboard *models.AppDashboard) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// AppLogAlertRuleRepository represents the set of queries on the AppLogAlertRule model
+type AppLogAlertRuleRepository interface {
+	CreateAppLogAlertRule(rule *models.AppLogAlertRule) (*models.AppLogAlertRule, error)
+	ReadAppLogAlertRule(porterAppID, ruleID uint) (*models.AppLogAlertRule, error)
+	ReadAppLogAlertRuleByName(porterAppID uint, deploymentTargetID, name string) (*models.AppLogAlertRule, error)
+	ListAppLogAlertRules(porterAppID uint, deploymentTargetID string) ([]*models.AppLogAlertRule, error)
+	ListAppLogAlertRulesByClusterID(clusterID uint) ([]*models.AppLogAlertRule, error)
+	UpdateAppLogAlertRule(rule *models.AppLogAlertRule) (*models.AppLogAlertRule, error)
+	DeleteAppLogAlertRule(rule *models.AppLogAlertRule) error
+}
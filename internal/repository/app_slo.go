@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// AppSLORepository represents the set of queries on the AppSLO and AppSLOStatus models
+type AppSLORepository interface {
+	CreateAppSLO(slo *models.AppSLO) (*models.AppSLO, error)
+	ReadAppSLO(porterAppID, sloID uint) (*models.AppSLO, error)
+	ListAppSLOs(porterAppID uint, deploymentTargetID string) ([]*models.AppSLO, error)
+	ListAppSLOsByClusterID(clusterID uint) ([]*models.AppSLO, error)
+	UpdateAppSLO(slo *models.AppSLO) (*models.AppSLO, error)
+	DeleteAppSLO(slo *models.AppSLO) error
+	CreateAppSLOStatus(status *models.AppSLOStatus) (*models.AppSLOStatus, error)
+	ListAppSLOStatuses(sloID uint, limit int) ([]*models.AppSLOStatus, error)
+	DeleteAppSLOStatusesBefore(sloID uint, before time.Time) error
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// AppSyntheticCheckRepository represents the set of queries on the AppSyntheticCheck and AppSyntheticCheckResult models
+type AppSyntheticCheckRepository interface {
+	CreateAppSyntheticCheck(check *models.AppSyntheticCheck) (*models.AppSyntheticCheck, error)
+	ReadAppSyntheticCheck(porterAppID, checkID uint) (*models.AppSyntheticCheck, error)
+	ListAppSyntheticChecks(porterAppID uint, deploymentTargetID string) ([]*models.AppSyntheticCheck, error)
+	ListDueAppSyntheticChecks(now time.Time, limit int) ([]*models.AppSyntheticCheck, error)
+	UpdateAppSyntheticCheck(check *models.AppSyntheticCheck) (*models.AppSyntheticCheck, error)
+	DeleteAppSyntheticCheck(check *models.AppSyntheticCheck) error
+	CreateAppSyntheticCheckResult(result *models.AppSyntheticCheckResult) (*models.AppSyntheticCheckResult, error)
+	ListAppSyntheticCheckResults(checkID uint, limit int) ([]*models.AppSyntheticCheckResult, error)
+	DeleteAppSyntheticCheckResultsBefore(checkID uint, before time.Time) error
+}
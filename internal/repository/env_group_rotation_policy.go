@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// EnvGroupRotationPolicyRepository represents the set of queries on the EnvGroupRotationPolicy model
+type EnvGroupRotationPolicyRepository interface {
+	CreateEnvGroupRotationPolicy(policy *models.EnvGroupRotationPolicy) (*models.EnvGroupRotationPolicy, error)
+	ReadEnvGroupRotationPolicy(clusterID uint, envGroupName string, policyID uint) (*models.EnvGroupRotationPolicy, error)
+	ReadEnvGroupRotationPolicyByKey(clusterID uint, envGroupName, key string) (*models.EnvGroupRotationPolicy, error)
+	ListEnvGroupRotationPolicies(clusterID uint, envGroupName string) ([]*models.EnvGroupRotationPolicy, error)
+	ListDueEnvGroupRotationPoliciesByClusterID(clusterID uint, now time.Time) ([]*models.EnvGroupRotationPolicy, error)
+	UpdateEnvGroupRotationPolicy(policy *models.EnvGroupRotationPolicy) (*models.EnvGroupRotationPolicy, error)
+	DeleteEnvGroupRotationPolicy(policy *models.EnvGroupRotationPolicy) error
+}
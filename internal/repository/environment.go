@@ -8,6 +8,7 @@ type EnvironmentRepository interface {
 	ReadEnvironmentByID(projectID, clusterID, envID uint) (*models.Environment, error)
 	ReadEnvironmentByOwnerRepoName(projectID, clusterID uint, owner, repo string) (*models.Environment, error)
 	ReadEnvironmentByWebhookIDOwnerRepoName(webhookID, owner, repo string) (*models.Environment, error)
+	ReadEnvironmentForDeployment(deployment *models.Deployment) (*models.Environment, error)
 	ListEnvironments(projectID, clusterID uint) ([]*models.Environment, error)
 	UpdateEnvironment(environment *models.Environment) (*models.Environment, error)
 	DeleteEnvironment(env *models.Environment) (*models.Environment, error)
@@ -19,6 +20,7 @@ type EnvironmentRepository interface {
 	ReadDeploymentForBranch(environmentID uint, owner, name, branch string) (*models.Deployment, error)
 	ListDeploymentsByCluster(projectID, clusterID uint, states ...string) ([]*models.Deployment, error)
 	ListDeployments(environmentID uint, states ...string) ([]*models.Deployment, error)
+	ListDeploymentsBySleepingHost(host string) ([]*models.Deployment, error)
 	UpdateDeployment(deployment *models.Deployment) (*models.Deployment, error)
 	DeleteDeployment(deployment *models.Deployment) (*models.Deployment, error)
 }
@@ -0,0 +1,74 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// AppDashboardRepository uses gorm.DB for querying the database
+type AppDashboardRepository struct {
+	db *gorm.DB
+}
+
+// NewAppDashboardRepository returns an AppDashboardRepository which uses
+// gorm.DB for querying the database
+func NewAppDashboardRepository(db *gorm.DB) repository.AppDashboardRepository {
+	return &AppDashboardRepository{db}
+}
+
+// CreateAppDashboard creates a new app dashboard
+func (repo *AppDashboardRepository) CreateAppDashboard(dashboard *models.AppDashboard) (*models.AppDashboard, error) {
+	if err := repo.db.Create(dashboard).Error; err != nil {
+		return nil, err
+	}
+
+	return dashboard, nil
+}
+
+// ReadAppDashboard reads a dashboard of an app by its id
+func (repo *AppDashboardRepository) ReadAppDashboard(porterAppID, dashboardID uint) (*models.AppDashboard, error) {
+	dashboard := &models.AppDashboard{}
+
+	if err := repo.db.Where("porter_app_id = ? AND id = ?", porterAppID, dashboardID).First(dashboard).Error; err != nil {
+		return nil, err
+	}
+
+	return dashboard, nil
+}
+
+// ReadAppDashboardByName reads a dashboard of an app by its name
+func (repo *AppDashboardRepository) ReadAppDashboardByName(porterAppID uint, name string) (*models.AppDashboard, error) {
+	dashboard := &models.AppDashboard{}
+
+	if err := repo.db.Where("porter_app_id = ? AND name = ?", porterAppID, name).First(dashboard).Error; err != nil {
+		return nil, err
+	}
+
+	return dashboard, nil
+}
+
+// ListAppDashboards lists the dashboards of an app, ordered by name
+func (repo *AppDashboardRepository) ListAppDashboards(porterAppID uint) ([]*models.AppDashboard, error) {
+	dashboards := []*models.AppDashboard{}
+
+	if err := repo.db.Where("porter_app_id = ?", porterAppID).Order("name ASC").Find(&dashboards).Error; err != nil {
+		return nil, err
+	}
+
+	return dashboards, nil
+}
+
+// UpdateAppDashboard updates an app dashboard
+func (repo *AppDashboardRepository) UpdateAppDashboard(dashboard *models.AppDashboard) (*models.AppDashboard, error) {
+	if err := repo.db.Save(dashboard).Error; err != nil {
+		return nil, err
+	}
+
+	return dashboard, nil
+}
+
+// DeleteAppDashboard deletes an app dashboard
+func (repo *AppDashboardRepository) DeleteAppDashboard(dashboard *models.AppDashboard) error {
+	return repo.db.Delete(dashboard).Error
+}
@@ -0,0 +1,91 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// AppLogAlertRuleRepository uses gorm.DB for querying the database
+type AppLogAlertRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewAppLogAlertRuleRepository returns an AppLogAlertRuleRepository which uses
+// gorm.DB for querying the database
+func NewAppLogAlertRuleRepository(db *gorm.DB) repository.AppLogAlertRuleRepository {
+	return &AppLogAlertRuleRepository{db}
+}
+
+// CreateAppLogAlertRule creates a new log alert rule
+func (repo *AppLogAlertRuleRepository) CreateAppLogAlertRule(rule *models.AppLogAlertRule) (*models.AppLogAlertRule, error) {
+	if err := repo.db.Create(rule).Error; err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// ReadAppLogAlertRule reads a log alert rule of an app by its id
+func (repo *AppLogAlertRuleRepository) ReadAppLogAlertRule(porterAppID, ruleID uint) (*models.AppLogAlertRule, error) {
+	rule := &models.AppLogAlertRule{}
+
+	if err := repo.db.Where("porter_app_id = ? AND id = ?", porterAppID, ruleID).First(rule).Error; err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// ReadAppLogAlertRuleByName reads a log alert rule of an app in a deployment target by its name
+func (repo *AppLogAlertRuleRepository) ReadAppLogAlertRuleByName(porterAppID uint, deploymentTargetID, name string) (*models.AppLogAlertRule, error) {
+	rule := &models.AppLogAlertRule{}
+
+	if err := repo.db.Where(
+		"porter_app_id = ? AND deployment_target_id = ? AND name = ?",
+		porterAppID, deploymentTargetID, name,
+	).First(rule).Error; err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// ListAppLogAlertRules lists the log alert rules of an app in a deployment target, ordered by name
+func (repo *AppLogAlertRuleRepository) ListAppLogAlertRules(porterAppID uint, deploymentTargetID string) ([]*models.AppLogAlertRule, error) {
+	rules := []*models.AppLogAlertRule{}
+
+	if err := repo.db.Where(
+		"porter_app_id = ? AND deployment_target_id = ?",
+		porterAppID, deploymentTargetID,
+	).Order("name ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// ListAppLogAlertRulesByClusterID lists the log alert rules of every app in a cluster
+func (repo *AppLogAlertRuleRepository) ListAppLogAlertRulesByClusterID(clusterID uint) ([]*models.AppLogAlertRule, error) {
+	rules := []*models.AppLogAlertRule{}
+
+	if err := repo.db.Where("cluster_id = ?", clusterID).Order("id ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// UpdateAppLogAlertRule updates a log alert rule
+func (repo *AppLogAlertRuleRepository) UpdateAppLogAlertRule(rule *models.AppLogAlertRule) (*models.AppLogAlertRule, error) {
+	if err := repo.db.Save(rule).Error; err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// DeleteAppLogAlertRule deletes a log alert rule
+func (repo *AppLogAlertRuleRepository) DeleteAppLogAlertRule(rule *models.AppLogAlertRule) error {
+	return repo.db.Delete(rule).Error
+}
@@ -0,0 +1,102 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// AppSLORepository uses gorm.DB for querying the database
+type AppSLORepository struct {
+	db *gorm.DB
+}
+
+// NewAppSLORepository returns an AppSLORepository which uses
+// gorm.DB for querying the database
+func NewAppSLORepository(db *gorm.DB) repository.AppSLORepository {
+	return &AppSLORepository{db}
+}
+
+// CreateAppSLO creates a new app SLO
+func (repo *AppSLORepository) CreateAppSLO(slo *models.AppSLO) (*models.AppSLO, error) {
+	if err := repo.db.Create(slo).Error; err != nil {
+		return nil, err
+	}
+
+	return slo, nil
+}
+
+// ReadAppSLO reads an SLO of an app by its id
+func (repo *AppSLORepository) ReadAppSLO(porterAppID, sloID uint) (*models.AppSLO, error) {
+	slo := &models.AppSLO{}
+
+	if err := repo.db.Where("porter_app_id = ? AND id = ?", porterAppID, sloID).First(slo).Error; err != nil {
+		return nil, err
+	}
+
+	return slo, nil
+}
+
+// ListAppSLOs lists the SLOs of an app in a deployment target, ordered by name
+func (repo *AppSLORepository) ListAppSLOs(porterAppID uint, deploymentTargetID string) ([]*models.AppSLO, error) {
+	slos := []*models.AppSLO{}
+
+	if err := repo.db.Where("porter_app_id = ? AND deployment_target_id = ?", porterAppID, deploymentTargetID).
+		Order("name ASC").Find(&slos).Error; err != nil {
+		return nil, err
+	}
+
+	return slos, nil
+}
+
+// ListAppSLOsByClusterID lists the SLOs of all apps in a cluster
+func (repo *AppSLORepository) ListAppSLOsByClusterID(clusterID uint) ([]*models.AppSLO, error) {
+	slos := []*models.AppSLO{}
+
+	if err := repo.db.Where("cluster_id = ?", clusterID).Order("id ASC").Find(&slos).Error; err != nil {
+		return nil, err
+	}
+
+	return slos, nil
+}
+
+// UpdateAppSLO updates an app SLO
+func (repo *AppSLORepository) UpdateAppSLO(slo *models.AppSLO) (*models.AppSLO, error) {
+	if err := repo.db.Save(slo).Error; err != nil {
+		return nil, err
+	}
+
+	return slo, nil
+}
+
+// DeleteAppSLO deletes an app SLO
+func (repo *AppSLORepository) DeleteAppSLO(slo *models.AppSLO) error {
+	return repo.db.Delete(slo).Error
+}
+
+// CreateAppSLOStatus stores the result of an evaluation of an SLO
+func (repo *AppSLORepository) CreateAppSLOStatus(status *models.AppSLOStatus) (*models.AppSLOStatus, error) {
+	if err := repo.db.Create(status).Error; err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// ListAppSLOStatuses lists the most recent evaluations of an SLO, most recent first
+func (repo *AppSLORepository) ListAppSLOStatuses(sloID uint, limit int) ([]*models.AppSLOStatus, error) {
+	statuses := []*models.AppSLOStatus{}
+
+	if err := repo.db.Where("app_slo_id = ?", sloID).Order("checked_at DESC").Limit(limit).Find(&statuses).Error; err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+// DeleteAppSLOStatusesBefore permanently deletes the evaluations of an SLO which were made before the given time
+func (repo *AppSLORepository) DeleteAppSLOStatusesBefore(sloID uint, before time.Time) error {
+	return repo.db.Unscoped().Where("app_slo_id = ? AND checked_at < ?", sloID, before).Delete(&models.AppSLOStatus{}).Error
+}
@@ -0,0 +1,102 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// AppSyntheticCheckRepository uses gorm.DB for querying the database
+type AppSyntheticCheckRepository struct {
+	db *gorm.DB
+}
+
+// NewAppSyntheticCheckRepository returns an AppSyntheticCheckRepository which uses
+// gorm.DB for querying the database
+func NewAppSyntheticCheckRepository(db *gorm.DB) repository.AppSyntheticCheckRepository {
+	return &AppSyntheticCheckRepository{db}
+}
+
+// CreateAppSyntheticCheck creates a new synthetic check
+func (repo *AppSyntheticCheckRepository) CreateAppSyntheticCheck(check *models.AppSyntheticCheck) (*models.AppSyntheticCheck, error) {
+	if err := repo.db.Create(check).Error; err != nil {
+		return nil, err
+	}
+
+	return check, nil
+}
+
+// ReadAppSyntheticCheck reads a synthetic check of an app by its id
+func (repo *AppSyntheticCheckRepository) ReadAppSyntheticCheck(porterAppID, checkID uint) (*models.AppSyntheticCheck, error) {
+	check := &models.AppSyntheticCheck{}
+
+	if err := repo.db.Where("porter_app_id = ? AND id = ?", porterAppID, checkID).First(check).Error; err != nil {
+		return nil, err
+	}
+
+	return check, nil
+}
+
+// ListAppSyntheticChecks lists the synthetic checks of an app in a deployment target, ordered by name
+func (repo *AppSyntheticCheckRepository) ListAppSyntheticChecks(porterAppID uint, deploymentTargetID string) ([]*models.AppSyntheticCheck, error) {
+	checks := []*models.AppSyntheticCheck{}
+
+	if err := repo.db.Where("porter_app_id = ? AND deployment_target_id = ?", porterAppID, deploymentTargetID).
+		Order("name ASC").Find(&checks).Error; err != nil {
+		return nil, err
+	}
+
+	return checks, nil
+}
+
+// ListDueAppSyntheticChecks lists the synthetic checks of all apps which are due to run, the most overdue first
+func (repo *AppSyntheticCheckRepository) ListDueAppSyntheticChecks(now time.Time, limit int) ([]*models.AppSyntheticCheck, error) {
+	checks := []*models.AppSyntheticCheck{}
+
+	if err := repo.db.Where("next_check_at <= ?", now).Order("next_check_at ASC").Limit(limit).Find(&checks).Error; err != nil {
+		return nil, err
+	}
+
+	return checks, nil
+}
+
+// UpdateAppSyntheticCheck updates a synthetic check
+func (repo *AppSyntheticCheckRepository) UpdateAppSyntheticCheck(check *models.AppSyntheticCheck) (*models.AppSyntheticCheck, error) {
+	if err := repo.db.Save(check).Error; err != nil {
+		return nil, err
+	}
+
+	return check, nil
+}
+
+// DeleteAppSyntheticCheck deletes a synthetic check
+func (repo *AppSyntheticCheckRepository) DeleteAppSyntheticCheck(check *models.AppSyntheticCheck) error {
+	return repo.db.Delete(check).Error
+}
+
+// CreateAppSyntheticCheckResult stores the result of a run of a synthetic check
+func (repo *AppSyntheticCheckRepository) CreateAppSyntheticCheckResult(result *models.AppSyntheticCheckResult) (*models.AppSyntheticCheckResult, error) {
+	if err := repo.db.Create(result).Error; err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListAppSyntheticCheckResults lists the most recent results of a synthetic check, most recent first
+func (repo *AppSyntheticCheckRepository) ListAppSyntheticCheckResults(checkID uint, limit int) ([]*models.AppSyntheticCheckResult, error) {
+	results := []*models.AppSyntheticCheckResult{}
+
+	if err := repo.db.Where("app_synthetic_check_id = ?", checkID).Order("checked_at DESC").Limit(limit).Find(&results).Error; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// DeleteAppSyntheticCheckResultsBefore permanently deletes the results of a synthetic check from before the given time
+func (repo *AppSyntheticCheckRepository) DeleteAppSyntheticCheckResultsBefore(checkID uint, before time.Time) error {
+	return repo.db.Unscoped().Where("app_synthetic_check_id = ? AND checked_at < ?", checkID, before).Delete(&models.AppSyntheticCheckResult{}).Error
+}
@@ -0,0 +1,99 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// EnvGroupRotationPolicyRepository uses gorm.DB for querying the database
+type EnvGroupRotationPolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewEnvGroupRotationPolicyRepository returns an EnvGroupRotationPolicyRepository which uses
+// gorm.DB for querying the database
+func NewEnvGroupRotationPolicyRepository(db *gorm.DB) repository.EnvGroupRotationPolicyRepository {
+	return &EnvGroupRotationPolicyRepository{db}
+}
+
+// CreateEnvGroupRotationPolicy creates a new rotation policy
+func (repo *EnvGroupRotationPolicyRepository) CreateEnvGroupRotationPolicy(policy *models.EnvGroupRotationPolicy) (*models.EnvGroupRotationPolicy, error) {
+	if err := repo.db.Create(policy).Error; err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// ReadEnvGroupRotationPolicy reads a rotation policy of an environment group by its id
+func (repo *EnvGroupRotationPolicyRepository) ReadEnvGroupRotationPolicy(clusterID uint, envGroupName string, policyID uint) (*models.EnvGroupRotationPolicy, error) {
+	policy := &models.EnvGroupRotationPolicy{}
+
+	if err := repo.db.Where(
+		"cluster_id = ? AND env_group_name = ? AND id = ?",
+		clusterID, envGroupName, policyID,
+	).First(policy).Error; err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// ReadEnvGroupRotationPolicyByKey reads the rotation policy of a key of an environment group
+func (repo *EnvGroupRotationPolicyRepository) ReadEnvGroupRotationPolicyByKey(clusterID uint, envGroupName, key string) (*models.EnvGroupRotationPolicy, error) {
+	policy := &models.EnvGroupRotationPolicy{}
+
+	if err := repo.db.Where(
+		"cluster_id = ? AND env_group_name = ? AND key = ?",
+		clusterID, envGroupName, key,
+	).First(policy).Error; err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// ListEnvGroupRotationPolicies lists the rotation policies of an environment group, ordered by key
+func (repo *EnvGroupRotationPolicyRepository) ListEnvGroupRotationPolicies(clusterID uint, envGroupName string) ([]*models.EnvGroupRotationPolicy, error) {
+	policies := []*models.EnvGroupRotationPolicy{}
+
+	if err := repo.db.Where(
+		"cluster_id = ? AND env_group_name = ?",
+		clusterID, envGroupName,
+	).Order("key ASC").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// ListDueEnvGroupRotationPoliciesByClusterID lists the rotation policies in a cluster whose next rotation is at or before now
+func (repo *EnvGroupRotationPolicyRepository) ListDueEnvGroupRotationPoliciesByClusterID(clusterID uint, now time.Time) ([]*models.EnvGroupRotationPolicy, error) {
+	policies := []*models.EnvGroupRotationPolicy{}
+
+	if err := repo.db.Where(
+		"cluster_id = ? AND next_rotation_at <= ?",
+		clusterID, now,
+	).Order("env_group_name ASC, key ASC").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// UpdateEnvGroupRotationPolicy updates a rotation policy
+func (repo *EnvGroupRotationPolicyRepository) UpdateEnvGroupRotationPolicy(policy *models.EnvGroupRotationPolicy) (*models.EnvGroupRotationPolicy, error) {
+	if err := repo.db.Save(policy).Error; err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// DeleteEnvGroupRotationPolicy deletes a rotation policy
+func (repo *EnvGroupRotationPolicyRepository) DeleteEnvGroupRotationPolicy(policy *models.EnvGroupRotationPolicy) error {
+	return repo.db.Delete(policy).Error
+}
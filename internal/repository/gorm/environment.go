@@ -62,6 +62,17 @@ func (repo *EnvironmentRepository) ReadEnvironmentByID(projectID, clusterID, env
 	return env, nil
 }
 
+// ReadEnvironmentForDeployment reads the environment that a deployment belongs to
+func (repo *EnvironmentRepository) ReadEnvironmentForDeployment(deployment *models.Deployment) (*models.Environment, error) {
+	env := &models.Environment{}
+
+	if err := repo.db.Where("id = ?", deployment.EnvironmentID).First(&env).Error; err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
 func (repo *EnvironmentRepository) ReadEnvironmentByOwnerRepoName(
 	projectID, clusterID uint,
 	gitRepoOwner, gitRepoName string,
@@ -269,6 +280,21 @@ func (repo *EnvironmentRepository) ListDeployments(environmentID uint, states ..
 	return depls, nil
 }
 
+// ListDeploymentsBySleepingHost lists the deployments, across all clusters, whose ingress host is routed to the
+// preview waker. The host is matched exactly by the caller, since a host may be contained in another.
+func (repo *EnvironmentRepository) ListDeploymentsBySleepingHost(host string) ([]*models.Deployment, error) {
+	depls := make([]*models.Deployment, 0)
+
+	if err := repo.db.
+		Joins("INNER JOIN environments ON environments.id = deployments.environment_id").
+		Where("deployments.sleeping_hosts LIKE ? AND environments.deleted_at IS NULL", "%"+host+"%").
+		Find(&depls).Error; err != nil {
+		return nil, err
+	}
+
+	return depls, nil
+}
+
 func (repo *EnvironmentRepository) DeleteDeployment(deployment *models.Deployment) (*models.Deployment, error) {
 	if err := repo.db.Delete(deployment).Error; err != nil {
 		return nil, err
@@ -3,7 +3,9 @@ package gorm
 import (
 	"encoding/hex"
 	"fmt"
+	"time"
 
+	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/encryption"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
@@ -245,6 +247,30 @@ func (repo *InfraRepository) UpdateOperation(
 	return operation, nil
 }
 
+// ApproveOperation starts an operation which is awaiting approval. The status is only updated if it is
+// still awaiting approval, so only one of several concurrent approvals can start the operation.
+func (repo *InfraRepository) ApproveOperation(operation *models.Operation, approvedAt time.Time) (bool, error) {
+	res := repo.db.Model(&models.Operation{}).
+		Where("id = ? AND status = ?", operation.ID, types.OperationStatusAwaitingApproval).
+		Updates(map[string]interface{}{
+			"status":      types.OperationStatusStarting,
+			"approved_at": approvedAt,
+		})
+
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+
+	operation.Status = types.OperationStatusStarting
+	operation.ApprovedAt = &approvedAt
+
+	return true, nil
+}
+
 // EncryptInfraData will encrypt the infra data before
 // writing to the DB
 func (repo *InfraRepository) EncryptInfraData(
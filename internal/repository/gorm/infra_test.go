@@ -2,6 +2,7 @@ package gorm_test
 
 import (
 	"testing"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -89,3 +90,68 @@ func TestListInfrasByProjectID(t *testing.T) {
 		t.Error(diff)
 	}
 }
+
+func TestApproveOperation(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_approve_operation.db",
+	}
+
+	setupTestEnv(tester, t)
+	initProject(tester, t)
+	initInfra(tester, t)
+	defer cleanup(tester, t)
+
+	infra := tester.initInfras[0]
+
+	_, err := tester.repo.Infra().AddOperation(infra, &models.Operation{
+		UID:     "0123456789abcdef0123",
+		InfraID: infra.ID,
+		Type:    "update",
+		Status:  types.OperationStatusAwaitingApproval,
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	// read the operation twice, as two concurrent approvals would
+	first, err := tester.repo.Infra().ReadOperation(infra.ID, "0123456789abcdef0123")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	second, err := tester.repo.Infra().ReadOperation(infra.ID, "0123456789abcdef0123")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	approved, err := tester.repo.Infra().ApproveOperation(first, time.Now())
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if !approved {
+		t.Fatalf("expected the first approval to succeed")
+	}
+
+	approved, err = tester.repo.Infra().ApproveOperation(second, time.Now())
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if approved {
+		t.Fatalf("expected the second approval to be refused")
+	}
+
+	operation, err := tester.repo.Infra().ReadOperation(infra.ID, "0123456789abcdef0123")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if operation.Status != types.OperationStatusStarting {
+		t.Errorf("incorrect operation status: expected %s, got %s\n", types.OperationStatusStarting, operation.Status)
+	}
+
+	if operation.ApprovedAt == nil {
+		t.Errorf("expected approved at to be set")
+	}
+}
@@ -0,0 +1,65 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// LogSearchRepository uses gorm.DB for querying the database
+type LogSearchRepository struct {
+	db *gorm.DB
+}
+
+// NewLogSearchRepository returns a LogSearchRepository which uses
+// gorm.DB for querying the database
+func NewLogSearchRepository(db *gorm.DB) repository.LogSearchRepository {
+	return &LogSearchRepository{db}
+}
+
+// CreateLogSearch creates a new saved log search
+func (repo *LogSearchRepository) CreateLogSearch(search *models.LogSearch) (*models.LogSearch, error) {
+	if err := repo.db.Create(search).Error; err != nil {
+		return nil, err
+	}
+
+	return search, nil
+}
+
+// ReadLogSearch reads a saved log search of a project by its id
+func (repo *LogSearchRepository) ReadLogSearch(projectID, searchID uint) (*models.LogSearch, error) {
+	search := &models.LogSearch{}
+
+	if err := repo.db.Where("project_id = ? AND id = ?", projectID, searchID).First(search).Error; err != nil {
+		return nil, err
+	}
+
+	return search, nil
+}
+
+// ReadLogSearchByName reads a saved log search of a project by its name
+func (repo *LogSearchRepository) ReadLogSearchByName(projectID uint, name string) (*models.LogSearch, error) {
+	search := &models.LogSearch{}
+
+	if err := repo.db.Where("project_id = ? AND name = ?", projectID, name).First(search).Error; err != nil {
+		return nil, err
+	}
+
+	return search, nil
+}
+
+// ListLogSearches lists the saved log searches of a project, ordered by name
+func (repo *LogSearchRepository) ListLogSearches(projectID uint) ([]*models.LogSearch, error) {
+	searches := []*models.LogSearch{}
+
+	if err := repo.db.Where("project_id = ?", projectID).Order("name ASC").Find(&searches).Error; err != nil {
+		return nil, err
+	}
+
+	return searches, nil
+}
+
+// DeleteLogSearch deletes a saved log search
+func (repo *LogSearchRepository) DeleteLogSearch(search *models.LogSearch) error {
+	return repo.db.Delete(search).Error
+}
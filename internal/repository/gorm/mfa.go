@@ -0,0 +1,216 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/internal/encryption"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MFARepository uses gorm.DB for querying the database
+type MFARepository struct {
+	db  *gorm.DB
+	key *[32]byte
+}
+
+// NewMFARepository returns a MFARepository which uses gorm.DB for querying the database.
+// It accepts an encryption key to encrypt TOTP secrets
+func NewMFARepository(db *gorm.DB, key *[32]byte) repository.MFARepository {
+	return &MFARepository{db, key}
+}
+
+// CreateUserTOTP creates a new TOTP enrollment for a user
+func (repo *MFARepository) CreateUserTOTP(totp *models.UserTOTP) (*models.UserTOTP, error) {
+	secret := totp.Secret
+
+	if err := repo.encryptTOTP(totp); err != nil {
+		return nil, err
+	}
+
+	if err := repo.db.Create(totp).Error; err != nil {
+		return nil, err
+	}
+
+	totp.Secret = secret
+
+	return totp, nil
+}
+
+// ReadUserTOTP finds the TOTP enrollment for a user
+func (repo *MFARepository) ReadUserTOTP(userID uint) (*models.UserTOTP, error) {
+	totp := &models.UserTOTP{}
+
+	if err := repo.db.Where("user_id = ?", userID).First(totp).Error; err != nil {
+		return nil, err
+	}
+
+	if err := repo.decryptTOTP(totp); err != nil {
+		return nil, err
+	}
+
+	return totp, nil
+}
+
+// UpdateUserTOTP modifies an existing TOTP enrollment in the database
+func (repo *MFARepository) UpdateUserTOTP(totp *models.UserTOTP) (*models.UserTOTP, error) {
+	secret := totp.Secret
+
+	if err := repo.encryptTOTP(totp); err != nil {
+		return nil, err
+	}
+
+	if err := repo.db.Save(totp).Error; err != nil {
+		return nil, err
+	}
+
+	totp.Secret = secret
+
+	return totp, nil
+}
+
+// DeleteUserTOTP removes a TOTP enrollment from the database
+func (repo *MFARepository) DeleteUserTOTP(totp *models.UserTOTP) error {
+	return repo.db.Unscoped().Delete(totp).Error
+}
+
+// ReplaceRecoveryCodes deletes all existing recovery codes for a user and stores the new set of hashes
+func (repo *MFARepository) ReplaceRecoveryCodes(userID uint, codeHashes []string) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		if len(codeHashes) == 0 {
+			return nil
+		}
+
+		codes := make([]*models.MFARecoveryCode, 0, len(codeHashes))
+
+		for _, hash := range codeHashes {
+			codes = append(codes, &models.MFARecoveryCode{
+				UserID:   userID,
+				CodeHash: hash,
+			})
+		}
+
+		return tx.Create(&codes).Error
+	})
+}
+
+// ListRecoveryCodes lists all recovery codes for a user, including used codes
+func (repo *MFARepository) ListRecoveryCodes(userID uint) ([]*models.MFARecoveryCode, error) {
+	codes := []*models.MFARecoveryCode{}
+
+	if err := repo.db.Where("user_id = ?", userID).Find(&codes).Error; err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// UseRecoveryCode marks an unused recovery code as used. It returns false if no unused
+// code matches the hash.
+func (repo *MFARepository) UseRecoveryCode(userID uint, codeHash string) (bool, error) {
+	res := repo.db.Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now().UTC())
+
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+// DeleteRecoveryCodes removes all recovery codes for a user
+func (repo *MFARepository) DeleteRecoveryCodes(userID uint) error {
+	return repo.db.Unscoped().Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error
+}
+
+// CreateWebAuthnCredential registers a new WebAuthn credential
+func (repo *MFARepository) CreateWebAuthnCredential(cred *models.WebAuthnCredential) (*models.WebAuthnCredential, error) {
+	if err := repo.db.Create(cred).Error; err != nil {
+		return nil, err
+	}
+
+	return cred, nil
+}
+
+// ListWebAuthnCredentials lists all WebAuthn credentials registered by a user
+func (repo *MFARepository) ListWebAuthnCredentials(userID uint) ([]*models.WebAuthnCredential, error) {
+	creds := []*models.WebAuthnCredential{}
+
+	if err := repo.db.Where("user_id = ?", userID).Order("id asc").Find(&creds).Error; err != nil {
+		return nil, err
+	}
+
+	return creds, nil
+}
+
+// ReadWebAuthnCredential finds a WebAuthn credential by its database id
+func (repo *MFARepository) ReadWebAuthnCredential(userID, id uint) (*models.WebAuthnCredential, error) {
+	cred := &models.WebAuthnCredential{}
+
+	if err := repo.db.Where("user_id = ? AND id = ?", userID, id).First(cred).Error; err != nil {
+		return nil, err
+	}
+
+	return cred, nil
+}
+
+// ReadWebAuthnCredentialByCredentialID finds a WebAuthn credential by the id assigned by the authenticator
+func (repo *MFARepository) ReadWebAuthnCredentialByCredentialID(userID uint, credentialID string) (*models.WebAuthnCredential, error) {
+	cred := &models.WebAuthnCredential{}
+
+	if err := repo.db.Where("user_id = ? AND credential_id = ?", userID, credentialID).First(cred).Error; err != nil {
+		return nil, err
+	}
+
+	return cred, nil
+}
+
+// UpdateWebAuthnCredential modifies an existing WebAuthn credential in the database
+func (repo *MFARepository) UpdateWebAuthnCredential(cred *models.WebAuthnCredential) (*models.WebAuthnCredential, error) {
+	if err := repo.db.Save(cred).Error; err != nil {
+		return nil, err
+	}
+
+	return cred, nil
+}
+
+// DeleteWebAuthnCredential removes a WebAuthn credential from the database
+func (repo *MFARepository) DeleteWebAuthnCredential(cred *models.WebAuthnCredential) error {
+	return repo.db.Unscoped().Delete(cred).Error
+}
+
+func (repo *MFARepository) encryptTOTP(totp *models.UserTOTP) error {
+	if len(totp.Secret) == 0 {
+		return nil
+	}
+
+	cipherData, err := encryption.Encrypt(totp.Secret, repo.key)
+	if err != nil {
+		return err
+	}
+
+	totp.Secret = cipherData
+
+	return nil
+}
+
+func (repo *MFARepository) decryptTOTP(totp *models.UserTOTP) error {
+	if len(totp.Secret) == 0 {
+		return nil
+	}
+
+	plaintext, err := encryption.Decrypt(totp.Secret, repo.key)
+	if err != nil {
+		return err
+	}
+
+	totp.Secret = plaintext
+
+	return nil
+}
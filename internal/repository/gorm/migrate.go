@@ -31,6 +31,7 @@ func AutoMigrate(db *gorm.DB, debug bool) error {
 		&models.Database{},
 		&models.Infra{},
 		&models.Operation{},
+		&models.ProvisionerFile{},
 		&models.GitActionConfig{},
 		&models.Invite{},
 		&models.AuthCode{},
@@ -91,5 +92,19 @@ func AutoMigrate(db *gorm.DB, debug bool) error {
 		&models.AppEventWebhooks{},
 		&models.ClusterHealthReport{},
 		&models.Referral{},
+		&models.UserTOTP{},
+		&models.MFARecoveryCode{},
+		&models.WebAuthnCredential{},
+		&models.AppDashboard{},
+		&models.AppSLO{},
+		&models.AppSLOStatus{},
+		&models.LogSearch{},
+		&models.AppLogAlertRule{},
+		&models.EnvGroupRotationPolicy{},
+		&models.StatusPage{},
+		&models.StatusPageComponent{},
+		&models.StatusPageIncident{},
+		&models.AppSyntheticCheck{},
+		&models.AppSyntheticCheckResult{},
 	)
 }
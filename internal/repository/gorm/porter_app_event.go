@@ -246,3 +246,24 @@ func (repo *PorterAppEventRepository) ReadDeployEventByAppRevisionID(ctx context
 
 	return appEvent, nil
 }
+
+// ReadLatestEventByType returns the most recent event of the given type for a porter app id and deployment target id
+func (repo *PorterAppEventRepository) ReadLatestEventByType(ctx context.Context, porterAppID uint, deploymentTargetID uuid.UUID, eventType string) (models.PorterAppEvent, error) {
+	appEvent := models.PorterAppEvent{}
+
+	if porterAppID == 0 {
+		return appEvent, errors.New("invalid porter app ID supplied")
+	}
+
+	if deploymentTargetID == uuid.Nil {
+		return appEvent, errors.New("invalid deployment target ID supplied")
+	}
+
+	strAppID := strconv.Itoa(int(porterAppID))
+
+	if err := repo.db.Where("porter_app_id = ? AND deployment_target_id = ? AND type = ?", strAppID, deploymentTargetID, eventType).Order("created_at DESC").First(&appEvent).Error; err != nil {
+		return appEvent, err
+	}
+
+	return appEvent, nil
+}
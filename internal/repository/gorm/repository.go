@@ -65,6 +65,14 @@ type GormRepository struct {
 	appInstance               repository.AppInstanceRepository
 	ipam                      repository.IpamRepository
 	referral                  repository.ReferralRepository
+	mfa                       repository.MFARepository
+	appDashboard              repository.AppDashboardRepository
+	appSLO                    repository.AppSLORepository
+	logSearch                 repository.LogSearchRepository
+	appLogAlertRule           repository.AppLogAlertRuleRepository
+	envGroupRotationPolicy    repository.EnvGroupRotationPolicyRepository
+	statusPage                repository.StatusPageRepository
+	appSyntheticCheck         repository.AppSyntheticCheckRepository
 }
 
 func (t *GormRepository) User() repository.UserRepository {
@@ -311,6 +319,46 @@ func (t *GormRepository) Referral() repository.ReferralRepository {
 	return t.referral
 }
 
+// MFA returns the MFARepository interface implemented by gorm
+func (t *GormRepository) MFA() repository.MFARepository {
+	return t.mfa
+}
+
+// AppDashboard returns the AppDashboardRepository interface implemented by gorm
+func (t *GormRepository) AppDashboard() repository.AppDashboardRepository {
+	return t.appDashboard
+}
+
+// AppSLO returns the AppSLORepository interface implemented by gorm
+func (t *GormRepository) AppSLO() repository.AppSLORepository {
+	return t.appSLO
+}
+
+// LogSearch returns the LogSearchRepository interface implemented by gorm
+func (t *GormRepository) LogSearch() repository.LogSearchRepository {
+	return t.logSearch
+}
+
+// AppLogAlertRule returns the AppLogAlertRuleRepository interface implemented by gorm
+func (t *GormRepository) AppLogAlertRule() repository.AppLogAlertRuleRepository {
+	return t.appLogAlertRule
+}
+
+// EnvGroupRotationPolicy returns the EnvGroupRotationPolicyRepository interface implemented by gorm
+func (t *GormRepository) EnvGroupRotationPolicy() repository.EnvGroupRotationPolicyRepository {
+	return t.envGroupRotationPolicy
+}
+
+// StatusPage returns the StatusPageRepository interface implemented by gorm
+func (t *GormRepository) StatusPage() repository.StatusPageRepository {
+	return t.statusPage
+}
+
+// AppSyntheticCheck returns the AppSyntheticCheckRepository interface implemented by gorm
+func (t *GormRepository) AppSyntheticCheck() repository.AppSyntheticCheckRepository {
+	return t.appSyntheticCheck
+}
+
 // NewRepository returns a Repository which persists users in memory
 // and accepts a parameter that can trigger read/write errors
 func NewRepository(db *gorm.DB, key *[32]byte, storageBackend credentials.CredentialStorage) repository.Repository {
@@ -373,5 +421,13 @@ func NewRepository(db *gorm.DB, key *[32]byte, storageBackend credentials.Creden
 		ipam:                      NewIpamRepository(db),
 		appEventWebhook:           NewAppEventWebhookRepository(db),
 		referral:                  NewReferralRepository(db),
+		mfa:                       NewMFARepository(db, key),
+		appDashboard:              NewAppDashboardRepository(db),
+		appSLO:                    NewAppSLORepository(db),
+		logSearch:                 NewLogSearchRepository(db),
+		appLogAlertRule:           NewAppLogAlertRuleRepository(db),
+		envGroupRotationPolicy:    NewEnvGroupRotationPolicyRepository(db),
+		statusPage:                NewStatusPageRepository(db),
+		appSyntheticCheck:         NewAppSyntheticCheckRepository(db),
 	}
 }
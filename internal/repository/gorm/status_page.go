@@ -0,0 +1,163 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// StatusPageRepository uses gorm.DB for querying the database
+type StatusPageRepository struct {
+	db *gorm.DB
+}
+
+// NewStatusPageRepository returns a StatusPageRepository which uses
+// gorm.DB for querying the database
+func NewStatusPageRepository(db *gorm.DB) repository.StatusPageRepository {
+	return &StatusPageRepository{db}
+}
+
+// CreateStatusPage creates a new status page
+func (repo *StatusPageRepository) CreateStatusPage(page *models.StatusPage) (*models.StatusPage, error) {
+	if err := repo.db.Create(page).Error; err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// ReadStatusPage reads the status page of a project
+func (repo *StatusPageRepository) ReadStatusPage(projectID uint) (*models.StatusPage, error) {
+	page := &models.StatusPage{}
+
+	if err := repo.db.Where("project_id = ?", projectID).First(page).Error; err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// ReadStatusPageBySlug reads a status page by the slug of its public URL
+func (repo *StatusPageRepository) ReadStatusPageBySlug(slug string) (*models.StatusPage, error) {
+	page := &models.StatusPage{}
+
+	if err := repo.db.Where("slug = ?", slug).First(page).Error; err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// ListStatusPages lists the status pages of every project
+func (repo *StatusPageRepository) ListStatusPages() ([]*models.StatusPage, error) {
+	pages := []*models.StatusPage{}
+
+	if err := repo.db.Order("id ASC").Find(&pages).Error; err != nil {
+		return nil, err
+	}
+
+	return pages, nil
+}
+
+// UpdateStatusPage updates a status page
+func (repo *StatusPageRepository) UpdateStatusPage(page *models.StatusPage) (*models.StatusPage, error) {
+	if err := repo.db.Save(page).Error; err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// CreateStatusPageComponent creates a new component of a status page
+func (repo *StatusPageRepository) CreateStatusPageComponent(component *models.StatusPageComponent) (*models.StatusPageComponent, error) {
+	if err := repo.db.Create(component).Error; err != nil {
+		return nil, err
+	}
+
+	return component, nil
+}
+
+// ListStatusPageComponents lists the components of a status page in the order they are shown
+func (repo *StatusPageRepository) ListStatusPageComponents(statusPageID uint) ([]*models.StatusPageComponent, error) {
+	components := []*models.StatusPageComponent{}
+
+	if err := repo.db.Where("status_page_id = ?", statusPageID).Order("position ASC, id ASC").Find(&components).Error; err != nil {
+		return nil, err
+	}
+
+	return components, nil
+}
+
+// UpdateStatusPageComponent updates a component of a status page
+func (repo *StatusPageRepository) UpdateStatusPageComponent(component *models.StatusPageComponent) (*models.StatusPageComponent, error) {
+	if err := repo.db.Save(component).Error; err != nil {
+		return nil, err
+	}
+
+	return component, nil
+}
+
+// DeleteStatusPageComponent deletes a component of a status page
+func (repo *StatusPageRepository) DeleteStatusPageComponent(component *models.StatusPageComponent) error {
+	return repo.db.Delete(component).Error
+}
+
+// CreateStatusPageIncident creates a new incident on a status page
+func (repo *StatusPageRepository) CreateStatusPageIncident(incident *models.StatusPageIncident) (*models.StatusPageIncident, error) {
+	if err := repo.db.Create(incident).Error; err != nil {
+		return nil, err
+	}
+
+	return incident, nil
+}
+
+// ReadStatusPageIncident reads an incident of a status page by its id
+func (repo *StatusPageRepository) ReadStatusPageIncident(statusPageID, incidentID uint) (*models.StatusPageIncident, error) {
+	incident := &models.StatusPageIncident{}
+
+	if err := repo.db.Where("status_page_id = ? AND id = ?", statusPageID, incidentID).First(incident).Error; err != nil {
+		return nil, err
+	}
+
+	return incident, nil
+}
+
+// ReadOpenAutomaticStatusPageIncident reads the unresolved incident which the synthetic HTTP checker opened for a component
+func (repo *StatusPageRepository) ReadOpenAutomaticStatusPageIncident(statusPageID, componentID uint) (*models.StatusPageIncident, error) {
+	incident := &models.StatusPageIncident{}
+
+	if err := repo.db.Where(
+		"status_page_id = ? AND component_id = ? AND automatic = ? AND resolved_at IS NULL",
+		statusPageID, componentID, true,
+	).Order("started_at DESC").First(incident).Error; err != nil {
+		return nil, err
+	}
+
+	return incident, nil
+}
+
+// ListStatusPageIncidentsSince lists the incidents of a status page which were ongoing at any point since a time,
+// ordered from the most recent
+func (repo *StatusPageRepository) ListStatusPageIncidentsSince(statusPageID uint, since time.Time) ([]*models.StatusPageIncident, error) {
+	incidents := []*models.StatusPageIncident{}
+
+	if err := repo.db.Where(
+		"status_page_id = ? AND (resolved_at IS NULL OR resolved_at >= ?)",
+		statusPageID, since,
+	).Order("started_at DESC").Find(&incidents).Error; err != nil {
+		return nil, err
+	}
+
+	return incidents, nil
+}
+
+// UpdateStatusPageIncident updates an incident of a status page
+func (repo *StatusPageRepository) UpdateStatusPageIncident(incident *models.StatusPageIncident) (*models.StatusPageIncident, error) {
+	if err := repo.db.Save(incident).Error; err != nil {
+		return nil, err
+	}
+
+	return incident, nil
+}
@@ -3,6 +3,7 @@ package gorm
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/porter-dev/porter/internal/models"
@@ -33,3 +34,18 @@ func (repo *SystemServiceStatusRepository) ReadSystemServiceStatus(ctx context.C
 	}
 	return status, nil
 }
+
+// ListSystemServiceStatusesSince lists the statuses of the system services of a cluster which were ongoing at any
+// point since a time
+func (repo *SystemServiceStatusRepository) ListSystemServiceStatusesSince(ctx context.Context, projectID, clusterID uint, since time.Time) ([]models.SystemServiceStatus, error) {
+	statuses := []models.SystemServiceStatus{}
+
+	if err := repo.db.WithContext(ctx).Where(
+		"project_id = ? AND cluster_id = ? AND (end_time IS NULL OR end_time >= ?)",
+		projectID, clusterID, since,
+	).Order("start_time ASC").Find(&statuses).Error; err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/porter-dev/porter/internal/models"
 )
 
@@ -17,4 +19,8 @@ type InfraRepository interface {
 	ListOperations(infraID uint) ([]*models.Operation, error)
 	GetLatestOperation(infra *models.Infra) (*models.Operation, error)
 	UpdateOperation(repo *models.Operation) (*models.Operation, error)
+
+	// ApproveOperation starts an operation which is awaiting approval. It returns false if the operation
+	// is no longer awaiting approval, for example because it was approved by a concurrent request.
+	ApproveOperation(operation *models.Operation, approvedAt time.Time) (bool, error)
 }
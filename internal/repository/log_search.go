@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// LogSearchRepository represents the set of queries on the LogSearch model
+type LogSearchRepository interface {
+	CreateLogSearch(search *models.LogSearch) (*models.LogSearch, error)
+	ReadLogSearch(projectID, searchID uint) (*models.LogSearch, error)
+	ReadLogSearchByName(projectID uint, name string) (*models.LogSearch, error)
+	ListLogSearches(projectID uint) ([]*models.LogSearch, error)
+	DeleteLogSearch(search *models.LogSearch) error
+}
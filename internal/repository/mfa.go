@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// MFARepository represents the set of queries on a user's multi-factor authentication settings
+type MFARepository interface {
+	CreateUserTOTP(totp *models.UserTOTP) (*models.UserTOTP, error)
+	ReadUserTOTP(userID uint) (*models.UserTOTP, error)
+	UpdateUserTOTP(totp *models.UserTOTP) (*models.UserTOTP, error)
+	DeleteUserTOTP(totp *models.UserTOTP) error
+
+	ReplaceRecoveryCodes(userID uint, codeHashes []string) error
+	ListRecoveryCodes(userID uint) ([]*models.MFARecoveryCode, error)
+	UseRecoveryCode(userID uint, codeHash string) (bool, error)
+	DeleteRecoveryCodes(userID uint) error
+
+	CreateWebAuthnCredential(cred *models.WebAuthnCredential) (*models.WebAuthnCredential, error)
+	ListWebAuthnCredentials(userID uint) ([]*models.WebAuthnCredential, error)
+	ReadWebAuthnCredential(userID, id uint) (*models.WebAuthnCredential, error)
+	ReadWebAuthnCredentialByCredentialID(userID uint, credentialID string) (*models.WebAuthnCredential, error)
+	UpdateWebAuthnCredential(cred *models.WebAuthnCredential) (*models.WebAuthnCredential, error)
+	DeleteWebAuthnCredential(cred *models.WebAuthnCredential) error
+}
@@ -20,6 +20,8 @@ type PorterAppEventRepository interface {
 	ReadDeployEventByRevision(ctx context.Context, porterAppID uint, revision float64) (models.PorterAppEvent, error)
 	// ReadDeployEventByAppRevisionID returns a deploy event for a given porter app id and app revision ID
 	ReadDeployEventByAppRevisionID(ctx context.Context, porterAppID uint, appRevisionID string) (models.PorterAppEvent, error)
+	// ReadLatestEventByType returns the most recent event of the given type for a porter app id and deployment target id
+	ReadLatestEventByType(ctx context.Context, porterAppID uint, deploymentTargetID uuid.UUID, eventType string) (models.PorterAppEvent, error)
 	ReadNotificationsByAppRevisionID(ctx context.Context, porterAppInstanceID uuid.UUID, appRevisionID string) ([]*models.PorterAppEvent, error)
 	NotificationByID(ctx context.Context, notificationID string) (*models.PorterAppEvent, error)
 }
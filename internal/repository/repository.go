@@ -58,4 +58,12 @@ type Repository interface {
 	Datastore() DatastoreRepository
 	AppInstance() AppInstanceRepository
 	Referral() ReferralRepository
+	MFA() MFARepository
+	AppDashboard() AppDashboardRepository
+	AppSLO() AppSLORepository
+	LogSearch() LogSearchRepository
+	AppLogAlertRule() AppLogAlertRuleRepository
+	EnvGroupRotationPolicy() EnvGroupRotationPolicyRepository
+	StatusPage() StatusPageRepository
+	AppSyntheticCheck() AppSyntheticCheckRepository
 }
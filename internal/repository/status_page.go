@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// StatusPageRepository represents the set of queries on the StatusPage, StatusPageComponent and StatusPageIncident models
+type StatusPageRepository interface {
+	CreateStatusPage(page *models.StatusPage) (*models.StatusPage, error)
+	ReadStatusPage(projectID uint) (*models.StatusPage, error)
+	ReadStatusPageBySlug(slug string) (*models.StatusPage, error)
+	ListStatusPages() ([]*models.StatusPage, error)
+	UpdateStatusPage(page *models.StatusPage) (*models.StatusPage, error)
+
+	CreateStatusPageComponent(component *models.StatusPageComponent) (*models.StatusPageComponent, error)
+	ListStatusPageComponents(statusPageID uint) ([]*models.StatusPageComponent, error)
+	UpdateStatusPageComponent(component *models.StatusPageComponent) (*models.StatusPageComponent, error)
+	DeleteStatusPageComponent(component *models.StatusPageComponent) error
+
+	CreateStatusPageIncident(incident *models.StatusPageIncident) (*models.StatusPageIncident, error)
+	ReadStatusPageIncident(statusPageID, incidentID uint) (*models.StatusPageIncident, error)
+	ReadOpenAutomaticStatusPageIncident(statusPageID, componentID uint) (*models.StatusPageIncident, error)
+	ListStatusPageIncidentsSince(statusPageID uint, since time.Time) ([]*models.StatusPageIncident, error)
+	UpdateStatusPageIncident(incident *models.StatusPageIncident) (*models.StatusPageIncident, error)
+}
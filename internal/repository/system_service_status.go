@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/porter-dev/porter/internal/models"
@@ -10,4 +11,5 @@ import (
 // SystemServiceStatusRepository represents the set of queries on the SystemServiceStatus model
 type SystemServiceStatusRepository interface {
 	ReadSystemServiceStatus(ctx context.Context, id uuid.UUID) (models.SystemServiceStatus, error)
+	ListSystemServiceStatusesSince(ctx context.Context, projectID, clusterID uint, since time.Time) ([]models.SystemServiceStatus, error)
 }
@@ -0,0 +1,46 @@
+package test
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// AppDashboardRepository is a test repository for app dashboards
+type AppDashboardRepository struct{}
+
+// NewAppDashboardRepository returns the test AppDashboardRepository
+func NewAppDashboardRepository() repository.AppDashboardRepository {
+	return &AppDashboardRepository{}
+}
+
+// CreateAppDashboard is a test method
+func (repo *AppDashboardRepository) CreateAppDashboard(dashboard *models.AppDashboard) (*models.AppDashboard, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// ReadAppDashboard is a test method
+func (repo *AppDashboardRepository) ReadAppDashboard(porterAppID, dashboardID uint) (*models.AppDashboard, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ReadAppDashboardByName is a test method
+func (repo *AppDashboardRepository) ReadAppDashboardByName(porterAppID uint, name string) (*models.AppDashboard, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ListAppDashboards is a test method
+func (repo *AppDashboardRepository) ListAppDashboards(porterAppID uint) ([]*models.AppDashboard, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// UpdateAppDashboard is a test method
+func (repo *AppDashboardRepository) UpdateAppDashboard(dashboard *models.AppDashboard) (*models.AppDashboard, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// DeleteAppDashboard is a test method
+func (repo *AppDashboardRepository) DeleteAppDashboard(dashboard *models.AppDashboard) error {
+	return errors.New("cannot write database")
+}
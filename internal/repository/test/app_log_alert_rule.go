@@ -0,0 +1,51 @@
+package test
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// AppLogAlertRuleRepository is a test repository for log alert rules
+type AppLogAlertRuleRepository struct{}
+
+// NewAppLogAlertRuleRepository returns the test AppLogAlertRuleRepository
+func NewAppLogAlertRuleRepository() repository.AppLogAlertRuleRepository {
+	return &AppLogAlertRuleRepository{}
+}
+
+// CreateAppLogAlertRule is a test method
+func (repo *AppLogAlertRuleRepository) CreateAppLogAlertRule(rule *models.AppLogAlertRule) (*models.AppLogAlertRule, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// ReadAppLogAlertRule is a test method
+func (repo *AppLogAlertRuleRepository) ReadAppLogAlertRule(porterAppID, ruleID uint) (*models.AppLogAlertRule, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ReadAppLogAlertRuleByName is a test method
+func (repo *AppLogAlertRuleRepository) ReadAppLogAlertRuleByName(porterAppID uint, deploymentTargetID, name string) (*models.AppLogAlertRule, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ListAppLogAlertRules is a test method
+func (repo *AppLogAlertRuleRepository) ListAppLogAlertRules(porterAppID uint, deploymentTargetID string) ([]*models.AppLogAlertRule, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ListAppLogAlertRulesByClusterID is a test method
+func (repo *AppLogAlertRuleRepository) ListAppLogAlertRulesByClusterID(clusterID uint) ([]*models.AppLogAlertRule, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// UpdateAppLogAlertRule is a test method
+func (repo *AppLogAlertRuleRepository) UpdateAppLogAlertRule(rule *models.AppLogAlertRule) (*models.AppLogAlertRule, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// DeleteAppLogAlertRule is a test method
+func (repo *AppLogAlertRuleRepository) DeleteAppLogAlertRule(rule *models.AppLogAlertRule) error {
+	return errors.New("cannot write database")
+}
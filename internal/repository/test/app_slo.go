@@ -0,0 +1,62 @@
+package test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// AppSLORepository is a test repository for app SLOs
+type AppSLORepository struct{}
+
+// NewAppSLORepository returns the test AppSLORepository
+func NewAppSLORepository() repository.AppSLORepository {
+	return &AppSLORepository{}
+}
+
+// CreateAppSLO is a test method
+func (repo *AppSLORepository) CreateAppSLO(slo *models.AppSLO) (*models.AppSLO, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// ReadAppSLO is a test method
+func (repo *AppSLORepository) ReadAppSLO(porterAppID, sloID uint) (*models.AppSLO, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ListAppSLOs is a test method
+func (repo *AppSLORepository) ListAppSLOs(porterAppID uint, deploymentTargetID string) ([]*models.AppSLO, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ListAppSLOsByClusterID is a test method
+func (repo *AppSLORepository) ListAppSLOsByClusterID(clusterID uint) ([]*models.AppSLO, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// UpdateAppSLO is a test method
+func (repo *AppSLORepository) UpdateAppSLO(slo *models.AppSLO) (*models.AppSLO, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// DeleteAppSLO is a test method
+func (repo *AppSLORepository) DeleteAppSLO(slo *models.AppSLO) error {
+	return errors.New("cannot write database")
+}
+
+// CreateAppSLOStatus is a test method
+func (repo *AppSLORepository) CreateAppSLOStatus(status *models.AppSLOStatus) (*models.AppSLOStatus, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// ListAppSLOStatuses is a test method
+func (repo *AppSLORepository) ListAppSLOStatuses(sloID uint, limit int) ([]*models.AppSLOStatus, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// DeleteAppSLOStatusesBefore is a test method
+func (repo *AppSLORepository) DeleteAppSLOStatusesBefore(sloID uint, before time.Time) error {
+	return errors.New("cannot write database")
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// AppSyntheticCheckRepository is a test repository for synthetic checks
+type AppSyntheticCheckRepository struct{}
+
+// NewAppSyntheticCheckRepository returns the test AppSyntheticCheckRepository
+func NewAppSyntheticCheckRepository() repository.AppSyntheticCheckRepository {
+	return &AppSyntheticCheckRepository{}
+}
+
+// CreateAppSyntheticCheck is a test method
+func (repo *AppSyntheticCheckRepository) CreateAppSyntheticCheck(check *models.AppSyntheticCheck) (*models.AppSyntheticCheck, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// ReadAppSyntheticCheck is a test method
+func (repo *AppSyntheticCheckRepository) ReadAppSyntheticCheck(porterAppID, checkID uint) (*models.AppSyntheticCheck, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ListAppSyntheticChecks is a test method
+func (repo *AppSyntheticCheckRepository) ListAppSyntheticChecks(porterAppID uint, deploymentTargetID string) ([]*models.AppSyntheticCheck, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ListDueAppSyntheticChecks is a test method
+func (repo *AppSyntheticCheckRepository) ListDueAppSyntheticChecks(now time.Time, limit int) ([]*models.AppSyntheticCheck, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// UpdateAppSyntheticCheck is a test method
+func (repo *AppSyntheticCheckRepository) UpdateAppSyntheticCheck(check *models.AppSyntheticCheck) (*models.AppSyntheticCheck, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// DeleteAppSyntheticCheck is a test method
+func (repo *AppSyntheticCheckRepository) DeleteAppSyntheticCheck(check *models.AppSyntheticCheck) error {
+	return errors.New("cannot write database")
+}
+
+// CreateAppSyntheticCheckResult is a test method
+func (repo *AppSyntheticCheckRepository) CreateAppSyntheticCheckResult(result *models.AppSyntheticCheckResult) (*models.AppSyntheticCheckResult, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// ListAppSyntheticCheckResults is a test method
+func (repo *AppSyntheticCheckRepository) ListAppSyntheticCheckResults(checkID uint, limit int) ([]*models.AppSyntheticCheckResult, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// DeleteAppSyntheticCheckResultsBefore is a test method
+func (repo *AppSyntheticCheckRepository) DeleteAppSyntheticCheckResultsBefore(checkID uint, before time.Time) error {
+	return errors.New("cannot write database")
+}
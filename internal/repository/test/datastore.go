@@ -3,42 +3,103 @@ package test
 import (
 	"context"
 	"errors"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
 )
 
 // DatastoreRepository is a test repository that implements repository.DatastoreRepository
 type DatastoreRepository struct {
-	canQuery bool
+	canQuery   bool
+	datastores []*models.Datastore
 }
 
 // NewDatastoreRepository returns the test DatastoreRepository
-func NewDatastoreRepository() repository.DatastoreRepository {
-	return &DatastoreRepository{canQuery: false}
+func NewDatastoreRepository(canQuery bool) repository.DatastoreRepository {
+	return &DatastoreRepository{canQuery: canQuery}
 }
 
-// GetByProjectIDAndName retrieves a datastore by project id and name
+// GetByProjectIDAndName retrieves a datastore by project id and name. As with the gorm repository, an
+// empty datastore is returned if none exists.
 func (repo *DatastoreRepository) GetByProjectIDAndName(ctx context.Context, projectID uint, name string) (*models.Datastore, error) {
-	return nil, errors.New("cannot read database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	for _, datastore := range repo.datastores {
+		if datastore.ProjectID == projectID && datastore.Name == name {
+			return datastore, nil
+		}
+	}
+
+	return &models.Datastore{}, nil
 }
 
 // Insert inserts a datastore into the database
 func (repo *DatastoreRepository) Insert(ctx context.Context, datastore *models.Datastore) (*models.Datastore, error) {
-	return nil, errors.New("cannot write database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	if datastore == nil || datastore.ProjectID == 0 || datastore.Name == "" {
+		return nil, errors.New("datastore must have a project id and name")
+	}
+
+	if datastore.ID == uuid.Nil {
+		datastore.ID = uuid.New()
+	}
+
+	if datastore.CreatedAt.IsZero() {
+		datastore.CreatedAt = time.Now().UTC()
+	}
+
+	repo.datastores = append(repo.datastores, datastore)
+
+	return datastore, nil
 }
 
 // ListByProjectID retrieves a list of datastores by project id
 func (repo *DatastoreRepository) ListByProjectID(ctx context.Context, projectID uint) ([]*models.Datastore, error) {
-	return nil, errors.New("cannot read database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	datastores := []*models.Datastore{}
+
+	for _, datastore := range repo.datastores {
+		if datastore.ProjectID == projectID {
+			datastores = append(datastores, datastore)
+		}
+	}
+
+	return datastores, nil
 }
 
 // Delete deletes a datastore by id
 func (repo *DatastoreRepository) Delete(ctx context.Context, datastore *models.Datastore) (*models.Datastore, error) {
-	return nil, errors.New("cannot write database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	for i, existing := range repo.datastores {
+		if existing.ID == datastore.ID {
+			repo.datastores = append(repo.datastores[:i], repo.datastores[i+1:]...)
+			return datastore, nil
+		}
+	}
+
+	return nil, errors.New("datastore not found")
 }
 
 // UpdateStatus updates the status of a datastore
 func (repo *DatastoreRepository) UpdateStatus(ctx context.Context, datastore *models.Datastore, status models.DatastoreStatus) (*models.Datastore, error) {
-	return nil, errors.New("cannot write database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	datastore.Status = status
+
+	return datastore, nil
 }
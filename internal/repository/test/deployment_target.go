@@ -3,18 +3,20 @@ package test
 import (
 	"errors"
 
+	"github.com/google/uuid"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
 )
 
 // DeploymentTargetRepository is a test repository that implements repository.DeploymentTargetRepository
 type DeploymentTargetRepository struct {
-	canQuery bool
+	canQuery          bool
+	deploymentTargets []*models.DeploymentTarget
 }
 
 // NewDeploymentTargetRepository returns the test DeploymentTargetRepository
-func NewDeploymentTargetRepository() repository.DeploymentTargetRepository {
-	return &DeploymentTargetRepository{canQuery: false}
+func NewDeploymentTargetRepository(canQuery bool) repository.DeploymentTargetRepository {
+	return &DeploymentTargetRepository{canQuery: canQuery}
 }
 
 // DeploymentTargetBySelectorAndSelectorType finds a deployment target for a projectID and clusterID by its selector and selector type
@@ -34,12 +36,37 @@ func (repo *DeploymentTargetRepository) List(projectID uint, preview bool) ([]*m
 
 // CreateDeploymentTarget creates a new deployment target
 func (repo *DeploymentTargetRepository) CreateDeploymentTarget(deploymentTarget *models.DeploymentTarget) (*models.DeploymentTarget, error) {
-	return nil, errors.New("cannot write database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	if deploymentTarget.ID == uuid.Nil {
+		deploymentTarget.ID = uuid.New()
+	}
+
+	repo.deploymentTargets = append(repo.deploymentTargets, deploymentTarget)
+
+	return deploymentTarget, nil
 }
 
 // DeploymentTarget finds a deployment target by its id if a uuid is provided or by name
 func (repo *DeploymentTargetRepository) DeploymentTarget(projectID uint, deploymentTargetIdentifier string) (*models.DeploymentTarget, error) {
-	return nil, errors.New("cannot read database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	for _, deploymentTarget := range repo.deploymentTargets {
+		if deploymentTarget.ProjectID != int(projectID) {
+			continue
+		}
+
+		if deploymentTarget.ID.String() == deploymentTargetIdentifier || deploymentTarget.VanityName == deploymentTargetIdentifier {
+			return deploymentTarget, nil
+		}
+	}
+
+	// as with the gorm repository, an empty deployment target is returned if none exists
+	return &models.DeploymentTarget{}, nil
 }
 
 // DeploymentTargetById finds a deployment target by its uuid
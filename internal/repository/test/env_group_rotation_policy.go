@@ -0,0 +1,52 @@
+package test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// EnvGroupRotationPolicyRepository is a test repository for env group rotation policies
+type EnvGroupRotationPolicyRepository struct{}
+
+// NewEnvGroupRotationPolicyRepository returns the test EnvGroupRotationPolicyRepository
+func NewEnvGroupRotationPolicyRepository() repository.EnvGroupRotationPolicyRepository {
+	return &EnvGroupRotationPolicyRepository{}
+}
+
+// CreateEnvGroupRotationPolicy is a test method
+func (repo *EnvGroupRotationPolicyRepository) CreateEnvGroupRotationPolicy(policy *models.EnvGroupRotationPolicy) (*models.EnvGroupRotationPolicy, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// ReadEnvGroupRotationPolicy is a test method
+func (repo *EnvGroupRotationPolicyRepository) ReadEnvGroupRotationPolicy(clusterID uint, envGroupName string, policyID uint) (*models.EnvGroupRotationPolicy, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ReadEnvGroupRotationPolicyByKey is a test method
+func (repo *EnvGroupRotationPolicyRepository) ReadEnvGroupRotationPolicyByKey(clusterID uint, envGroupName, key string) (*models.EnvGroupRotationPolicy, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ListEnvGroupRotationPolicies is a test method
+func (repo *EnvGroupRotationPolicyRepository) ListEnvGroupRotationPolicies(clusterID uint, envGroupName string) ([]*models.EnvGroupRotationPolicy, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ListDueEnvGroupRotationPoliciesByClusterID is a test method
+func (repo *EnvGroupRotationPolicyRepository) ListDueEnvGroupRotationPoliciesByClusterID(clusterID uint, now time.Time) ([]*models.EnvGroupRotationPolicy, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// UpdateEnvGroupRotationPolicy is a test method
+func (repo *EnvGroupRotationPolicyRepository) UpdateEnvGroupRotationPolicy(policy *models.EnvGroupRotationPolicy) (*models.EnvGroupRotationPolicy, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// DeleteEnvGroupRotationPolicy is a test method
+func (repo *EnvGroupRotationPolicyRepository) DeleteEnvGroupRotationPolicy(policy *models.EnvGroupRotationPolicy) error {
+	return errors.New("cannot write database")
+}
@@ -26,6 +26,10 @@ func (repo *EnvironmentRepository) ReadEnvironmentByID(projectID, clusterID, env
 	panic("unimplemented")
 }
 
+func (repo *EnvironmentRepository) ReadEnvironmentForDeployment(deployment *models.Deployment) (*models.Environment, error) {
+	panic("unimplemented")
+}
+
 func (repo *EnvironmentRepository) ReadEnvironmentByOwnerRepoName(
 	projectID, clusterID uint,
 	gitRepoOwner, gitRepoName string,
@@ -81,6 +85,10 @@ func (repo *EnvironmentRepository) ListDeployments(environmentID uint, states ..
 	panic("unimplemented")
 }
 
+func (repo *EnvironmentRepository) ListDeploymentsBySleepingHost(host string) ([]*models.Deployment, error) {
+	panic("unimplemented")
+}
+
 func (repo *EnvironmentRepository) DeleteDeployment(deployment *models.Deployment) (*models.Deployment, error) {
 	panic("unimplemented")
 }
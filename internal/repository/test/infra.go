@@ -2,7 +2,9 @@ package test
 
 import (
 	"errors"
+	"time"
 
+	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
 	"gorm.io/gorm"
@@ -10,15 +12,17 @@ import (
 
 // InfraRepository implements repository.InfraRepository
 type InfraRepository struct {
-	canQuery bool
-	infras   []*models.Infra
+	canQuery   bool
+	infras     []*models.Infra
+	operations []*models.Operation
 }
 
 // NewInfraRepository will return errors if canQuery is false
 func NewInfraRepository(canQuery bool) repository.InfraRepository {
 	return &InfraRepository{
-		canQuery,
-		[]*models.Infra{},
+		canQuery:   canQuery,
+		infras:     []*models.Infra{},
+		operations: []*models.Operation{},
 	}
 }
 
@@ -92,24 +96,105 @@ func (repo *InfraRepository) UpdateInfra(
 	return ai, nil
 }
 
+// AddOperation adds a new operation to an infra
 func (repo *InfraRepository) AddOperation(infra *models.Infra, operation *models.Operation) (*models.Operation, error) {
-	panic("unimplemented")
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	operation.InfraID = infra.ID
+
+	repo.operations = append(repo.operations, operation)
+	operation.ID = uint(len(repo.operations))
+
+	return operation, nil
 }
 
+// GetLatestOperation returns the most recently added operation of an infra
 func (repo *InfraRepository) GetLatestOperation(infra *models.Infra) (*models.Operation, error) {
-	panic("unimplemented")
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for i := len(repo.operations) - 1; i >= 0; i-- {
+		if repo.operations[i].InfraID == infra.ID {
+			return repo.operations[i], nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
 }
 
+// ListOperations lists the operations of an infra, most recent first
 func (repo *InfraRepository) ListOperations(infraID uint) ([]*models.Operation, error) {
-	panic("unimplemented")
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.Operation, 0)
+
+	for i := len(repo.operations) - 1; i >= 0; i-- {
+		if repo.operations[i].InfraID == infraID {
+			res = append(res, repo.operations[i])
+		}
+	}
+
+	return res, nil
 }
 
+// ReadOperation finds an operation of an infra by its uid
 func (repo *InfraRepository) ReadOperation(infraID uint, operationUID string) (*models.Operation, error) {
-	panic("unimplemented")
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for _, operation := range repo.operations {
+		if operation.InfraID == infraID && operation.UID == operationUID {
+			return operation, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
 }
 
+// UpdateOperation modifies an existing operation in the database
 func (repo *InfraRepository) UpdateOperation(
 	operation *models.Operation,
 ) (*models.Operation, error) {
-	panic("unimplemented")
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	if int(operation.ID-1) >= len(repo.operations) || repo.operations[operation.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.operations[operation.ID-1] = operation
+
+	return operation, nil
+}
+
+// ApproveOperation starts an operation which is awaiting approval
+func (repo *InfraRepository) ApproveOperation(operation *models.Operation, approvedAt time.Time) (bool, error) {
+	if !repo.canQuery {
+		return false, errors.New("Cannot write database")
+	}
+
+	if int(operation.ID-1) >= len(repo.operations) || repo.operations[operation.ID-1] == nil {
+		return false, gorm.ErrRecordNotFound
+	}
+
+	stored := repo.operations[operation.ID-1]
+
+	if stored.Status != types.OperationStatusAwaitingApproval {
+		return false, nil
+	}
+
+	stored.Status = types.OperationStatusStarting
+	stored.ApprovedAt = &approvedAt
+
+	operation.Status = stored.Status
+	operation.ApprovedAt = stored.ApprovedAt
+
+	return true, nil
 }
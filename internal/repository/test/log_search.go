@@ -0,0 +1,41 @@
+package test
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// LogSearchRepository is a test repository for saved log searches
+type LogSearchRepository struct{}
+
+// NewLogSearchRepository returns the test LogSearchRepository
+func NewLogSearchRepository() repository.LogSearchRepository {
+	return &LogSearchRepository{}
+}
+
+// CreateLogSearch is a test method
+func (repo *LogSearchRepository) CreateLogSearch(search *models.LogSearch) (*models.LogSearch, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// ReadLogSearch is a test method
+func (repo *LogSearchRepository) ReadLogSearch(projectID, searchID uint) (*models.LogSearch, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ReadLogSearchByName is a test method
+func (repo *LogSearchRepository) ReadLogSearchByName(projectID uint, name string) (*models.LogSearch, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ListLogSearches is a test method
+func (repo *LogSearchRepository) ListLogSearches(projectID uint) ([]*models.LogSearch, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// DeleteLogSearch is a test method
+func (repo *LogSearchRepository) DeleteLogSearch(search *models.LogSearch) error {
+	return errors.New("cannot write database")
+}
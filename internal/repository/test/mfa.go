@@ -0,0 +1,226 @@
+package test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MFARepository stores multi-factor authentication settings in memory
+type MFARepository struct {
+	canQuery    bool
+	totps       []*models.UserTOTP
+	codes       []*models.MFARecoveryCode
+	credentials []*models.WebAuthnCredential
+}
+
+// NewMFARepository will return errors if canQuery is false
+func NewMFARepository(canQuery bool) repository.MFARepository {
+	return &MFARepository{canQuery: canQuery}
+}
+
+func (repo *MFARepository) CreateUserTOTP(totp *models.UserTOTP) (*models.UserTOTP, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.totps = append(repo.totps, totp)
+	totp.ID = uint(len(repo.totps))
+
+	return totp, nil
+}
+
+func (repo *MFARepository) ReadUserTOTP(userID uint) (*models.UserTOTP, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for _, totp := range repo.totps {
+		if totp != nil && totp.UserID == userID {
+			return totp, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (repo *MFARepository) UpdateUserTOTP(totp *models.UserTOTP) (*models.UserTOTP, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	if int(totp.ID-1) >= len(repo.totps) || repo.totps[totp.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.totps[totp.ID-1] = totp
+
+	return totp, nil
+}
+
+func (repo *MFARepository) DeleteUserTOTP(totp *models.UserTOTP) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	if int(totp.ID-1) >= len(repo.totps) || repo.totps[totp.ID-1] == nil {
+		return gorm.ErrRecordNotFound
+	}
+
+	repo.totps[totp.ID-1] = nil
+
+	return nil
+}
+
+func (repo *MFARepository) ReplaceRecoveryCodes(userID uint, codeHashes []string) error {
+	if err := repo.DeleteRecoveryCodes(userID); err != nil {
+		return err
+	}
+
+	for _, hash := range codeHashes {
+		repo.codes = append(repo.codes, &models.MFARecoveryCode{
+			Model:    gorm.Model{ID: uint(len(repo.codes) + 1)},
+			UserID:   userID,
+			CodeHash: hash,
+		})
+	}
+
+	return nil
+}
+
+func (repo *MFARepository) ListRecoveryCodes(userID uint) ([]*models.MFARecoveryCode, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.MFARecoveryCode, 0)
+
+	for _, code := range repo.codes {
+		if code.UserID == userID {
+			res = append(res, code)
+		}
+	}
+
+	return res, nil
+}
+
+func (repo *MFARepository) UseRecoveryCode(userID uint, codeHash string) (bool, error) {
+	if !repo.canQuery {
+		return false, errors.New("Cannot write database")
+	}
+
+	for _, code := range repo.codes {
+		if code.UserID == userID && code.CodeHash == codeHash && code.UsedAt == nil {
+			now := time.Now()
+			code.UsedAt = &now
+
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (repo *MFARepository) DeleteRecoveryCodes(userID uint) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	codes := make([]*models.MFARecoveryCode, 0)
+
+	for _, code := range repo.codes {
+		if code.UserID != userID {
+			codes = append(codes, code)
+		}
+	}
+
+	repo.codes = codes
+
+	return nil
+}
+
+func (repo *MFARepository) CreateWebAuthnCredential(cred *models.WebAuthnCredential) (*models.WebAuthnCredential, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.credentials = append(repo.credentials, cred)
+	cred.ID = uint(len(repo.credentials))
+
+	return cred, nil
+}
+
+func (repo *MFARepository) ListWebAuthnCredentials(userID uint) ([]*models.WebAuthnCredential, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.WebAuthnCredential, 0)
+
+	for _, cred := range repo.credentials {
+		if cred != nil && cred.UserID == userID {
+			res = append(res, cred)
+		}
+	}
+
+	return res, nil
+}
+
+func (repo *MFARepository) ReadWebAuthnCredential(userID, id uint) (*models.WebAuthnCredential, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for _, cred := range repo.credentials {
+		if cred != nil && cred.UserID == userID && cred.ID == id {
+			return cred, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (repo *MFARepository) ReadWebAuthnCredentialByCredentialID(userID uint, credentialID string) (*models.WebAuthnCredential, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for _, cred := range repo.credentials {
+		if cred != nil && cred.UserID == userID && cred.CredentialID == credentialID {
+			return cred, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (repo *MFARepository) UpdateWebAuthnCredential(cred *models.WebAuthnCredential) (*models.WebAuthnCredential, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	if int(cred.ID-1) >= len(repo.credentials) || repo.credentials[cred.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.credentials[cred.ID-1] = cred
+
+	return cred, nil
+}
+
+func (repo *MFARepository) DeleteWebAuthnCredential(cred *models.WebAuthnCredential) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	if int(cred.ID-1) >= len(repo.credentials) || repo.credentials[cred.ID-1] == nil {
+		return gorm.ErrRecordNotFound
+	}
+
+	repo.credentials[cred.ID-1] = nil
+
+	return nil
+}
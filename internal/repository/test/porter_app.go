@@ -12,14 +12,27 @@ import (
 type PorterAppRepository struct {
 	canQuery       bool
 	failingMethods string
+	porterApps     []*models.PorterApp
 }
 
 func NewPorterAppRepository(canQuery bool, failingMethods ...string) repository.PorterAppRepository {
-	return &PorterAppRepository{canQuery, strings.Join(failingMethods, ",")}
+	return &PorterAppRepository{canQuery: canQuery, failingMethods: strings.Join(failingMethods, ",")}
 }
 
+// ReadPorterAppByName returns the app with the given name in a cluster. As with the gorm repository, an empty
+// app is returned if none exists.
 func (repo *PorterAppRepository) ReadPorterAppByName(clusterID uint, name string) (*models.PorterApp, error) {
-	return nil, errors.New("cannot write database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	for _, app := range repo.porterApps {
+		if app.ClusterID == clusterID && app.Name == name {
+			return app, nil
+		}
+	}
+
+	return &models.PorterApp{}, nil
 }
 
 // ReadPorterAppsByProjectIDAndName is a test method that is not implemented
@@ -27,8 +40,16 @@ func (repo *PorterAppRepository) ReadPorterAppsByProjectIDAndName(projectID uint
 	return nil, errors.New("cannot write database")
 }
 
+// CreatePorterApp stores an app and assigns it an id
 func (repo *PorterAppRepository) CreatePorterApp(app *models.PorterApp) (*models.PorterApp, error) {
-	return nil, errors.New("cannot write database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	repo.porterApps = append(repo.porterApps, app)
+	app.ID = uint(len(repo.porterApps))
+
+	return app, nil
 }
 
 func (repo *PorterAppRepository) UpdatePorterApp(app *models.PorterApp) (*models.PorterApp, error) {
@@ -3,6 +3,7 @@ package test
 import (
 	"context"
 	"errors"
+	"sync"
 
 	"github.com/google/uuid"
 	"github.com/porter-dev/porter/internal/models"
@@ -12,14 +13,34 @@ import (
 
 type PorterAppEventRepository struct {
 	canQuery bool
+
+	// mu guards events, since events are completed by handlers after their response has been sent
+	mu     sync.Mutex
+	events []*models.PorterAppEvent
 }
 
 func NewPorterAppEventRepository(canQuery bool, failingMethods ...string) repository.PorterAppEventRepository {
-	return &PorterAppEventRepository{canQuery: false}
+	return &PorterAppEventRepository{canQuery: canQuery}
 }
 
+// ListEventsByPorterAppID returns every event of an app, in the order they were created. Query options are ignored.
 func (repo *PorterAppEventRepository) ListEventsByPorterAppID(ctx context.Context, porterAppID uint, opts ...helpers.QueryOption) ([]*models.PorterAppEvent, helpers.PaginatedResult, error) {
-	return nil, helpers.PaginatedResult{}, errors.New("cannot write database")
+	if !repo.canQuery {
+		return nil, helpers.PaginatedResult{}, errors.New("cannot read database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	events := []*models.PorterAppEvent{}
+
+	for _, event := range repo.events {
+		if event.PorterAppID == porterAppID {
+			events = append(events, event)
+		}
+	}
+
+	return events, helpers.PaginatedResult{}, nil
 }
 
 // ListEventsByPorterAppIDAndDeploymentTargetID is a test method
@@ -32,16 +53,63 @@ func (repo *PorterAppEventRepository) ListBuildDeployEventsByPorterAppIDAndDeplo
 	return nil, helpers.PaginatedResult{}, errors.New("cannot write database")
 }
 
+// CreateEvent stores a copy of an event
 func (repo *PorterAppEventRepository) CreateEvent(ctx context.Context, appEvent *models.PorterAppEvent) error {
-	return errors.New("cannot write database")
+	if !repo.canQuery {
+		return errors.New("cannot write database")
+	}
+
+	if appEvent.PorterAppID == 0 {
+		return errors.New("invalid porter app id supplied to create event")
+	}
+
+	if appEvent.ID == uuid.Nil {
+		appEvent.ID = uuid.New()
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.events = append(repo.events, copyPorterAppEvent(appEvent))
+
+	return nil
 }
 
+// UpdateEvent replaces the stored copy of an event
 func (repo *PorterAppEventRepository) UpdateEvent(ctx context.Context, appEvent *models.PorterAppEvent) error {
-	return errors.New("cannot update database")
+	if !repo.canQuery {
+		return errors.New("cannot update database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i, event := range repo.events {
+		if event.ID == appEvent.ID {
+			repo.events[i] = copyPorterAppEvent(appEvent)
+			return nil
+		}
+	}
+
+	return errors.New("porter app event not found")
 }
 
+// ReadEvent returns the stored copy of an event
 func (repo *PorterAppEventRepository) ReadEvent(ctx context.Context, id uuid.UUID) (models.PorterAppEvent, error) {
-	return models.PorterAppEvent{}, errors.New("cannot read database")
+	if !repo.canQuery {
+		return models.PorterAppEvent{}, errors.New("cannot read database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, event := range repo.events {
+		if event.ID == id {
+			return *copyPorterAppEvent(event), nil
+		}
+	}
+
+	return models.PorterAppEvent{}, errors.New("porter app event not found")
 }
 
 func (repo *PorterAppEventRepository) ReadDeployEventByRevision(ctx context.Context, porterAppID uint, revision float64) (models.PorterAppEvent, error) {
@@ -53,6 +121,11 @@ func (repo *PorterAppEventRepository) ReadDeployEventByAppRevisionID(ctx context
 	return models.PorterAppEvent{}, errors.New("cannot read database")
 }
 
+// ReadLatestEventByType is a test method
+func (repo *PorterAppEventRepository) ReadLatestEventByType(ctx context.Context, porterAppID uint, deploymentTargetID uuid.UUID, eventType string) (models.PorterAppEvent, error) {
+	return models.PorterAppEvent{}, errors.New("cannot read database")
+}
+
 // ReadNotificationsByAppRevisionID is a test method
 func (repo *PorterAppEventRepository) ReadNotificationsByAppRevisionID(ctx context.Context, porterAppInstanceID uuid.UUID, appRevisionID string) ([]*models.PorterAppEvent, error) {
 	return nil, errors.New("cannot read database")
@@ -62,3 +135,17 @@ func (repo *PorterAppEventRepository) ReadNotificationsByAppRevisionID(ctx conte
 func (repo *PorterAppEventRepository) NotificationByID(ctx context.Context, notificationID string) (*models.PorterAppEvent, error) {
 	return nil, errors.New("cannot read database")
 }
+
+// copyPorterAppEvent copies an event and its metadata, so that stored events are not changed by the caller
+func copyPorterAppEvent(appEvent *models.PorterAppEvent) *models.PorterAppEvent {
+	event := *appEvent
+
+	if appEvent.Metadata != nil {
+		event.Metadata = make(models.JSONB, len(appEvent.Metadata))
+		for k, v := range appEvent.Metadata {
+			event.Metadata[k] = v
+		}
+	}
+
+	return &event
+}
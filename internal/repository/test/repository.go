@@ -62,6 +62,14 @@ type TestRepository struct {
 	datastore                 repository.DatastoreRepository
 	appInstance               repository.AppInstanceRepository
 	referral                  repository.ReferralRepository
+	mfa                       repository.MFARepository
+	appDashboard              repository.AppDashboardRepository
+	appSLO                    repository.AppSLORepository
+	logSearch                 repository.LogSearchRepository
+	appLogAlertRule           repository.AppLogAlertRuleRepository
+	envGroupRotationPolicy    repository.EnvGroupRotationPolicyRepository
+	statusPage                repository.StatusPageRepository
+	appSyntheticCheck         repository.AppSyntheticCheckRepository
 }
 
 func (t *TestRepository) User() repository.UserRepository {
@@ -299,6 +307,46 @@ func (t *TestRepository) Referral() repository.ReferralRepository {
 	return t.referral
 }
 
+// MFA returns a test MFARepository
+func (t *TestRepository) MFA() repository.MFARepository {
+	return t.mfa
+}
+
+// AppDashboard returns a test AppDashboardRepository
+func (t *TestRepository) AppDashboard() repository.AppDashboardRepository {
+	return t.appDashboard
+}
+
+// AppSLO returns a test AppSLORepository
+func (t *TestRepository) AppSLO() repository.AppSLORepository {
+	return t.appSLO
+}
+
+// LogSearch returns a test LogSearchRepository
+func (t *TestRepository) LogSearch() repository.LogSearchRepository {
+	return t.logSearch
+}
+
+// AppLogAlertRule returns a test AppLogAlertRuleRepository
+func (t *TestRepository) AppLogAlertRule() repository.AppLogAlertRuleRepository {
+	return t.appLogAlertRule
+}
+
+// EnvGroupRotationPolicy returns a test EnvGroupRotationPolicyRepository
+func (t *TestRepository) EnvGroupRotationPolicy() repository.EnvGroupRotationPolicyRepository {
+	return t.envGroupRotationPolicy
+}
+
+// StatusPage returns a test StatusPageRepository
+func (t *TestRepository) StatusPage() repository.StatusPageRepository {
+	return t.statusPage
+}
+
+// AppSyntheticCheck returns a test AppSyntheticCheckRepository
+func (t *TestRepository) AppSyntheticCheck() repository.AppSyntheticCheckRepository {
+	return t.appSyntheticCheck
+}
+
 // NewRepository returns a Repository which persists users in memory
 // and accepts a parameter that can trigger read/write errors
 func NewRepository(canQuery bool, failingMethods ...string) repository.Repository {
@@ -353,12 +401,20 @@ func NewRepository(canQuery bool, failingMethods ...string) repository.Repositor
 		porterApp:                 NewPorterAppRepository(canQuery, failingMethods...),
 		porterAppEvent:            NewPorterAppEventRepository(canQuery),
 		systemServiceStatus:       NewSystemServiceStatusRepository(canQuery),
-		deploymentTarget:          NewDeploymentTargetRepository(),
+		deploymentTarget:          NewDeploymentTargetRepository(canQuery),
 		appRevision:               NewAppRevisionRepository(),
 		appTemplate:               NewAppTemplateRepository(),
 		githubWebhook:             NewGithubWebhookRepository(),
-		datastore:                 NewDatastoreRepository(),
+		datastore:                 NewDatastoreRepository(canQuery),
 		appInstance:               NewAppInstanceRepository(),
 		referral:                  NewReferralRepository(),
+		mfa:                       NewMFARepository(canQuery),
+		appDashboard:              NewAppDashboardRepository(),
+		appSLO:                    NewAppSLORepository(),
+		logSearch:                 NewLogSearchRepository(),
+		appLogAlertRule:           NewAppLogAlertRuleRepository(),
+		envGroupRotationPolicy:    NewEnvGroupRotationPolicyRepository(),
+		statusPage:                NewStatusPageRepository(),
+		appSyntheticCheck:         NewAppSyntheticCheckRepository(),
 	}
 }
@@ -0,0 +1,87 @@
+package test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// StatusPageRepository is a test repository for status pages
+type StatusPageRepository struct{}
+
+// NewStatusPageRepository returns the test StatusPageRepository
+func NewStatusPageRepository() repository.StatusPageRepository {
+	return &StatusPageRepository{}
+}
+
+// CreateStatusPage is a test method
+func (repo *StatusPageRepository) CreateStatusPage(page *models.StatusPage) (*models.StatusPage, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// ReadStatusPage is a test method
+func (repo *StatusPageRepository) ReadStatusPage(projectID uint) (*models.StatusPage, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ReadStatusPageBySlug is a test method
+func (repo *StatusPageRepository) ReadStatusPageBySlug(slug string) (*models.StatusPage, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ListStatusPages is a test method
+func (repo *StatusPageRepository) ListStatusPages() ([]*models.StatusPage, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// UpdateStatusPage is a test method
+func (repo *StatusPageRepository) UpdateStatusPage(page *models.StatusPage) (*models.StatusPage, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// CreateStatusPageComponent is a test method
+func (repo *StatusPageRepository) CreateStatusPageComponent(component *models.StatusPageComponent) (*models.StatusPageComponent, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// ListStatusPageComponents is a test method
+func (repo *StatusPageRepository) ListStatusPageComponents(statusPageID uint) ([]*models.StatusPageComponent, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// UpdateStatusPageComponent is a test method
+func (repo *StatusPageRepository) UpdateStatusPageComponent(component *models.StatusPageComponent) (*models.StatusPageComponent, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// DeleteStatusPageComponent is a test method
+func (repo *StatusPageRepository) DeleteStatusPageComponent(component *models.StatusPageComponent) error {
+	return errors.New("cannot write database")
+}
+
+// CreateStatusPageIncident is a test method
+func (repo *StatusPageRepository) CreateStatusPageIncident(incident *models.StatusPageIncident) (*models.StatusPageIncident, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// ReadStatusPageIncident is a test method
+func (repo *StatusPageRepository) ReadStatusPageIncident(statusPageID, incidentID uint) (*models.StatusPageIncident, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ReadOpenAutomaticStatusPageIncident is a test method
+func (repo *StatusPageRepository) ReadOpenAutomaticStatusPageIncident(statusPageID, componentID uint) (*models.StatusPageIncident, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ListStatusPageIncidentsSince is a test method
+func (repo *StatusPageRepository) ListStatusPageIncidentsSince(statusPageID uint, since time.Time) ([]*models.StatusPageIncident, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// UpdateStatusPageIncident is a test method
+func (repo *StatusPageRepository) UpdateStatusPageIncident(incident *models.StatusPageIncident) (*models.StatusPageIncident, error) {
+	return nil, errors.New("cannot write database")
+}
@@ -3,6 +3,7 @@ package test
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/porter-dev/porter/internal/models"
@@ -20,3 +21,7 @@ func NewSystemServiceStatusRepository(canQuery bool, failingMethods ...string) r
 func (repo *SystemServiceStatusRepository) ReadSystemServiceStatus(ctx context.Context, id uuid.UUID) (models.SystemServiceStatus, error) {
 	return models.SystemServiceStatus{}, errors.New("cannot read database")
 }
+
+func (repo *SystemServiceStatusRepository) ListSystemServiceStatusesSince(ctx context.Context, projectID, clusterID uint, since time.Time) ([]models.SystemServiceStatus, error) {
+	return nil, errors.New("cannot read database")
+}
@@ -0,0 +1,87 @@
+package secretref
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/repository"
+	"golang.org/x/oauth2"
+)
+
+// gcpSecretManagerScope is the oauth scope required to access GCP Secret Manager secrets
+const gcpSecretManagerScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// NewClusterResolverInput is the input for NewClusterResolver
+type NewClusterResolverInput struct {
+	// Cluster is the cluster whose environment groups contain the references
+	Cluster *models.Cluster
+	// Repo is used to read the cloud integrations of the cluster
+	Repo repository.Repository
+	// Vault reads vault references. Vault references fail to resolve if it is nil.
+	Vault VaultReader
+	// VaultMount is the mount of the Vault KV v2 engine which holds the secrets of every project
+	VaultMount string
+	// VaultProjectsPath is the path within VaultMount under which the secrets of every project are stored. References of a
+	// project must be under <VaultMount>/data/<VaultProjectsPath>/<project id>/.
+	VaultProjectsPath string
+}
+
+// VaultProjectPathPrefix returns the path under which the vault references of a project must be, where data is the
+// segment through which a KV v2 engine serves the data of secrets
+func VaultProjectPathPrefix(mount string, projectsPath string, projectID uint) string {
+	segments := []string{strings.Trim(mount, "/"), "data"}
+	if projectsPath = strings.Trim(projectsPath, "/"); projectsPath != "" {
+		segments = append(segments, projectsPath)
+	}
+
+	return fmt.Sprintf("%s/%d", strings.Join(segments, "/"), projectID)
+}
+
+// NewClusterResolver creates a resolver for the references in the environment groups of a cluster. AWS and GCP references
+// use the credentials of the cluster's AWS or GCP integration, and vault references are limited to the cluster's project.
+func NewClusterResolver(ctx context.Context, inp NewClusterResolverInput) (*Resolver, error) {
+	if inp.Cluster == nil {
+		return nil, fmt.Errorf("cluster is required")
+	}
+
+	var providers []Provider
+
+	if inp.Vault != nil {
+		providers = append(providers, NewVaultProvider(inp.Vault, VaultProjectPathPrefix(inp.VaultMount, inp.VaultProjectsPath, inp.Cluster.ProjectID)))
+	}
+
+	if inp.Cluster.AWSIntegrationID != 0 {
+		awsInt, err := inp.Repo.AWSIntegration().ReadAWSIntegration(inp.Cluster.ProjectID, inp.Cluster.AWSIntegrationID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read aws integration: %w", err)
+		}
+
+		sess, err := awsInt.GetSession()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get aws session: %w", err)
+		}
+
+		providers = append(providers, NewAWSSecretsManagerProvider(sess))
+	}
+
+	if inp.Cluster.GCPIntegrationID != 0 {
+		gcpInt, err := inp.Repo.GCPIntegration().ReadGCPIntegration(inp.Cluster.ProjectID, inp.Cluster.GCPIntegrationID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read gcp integration: %w", err)
+		}
+
+		// tokens are not cached, since references are only resolved when an environment group is synced or refreshed
+		noCache := func(ctx context.Context) (*integrations.TokenCache, error) { return nil, nil }
+		noCacheSet := func(ctx context.Context, token string, expiry time.Time) error { return nil }
+
+		providers = append(providers, NewGCPSecretManagerProvider(func(ctx context.Context) (*oauth2.Token, error) {
+			return gcpInt.GetBearerToken(ctx, noCache, noCacheSet, gcpSecretManagerScope)
+		}))
+	}
+
+	return NewResolver(providers...), nil
+}
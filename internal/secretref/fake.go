@@ -0,0 +1,44 @@
+package secretref
+
+import (
+	"context"
+	"errors"
+)
+
+// FakeProvider is a provider for tests which resolves references from a map
+type FakeProvider struct {
+	// SchemeName is the scheme handled by the provider
+	SchemeName string
+	// Values are keyed by the path of a reference, followed by #<key> if the reference has a key
+	Values map[string]string
+	// Err is returned for every reference if set
+	Err error
+	// Calls is the number of references resolved
+	Calls int
+}
+
+// Scheme implements Provider
+func (f *FakeProvider) Scheme() string {
+	return f.SchemeName
+}
+
+// Resolve implements Provider
+func (f *FakeProvider) Resolve(ctx context.Context, ref Reference) (string, error) {
+	f.Calls++
+
+	if f.Err != nil {
+		return "", f.Err
+	}
+
+	id := ref.Path
+	if ref.Key != "" {
+		id += "#" + ref.Key
+	}
+
+	value, ok := f.Values[id]
+	if !ok {
+		return "", errors.New("secret not found")
+	}
+
+	return value, nil
+}
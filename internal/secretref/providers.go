@@ -0,0 +1,222 @@
+package secretref
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"golang.org/x/oauth2"
+)
+
+// VaultReader reads the data of a secret in a Vault KV v2 engine
+type VaultReader interface {
+	ReadSecretData(pathSegments []string) (map[string]interface{}, error)
+}
+
+// vaultProvider resolves vault references under a single path prefix, so that a project can only reference its own secrets
+type vaultProvider struct {
+	client         VaultReader
+	prefixSegments []string
+}
+
+// NewVaultProvider creates a provider for vault references. References must be under pathPrefix, such as kv/data/projects/1/.
+func NewVaultProvider(client VaultReader, pathPrefix string) Provider {
+	return &vaultProvider{
+		client:         client,
+		prefixSegments: strings.Split(strings.Trim(pathPrefix, "/"), "/"),
+	}
+}
+
+// Scheme implements Provider
+func (p *vaultProvider) Scheme() string {
+	return Scheme_Vault
+}
+
+// Resolve implements Provider
+func (p *vaultProvider) Resolve(ctx context.Context, ref Reference) (string, error) {
+	// the path is checked again here, since a reference does not have to come from Parse
+	segments, err := pathSegments(ref.Path)
+	if err != nil {
+		return "", err
+	}
+
+	if !p.isUnderPrefix(segments) {
+		return "", fmt.Errorf("vault references must be under %s/", strings.Join(p.prefixSegments, "/"))
+	}
+
+	data, err := p.client.ReadSecretData(segments)
+	if err != nil {
+		return "", err
+	}
+
+	return valueOfKey(data, ref.Key)
+}
+
+// isUnderPrefix returns true if the path segments are strictly below the prefix of the provider
+func (p *vaultProvider) isUnderPrefix(segments []string) bool {
+	if len(segments) <= len(p.prefixSegments) {
+		return false
+	}
+
+	for i, prefixSegment := range p.prefixSegments {
+		if segments[i] != prefixSegment {
+			return false
+		}
+	}
+
+	return true
+}
+
+// awsSecretsManagerProvider resolves awssm references with the credentials of a cluster's AWS integration
+type awsSecretsManagerProvider struct {
+	session *session.Session
+}
+
+// NewAWSSecretsManagerProvider creates a provider for awssm references. The region of a secret defaults to the region of
+// the session, and can be set with the region parameter of a reference.
+func NewAWSSecretsManagerProvider(sess *session.Session) Provider {
+	return &awsSecretsManagerProvider{session: sess}
+}
+
+// Scheme implements Provider
+func (p *awsSecretsManagerProvider) Scheme() string {
+	return Scheme_AWSSecretsManager
+}
+
+// Resolve implements Provider
+func (p *awsSecretsManagerProvider) Resolve(ctx context.Context, ref Reference) (string, error) {
+	conf := aws.NewConfig()
+	if region := ref.Params.Get("region"); region != "" {
+		conf = conf.WithRegion(region)
+	}
+
+	input := &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(ref.Path),
+	}
+	if stage := ref.Params.Get("stage"); stage != "" {
+		input.VersionStage = aws.String(stage)
+	}
+
+	out, err := secretsmanager.New(p.session, conf).GetSecretValueWithContext(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	value := aws.StringValue(out.SecretString)
+	if out.SecretString == nil {
+		value = string(out.SecretBinary)
+	}
+
+	return valueOfJSONKey(value, ref.Key)
+}
+
+// gcpSecretManagerProvider resolves gcpsm references with the credentials of a cluster's GCP integration
+type gcpSecretManagerProvider struct {
+	httpClient *http.Client
+	token      func(ctx context.Context) (*oauth2.Token, error)
+}
+
+// NewGCPSecretManagerProvider creates a provider for gcpsm references, which calls the Secret Manager API with tokens
+// from the given function. References without a version resolve the latest version.
+func NewGCPSecretManagerProvider(token func(ctx context.Context) (*oauth2.Token, error)) Provider {
+	return &gcpSecretManagerProvider{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		token:      token,
+	}
+}
+
+// Scheme implements Provider
+func (p *gcpSecretManagerProvider) Scheme() string {
+	return Scheme_GCPSecretManager
+}
+
+// Resolve implements Provider
+func (p *gcpSecretManagerProvider) Resolve(ctx context.Context, ref Reference) (string, error) {
+	path := ref.Path
+	if !strings.Contains(path, "/versions/") {
+		path += "/versions/latest"
+	}
+
+	tok, err := p.token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to get gcp token: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://secretmanager.googleapis.com/v1/%s:access", path), nil)
+	if err != nil {
+		return "", err
+	}
+	tok.SetAuthHeader(req)
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close() // nolint:errcheck
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("secret manager returned status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var accessResp struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &accessResp); err != nil {
+		return "", fmt.Errorf("unable to parse secret manager response: %w", err)
+	}
+
+	value, err := base64.StdEncoding.DecodeString(accessResp.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("unable to decode secret payload: %w", err)
+	}
+
+	return valueOfJSONKey(string(value), ref.Key)
+}
+
+// valueOfJSONKey returns a secret value, or the value of a key of the JSON object stored in a secret if key is set
+func valueOfJSONKey(value string, key string) (string, error) {
+	if key == "" {
+		return value, nil
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &data); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object, so key %s cannot be selected", key)
+	}
+
+	return valueOfKey(data, key)
+}
+
+// valueOfKey returns the value of a key of a secret. Values which are not strings are returned as JSON.
+func valueOfKey(data map[string]interface{}, key string) (string, error) {
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("secret has no key %s", key)
+	}
+
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("unable to encode value of key %s: %w", key, err)
+	}
+
+	return string(encoded), nil
+}
@@ -0,0 +1,158 @@
+package secretref
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// ReferencePrefix marks a secret variable as a reference, so that a literal value which happens to look like a URL of a
+	// secret manager is never resolved
+	ReferencePrefix = "ref+"
+
+	// Scheme_Vault references a key of a secret in a Vault KV v2 engine, e.g. ref+vault://kv/data/projects/1/payments#DB_PASSWORD
+	Scheme_Vault = "vault"
+	// Scheme_AWSSecretsManager references an AWS Secrets Manager secret, or a key of a JSON secret, e.g. ref+awssm://payments#DB_PASSWORD
+	Scheme_AWSSecretsManager = "awssm"
+	// Scheme_GCPSecretManager references a GCP Secret Manager secret, or a key of a JSON secret, e.g. ref+gcpsm://projects/my-project/secrets/payments
+	Scheme_GCPSecretManager = "gcpsm"
+
+	// DefaultRefreshInterval is how often a reference is resolved again when it does not set a refresh interval
+	DefaultRefreshInterval = time.Hour
+	// MinRefreshInterval is the shortest refresh interval a reference can set
+	MinRefreshInterval = time.Minute
+)
+
+// ErrInvalidReference is returned when a value is marked as a reference but is not a valid reference
+var ErrInvalidReference = errors.New("invalid secret reference")
+
+// Reference is a pointer to a value stored in an external secret manager, which is stored in place of the value
+// in the secret variables of an environment group
+type Reference struct {
+	// Raw is the reference as it was written
+	Raw string
+	// Scheme is the secret manager holding the value
+	Scheme string
+	// Path identifies the secret within the secret manager
+	Path string
+	// Key is the key of the value within the secret. It is required for vault references, and optional for other schemes,
+	// where it selects a key of a secret whose value is a JSON object.
+	Key string
+	// Params are scheme specific parameters, such as the region of an AWS secret or the version of a GCP secret
+	Params url.Values
+	// RefreshInterval is how often the reference is resolved again
+	RefreshInterval time.Duration
+}
+
+// IsReference returns true if a value is marked as a reference by ReferencePrefix. Such values are always treated as
+// references, so a marked value which cannot be parsed is an error rather than a literal value. Values without the prefix,
+// such as vault://kv/data/payments, are literal values.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, ReferencePrefix)
+}
+
+// Parse parses a reference of the form ref+<scheme>://<path>[?<params>][#<key>]. The refresh parameter sets how often the
+// reference is resolved again, as a duration such as 15m.
+func Parse(raw string) (Reference, error) {
+	ref := Reference{
+		Raw:             raw,
+		RefreshInterval: DefaultRefreshInterval,
+	}
+
+	scheme, rest, ok := strings.Cut(strings.TrimPrefix(raw, ReferencePrefix), "://")
+	switch {
+	case !ok || !IsReference(raw):
+		return ref, fmt.Errorf("%w: must start with ref+vault://, ref+awssm:// or ref+gcpsm://", ErrInvalidReference)
+	case scheme != Scheme_Vault && scheme != Scheme_AWSSecretsManager && scheme != Scheme_GCPSecretManager:
+		return ref, fmt.Errorf("%w: unsupported scheme %s, must be vault, awssm or gcpsm", ErrInvalidReference, scheme)
+	}
+	ref.Scheme = scheme
+
+	rest, ref.Key, _ = strings.Cut(rest, "#")
+
+	rest, rawQuery, _ := strings.Cut(rest, "?")
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return ref, fmt.Errorf("%w: %s", ErrInvalidReference, err.Error())
+	}
+
+	if refresh := params.Get("refresh"); refresh != "" {
+		interval, err := time.ParseDuration(refresh)
+		if err != nil {
+			return ref, fmt.Errorf("%w: refresh must be a duration such as 15m", ErrInvalidReference)
+		}
+		if interval < MinRefreshInterval {
+			return ref, fmt.Errorf("%w: refresh must be at least %s", ErrInvalidReference, MinRefreshInterval)
+		}
+		ref.RefreshInterval = interval
+		params.Del("refresh")
+	}
+	ref.Params = params
+
+	ref.Path = strings.Trim(rest, "/")
+	if ref.Path == "" {
+		return ref, fmt.Errorf("%w: %s reference must include a path", ErrInvalidReference, scheme)
+	}
+	if _, err := pathSegments(ref.Path); err != nil {
+		return ref, err
+	}
+
+	switch scheme {
+	case Scheme_Vault:
+		if ref.Key == "" {
+			return ref, fmt.Errorf("%w: vault reference must include a key after '#'", ErrInvalidReference)
+		}
+	case Scheme_GCPSecretManager:
+		parts := strings.Split(ref.Path, "/")
+		validSecret := len(parts) == 4 && parts[0] == "projects" && parts[2] == "secrets"
+		validVersion := len(parts) == 6 && parts[0] == "projects" && parts[2] == "secrets" && parts[4] == "versions"
+		if !validSecret && !validVersion {
+			return ref, fmt.Errorf("%w: gcpsm reference path must be projects/<project>/secrets/<secret>[/versions/<version>]", ErrInvalidReference)
+		}
+	}
+
+	return ref, nil
+}
+
+// pathSegments splits the path of a reference into its segments. Empty, '.' and '..' segments are rejected, as are
+// segments containing '%', so that a path cannot leave the prefix it appears to be under once a secret manager
+// decodes or cleans it.
+func pathSegments(path string) ([]string, error) {
+	segments := strings.Split(path, "/")
+
+	for _, segment := range segments {
+		switch {
+		case segment == "":
+			return nil, fmt.Errorf("%w: path cannot contain empty segments", ErrInvalidReference)
+		case segment == "." || segment == "..":
+			return nil, fmt.Errorf("%w: path cannot contain '.' or '..' segments", ErrInvalidReference)
+		case strings.Contains(segment, "%"):
+			return nil, fmt.Errorf("%w: path cannot contain '%%'", ErrInvalidReference)
+		}
+	}
+
+	return segments, nil
+}
+
+// ParseReferences returns the references among a set of secret variables, keyed by variable name. Values which are not
+// references are ignored.
+func ParseReferences(secretVariables map[string]string) (map[string]Reference, error) {
+	refs := make(map[string]Reference)
+
+	for key, value := range secretVariables {
+		if !IsReference(value) {
+			continue
+		}
+
+		ref, err := Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		refs[key] = ref
+	}
+
+	return refs, nil
+}
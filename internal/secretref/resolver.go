@@ -0,0 +1,179 @@
+package secretref
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Provider resolves references to a single secret manager
+type Provider interface {
+	// Scheme is the reference scheme handled by the provider
+	Scheme() string
+	// Resolve returns the value a reference points to
+	Resolve(ctx context.Context, ref Reference) (string, error)
+}
+
+// KeyState is the outcome of resolving the reference of a single key
+type KeyState string
+
+const (
+	// KeyState_Resolved means the reference was resolved on the last attempt
+	KeyState_Resolved KeyState = "resolved"
+	// KeyState_Stale means the last attempt failed, and the value from an earlier attempt is still in use
+	KeyState_Stale KeyState = "stale"
+	// KeyState_Failed means the reference has never been resolved
+	KeyState_Failed KeyState = "failed"
+)
+
+// KeyStatus is the resolution status of the reference of a single key
+type KeyStatus struct {
+	// Key is the name of the secret variable
+	Key string `json:"key"`
+	// Reference is the reference stored in the environment group
+	Reference string `json:"reference"`
+	// State is the outcome of the last attempt
+	State KeyState `json:"state"`
+	// Error is the error of the last attempt, if it failed
+	Error string `json:"error,omitempty"`
+	// CheckedAt is the time of the last attempt
+	CheckedAt time.Time `json:"checked_at"`
+	// ResolvedAt is the time of the last successful attempt
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// Resolver resolves references using the provider registered for their scheme
+type Resolver struct {
+	providers map[string]Provider
+	now       func() time.Time
+}
+
+// NewResolver creates a resolver for the given providers. References to a scheme without a provider fail to resolve.
+func NewResolver(providers ...Provider) *Resolver {
+	r := &Resolver{
+		providers: make(map[string]Provider),
+		now:       func() time.Time { return time.Now().UTC() },
+	}
+
+	for _, p := range providers {
+		r.providers[p.Scheme()] = p
+	}
+
+	return r
+}
+
+// ResolveInput is the input for Resolve
+type ResolveInput struct {
+	// References are the references to resolve, keyed by variable name
+	References map[string]Reference
+	// PreviousValues are the values resolved on an earlier attempt, which are kept for references which fail to resolve
+	PreviousValues map[string]string
+	// PreviousStatuses are the statuses of an earlier attempt, used to keep the time each key was last resolved
+	PreviousStatuses []KeyStatus
+}
+
+// ResolveOutput is the output of Resolve
+type ResolveOutput struct {
+	// Values are the resolved values, keyed by variable name. Keys which failed to resolve and have no previous value are omitted.
+	Values map[string]string
+	// Statuses are the status of each reference, ordered by key
+	Statuses []KeyStatus
+	// NextRefresh is when the references should be resolved again, which is the earliest refresh interval of any reference
+	NextRefresh time.Time
+}
+
+// Failed returns the statuses of the keys which have no value
+func (o ResolveOutput) Failed() []KeyStatus {
+	var failed []KeyStatus
+	for _, status := range o.Statuses {
+		if status.State == KeyState_Failed {
+			failed = append(failed, status)
+		}
+	}
+	return failed
+}
+
+// Resolve resolves each reference. A reference which fails keeps its previous value if it has one, so that a secret
+// manager outage does not remove values which apps depend on. Identical references are only resolved once.
+func (r *Resolver) Resolve(ctx context.Context, inp ResolveInput) ResolveOutput {
+	now := r.now()
+
+	out := ResolveOutput{
+		Values: make(map[string]string),
+	}
+
+	previousStatuses := make(map[string]KeyStatus)
+	for _, status := range inp.PreviousStatuses {
+		previousStatuses[status.Key] = status
+	}
+
+	type result struct {
+		value string
+		err   error
+	}
+	results := make(map[string]result)
+
+	var nextRefresh time.Duration
+
+	for key, ref := range inp.References {
+		if nextRefresh == 0 || ref.RefreshInterval < nextRefresh {
+			nextRefresh = ref.RefreshInterval
+		}
+
+		res, ok := results[ref.Raw]
+		if !ok {
+			res.value, res.err = r.resolve(ctx, ref)
+			results[ref.Raw] = res
+		}
+
+		status := KeyStatus{
+			Key:       key,
+			Reference: ref.Raw,
+			CheckedAt: now,
+		}
+
+		switch previousValue, hasPrevious := inp.PreviousValues[key]; {
+		case res.err == nil:
+			status.State = KeyState_Resolved
+			status.ResolvedAt = &now
+			out.Values[key] = res.value
+		case hasPrevious:
+			status.State = KeyState_Stale
+			status.Error = res.err.Error()
+			if previous, ok := previousStatuses[key]; ok && previous.Reference == ref.Raw {
+				status.ResolvedAt = previous.ResolvedAt
+			}
+			out.Values[key] = previousValue
+		default:
+			status.State = KeyState_Failed
+			status.Error = res.err.Error()
+		}
+
+		out.Statuses = append(out.Statuses, status)
+	}
+
+	sort.Slice(out.Statuses, func(i, j int) bool {
+		return out.Statuses[i].Key < out.Statuses[j].Key
+	})
+
+	if nextRefresh != 0 {
+		out.NextRefresh = now.Add(nextRefresh)
+	}
+
+	return out
+}
+
+func (r *Resolver) resolve(ctx context.Context, ref Reference) (string, error) {
+	provider, ok := r.providers[ref.Scheme]
+	if !ok {
+		return "", fmt.Errorf("%s references are not configured for this cluster", ref.Scheme)
+	}
+
+	value, err := provider.Resolve(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve %s: %w", ref.Raw, err)
+	}
+
+	return value, nil
+}
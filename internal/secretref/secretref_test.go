@@ -0,0 +1,246 @@
+package secretref
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	ref, err := Parse("ref+vault://kv/data/projects/1/payments#DB_PASSWORD")
+	assert.NoError(t, err)
+	assert.Equal(t, Scheme_Vault, ref.Scheme)
+	assert.Equal(t, "kv/data/projects/1/payments", ref.Path)
+	assert.Equal(t, "DB_PASSWORD", ref.Key)
+	assert.Equal(t, DefaultRefreshInterval, ref.RefreshInterval)
+
+	ref, err = Parse("ref+awssm://prod/payments?region=us-west-2&refresh=15m#password")
+	assert.NoError(t, err)
+	assert.Equal(t, "prod/payments", ref.Path)
+	assert.Equal(t, "password", ref.Key)
+	assert.Equal(t, "us-west-2", ref.Params.Get("region"))
+	assert.Equal(t, 15*time.Minute, ref.RefreshInterval)
+
+	ref, err = Parse("ref+gcpsm://projects/my-project/secrets/payments/versions/3")
+	assert.NoError(t, err)
+	assert.Equal(t, "projects/my-project/secrets/payments/versions/3", ref.Path)
+	assert.Empty(t, ref.Key)
+
+	invalid := []string{
+		"ref+vault://kv/data/projects/1/payments",       // vault references need a key
+		"ref+vault://#KEY",                              // no path
+		"ref+vault://kv/data/projects/1/../2/x#KEY",     // path traversal
+		"ref+vault://kv/data/projects/1/%2e%2e/2/x#KEY", // percent-encoded path traversal
+		"ref+vault://kv/data/projects/1/%2F2/x#KEY",     // percent-encoded slash
+		"ref+vault://kv/data/projects/1//x#KEY",         // empty segment
+		"ref+vault://kv/data/projects/1/./x#KEY",        // current directory segment
+		"ref+awssm://payments?refresh=10s",              // refresh below the minimum
+		"ref+awssm://payments?refresh=often",            // refresh is not a duration
+		"ref+gcpsm://payments",                          // not a secret resource name
+		"ref+gcpsm://projects/p/secrets/s/versions",     // incomplete version
+		"ref+https://example.com/payments#KEY",          // unknown scheme
+		"vault://kv/data/projects/1/payments#KEY",       // not marked as a reference
+	}
+	for _, raw := range invalid {
+		_, err := Parse(raw)
+		assert.True(t, errors.Is(err, ErrInvalidReference), raw)
+	}
+}
+
+func TestParseReferences(t *testing.T) {
+	refs, err := ParseReferences(map[string]string{
+		"DB_PASSWORD": "ref+vault://kv/data/projects/1/payments#DB_PASSWORD",
+		"API_KEY":     "literal-value",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, refs, 1)
+	assert.Equal(t, "DB_PASSWORD", refs["DB_PASSWORD"].Key)
+
+	_, err = ParseReferences(map[string]string{"BROKEN": "ref+vault://kv/data/projects/1/payments"})
+	assert.ErrorContains(t, err, "BROKEN")
+
+	// values which only look like references are literal values
+	refs, err = ParseReferences(map[string]string{
+		"VAULT_ADDR":   "vault://kv/data/projects/1/payments#DB_PASSWORD",
+		"SECRETS_URL":  "awssm://payments",
+		"RELEASE_NAME": "gcpsm://not-a-resource",
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, refs)
+}
+
+func TestResolve(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	vault := &FakeProvider{
+		SchemeName: Scheme_Vault,
+		Values: map[string]string{
+			"kv/data/projects/1/payments#DB_PASSWORD": "hunter2",
+		},
+	}
+	resolver := NewResolver(vault)
+	resolver.now = func() time.Time { return now }
+
+	refs, err := ParseReferences(map[string]string{
+		"DB_PASSWORD":      "ref+vault://kv/data/projects/1/payments#DB_PASSWORD",
+		"DB_PASSWORD_COPY": "ref+vault://kv/data/projects/1/payments#DB_PASSWORD",
+		"MISSING":          "ref+vault://kv/data/projects/1/payments?refresh=5m#MISSING",
+		"UNCONFIGURED":     "ref+awssm://payments",
+	})
+	assert.NoError(t, err)
+
+	out := resolver.Resolve(context.Background(), ResolveInput{
+		References:     refs,
+		PreviousValues: map[string]string{"MISSING": "previous"},
+	})
+
+	assert.Equal(t, map[string]string{
+		"DB_PASSWORD":      "hunter2",
+		"DB_PASSWORD_COPY": "hunter2",
+		"MISSING":          "previous",
+	}, out.Values)
+	assert.Equal(t, 2, vault.Calls) // identical references are only resolved once
+
+	states := make(map[string]KeyState)
+	for _, status := range out.Statuses {
+		states[status.Key] = status.State
+	}
+	assert.Equal(t, map[string]KeyState{
+		"DB_PASSWORD":      KeyState_Resolved,
+		"DB_PASSWORD_COPY": KeyState_Resolved,
+		"MISSING":          KeyState_Stale,
+		"UNCONFIGURED":     KeyState_Failed,
+	}, states)
+
+	failed := out.Failed()
+	assert.Len(t, failed, 1)
+	assert.Equal(t, "UNCONFIGURED", failed[0].Key)
+	assert.Contains(t, failed[0].Error, "not configured")
+
+	// the earliest refresh interval of any reference decides when all of them are resolved again
+	assert.Equal(t, now.Add(5*time.Minute), out.NextRefresh)
+}
+
+func TestResolve_KeepsResolvedAtOfStaleKeys(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	earlier := now.Add(-time.Hour)
+
+	resolver := NewResolver(&FakeProvider{SchemeName: Scheme_Vault, Err: errors.New("vault is sealed")})
+	resolver.now = func() time.Time { return now }
+
+	refs, err := ParseReferences(map[string]string{"DB_PASSWORD": "ref+vault://kv/data/projects/1/payments#DB_PASSWORD"})
+	assert.NoError(t, err)
+
+	out := resolver.Resolve(context.Background(), ResolveInput{
+		References:     refs,
+		PreviousValues: map[string]string{"DB_PASSWORD": "hunter2"},
+		PreviousStatuses: []KeyStatus{{
+			Key:        "DB_PASSWORD",
+			Reference:  "ref+vault://kv/data/projects/1/payments#DB_PASSWORD",
+			State:      KeyState_Resolved,
+			ResolvedAt: &earlier,
+		}},
+	})
+
+	assert.Equal(t, "hunter2", out.Values["DB_PASSWORD"])
+	assert.Equal(t, KeyState_Stale, out.Statuses[0].State)
+	assert.Contains(t, out.Statuses[0].Error, "vault is sealed")
+	assert.Equal(t, &earlier, out.Statuses[0].ResolvedAt)
+	assert.Equal(t, now, out.Statuses[0].CheckedAt)
+}
+
+func TestVaultProvider_PathPrefix(t *testing.T) {
+	provider := NewVaultProvider(fakeVaultReader{
+		"kv/data/projects/1/payments": {"DB_PASSWORD": "hunter2", "PORT": float64(5432)},
+	}, "kv/data/projects/1")
+
+	ref, err := Parse("ref+vault://kv/data/projects/1/payments#DB_PASSWORD")
+	assert.NoError(t, err)
+	value, err := provider.Resolve(context.Background(), ref)
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", value)
+
+	ref, err = Parse("ref+vault://kv/data/projects/1/payments#PORT")
+	assert.NoError(t, err)
+	value, err = provider.Resolve(context.Background(), ref)
+	assert.NoError(t, err)
+	assert.Equal(t, "5432", value)
+
+	// references to other projects are rejected, including prefixes of another project id
+	for _, raw := range []string{"ref+vault://kv/data/projects/2/payments#DB_PASSWORD", "ref+vault://kv/data/projects/12/payments#DB_PASSWORD"} {
+		ref, err = Parse(raw)
+		assert.NoError(t, err)
+		_, err = provider.Resolve(context.Background(), ref)
+		assert.ErrorContains(t, err, "must be under", raw)
+	}
+}
+
+func TestVaultProvider_PathBypass(t *testing.T) {
+	provider := NewVaultProvider(fakeVaultReader{
+		"kv/data/projects/1/payments": {"DB_PASSWORD": "hunter2"},
+		"kv/data/projects/2/payments": {"DB_PASSWORD": "other-project"},
+	}, "kv/data/projects/1/")
+
+	// references which do not come from Parse must be rejected by the provider as well
+	for _, path := range []string{
+		"kv/data/projects/1/../2/payments",
+		"kv/data/projects/1/%2e%2e/2/payments",
+		"kv/data/projects/1/%2E%2E%2F2/payments",
+		"kv/data/projects/1/..%2F2/payments",
+		"kv/data/projects/1//payments",
+		"kv/data/projects/1/./payments",
+		"kv/data/projects/1",
+		"kv/data/projects/1/",
+	} {
+		_, err := provider.Resolve(context.Background(), Reference{Scheme: Scheme_Vault, Path: path, Key: "DB_PASSWORD"})
+		assert.Error(t, err, path)
+	}
+}
+
+func TestNewClusterResolver_VaultMount(t *testing.T) {
+	resolver, err := NewClusterResolver(context.Background(), NewClusterResolverInput{
+		Cluster: &models.Cluster{ProjectID: 1},
+		Vault: fakeVaultReader{
+			"secret/data/teams/1/payments": {"DB_PASSWORD": "hunter2"},
+			"kv/data/projects/1/payments":  {"DB_PASSWORD": "default-mount"},
+		},
+		VaultMount:        "secret",
+		VaultProjectsPath: "/teams/",
+	})
+	assert.NoError(t, err)
+
+	refs, err := ParseReferences(map[string]string{
+		"DB_PASSWORD":   "ref+vault://secret/data/teams/1/payments#DB_PASSWORD",
+		"DEFAULT_MOUNT": "ref+vault://kv/data/projects/1/payments#DB_PASSWORD",
+	})
+	assert.NoError(t, err)
+
+	out := resolver.Resolve(context.Background(), ResolveInput{References: refs})
+	assert.Equal(t, map[string]string{"DB_PASSWORD": "hunter2"}, out.Values)
+
+	failed := out.Failed()
+	assert.Len(t, failed, 1)
+	assert.Equal(t, "DEFAULT_MOUNT", failed[0].Key)
+	assert.Contains(t, failed[0].Error, "must be under secret/data/teams/1/")
+}
+
+func TestVaultProjectPathPrefix(t *testing.T) {
+	assert.Equal(t, "kv/data/projects/1", VaultProjectPathPrefix("kv", "projects", 1))
+	assert.Equal(t, "secret/data/porter/projects/12", VaultProjectPathPrefix("/secret/", "porter/projects/", 12))
+	assert.Equal(t, "secret/data/3", VaultProjectPathPrefix("secret", "", 3))
+}
+
+type fakeVaultReader map[string]map[string]interface{}
+
+func (f fakeVaultReader) ReadSecretData(pathSegments []string) (map[string]interface{}, error) {
+	data, ok := f[strings.Join(pathSegments, "/")]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return data, nil
+}
@@ -0,0 +1,28 @@
+package secretrotation
+
+import (
+	"github.com/porter-dev/porter/internal/random"
+)
+
+const (
+	// DefaultGeneratorLength is the number of characters of generated values when a policy does not set one
+	DefaultGeneratorLength = 32
+
+	// generatorCharset matches the charset of the random_string preview driver
+	generatorCharset      = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	generatorLowerCharset = "abcdefghijklmnopqrstuvwxyz"
+)
+
+// GenerateValue returns a random string of the given length, made of letters and digits, or only lowercase letters if lower is set
+func GenerateValue(length uint, lower bool) (string, error) {
+	if length == 0 {
+		length = DefaultGeneratorLength
+	}
+
+	charset := generatorCharset
+	if lower {
+		charset = generatorLowerCharset
+	}
+
+	return random.StringWithCharset(int(length), charset)
+}
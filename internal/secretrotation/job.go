@@ -0,0 +1,218 @@
+package secretrotation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	environmentgroups "github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/porter-dev/porter/internal/random"
+	"github.com/porter-dev/porter/internal/telemetry"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultJobTimeout is how long a rotation job may run when a policy does not set a timeout
+	DefaultJobTimeout = 5 * time.Minute
+
+	// LabelKey_RotationJob is the label on rotation jobs, holding the name of the environment group being rotated
+	LabelKey_RotationJob = "porter.run/env-group-rotation"
+
+	// HookOutputPath is the file a rotation job writes the new value to. It is the termination message file of the job's container,
+	// so the value is read from the status of its pod rather than from its logs, which are collected by the cluster's log pipeline.
+	HookOutputPath = "/dev/termination-log"
+
+	jobPollInterval = 5 * time.Second
+	// hookContainerName is the name of the container of a rotation job
+	hookContainerName = "rotate"
+	// jobTTLAfterFinished is how long a finished rotation job is kept if it could not be deleted
+	jobTTLAfterFinished = int32(600)
+)
+
+// RunHookJobInput is the input to RunHookJob
+type RunHookJobInput struct {
+	EnvGroupName string
+	Key          string
+	Image        string
+	Command      []string
+	// Timeout is how long the job may run. Defaults to DefaultJobTimeout
+	Timeout time.Duration
+}
+
+// RunHookJob runs a rotation job in the environment groups namespace of a cluster and returns the new value, which the job writes to
+// HookOutputPath. The value is never read from the job's logs. The job is deleted once it finishes, which removes its pod and the
+// value held in the pod's status.
+func RunHookJob(ctx context.Context, a *kubernetes.Agent, inp RunHookJobInput) (string, error) {
+	ctx, span := telemetry.NewSpan(ctx, "run-rotation-hook-job")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "env-group-name", Value: inp.EnvGroupName},
+		telemetry.AttributeKV{Key: "key", Value: inp.Key},
+		telemetry.AttributeKV{Key: "image", Value: inp.Image},
+	)
+
+	if inp.Image == "" {
+		return "", telemetry.Error(ctx, span, nil, "rotation job image cannot be empty")
+	}
+
+	timeout := inp.Timeout
+	if timeout == 0 {
+		timeout = DefaultJobTimeout
+	}
+
+	suffix, err := random.StringWithCharset(6, "")
+	if err != nil {
+		return "", telemetry.Error(ctx, span, err, "unable to generate job name")
+	}
+
+	// env group names are at most 63 characters, so they are shortened to leave room for the prefix and suffix
+	name := inp.EnvGroupName
+	if len(name) > 40 {
+		name = strings.TrimSuffix(name[:40], "-")
+	}
+	name = fmt.Sprintf("rotate-%s-%s", name, suffix)
+
+	deadline := int64(timeout.Seconds())
+	backoffLimit := int32(0)
+	ttl := jobTTLAfterFinished
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: environmentgroups.Namespace_EnvironmentGroups,
+			Labels: map[string]string{
+				LabelKey_RotationJob: inp.EnvGroupName,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			ActiveDeadlineSeconds:   &deadline,
+			TTLSecondsAfterFinished: &ttl,
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						LabelKey_RotationJob: inp.EnvGroupName,
+					},
+				},
+				Spec: v1.PodSpec{
+					RestartPolicy:                v1.RestartPolicyNever,
+					AutomountServiceAccountToken: boolPtr(false),
+					Containers: []v1.Container{
+						{
+							Name:    hookContainerName,
+							Image:   inp.Image,
+							Command: inp.Command,
+							Env: []v1.EnvVar{
+								{Name: "PORTER_ENV_GROUP_NAME", Value: inp.EnvGroupName},
+								{Name: "PORTER_ENV_GROUP_KEY", Value: inp.Key},
+								{Name: "PORTER_ROTATION_OUTPUT_FILE", Value: HookOutputPath},
+							},
+							TerminationMessagePath: HookOutputPath,
+							// the message must only ever come from the file, since falling back to logs would read stdout
+							TerminationMessagePolicy: v1.TerminationMessageReadFile,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	_, err = a.Clientset.BatchV1().Jobs(environmentgroups.Namespace_EnvironmentGroups).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return "", telemetry.Error(ctx, span, err, "unable to create rotation job")
+	}
+
+	defer func() {
+		propagation := metav1.DeletePropagationBackground
+		// the job is deleted even if the rotation was cancelled
+		err := a.Clientset.BatchV1().Jobs(environmentgroups.Namespace_EnvironmentGroups).Delete(context.Background(), name, metav1.DeleteOptions{
+			PropagationPolicy: &propagation,
+		})
+		if err != nil {
+			_ = telemetry.Error(ctx, span, err, "unable to delete rotation job")
+		}
+	}()
+
+	// the deadline is enforced by the job, and the extra minute allows for its pod to be scheduled and its status to be updated
+	waitCtx, cancel := context.WithTimeout(ctx, timeout+time.Minute)
+	defer cancel()
+
+	if err := waitForJob(waitCtx, a, name); err != nil {
+		return "", telemetry.Error(ctx, span, err, "rotation job did not succeed")
+	}
+
+	pods, err := a.Clientset.CoreV1().Pods(environmentgroups.Namespace_EnvironmentGroups).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("job-name=%s", name),
+	})
+	if err != nil {
+		return "", telemetry.Error(ctx, span, err, "unable to list rotation job pods")
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != v1.PodSucceeded {
+			continue
+		}
+
+		value := hookOutputValue(pod)
+		if value == "" {
+			return "", telemetry.Error(ctx, span, nil, fmt.Sprintf("rotation job did not write a new value to %s", HookOutputPath))
+		}
+
+		return value, nil
+	}
+
+	return "", telemetry.Error(ctx, span, nil, "no succeeded rotation job pod found")
+}
+
+// waitForJob polls a job until it succeeds, fails or the context is done
+func waitForJob(ctx context.Context, a *kubernetes.Agent, name string) error {
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		job, err := a.Clientset.BatchV1().Jobs(environmentgroups.Namespace_EnvironmentGroups).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("unable to get rotation job: %w", err)
+		}
+
+		if job.Status.Succeeded > 0 {
+			return nil
+		}
+		if job.Status.Failed > 0 {
+			for _, condition := range job.Status.Conditions {
+				if condition.Type == batchv1.JobFailed && condition.Message != "" {
+					return fmt.Errorf("rotation job failed: %s", condition.Message)
+				}
+			}
+			return errors.New("rotation job failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for rotation job: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// hookOutputValue returns the value a rotation job wrote to HookOutputPath, without surrounding whitespace, from the
+// termination message of its container
+func hookOutputValue(pod v1.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == hookContainerName && status.State.Terminated != nil {
+			return strings.TrimSpace(status.State.Terminated.Message)
+		}
+	}
+
+	return ""
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,248 @@
+package secretrotation
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"connectrpc.com/connect"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/api-contracts/generated/go/porter/v1/porterv1connect"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	environmentgroups "github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/secretref"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// RotationAuthor is recorded as the author of environment group versions created by a rotation
+const RotationAuthor = "porter-secret-rotation"
+
+// ErrNotRotatable is returned when the keys of an environment group cannot be rotated, such as when it is synced from an external provider
+var ErrNotRotatable = errors.New("environment group cannot be rotated")
+
+// RotateEnvironmentGroupInput is the input to RotateEnvironmentGroup
+type RotateEnvironmentGroupInput struct {
+	Agent        *kubernetes.Agent
+	CCPClient    porterv1connect.ClusterControlPlaneServiceClient
+	ProjectID    uint
+	ClusterID    uint
+	EnvGroupName string
+	// Policies are the policies of the keys to rotate, which must all belong to the environment group
+	Policies []*models.EnvGroupRotationPolicy
+}
+
+// KeyResult is the outcome of the rotation of one key
+type KeyResult struct {
+	Policy *models.EnvGroupRotationPolicy
+	// Rotated is true if the new value was written to a new version of the environment group
+	Rotated bool
+	// Err is the reason the key could not be rotated, or the linked apps could not be rolled once it was
+	Err error
+}
+
+// RotateEnvironmentGroupOutput is the output of RotateEnvironmentGroup
+type RotateEnvironmentGroupOutput struct {
+	// Version is the environment group version created with the rotated values, or 0 if none was created
+	Version int
+	Results []KeyResult
+}
+
+// RotateEnvironmentGroup produces a new value for the key of each policy, writes the values which were produced to a single new version of
+// the environment group, and then rolls the apps linked to it. Linked apps are only rolled once the new version exists, so that they never
+// start with a mix of old and new values. A key whose hook fails does not stop the other keys from being rotated.
+func RotateEnvironmentGroup(ctx context.Context, inp RotateEnvironmentGroupInput) (RotateEnvironmentGroupOutput, error) {
+	ctx, span := telemetry.NewSpan(ctx, "rotate-environment-group")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "env-group-name", Value: inp.EnvGroupName},
+		telemetry.AttributeKV{Key: "policy-count", Value: len(inp.Policies)},
+	)
+
+	var out RotateEnvironmentGroupOutput
+
+	policies := make([]*models.EnvGroupRotationPolicy, len(inp.Policies))
+	copy(policies, inp.Policies)
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Key < policies[j].Key
+	})
+
+	failAll := func(err error) (RotateEnvironmentGroupOutput, error) {
+		out.Results = nil
+		for _, policy := range policies {
+			out.Results = append(out.Results, KeyResult{Policy: policy, Err: err})
+		}
+		return out, err
+	}
+
+	latest, err := environmentgroups.LatestBaseEnvironmentGroup(ctx, inp.Agent, inp.EnvGroupName)
+	if err != nil {
+		return failAll(telemetry.Error(ctx, span, err, "unable to get latest env group version"))
+	}
+	if latest.Version == 0 {
+		return failAll(telemetry.Error(ctx, span, ErrNotRotatable, "env group not found"))
+	}
+	if latest.Type != "" && latest.Type != "porter" {
+		return failAll(telemetry.Error(ctx, span, ErrNotRotatable, "only porter env groups can be rotated"))
+	}
+
+	current, err := environmentgroups.BaseEnvironmentGroupVersionWithSecrets(ctx, inp.Agent, inp.EnvGroupName, latest.Version)
+	if err != nil {
+		return failAll(telemetry.Error(ctx, span, err, "unable to get latest env group values"))
+	}
+
+	values := make(map[string]string)
+	for _, policy := range policies {
+		result := KeyResult{Policy: policy}
+
+		value, err := rotatedValue(ctx, inp.Agent, inp.EnvGroupName, current, policy)
+		if err != nil {
+			result.Err = err
+		} else {
+			values[policy.Key] = value
+		}
+
+		out.Results = append(out.Results, result)
+	}
+
+	if len(values) == 0 {
+		return out, telemetry.Error(ctx, span, nil, "no keys could be rotated")
+	}
+
+	proposed := applyRotatedValues(current, values)
+
+	var files []*porterv1.EnvGroupFile
+	for _, file := range proposed.Files {
+		files = append(files, &porterv1.EnvGroupFile{
+			Name:        file.Name,
+			B64Contents: base64.StdEncoding.EncodeToString([]byte(file.Contents)),
+		})
+	}
+
+	_, err = inp.CCPClient.CreateOrUpdateEnvGroup(ctx, connect.NewRequest(&porterv1.CreateOrUpdateEnvGroupRequest{
+		ProjectId:            int64(inp.ProjectID),
+		ClusterId:            int64(inp.ClusterID),
+		EnvGroupProviderType: porterv1.EnumEnvGroupProviderType_ENUM_ENV_GROUP_PROVIDER_TYPE_PORTER,
+		EnvGroupName:         inp.EnvGroupName,
+		EnvVars: &porterv1.EnvGroupVariables{
+			Normal: proposed.Variables,
+			Secret: proposed.SecretVariables,
+			Files:  files,
+		},
+		IsEnvOverride: true,
+		// linked apps are rolled below, once the new version exists
+		SkipAppAutoDeploy: true,
+	}))
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to create env group version")
+		for i := range out.Results {
+			if out.Results[i].Err == nil {
+				out.Results[i].Err = err
+			}
+		}
+		return out, err
+	}
+
+	for i := range out.Results {
+		if out.Results[i].Err == nil {
+			out.Results[i].Rotated = true
+		}
+	}
+
+	err = environmentgroups.SetBaseEnvironmentGroupAuthor(ctx, inp.Agent, environmentgroups.SetBaseEnvironmentGroupAuthorInput{
+		Name:            inp.EnvGroupName,
+		PreviousVersion: latest.Version,
+		Author:          RotationAuthor,
+	})
+	if err != nil {
+		_ = telemetry.Error(ctx, span, err, "unable to record env group author")
+	}
+
+	latest, err = environmentgroups.LatestBaseEnvironmentGroup(ctx, inp.Agent, inp.EnvGroupName)
+	if err != nil {
+		_ = telemetry.Error(ctx, span, err, "unable to get rotated env group version")
+	} else {
+		out.Version = latest.Version
+	}
+
+	_, err = inp.CCPClient.UpdateAppsLinkedToEnvGroup(ctx, connect.NewRequest(&porterv1.UpdateAppsLinkedToEnvGroupRequest{
+		ProjectId:    int64(inp.ProjectID),
+		ClusterId:    int64(inp.ClusterID),
+		EnvGroupName: inp.EnvGroupName,
+	}))
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to roll apps linked to env group")
+		for i := range out.Results {
+			if out.Results[i].Rotated {
+				out.Results[i].Err = fmt.Errorf("value was rotated but linked apps could not be rolled: %w", err)
+			}
+		}
+		return out, err
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "rotated-count", Value: len(values)},
+		telemetry.AttributeKV{Key: "version", Value: out.Version},
+	)
+
+	return out, nil
+}
+
+// rotatedValue runs the hook of a policy to produce the new value of its key
+func rotatedValue(ctx context.Context, a *kubernetes.Agent, envGroupName string, current environmentgroups.EnvironmentGroup, policy *models.EnvGroupRotationPolicy) (string, error) {
+	// a key whose value is read from an external secret manager is rotated there
+	if secretref.IsReference(current.SecretVariables[policy.Key]) {
+		return "", fmt.Errorf("%w: key %s references an external secret manager", ErrNotRotatable, policy.Key)
+	}
+
+	switch policy.HookType {
+	case types.EnvGroupRotationHookType_Generator:
+		return GenerateValue(policy.GeneratorLength, policy.GeneratorLower)
+	case types.EnvGroupRotationHookType_Job:
+		var command []string
+		if len(policy.JobCommand) > 0 {
+			if err := json.Unmarshal(policy.JobCommand, &command); err != nil {
+				return "", fmt.Errorf("invalid rotation job command: %w", err)
+			}
+		}
+
+		return RunHookJob(ctx, a, RunHookJobInput{
+			EnvGroupName: envGroupName,
+			Key:          policy.Key,
+			Image:        policy.JobImage,
+			Command:      command,
+			Timeout:      time.Duration(policy.JobTimeoutSeconds) * time.Second,
+		})
+	default:
+		return "", fmt.Errorf("unknown rotation hook type %q", policy.HookType)
+	}
+}
+
+// applyRotatedValues returns a copy of an environment group with the rotated values set as secrets. A rotated key which was a
+// variable is moved to the secrets, since rotated values are credentials.
+func applyRotatedValues(current environmentgroups.EnvironmentGroup, values map[string]string) environmentgroups.EnvironmentGroup {
+	proposed := current
+	proposed.Variables = make(map[string]string)
+	proposed.SecretVariables = make(map[string]string)
+
+	for k, v := range current.Variables {
+		if _, ok := values[k]; ok {
+			continue
+		}
+		proposed.Variables[k] = v
+	}
+	for k, v := range current.SecretVariables {
+		proposed.SecretVariables[k] = v
+	}
+	for k, v := range values {
+		proposed.SecretVariables[k] = v
+	}
+
+	return proposed
+}
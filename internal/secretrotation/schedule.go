@@ -0,0 +1,66 @@
+package secretrotation
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// RetryInterval is how long after a failed rotation it is attempted again, unless the policy's interval is shorter
+const RetryInterval = time.Hour
+
+// NextRotationAt returns when the key of a policy should next be rotated. Keys are rotated an interval after they were last rotated,
+// or after the policy was created if they never were. A rotation which failed before the value was changed is retried sooner.
+func NextRotationAt(policy *models.EnvGroupRotationPolicy, now time.Time) time.Time {
+	interval := time.Duration(policy.IntervalHours) * time.Hour
+
+	// a rotation which changed the value but could not roll linked apps is not retried, since that would change the value again.
+	// The apps pick up the value when they are next deployed.
+	if policy.LastStatus == types.EnvGroupRotationStatus_Failed && policy.LastAttemptedAt != nil &&
+		(policy.LastRotatedAt == nil || policy.LastRotatedAt.Before(*policy.LastAttemptedAt)) {
+		retry := RetryInterval
+		if interval < retry {
+			retry = interval
+		}
+
+		return policy.LastAttemptedAt.Add(retry)
+	}
+
+	from := policy.CreatedAt
+	if policy.LastRotatedAt != nil {
+		from = *policy.LastRotatedAt
+	}
+	if from.IsZero() {
+		from = now
+	}
+
+	return from.Add(interval)
+}
+
+// RecordResult records the outcome of a rotation on its policy and schedules its next rotation. It returns true if the failure of the
+// rotation should be notified, which is when the previous rotation of the key did not also fail, so that a failing hook is notified once.
+func RecordResult(result KeyResult, version int, now time.Time) bool {
+	policy := result.Policy
+	previous := policy.LastStatus
+
+	policy.LastAttemptedAt = &now
+
+	if result.Rotated {
+		policy.LastRotatedAt = &now
+		if version > 0 {
+			policy.LastVersion = uint(version)
+		}
+	}
+
+	policy.LastStatus = types.EnvGroupRotationStatus_Succeeded
+	policy.LastError = ""
+	if result.Err != nil {
+		policy.LastStatus = types.EnvGroupRotationStatus_Failed
+		policy.LastError = result.Err.Error()
+	}
+
+	policy.NextRotationAt = NextRotationAt(policy, now)
+
+	return result.Err != nil && previous != types.EnvGroupRotationStatus_Failed
+}
@@ -0,0 +1,131 @@
+package secretrotation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	environmentgroups "github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestNextRotationAt(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	created := now.Add(-48 * time.Hour)
+	rotated := now.Add(-2 * time.Hour)
+	attempted := now.Add(-10 * time.Minute)
+
+	policy := &models.EnvGroupRotationPolicy{IntervalHours: 24}
+	policy.CreatedAt = created
+
+	// a key which was never rotated is rotated an interval after the policy was created
+	assert.Equal(t, created.Add(24*time.Hour), NextRotationAt(policy, now))
+
+	policy.LastRotatedAt = &rotated
+	policy.LastStatus = types.EnvGroupRotationStatus_Succeeded
+	assert.Equal(t, rotated.Add(24*time.Hour), NextRotationAt(policy, now))
+
+	// a rotation which failed before the value changed is retried sooner
+	policy.LastStatus = types.EnvGroupRotationStatus_Failed
+	policy.LastAttemptedAt = &attempted
+	assert.Equal(t, attempted.Add(RetryInterval), NextRotationAt(policy, now))
+
+	// but never later than the interval
+	policy.IntervalHours = 1
+	policy.LastAttemptedAt = &attempted
+	assert.Equal(t, attempted.Add(time.Hour), NextRotationAt(policy, now))
+
+	// a rotation which changed the value but could not roll linked apps is not retried
+	policy.IntervalHours = 24
+	policy.LastRotatedAt = &attempted
+	assert.Equal(t, attempted.Add(24*time.Hour), NextRotationAt(policy, now))
+
+	// a policy which has not been saved yet is scheduled from now
+	assert.Equal(t, now.Add(time.Hour), NextRotationAt(&models.EnvGroupRotationPolicy{IntervalHours: 1}, now))
+}
+
+func TestRecordResult(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	policy := &models.EnvGroupRotationPolicy{IntervalHours: 24, LastStatus: types.EnvGroupRotationStatus_Succeeded}
+
+	notify := RecordResult(KeyResult{Policy: policy, Err: errors.New("job failed")}, 0, now)
+	assert.True(t, notify)
+	assert.Equal(t, types.EnvGroupRotationStatus_Failed, policy.LastStatus)
+	assert.Equal(t, "job failed", policy.LastError)
+	assert.Nil(t, policy.LastRotatedAt)
+	assert.Equal(t, now.Add(RetryInterval), policy.NextRotationAt)
+
+	// a retry which fails again is not notified again
+	later := now.Add(RetryInterval)
+	notify = RecordResult(KeyResult{Policy: policy, Err: errors.New("job failed")}, 0, later)
+	assert.False(t, notify)
+
+	evenLater := later.Add(RetryInterval)
+	notify = RecordResult(KeyResult{Policy: policy, Rotated: true}, 7, evenLater)
+	assert.False(t, notify)
+	assert.Equal(t, types.EnvGroupRotationStatus_Succeeded, policy.LastStatus)
+	assert.Empty(t, policy.LastError)
+	assert.Equal(t, uint(7), policy.LastVersion)
+	assert.Equal(t, &evenLater, policy.LastRotatedAt)
+	assert.Equal(t, evenLater.Add(24*time.Hour), policy.NextRotationAt)
+}
+
+func TestGenerateValue(t *testing.T) {
+	value, err := GenerateValue(0, false)
+	assert.NoError(t, err)
+	assert.Len(t, value, DefaultGeneratorLength)
+	assert.Empty(t, strings.Trim(value, generatorCharset))
+
+	value, err = GenerateValue(64, true)
+	assert.NoError(t, err)
+	assert.Len(t, value, 64)
+	assert.Empty(t, strings.Trim(value, generatorLowerCharset))
+
+	other, err := GenerateValue(64, true)
+	assert.NoError(t, err)
+	assert.NotEqual(t, value, other)
+}
+
+func TestApplyRotatedValues(t *testing.T) {
+	current := environmentgroups.EnvironmentGroup{
+		Name:            "payments",
+		Version:         3,
+		Variables:       map[string]string{"DB_HOST": "db.internal", "DB_USER": "payments"},
+		SecretVariables: map[string]string{"DB_PASSWORD": "old", "API_KEY": "unchanged"},
+		Files:           []environmentgroups.EnvGroupFile{{Name: "ca.pem", Contents: "cert"}},
+	}
+
+	proposed := applyRotatedValues(current, map[string]string{"DB_PASSWORD": "new", "DB_USER": "payments-2"})
+
+	assert.Equal(t, map[string]string{"DB_HOST": "db.internal"}, proposed.Variables)
+	assert.Equal(t, map[string]string{"DB_PASSWORD": "new", "API_KEY": "unchanged", "DB_USER": "payments-2"}, proposed.SecretVariables)
+	assert.Equal(t, current.Files, proposed.Files)
+
+	// the current version is not modified
+	assert.Equal(t, "old", current.SecretVariables["DB_PASSWORD"])
+	assert.Equal(t, "payments", current.Variables["DB_USER"])
+}
+
+func TestHookOutputValue(t *testing.T) {
+	pod := v1.Pod{
+		Status: v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "istio-proxy", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Message: "proxy exited"}}},
+				{Name: hookContainerName, State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Message: "  s3cr3t\n"}}},
+			},
+		},
+	}
+	assert.Equal(t, "s3cr3t", hookOutputValue(pod))
+
+	// a job which wrote nothing has no value, even if it printed to its logs
+	pod.Status.ContainerStatuses[1].State.Terminated.Message = ""
+	assert.Equal(t, "", hookOutputValue(pod))
+
+	pod.Status.ContainerStatuses[1].State = v1.ContainerState{Running: &v1.ContainerStateRunning{}}
+	assert.Equal(t, "", hookOutputValue(pod))
+}
@@ -0,0 +1,79 @@
+package statuspage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/synthetics"
+)
+
+// FailureThreshold is the number of checks of a component which must fail in a row before an incident is opened
+const FailureThreshold = 2
+
+// CheckAction is what should be done with the automatic incident of a component after it is checked
+type CheckAction int
+
+const (
+	// CheckAction_None leaves the automatic incident of the component as it is
+	CheckAction_None CheckAction = iota
+	// CheckAction_OpenIncident opens an automatic incident for the component
+	CheckAction_OpenIncident
+	// CheckAction_ResolveIncident resolves the open automatic incident of the component
+	CheckAction_ResolveIncident
+)
+
+// ApplyCheckResult records the result of a check on a component, and returns what should be done with its automatic
+// incident given whether it has one open
+func ApplyCheckResult(component *models.StatusPageComponent, result synthetics.Result, hasOpenIncident bool) CheckAction {
+	checkedAt := result.CheckedAt
+	component.LastCheckedAt = &checkedAt
+	component.LastLatencyMs = uint(result.Latency.Milliseconds())
+	component.LastCheckError = result.Error
+
+	if result.Healthy {
+		component.ConsecutiveFailures = 0
+
+		if hasOpenIncident {
+			return CheckAction_ResolveIncident
+		}
+
+		return CheckAction_None
+	}
+
+	component.ConsecutiveFailures++
+
+	if component.ConsecutiveFailures >= FailureThreshold && !hasOpenIncident {
+		return CheckAction_OpenIncident
+	}
+
+	return CheckAction_None
+}
+
+// NewAutomaticIncident returns the incident opened by the checker when a component fails. The incident starts at the
+// first of the failed checks.
+func NewAutomaticIncident(component *models.StatusPageComponent, result synthetics.Result, interval time.Duration) *models.StatusPageIncident {
+	startedAt := result.CheckedAt.Add(-time.Duration(component.ConsecutiveFailures-1) * interval)
+
+	return &models.StatusPageIncident{
+		StatusPageID: component.StatusPageID,
+		ComponentID:  component.ID,
+		Title:        fmt.Sprintf("%s is unreachable", component.Name),
+		Message:      fmt.Sprintf("%d checks in a row failed: %s", component.ConsecutiveFailures, result.Error),
+		Status:       types.StatusPageIncidentStatus_Investigating,
+		Severity:     types.ServiceStatus_Failure,
+		Automatic:    true,
+		StartedAt:    startedAt,
+	}
+}
+
+// ResolveIncident marks an incident as resolved
+func ResolveIncident(incident *models.StatusPageIncident, message string, now time.Time) {
+	incident.Status = types.StatusPageIncidentStatus_Resolved
+	incident.ResolvedAt = &now
+
+	if message != "" {
+		incident.Message = message
+	}
+}
@@ -0,0 +1,148 @@
+package statuspage
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// jsonFeedVersion is the version of the JSON Feed spec that feeds are generated with
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link,omitempty"`
+	Description string  `xml:"description"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url,omitempty"`
+	FeedURL     string         `json:"feed_url,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string    `json:"id"`
+	URL           string    `json:"url,omitempty"`
+	Title         string    `json:"title"`
+	ContentText   string    `json:"content_text"`
+	DatePublished time.Time `json:"date_published"`
+	DateModified  time.Time `json:"date_modified"`
+}
+
+// RSS generates an RSS 2.0 feed of the incidents of a status page. The link is the URL of the page itself.
+func RSS(summary types.StatusPageSummary, link string) ([]byte, error) {
+	channel := rssChannel{
+		Title:       summary.Title,
+		Link:        link,
+		Description: fmt.Sprintf("Incident history of %s", summary.Title),
+		Items:       make([]rssItem, 0, len(summary.Incidents)),
+	}
+
+	for _, incident := range summary.Incidents {
+		if channel.LastBuildDate == "" {
+			channel.LastBuildDate = incident.UpdatedAt.UTC().Format(time.RFC1123Z)
+		}
+
+		channel.Items = append(channel.Items, rssItem{
+			Title:       incidentTitle(incident),
+			Link:        link,
+			Description: incidentText(incident, summary.Components),
+			GUID:        rssGUID{Value: incidentGUID(summary.Slug, incident)},
+			PubDate:     incident.UpdatedAt.UTC().Format(time.RFC1123Z),
+		})
+	}
+
+	out, err := xml.MarshalIndent(rssFeed{Version: "2.0", Channel: channel}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling rss feed: %w", err)
+	}
+
+	return append([]byte(xml.Header), out...), nil
+}
+
+// JSONFeed generates a JSON Feed of the incidents of a status page. The link is the URL of the page itself.
+func JSONFeed(summary types.StatusPageSummary, link, feedURL string) ([]byte, error) {
+	feed := jsonFeed{
+		Version:     jsonFeedVersion,
+		Title:       summary.Title,
+		HomePageURL: link,
+		FeedURL:     feedURL,
+		Items:       make([]jsonFeedItem, 0, len(summary.Incidents)),
+	}
+
+	for _, incident := range summary.Incidents {
+		feed.Items = append(feed.Items, jsonFeedItem{
+			ID:            incidentGUID(summary.Slug, incident),
+			URL:           link,
+			Title:         incidentTitle(incident),
+			ContentText:   incidentText(incident, summary.Components),
+			DatePublished: incident.StartedAt.UTC(),
+			DateModified:  incident.UpdatedAt.UTC(),
+		})
+	}
+
+	out, err := json.Marshal(feed)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling json feed: %w", err)
+	}
+
+	return out, nil
+}
+
+// incidentGUID identifies an incident across updates, so that feed readers show its updates as one item
+func incidentGUID(slug string, incident types.StatusPageIncident) string {
+	return fmt.Sprintf("%s-incident-%d", slug, incident.ID)
+}
+
+func incidentTitle(incident types.StatusPageIncident) string {
+	status := string(incident.Status)
+	if status == "" {
+		status = string(types.StatusPageIncidentStatus_Investigating)
+	}
+
+	return fmt.Sprintf("[%s%s] %s", strings.ToUpper(status[:1]), status[1:], incident.Title)
+}
+
+func incidentText(incident types.StatusPageIncident, components []types.StatusPageComponentSummary) string {
+	affected := "All components"
+	for _, component := range components {
+		if component.ID == incident.ComponentID {
+			affected = component.Name
+		}
+	}
+
+	text := fmt.Sprintf("Affected: %s", affected)
+	if incident.Message != "" {
+		text = fmt.Sprintf("%s\n\n%s", incident.Message, text)
+	}
+
+	return text
+}
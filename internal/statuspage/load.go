@@ -0,0 +1,47 @@
+package statuspage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// Load reads the components, incidents and system service statuses of a status page, and summarizes them
+func Load(ctx context.Context, repo repository.Repository, page *models.StatusPage, now time.Time) ([]*models.StatusPageComponent, types.StatusPageSummary, error) {
+	components, err := repo.StatusPage().ListStatusPageComponents(page.ID)
+	if err != nil {
+		return nil, types.StatusPageSummary{}, fmt.Errorf("error listing status page components: %w", err)
+	}
+
+	historyStart := now.AddDate(0, 0, -HistoryDays)
+
+	incidents, err := repo.StatusPage().ListStatusPageIncidentsSince(page.ID, historyStart)
+	if err != nil {
+		return nil, types.StatusPageSummary{}, fmt.Errorf("error listing status page incidents: %w", err)
+	}
+
+	systemStatuses := make(map[uint][]models.SystemServiceStatus)
+
+	for _, component := range components {
+		if component.Type != types.StatusPageComponentType_Cluster {
+			continue
+		}
+
+		if _, ok := systemStatuses[component.ClusterID]; ok {
+			continue
+		}
+
+		statuses, err := repo.SystemServiceStatus().ListSystemServiceStatusesSince(ctx, page.ProjectID, component.ClusterID, historyStart)
+		if err != nil {
+			return nil, types.StatusPageSummary{}, fmt.Errorf("error listing system service statuses of cluster %d: %w", component.ClusterID, err)
+		}
+
+		systemStatuses[component.ClusterID] = statuses
+	}
+
+	return components, Summarize(page, components, incidents, systemStatuses, now), nil
+}
@@ -0,0 +1,223 @@
+package statuspage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/synthetics"
+	"github.com/stretchr/testify/assert"
+)
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestUptime(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(100 * time.Hour)
+
+	assert.Equal(t, float64(100), Uptime(nil, from, to))
+	assert.Equal(t, float64(100), Uptime(nil, to, from))
+
+	// overlapping windows are only counted once
+	windows := []Window{
+		{Start: from.Add(10 * time.Hour), End: timePtr(from.Add(15 * time.Hour))},
+		{Start: from.Add(12 * time.Hour), End: timePtr(from.Add(20 * time.Hour))},
+	}
+	assert.InDelta(t, 90, Uptime(windows, from, to), 0.0001)
+
+	// windows are clipped to the range, and ongoing windows last until its end
+	windows = []Window{
+		{Start: from.Add(-10 * time.Hour), End: timePtr(from.Add(5 * time.Hour))},
+		{Start: from.Add(95 * time.Hour)},
+		{Start: from.Add(-20 * time.Hour), End: timePtr(from.Add(-15 * time.Hour))},
+	}
+	assert.InDelta(t, 90, Uptime(windows, from, to), 0.0001)
+}
+
+func TestDailyUptime(t *testing.T) {
+	from := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 3, 6, 0, 0, 0, time.UTC)
+
+	windows := []Window{
+		{Start: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), End: timePtr(time.Date(2024, 1, 2, 6, 0, 0, 0, time.UTC))},
+	}
+
+	days := DailyUptime(windows, from, to)
+	assert.Equal(t, []types.StatusPageDailyUptime{
+		{Date: "2024-01-01", UptimePercentage: 100},
+		{Date: "2024-01-02", UptimePercentage: 75},
+		{Date: "2024-01-03", UptimePercentage: 100},
+	}, days)
+}
+
+func TestSummarize(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	created := now.Add(-100 * time.Hour)
+
+	page := &models.StatusPage{Slug: "acme", Title: "Acme status"}
+
+	api := &models.StatusPageComponent{Name: "API", Type: types.StatusPageComponentType_Endpoint, URL: "https://api.acme.dev", LastCheckedAt: timePtr(now)}
+	api.ID = 1
+	api.CreatedAt = created
+
+	web := &models.StatusPageComponent{Name: "Web", Type: types.StatusPageComponentType_App, AppName: "web", URL: "https://acme.dev"}
+	web.ID = 2
+	web.CreatedAt = created
+
+	cluster := &models.StatusPageComponent{Name: "Cluster", Type: types.StatusPageComponentType_Cluster, ClusterID: 7}
+	cluster.ID = 3
+	cluster.CreatedAt = created
+
+	apiIncident := &models.StatusPageIncident{ComponentID: 1, Severity: types.ServiceStatus_Failure, StartedAt: now.Add(-10 * time.Hour), ResolvedAt: timePtr(now.Add(-5 * time.Hour))}
+	pageIncident := &models.StatusPageIncident{Severity: types.ServiceStatus_PartialFailure, StartedAt: now.Add(-1 * time.Hour)}
+
+	systemStatuses := map[uint][]models.SystemServiceStatus{
+		7: {
+			{
+				Severity:  string(types.ServiceStatus_Failure),
+				StartTime: sql.NullTime{Time: now.Add(-20 * time.Hour), Valid: true},
+				EndTime:   sql.NullTime{Time: now.Add(-10 * time.Hour), Valid: true},
+			},
+			{
+				Severity:  string(types.ServiceStatus_Failure),
+				StartTime: sql.NullTime{Time: now.Add(-30 * time.Minute), Valid: true},
+			},
+		},
+	}
+
+	summary := Summarize(page, []*models.StatusPageComponent{api, web, cluster}, []*models.StatusPageIncident{pageIncident, apiIncident}, systemStatuses, now)
+
+	assert.Equal(t, "acme", summary.Slug)
+	assert.Equal(t, types.ServiceStatus_Failure, summary.Status)
+	assert.Len(t, summary.Incidents, 2)
+	assert.Len(t, summary.Components, 3)
+
+	// the page-wide incident affects every component, but only failures count as downtime
+	assert.Equal(t, types.ServiceStatus_PartialFailure, summary.Components[0].Status)
+	assert.InDelta(t, 95, summary.Components[0].UptimePercentage, 0.0001)
+
+	// components whose URL was never checked have an undefined status, which is outranked by incidents
+	assert.Equal(t, types.ServiceStatus_PartialFailure, summary.Components[1].Status)
+	assert.Equal(t, float64(100), summary.Components[1].UptimePercentage)
+
+	assert.Equal(t, types.ServiceStatus_Failure, summary.Components[2].Status)
+	assert.InDelta(t, 89.5, summary.Components[2].UptimePercentage, 0.0001)
+	assert.Len(t, summary.Components[2].DailyUptime, 5)
+
+	web.LastCheckedAt = nil
+	summary = Summarize(page, []*models.StatusPageComponent{web}, nil, nil, now)
+	assert.Equal(t, types.ServiceStatus_Undefined, summary.Status)
+}
+
+func TestApplyCheckResult(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	component := &models.StatusPageComponent{Name: "API", StatusPageID: 3}
+	component.ID = 4
+
+	failed := synthetics.Result{StatusCode: 502, Error: "unexpected status code 502", Latency: 120 * time.Millisecond, CheckedAt: now}
+
+	assert.Equal(t, CheckAction_None, ApplyCheckResult(component, failed, false))
+	assert.Equal(t, uint(1), component.ConsecutiveFailures)
+	assert.Equal(t, uint(120), component.LastLatencyMs)
+	assert.Equal(t, "unexpected status code 502", component.LastCheckError)
+
+	failed.CheckedAt = now.Add(time.Minute)
+	assert.Equal(t, CheckAction_OpenIncident, ApplyCheckResult(component, failed, false))
+
+	incident := NewAutomaticIncident(component, failed, time.Minute)
+	assert.True(t, incident.Automatic)
+	assert.Equal(t, uint(3), incident.StatusPageID)
+	assert.Equal(t, uint(4), incident.ComponentID)
+	assert.Equal(t, types.ServiceStatus_Failure, incident.Severity)
+	assert.Equal(t, now, incident.StartedAt)
+
+	failed.CheckedAt = now.Add(2 * time.Minute)
+	assert.Equal(t, CheckAction_None, ApplyCheckResult(component, failed, true))
+
+	healthy := synthetics.Result{Healthy: true, StatusCode: 200, CheckedAt: now.Add(3 * time.Minute)}
+	assert.Equal(t, CheckAction_ResolveIncident, ApplyCheckResult(component, healthy, true))
+	assert.Equal(t, uint(0), component.ConsecutiveFailures)
+	assert.Equal(t, "", component.LastCheckError)
+
+	ResolveIncident(incident, "", healthy.CheckedAt)
+	assert.Equal(t, types.StatusPageIncidentStatus_Resolved, incident.Status)
+	assert.Equal(t, healthy.CheckedAt, *incident.ResolvedAt)
+}
+
+func TestFeeds(t *testing.T) {
+	updated := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	summary := types.StatusPageSummary{
+		Slug:  "acme",
+		Title: "Acme status",
+		Components: []types.StatusPageComponentSummary{
+			{ID: 1, Name: "API"},
+		},
+		Incidents: []types.StatusPageIncident{
+			{ID: 9, ComponentID: 1, Title: "API errors", Message: "Elevated 5xx", Status: types.StatusPageIncidentStatus_Monitoring, StartedAt: updated.Add(-time.Hour), UpdatedAt: updated},
+			{ID: 8, Title: "Maintenance", Status: types.StatusPageIncidentStatus_Resolved, StartedAt: updated.Add(-48 * time.Hour), UpdatedAt: updated.Add(-47 * time.Hour)},
+		},
+	}
+
+	raw, err := RSS(summary, "https://status.acme.dev")
+	assert.NoError(t, err)
+
+	rss := rssFeed{}
+	assert.NoError(t, xml.Unmarshal(raw, &rss))
+	assert.Equal(t, "Acme status", rss.Channel.Title)
+	assert.Len(t, rss.Channel.Items, 2)
+	assert.Equal(t, "[Monitoring] API errors", rss.Channel.Items[0].Title)
+	assert.Equal(t, "acme-incident-9", rss.Channel.Items[0].GUID.Value)
+	assert.Equal(t, "Elevated 5xx\n\nAffected: API", rss.Channel.Items[0].Description)
+	assert.Equal(t, "Affected: All components", rss.Channel.Items[1].Description)
+	assert.Equal(t, updated.Format(time.RFC1123Z), rss.Channel.LastBuildDate)
+
+	raw, err = JSONFeed(summary, "https://status.acme.dev", "https://status.acme.dev/feed.json")
+	assert.NoError(t, err)
+
+	feed := jsonFeed{}
+	assert.NoError(t, json.Unmarshal(raw, &feed))
+	assert.Equal(t, jsonFeedVersion, feed.Version)
+	assert.Len(t, feed.Items, 2)
+	assert.Equal(t, "[Resolved] Maintenance", feed.Items[1].Title)
+	assert.True(t, strings.HasPrefix(feed.Items[0].ID, "acme-"))
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, ValidateSlug("acme"))
+	assert.NoError(t, ValidateSlug("acme-prod-1"))
+	assert.Error(t, ValidateSlug(""))
+	assert.Error(t, ValidateSlug("-acme"))
+	assert.Error(t, ValidateSlug("Acme"))
+	assert.Error(t, ValidateSlug("acme/prod"))
+
+	assert.NoError(t, ValidateComponent(types.StatusPageComponent{Name: "API", Type: types.StatusPageComponentType_Endpoint, URL: "https://api.acme.dev"}))
+	assert.NoError(t, ValidateComponent(types.StatusPageComponent{Name: "Web", Type: types.StatusPageComponentType_App, ClusterID: 1, AppName: "web"}))
+	assert.NoError(t, ValidateComponent(types.StatusPageComponent{Name: "Cluster", Type: types.StatusPageComponentType_Cluster, ClusterID: 1}))
+
+	assert.Error(t, ValidateComponent(types.StatusPageComponent{Name: "API", Type: types.StatusPageComponentType_Endpoint}))
+	assert.Error(t, ValidateComponent(types.StatusPageComponent{Name: "Web", Type: types.StatusPageComponentType_App, ClusterID: 1}))
+	assert.Error(t, ValidateComponent(types.StatusPageComponent{Name: "Cluster", Type: types.StatusPageComponentType_Cluster, ClusterID: 1, URL: "https://acme.dev"}))
+	assert.Error(t, ValidateComponent(types.StatusPageComponent{Name: "Queue", Type: "queue"}))
+
+	// urls are checked from outside the cluster, and must not reach private or internal hosts
+	for _, url := range []string{
+		"ftp://api.acme.dev",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.12:8080",
+		"http://127.0.0.1",
+		"http://[::1]/",
+		"http://kubernetes.default.svc",
+		"http://web",
+	} {
+		assert.Error(t, ValidateComponent(types.StatusPageComponent{Name: "API", Type: types.StatusPageComponentType_Endpoint, URL: url}), url)
+		assert.Error(t, ValidateComponent(types.StatusPageComponent{Name: "Web", Type: types.StatusPageComponentType_App, ClusterID: 1, AppName: "web", URL: url}), url)
+	}
+}
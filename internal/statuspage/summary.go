@@ -0,0 +1,128 @@
+package statuspage
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// statusRank orders statuses from best to worst
+var statusRank = map[types.ServiceStatus]int{
+	types.ServiceStatus_Healthy:        0,
+	types.ServiceStatus_Undefined:      1,
+	types.ServiceStatus_PartialFailure: 2,
+	types.ServiceStatus_Failure:        3,
+}
+
+// worst returns the worse of two statuses
+func worst(a, b types.ServiceStatus) types.ServiceStatus {
+	if statusRank[b] > statusRank[a] {
+		return b
+	}
+
+	return a
+}
+
+// Summarize computes the current status and uptime history of every component of a status page. Incidents should be
+// those ongoing at any point in the history of the page, and system service statuses are keyed by the cluster they
+// belong to.
+func Summarize(
+	page *models.StatusPage,
+	components []*models.StatusPageComponent,
+	incidents []*models.StatusPageIncident,
+	systemStatuses map[uint][]models.SystemServiceStatus,
+	now time.Time,
+) types.StatusPageSummary {
+	res := types.StatusPageSummary{
+		Slug:       page.Slug,
+		Title:      page.Title,
+		Status:     types.ServiceStatus_Healthy,
+		Components: make([]types.StatusPageComponentSummary, 0, len(components)),
+		Incidents:  make([]types.StatusPageIncident, 0, len(incidents)),
+	}
+
+	for _, incident := range incidents {
+		if incident.ComponentID == 0 && incident.ResolvedAt == nil {
+			res.Status = worst(res.Status, incident.Severity)
+		}
+
+		res.Incidents = append(res.Incidents, incident.ToStatusPageIncidentType())
+	}
+
+	historyStart := now.AddDate(0, 0, -HistoryDays)
+
+	for _, component := range components {
+		var clusterStatuses []models.SystemServiceStatus
+		if component.Type == types.StatusPageComponentType_Cluster {
+			clusterStatuses = systemStatuses[component.ClusterID]
+		}
+
+		summary := summarizeComponent(component, incidents, clusterStatuses, historyStart, now)
+		res.Status = worst(res.Status, summary.Status)
+		res.Components = append(res.Components, summary)
+	}
+
+	return res
+}
+
+func summarizeComponent(
+	component *models.StatusPageComponent,
+	incidents []*models.StatusPageIncident,
+	clusterStatuses []models.SystemServiceStatus,
+	historyStart, now time.Time,
+) types.StatusPageComponentSummary {
+	status := types.ServiceStatus_Healthy
+	if component.URL != "" && component.LastCheckedAt == nil {
+		status = types.ServiceStatus_Undefined
+	}
+
+	var downtime []Window
+
+	for _, incident := range incidents {
+		if incident.ComponentID != 0 && incident.ComponentID != component.ID {
+			continue
+		}
+
+		if incident.ResolvedAt == nil {
+			status = worst(status, incident.Severity)
+		}
+
+		if incident.Severity == types.ServiceStatus_Failure {
+			downtime = append(downtime, Window{Start: incident.StartedAt, End: incident.ResolvedAt})
+		}
+	}
+
+	for _, s := range clusterStatuses {
+		severity := types.ServiceStatus(s.Severity)
+
+		var end *time.Time
+		if s.EndTime.Valid {
+			t := s.EndTime.Time
+			end = &t
+		} else {
+			status = worst(status, severity)
+		}
+
+		if severity == types.ServiceStatus_Failure && s.StartTime.Valid {
+			downtime = append(downtime, Window{Start: s.StartTime.Time, End: end})
+		}
+	}
+
+	// uptime is only counted from when the component was added to the page
+	from := historyStart
+	if component.CreatedAt.After(from) {
+		from = component.CreatedAt
+	}
+
+	return types.StatusPageComponentSummary{
+		ID:               component.ID,
+		Name:             component.Name,
+		Type:             component.Type,
+		Status:           status,
+		UptimePercentage: Uptime(downtime, from, now),
+		DailyUptime:      DailyUptime(downtime, from, now),
+		LastCheckedAt:    component.LastCheckedAt,
+		LatencyMs:        component.LastLatencyMs,
+	}
+}
@@ -0,0 +1,114 @@
+package statuspage
+
+import (
+	"sort"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// HistoryDays is how many days of uptime history are shown on a status page
+const HistoryDays = 90
+
+// Window is a period of downtime. Windows without an end are still ongoing.
+type Window struct {
+	Start time.Time
+	End   *time.Time
+}
+
+// Uptime returns the percentage of time between from and to which is not covered by any of the windows. Overlapping
+// windows are only counted once.
+func Uptime(windows []Window, from, to time.Time) float64 {
+	total := to.Sub(from)
+	if total <= 0 {
+		return 100
+	}
+
+	down := downtime(windows, from, to)
+
+	return 100 * float64(total-down) / float64(total)
+}
+
+// DailyUptime returns the uptime of each UTC day between from and to, with the last day ending at to
+func DailyUptime(windows []Window, from, to time.Time) []types.StatusPageDailyUptime {
+	var res []types.StatusPageDailyUptime
+
+	from = from.UTC()
+	to = to.UTC()
+
+	for day := startOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		start := day
+		if start.Before(from) {
+			start = from
+		}
+
+		end := day.AddDate(0, 0, 1)
+		if end.After(to) {
+			end = to
+		}
+
+		res = append(res, types.StatusPageDailyUptime{
+			Date:             day.Format("2006-01-02"),
+			UptimePercentage: Uptime(windows, start, end),
+		})
+	}
+
+	return res
+}
+
+// downtime returns how much of the time between from and to is covered by the windows
+func downtime(windows []Window, from, to time.Time) time.Duration {
+	type span struct{ start, end time.Time }
+
+	var spans []span
+
+	for _, w := range windows {
+		start := w.Start
+		end := to
+		if w.End != nil && w.End.Before(to) {
+			end = *w.End
+		}
+
+		if start.Before(from) {
+			start = from
+		}
+
+		if end.After(start) {
+			spans = append(spans, span{start, end})
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].start.Before(spans[j].start)
+	})
+
+	var total time.Duration
+	var cur *span
+
+	for i := range spans {
+		s := spans[i]
+
+		if cur != nil && !s.start.After(cur.end) {
+			if s.end.After(cur.end) {
+				cur.end = s.end
+			}
+			continue
+		}
+
+		if cur != nil {
+			total += cur.end.Sub(cur.start)
+		}
+
+		cur = &s
+	}
+
+	if cur != nil {
+		total += cur.end.Sub(cur.start)
+	}
+
+	return total
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,52 @@
+package statuspage
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/synthetics"
+)
+
+var slugRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// ValidateSlug checks that a slug can be used in the public URL of a status page
+func ValidateSlug(slug string) error {
+	if !slugRegex.MatchString(slug) {
+		return fmt.Errorf("slug %q must consist of lowercase letters, numbers and dashes, and must start and end with a letter or number", slug)
+	}
+
+	return nil
+}
+
+// ValidateComponent checks that a component has the fields required by its type
+func ValidateComponent(component types.StatusPageComponent) error {
+	switch component.Type {
+	case types.StatusPageComponentType_Endpoint:
+		if component.URL == "" {
+			return fmt.Errorf("endpoint component %q must have a url", component.Name)
+		}
+	case types.StatusPageComponentType_App:
+		if component.ClusterID == 0 || component.AppName == "" {
+			return fmt.Errorf("app component %q must have a cluster id and app name", component.Name)
+		}
+	case types.StatusPageComponentType_Cluster:
+		if component.ClusterID == 0 {
+			return fmt.Errorf("cluster component %q must have a cluster id", component.Name)
+		}
+		if component.URL != "" {
+			return fmt.Errorf("cluster component %q cannot have a url", component.Name)
+		}
+	default:
+		return fmt.Errorf("component %q has unknown type %q", component.Name, component.Type)
+	}
+
+	// the url is checked from outside the cluster, and whether it is up is shown on the public status page
+	if component.URL != "" {
+		if err := synthetics.ValidatePublicURL(component.URL); err != nil {
+			return fmt.Errorf("component %q: %w", component.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package synthetics
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// maxRedirects is the number of redirects a probe follows before it fails
+const maxRedirects = 10
+
+// ErrNonPublicAddress is returned when a probe would connect to an address which is not reachable from the public internet,
+// such as a private, loopback or link-local address
+var ErrNonPublicAddress = errors.New("not a public address")
+
+// nonPublicNetworks are the reserved networks which are not covered by the checks of the net package
+var nonPublicNetworks = mustParseCIDRs(
+	"0.0.0.0/8",       // this network
+	"100.64.0.0/10",   // carrier-grade NAT
+	"192.0.0.0/24",    // IETF protocol assignments
+	"192.0.2.0/24",    // documentation
+	"198.18.0.0/15",   // benchmarking
+	"198.51.100.0/24", // documentation
+	"203.0.113.0/24",  // documentation
+	"240.0.0.0/4",     // reserved, including broadcast
+	"64:ff9b::/96",    // NAT64, which can reach private IPv4 addresses
+	"100::/64",        // discard
+	"2001:db8::/32",   // documentation
+)
+
+// nonPublicHostSuffixes are host names which only resolve inside of a cluster or a private network
+var nonPublicHostSuffixes = []string{".localhost", ".local", ".internal", ".svc"}
+
+// IsPublicIP returns true if an IP address is a unicast address which is reachable from the public internet
+func IsPublicIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ValidatePublicURL checks that a URL can be probed: it must be an http or https URL whose host is not an IP address
+// which is not public, nor a name which only resolves inside of a cluster. Names which resolve to addresses which are
+// not public are refused when the probe connects, by the client of NewProbeClient.
+func ValidatePublicURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("url %s must use http or https", rawURL)
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return fmt.Errorf("url %s must have a host", rawURL)
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if !IsPublicIP(ip) {
+			return fmt.Errorf("host of url %s is %w", rawURL, ErrNonPublicAddress)
+		}
+		return nil
+	}
+
+	// names without a dot are resolved with the search domains of the resolver, which include the namespaces of a cluster
+	if !strings.Contains(host, ".") {
+		return fmt.Errorf("host of url %s must be a fully qualified domain name", rawURL)
+	}
+
+	for _, suffix := range nonPublicHostSuffixes {
+		if host == strings.TrimPrefix(suffix, ".") || strings.HasSuffix(host, suffix) {
+			return fmt.Errorf("host of url %s is %w", rawURL, ErrNonPublicAddress)
+		}
+	}
+
+	return nil
+}
+
+// NewProbeClient returns a client for probing URLs which are set by users. The address of every connection is checked
+// once the host is resolved, so that neither the URL nor any redirect can reach an address which is not public.
+func NewProbeClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: publicAddressControl,
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// a proxy would connect on behalf of the probe, so the addresses it connects to could not be checked
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: checkRedirect,
+	}
+}
+
+// checkRedirect refuses redirects to URLs which cannot be probed, and stops after maxRedirects redirects
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+
+	if err := ValidatePublicURL(req.URL.String()); err != nil {
+		return fmt.Errorf("refused redirect: %w", err)
+	}
+
+	return nil
+}
+
+// publicAddressControl refuses connections to addresses which are not public. It runs after the host of a request has
+// been resolved, so it also covers names which resolve to private addresses.
+func publicAddressControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublicIP(ip) {
+		return fmt.Errorf("refused to connect to %s: %w", host, ErrNonPublicAddress)
+	}
+
+	return nil
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+
+	return networks
+}
@@ -0,0 +1,84 @@
+package synthetics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	for _, ip := range []string{"8.8.8.8", "151.101.1.69", "2606:4700:4700::1111"} {
+		assert.True(t, IsPublicIP(net.ParseIP(ip)), ip)
+	}
+
+	for _, ip := range []string{
+		"127.0.0.1",
+		"10.4.0.1",
+		"172.16.0.1",
+		"192.168.1.1",
+		"169.254.169.254",
+		"100.64.0.1",
+		"0.0.0.0",
+		"255.255.255.255",
+		"224.0.0.1",
+		"::1",
+		"::",
+		"fd00:ec2::254",
+		"fe80::1",
+		"::ffff:10.0.0.1",
+		"64:ff9b::a00:1",
+	} {
+		assert.False(t, IsPublicIP(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestValidatePublicURL(t *testing.T) {
+	for _, url := range []string{"https://api.acme.dev/healthz", "http://8.8.8.8:8080", "https://acme.dev."} {
+		assert.NoError(t, ValidatePublicURL(url), url)
+	}
+
+	for _, url := range []string{
+		"",
+		"ftp://acme.dev",
+		"file:///etc/passwd",
+		"https://",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]:8080",
+		"http://localhost:8080",
+		"http://api.localhost",
+		"http://web",
+		"http://web.default.svc",
+		"http://web.default.svc.cluster.local",
+		"http://metadata.google.internal",
+	} {
+		assert.Error(t, ValidatePublicURL(url), url)
+	}
+}
+
+func TestNewProbeClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// the test server listens on a loopback address, so the connection is refused once the address is known
+	client := NewProbeClient(DefaultTimeout)
+	res := Probe(context.Background(), client, server.URL)
+	assert.False(t, res.Healthy)
+	assert.Contains(t, res.Error, ErrNonPublicAddress.Error())
+
+	req, err := http.NewRequest(http.MethodGet, "http://169.254.169.254/latest/meta-data", nil)
+	assert.NoError(t, err)
+	err = client.CheckRedirect(req, []*http.Request{{}})
+	assert.True(t, errors.Is(err, ErrNonPublicAddress), "redirects to private addresses should be refused, got %v", err)
+
+	req, err = http.NewRequest(http.MethodGet, "https://acme.dev/login", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, client.CheckRedirect(req, []*http.Request{{}}))
+	assert.Error(t, client.CheckRedirect(req, make([]*http.Request, maxRedirects)))
+}
@@ -0,0 +1,215 @@
+package synthetics
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+const (
+	// FailureThreshold is the number of runs of a check which must fail in a row before an incident is opened
+	FailureThreshold = 3
+	// ResultRetention is how long the results of a check are kept
+	ResultRetention = 30 * 24 * time.Hour
+
+	defaultPath            = "/"
+	defaultExpectedStatus  = 200
+	defaultIntervalSeconds = 60
+)
+
+// IncidentAction is what should be done with the incident of a check after it runs
+type IncidentAction int
+
+const (
+	// IncidentAction_None leaves the incident of the check as it is
+	IncidentAction_None IncidentAction = iota
+	// IncidentAction_Open notifies a new incident for the check
+	IncidentAction_Open
+	// IncidentAction_Resolve notifies that the incident of the check is resolved
+	IncidentAction_Resolve
+)
+
+// WithDefaults returns a spec with the defaults of unset fields filled in
+func WithDefaults(spec types.AppSyntheticCheckSpec) types.AppSyntheticCheckSpec {
+	if spec.Path == "" {
+		spec.Path = defaultPath
+	}
+	if spec.ExpectedStatus == 0 {
+		spec.ExpectedStatus = defaultExpectedStatus
+	}
+	if spec.IntervalSeconds == 0 {
+		spec.IntervalSeconds = defaultIntervalSeconds
+	}
+
+	return spec
+}
+
+// ValidateSpecs checks the constraints on a set of checks which cannot be expressed as validation tags
+func ValidateSpecs(specs []types.AppSyntheticCheckSpec) error {
+	names := make(map[string]bool, len(specs))
+
+	for _, spec := range specs {
+		if names[spec.Name] {
+			return fmt.Errorf("synthetic check name %s is declared more than once", spec.Name)
+		}
+		names[spec.Name] = true
+
+		if _, err := regexp.Compile(spec.BodyRegex); err != nil {
+			return fmt.Errorf("synthetic check %s has an invalid body regex: %w", spec.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateServiceDomains checks that each check is sent to a domain of a web service of the app, where webServiceDomains
+// are the domains of each web service of the current revision of the app, keyed by service name
+func ValidateServiceDomains(specs []types.AppSyntheticCheckSpec, webServiceDomains map[string][]string) error {
+	for _, spec := range specs {
+		domains, ok := webServiceDomains[spec.ServiceName]
+		if !ok {
+			return fmt.Errorf("synthetic check %s applies to service %s, which is not a web service of the app", spec.Name, spec.ServiceName)
+		}
+
+		if !containsDomain(domains, spec.Domain) {
+			return fmt.Errorf("synthetic check %s is sent to %s, which is not a domain of service %s", spec.Name, spec.Domain, spec.ServiceName)
+		}
+
+		if err := ValidatePublicURL("https://" + spec.Domain); err != nil {
+			return fmt.Errorf("synthetic check %s: %w", spec.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// containsDomain returns true if a domain is among a list of domains, ignoring case and a trailing dot
+func containsDomain(domains []string, domain string) bool {
+	normalize := func(d string) string {
+		return strings.TrimSuffix(strings.ToLower(d), ".")
+	}
+
+	for _, d := range domains {
+		if normalize(d) == normalize(domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckFromModel builds the check that is run for a synthetic check of an app
+func CheckFromModel(check *models.AppSyntheticCheck) (Check, error) {
+	res := Check{
+		URL:              fmt.Sprintf("https://%s%s", check.Domain, check.Path),
+		ExpectedStatus:   check.ExpectedStatus,
+		TLSExpiryWarning: time.Duration(check.TLSExpiryWarningDays) * 24 * time.Hour,
+	}
+
+	if err := ValidatePublicURL(res.URL); err != nil {
+		return res, err
+	}
+
+	if check.BodyRegex != "" {
+		re, err := regexp.Compile(check.BodyRegex)
+		if err != nil {
+			return res, fmt.Errorf("invalid body regex: %w", err)
+		}
+		res.BodyRegex = re
+	}
+
+	return res, nil
+}
+
+// RecordResult updates the state of a check with the result of a run, and returns the result to store along with what
+// should be done with the incident of the check. The incident is only marked as opened or resolved by the caller once
+// it has been notified, so that a failed notification is retried on the next run.
+func RecordResult(check *models.AppSyntheticCheck, result Result) (*models.AppSyntheticCheckResult, IncidentAction) {
+	status := types.AppSyntheticCheckStatus_Passing
+	message := result.Error
+
+	switch {
+	case !result.Healthy:
+		status = types.AppSyntheticCheckStatus_Failing
+	case result.Warning != "":
+		status = types.AppSyntheticCheckStatus_Warning
+		message = result.Warning
+	}
+
+	checkedAt := result.CheckedAt
+	check.LastStatus = status
+	check.LastCheckedAt = &checkedAt
+	check.NextCheckAt = checkedAt.Add(time.Duration(check.IntervalSeconds) * time.Second)
+
+	stored := &models.AppSyntheticCheckResult{
+		AppSyntheticCheckID: check.ID,
+		Status:              status,
+		StatusCode:          result.StatusCode,
+		LatencyMs:           uint(result.Latency.Milliseconds()),
+		Error:               message,
+		TLSExpiresAt:        result.TLSExpiresAt,
+		CheckedAt:           checkedAt,
+	}
+
+	if result.Healthy {
+		check.ConsecutiveFailures = 0
+
+		if check.IncidentOpenedAt != nil {
+			return stored, IncidentAction_Resolve
+		}
+
+		return stored, IncidentAction_None
+	}
+
+	check.ConsecutiveFailures++
+
+	if check.ConsecutiveFailures >= FailureThreshold && check.IncidentOpenedAt == nil {
+		return stored, IncidentAction_Open
+	}
+
+	return stored, IncidentAction_None
+}
+
+// Incident builds the incident that is notified when a check opens or resolves. The incident of a check is identified
+// by when it was opened, so that each failure is notified as a separate incident.
+func Incident(check *models.AppSyntheticCheck, appName string, result *models.AppSyntheticCheckResult, openedAt time.Time) *types.Incident {
+	status := types.IncidentStatusActive
+	summary := fmt.Sprintf(
+		"Synthetic check %s of service %s failed %d times in a row: GET https://%s%s: %s",
+		check.Name, check.ServiceName, check.ConsecutiveFailures, check.Domain, check.Path, result.Error,
+	)
+
+	if result.Status != types.AppSyntheticCheckStatus_Failing {
+		status = types.IncidentStatusResolved
+		summary = fmt.Sprintf(
+			"Synthetic check %s of service %s is passing again: GET https://%s%s returned %d",
+			check.Name, check.ServiceName, check.Domain, check.Path, result.StatusCode,
+		)
+	}
+
+	lastSeen := result.CheckedAt
+
+	return &types.Incident{
+		IncidentMeta: &types.IncidentMeta{
+			ID:                      fmt.Sprintf("synthetic-check-%d-%d", check.ID, openedAt.Unix()),
+			ReleaseName:             appName,
+			ReleaseNamespace:        check.Namespace,
+			CreatedAt:               openedAt,
+			UpdatedAt:               result.CheckedAt,
+			LastSeen:                &lastSeen,
+			Status:                  status,
+			Summary:                 summary,
+			ShortSummary:            fmt.Sprintf("Synthetic check %s is failing", check.Name),
+			Severity:                types.SeverityCritical,
+			InvolvedObjectKind:      types.InvolvedObjectDeployment,
+			InvolvedObjectName:      fmt.Sprintf("%s-%s", appName, check.ServiceName),
+			InvolvedObjectNamespace: check.Namespace,
+		},
+		Pods:   []string{},
+		Detail: result.Error,
+	}
+}
@@ -0,0 +1,117 @@
+package synthetics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// DefaultTimeout is how long a probe waits for a response when its client has no timeout
+const DefaultTimeout = 10 * time.Second
+
+const (
+	// maxDrainBytes is how much of a response body is read before the connection is closed, so that it can be reused
+	maxDrainBytes = 64 * 1024
+	// maxBodyBytes is how much of a response body is matched against the body regex of a check
+	maxBodyBytes = 1024 * 1024
+)
+
+// Check is an HTTP check of a URL
+type Check struct {
+	URL string
+	// ExpectedStatus is the status code that the response must have. If zero, any status below 400 passes.
+	ExpectedStatus int
+	// BodyRegex must match the response body, if set
+	BodyRegex *regexp.Regexp
+	// TLSExpiryWarning warns when the TLS certificate of the URL expires within this duration, if set
+	TLSExpiryWarning time.Duration
+}
+
+// Result is the outcome of probing a URL
+type Result struct {
+	// Healthy is set when the response matched every expectation of the check
+	Healthy    bool
+	StatusCode int
+	Latency    time.Duration
+	// Error is the reason that the probe failed, if it did
+	Error string
+	// Warning is set on healthy results whose TLS certificate expires soon
+	Warning string
+	// TLSExpiresAt is when the TLS certificate of the URL expires, for https URLs
+	TLSExpiresAt *time.Time
+	CheckedAt    time.Time
+}
+
+// Probe sends a GET request to a URL from outside the cluster, the way a user of the app would. Any status below 400
+// is healthy.
+func Probe(ctx context.Context, client *http.Client, url string) Result {
+	return Run(ctx, client, Check{URL: url})
+}
+
+// Run sends the request of a check, and matches the response against its expectations
+func Run(ctx context.Context, client *http.Client, check Check) Result {
+	if client == nil {
+		client = NewProbeClient(DefaultTimeout)
+	}
+
+	res := Result{CheckedAt: time.Now().UTC()}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, check.URL, nil)
+	if err != nil {
+		res.Error = fmt.Sprintf("invalid url: %s", err.Error())
+		return res
+	}
+
+	req.Header.Set("User-Agent", "porter-synthetics/1.0")
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	res.Latency = time.Since(start)
+
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	defer resp.Body.Close() // nolint:errcheck
+
+	res.StatusCode = resp.StatusCode
+
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		expiresAt := resp.TLS.PeerCertificates[0].NotAfter.UTC()
+		res.TLSExpiresAt = &expiresAt
+	}
+
+	var body []byte
+	if check.BodyRegex != nil {
+		body, err = io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+		if err != nil {
+			res.Error = fmt.Sprintf("error reading response body: %s", err.Error())
+			return res
+		}
+	} else {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+	}
+
+	switch {
+	case check.ExpectedStatus == 0 && resp.StatusCode >= http.StatusBadRequest:
+		res.Error = fmt.Sprintf("unexpected status code %d", resp.StatusCode)
+		return res
+	case check.ExpectedStatus != 0 && resp.StatusCode != check.ExpectedStatus:
+		res.Error = fmt.Sprintf("unexpected status code %d, expected %d", resp.StatusCode, check.ExpectedStatus)
+		return res
+	case check.BodyRegex != nil && !check.BodyRegex.Match(body):
+		res.Error = fmt.Sprintf("response body does not match %s", check.BodyRegex.String())
+		return res
+	}
+
+	res.Healthy = true
+
+	if check.TLSExpiryWarning > 0 && res.TLSExpiresAt != nil && res.TLSExpiresAt.Sub(res.CheckedAt) < check.TLSExpiryWarning {
+		res.Warning = fmt.Sprintf("tls certificate expires at %s", res.TLSExpiresAt.Format(time.RFC3339))
+	}
+
+	return res
+}
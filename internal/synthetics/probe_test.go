@@ -0,0 +1,168 @@
+package synthetics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	res := Probe(context.Background(), server.Client(), server.URL)
+	assert.True(t, res.Healthy)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Empty(t, res.Error)
+
+	res = Probe(context.Background(), server.Client(), server.URL+"/down")
+	assert.False(t, res.Healthy)
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	assert.Equal(t, "unexpected status code 503", res.Error)
+
+	res = Probe(context.Background(), server.Client(), "://bad")
+	assert.False(t, res.Healthy)
+	assert.NotEmpty(t, res.Error)
+}
+
+func TestRun(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/created" {
+			w.WriteHeader(http.StatusCreated)
+		}
+		_, _ = w.Write([]byte(`{"status":"ok","version":"1.2.3"}`))
+	}))
+	defer server.Close()
+
+	res := Run(context.Background(), server.Client(), Check{
+		URL:            server.URL + "/healthz",
+		ExpectedStatus: http.StatusOK,
+		BodyRegex:      regexp.MustCompile(`"status":"ok"`),
+	})
+	assert.True(t, res.Healthy)
+	assert.Empty(t, res.Warning)
+	assert.NotNil(t, res.TLSExpiresAt)
+
+	res = Run(context.Background(), server.Client(), Check{URL: server.URL + "/created", ExpectedStatus: http.StatusOK})
+	assert.False(t, res.Healthy)
+	assert.Equal(t, "unexpected status code 201, expected 200", res.Error)
+
+	res = Run(context.Background(), server.Client(), Check{URL: server.URL, BodyRegex: regexp.MustCompile(`"status":"degraded"`)})
+	assert.False(t, res.Healthy)
+	assert.Equal(t, `response body does not match "status":"degraded"`, res.Error)
+
+	// the certificate of the test server expires long before a hundred years from now
+	res = Run(context.Background(), server.Client(), Check{URL: server.URL, TLSExpiryWarning: 100 * 365 * 24 * time.Hour})
+	assert.True(t, res.Healthy)
+	assert.Contains(t, res.Warning, "tls certificate expires at")
+}
+
+func TestValidateSpecs(t *testing.T) {
+	assert.NoError(t, ValidateSpecs([]types.AppSyntheticCheckSpec{
+		{Name: "home", ServiceName: "web", Domain: "acme.dev"},
+		{Name: "health", ServiceName: "web", Domain: "acme.dev", BodyRegex: `"ok"`},
+	}))
+	assert.Error(t, ValidateSpecs([]types.AppSyntheticCheckSpec{
+		{Name: "home", ServiceName: "web", Domain: "acme.dev"},
+		{Name: "home", ServiceName: "api", Domain: "api.acme.dev"},
+	}))
+	assert.Error(t, ValidateSpecs([]types.AppSyntheticCheckSpec{
+		{Name: "home", ServiceName: "web", Domain: "acme.dev", BodyRegex: `(`},
+	}))
+
+	webServiceDomains := map[string][]string{
+		"web": {"acme.dev", "web-acme.onporter.run"},
+		// private web services have no domains
+		"internal": nil,
+	}
+	assert.NoError(t, ValidateServiceDomains([]types.AppSyntheticCheckSpec{
+		{Name: "home", ServiceName: "web", Domain: "acme.dev"},
+		{Name: "generated", ServiceName: "web", Domain: "Web-Acme.onporter.run."},
+	}, webServiceDomains))
+	assert.ErrorContains(t, ValidateServiceDomains([]types.AppSyntheticCheckSpec{
+		{Name: "home", ServiceName: "web", Domain: "169.254.169.254"},
+	}, webServiceDomains), "not a domain of service web")
+	assert.ErrorContains(t, ValidateServiceDomains([]types.AppSyntheticCheckSpec{
+		{Name: "home", ServiceName: "api", Domain: "acme.dev"},
+	}, webServiceDomains), "not a web service")
+	assert.ErrorContains(t, ValidateServiceDomains([]types.AppSyntheticCheckSpec{
+		{Name: "home", ServiceName: "internal", Domain: "acme.dev"},
+	}, webServiceDomains), "not a domain of service internal")
+
+	// a web service whose domain points at an internal name still cannot be checked
+	assert.ErrorIs(t, ValidateServiceDomains([]types.AppSyntheticCheckSpec{
+		{Name: "home", ServiceName: "web", Domain: "web.default.svc"},
+	}, map[string][]string{"web": {"web.default.svc"}}), ErrNonPublicAddress)
+
+	spec := WithDefaults(types.AppSyntheticCheckSpec{Name: "home"})
+	assert.Equal(t, "/", spec.Path)
+	assert.Equal(t, 200, spec.ExpectedStatus)
+	assert.Equal(t, uint(60), spec.IntervalSeconds)
+}
+
+func TestRecordResult(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	check := &models.AppSyntheticCheck{Name: "home", ServiceName: "web", Domain: "acme.dev", Path: "/", IntervalSeconds: 60, Namespace: "default"}
+	check.ID = 7
+
+	built, err := CheckFromModel(check)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://acme.dev/", built.URL)
+	assert.Nil(t, built.BodyRegex)
+
+	failed := Result{StatusCode: 502, Error: "unexpected status code 502, expected 200", Latency: 80 * time.Millisecond, CheckedAt: now}
+
+	for i := 1; i < FailureThreshold; i++ {
+		stored, action := RecordResult(check, failed)
+		assert.Equal(t, IncidentAction_None, action)
+		assert.Equal(t, types.AppSyntheticCheckStatus_Failing, stored.Status)
+		assert.Equal(t, uint(80), stored.LatencyMs)
+	}
+
+	stored, action := RecordResult(check, failed)
+	assert.Equal(t, IncidentAction_Open, action)
+	assert.Equal(t, uint(FailureThreshold), check.ConsecutiveFailures)
+	assert.Equal(t, now.Add(time.Minute), check.NextCheckAt)
+
+	incident := Incident(check, "acme", stored, now)
+	assert.Equal(t, "synthetic-check-7-1717200000", incident.ID)
+	assert.Equal(t, types.IncidentStatusActive, incident.Status)
+	assert.Equal(t, "acme", incident.ReleaseName)
+	assert.Equal(t, "acme-web", incident.InvolvedObjectName)
+	assert.Contains(t, incident.Summary, "failed 3 times in a row")
+
+	// the incident stays open until it is notified
+	_, action = RecordResult(check, failed)
+	assert.Equal(t, IncidentAction_Open, action)
+
+	check.IncidentOpenedAt = &now
+	_, action = RecordResult(check, failed)
+	assert.Equal(t, IncidentAction_None, action)
+
+	expiresAt := now.Add(24 * time.Hour)
+	passed := Result{Healthy: true, StatusCode: 200, Warning: "tls certificate expires soon", TLSExpiresAt: &expiresAt, CheckedAt: now.Add(5 * time.Minute)}
+
+	stored, action = RecordResult(check, passed)
+	assert.Equal(t, IncidentAction_Resolve, action)
+	assert.Equal(t, types.AppSyntheticCheckStatus_Warning, stored.Status)
+	assert.Equal(t, "tls certificate expires soon", stored.Error)
+	assert.Equal(t, uint(0), check.ConsecutiveFailures)
+
+	incident = Incident(check, "acme", stored, now)
+	assert.Equal(t, types.IncidentStatusResolved, incident.Status)
+	assert.Equal(t, "synthetic-check-7-1717200000", incident.ID)
+}
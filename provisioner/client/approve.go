@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// Approve approves the plan for an operation, which starts the apply or destroy
+func (c *Client) Approve(
+	ctx context.Context,
+	projID, infraID uint,
+	operationID string,
+) (*types.Operation, error) {
+	resp := &types.Operation{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/infras/%d/operations/%s/approve",
+			projID,
+			infraID,
+			operationID,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
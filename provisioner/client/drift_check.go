@@ -0,0 +1,28 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// DriftCheck starts a drift check for infra
+func (c *Client) DriftCheck(
+	ctx context.Context,
+	projID, infraID uint,
+) (*types.Operation, error) {
+	resp := &types.Operation{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/infras/%d/drift_check",
+			projID,
+			infraID,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
@@ -0,0 +1,28 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// Reconcile re-applies the last-applied values for infra which has drifted
+func (c *Client) Reconcile(
+	ctx context.Context,
+	projID, infraID uint,
+) (*types.Operation, error) {
+	resp := &types.Operation{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/infras/%d/reconcile",
+			projID,
+			infraID,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
@@ -159,5 +159,10 @@ func (k *KubernetesProvisioner) getTFEnv(opts *provisioner.ProvisionOpts) ([]v1.
 		Value: opts.Kind,
 	})
 
+	env = append(env, v1.EnvVar{
+		Name:  "TF_APPLY_SAVED_PLAN",
+		Value: fmt.Sprintf("%t", opts.ApplySavedPlan),
+	})
+
 	return env, nil
 }
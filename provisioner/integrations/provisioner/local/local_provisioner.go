@@ -66,6 +66,7 @@ func (l *LocalProvisioner) getEnv(opts *provisioner.ProvisionOpts) ([]string, er
 	env = append(env, fmt.Sprintf("VAULT_TOKEN=%s", opts.CredentialExchange.VaultToken))
 	env = append(env, fmt.Sprintf("TF_VALUES=%s", base64.StdEncoding.EncodeToString(valBytes)))
 	env = append(env, fmt.Sprintf("TF_KIND=%s", opts.Kind))
+	env = append(env, fmt.Sprintf("TF_APPLY_SAVED_PLAN=%t", opts.ApplySavedPlan))
 
 	return env, nil
 }
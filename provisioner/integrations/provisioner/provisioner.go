@@ -9,6 +9,16 @@ type ProvisionerOperation string
 const (
 	Apply   ProvisionerOperation = "apply"
 	Destroy ProvisionerOperation = "destroy"
+
+	// Plan and PlanDestroy run terraform plan without modifying any resources. The
+	// resulting plan is saved to /{workspace_id}/tfplan, and must be approved before the
+	// corresponding apply or destroy is run.
+	Plan        ProvisionerOperation = "plan"
+	PlanDestroy ProvisionerOperation = "plan-destroy"
+
+	// DriftCheck runs a refresh-only plan, which reports resources that were changed outside
+	// of terraform without modifying them
+	DriftCheck ProvisionerOperation = "drift-check"
 )
 
 type ProvisionCredentialExchange struct {
@@ -26,6 +36,11 @@ type ProvisionOpts struct {
 	OperationKind      ProvisionerOperation
 	Kind               string
 	Values             map[string]interface{}
+
+	// ApplySavedPlan is set for an apply or destroy of an approved plan. The provisioner downloads the
+	// saved plan from /{workspace_id}/tfplan and passes it to terraform apply, instead of planning again,
+	// and terraform refuses to apply the plan if it is stale.
+	ApplySavedPlan bool
 }
 
 type Provisioner interface {
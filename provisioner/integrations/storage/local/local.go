@@ -0,0 +1,129 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/porter-dev/porter/internal/encryption"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/storage"
+)
+
+// LocalStorageClient stores files on the local filesystem, under a root directory
+type LocalStorageClient struct {
+	rootDir       string
+	encryptionKey *[32]byte
+}
+
+type LocalOptions struct {
+	RootDirectory string
+	EncryptionKey *[32]byte
+}
+
+func NewLocalStorageClient(opts *LocalOptions) (*LocalStorageClient, error) {
+	if opts.RootDirectory == "" {
+		return nil, fmt.Errorf("local storage backend requires a root directory")
+	}
+
+	if err := os.MkdirAll(opts.RootDirectory, 0o700); err != nil {
+		return nil, fmt.Errorf("cannot create local storage directory: %v", err)
+	}
+
+	return &LocalStorageClient{
+		rootDir:       opts.RootDirectory,
+		encryptionKey: opts.EncryptionKey,
+	}, nil
+}
+
+func (l *LocalStorageClient) WriteFile(infra *models.Infra, name string, fileBytes []byte, shouldEncrypt bool) error {
+	body := fileBytes
+	var err error
+	if shouldEncrypt {
+		body, err = encryption.Encrypt(fileBytes, l.encryptionKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	path := l.getPath(infra, name)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	// write to a temporary file and rename it, so that readers never see a partially
+	// written state file. Each write has its own temporary file, so concurrent writes
+	// of the same file cannot interleave.
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := tmpFile.Name()
+
+	if err := writeAndSync(tmpFile, body); err != nil {
+		os.Remove(tmpPath) // nolint:errcheck,gosec
+
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath) // nolint:errcheck,gosec
+
+		return err
+	}
+
+	return nil
+}
+
+// writeAndSync writes the body to the file and flushes it to disk before closing it, so
+// that the file is complete once it has been renamed into place
+func writeAndSync(file *os.File, body []byte) error {
+	if _, err := file.Write(body); err != nil {
+		file.Close() // nolint:errcheck,gosec
+
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close() // nolint:errcheck,gosec
+
+		return err
+	}
+
+	return file.Close()
+}
+
+func (l *LocalStorageClient) ReadFile(infra *models.Infra, name string, shouldDecrypt bool) ([]byte, error) {
+	fileBytes, err := os.ReadFile(l.getPath(infra, name))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, storage.FileDoesNotExist
+		}
+
+		return nil, err
+	}
+
+	if shouldDecrypt {
+		return encryption.Decrypt(fileBytes, l.encryptionKey)
+	}
+
+	return fileBytes, nil
+}
+
+func (l *LocalStorageClient) DeleteFile(infra *models.Infra, name string) error {
+	err := os.Remove(l.getPath(infra, name))
+
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (l *LocalStorageClient) getPath(infra *models.Infra, name string) string {
+	return filepath.Join(l.rootDir, filepath.FromSlash(storage.FileKey(infra, filepath.Base(name))))
+}
@@ -0,0 +1,183 @@
+package local
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/storage"
+)
+
+func TestLocalReadWriteFile(t *testing.T) {
+	client, infra := newTestClient(t)
+
+	tests := map[string]bool{
+		"default.tfstate":    true,
+		"operation-logs.txt": false,
+	}
+
+	for name, encrypted := range tests {
+		t.Run(name, func(t *testing.T) {
+			data := []byte(`{"version": 4}`)
+
+			if err := client.WriteFile(infra, name, data, encrypted); err != nil {
+				t.Fatalf("%v\n", err)
+			}
+
+			stored, err := os.ReadFile(client.getPath(infra, name))
+			if err != nil {
+				t.Fatalf("%v\n", err)
+			}
+
+			if encrypted == bytes.Equal(stored, data) {
+				t.Errorf("expected file to be stored encrypted: %t, got contents %q", encrypted, stored)
+			}
+
+			got, err := client.ReadFile(infra, name, encrypted)
+			if err != nil {
+				t.Fatalf("%v\n", err)
+			}
+
+			if !bytes.Equal(got, data) {
+				t.Errorf("incorrect file contents: expected %q, got %q", data, got)
+			}
+
+			// writing the file again overwrites it
+			data = []byte(`{"version": 5}`)
+
+			if err := client.WriteFile(infra, name, data, encrypted); err != nil {
+				t.Fatalf("%v\n", err)
+			}
+
+			got, err = client.ReadFile(infra, name, encrypted)
+			if err != nil {
+				t.Fatalf("%v\n", err)
+			}
+
+			if !bytes.Equal(got, data) {
+				t.Errorf("incorrect file contents after overwrite: expected %q, got %q", data, got)
+			}
+		})
+	}
+}
+
+func TestLocalDeleteFile(t *testing.T) {
+	client, infra := newTestClient(t)
+
+	if err := client.WriteFile(infra, "default.tfstate", []byte("state"), true); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if err := client.DeleteFile(infra, "default.tfstate"); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if _, err := client.ReadFile(infra, "default.tfstate", true); !errors.Is(err, storage.FileDoesNotExist) {
+		t.Errorf("expected FileDoesNotExist after delete, got %v", err)
+	}
+
+	// deleting a file which does not exist is not an error
+	if err := client.DeleteFile(infra, "default.tfstate"); err != nil {
+		t.Errorf("expected no error deleting a missing file, got %v", err)
+	}
+}
+
+func TestLocalReadMissingFile(t *testing.T) {
+	client, infra := newTestClient(t)
+
+	if _, err := client.ReadFile(infra, "current_state.json", true); !errors.Is(err, storage.FileDoesNotExist) {
+		t.Errorf("expected FileDoesNotExist, got %v", err)
+	}
+}
+
+func TestLocalFileStaysUnderRoot(t *testing.T) {
+	client, infra := newTestClient(t)
+
+	if err := client.WriteFile(infra, "../../escaped.txt", []byte("data"), false); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	got, err := client.ReadFile(infra, "escaped.txt", false)
+	if err != nil {
+		t.Fatalf("expected file to be written in the infra's directory: %v", err)
+	}
+
+	if string(got) != "data" {
+		t.Errorf("incorrect file contents: expected %q, got %q", "data", got)
+	}
+}
+
+func TestLocalConcurrentWrites(t *testing.T) {
+	client, infra := newTestClient(t)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			errs <- client.WriteFile(infra, "default.tfstate", []byte(fmt.Sprintf(`{"serial": %d}`, i)), true)
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+
+	// the file holds one of the writes in full
+	got, err := client.ReadFile(infra, "default.tfstate", true)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if !bytes.HasPrefix(got, []byte(`{"serial": `)) || !bytes.HasSuffix(got, []byte("}")) {
+		t.Errorf("incorrect file contents after concurrent writes: %q", got)
+	}
+
+	// no temporary files are left behind
+	entries, err := os.ReadDir(filepath.Dir(client.getPath(infra, "default.tfstate")))
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("expected only the written file in the infra's directory, got %d entries", len(entries))
+	}
+}
+
+func newTestClient(t *testing.T) (*LocalStorageClient, *models.Infra) {
+	t.Helper()
+
+	var key [32]byte
+	copy(key[:], "__random_strong_encryption_key__")
+
+	client, err := NewLocalStorageClient(&LocalOptions{
+		RootDirectory: t.TempDir(),
+		EncryptionKey: &key,
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	infra := &models.Infra{
+		Kind:      "eks",
+		ProjectID: 1,
+		Suffix:    "abcdef",
+	}
+	infra.ID = 1
+
+	return client, infra
+}
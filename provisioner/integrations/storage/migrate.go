@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+// process 100 infras at a time
+const migrateStepSize = 100
+
+// MigrateResult is a summary of a completed migration between storage backends
+type MigrateResult struct {
+	Infras int
+	Files  int
+}
+
+// Migrate copies the state and operation logs for every infra from one storage backend to
+// another. Files which do not exist in the source backend are skipped, and files which already
+// exist in the destination backend are overwritten.
+func Migrate(db *gorm.DB, from, to StorageManager) (*MigrateResult, error) {
+	res := &MigrateResult{}
+
+	var count int64
+
+	if err := db.Model(&models.Infra{}).Count(&count).Error; err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < int(count)/migrateStepSize+1; i++ {
+		infras := []*models.Infra{}
+
+		if err := db.Order("id asc").Offset(i * migrateStepSize).Limit(migrateStepSize).Find(&infras).Error; err != nil {
+			return nil, err
+		}
+
+		for _, infra := range infras {
+			files, err := migrateInfra(db, from, to, infra)
+			if err != nil {
+				return nil, fmt.Errorf("error migrating files for infra %d: %w", infra.ID, err)
+			}
+
+			res.Infras++
+			res.Files += files
+		}
+	}
+
+	return res, nil
+}
+
+func migrateInfra(db *gorm.DB, from, to StorageManager, infra *models.Infra) (int, error) {
+	operations := []*models.Operation{}
+
+	if err := db.Where("infra_id = ?", infra.ID).Find(&operations).Error; err != nil {
+		return 0, err
+	}
+
+	// state files are stored encrypted, while logs are stored in plaintext
+	shouldEncrypt := map[string]bool{
+		ptypes.DefaultCurrentStateFile:   true,
+		ptypes.DefaultTerraformStateFile: true,
+	}
+
+	for _, operation := range operations {
+		shouldEncrypt[fmt.Sprintf("%s-logs.txt", models.GetWorkspaceID(infra, operation))] = false
+	}
+
+	files := 0
+
+	for name, encrypted := range shouldEncrypt {
+		fileBytes, err := from.ReadFile(infra, name, encrypted)
+		if err != nil {
+			if errors.Is(err, FileDoesNotExist) {
+				continue
+			}
+
+			return files, err
+		}
+
+		if err := to.WriteFile(infra, name, fileBytes, encrypted); err != nil {
+			return files, err
+		}
+
+		files++
+	}
+
+	return files, nil
+}
@@ -0,0 +1,154 @@
+package storage_test
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/internal/adapter"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/storage"
+	"github.com/porter-dev/porter/provisioner/integrations/storage/local"
+	"github.com/porter-dev/porter/provisioner/integrations/storage/postgres"
+	"gorm.io/gorm"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+func TestMigrateLocalToPostgres(t *testing.T) {
+	db := newTestDB(t)
+
+	var key [32]byte
+	copy(key[:], "__random_strong_encryption_key__")
+
+	from, err := local.NewLocalStorageClient(&local.LocalOptions{
+		RootDirectory: t.TempDir(),
+		EncryptionKey: &key,
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	to := postgres.NewPostgresStorageClient(&postgres.PostgresOptions{
+		DB:            db,
+		EncryptionKey: &key,
+	})
+
+	// the first infra has state and logs for both of its operations, the second has only a
+	// terraform state file, and the third has no files at all
+	infras := []*models.Infra{
+		{Kind: "eks", ProjectID: 1, Suffix: "aaaaaa"},
+		{Kind: "ecr", ProjectID: 1, Suffix: "bbbbbb"},
+		{Kind: "gke", ProjectID: 2, Suffix: "cccccc"},
+	}
+
+	for _, infra := range infras {
+		if err := db.Create(infra).Error; err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+
+	operations := []*models.Operation{
+		{UID: "operation-1", InfraID: infras[0].ID},
+		{UID: "operation-2", InfraID: infras[0].ID},
+	}
+
+	for _, operation := range operations {
+		if err := db.Create(operation).Error; err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+
+	type testFile struct {
+		infra     *models.Infra
+		name      string
+		data      string
+		encrypted bool
+	}
+
+	files := []testFile{
+		{infras[0], ptypes.DefaultCurrentStateFile, `{"status": "created"}`, true},
+		{infras[0], ptypes.DefaultTerraformStateFile, `{"version": 4}`, true},
+		{infras[0], logsFileName(infras[0], operations[0]), "plan logs", false},
+		{infras[0], logsFileName(infras[0], operations[1]), "apply logs", false},
+		{infras[1], ptypes.DefaultTerraformStateFile, `{"version": 4, "serial": 2}`, true},
+	}
+
+	for _, file := range files {
+		if err := from.WriteFile(file.infra, file.name, []byte(file.data), file.encrypted); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+
+	res, err := storage.Migrate(db, from, to)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if res.Infras != len(infras) {
+		t.Errorf("incorrect number of infras migrated: expected %d, got %d", len(infras), res.Infras)
+	}
+
+	if res.Files != len(files) {
+		t.Errorf("incorrect number of files migrated: expected %d, got %d", len(files), res.Files)
+	}
+
+	for _, file := range files {
+		got, err := to.ReadFile(file.infra, file.name, file.encrypted)
+		if err != nil {
+			t.Errorf("could not read migrated file %s for infra %d: %v", file.name, file.infra.ID, err)
+			continue
+		}
+
+		if string(got) != file.data {
+			t.Errorf("incorrect contents for migrated file %s: expected %q, got %q", file.name, file.data, got)
+		}
+	}
+
+	// files which did not exist in the source backend are not created
+	if _, err := to.ReadFile(infras[1], ptypes.DefaultCurrentStateFile, true); !errors.Is(err, storage.FileDoesNotExist) {
+		t.Errorf("expected FileDoesNotExist for a file missing from the source, got %v", err)
+	}
+
+	// running the migration again overwrites the existing files
+	if err := from.WriteFile(infras[0], ptypes.DefaultTerraformStateFile, []byte(`{"version": 4, "serial": 3}`), true); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if _, err := storage.Migrate(db, from, to); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	got, err := to.ReadFile(infras[0], ptypes.DefaultTerraformStateFile, true)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if string(got) != `{"version": 4, "serial": 3}` {
+		t.Errorf("expected migration to overwrite the existing state, got %q", got)
+	}
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := adapter.New(&env.DBConf{
+		SQLLite:     true,
+		SQLLitePath: filepath.Join(t.TempDir(), "porter_test.db"),
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if err := db.AutoMigrate(&models.Infra{}, &models.Operation{}, &models.ProvisionerFile{}); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	return db
+}
+
+func logsFileName(infra *models.Infra, operation *models.Operation) string {
+	return fmt.Sprintf("%s-logs.txt", models.GetWorkspaceID(infra, operation))
+}
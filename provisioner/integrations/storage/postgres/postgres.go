@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/internal/encryption"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/storage"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresStorageClient stores files in the provisioner_files table of the Porter database
+type PostgresStorageClient struct {
+	db            *gorm.DB
+	encryptionKey *[32]byte
+}
+
+type PostgresOptions struct {
+	DB            *gorm.DB
+	EncryptionKey *[32]byte
+}
+
+func NewPostgresStorageClient(opts *PostgresOptions) *PostgresStorageClient {
+	return &PostgresStorageClient{
+		db:            opts.DB,
+		encryptionKey: opts.EncryptionKey,
+	}
+}
+
+func (p *PostgresStorageClient) WriteFile(infra *models.Infra, name string, fileBytes []byte, shouldEncrypt bool) error {
+	body := fileBytes
+	var err error
+	if shouldEncrypt {
+		body, err = encryption.Encrypt(fileBytes, p.encryptionKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	file := &models.ProvisionerFile{
+		Key:  storage.FileKey(infra, name),
+		Data: body,
+	}
+
+	return p.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"data", "updated_at"}),
+	}).Create(file).Error
+}
+
+func (p *PostgresStorageClient) ReadFile(infra *models.Infra, name string, shouldDecrypt bool) ([]byte, error) {
+	file := &models.ProvisionerFile{}
+
+	if err := p.db.Where("key = ?", storage.FileKey(infra, name)).First(file).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, storage.FileDoesNotExist
+		}
+
+		return nil, err
+	}
+
+	if shouldDecrypt {
+		return encryption.Decrypt(file.Data, p.encryptionKey)
+	}
+
+	return file.Data, nil
+}
+
+func (p *PostgresStorageClient) DeleteFile(infra *models.Infra, name string) error {
+	// files are hard-deleted so that the key can be written again
+	return p.db.Unscoped().Where("key = ?", storage.FileKey(infra, name)).Delete(&models.ProvisionerFile{}).Error
+}
@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/internal/adapter"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/storage"
+)
+
+func TestPostgresReadWriteFile(t *testing.T) {
+	client, infra := newTestClient(t)
+
+	tests := map[string]bool{
+		"default.tfstate":    true,
+		"operation-logs.txt": false,
+	}
+
+	for name, encrypted := range tests {
+		t.Run(name, func(t *testing.T) {
+			data := []byte(`{"version": 4}`)
+
+			if err := client.WriteFile(infra, name, data, encrypted); err != nil {
+				t.Fatalf("%v\n", err)
+			}
+
+			file := &models.ProvisionerFile{}
+			if err := client.db.Where("key = ?", storage.FileKey(infra, name)).First(file).Error; err != nil {
+				t.Fatalf("%v\n", err)
+			}
+
+			if encrypted == bytes.Equal(file.Data, data) {
+				t.Errorf("expected file to be stored encrypted: %t, got contents %q", encrypted, file.Data)
+			}
+
+			got, err := client.ReadFile(infra, name, encrypted)
+			if err != nil {
+				t.Fatalf("%v\n", err)
+			}
+
+			if !bytes.Equal(got, data) {
+				t.Errorf("incorrect file contents: expected %q, got %q", data, got)
+			}
+
+			// writing the file again updates the existing row
+			data = []byte(`{"version": 5}`)
+
+			if err := client.WriteFile(infra, name, data, encrypted); err != nil {
+				t.Fatalf("%v\n", err)
+			}
+
+			got, err = client.ReadFile(infra, name, encrypted)
+			if err != nil {
+				t.Fatalf("%v\n", err)
+			}
+
+			if !bytes.Equal(got, data) {
+				t.Errorf("incorrect file contents after overwrite: expected %q, got %q", data, got)
+			}
+
+			var count int64
+			if err := client.db.Model(&models.ProvisionerFile{}).Where("key = ?", storage.FileKey(infra, name)).Count(&count).Error; err != nil {
+				t.Fatalf("%v\n", err)
+			}
+
+			if count != 1 {
+				t.Errorf("expected 1 row for the file, got %d", count)
+			}
+		})
+	}
+}
+
+func TestPostgresDeleteFile(t *testing.T) {
+	client, infra := newTestClient(t)
+
+	if err := client.WriteFile(infra, "default.tfstate", []byte("state"), true); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if err := client.DeleteFile(infra, "default.tfstate"); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if _, err := client.ReadFile(infra, "default.tfstate", true); !errors.Is(err, storage.FileDoesNotExist) {
+		t.Errorf("expected FileDoesNotExist after delete, got %v", err)
+	}
+
+	// the key can be written again once the file has been deleted
+	if err := client.WriteFile(infra, "default.tfstate", []byte("new state"), true); err != nil {
+		t.Fatalf("expected file to be written again after delete: %v", err)
+	}
+
+	got, err := client.ReadFile(infra, "default.tfstate", true)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if string(got) != "new state" {
+		t.Errorf("incorrect file contents: expected %q, got %q", "new state", got)
+	}
+}
+
+func TestPostgresReadMissingFile(t *testing.T) {
+	client, infra := newTestClient(t)
+
+	if _, err := client.ReadFile(infra, "current_state.json", true); !errors.Is(err, storage.FileDoesNotExist) {
+		t.Errorf("expected FileDoesNotExist, got %v", err)
+	}
+}
+
+func newTestClient(t *testing.T) (*PostgresStorageClient, *models.Infra) {
+	t.Helper()
+
+	db, err := adapter.New(&env.DBConf{
+		SQLLite:     true,
+		SQLLitePath: filepath.Join(t.TempDir(), "porter_test.db"),
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if err := db.AutoMigrate(&models.ProvisionerFile{}); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	var key [32]byte
+	copy(key[:], "__random_strong_encryption_key__")
+
+	infra := &models.Infra{
+		Kind:      "eks",
+		ProjectID: 1,
+		Suffix:    "abcdef",
+	}
+	infra.ID = 1
+
+	return NewPostgresStorageClient(&PostgresOptions{
+		DB:            db,
+		EncryptionKey: &key,
+	}), infra
+}
@@ -70,7 +70,7 @@ func (s *S3StorageClient) WriteFile(infra *models.Infra, name string, fileBytes
 	_, err = s.client.PutObject(&s3.PutObjectInput{
 		Body:   aws.ReadSeekCloser(bytes.NewReader(body)),
 		Bucket: &s.bucket,
-		Key:    aws.String(getKeyFromInfra(infra, name)),
+		Key:    aws.String(storage.FileKey(infra, name)),
 	})
 	return err
 }
@@ -96,7 +96,7 @@ func (s *S3StorageClient) WriteFileWithKey(fileBytes []byte, shouldEncrypt bool,
 func (s *S3StorageClient) ReadFile(infra *models.Infra, name string, shouldDecrypt bool) ([]byte, error) {
 	output, err := s.client.GetObject(&s3.GetObjectInput{
 		Bucket: &s.bucket,
-		Key:    aws.String(getKeyFromInfra(infra, name)),
+		Key:    aws.String(storage.FileKey(infra, name)),
 	})
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); ok {
@@ -130,14 +130,10 @@ func (s *S3StorageClient) ReadFile(infra *models.Infra, name string, shouldDecry
 func (s *S3StorageClient) DeleteFile(infra *models.Infra, name string) error {
 	_, err := s.client.DeleteObject(&s3.DeleteObjectInput{
 		Bucket: &s.bucket,
-		Key:    aws.String(getKeyFromInfra(infra, name)),
+		Key:    aws.String(storage.FileKey(infra, name)),
 	})
 	if err != nil {
 		return err
 	}
 	return nil
 }
-
-func getKeyFromInfra(infra *models.Infra, name string) string {
-	return fmt.Sprintf("%s/%s", infra.GetUniqueName(), name)
-}
@@ -13,3 +13,8 @@ type StorageManager interface {
 	ReadFile(infra *models.Infra, name string, shouldDecrypt bool) ([]byte, error)
 	DeleteFile(infra *models.Infra, name string) error
 }
+
+// FileKey returns the key that a file for an infra is stored under
+func FileKey(infra *models.Infra, name string) string {
+	return fmt.Sprintf("%s/%s", infra.GetUniqueName(), name)
+}
@@ -26,6 +26,8 @@ import (
 	"github.com/porter-dev/porter/provisioner/integrations/provisioner/k8s"
 	"github.com/porter-dev/porter/provisioner/integrations/provisioner/local"
 	"github.com/porter-dev/porter/provisioner/integrations/storage"
+	slocal "github.com/porter-dev/porter/provisioner/integrations/storage/local"
+	"github.com/porter-dev/porter/provisioner/integrations/storage/postgres"
 	"github.com/porter-dev/porter/provisioner/integrations/storage/s3"
 	"golang.org/x/oauth2"
 
@@ -92,10 +94,20 @@ type ProvisionerConf struct {
 
 	StaticAuthToken string `env:"STATIC_AUTH_TOKEN"`
 
+	// ServerURL is the URL of the Porter dashboard, which is linked to from notifications
+	ServerURL string `env:"SERVER_URL,default=http://localhost:8080"`
+
 	SentryDSN string `env:"SENTRY_DSN"`
 	SentryEnv string `env:"SENTRY_ENV,default=dev"`
 
-	// Configuration for the S3 storage backend
+	// StorageBackend is the backend for terraform state and logs: options are "s3", "local" or "postgres"
+	StorageBackend string `env:"STORAGE_BACKEND,default=s3"`
+
+	// LocalStorageDirectory is the root directory for the "local" storage backend
+	LocalStorageDirectory string `env:"LOCAL_STORAGE_DIRECTORY"`
+
+	// Configuration for the S3 storage backend. The S3 encryption key is used to encrypt
+	// state files for every storage backend.
 	S3AWSAccessKeyID string `env:"S3_AWS_ACCESS_KEY_ID"`
 	S3AWSSecretKey   string `env:"S3_AWS_SECRET_KEY"`
 	S3AWSRegion      string `env:"S3_AWS_REGION"`
@@ -120,6 +132,9 @@ type ProvisionerConf struct {
 	ProvisionerImagePullSecret string `env:"PROV_IMAGE_PULL_SECRET"`
 	ProvisionerJobNamespace    string `env:"PROV_JOB_NAMESPACE,default=default"`
 
+	// DriftCheckInterval is how often each infra is checked for drift: set to 0 to disable drift checks
+	DriftCheckInterval time.Duration `env:"DRIFT_CHECK_INTERVAL,default=24h"`
+
 	// Options to configure for the "local" provisioner method
 	LocalTerraformDirectory string `env:"LOCAL_TERRAFORM_DIRECTORY"`
 
@@ -185,27 +200,9 @@ func GetConfig(envConf *EnvConf) (*Config, error) {
 		res.Alerter, err = alerter.NewSentryAlerter(envConf.ProvisionerConf.SentryDSN, envConf.ProvisionerConf.SentryEnv)
 	}
 
-	// load a storage backend; if correct env vars are not set, throw an error
-	if envConf.ProvisionerConf.S3AWSAccessKeyID != "" && envConf.ProvisionerConf.S3AWSSecretKey != "" && envConf.ProvisionerConf.S3EncryptionKey != "" {
-		var s3Key [32]byte
-
-		for i, b := range []byte(envConf.ProvisionerConf.S3EncryptionKey) {
-			s3Key[i] = b
-		}
-
-		res.StorageManager, err = s3.NewS3StorageClient(&s3.S3Options{
-			AWSRegion:      envConf.ProvisionerConf.S3AWSRegion,
-			AWSAccessKeyID: envConf.ProvisionerConf.S3AWSAccessKeyID,
-			AWSSecretKey:   envConf.ProvisionerConf.S3AWSSecretKey,
-			AWSBucketName:  envConf.ProvisionerConf.S3BucketName,
-			EncryptionKey:  &s3Key,
-		})
-
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		return nil, fmt.Errorf("no storage backend is available")
+	res.StorageManager, err = NewStorageManager(envConf.ProvisionerConf.StorageBackend, envConf, db)
+	if err != nil {
+		return nil, err
 	}
 
 	if envConf.RedisConf.Enabled {
@@ -253,6 +250,47 @@ func GetConfig(envConf *EnvConf) (*Config, error) {
 	return res, nil
 }
 
+// NewStorageManager loads a storage backend; if the correct env vars for the backend are not set,
+// it returns an error
+func NewStorageManager(backend string, envConf *EnvConf, db *_gorm.DB) (storage.StorageManager, error) {
+	if envConf.ProvisionerConf.S3EncryptionKey == "" {
+		return nil, fmt.Errorf("no storage encryption key is set")
+	}
+
+	var key [32]byte
+
+	for i, b := range []byte(envConf.ProvisionerConf.S3EncryptionKey) {
+		key[i] = b
+	}
+
+	switch backend {
+	case "s3":
+		if envConf.ProvisionerConf.S3AWSAccessKeyID == "" || envConf.ProvisionerConf.S3AWSSecretKey == "" {
+			return nil, fmt.Errorf("no storage backend is available")
+		}
+
+		return s3.NewS3StorageClient(&s3.S3Options{
+			AWSRegion:      envConf.ProvisionerConf.S3AWSRegion,
+			AWSAccessKeyID: envConf.ProvisionerConf.S3AWSAccessKeyID,
+			AWSSecretKey:   envConf.ProvisionerConf.S3AWSSecretKey,
+			AWSBucketName:  envConf.ProvisionerConf.S3BucketName,
+			EncryptionKey:  &key,
+		})
+	case "local":
+		return slocal.NewLocalStorageClient(&slocal.LocalOptions{
+			RootDirectory: envConf.ProvisionerConf.LocalStorageDirectory,
+			EncryptionKey: &key,
+		})
+	case "postgres":
+		return postgres.NewPostgresStorageClient(&postgres.PostgresOptions{
+			DB:            db,
+			EncryptionKey: &key,
+		}), nil
+	}
+
+	return nil, fmt.Errorf("unknown storage backend %s", backend)
+}
+
 func getProvisionerAgent(ctx context.Context, conf *ProvisionerConf) (*kubernetes.Agent, error) {
 	if conf.ProvisionerCluster == "kubeconfig" && conf.SelfKubeconfig != "" {
 		agent, err := klocal.GetSelfAgentFromFileConfig(conf.SelfKubeconfig)
@@ -0,0 +1,105 @@
+package drift
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/server/config"
+	"github.com/porter-dev/porter/provisioner/server/handlers/provision"
+)
+
+// how often the scheduler looks for infras that are due for a drift check
+const schedulerTick = 10 * time.Minute
+
+// process 100 infras at a time
+const stepSize = 100
+
+// StartScheduler runs a drift check for each created infra once per drift check interval,
+// until the context is cancelled. When more than one provisioner is running, a redis lock
+// ensures that each infra is only checked by a single provisioner, so drift checks are
+// disabled when redis is not configured.
+func StartScheduler(ctx context.Context, conf *config.Config) {
+	interval := conf.ProvisionerConf.DriftCheckInterval
+
+	if interval <= 0 {
+		return
+	}
+
+	if conf.RedisClient == nil {
+		conf.Logger.Info().Msg("redis is not enabled, so drift checks are disabled")
+		return
+	}
+
+	ticker := time.NewTicker(schedulerTick)
+	defer ticker.Stop()
+
+	for {
+		if err := scheduleDriftChecks(ctx, conf, interval); err != nil {
+			conf.Logger.Error().Err(err).Msg("error scheduling drift checks")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func scheduleDriftChecks(ctx context.Context, conf *config.Config, interval time.Duration) error {
+	var count int64
+
+	if err := conf.DB.Model(&models.Infra{}).Where("status = ?", types.StatusCreated).Count(&count).Error; err != nil {
+		return err
+	}
+
+	for i := 0; i < int(count)/stepSize+1; i++ {
+		infras := []*models.Infra{}
+
+		err := conf.DB.Where("status = ?", types.StatusCreated).
+			Order("id asc").Offset(i * stepSize).Limit(stepSize).Find(&infras).Error
+		if err != nil {
+			return err
+		}
+
+		for _, infra := range infras {
+			if infra.DriftCheckedAt != nil && time.Since(*infra.DriftCheckedAt) < interval {
+				continue
+			}
+
+			acquired, err := conf.RedisClient.SetNX(ctx, getLockKey(infra), "1", interval).Result()
+			if err != nil {
+				return err
+			}
+
+			if !acquired {
+				continue
+			}
+
+			// the repository decrypts the fields of the infra which are needed to provision
+			fullInfra, err := conf.Repo.Infra().ReadInfra(infra.ProjectID, infra.ID)
+			if err != nil {
+				conf.Logger.Error().Err(err).Msgf("could not read infra %d for drift check", infra.ID)
+				continue
+			}
+
+			if _, err := provision.RunDriftCheck(conf, fullInfra); err != nil {
+				// release the lock so that the infra is checked on the next tick
+				conf.RedisClient.Del(ctx, getLockKey(infra))
+
+				if err != provision.ErrDriftCheckNotAllowed {
+					conf.Logger.Error().Err(err).Msgf("could not start drift check for infra %d", infra.ID)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func getLockKey(infra *models.Infra) string {
+	return fmt.Sprintf("drift-check-%s", infra.GetUniqueName())
+}
@@ -0,0 +1,203 @@
+package drift
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/adapter"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/provisioner"
+	"github.com/porter-dev/porter/provisioner/server/config"
+	"github.com/porter-dev/porter/provisioner/server/ptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleDriftChecks(t *testing.T) {
+	conf, prov, redis := ptest.LoadConfig(t)
+	loadTestDB(t, conf)
+
+	interval := 24 * time.Hour
+	checkedRecently := time.Now().Add(-time.Hour)
+	checkedBeforeInterval := time.Now().Add(-25 * time.Hour)
+
+	neverChecked := createTestInfra(t, conf, types.StatusCreated, nil, types.OperationStatusCompleted)
+	createTestInfra(t, conf, types.StatusCreated, &checkedRecently, types.OperationStatusCompleted)
+	due := createTestInfra(t, conf, types.StatusCreated, &checkedBeforeInterval, types.OperationStatusCompleted)
+	createTestInfra(t, conf, "errored", nil, "errored")
+	createTestInfra(t, conf, "updating", nil, types.OperationStatusStarting)
+
+	// another provisioner has already started a drift check for this infra
+	locked := createTestInfra(t, conf, types.StatusCreated, nil, types.OperationStatusCompleted)
+	redis.Set(getLockKey(locked), "1")
+
+	// a drift check cannot supersede a plan which is awaiting approval
+	awaitingApproval := createTestInfra(t, conf, types.StatusCreated, nil, types.OperationStatusAwaitingApproval)
+
+	if err := scheduleDriftChecks(context.Background(), conf, interval); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []uint{neverChecked.ID, due.ID}, checkedInfraIDs(prov), "only created infras which are due should be checked")
+
+	for _, kind := range prov.OperationKinds() {
+		assert.Equal(t, provisioner.DriftCheck, kind)
+	}
+
+	assert.True(t, redis.Exists(getLockKey(neverChecked)), "the lock should be held until the next check is due")
+	assert.True(t, redis.Exists(getLockKey(due)), "the lock should be held until the next check is due")
+	assert.False(t, redis.Exists(getLockKey(awaitingApproval)), "the lock should be released when the check could not start")
+
+	// the checks which were started have not completed yet, so the next tick must not start them again
+	if err := scheduleDriftChecks(context.Background(), conf, interval); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []uint{neverChecked.ID, due.ID}, checkedInfraIDs(prov), "infras should be checked once per interval")
+}
+
+func TestScheduleDriftChecksPagesInfras(t *testing.T) {
+	conf, prov, _ := ptest.LoadConfig(t)
+	loadTestDB(t, conf)
+
+	numInfras := stepSize + stepSize/2
+
+	for i := 0; i < numInfras; i++ {
+		createTestInfra(t, conf, types.StatusCreated, nil, types.OperationStatusCompleted)
+	}
+
+	if err := scheduleDriftChecks(context.Background(), conf, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, checkedInfraIDs(prov), numInfras, "every infra should be checked, across pages")
+}
+
+func TestStartSchedulerDisabled(t *testing.T) {
+	conf, prov, _ := ptest.LoadConfig(t)
+	conf.ProvisionerConf.DriftCheckInterval = 0
+
+	done := make(chan struct{})
+
+	go func() {
+		StartScheduler(context.Background(), conf)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the scheduler to return immediately when drift checks are disabled")
+	}
+
+	assert.Empty(t, prov.OperationKinds())
+}
+
+func TestStartSchedulerWithoutRedis(t *testing.T) {
+	conf, prov, _ := ptest.LoadConfig(t)
+	loadTestDB(t, conf)
+	conf.ProvisionerConf.DriftCheckInterval = time.Hour
+	conf.RedisClient = nil
+
+	createTestInfra(t, conf, types.StatusCreated, nil, types.OperationStatusCompleted)
+
+	done := make(chan struct{})
+
+	go func() {
+		StartScheduler(context.Background(), conf)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the scheduler to return immediately when redis is not enabled")
+	}
+
+	assert.Empty(t, prov.OperationKinds())
+}
+
+func TestStartSchedulerStopsOnCancel(t *testing.T) {
+	conf, prov, _ := ptest.LoadConfig(t)
+	loadTestDB(t, conf)
+	conf.ProvisionerConf.DriftCheckInterval = time.Hour
+
+	infra := createTestInfra(t, conf, types.StatusCreated, nil, types.OperationStatusCompleted)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		StartScheduler(ctx, conf)
+		close(done)
+	}()
+
+	// the scheduler checks for due infras as soon as it starts
+	assert.Eventually(t, func() bool {
+		return len(prov.OperationKinds()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the scheduler to stop when the context is cancelled")
+	}
+
+	assert.Equal(t, []uint{infra.ID}, checkedInfraIDs(prov))
+}
+
+// loadTestDB sets the database that the scheduler queries for due infras. Infras are created in
+// both the database and the test repository, with the same IDs.
+func loadTestDB(t *testing.T, conf *config.Config) {
+	t.Helper()
+
+	db, err := adapter.New(&env.DBConf{
+		SQLLite:     true,
+		SQLLitePath: filepath.Join(t.TempDir(), "porter_test.db"),
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if err := db.AutoMigrate(&models.Infra{}); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	conf.DB = db
+}
+
+func createTestInfra(
+	t *testing.T,
+	conf *config.Config,
+	status types.InfraStatus,
+	driftCheckedAt *time.Time,
+	lastOpStatus string,
+) *models.Infra {
+	t.Helper()
+
+	infra := ptest.CreateTestInfra(t, conf, status)
+	infra.DriftCheckedAt = driftCheckedAt
+
+	if err := conf.DB.Create(infra).Error; err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	ptest.AddTestOperation(t, conf, infra, "update", lastOpStatus)
+
+	return infra
+}
+
+func checkedInfraIDs(prov *ptest.FakeProvisioner) []uint {
+	ids := []uint{}
+
+	for _, call := range prov.Calls() {
+		ids = append(ids, call.Infra.ID)
+	}
+
+	return ids
+}
@@ -38,6 +38,15 @@ func (s *ProvisionerServer) GetStateUpdate(infra *pb.Infra, server pb.Provisione
 			res.Error = *update.Error
 		}
 
+		if update.Changes != nil {
+			res.Changes = &pb.TerraformChanges{
+				Add:       int64(update.Changes.Add),
+				Change:    int64(update.Changes.Change),
+				Remove:    int64(update.Changes.Remove),
+				Operation: update.Changes.Operation,
+			}
+		}
+
 		return server.Send(res)
 	}
 
@@ -0,0 +1,138 @@
+package grpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/notifier"
+	"github.com/porter-dev/porter/internal/notifier/slack"
+	"github.com/porter-dev/porter/provisioner/integrations/redis_stream"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+// storeDrift records the resources which drifted during a refresh-only plan on the infra, and
+// sends a notification if drift was found that had not already been reported
+func (s *ProvisionerServer) storeDrift(
+	infra *models.Infra,
+	operation *models.Operation,
+	changes ptypes.Changes,
+	driftedChanges []ptypes.Change,
+) (*models.Operation, error) {
+	prevDrift := infra.GetDrift()
+
+	drifted := make([]types.TerraformDriftedResource, 0, len(driftedChanges))
+	plan := &types.TerraformPlan{
+		Add:       changes.Add,
+		Change:    changes.Change,
+		Remove:    changes.Remove,
+		Resources: make([]types.TerraformPlannedChange, 0, len(driftedChanges)),
+	}
+
+	for _, change := range driftedChanges {
+		drifted = append(drifted, types.TerraformDriftedResource{
+			Address:      change.Resource.Addr,
+			ResourceType: change.Resource.ResourceType,
+			ResourceName: change.Resource.ResourceName,
+			Provider:     change.Resource.Provider,
+			Action:       change.Action,
+		})
+
+		plan.Resources = append(plan.Resources, types.TerraformPlannedChange{
+			Address: change.Resource.Addr,
+			Action:  change.Action,
+		})
+	}
+
+	driftBytes, err := json.Marshal(drifted)
+	if err != nil {
+		return nil, err
+	}
+
+	planBytes, err := json.Marshal(plan)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	infra.DriftedResources = driftBytes
+	infra.DriftCheckedAt = &now
+
+	infra, err = s.config.Repo.Infra().UpdateInfra(infra)
+	if err != nil {
+		return nil, err
+	}
+
+	operation.Plan = planBytes
+	operation.Status = types.OperationStatusCompleted
+
+	operation, err = s.config.Repo.Infra().UpdateOperation(operation)
+	if err != nil {
+		return nil, err
+	}
+
+	err = redis_stream.PushToOperationStream(s.config.RedisClient, infra, operation, &ptypes.TFResourceState{
+		Status:  ptypes.TFOperationChangeSummary,
+		Changes: &changes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := redis_stream.SendOperationCompleted(s.config.RedisClient, infra, operation); err != nil {
+		return nil, err
+	}
+
+	if drift := infra.GetDrift(); drift != nil && drift.Drifted && !sameDrift(prevDrift, drift) {
+		if err := s.notifyDrift(infra, drift); err != nil {
+			// a failed notification should not fail the drift check
+			s.config.Logger.Error().Err(err).Msgf("could not send drift notification for infra %d", infra.ID)
+		}
+	}
+
+	return operation, nil
+}
+
+func (s *ProvisionerServer) notifyDrift(infra *models.Infra, drift *types.InfraDrift) error {
+	slackInts, err := s.config.Repo.SlackIntegration().ListSlackIntegrationsByProjectID(infra.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	multi := notifier.NewMultiInfraDriftNotifier(slack.NewInfraDriftNotifier(slackInts...))
+
+	url := fmt.Sprintf(
+		"%s/infrastructure/%d?project_id=%d",
+		s.config.ProvisionerConf.ServerURL,
+		infra.ID,
+		infra.ProjectID,
+	)
+
+	return multi.NotifyDrift(infra.ToInfraType(), drift, url)
+}
+
+// sameDrift returns true if the same resources drifted in both drift checks, so that
+// drift is only reported once
+func sameDrift(prev, curr *types.InfraDrift) bool {
+	if prev == nil || len(prev.Resources) != len(curr.Resources) {
+		return false
+	}
+
+	prevAddrs := make(map[string]string, len(prev.Resources))
+
+	for _, resource := range prev.Resources {
+		prevAddrs[resource.Address] = resource.Action
+	}
+
+	for _, resource := range curr.Resources {
+		if action, ok := prevAddrs[resource.Address]; !ok || action != resource.Action {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,203 @@
+package grpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/provisioner/server/ptest"
+	"github.com/stretchr/testify/assert"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+func TestStoreDrift(t *testing.T) {
+	conf, _, redis := ptest.LoadConfig(t)
+	server := NewProvisionerServer(conf)
+	infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+	operation := ptest.AddTestOperation(t, conf, infra, types.OperationTypeDriftCheck, types.OperationStatusPlanning)
+
+	operation, err := server.storeDrift(infra, operation, ptypes.Changes{
+		Change:    1,
+		Remove:    1,
+		Operation: "plan",
+	}, []ptypes.Change{
+		{
+			Resource: ptypes.Resource{
+				Addr:         "aws_eks_cluster.cluster",
+				ResourceType: "aws_eks_cluster",
+				ResourceName: "cluster",
+				Provider:     "registry.terraform.io/hashicorp/aws",
+			},
+			Action: "update",
+		},
+		{
+			Resource: ptypes.Resource{
+				Addr:         "aws_security_group.nodes",
+				ResourceType: "aws_security_group",
+				ResourceName: "nodes",
+				Provider:     "registry.terraform.io/hashicorp/aws",
+			},
+			Action: "delete",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	drift := infra.GetDrift()
+	if drift == nil {
+		t.Fatal("expected drift to be stored on the infra")
+	}
+
+	assert.True(t, drift.Drifted)
+	assert.WithinDuration(t, time.Now(), drift.CheckedAt, time.Minute)
+	assert.Equal(t, []types.TerraformDriftedResource{
+		{
+			Address:      "aws_eks_cluster.cluster",
+			ResourceType: "aws_eks_cluster",
+			ResourceName: "cluster",
+			Provider:     "registry.terraform.io/hashicorp/aws",
+			Action:       "update",
+		},
+		{
+			Address:      "aws_security_group.nodes",
+			ResourceType: "aws_security_group",
+			ResourceName: "nodes",
+			Provider:     "registry.terraform.io/hashicorp/aws",
+			Action:       "delete",
+		},
+	}, drift.Resources)
+
+	assert.Equal(t, types.OperationStatusCompleted, operation.Status)
+	assert.NotEmpty(t, operation.Plan)
+	assert.Equal(t, types.StatusCreated, infra.Status, "drift should not change the status of the infra")
+
+	assert.Contains(t, streamStatuses(t, redis, infra, operation), ptypes.TFOperationChangeSummary)
+}
+
+func TestStoreNoDrift(t *testing.T) {
+	conf, _, _ := ptest.LoadConfig(t)
+	server := NewProvisionerServer(conf)
+	infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+
+	// drift from an earlier check is cleared once a check finds no drift
+	setDriftedResources(t, server, infra.ID, []ptypes.Change{
+		{Resource: ptypes.Resource{Addr: "aws_eks_cluster.cluster"}, Action: "update"},
+	})
+
+	operation := ptest.AddTestOperation(t, conf, infra, types.OperationTypeDriftCheck, types.OperationStatusPlanning)
+
+	operation, err := server.storeDrift(infra, operation, ptypes.Changes{Operation: "plan"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	drift := infra.GetDrift()
+	if drift == nil {
+		t.Fatal("expected the drift check to be recorded on the infra")
+	}
+
+	assert.False(t, drift.Drifted)
+	assert.Empty(t, drift.Resources)
+	assert.Equal(t, types.OperationStatusCompleted, operation.Status)
+}
+
+func TestStoreDriftNotifiesOnce(t *testing.T) {
+	var notifications int32
+
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&notifications, 1)
+	}))
+	defer webhook.Close()
+
+	conf, _, _ := ptest.LoadConfig(t)
+	server := NewProvisionerServer(conf)
+	infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+
+	slackInt := &ints.SlackIntegration{Webhook: []byte(webhook.URL)}
+	slackInt.ProjectID = infra.ProjectID
+
+	if _, err := conf.Repo.SlackIntegration().CreateSlackIntegration(slackInt); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := []ptypes.Change{
+		{Resource: ptypes.Resource{Addr: "aws_eks_cluster.cluster"}, Action: "update"},
+	}
+
+	setDriftedResources(t, server, infra.ID, changes)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&notifications), "new drift should be reported")
+
+	setDriftedResources(t, server, infra.ID, changes)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&notifications), "the same drift should only be reported once")
+
+	setDriftedResources(t, server, infra.ID, append(changes, ptypes.Change{
+		Resource: ptypes.Resource{Addr: "aws_security_group.nodes"},
+		Action:   "delete",
+	}))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&notifications), "additional drift should be reported")
+}
+
+func TestSameDrift(t *testing.T) {
+	update := types.TerraformDriftedResource{Address: "aws_eks_cluster.cluster", Action: "update"}
+	remove := types.TerraformDriftedResource{Address: "aws_security_group.nodes", Action: "delete"}
+
+	tests := map[string]struct {
+		prev     *types.InfraDrift
+		curr     *types.InfraDrift
+		expected bool
+	}{
+		"never checked": {
+			prev:     nil,
+			curr:     &types.InfraDrift{Resources: []types.TerraformDriftedResource{update}},
+			expected: false,
+		},
+		"same resources in a different order": {
+			prev:     &types.InfraDrift{Resources: []types.TerraformDriftedResource{update, remove}},
+			curr:     &types.InfraDrift{Resources: []types.TerraformDriftedResource{remove, update}},
+			expected: true,
+		},
+		"new resource drifted": {
+			prev:     &types.InfraDrift{Resources: []types.TerraformDriftedResource{update}},
+			curr:     &types.InfraDrift{Resources: []types.TerraformDriftedResource{update, remove}},
+			expected: false,
+		},
+		"same resource, different action": {
+			prev: &types.InfraDrift{Resources: []types.TerraformDriftedResource{update}},
+			curr: &types.InfraDrift{Resources: []types.TerraformDriftedResource{
+				{Address: "aws_eks_cluster.cluster", Action: "delete"},
+			}},
+			expected: false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, sameDrift(tc.prev, tc.curr))
+		})
+	}
+}
+
+func setDriftedResources(t *testing.T, server *ProvisionerServer, infraID uint, changes []ptypes.Change) {
+	t.Helper()
+
+	infra, err := server.config.Repo.Infra().ReadInfra(1, infraID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	operation := ptest.AddTestOperation(t, server.config, infra, types.OperationTypeDriftCheck, types.OperationStatusPlanning)
+
+	if _, err := server.storeDrift(infra, operation, ptypes.Changes{Change: len(changes), Operation: "plan"}, changes); err != nil {
+		t.Fatal(err)
+	}
+
+	if drift := infra.GetDrift(); drift == nil || !drift.Drifted {
+		t.Fatal("expected drift to be stored on the infra")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"strings"
 
+	apitypes "github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/provisioner/integrations/redis_stream"
 	"github.com/porter-dev/porter/provisioner/pb"
 	"github.com/porter-dev/porter/provisioner/types"
@@ -27,6 +28,11 @@ func (s *ProvisionerServer) StoreLog(stream pb.Provisioner_StoreLogServer) error
 		return err
 	}
 
+	// planned changes are collected so that they can be stored with the plan once the
+	// change summary is received
+	plannedChanges := make([]types.Change, 0)
+	driftedChanges := make([]types.Change, 0)
+
 	for {
 		tfLog, err := stream.Recv()
 
@@ -65,6 +71,20 @@ func (s *ProvisionerServer) StoreLog(stream pb.Provisioner_StoreLogServer) error
 			} else if logType.Change.Action == "update" {
 				stateUpdate.Status = types.TFResourcePlannedUpdate
 			}
+
+			plannedChanges = append(plannedChanges, logType.Change)
+		case types.ResourceDrift:
+			driftedChanges = append(driftedChanges, logType.Change)
+		case types.ChangeSummary:
+			if operation.Type == apitypes.OperationTypeDriftCheck {
+				operation, err = s.storeDrift(infra, operation, logType.Changes, driftedChanges)
+			} else {
+				operation, err = s.storeChangeSummary(infra, operation, logType.Changes, plannedChanges)
+			}
+
+			if err != nil {
+				return err
+			}
 		case types.Diagnostic:
 			stateUpdate.ID = logType.Diagnostic.Address
 			stateUpdate.Status = types.TFResourceErrored
@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"encoding/json"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/redis_stream"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+// storeChangeSummary pushes the change summary for a plan, apply or destroy to the state stream. If
+// the summary is for a plan, the plan is stored on the operation, which then waits for approval.
+func (s *ProvisionerServer) storeChangeSummary(
+	infra *models.Infra,
+	operation *models.Operation,
+	changes ptypes.Changes,
+	plannedChanges []ptypes.Change,
+) (*models.Operation, error) {
+	stateUpdate := &ptypes.TFResourceState{
+		Status:  ptypes.TFOperationChangeSummary,
+		Changes: &changes,
+	}
+
+	if changes.Operation == "plan" {
+		plan := &types.TerraformPlan{
+			Add:       changes.Add,
+			Change:    changes.Change,
+			Remove:    changes.Remove,
+			Resources: make([]types.TerraformPlannedChange, 0, len(plannedChanges)),
+		}
+
+		for _, change := range plannedChanges {
+			plan.Resources = append(plan.Resources, types.TerraformPlannedChange{
+				Address: change.Resource.Addr,
+				Action:  change.Action,
+			})
+		}
+
+		planBytes, err := json.Marshal(plan)
+		if err != nil {
+			return nil, err
+		}
+
+		operation.Plan = planBytes
+		operation.Status = types.OperationStatusAwaitingApproval
+
+		operation, err = s.config.Repo.Infra().UpdateOperation(operation)
+		if err != nil {
+			return nil, err
+		}
+
+		stateUpdate.Status = ptypes.TFOperationPlanned
+	}
+
+	err := redis_stream.PushToOperationStream(s.config.RedisClient, infra, operation, stateUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	return operation, nil
+}
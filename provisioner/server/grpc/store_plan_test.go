@@ -0,0 +1,128 @@
+package grpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/server/ptest"
+	"github.com/stretchr/testify/assert"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+func TestStoreChangeSummaryPlan(t *testing.T) {
+	conf, _, redis := ptest.LoadConfig(t)
+	server := NewProvisionerServer(conf)
+	infra, operation := createTestOperation(t, server, types.OperationStatusPlanning)
+
+	operation, err := server.storeChangeSummary(infra, operation, ptypes.Changes{
+		Add:       1,
+		Change:    1,
+		Operation: "plan",
+	}, []ptypes.Change{
+		{Resource: ptypes.Resource{Addr: "aws_eks_cluster.cluster"}, Action: "update"},
+		{Resource: ptypes.Resource{Addr: "aws_iam_role.node"}, Action: "create"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := conf.Repo.Infra().ReadOperation(infra.ID, operation.UID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, types.OperationStatusAwaitingApproval, stored.Status, "a planned operation should wait for approval")
+
+	plan := &types.TerraformPlan{}
+	if err := json.Unmarshal(stored.Plan, plan); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, &types.TerraformPlan{
+		Add:    1,
+		Change: 1,
+		Resources: []types.TerraformPlannedChange{
+			{Address: "aws_eks_cluster.cluster", Action: "update"},
+			{Address: "aws_iam_role.node", Action: "create"},
+		},
+	}, plan)
+
+	assert.Equal(t, []ptypes.TFResourceStatus{ptypes.TFOperationPlanned}, streamStatuses(t, redis, infra, operation))
+}
+
+func TestStoreChangeSummaryApply(t *testing.T) {
+	for _, op := range []string{"apply", "destroy"} {
+		t.Run(op, func(t *testing.T) {
+			conf, _, redis := ptest.LoadConfig(t)
+			server := NewProvisionerServer(conf)
+			infra, operation := createTestOperation(t, server, types.OperationStatusStarting)
+
+			_, err := server.storeChangeSummary(infra, operation, ptypes.Changes{
+				Add:       1,
+				Operation: op,
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			stored, err := conf.Repo.Infra().ReadOperation(infra.ID, operation.UID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, types.OperationStatusStarting, stored.Status, "only a plan should change the status of the operation")
+			assert.Empty(t, stored.Plan)
+			assert.Equal(t, []ptypes.TFResourceStatus{ptypes.TFOperationChangeSummary}, streamStatuses(t, redis, infra, operation))
+		})
+	}
+}
+
+func createTestOperation(t *testing.T, server *ProvisionerServer, status string) (*models.Infra, *models.Operation) {
+	infra, err := server.config.Repo.Infra().CreateInfra(&models.Infra{
+		Kind:      types.InfraEKS,
+		ProjectID: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := models.GetOperationID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	operation, err := server.config.Repo.Infra().AddOperation(infra, &models.Operation{
+		UID:             uid,
+		InfraID:         infra.ID,
+		Type:            "update",
+		Status:          status,
+		TemplateVersion: "v0.1.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return infra, operation
+}
+
+// streamStatuses returns the status of every update pushed to the state stream of the operation
+func streamStatuses(t *testing.T, redis *ptest.FakeRedis, infra *models.Infra, operation *models.Operation) []ptypes.TFResourceStatus {
+	var statuses []ptypes.TFResourceStatus
+
+	stream := fmt.Sprintf("%s-state", models.GetWorkspaceID(infra, operation))
+
+	for _, entry := range redis.StreamEntries(stream) {
+		update := &ptypes.TFResourceStateEntry{}
+		if err := json.Unmarshal([]byte(entry["data"]), update); err != nil {
+			t.Fatal(err)
+		}
+
+		statuses = append(statuses, update.Status)
+	}
+
+	return statuses
+}
@@ -2,6 +2,7 @@ package provision
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -9,7 +10,6 @@ import (
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/types"
-	"github.com/porter-dev/porter/internal/analytics"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/random"
 	"github.com/porter-dev/porter/provisioner/integrations/provisioner"
@@ -65,7 +65,7 @@ func (c *ProvisionApplyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		UID:             operationUID,
 		InfraID:         infra.ID,
 		Type:            req.OperationKind,
-		Status:          "starting",
+		Status:          types.OperationStatusPlanning,
 		LastApplied:     valuesJSON,
 		TemplateVersion: "v0.1.0",
 	}
@@ -77,12 +77,6 @@ func (c *ProvisionApplyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	ceToken, rawToken, err := createCredentialsExchangeToken(c.Config, infra)
-	if err != nil {
-		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
-		return
-	}
-
 	// push a first message to the operation stream
 	err = redis_stream.PushToOperationStream(c.Config.RedisClient, infra, operation, &ptypes.TFResourceState{
 		Status: "OPERATION_STARTED",
@@ -93,37 +87,8 @@ func (c *ProvisionApplyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// spawn a new provisioning process
-	err = c.Config.Provisioner.Provision(&provisioner.ProvisionOpts{
-		Infra:         infra,
-		Operation:     operation,
-		OperationKind: provisioner.Apply,
-		Kind:          req.Kind,
-		Values:        req.Values,
-		CredentialExchange: &provisioner.ProvisionCredentialExchange{
-			CredExchangeEndpoint: fmt.Sprintf(
-				"%s/api/v1/%s/credentials",
-				c.Config.ProvisionerConf.ProvisionerCredExchangeURL,
-				models.GetWorkspaceID(infra, operation),
-			),
-			CredExchangeToken: rawToken,
-			CredExchangeID:    ceToken.ID,
-		},
-	})
-
-	if err != nil {
-		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
-		return
-	}
-
-	// update the infrastructure as either "updating" or "creating"
-	if req.OperationKind == "create" || req.OperationKind == "retry_create" {
-		infra.Status = types.InfraStatus("creating")
-	} else if req.OperationKind == "update" {
-		infra.Status = types.InfraStatus("updating")
-	}
-
-	infra, err = c.Config.Repo.Infra().UpdateInfra(infra)
+	// spawn a new plan process: the apply is run once the plan has been approved
+	err = provision(c.Config, infra, operation, provisioner.Plan, req.Values)
 
 	if err != nil {
 		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
@@ -138,26 +103,61 @@ func (c *ProvisionApplyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 
 	// return the operation response type to the server
 	c.resultWriter.WriteResult(w, r, op)
+}
+
+// errOperationNotApproved is returned when an apply or destroy is requested for an operation whose plan
+// has not been approved
+var errOperationNotApproved = errors.New("the plan for this operation must be approved before it is applied")
+
+// getLastAppliedValues returns the values that the operation was created with
+func getLastAppliedValues(operation *models.Operation) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	if err := json.Unmarshal(operation.LastApplied, &values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// provision spawns a new provisioning process for the operation
+func provision(
+	conf *config.Config,
+	infra *models.Infra,
+	operation *models.Operation,
+	operationKind provisioner.ProvisionerOperation,
+	values map[string]interface{},
+) error {
+	isApplyOrDestroy := operationKind == provisioner.Apply || operationKind == provisioner.Destroy
+
+	// reconciles re-apply values which were already approved, so they are the only applies without a plan
+	if isApplyOrDestroy && operation.ApprovedAt == nil && operation.Type != types.OperationTypeReconcile {
+		return errOperationNotApproved
+	}
 
-	// if this is a cluster or registry infra type, send to analytics client
-	switch infra.Kind {
-	case types.InfraDOKS, types.InfraEKS, types.InfraGKE, types.InfraAKS:
-		c.Config.AnalyticsClient.Track(analytics.ClusterProvisioningStartTrack(
-			&analytics.ClusterProvisioningStartTrackOpts{
-				ProjectScopedTrackOpts: analytics.GetProjectScopedTrackOpts(0, infra.ProjectID),
-				ClusterType:            infra.Kind,
-				InfraID:                infra.ID,
-			},
-		))
-	case types.InfraDOCR, types.InfraECR, types.InfraGCR, types.InfraGAR, types.InfraACR:
-		c.Config.AnalyticsClient.Track(analytics.RegistryProvisioningStartTrack(
-			&analytics.RegistryProvisioningStartTrackOpts{
-				ProjectScopedTrackOpts: analytics.GetProjectScopedTrackOpts(0, infra.ProjectID),
-				RegistryType:           infra.Kind,
-				InfraID:                infra.ID,
-			},
-		))
+	ceToken, rawToken, err := createCredentialsExchangeToken(conf, infra)
+	if err != nil {
+		return err
 	}
+
+	return conf.Provisioner.Provision(&provisioner.ProvisionOpts{
+		Infra:         infra,
+		Operation:     operation,
+		OperationKind: operationKind,
+		Kind:          string(infra.Kind),
+		Values:        values,
+		// an approved operation applies exactly the plan that was approved, rather than planning again
+		ApplySavedPlan: isApplyOrDestroy && operation.ApprovedAt != nil,
+		CredentialExchange: &provisioner.ProvisionCredentialExchange{
+			CredExchangeEndpoint: fmt.Sprintf(
+				"%s/api/v1/%s/credentials",
+				conf.ProvisionerConf.ProvisionerCredExchangeURL,
+				models.GetWorkspaceID(infra, operation),
+			),
+			CredExchangeToken: rawToken,
+			CredExchangeID:    ceToken.ID,
+		},
+	})
 }
 
 func createCredentialsExchangeToken(conf *config.Config, infra *models.Infra) (*models.CredentialsExchangeToken, string, error) {
@@ -0,0 +1,217 @@
+package provision
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/analytics"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/provisioner"
+	"github.com/porter-dev/porter/provisioner/integrations/redis_stream"
+	"github.com/porter-dev/porter/provisioner/integrations/storage"
+	"github.com/porter-dev/porter/provisioner/server/config"
+	"gorm.io/gorm"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+// ProvisionApproveHandler approves the plan for an operation and runs the corresponding
+// apply or destroy
+type ProvisionApproveHandler struct {
+	Config *config.Config
+
+	resultWriter shared.ResultWriter
+}
+
+func NewProvisionApproveHandler(
+	config *config.Config,
+) *ProvisionApproveHandler {
+	return &ProvisionApproveHandler{
+		Config:       config,
+		resultWriter: shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	}
+}
+
+func (c *ProvisionApproveHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// read the project and infra from the attached scope
+	infra, _ := r.Context().Value(types.InfraScope).(*models.Infra)
+
+	operationID, reqErr := requestutils.GetURLParamString(r, types.URLParamOperationID)
+
+	if reqErr != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, reqErr, true)
+		return
+	}
+
+	operation, err := c.Config.Repo.Infra().ReadOperation(infra.ID, operationID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrForbidden(
+				fmt.Errorf("could not read operation %s for infra %d", operationID, infra.ID),
+			), true)
+		} else {
+			apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		}
+
+		return
+	}
+
+	if operation.Status != types.OperationStatusAwaitingApproval {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("operation %s is not awaiting approval", operation.UID),
+			http.StatusBadRequest,
+		), true)
+
+		return
+	}
+
+	// a plan is superseded by any operation created after it, so only the latest plan can be approved
+	lastOp, err := c.Config.Repo.Infra().GetLatestOperation(infra)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	if lastOp.UID != operation.UID {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("operation %s has been superseded by operation %s", operation.UID, lastOp.UID),
+			http.StatusBadRequest,
+		), true)
+
+		return
+	}
+
+	values, err := getLastAppliedValues(operation)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	// the saved plan is what gets applied, so it must exist and must have been created against the current state
+	if reqErr := c.checkSavedPlan(infra, operation); reqErr != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, reqErr, true)
+		return
+	}
+
+	// the status is only updated if the operation is still awaiting approval, so concurrent approvals cannot
+	// both start an apply
+	approved, err := c.Config.Repo.Infra().ApproveOperation(operation, time.Now())
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	if !approved {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("operation %s is no longer awaiting approval", operation.UID),
+			http.StatusConflict,
+		), true)
+
+		return
+	}
+
+	err = redis_stream.PushToOperationStream(c.Config.RedisClient, infra, operation, &ptypes.TFResourceState{
+		Status: "OPERATION_STARTED",
+	})
+
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	operationKind := provisioner.Apply
+
+	// update the infrastructure as either "updating", "creating" or "deleting"
+	switch operation.Type {
+	case "create", "retry_create":
+		infra.Status = types.InfraStatus("creating")
+	case "update":
+		infra.Status = types.InfraStatus("updating")
+	case "delete", "retry_delete":
+		infra.Status = types.InfraStatus("deleting")
+		operationKind = provisioner.Destroy
+	}
+
+	err = provision(c.Config, infra, operation, operationKind, values)
+
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	infra, err = c.Config.Repo.Infra().UpdateInfra(infra)
+
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	op, err := operation.ToOperationType()
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	// return the operation response type to the server
+	c.resultWriter.WriteResult(w, r, op)
+
+	if operationKind != provisioner.Apply {
+		return
+	}
+
+	// if this is a cluster or registry infra type, send to analytics client
+	switch infra.Kind {
+	case types.InfraDOKS, types.InfraEKS, types.InfraGKE, types.InfraAKS:
+		c.Config.AnalyticsClient.Track(analytics.ClusterProvisioningStartTrack(
+			&analytics.ClusterProvisioningStartTrackOpts{
+				ProjectScopedTrackOpts: analytics.GetProjectScopedTrackOpts(0, infra.ProjectID),
+				ClusterType:            infra.Kind,
+				InfraID:                infra.ID,
+			},
+		))
+	case types.InfraDOCR, types.InfraECR, types.InfraGCR, types.InfraGAR, types.InfraACR:
+		c.Config.AnalyticsClient.Track(analytics.RegistryProvisioningStartTrack(
+			&analytics.RegistryProvisioningStartTrackOpts{
+				ProjectScopedTrackOpts: analytics.GetProjectScopedTrackOpts(0, infra.ProjectID),
+				RegistryType:           infra.Kind,
+				InfraID:                infra.ID,
+			},
+		))
+	}
+}
+
+// checkSavedPlan returns an error if the operation has no saved plan, or if the terraform state has changed
+// since the plan was created, in which case the plan is stale and the operation must be planned again
+func (c *ProvisionApproveHandler) checkSavedPlan(infra *models.Infra, operation *models.Operation) apierrors.RequestError {
+	_, err := c.Config.StorageManager.ReadFile(infra, ptypes.TerraformPlanFile(models.GetWorkspaceID(infra, operation)), true)
+	if err != nil {
+		if errors.Is(err, storage.FileDoesNotExist) {
+			return apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("no plan was saved for operation %s, so it must be planned again", operation.UID),
+				http.StatusConflict,
+			)
+		}
+
+		return apierrors.NewErrInternal(err)
+	}
+
+	stateBytes, err := c.Config.StorageManager.ReadFile(infra, ptypes.DefaultTerraformStateFile, true)
+	if err != nil && !errors.Is(err, storage.FileDoesNotExist) {
+		return apierrors.NewErrInternal(err)
+	}
+
+	if ptypes.StateChecksum(stateBytes) != operation.PlanStateChecksum {
+		return apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("the state has changed since operation %s was planned, so it must be planned again", operation.UID),
+			http.StatusConflict,
+		)
+	}
+
+	return nil
+}
@@ -2,7 +2,6 @@ package provision
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 
 	"github.com/porter-dev/porter/api/server/shared"
@@ -60,7 +59,7 @@ func (c *ProvisionDestroyHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		UID:             operationUID,
 		InfraID:         infra.ID,
 		Type:            req.OperationKind,
-		Status:          "starting",
+		Status:          types.OperationStatusPlanning,
 		LastApplied:     lastOp.LastApplied,
 		TemplateVersion: "v0.1.0",
 	}
@@ -72,12 +71,6 @@ func (c *ProvisionDestroyHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	ceToken, rawToken, err := createCredentialsExchangeToken(c.Config, infra)
-	if err != nil {
-		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
-		return
-	}
-
 	// marshal the last applied values into a map[string]interface{}
 	lastApplied := make(map[string]interface{})
 
@@ -88,23 +81,8 @@ func (c *ProvisionDestroyHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	// spawn a new provisioning process
-	err = c.Config.Provisioner.Provision(&provisioner.ProvisionOpts{
-		Infra:         infra,
-		Operation:     operation,
-		OperationKind: provisioner.Destroy,
-		Kind:          string(infra.Kind),
-		Values:        lastApplied,
-		CredentialExchange: &provisioner.ProvisionCredentialExchange{
-			CredExchangeEndpoint: fmt.Sprintf(
-				"%s/api/v1/%s/credentials",
-				c.Config.ProvisionerConf.ProvisionerCredExchangeURL,
-				models.GetWorkspaceID(infra, operation),
-			),
-			CredExchangeToken: rawToken,
-			CredExchangeID:    ceToken.ID,
-		},
-	})
+	// spawn a new plan process: the destroy is run once the plan has been approved
+	err = provision(c.Config, infra, operation, provisioner.PlanDestroy, lastApplied)
 
 	if err != nil {
 		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
@@ -117,15 +95,6 @@ func (c *ProvisionDestroyHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	infra.Status = types.InfraStatus("deleting")
-
-	infra, err = c.Config.Repo.Infra().UpdateInfra(infra)
-
-	if err != nil {
-		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
-		return
-	}
-
 	// return the operation response type to the server
 	c.resultWriter.WriteResult(w, r, op)
 }
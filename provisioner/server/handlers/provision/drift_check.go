@@ -0,0 +1,109 @@
+package provision
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/provisioner"
+	"github.com/porter-dev/porter/provisioner/server/config"
+)
+
+// ErrDriftCheckNotAllowed is returned when an infra cannot be checked for drift, because it has
+// not been created or another operation is in progress
+var ErrDriftCheckNotAllowed = fmt.Errorf("drift can only be checked for created infra with no operation in progress")
+
+// ProvisionDriftCheckHandler starts a drift check for an infra
+type ProvisionDriftCheckHandler struct {
+	Config *config.Config
+
+	resultWriter shared.ResultWriter
+}
+
+func NewProvisionDriftCheckHandler(
+	config *config.Config,
+) *ProvisionDriftCheckHandler {
+	return &ProvisionDriftCheckHandler{
+		Config:       config,
+		resultWriter: shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	}
+}
+
+func (c *ProvisionDriftCheckHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// read the project and infra from the attached scope
+	infra, _ := r.Context().Value(types.InfraScope).(*models.Infra)
+
+	operation, err := RunDriftCheck(c.Config, infra)
+	if err != nil {
+		if err == ErrDriftCheckNotAllowed {
+			apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrPassThroughToClient(
+				err,
+				http.StatusBadRequest,
+			), true)
+		} else {
+			apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		}
+
+		return
+	}
+
+	op, err := operation.ToOperationType()
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	// return the operation response type to the server
+	c.resultWriter.WriteResult(w, r, op)
+}
+
+// RunDriftCheck creates a new drift check operation for the infra and spawns a refresh-only plan
+// using the last-applied values. The result is stored on the infra once the plan has completed.
+func RunDriftCheck(conf *config.Config, infra *models.Infra) (*models.Operation, error) {
+	if infra.Status != types.StatusCreated {
+		return nil, ErrDriftCheckNotAllowed
+	}
+
+	lastOp, err := conf.Repo.Infra().GetLatestOperation(infra)
+	if err != nil {
+		return nil, err
+	}
+
+	// drift checks must not supersede a plan that is awaiting approval
+	if lastOp.Status != types.OperationStatusCompleted && lastOp.Status != "errored" {
+		return nil, ErrDriftCheckNotAllowed
+	}
+
+	operationUID, err := models.GetOperationID()
+	if err != nil {
+		return nil, err
+	}
+
+	operation := &models.Operation{
+		UID:             operationUID,
+		InfraID:         infra.ID,
+		Type:            types.OperationTypeDriftCheck,
+		Status:          types.OperationStatusPlanning,
+		LastApplied:     lastOp.LastApplied,
+		TemplateVersion: lastOp.TemplateVersion,
+	}
+
+	operation, err = conf.Repo.Infra().AddOperation(infra, operation)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := getLastAppliedValues(operation)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := provision(conf, infra, operation, provisioner.DriftCheck, values); err != nil {
+		return nil, err
+	}
+
+	return operation, nil
+}
@@ -0,0 +1,86 @@
+package provision
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/provisioner/integrations/provisioner"
+	"github.com/porter-dev/porter/provisioner/server/ptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunDriftCheck(t *testing.T) {
+	conf, prov, _ := ptest.LoadConfig(t)
+	infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+	lastOp := ptest.AddTestOperation(t, conf, infra, "update", types.OperationStatusCompleted)
+
+	operation, err := RunDriftCheck(conf, infra)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, types.OperationTypeDriftCheck, operation.Type)
+	assert.Equal(t, types.OperationStatusPlanning, operation.Status)
+	assert.Equal(t, lastOp.LastApplied, operation.LastApplied, "drift should be checked against the last-applied values")
+	assert.Equal(t, types.StatusCreated, infra.Status, "a drift check should not change the status of the infra")
+
+	assert.Equal(t, []provisioner.ProvisionerOperation{provisioner.DriftCheck}, prov.OperationKinds())
+	assert.Equal(t, map[string]interface{}{"cluster_name": "test"}, prov.Calls()[0].Values)
+}
+
+func TestRunDriftCheckNotAllowed(t *testing.T) {
+	tests := map[string]struct {
+		infraStatus  types.InfraStatus
+		lastOpStatus string
+	}{
+		"infra not created": {
+			infraStatus:  "errored",
+			lastOpStatus: "errored",
+		},
+		"plan awaiting approval": {
+			infraStatus:  types.StatusCreated,
+			lastOpStatus: types.OperationStatusAwaitingApproval,
+		},
+		"operation in progress": {
+			infraStatus:  types.StatusCreated,
+			lastOpStatus: types.OperationStatusStarting,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			conf, prov, _ := ptest.LoadConfig(t)
+			infra := ptest.CreateTestInfra(t, conf, tc.infraStatus)
+			lastOp := ptest.AddTestOperation(t, conf, infra, "update", tc.lastOpStatus)
+
+			_, err := RunDriftCheck(conf, infra)
+			assert.ErrorIs(t, err, ErrDriftCheckNotAllowed)
+			assert.Empty(t, prov.OperationKinds())
+
+			latest, err := conf.Repo.Infra().GetLatestOperation(infra)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, lastOp.UID, latest.UID, "a drift check should not supersede the latest operation")
+		})
+	}
+}
+
+func TestDriftCheckHandlerNotAllowed(t *testing.T) {
+	conf, prov, _ := ptest.LoadConfig(t)
+	infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+	ptest.AddTestOperation(t, conf, infra, "update", types.OperationStatusAwaitingApproval)
+
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/v1/infra/drift_check", nil)
+	req = withInfra(req, infra)
+
+	NewProvisionDriftCheckHandler(conf).ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
+		Error: ErrDriftCheckNotAllowed.Error(),
+	})
+	assert.Empty(t, prov.OperationKinds())
+}
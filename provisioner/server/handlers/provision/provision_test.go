@@ -0,0 +1,264 @@
+package provision
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/provisioner"
+	"github.com/porter-dev/porter/provisioner/server/config"
+	"github.com/porter-dev/porter/provisioner/server/ptest"
+	"github.com/stretchr/testify/assert"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+func TestApplyPlansBeforeApproval(t *testing.T) {
+	conf, prov, _ := ptest.LoadConfig(t)
+	infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/v1/infra/apply", &ptypes.ApplyBaseRequest{
+		Kind:          string(types.InfraEKS),
+		Values:        map[string]interface{}{"cluster_name": "test"},
+		OperationKind: "update",
+	})
+	req = withInfra(req, infra)
+
+	NewProvisionApplyHandler(conf).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	assert.Equal(t, []provisioner.ProvisionerOperation{provisioner.Plan}, prov.OperationKinds(), "apply should only run a plan")
+
+	operation, err := conf.Repo.Infra().GetLatestOperation(infra)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, types.OperationStatusPlanning, operation.Status)
+	assert.Nil(t, operation.ApprovedAt)
+}
+
+func TestDestroyPlansBeforeApproval(t *testing.T) {
+	conf, prov, _ := ptest.LoadConfig(t)
+	infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+	ptest.AddTestOperation(t, conf, infra, "create", types.OperationStatusCompleted)
+
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/v1/infra/destroy", &ptypes.DeleteBaseRequest{
+		OperationKind: "delete",
+	})
+	req = withInfra(req, infra)
+
+	NewProvisionDestroyHandler(conf).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	assert.Equal(t, []provisioner.ProvisionerOperation{provisioner.PlanDestroy}, prov.OperationKinds(), "destroy should only run a plan")
+
+	operation, err := conf.Repo.Infra().GetLatestOperation(infra)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, types.OperationStatusPlanning, operation.Status)
+	assert.Nil(t, operation.ApprovedAt)
+}
+
+func TestApproveOperation(t *testing.T) {
+	tests := map[string]struct {
+		operationType string
+		expKind       provisioner.ProvisionerOperation
+		expStatus     types.InfraStatus
+	}{
+		"create": {
+			operationType: "create",
+			expKind:       provisioner.Apply,
+			expStatus:     "creating",
+		},
+		"update": {
+			operationType: "update",
+			expKind:       provisioner.Apply,
+			expStatus:     "updating",
+		},
+		"delete": {
+			operationType: "delete",
+			expKind:       provisioner.Destroy,
+			expStatus:     "deleting",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			conf, prov, _ := ptest.LoadConfig(t)
+			infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+			operation := ptest.AddTestOperation(t, conf, infra, tc.operationType, types.OperationStatusAwaitingApproval)
+			ptest.SaveTestPlan(t, conf, infra, operation)
+
+			rr := approve(t, conf, infra, operation.UID)
+
+			assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+			assert.Equal(t, []provisioner.ProvisionerOperation{tc.expKind}, prov.OperationKinds())
+
+			operation, err := conf.Repo.Infra().ReadOperation(infra.ID, operation.UID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, types.OperationStatusStarting, operation.Status)
+			assert.NotNil(t, operation.ApprovedAt, "approved operation should record when it was approved")
+			assert.Equal(t, tc.expStatus, infra.Status)
+
+			// the plan that was approved is the one which is applied
+			assert.True(t, prov.Calls()[0].ApplySavedPlan, "the saved plan should be applied")
+			assert.Equal(t, map[string]interface{}{"cluster_name": "test"}, prov.Calls()[0].Values)
+		})
+	}
+}
+
+func TestApproveOperationWithoutSavedPlan(t *testing.T) {
+	conf, prov, _ := ptest.LoadConfig(t)
+	infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+	operation := ptest.AddTestOperation(t, conf, infra, "update", types.OperationStatusAwaitingApproval)
+
+	rr := approve(t, conf, infra, operation.UID)
+
+	apitest.AssertResponseError(t, rr, http.StatusConflict, &types.ExternalError{
+		Error: "no plan was saved for operation " + operation.UID + ", so it must be planned again",
+	})
+	assert.Empty(t, prov.OperationKinds(), "nothing should be applied without a saved plan")
+	assertAwaitingApproval(t, conf, infra, operation.UID)
+}
+
+func TestApproveOperationWithStalePlan(t *testing.T) {
+	conf, prov, _ := ptest.LoadConfig(t)
+	infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+	operation := ptest.AddTestOperation(t, conf, infra, "update", types.OperationStatusAwaitingApproval)
+	ptest.SaveTestPlan(t, conf, infra, operation)
+
+	// the state changes after the plan was created, e.g. through a reconcile
+	err := conf.StorageManager.WriteFile(infra, ptypes.DefaultTerraformStateFile, []byte(`{"serial": 2}`), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := approve(t, conf, infra, operation.UID)
+
+	apitest.AssertResponseError(t, rr, http.StatusConflict, &types.ExternalError{
+		Error: "the state has changed since operation " + operation.UID + " was planned, so it must be planned again",
+	})
+	assert.Empty(t, prov.OperationKinds(), "a stale plan should not be applied")
+	assertAwaitingApproval(t, conf, infra, operation.UID)
+}
+
+func TestApproveOperationNotAwaitingApproval(t *testing.T) {
+	for _, status := range []string{types.OperationStatusPlanning, types.OperationStatusStarting, types.OperationStatusCompleted} {
+		t.Run(status, func(t *testing.T) {
+			conf, prov, _ := ptest.LoadConfig(t)
+			infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+			operation := ptest.AddTestOperation(t, conf, infra, "update", status)
+
+			rr := approve(t, conf, infra, operation.UID)
+
+			apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
+				Error: "operation " + operation.UID + " is not awaiting approval",
+			})
+			assert.Empty(t, prov.OperationKinds(), "nothing should be applied")
+		})
+	}
+}
+
+func TestApproveStaleOperation(t *testing.T) {
+	conf, prov, _ := ptest.LoadConfig(t)
+	infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+
+	stale := ptest.AddTestOperation(t, conf, infra, "update", types.OperationStatusAwaitingApproval)
+	latest := ptest.AddTestOperation(t, conf, infra, "update", types.OperationStatusAwaitingApproval)
+
+	rr := approve(t, conf, infra, stale.UID)
+
+	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
+		Error: "operation " + stale.UID + " has been superseded by operation " + latest.UID,
+	})
+	assert.Empty(t, prov.OperationKinds(), "a superseded plan should not be applied")
+
+	stale, err := conf.Repo.Infra().ReadOperation(infra.ID, stale.UID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, types.OperationStatusAwaitingApproval, stale.Status)
+	assert.Nil(t, stale.ApprovedAt)
+}
+
+func TestApproveUnknownOperation(t *testing.T) {
+	conf, prov, _ := ptest.LoadConfig(t)
+	infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+
+	rr := approve(t, conf, infra, "does-not-exist")
+
+	apitest.AssertResponseForbidden(t, rr)
+	assert.Empty(t, prov.OperationKinds())
+}
+
+func TestProvisionRefusesUnapprovedOperation(t *testing.T) {
+	for _, kind := range []provisioner.ProvisionerOperation{provisioner.Apply, provisioner.Destroy} {
+		t.Run(string(kind), func(t *testing.T) {
+			conf, prov, _ := ptest.LoadConfig(t)
+			infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+			operation := ptest.AddTestOperation(t, conf, infra, "update", types.OperationStatusAwaitingApproval)
+
+			err := provision(conf, infra, operation, kind, nil)
+			assert.ErrorIs(t, err, errOperationNotApproved)
+			assert.Empty(t, prov.OperationKinds(), "an unapproved operation should not be applied")
+
+			now := time.Now()
+			operation.ApprovedAt = &now
+
+			err = provision(conf, infra, operation, kind, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, []provisioner.ProvisionerOperation{kind}, prov.OperationKinds())
+		})
+	}
+}
+
+func TestProvisionAllowsReconcileWithoutPlan(t *testing.T) {
+	conf, prov, _ := ptest.LoadConfig(t)
+	infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+	operation := ptest.AddTestOperation(t, conf, infra, types.OperationTypeReconcile, types.OperationStatusStarting)
+
+	err := provision(conf, infra, operation, provisioner.Apply, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []provisioner.ProvisionerOperation{provisioner.Apply}, prov.OperationKinds())
+	assert.False(t, prov.Calls()[0].ApplySavedPlan, "a reconcile has no saved plan to apply")
+}
+
+func assertAwaitingApproval(t *testing.T, conf *config.Config, infra *models.Infra, operationUID string) {
+	t.Helper()
+
+	operation, err := conf.Repo.Infra().ReadOperation(infra.ID, operationUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, types.OperationStatusAwaitingApproval, operation.Status)
+	assert.Nil(t, operation.ApprovedAt)
+}
+
+func approve(t *testing.T, conf *config.Config, infra *models.Infra, operationUID string) *httptest.ResponseRecorder {
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/v1/infra/operations/"+operationUID+"/approve", nil)
+	req = withInfra(req, infra)
+	req = apitest.WithURLParams(t, req, map[string]string{
+		string(types.URLParamOperationID): operationUID,
+	})
+
+	NewProvisionApproveHandler(conf).ServeHTTP(rr, req)
+
+	return rr
+}
+
+func withInfra(req *http.Request, infra *models.Infra) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), types.InfraScope, infra))
+}
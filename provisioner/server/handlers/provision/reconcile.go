@@ -0,0 +1,125 @@
+package provision
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/provisioner"
+	"github.com/porter-dev/porter/provisioner/integrations/redis_stream"
+	"github.com/porter-dev/porter/provisioner/server/config"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+// ProvisionReconcileHandler re-applies the last-applied values for an infra which has drifted.
+// Since these values were already approved, the apply is not gated on a new plan.
+type ProvisionReconcileHandler struct {
+	Config *config.Config
+
+	resultWriter shared.ResultWriter
+}
+
+func NewProvisionReconcileHandler(
+	config *config.Config,
+) *ProvisionReconcileHandler {
+	return &ProvisionReconcileHandler{
+		Config:       config,
+		resultWriter: shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	}
+}
+
+func (c *ProvisionReconcileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// read the project and infra from the attached scope
+	infra, _ := r.Context().Value(types.InfraScope).(*models.Infra)
+
+	if drift := infra.GetDrift(); drift == nil || !drift.Drifted {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("infra %d has no drift to reconcile", infra.ID),
+			http.StatusBadRequest,
+		), true)
+
+		return
+	}
+
+	lastOp, err := c.Config.Repo.Infra().GetLatestOperation(infra)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	if lastOp.Status != types.OperationStatusCompleted && lastOp.Status != "errored" {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("Operation currently in progress. Please try again when latest operation has completed."),
+			http.StatusBadRequest,
+		), true)
+
+		return
+	}
+
+	operationUID, err := models.GetOperationID()
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	operation := &models.Operation{
+		UID:             operationUID,
+		InfraID:         infra.ID,
+		Type:            types.OperationTypeReconcile,
+		Status:          types.OperationStatusStarting,
+		LastApplied:     lastOp.LastApplied,
+		TemplateVersion: lastOp.TemplateVersion,
+	}
+
+	operation, err = c.Config.Repo.Infra().AddOperation(infra, operation)
+
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	values, err := getLastAppliedValues(operation)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	// push a first message to the operation stream
+	err = redis_stream.PushToOperationStream(c.Config.RedisClient, infra, operation, &ptypes.TFResourceState{
+		Status: "OPERATION_STARTED",
+	})
+
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	err = provision(c.Config, infra, operation, provisioner.Apply, values)
+
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	infra.Status = types.InfraStatus("updating")
+
+	infra, err = c.Config.Repo.Infra().UpdateInfra(infra)
+
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	op, err := operation.ToOperationType()
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	// return the operation response type to the server
+	c.resultWriter.WriteResult(w, r, op)
+}
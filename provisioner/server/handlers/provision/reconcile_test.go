@@ -0,0 +1,108 @@
+package provision
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/provisioner"
+	"github.com/porter-dev/porter/provisioner/server/config"
+	"github.com/porter-dev/porter/provisioner/server/ptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcile(t *testing.T) {
+	conf, prov, _ := ptest.LoadConfig(t)
+	infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+	ptest.AddTestOperation(t, conf, infra, types.OperationTypeDriftCheck, types.OperationStatusCompleted)
+	setDrift(t, infra, []types.TerraformDriftedResource{
+		{Address: "aws_eks_cluster.cluster", Action: "update"},
+	})
+
+	rr := reconcile(t, conf, infra)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+	// the last-applied values were already approved, so they are applied without a new plan
+	assert.Equal(t, []provisioner.ProvisionerOperation{provisioner.Apply}, prov.OperationKinds())
+	assert.Equal(t, map[string]interface{}{"cluster_name": "test"}, prov.Calls()[0].Values)
+
+	operation, err := conf.Repo.Infra().GetLatestOperation(infra)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, types.OperationTypeReconcile, operation.Type)
+	assert.Equal(t, types.OperationStatusStarting, operation.Status)
+	assert.Equal(t, types.InfraStatus("updating"), infra.Status)
+}
+
+func TestReconcileNoDrift(t *testing.T) {
+	tests := map[string][]types.TerraformDriftedResource{
+		"never checked":  nil,
+		"checked, clean": {},
+	}
+
+	for name, drifted := range tests {
+		t.Run(name, func(t *testing.T) {
+			conf, prov, _ := ptest.LoadConfig(t)
+			infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+			ptest.AddTestOperation(t, conf, infra, types.OperationTypeDriftCheck, types.OperationStatusCompleted)
+
+			if drifted != nil {
+				setDrift(t, infra, drifted)
+			}
+
+			rr := reconcile(t, conf, infra)
+
+			apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
+				Error: fmt.Sprintf("infra %d has no drift to reconcile", infra.ID),
+			})
+			assert.Empty(t, prov.OperationKinds())
+			assert.Equal(t, types.StatusCreated, infra.Status)
+		})
+	}
+}
+
+func TestReconcileOperationInProgress(t *testing.T) {
+	conf, prov, _ := ptest.LoadConfig(t)
+	infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+	ptest.AddTestOperation(t, conf, infra, "update", types.OperationStatusAwaitingApproval)
+	setDrift(t, infra, []types.TerraformDriftedResource{
+		{Address: "aws_eks_cluster.cluster", Action: "update"},
+	})
+
+	rr := reconcile(t, conf, infra)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Result().StatusCode)
+	assert.Empty(t, prov.OperationKinds(), "a reconcile should not supersede a plan awaiting approval")
+}
+
+func setDrift(t *testing.T, infra *models.Infra, drifted []types.TerraformDriftedResource) {
+	t.Helper()
+
+	driftBytes, err := json.Marshal(drifted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+
+	infra.DriftedResources = driftBytes
+	infra.DriftCheckedAt = &now
+}
+
+func reconcile(t *testing.T, conf *config.Config, infra *models.Infra) *httptest.ResponseRecorder {
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/v1/infra/reconcile", nil)
+	req = withInfra(req, infra)
+
+	NewProvisionReconcileHandler(conf).ServeHTTP(rr, req)
+
+	return rr
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/service/ecr"
 	"github.com/porter-dev/porter/api/server/shared"
@@ -71,6 +72,14 @@ func (c *CreateResourceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	}
 	// update the infra to indicate completion
 	infra.Status = "created"
+
+	// a completed reconcile has reverted any drift
+	if operation.Type == types.OperationTypeReconcile {
+		now := time.Now()
+
+		infra.DriftedResources = nil
+		infra.DriftCheckedAt = &now
+	}
 	infra, err = c.Config.Repo.Infra().UpdateInfra(infra)
 	if err != nil {
 		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
@@ -0,0 +1,62 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/storage"
+	"github.com/porter-dev/porter/provisioner/server/config"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+// PlanGetHandler returns the saved plan of an approved operation, which is passed to `terraform apply`
+type PlanGetHandler struct {
+	Config *config.Config
+}
+
+func NewPlanGetHandler(
+	config *config.Config,
+) *PlanGetHandler {
+	return &PlanGetHandler{
+		Config: config,
+	}
+}
+
+func (c *PlanGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// read the infra and operation from the attached scope
+	infra, _ := r.Context().Value(types.InfraScope).(*models.Infra)
+	operation, _ := r.Context().Value(types.OperationScope).(*models.Operation)
+
+	if operation.ApprovedAt == nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("the plan for operation %s has not been approved", operation.UID),
+			http.StatusBadRequest,
+		), true)
+
+		return
+	}
+
+	fileBytes, err := c.Config.StorageManager.ReadFile(infra, ptypes.TerraformPlanFile(models.GetWorkspaceID(infra, operation)), true)
+	if err != nil {
+		if errors.Is(err, storage.FileDoesNotExist) {
+			apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrNotFound(
+				fmt.Errorf("no plan was saved for operation %s", operation.UID),
+			), true)
+
+			return
+		}
+
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	if _, err = w.Write(fileBytes); err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+}
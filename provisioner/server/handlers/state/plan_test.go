@@ -0,0 +1,109 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/server/ptest"
+	"github.com/stretchr/testify/assert"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+func TestUpdatePlan(t *testing.T) {
+	conf, _, _ := ptest.LoadConfig(t)
+	infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+	operation := ptest.AddTestOperation(t, conf, infra, "update", types.OperationStatusPlanning)
+
+	state := []byte(`{"serial": 1}`)
+
+	if err := conf.StorageManager.WriteFile(infra, ptypes.DefaultTerraformStateFile, state, true); err != nil {
+		t.Fatal(err)
+	}
+
+	rr := servePlanRequest(NewPlanUpdateHandler(conf), http.MethodPost, infra, operation, []byte("plan"))
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+	plan, err := conf.StorageManager.ReadFile(infra, ptypes.TerraformPlanFile(models.GetWorkspaceID(infra, operation)), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []byte("plan"), plan)
+
+	operation, err = conf.Repo.Infra().ReadOperation(infra.ID, operation.UID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, ptypes.StateChecksum(state), operation.PlanStateChecksum, "the plan should record the state it was created against")
+}
+
+func TestUpdatePlanRefusedOnceAwaitingApproval(t *testing.T) {
+	conf, _, _ := ptest.LoadConfig(t)
+	infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+	operation := ptest.AddTestOperation(t, conf, infra, "update", types.OperationStatusAwaitingApproval)
+
+	rr := servePlanRequest(NewPlanUpdateHandler(conf), http.MethodPost, infra, operation, []byte("plan"))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Result().StatusCode)
+
+	_, err := conf.StorageManager.ReadFile(infra, ptypes.TerraformPlanFile(models.GetWorkspaceID(infra, operation)), true)
+	assert.Error(t, err, "a plan which is awaiting approval should not be replaced")
+}
+
+func TestGetPlan(t *testing.T) {
+	conf, _, _ := ptest.LoadConfig(t)
+	infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+	operation := ptest.AddTestOperation(t, conf, infra, "update", types.OperationStatusAwaitingApproval)
+
+	ptest.SaveTestPlan(t, conf, infra, operation)
+
+	// the plan cannot be applied before it is approved
+	rr := servePlanRequest(NewPlanGetHandler(conf), http.MethodGet, infra, operation, nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Result().StatusCode)
+
+	approvedAt := time.Now()
+	operation.ApprovedAt = &approvedAt
+
+	rr = servePlanRequest(NewPlanGetHandler(conf), http.MethodGet, infra, operation, nil)
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	assert.NotEmpty(t, rr.Body.Bytes())
+}
+
+func TestGetPlanNotSaved(t *testing.T) {
+	conf, _, _ := ptest.LoadConfig(t)
+	infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+	operation := ptest.AddTestOperation(t, conf, infra, "update", types.OperationStatusStarting)
+
+	approvedAt := time.Now()
+	operation.ApprovedAt = &approvedAt
+
+	rr := servePlanRequest(NewPlanGetHandler(conf), http.MethodGet, infra, operation, nil)
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+}
+
+func servePlanRequest(
+	handler http.Handler,
+	method string,
+	infra *models.Infra,
+	operation *models.Operation,
+	body []byte,
+) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/v1/tfplan", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	ctx := context.WithValue(req.Context(), types.InfraScope, infra)
+	ctx = context.WithValue(ctx, types.OperationScope, operation)
+
+	handler.ServeHTTP(rr, req.WithContext(ctx))
+
+	return rr
+}
@@ -39,13 +39,20 @@ func (c *ReportErrorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// update the infra to indicate error
-	infra.Status = "errored"
+	// a failed drift check does not modify any resources, so the infra is not marked as errored
+	isDriftCheck := operation.Type == types.OperationTypeDriftCheck
 
-	infra, err := c.Config.Repo.Infra().UpdateInfra(infra)
-	if err != nil {
-		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
-		return
+	if !isDriftCheck {
+		// update the infra to indicate error
+		infra.Status = "errored"
+
+		var err error
+
+		infra, err = c.Config.Repo.Infra().UpdateInfra(infra)
+		if err != nil {
+			apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+			return
+		}
 	}
 
 	// update the operation with the error
@@ -53,7 +60,7 @@ func (c *ReportErrorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	operation.Errored = true
 	operation.Error = req.Error
 
-	operation, err = c.Config.Repo.Infra().UpdateOperation(operation)
+	operation, err := c.Config.Repo.Infra().UpdateOperation(operation)
 
 	if err != nil {
 		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
@@ -68,6 +75,11 @@ func (c *ReportErrorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if isDriftCheck {
+		c.Config.Logger.Error().Msgf("drift check %s for infra %d failed: %s", operation.UID, infra.ID, req.Error)
+		return
+	}
+
 	// push to the global stream
 	err = redis_stream.PushToGlobalStream(c.Config.RedisClient, infra, operation, "error")
 
@@ -0,0 +1,71 @@
+package state
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/provisioner/integrations/redis_stream"
+	"github.com/porter-dev/porter/provisioner/server/ptest"
+	"github.com/stretchr/testify/assert"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+func TestReportError(t *testing.T) {
+	tests := map[string]struct {
+		operationType  string
+		expInfraStatus types.InfraStatus
+		expGlobalEvent bool
+	}{
+		"apply": {
+			operationType:  "update",
+			expInfraStatus: "errored",
+			expGlobalEvent: true,
+		},
+		"drift check": {
+			operationType:  types.OperationTypeDriftCheck,
+			expInfraStatus: types.StatusCreated,
+			expGlobalEvent: false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			conf, _, redis := ptest.LoadConfig(t)
+			infra := ptest.CreateTestInfra(t, conf, types.StatusCreated)
+			operation := ptest.AddTestOperation(t, conf, infra, tc.operationType, types.OperationStatusStarting)
+
+			req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/v1/error", &ptypes.ReportErrorRequest{
+				Error: "terraform exited with code 1",
+			})
+
+			ctx := context.WithValue(req.Context(), types.InfraScope, infra)
+			ctx = context.WithValue(ctx, types.OperationScope, operation)
+			req = req.WithContext(ctx)
+
+			NewReportErrorHandler(conf).ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+			infra, err := conf.Repo.Infra().ReadInfra(infra.ProjectID, infra.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, tc.expInfraStatus, infra.Status)
+
+			operation, err = conf.Repo.Infra().ReadOperation(infra.ID, operation.UID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.True(t, operation.Errored)
+			assert.Equal(t, "terraform exited with code 1", operation.Error)
+
+			assert.Equal(t, tc.expGlobalEvent, len(redis.StreamEntries(redis_stream.GlobalStreamName)) > 0, "only errors which change the infra should be pushed to the global stream")
+		})
+	}
+}
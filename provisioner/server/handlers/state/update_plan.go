@@ -0,0 +1,72 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/storage"
+	"github.com/porter-dev/porter/provisioner/server/config"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+// PlanUpdateHandler saves the plan file written by `terraform plan -out`, so that the plan which is
+// approved is the plan which is applied
+type PlanUpdateHandler struct {
+	Config *config.Config
+}
+
+func NewPlanUpdateHandler(
+	config *config.Config,
+) *PlanUpdateHandler {
+	return &PlanUpdateHandler{
+		Config: config,
+	}
+}
+
+func (c *PlanUpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// read the infra and operation from the attached scope
+	infra, _ := r.Context().Value(types.InfraScope).(*models.Infra)
+	operation, _ := r.Context().Value(types.OperationScope).(*models.Operation)
+
+	// a plan can only be saved while it is being created, so an approved plan cannot be replaced
+	if operation.Status != types.OperationStatusPlanning {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("operation %s is not planning", operation.UID),
+			http.StatusBadRequest,
+		), true)
+
+		return
+	}
+
+	fileBytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	// the checksum of the state is stored so that the plan can be refused once the state has changed
+	stateBytes, err := c.Config.StorageManager.ReadFile(infra, ptypes.DefaultTerraformStateFile, true)
+	if err != nil && !errors.Is(err, storage.FileDoesNotExist) {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	err = c.Config.StorageManager.WriteFile(infra, ptypes.TerraformPlanFile(models.GetWorkspaceID(infra, operation)), fileBytes, true)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	operation.PlanStateChecksum = ptypes.StateChecksum(stateBytes)
+
+	if _, err := c.Config.Repo.Infra().UpdateOperation(operation); err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+}
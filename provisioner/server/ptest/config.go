@@ -0,0 +1,84 @@
+// Package ptest contains helpers for testing the provisioner server, similar to the apitest
+// package of the API server
+package ptest
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/shared/apierrors/alerter"
+	"github.com/porter-dev/porter/internal/analytics"
+	"github.com/porter-dev/porter/internal/repository/test"
+	"github.com/porter-dev/porter/pkg/logger"
+	"github.com/porter-dev/porter/provisioner/integrations/provisioner"
+	"github.com/porter-dev/porter/provisioner/integrations/storage/local"
+	"github.com/porter-dev/porter/provisioner/server/config"
+)
+
+// LoadConfig returns a provisioner server config backed by the test repository, local storage in a
+// temporary directory, a fake redis server and a fake provisioner which records the operations it is asked to run
+func LoadConfig(t *testing.T, failingRepoMethods ...string) (*config.Config, *FakeProvisioner, *FakeRedis) {
+	l := logger.New(true, os.Stdout)
+
+	redis := NewFakeRedis(t)
+	prov := &FakeProvisioner{}
+
+	var key [32]byte
+
+	storageManager, err := local.NewLocalStorageClient(&local.LocalOptions{
+		RootDirectory: t.TempDir(),
+		EncryptionKey: &key,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &config.Config{
+		ProvisionerConf: &config.ProvisionerConf{
+			ProvisionerCredExchangeURL: "http://localhost:8082",
+		},
+		Repo:            test.NewRepository(true, failingRepoMethods...),
+		Logger:          l,
+		Alerter:         alerter.NoOpAlerter{},
+		RedisClient:     redis.Client(),
+		StorageManager:  storageManager,
+		Provisioner:     prov,
+		AnalyticsClient: analytics.InitializeAnalyticsSegmentClient("", l),
+	}, prov, redis
+}
+
+// FakeProvisioner implements provisioner.Provisioner by recording the operations it is asked to run
+type FakeProvisioner struct {
+	mu    sync.Mutex
+	calls []*provisioner.ProvisionOpts
+}
+
+// Provision records the provisioning options
+func (f *FakeProvisioner) Provision(opts *provisioner.ProvisionOpts) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, opts)
+
+	return nil
+}
+
+// Calls returns the options of every operation that the provisioner was asked to run, in order
+func (f *FakeProvisioner) Calls() []*provisioner.ProvisionOpts {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]*provisioner.ProvisionOpts{}, f.calls...)
+}
+
+// OperationKinds returns the kind of every operation that the provisioner was asked to run, in order
+func (f *FakeProvisioner) OperationKinds() []provisioner.ProvisionerOperation {
+	kinds := []provisioner.ProvisionerOperation{}
+
+	for _, call := range f.Calls() {
+		kinds = append(kinds, call.OperationKind)
+	}
+
+	return kinds
+}
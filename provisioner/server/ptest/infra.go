@@ -0,0 +1,82 @@
+package ptest
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/storage"
+	"github.com/porter-dev/porter/provisioner/server/config"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+// CreateTestInfra creates an EKS infra with the given status in the test repository
+func CreateTestInfra(t *testing.T, conf *config.Config, status types.InfraStatus) *models.Infra {
+	t.Helper()
+
+	infra, err := conf.Repo.Infra().CreateInfra(&models.Infra{
+		Kind:      types.InfraEKS,
+		ProjectID: 1,
+		Suffix:    "abcdef",
+		Status:    status,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return infra
+}
+
+// AddTestOperation adds an operation of the given type and status to the infra, which was
+// last applied with the values {"cluster_name": "test"}
+func AddTestOperation(t *testing.T, conf *config.Config, infra *models.Infra, operationType, status string) *models.Operation {
+	t.Helper()
+
+	uid, err := models.GetOperationID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := json.Marshal(map[string]interface{}{"cluster_name": "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	operation, err := conf.Repo.Infra().AddOperation(infra, &models.Operation{
+		UID:             uid,
+		InfraID:         infra.ID,
+		Type:            operationType,
+		Status:          status,
+		LastApplied:     values,
+		TemplateVersion: "v0.1.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return operation
+}
+
+// SaveTestPlan saves a plan for the operation, created against the current terraform state of the infra
+func SaveTestPlan(t *testing.T, conf *config.Config, infra *models.Infra, operation *models.Operation) {
+	t.Helper()
+
+	state, err := conf.StorageManager.ReadFile(infra, ptypes.DefaultTerraformStateFile, true)
+	if err != nil && !errors.Is(err, storage.FileDoesNotExist) {
+		t.Fatal(err)
+	}
+
+	err = conf.StorageManager.WriteFile(infra, ptypes.TerraformPlanFile(models.GetWorkspaceID(infra, operation)), []byte("plan"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	operation.PlanStateChecksum = ptypes.StateChecksum(state)
+
+	if _, err := conf.Repo.Infra().UpdateOperation(operation); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,225 @@
+package ptest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	redis "github.com/go-redis/redis/v8"
+)
+
+// FakeRedis is a minimal redis server which accepts the commands sent by the provisioner server and
+// records them. String keys are stored so that locks can be taken and released, while stream
+// entries are acknowledged but not stored, so streams always read as empty.
+type FakeRedis struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	commands [][]string
+	values   map[string]string
+}
+
+// NewFakeRedis starts a fake redis server which is stopped when the test finishes
+func NewFakeRedis(t *testing.T) *FakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &FakeRedis{
+		listener: listener,
+		values:   make(map[string]string),
+	}
+
+	go f.serve()
+
+	t.Cleanup(func() {
+		listener.Close() // nolint:errcheck,gosec
+	})
+
+	return f
+}
+
+// Client returns a redis client connected to the fake server
+func (f *FakeRedis) Client() *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:       f.listener.Addr().String(),
+		MaxRetries: -1,
+	})
+}
+
+// Commands returns every command that was received, in order
+func (f *FakeRedis) Commands() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([][]string{}, f.commands...)
+}
+
+// Set stores a string key, as if it had been set by another client
+func (f *FakeRedis) Set(key, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.values[key] = value
+}
+
+// Exists returns true if the string key is set
+func (f *FakeRedis) Exists(key string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.values[key]
+
+	return ok
+}
+
+// StreamEntries returns the values of every entry added to the given stream, in order
+func (f *FakeRedis) StreamEntries(stream string) []map[string]string {
+	var entries []map[string]string
+
+	for _, cmd := range f.Commands() {
+		if len(cmd) < 5 || !strings.EqualFold(cmd[0], "xadd") || cmd[1] != stream {
+			continue
+		}
+
+		// XADD stream id field value [field value ...]
+		entry := make(map[string]string)
+		for i := 3; i+1 < len(cmd); i += 2 {
+			entry[cmd[i]] = cmd[i+1]
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func (f *FakeRedis) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go f.handle(conn)
+	}
+}
+
+func (f *FakeRedis) handle(conn net.Conn) {
+	defer conn.Close() // nolint:errcheck
+
+	reader := bufio.NewReader(conn)
+
+	for {
+		cmd, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		f.mu.Lock()
+		f.commands = append(f.commands, cmd)
+		reply := f.reply(cmd, len(f.commands))
+		f.mu.Unlock()
+
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+// reply returns the response to a command, and must be called with the lock held
+func (f *FakeRedis) reply(cmd []string, seq int) string {
+	switch strings.ToLower(cmd[0]) {
+	case "set":
+		if len(cmd) < 3 {
+			return "-ERR wrong number of arguments\r\n"
+		}
+
+		for _, arg := range cmd[3:] {
+			if _, ok := f.values[cmd[1]]; ok && strings.EqualFold(arg, "nx") {
+				return "$-1\r\n"
+			}
+		}
+
+		f.values[cmd[1]] = cmd[2]
+
+		return "+OK\r\n"
+	case "del", "exists":
+		count := 0
+
+		for _, key := range cmd[1:] {
+			if _, ok := f.values[key]; ok {
+				count++
+
+				if strings.EqualFold(cmd[0], "del") {
+					delete(f.values, key)
+				}
+			}
+		}
+
+		return fmt.Sprintf(":%d\r\n", count)
+	case "xadd":
+		id := fmt.Sprintf("%d-0", seq)
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(id), id)
+	case "xdel", "xtrim":
+		return ":0\r\n"
+	case "xrange", "xrevrange":
+		return "*0\r\n"
+	case "xread":
+		return "*-1\r\n"
+	default:
+		return "+OK\r\n"
+	}
+}
+
+// readCommand reads a command sent by a client, which is an array of bulk strings
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("unexpected command %q", line)
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("invalid command length %q", line)
+	}
+
+	cmd := make([]string, 0, n)
+
+	for i := 0; i < n; i++ {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		header = strings.TrimSpace(header)
+		if !strings.HasPrefix(header, "$") {
+			return nil, fmt.Errorf("unexpected argument %q", header)
+		}
+
+		size, err := strconv.Atoi(header[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+
+		cmd = append(cmd, string(buf[:size]))
+	}
+
+	return cmd, nil
+}
@@ -38,6 +38,8 @@ func NewAPIRouter(config *config.Config) *chi.Mux {
 				r.Method("DELETE", "/{workspace_id}/resource", state.NewDeleteResourceHandler(config))
 				r.Method("POST", "/{workspace_id}/error", state.NewReportErrorHandler(config))
 				r.Method("GET", "/{workspace_id}/credentials", credentials.NewCredentialsGetHandler(config))
+				r.Method("POST", "/{workspace_id}/tfplan", state.NewPlanUpdateHandler(config))
+				r.Method("GET", "/{workspace_id}/tfplan", state.NewPlanGetHandler(config))
 			})
 
 			// This group is meant to be called from Terraform via basic auth
@@ -75,6 +77,9 @@ func NewAPIRouter(config *config.Config) *chi.Mux {
 			r.Method("GET", "/projects/{project_id}/infras/{infra_id}/state", state.NewStateGetHandler(config))
 			r.Method("POST", "/projects/{project_id}/infras/{infra_id}/apply", provision.NewProvisionApplyHandler(config))
 			r.Method("DELETE", "/projects/{project_id}/infras/{infra_id}", provision.NewProvisionDestroyHandler(config))
+			r.Method("POST", "/projects/{project_id}/infras/{infra_id}/operations/{operation_id}/approve", provision.NewProvisionApproveHandler(config))
+			r.Method("POST", "/projects/{project_id}/infras/{infra_id}/drift_check", provision.NewProvisionDriftCheckHandler(config))
+			r.Method("POST", "/projects/{project_id}/infras/{infra_id}/reconcile", provision.NewProvisionReconcileHandler(config))
 		})
 	})
 
@@ -1,7 +1,27 @@
 package types
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
 const DefaultTerraformStateFile = "default.tfstate"
 
+// TerraformPlanFile returns the name of the file that the plan of an operation is saved to, which is the
+// plan that is applied once it has been approved
+func TerraformPlanFile(workspaceID string) string {
+	return fmt.Sprintf("%s.tfplan", workspaceID)
+}
+
+// StateChecksum returns the checksum of a raw terraform state file, which identifies the state that a plan
+// was created against
+func StateChecksum(state []byte) string {
+	sum := sha256.Sum256(state)
+
+	return hex.EncodeToString(sum[:])
+}
+
 type RawTFState struct {
 	Version          int         `json:"version"`
 	TerraformVersion string      `json:"terraform_version"`
@@ -16,6 +16,14 @@ const (
 	TFResourceDeleting      TFResourceStatus = "deleting"
 	TFResourceDeleted       TFResourceStatus = "deleted"
 	TFResourceErrored       TFResourceStatus = "errored"
+
+	// TFOperationPlanned is pushed to the state stream with the change summary once a plan
+	// has completed and is awaiting approval
+	TFOperationPlanned TFResourceStatus = "OPERATION_PLANNED"
+
+	// TFOperationChangeSummary is pushed to the state stream with the change summary once an
+	// apply or destroy has completed
+	TFOperationChangeSummary TFResourceStatus = "CHANGE_SUMMARY"
 )
 
 type TFResourceState struct {
@@ -24,6 +32,7 @@ type TFResourceState struct {
 	ID        string           `json:"id"`
 	Status    TFResourceStatus `json:"status"`
 	Error     *string          `json:"error"`
+	Changes   *Changes         `json:"changes,omitempty"`
 }
 
 type TFResourceStateEntry struct {
@@ -12,6 +12,7 @@ const (
 	ApplyErrored  TerraformEvent = "apply_errored"
 	ApplyComplete TerraformEvent = "apply_complete"
 	Diagnostic    TerraformEvent = "diagnostic"
+	ResourceDrift TerraformEvent = "resource_drift"
 )
 
 type DesiredTFState []Resource
@@ -45,6 +46,8 @@ func (t *TFLogLine) ToPBType() *pb.TerraformLog {
 		tfEventType = pb.TerraformEvent_APPLY_COMPLETE
 	case Diagnostic:
 		tfEventType = pb.TerraformEvent_DIAGNOSTIC
+	case ResourceDrift:
+		tfEventType = pb.TerraformEvent_RESOURCE_DRIFT
 	}
 
 	return &pb.TerraformLog{
@@ -113,6 +116,8 @@ func ToProvisionerType(pbTFLog *pb.TerraformLog) *TFLogLine {
 		tfEventType = ApplyComplete
 	case pb.TerraformEvent_DIAGNOSTIC:
 		tfEventType = Diagnostic
+	case pb.TerraformEvent_RESOURCE_DRIFT:
+		tfEventType = ResourceDrift
 	}
 
 	return &TFLogLine{
@@ -0,0 +1,182 @@
+//go:build ee
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/ee/integrations/vault"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/oauth"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/repository"
+	rcreds "github.com/porter-dev/porter/internal/repository/credentials"
+	rgorm "github.com/porter-dev/porter/internal/repository/gorm"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+/*
+
+                                  === Canary Reconciler Job ===
+
+   This job rolls back canaries which are no longer run by any API server, e.g. because the
+   server running them was restarted or redeployed in the middle of a canary.
+
+   A running canary updates its app event at least every porter_app.CanaryHeartbeatInterval. Any
+   canary which is still progressing, but whose event has not been updated for longer than
+   porter_app.CanaryOrphanedAfter, is orphaned: its resources are deleted so that all traffic
+   returns to the stable deployment, and its event is marked as rolled back.
+
+*/
+
+// orphanedCanaryReason is recorded on canaries which are rolled back by this job
+const orphanedCanaryReason = "the canary was interrupted before it finished, so all traffic was returned to the stable deployment"
+
+type canaryReconciler struct {
+	enqueueTime time.Time
+	db          *gorm.DB
+	doConf      *oauth2.Config
+	repo        repository.Repository
+}
+
+// CanaryReconcilerOpts holds the options required to run this job
+type CanaryReconcilerOpts struct {
+	DBConf         *env.DBConf
+	ServerURL      string
+	DOClientID     string
+	DOClientSecret string
+	DOScopes       []string
+}
+
+func NewCanaryReconciler(
+	db *gorm.DB,
+	enqueueTime time.Time,
+	opts *CanaryReconcilerOpts,
+) (*canaryReconciler, error) {
+	var credBackend rcreds.CredentialStorage
+
+	if opts.DBConf.VaultAPIKey != "" && opts.DBConf.VaultServerURL != "" && opts.DBConf.VaultPrefix != "" {
+		credBackend = vault.NewClient(
+			opts.DBConf.VaultServerURL,
+			opts.DBConf.VaultAPIKey,
+			opts.DBConf.VaultPrefix,
+		)
+	}
+
+	doConf := oauth.NewDigitalOceanClient(&oauth.Config{
+		ClientID:     opts.DOClientID,
+		ClientSecret: opts.DOClientSecret,
+		Scopes:       opts.DOScopes,
+		BaseURL:      opts.ServerURL,
+	})
+
+	var key [32]byte
+
+	for i, b := range []byte(opts.DBConf.EncryptionKey) {
+		key[i] = b
+	}
+
+	repo := rgorm.NewRepository(db, &key, credBackend)
+
+	return &canaryReconciler{enqueueTime, db, doConf, repo}, nil
+}
+
+func (n *canaryReconciler) ID() string {
+	return "canary-reconciler"
+}
+
+func (n *canaryReconciler) EnqueueTime() time.Time {
+	return n.enqueueTime
+}
+
+func (n *canaryReconciler) Run(ctx context.Context) error {
+	var events []*models.PorterAppEvent
+
+	orphanedBefore := time.Now().UTC().Add(-porter_app.CanaryOrphanedAfter)
+
+	if err := n.db.Where(
+		"type = ? AND status = ? AND updated_at < ?",
+		string(types.PorterAppEventType_Canary),
+		string(types.PorterAppEventStatus_Progressing),
+		orphanedBefore,
+	).Find(&events).Error; err != nil {
+		return fmt.Errorf("error listing progressing canaries: %w", err)
+	}
+
+	log.Printf("rolling back %d orphaned canaries", len(events))
+
+	for _, event := range events {
+		if err := n.rollBackCanary(ctx, event); err != nil {
+			log.Printf("error rolling back canary %s: %v", event.ID, err)
+		}
+	}
+
+	log.Println("finished rolling back orphaned canaries")
+
+	return nil
+}
+
+func (n *canaryReconciler) SetData([]byte) {}
+
+// rollBackCanary deletes the resources of an orphaned canary, and marks its event as rolled back
+func (n *canaryReconciler) rollBackCanary(ctx context.Context, event *models.PorterAppEvent) error {
+	canary, resources, err := porter_app.CanaryFromEventMetadata(event.Metadata)
+	if err != nil {
+		return err
+	}
+
+	canary.Result = types.AppCanaryResult_RolledBack
+	canary.Reason = orphanedCanaryReason
+
+	if resources.Namespace == "" {
+		// canaries started before their resources were recorded can only be marked as rolled back
+		canary.Reason = "the canary was interrupted before it finished, and its resources were not recorded so they could not be deleted"
+	} else if err := n.deleteCanaryResources(ctx, event, resources); err != nil {
+		// the event is left progressing, so that deleting the resources is retried on the next run
+		return err
+	}
+
+	metadata, err := porter_app.CanaryEventMetadata(canary, resources)
+	if err != nil {
+		return err
+	}
+
+	event.Status = string(types.PorterAppEventStatus_Failed)
+	event.Metadata = metadata
+	event.UpdatedAt = time.Now().UTC()
+
+	return n.repo.PorterAppEvent().UpdateEvent(ctx, event)
+}
+
+// deleteCanaryResources deletes the resources of a canary from the cluster of its app
+func (n *canaryReconciler) deleteCanaryResources(ctx context.Context, event *models.PorterAppEvent, resources porter_app.CanaryResources) error {
+	app, err := n.repo.PorterApp().ReadPorterAppByID(ctx, event.PorterAppID)
+	if err != nil {
+		return fmt.Errorf("error reading porter app %d: %w", event.PorterAppID, err)
+	}
+
+	cluster, err := n.repo.Cluster().ReadCluster(app.ProjectID, app.ClusterID)
+	if err != nil {
+		return fmt.Errorf("error reading cluster %d: %w", app.ClusterID, err)
+	}
+
+	k8sAgent, err := kubernetes.GetAgentOutOfClusterConfig(ctx, &kubernetes.OutOfClusterConfig{
+		Cluster:                   cluster,
+		Repo:                      n.repo,
+		DigitalOceanOAuth:         n.doConf,
+		AllowInClusterConnections: false,
+		Timeout:                   10 * time.Second,
+	})
+	if err != nil {
+		return fmt.Errorf("error getting k8s agent for cluster %s: %w", cluster.Name, err)
+	}
+
+	return porter_app.DeleteCanary(ctx, k8sAgent.Clientset, resources)
+}
@@ -0,0 +1,235 @@
+//go:build ee
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/api-contracts/generated/go/porter/v1/porterv1connect"
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/ee/integrations/vault"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/notifier"
+	"github.com/porter-dev/porter/internal/notifier/slack"
+	"github.com/porter-dev/porter/internal/oauth"
+	"github.com/porter-dev/porter/internal/repository"
+	rcreds "github.com/porter-dev/porter/internal/repository/credentials"
+	rgorm "github.com/porter-dev/porter/internal/repository/gorm"
+	"github.com/porter-dev/porter/internal/secretrotation"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+/*
+
+                                  === Env Group Secret Rotator Job ===
+
+   This job goes through every cluster, and rotates the env group keys whose rotation policy
+   is due, using the policy's built-in generator or rotation job.
+
+   The keys of an env group which are due together are written to a single new version, after
+   which the apps linked to the env group are rolled. Failed rotations are retried, and are
+   notified to the policy's Slack channels the first time they fail.
+
+*/
+
+type envGroupSecretRotator struct {
+	enqueueTime time.Time
+	db          *gorm.DB
+	doConf      *oauth2.Config
+	repo        repository.Repository
+	ccpClient   porterv1connect.ClusterControlPlaneServiceClient
+	serverURL   string
+}
+
+// EnvGroupSecretRotatorOpts holds the options required to run this job
+type EnvGroupSecretRotatorOpts struct {
+	DBConf                     *env.DBConf
+	ClusterControlPlaneAddress string
+	ServerURL                  string
+	DOClientID                 string
+	DOClientSecret             string
+	DOScopes                   []string
+}
+
+func NewEnvGroupSecretRotator(
+	db *gorm.DB,
+	enqueueTime time.Time,
+	opts *EnvGroupSecretRotatorOpts,
+) (*envGroupSecretRotator, error) {
+	if opts.ClusterControlPlaneAddress == "" {
+		return nil, fmt.Errorf("cluster control plane address must be set to rotate env group secrets")
+	}
+
+	var credBackend rcreds.CredentialStorage
+
+	if opts.DBConf.VaultAPIKey != "" && opts.DBConf.VaultServerURL != "" && opts.DBConf.VaultPrefix != "" {
+		credBackend = vault.NewClient(
+			opts.DBConf.VaultServerURL,
+			opts.DBConf.VaultAPIKey,
+			opts.DBConf.VaultPrefix,
+		)
+	}
+
+	doConf := oauth.NewDigitalOceanClient(&oauth.Config{
+		ClientID:     opts.DOClientID,
+		ClientSecret: opts.DOClientSecret,
+		Scopes:       opts.DOScopes,
+		BaseURL:      opts.ServerURL,
+	})
+
+	var key [32]byte
+
+	for i, b := range []byte(opts.DBConf.EncryptionKey) {
+		key[i] = b
+	}
+
+	repo := rgorm.NewRepository(db, &key, credBackend)
+
+	ccpClient := porterv1connect.NewClusterControlPlaneServiceClient(http.DefaultClient, opts.ClusterControlPlaneAddress)
+
+	return &envGroupSecretRotator{enqueueTime, db, doConf, repo, ccpClient, opts.ServerURL}, nil
+}
+
+func (n *envGroupSecretRotator) ID() string {
+	return "env-group-secret-rotator"
+}
+
+func (n *envGroupSecretRotator) EnqueueTime() time.Time {
+	return n.enqueueTime
+}
+
+func (n *envGroupSecretRotator) Run(ctx context.Context) error {
+	var count int64
+
+	if err := n.db.Model(&models.Cluster{}).Count(&count).Error; err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+
+	log.Println("starting rotation of env group secrets")
+
+	for i := 0; i < (int(count)/stepSize)+1; i++ {
+		var clusters []*models.Cluster
+
+		if err := n.db.Order("id asc").Offset(i * stepSize).Limit(stepSize).Find(&clusters).
+			Error; err != nil {
+			return err
+		}
+
+		for _, cluster := range clusters {
+			policies, err := n.repo.EnvGroupRotationPolicy().ListDueEnvGroupRotationPoliciesByClusterID(cluster.ID, time.Now().UTC())
+			if err != nil {
+				log.Printf("error listing due rotation policies for cluster %s: %v", cluster.Name, err)
+				continue
+			}
+
+			if len(policies) == 0 {
+				continue
+			}
+
+			wg.Add(1)
+
+			go func(cluster *models.Cluster, policies []*models.EnvGroupRotationPolicy) {
+				defer wg.Done()
+
+				n.rotateClusterSecrets(ctx, cluster, policies)
+			}(cluster, policies)
+		}
+
+		wg.Wait()
+	}
+
+	log.Println("finished rotation of env group secrets")
+
+	return nil
+}
+
+func (n *envGroupSecretRotator) SetData([]byte) {}
+
+// rotateClusterSecrets rotates the due keys of each env group in a cluster. Env groups are rotated one at a time, so that
+// the cluster does not roll every linked app at once.
+func (n *envGroupSecretRotator) rotateClusterSecrets(ctx context.Context, cluster *models.Cluster, policies []*models.EnvGroupRotationPolicy) {
+	k8sAgent, err := kubernetes.GetAgentOutOfClusterConfig(ctx, &kubernetes.OutOfClusterConfig{
+		Cluster:                   cluster,
+		Repo:                      n.repo,
+		DigitalOceanOAuth:         n.doConf,
+		AllowInClusterConnections: false,
+		Timeout:                   10 * time.Second,
+	})
+	if err != nil {
+		log.Printf("error getting k8s agent for cluster %s: %v", cluster.Name, err)
+		return
+	}
+
+	var envGroupNames []string
+	byEnvGroup := make(map[string][]*models.EnvGroupRotationPolicy)
+	for _, policy := range policies {
+		if _, ok := byEnvGroup[policy.EnvGroupName]; !ok {
+			envGroupNames = append(envGroupNames, policy.EnvGroupName)
+		}
+		byEnvGroup[policy.EnvGroupName] = append(byEnvGroup[policy.EnvGroupName], policy)
+	}
+
+	for _, envGroupName := range envGroupNames {
+		out, err := secretrotation.RotateEnvironmentGroup(ctx, secretrotation.RotateEnvironmentGroupInput{
+			Agent:        k8sAgent,
+			CCPClient:    n.ccpClient,
+			ProjectID:    cluster.ProjectID,
+			ClusterID:    cluster.ID,
+			EnvGroupName: envGroupName,
+			Policies:     byEnvGroup[envGroupName],
+		})
+		if err != nil {
+			log.Printf("error rotating env group %s in cluster %s: %v", envGroupName, cluster.Name, err)
+		}
+
+		now := time.Now().UTC()
+
+		for _, result := range out.Results {
+			notify := secretrotation.RecordResult(result, out.Version, now)
+
+			if _, err := n.repo.EnvGroupRotationPolicy().UpdateEnvGroupRotationPolicy(result.Policy); err != nil {
+				log.Printf("error recording rotation of key %s of env group %s: %v", result.Policy.Key, envGroupName, err)
+			}
+
+			if notify {
+				if err := n.notifyRotationFailure(cluster, result.Policy, now); err != nil {
+					log.Printf("error sending notification for rotation of key %s of env group %s: %v", result.Policy.Key, envGroupName, err)
+				}
+			}
+		}
+	}
+}
+
+func (n *envGroupSecretRotator) notifyRotationFailure(cluster *models.Cluster, policy *models.EnvGroupRotationPolicy, now time.Time) error {
+	policyType, err := policy.ToEnvGroupRotationPolicyType()
+	if err != nil {
+		return err
+	}
+
+	slackInts, err := n.repo.SlackIntegration().ListSlackIntegrationsByProjectID(policy.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	multi := notifier.NewMultiEnvGroupRotationNotifier(slack.NewEnvGroupRotationNotifier(ruleSlackIntegrations(slackInts, policyType.Channels)...))
+
+	url := fmt.Sprintf("%s/environment-groups/%s?project_id=%d&cluster_id=%d", n.serverURL, policy.EnvGroupName, policy.ProjectID, cluster.ID)
+
+	return multi.NotifyRotationFailure(&types.EnvGroupRotationFailureNotification{
+		EnvGroupName: policy.EnvGroupName,
+		ClusterName:  cluster.Name,
+		Policy:       policyType,
+		Error:        policy.LastError,
+		FailedAt:     now,
+	}, url)
+}
@@ -0,0 +1,327 @@
+//go:build ee
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/ee/integrations/vault"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	porter_agent "github.com/porter-dev/porter/internal/kubernetes/porter_agent/v2"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/notifier"
+	"github.com/porter-dev/porter/internal/notifier/slack"
+	"github.com/porter-dev/porter/internal/oauth"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/repository"
+	rcreds "github.com/porter-dev/porter/internal/repository/credentials"
+	rgorm "github.com/porter-dev/porter/internal/repository/gorm"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+	v1 "k8s.io/api/core/v1"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+/*
+
+                                  === Log Alert Evaluator Job ===
+
+   This job goes through every log alert rule declared on porter apps, and counts the lines
+   matching the rule's query within the rule's window using the porter agent's historical logs.
+
+   A notification with the most recent matching lines is sent to the rule's Slack channels when
+   the count goes above the rule's threshold, and again every hour for as long as it stays above.
+
+*/
+
+type logAlertEvaluator struct {
+	enqueueTime time.Time
+	db          *gorm.DB
+	doConf      *oauth2.Config
+	repo        repository.Repository
+	serverURL   string
+}
+
+// LogAlertEvaluatorOpts holds the options required to run this job
+type LogAlertEvaluatorOpts struct {
+	DBConf         *env.DBConf
+	ServerURL      string
+	DOClientID     string
+	DOClientSecret string
+	DOScopes       []string
+}
+
+func NewLogAlertEvaluator(
+	db *gorm.DB,
+	enqueueTime time.Time,
+	opts *LogAlertEvaluatorOpts,
+) (*logAlertEvaluator, error) {
+	var credBackend rcreds.CredentialStorage
+
+	if opts.DBConf.VaultAPIKey != "" && opts.DBConf.VaultServerURL != "" && opts.DBConf.VaultPrefix != "" {
+		credBackend = vault.NewClient(
+			opts.DBConf.VaultServerURL,
+			opts.DBConf.VaultAPIKey,
+			opts.DBConf.VaultPrefix,
+		)
+	}
+
+	doConf := oauth.NewDigitalOceanClient(&oauth.Config{
+		ClientID:     opts.DOClientID,
+		ClientSecret: opts.DOClientSecret,
+		Scopes:       opts.DOScopes,
+		BaseURL:      opts.ServerURL,
+	})
+
+	var key [32]byte
+
+	for i, b := range []byte(opts.DBConf.EncryptionKey) {
+		key[i] = b
+	}
+
+	repo := rgorm.NewRepository(db, &key, credBackend)
+
+	return &logAlertEvaluator{enqueueTime, db, doConf, repo, opts.ServerURL}, nil
+}
+
+func (n *logAlertEvaluator) ID() string {
+	return "log-alert-evaluator"
+}
+
+func (n *logAlertEvaluator) EnqueueTime() time.Time {
+	return n.enqueueTime
+}
+
+func (n *logAlertEvaluator) Run(ctx context.Context) error {
+	var count int64
+
+	if err := n.db.Model(&models.Cluster{}).Count(&count).Error; err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+
+	log.Println("starting evaluation of app log alert rules")
+
+	for i := 0; i < (int(count)/stepSize)+1; i++ {
+		var clusters []*models.Cluster
+
+		if err := n.db.Order("id asc").Offset(i * stepSize).Limit(stepSize).Find(&clusters).
+			Error; err != nil {
+			return err
+		}
+
+		for _, cluster := range clusters {
+			rules, err := n.repo.AppLogAlertRule().ListAppLogAlertRulesByClusterID(cluster.ID)
+			if err != nil {
+				log.Printf("error listing log alert rules for cluster %s: %v", cluster.Name, err)
+				continue
+			}
+
+			if len(rules) == 0 {
+				continue
+			}
+
+			wg.Add(1)
+
+			go func(cluster *models.Cluster, rules []*models.AppLogAlertRule) {
+				defer wg.Done()
+
+				n.evaluateClusterLogAlertRules(ctx, cluster, rules)
+			}(cluster, rules)
+		}
+
+		wg.Wait()
+	}
+
+	log.Println("finished evaluation of app log alert rules")
+
+	return nil
+}
+
+func (n *logAlertEvaluator) SetData([]byte) {}
+
+// evaluateClusterLogAlertRules evaluates each log alert rule in a cluster against the cluster's porter agent
+func (n *logAlertEvaluator) evaluateClusterLogAlertRules(ctx context.Context, cluster *models.Cluster, rules []*models.AppLogAlertRule) {
+	k8sAgent, err := kubernetes.GetAgentOutOfClusterConfig(ctx, &kubernetes.OutOfClusterConfig{
+		Cluster:                   cluster,
+		Repo:                      n.repo,
+		DigitalOceanOAuth:         n.doConf,
+		AllowInClusterConnections: false,
+		Timeout:                   10 * time.Second,
+	})
+	if err != nil {
+		log.Printf("error getting k8s agent for cluster %s: %v", cluster.Name, err)
+		return
+	}
+
+	agentSvc, err := porter_agent.GetAgentService(k8sAgent.Clientset)
+	if err != nil {
+		log.Printf("error getting porter agent service for cluster %s: %v", cluster.Name, err)
+		return
+	}
+
+	appNames := make(map[uint]string)
+
+	for _, rule := range rules {
+		appName, ok := appNames[rule.PorterAppID]
+		if !ok {
+			app, err := n.repo.PorterApp().ReadPorterAppByID(ctx, rule.PorterAppID)
+			if err != nil {
+				log.Printf("error reading porter app %d for log alert rule %d: %v", rule.PorterAppID, rule.ID, err)
+				continue
+			}
+
+			appName = app.Name
+			appNames[rule.PorterAppID] = appName
+		}
+
+		query, err := porter_app.ParseLogQuery(rule.Query)
+		if err != nil {
+			log.Printf("error parsing query of log alert rule %d of app %s: %v", rule.ID, appName, err)
+			continue
+		}
+
+		now := time.Now().UTC()
+
+		result, err := porter_app.EvaluateLogAlertRule(ctx, porter_app.EvaluateLogAlertRuleInput{
+			Query:     query,
+			Threshold: rule.Threshold,
+			Window:    time.Duration(rule.WindowMinutes) * time.Minute,
+			Now:       now,
+			Fetch: historicalLogFetcher(
+				k8sAgent.Clientset,
+				agentSvc,
+				rule.Namespace,
+				porter_app.LogAlertPodSelector(appName, rule.ServiceName),
+				query.SearchParam(),
+			),
+		})
+		if err != nil {
+			log.Printf("error evaluating log alert rule %d of app %s: %v", rule.ID, appName, err)
+			continue
+		}
+
+		if err := n.recordLogAlertResult(rule, appName, result, now); err != nil {
+			log.Printf("error recording result of log alert rule %d of app %s: %v", rule.ID, appName, err)
+		}
+	}
+}
+
+// historicalLogFetcher reads logs from the historical log API of the porter agent
+func historicalLogFetcher(
+	clientset k8s.Interface,
+	agentSvc *v1.Service,
+	namespace, podSelector, searchParam string,
+) porter_app.LogFetcher {
+	return func(ctx context.Context, inp porter_app.LogFetchInput) ([]porter_app.StructuredLog, error) {
+		logs, err := porter_agent.GetHistoricalLogs(ctx, clientset, agentSvc, &types.GetLogRequest{
+			Limit:       inp.Limit,
+			StartRange:  &inp.Start,
+			EndRange:    &inp.End,
+			SearchParam: searchParam,
+			PodSelector: podSelector,
+			Namespace:   namespace,
+			Direction:   inp.Direction,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if logs == nil {
+			return nil, nil
+		}
+
+		return porter_app.AgentLogToStructuredLog(logs.Logs), nil
+	}
+}
+
+// recordLogAlertResult stores the result of an evaluation, and notifies if the rule started firing or has been firing
+// since it was last notified longer than the repeat interval
+func (n *logAlertEvaluator) recordLogAlertResult(
+	rule *models.AppLogAlertRule,
+	appName string,
+	result porter_app.EvaluateLogAlertRuleResult,
+	now time.Time,
+) error {
+	notify := porter_app.ShouldNotifyLogAlert(rule.LastState, rule.LastFiredAt, result.Firing, now)
+
+	rule.LastState = types.AppLogAlertState_OK
+	if result.Firing {
+		rule.LastState = types.AppLogAlertState_Firing
+	}
+	rule.LastMatchCount = result.MatchCount
+	rule.LastCheckedAt = &now
+
+	if notify {
+		if err := n.notifyLogAlert(rule, appName, result, now); err != nil {
+			// a failed notification is retried on the next evaluation
+			log.Printf("error sending notification for log alert rule %d of app %s: %v", rule.ID, appName, err)
+			rule.LastState = types.AppLogAlertState_OK
+		} else {
+			rule.LastFiredAt = &now
+		}
+	}
+
+	_, err := n.repo.AppLogAlertRule().UpdateAppLogAlertRule(rule)
+
+	return err
+}
+
+func (n *logAlertEvaluator) notifyLogAlert(
+	rule *models.AppLogAlertRule,
+	appName string,
+	result porter_app.EvaluateLogAlertRuleResult,
+	now time.Time,
+) error {
+	ruleType, err := rule.ToAppLogAlertRuleType()
+	if err != nil {
+		return err
+	}
+
+	slackInts, err := n.repo.SlackIntegration().ListSlackIntegrationsByProjectID(rule.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	multi := notifier.NewMultiAppLogAlertNotifier(slack.NewAppLogAlertNotifier(ruleSlackIntegrations(slackInts, ruleType.Channels)...))
+
+	url := fmt.Sprintf("%s/apps/%s/logs?project_id=%d", n.serverURL, appName, rule.ProjectID)
+
+	return multi.NotifyLogAlert(&types.AppLogAlertNotification{
+		AppName:     appName,
+		Rule:        ruleType,
+		MatchCount:  result.MatchCount,
+		SampleLines: result.SampleLines,
+		FiredAt:     now,
+	}, url)
+}
+
+// ruleSlackIntegrations returns the Slack integrations posting to one of the given channels, or every integration if
+// no channels are given
+func ruleSlackIntegrations(slackInts []*integrations.SlackIntegration, channels []string) []*integrations.SlackIntegration {
+	if len(channels) == 0 {
+		return slackInts
+	}
+
+	wanted := make(map[string]bool)
+	for _, channel := range channels {
+		wanted[strings.TrimPrefix(channel, "#")] = true
+	}
+
+	var res []*integrations.SlackIntegration
+	for _, slackInt := range slackInts {
+		if wanted[strings.TrimPrefix(slackInt.Channel, "#")] {
+			res = append(res, slackInt)
+		}
+	}
+
+	return res
+}
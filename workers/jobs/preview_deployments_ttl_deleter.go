@@ -5,20 +5,25 @@ package jobs
 import (
 	"context"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/ee/integrations/vault"
 	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/prometheus"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/oauth"
+	"github.com/porter-dev/porter/internal/previewpolicy"
 	"github.com/porter-dev/porter/internal/repository"
 	rcreds "github.com/porter-dev/porter/internal/repository/credentials"
 	rgorm "github.com/porter-dev/porter/internal/repository/gorm"
 	"golang.org/x/oauth2"
 	"gorm.io/gorm"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 /*
@@ -27,6 +32,8 @@ import (
 
    This job goes through every active preview environment in all connected clusters and deletes the
    deployments that have exceeded their TTL, corresponding to their respective preview environment.
+   Environments with a preview policy override the TTL, evict their least recently used deployments
+   once they have too many, and put idle deployments to sleep or sleep them on a schedule.
 
 */
 
@@ -40,6 +47,7 @@ type previewDeploymentsTTLDeleter struct {
 	doConf                *oauth2.Config
 	repo                  repository.Repository
 	previewDeploymentsTTL string
+	previewWaker          previewpolicy.WakerBackend
 }
 
 // PreviewDeploymentsTTLDeleterOpts holds the options required to run this job
@@ -50,6 +58,10 @@ type PreviewDeploymentsTTLDeleterOpts struct {
 	DOClientSecret        string
 	DOScopes              []string
 	PreviewDeploymentsTTL string
+	// PreviewWakerHost is the host of the preview waker, which sleeping deployments route requests to. Deployments
+	// are not put to sleep if it is not set.
+	PreviewWakerHost string
+	PreviewWakerPort int
 }
 
 func NewPreviewDeploymentsTTLDeleter(
@@ -82,7 +94,12 @@ func NewPreviewDeploymentsTTLDeleter(
 
 	repo := rgorm.NewRepository(db, &key, credBackend)
 
-	return &previewDeploymentsTTLDeleter{enqueueTime, db, doConf, repo, opts.PreviewDeploymentsTTL}, nil
+	previewWaker := previewpolicy.WakerBackend{
+		Host: opts.PreviewWakerHost,
+		Port: int32(opts.PreviewWakerPort),
+	}
+
+	return &previewDeploymentsTTLDeleter{enqueueTime, db, doConf, repo, opts.PreviewDeploymentsTTL, previewWaker}, nil
 }
 
 func (n *previewDeploymentsTTLDeleter) ID() string {
@@ -94,20 +111,17 @@ func (n *previewDeploymentsTTLDeleter) EnqueueTime() time.Time {
 }
 
 func (n *previewDeploymentsTTLDeleter) Run(ctx context.Context) error {
-	if n.previewDeploymentsTTL == "" {
-		log.Println("no TTL set for preview deployments, skipping job altogether")
-		return nil
-	}
-
-	ttlDuration, err := time.ParseDuration(n.previewDeploymentsTTL)
-	if err != nil {
-		log.Printf("error parsing preview deployments TTL: %v. skipping job altogether", err)
-		return nil
-	}
-
-	if ttlDuration.Hours() < 24 || ttlDuration.Hours() > 720 {
-		log.Printf("preview deployments TTL must be between 24 (1 day) and 720 hours (30 days). skipping job altogether")
-		return nil
+	var defaultTTL time.Duration
+
+	if n.previewDeploymentsTTL != "" {
+		ttlDuration, err := time.ParseDuration(n.previewDeploymentsTTL)
+		if err != nil {
+			log.Printf("error parsing preview deployments TTL: %v. only environment TTLs will be applied", err)
+		} else if ttlDuration.Hours() < 24 || ttlDuration.Hours() > 720 {
+			log.Printf("preview deployments TTL must be between 24 (1 day) and 720 hours (30 days). only environment TTLs will be applied")
+		} else {
+			defaultTTL = ttlDuration
+		}
 	}
 
 	var count int64
@@ -142,6 +156,12 @@ func (n *previewDeploymentsTTLDeleter) Run(ctx context.Context) error {
 			log.Printf("found %d environments for cluster %s", len(envs), cluster.Name)
 
 			for _, env := range envs {
+				policy := env.PreviewPolicy()
+
+				if defaultTTL == 0 && policy == nil {
+					continue
+				}
+
 				wg.Add(1)
 
 				go func(env *models.Environment, cluster *models.Cluster) {
@@ -155,9 +175,6 @@ func (n *previewDeploymentsTTLDeleter) Run(ctx context.Context) error {
 
 					log.Printf("found %d deployments for %s/%s", len(depls), env.GitRepoOwner, env.GitRepoName)
 
-					log.Printf("deleting preview deployments based on TTL %s for %s/%s",
-						n.previewDeploymentsTTL, env.GitRepoOwner, env.GitRepoName)
-
 					k8sAgent, err := kubernetes.GetAgentOutOfClusterConfig(ctx, &kubernetes.OutOfClusterConfig{
 						Cluster:                   cluster,
 						Repo:                      n.repo,
@@ -170,36 +187,7 @@ func (n *previewDeploymentsTTLDeleter) Run(ctx context.Context) error {
 						return
 					}
 
-					for _, depl := range depls {
-						// delete the deployment if it has been inactive for longer than the set TTL
-						if depl.UpdatedAt.Add(ttlDuration).Before(time.Now()) {
-							if depl.Namespace != "" {
-								log.Printf("deleting namespace for deployment '%s'", depl.PRName)
-
-								_, err := k8sAgent.GetNamespace(depl.Namespace)
-
-								if err == nil {
-									err := k8sAgent.DeleteNamespace(depl.Namespace)
-									if err != nil {
-										log.Printf("error deleting namespace for deployment '%s': %v. skipping ...",
-											depl.PRName, err)
-										continue
-									}
-								} else if !errors.IsNotFound(err) {
-									log.Printf("error getting k8s namespace for deployment '%s': %v. skipping ...",
-										depl.PRName, err)
-									continue
-								}
-							}
-
-							log.Printf("deleting deployment '%s'", depl.PRName)
-
-							_, err := n.repo.Environment().DeleteDeployment(depl)
-							if err != nil {
-								log.Printf("error deleting deployment '%s': %v", depl.PRName, err)
-							}
-						}
-					}
+					n.applyPolicy(ctx, k8sAgent, env, policy, defaultTTL, depls)
 				}(env, cluster)
 			}
 
@@ -212,4 +200,211 @@ func (n *previewDeploymentsTTLDeleter) Run(ctx context.Context) error {
 	return nil
 }
 
+// applyPolicy deletes the deployments of an environment which have exceeded their TTL or the maximum number of
+// concurrent previews, and puts the remaining deployments to sleep or wakes them up
+func (n *previewDeploymentsTTLDeleter) applyPolicy(
+	ctx context.Context,
+	k8sAgent *kubernetes.Agent,
+	env *models.Environment,
+	policy *types.PreviewEnvironmentPolicy,
+	defaultTTL time.Duration,
+	depls []*models.Deployment,
+) {
+	now := time.Now().UTC()
+	ttl := previewpolicy.TTL(policy, defaultTTL)
+
+	remaining := make([]*models.Deployment, 0, len(depls))
+
+	for _, depl := range depls {
+		// deployments created before pushes were recorded are counted from their last update, which was the
+		// TTL of the deployment before environments could set their own
+		if depl.LastPushedAt == nil {
+			updatedAt := depl.UpdatedAt
+			depl.LastPushedAt = &updatedAt
+
+			if _, err := n.repo.Environment().UpdateDeployment(depl); err != nil {
+				log.Printf("error recording last push of deployment '%s': %v", depl.PRName, err)
+			}
+		}
+
+		// delete the deployment if it has not been pushed to for longer than the TTL
+		if previewpolicy.Expired(depl, ttl, now) {
+			log.Printf("deleting deployment '%s' based on TTL %s for %s/%s", depl.PRName, ttl, env.GitRepoOwner, env.GitRepoName)
+			n.deleteDeployment(k8sAgent, depl)
+			continue
+		}
+
+		remaining = append(remaining, depl)
+	}
+
+	if policy == nil {
+		return
+	}
+
+	evicted := make(map[uint]bool)
+
+	for _, depl := range previewpolicy.EvictionCandidates(policy.MaxConcurrentPreviews, remaining) {
+		log.Printf("deleting least recently used deployment '%s' since %s/%s has more than %d previews",
+			depl.PRName, env.GitRepoOwner, env.GitRepoName, policy.MaxConcurrentPreviews)
+		n.deleteDeployment(k8sAgent, depl)
+		evicted[depl.ID] = true
+	}
+
+	for _, depl := range remaining {
+		if evicted[depl.ID] || depl.Namespace == "" || depl.Status == types.DeploymentStatusInactive {
+			continue
+		}
+
+		if err := n.applySleepPolicy(ctx, k8sAgent, policy, depl, now); err != nil {
+			log.Printf("error applying sleep policy to deployment '%s': %v", depl.PRName, err)
+		}
+	}
+}
+
+// applySleepPolicy puts an awake deployment to sleep if it is idle or within the sleep schedule of its environment,
+// and wakes up a sleeping deployment once the schedule which put it to sleep has ended
+func (n *previewDeploymentsTTLDeleter) applySleepPolicy(
+	ctx context.Context,
+	k8sAgent *kubernetes.Agent,
+	policy *types.PreviewEnvironmentPolicy,
+	depl *models.Deployment,
+	now time.Time,
+) error {
+	if depl.SleepingSince != nil {
+		wake, err := previewpolicy.ShouldWake(policy, depl, now)
+		if err != nil || !wake {
+			return err
+		}
+
+		log.Printf("waking deployment '%s'", depl.PRName)
+
+		if err := previewpolicy.Wake(ctx, k8sAgent.Clientset, depl.Namespace); err != nil {
+			return err
+		}
+
+		depl.SleepingSince = nil
+		depl.SleepReason = ""
+
+		_, err = n.repo.Environment().UpdateDeployment(depl)
+		return err
+	}
+
+	// deployments which were woken up or pushed to keep routing requests to the waker until they are ready
+	if depl.SleepingHosts != "" {
+		if err := previewpolicy.Wake(ctx, k8sAgent.Clientset, depl.Namespace); err != nil {
+			return err
+		}
+
+		removed, err := previewpolicy.RemoveWakerBackend(ctx, k8sAgent.Clientset, depl.Namespace)
+		if err != nil || !removed {
+			return err
+		}
+
+		depl.SleepingHosts = ""
+
+		if _, err := n.repo.Environment().UpdateDeployment(depl); err != nil {
+			return err
+		}
+	}
+
+	if policy.SleepAfterIdleHours == 0 && policy.SleepSchedule == nil {
+		return nil
+	}
+
+	if n.previewWaker.Host == "" {
+		log.Printf("no preview waker host is set, skipping sleep policy of deployment '%s'", depl.PRName)
+		return nil
+	}
+
+	idle, err := n.idle(ctx, k8sAgent, policy, depl, now)
+	if err != nil {
+		return err
+	}
+
+	reason, err := previewpolicy.ShouldSleep(policy, depl, idle, now)
+	if err != nil || reason == "" {
+		return err
+	}
+
+	log.Printf("putting deployment '%s' to sleep, reason: %s", depl.PRName, reason)
+
+	hosts, err := previewpolicy.Sleep(ctx, k8sAgent.Clientset, depl.Namespace, n.previewWaker)
+	if err != nil {
+		return err
+	}
+
+	depl.SleepingSince = &now
+	depl.SleepReason = string(reason)
+	depl.SleepingHosts = strings.Join(hosts, ",")
+
+	_, err = n.repo.Environment().UpdateDeployment(depl)
+	return err
+}
+
+// idle returns true if the ingresses of a deployment have not received a request within the idle period of its
+// environment. Deployments without ingresses are never idle, since they could not be woken up by a request.
+func (n *previewDeploymentsTTLDeleter) idle(
+	ctx context.Context,
+	k8sAgent *kubernetes.Agent,
+	policy *types.PreviewEnvironmentPolicy,
+	depl *models.Deployment,
+	now time.Time,
+) (bool, error) {
+	if !previewpolicy.IdleCheckDue(policy, depl, now) {
+		return false, nil
+	}
+
+	ingresses, err := k8sAgent.Clientset.NetworkingV1().Ingresses(depl.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	if len(ingresses.Items) == 0 {
+		return false, nil
+	}
+
+	promSvc, found, err := prometheus.GetPrometheusService(k8sAgent.Clientset)
+	if err != nil || !found {
+		return false, err
+	}
+
+	requests, found, err := prometheus.NamespaceRequestCount(
+		ctx, k8sAgent.Clientset, promSvc, depl.Namespace, time.Duration(policy.SleepAfterIdleHours)*time.Hour,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return !found || requests < 1, nil
+}
+
+// deleteDeployment deletes the namespace of a deployment and then the deployment itself
+func (n *previewDeploymentsTTLDeleter) deleteDeployment(k8sAgent *kubernetes.Agent, depl *models.Deployment) {
+	if depl.Namespace != "" {
+		log.Printf("deleting namespace for deployment '%s'", depl.PRName)
+
+		_, err := k8sAgent.GetNamespace(depl.Namespace)
+
+		if err == nil {
+			err := k8sAgent.DeleteNamespace(depl.Namespace)
+			if err != nil {
+				log.Printf("error deleting namespace for deployment '%s': %v. skipping ...",
+					depl.PRName, err)
+				return
+			}
+		} else if !errors.IsNotFound(err) {
+			log.Printf("error getting k8s namespace for deployment '%s': %v. skipping ...",
+				depl.PRName, err)
+			return
+		}
+	}
+
+	log.Printf("deleting deployment '%s'", depl.PRName)
+
+	_, err := n.repo.Environment().DeleteDeployment(depl)
+	if err != nil {
+		log.Printf("error deleting deployment '%s': %v", depl.PRName, err)
+	}
+}
+
 func (n *previewDeploymentsTTLDeleter) SetData([]byte) {}
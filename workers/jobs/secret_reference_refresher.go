@@ -0,0 +1,173 @@
+//go:build ee
+
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/ee/integrations/vault"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/oauth"
+	"github.com/porter-dev/porter/internal/repository"
+	rcreds "github.com/porter-dev/porter/internal/repository/credentials"
+	rgorm "github.com/porter-dev/porter/internal/repository/gorm"
+	"github.com/porter-dev/porter/internal/secretref"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+/*
+
+                                  === Secret Reference Refresher Job ===
+
+   This job goes through every cluster, and resolves the secret references of the environment
+   groups synced to app namespaces whose refresh interval has passed.
+
+   Resolved values are written to the synced secrets in place, and are read by pods when they
+   next start. References which fail to resolve keep their current value.
+
+*/
+
+type secretReferenceRefresher struct {
+	enqueueTime time.Time
+	db          *gorm.DB
+	doConf      *oauth2.Config
+	repo        repository.Repository
+	refConf     env.SecretReferenceConf
+}
+
+// SecretReferenceRefresherOpts holds the options required to run this job
+type SecretReferenceRefresherOpts struct {
+	DBConf              *env.DBConf
+	SecretReferenceConf env.SecretReferenceConf
+	ServerURL           string
+	DOClientID          string
+	DOClientSecret      string
+	DOScopes            []string
+}
+
+func NewSecretReferenceRefresher(
+	db *gorm.DB,
+	enqueueTime time.Time,
+	opts *SecretReferenceRefresherOpts,
+) (*secretReferenceRefresher, error) {
+	var credBackend rcreds.CredentialStorage
+
+	if opts.DBConf.VaultAPIKey != "" && opts.DBConf.VaultServerURL != "" && opts.DBConf.VaultPrefix != "" {
+		credBackend = vault.NewClient(
+			opts.DBConf.VaultServerURL,
+			opts.DBConf.VaultAPIKey,
+			opts.DBConf.VaultPrefix,
+		)
+	}
+
+	doConf := oauth.NewDigitalOceanClient(&oauth.Config{
+		ClientID:     opts.DOClientID,
+		ClientSecret: opts.DOClientSecret,
+		Scopes:       opts.DOScopes,
+		BaseURL:      opts.ServerURL,
+	})
+
+	var key [32]byte
+
+	for i, b := range []byte(opts.DBConf.EncryptionKey) {
+		key[i] = b
+	}
+
+	repo := rgorm.NewRepository(db, &key, credBackend)
+
+	return &secretReferenceRefresher{enqueueTime, db, doConf, repo, opts.SecretReferenceConf}, nil
+}
+
+func (n *secretReferenceRefresher) ID() string {
+	return "secret-reference-refresher"
+}
+
+func (n *secretReferenceRefresher) EnqueueTime() time.Time {
+	return n.enqueueTime
+}
+
+func (n *secretReferenceRefresher) Run(ctx context.Context) error {
+	var count int64
+
+	if err := n.db.Model(&models.Cluster{}).Count(&count).Error; err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+
+	log.Println("starting refresh of environment group secret references")
+
+	for i := 0; i < (int(count)/stepSize)+1; i++ {
+		var clusters []*models.Cluster
+
+		if err := n.db.Order("id asc").Offset(i * stepSize).Limit(stepSize).Find(&clusters).
+			Error; err != nil {
+			return err
+		}
+
+		for _, cluster := range clusters {
+			wg.Add(1)
+
+			go func(cluster *models.Cluster) {
+				defer wg.Done()
+
+				n.refreshClusterSecretReferences(ctx, cluster)
+			}(cluster)
+		}
+
+		wg.Wait()
+	}
+
+	log.Println("finished refresh of environment group secret references")
+
+	return nil
+}
+
+func (n *secretReferenceRefresher) SetData([]byte) {}
+
+// refreshClusterSecretReferences refreshes the due secret references of every environment group synced in a cluster
+func (n *secretReferenceRefresher) refreshClusterSecretReferences(ctx context.Context, cluster *models.Cluster) {
+	k8sAgent, err := kubernetes.GetAgentOutOfClusterConfig(ctx, &kubernetes.OutOfClusterConfig{
+		Cluster:                   cluster,
+		Repo:                      n.repo,
+		DigitalOceanOAuth:         n.doConf,
+		AllowInClusterConnections: false,
+		Timeout:                   10 * time.Second,
+	})
+	if err != nil {
+		log.Printf("error getting k8s agent for cluster %s: %v", cluster.Name, err)
+		return
+	}
+
+	inp := secretref.NewClusterResolverInput{
+		Cluster:           cluster,
+		Repo:              n.repo,
+		VaultMount:        n.refConf.SecretReferenceVaultMount,
+		VaultProjectsPath: n.refConf.SecretReferenceVaultProjectsPath,
+	}
+	if n.refConf.SecretReferenceVaultServerURL != "" && n.refConf.SecretReferenceVaultToken != "" {
+		inp.Vault = vault.NewClient(n.refConf.SecretReferenceVaultServerURL, n.refConf.SecretReferenceVaultToken, "")
+	}
+
+	resolver, err := secretref.NewClusterResolver(ctx, inp)
+	if err != nil {
+		log.Printf("error creating secret reference resolver for cluster %s: %v", cluster.Name, err)
+		return
+	}
+
+	refreshed, err := environment_groups.RefreshDueSecretReferences(ctx, k8sAgent, resolver)
+	if err != nil {
+		log.Printf("error refreshing secret references in cluster %s: %v", cluster.Name, err)
+	}
+
+	if refreshed > 0 {
+		log.Printf("refreshed secret references of %d synced environment groups in cluster %s", refreshed, cluster.Name)
+	}
+}
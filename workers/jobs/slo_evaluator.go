@@ -0,0 +1,280 @@
+//go:build ee
+
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/ee/integrations/vault"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/prometheus"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/notifier"
+	"github.com/porter-dev/porter/internal/notifier/slack"
+	"github.com/porter-dev/porter/internal/oauth"
+	"github.com/porter-dev/porter/internal/repository"
+	rcreds "github.com/porter-dev/porter/internal/repository/credentials"
+	rgorm "github.com/porter-dev/porter/internal/repository/gorm"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+/*
+
+                                  === SLO Evaluator Job ===
+
+   This job goes through every SLO declared on the web services of porter apps, computes the
+   remaining error budget and the multi-window burn rates of each SLO from the ingress metrics
+   in the cluster's Prometheus, and stores the result in the SLO's status history.
+
+   A notification is sent to the project's Slack integrations when an SLO starts burning its
+   budget, and again if the burn escalates. Once the SLO recovers, the next burn is notified again.
+
+*/
+
+// sloStatusRetention is how long the status history of an SLO is kept
+const sloStatusRetention = 90 * 24 * time.Hour
+
+type sloEvaluator struct {
+	enqueueTime time.Time
+	db          *gorm.DB
+	doConf      *oauth2.Config
+	repo        repository.Repository
+	serverURL   string
+}
+
+// SLOEvaluatorOpts holds the options required to run this job
+type SLOEvaluatorOpts struct {
+	DBConf         *env.DBConf
+	ServerURL      string
+	DOClientID     string
+	DOClientSecret string
+	DOScopes       []string
+}
+
+func NewSLOEvaluator(
+	db *gorm.DB,
+	enqueueTime time.Time,
+	opts *SLOEvaluatorOpts,
+) (*sloEvaluator, error) {
+	var credBackend rcreds.CredentialStorage
+
+	if opts.DBConf.VaultAPIKey != "" && opts.DBConf.VaultServerURL != "" && opts.DBConf.VaultPrefix != "" {
+		credBackend = vault.NewClient(
+			opts.DBConf.VaultServerURL,
+			opts.DBConf.VaultAPIKey,
+			opts.DBConf.VaultPrefix,
+		)
+	}
+
+	doConf := oauth.NewDigitalOceanClient(&oauth.Config{
+		ClientID:     opts.DOClientID,
+		ClientSecret: opts.DOClientSecret,
+		Scopes:       opts.DOScopes,
+		BaseURL:      opts.ServerURL,
+	})
+
+	var key [32]byte
+
+	for i, b := range []byte(opts.DBConf.EncryptionKey) {
+		key[i] = b
+	}
+
+	repo := rgorm.NewRepository(db, &key, credBackend)
+
+	return &sloEvaluator{enqueueTime, db, doConf, repo, opts.ServerURL}, nil
+}
+
+func (n *sloEvaluator) ID() string {
+	return "slo-evaluator"
+}
+
+func (n *sloEvaluator) EnqueueTime() time.Time {
+	return n.enqueueTime
+}
+
+func (n *sloEvaluator) Run(ctx context.Context) error {
+	var count int64
+
+	if err := n.db.Model(&models.Cluster{}).Count(&count).Error; err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+
+	log.Println("starting evaluation of app SLOs")
+
+	for i := 0; i < (int(count)/stepSize)+1; i++ {
+		var clusters []*models.Cluster
+
+		if err := n.db.Order("id asc").Offset(i * stepSize).Limit(stepSize).Find(&clusters).
+			Error; err != nil {
+			return err
+		}
+
+		for _, cluster := range clusters {
+			slos, err := n.repo.AppSLO().ListAppSLOsByClusterID(cluster.ID)
+			if err != nil {
+				log.Printf("error listing slos for cluster %s: %v", cluster.Name, err)
+				continue
+			}
+
+			if len(slos) == 0 {
+				continue
+			}
+
+			wg.Add(1)
+
+			go func(cluster *models.Cluster, slos []*models.AppSLO) {
+				defer wg.Done()
+
+				n.evaluateClusterSLOs(ctx, cluster, slos)
+			}(cluster, slos)
+		}
+
+		wg.Wait()
+	}
+
+	log.Println("finished evaluation of app SLOs")
+
+	return nil
+}
+
+func (n *sloEvaluator) SetData([]byte) {}
+
+// evaluateClusterSLOs evaluates each SLO in a cluster against the cluster's Prometheus
+func (n *sloEvaluator) evaluateClusterSLOs(ctx context.Context, cluster *models.Cluster, slos []*models.AppSLO) {
+	k8sAgent, err := kubernetes.GetAgentOutOfClusterConfig(ctx, &kubernetes.OutOfClusterConfig{
+		Cluster:                   cluster,
+		Repo:                      n.repo,
+		DigitalOceanOAuth:         n.doConf,
+		AllowInClusterConnections: false,
+		Timeout:                   10 * time.Second,
+	})
+	if err != nil {
+		log.Printf("error getting k8s agent for cluster %s: %v", cluster.Name, err)
+		return
+	}
+
+	promSvc, found, err := prometheus.GetPrometheusService(k8sAgent.Clientset)
+	if err != nil || !found {
+		log.Printf("error getting prometheus service for cluster %s: %v", cluster.Name, err)
+		return
+	}
+
+	appNames := make(map[uint]string)
+
+	for _, slo := range slos {
+		appName, ok := appNames[slo.PorterAppID]
+		if !ok {
+			app, err := n.repo.PorterApp().ReadPorterAppByID(ctx, slo.PorterAppID)
+			if err != nil {
+				log.Printf("error reading porter app %d for slo %d: %v", slo.PorterAppID, slo.ID, err)
+				continue
+			}
+
+			appName = app.Name
+			appNames[slo.PorterAppID] = appName
+		}
+
+		status, err := prometheus.EvaluateSLO(ctx, k8sAgent.Clientset, promSvc, prometheus.SLOEvaluationOpts{
+			SLOQueryOpts: prometheus.SLOQueryOpts{
+				Namespace:        slo.Namespace,
+				Ingress:          fmt.Sprintf("%s-%s", appName, slo.ServiceName),
+				Type:             slo.Type,
+				LatencyThreshold: time.Duration(slo.LatencyThresholdMs) * time.Millisecond,
+			},
+			Target: slo.Target,
+			Window: time.Duration(slo.WindowDays) * 24 * time.Hour,
+		})
+		if err != nil {
+			log.Printf("error evaluating slo %d of app %s: %v", slo.ID, appName, err)
+			continue
+		}
+
+		if err := n.recordSLOStatus(slo, appName, status); err != nil {
+			log.Printf("error recording status of slo %d of app %s: %v", slo.ID, appName, err)
+		}
+	}
+}
+
+// recordSLOStatus stores the result of an evaluation, and notifies if the SLO's budget started burning faster
+// than when it was last notified
+func (n *sloEvaluator) recordSLOStatus(slo *models.AppSLO, appName string, status types.AppSLOStatusEntry) error {
+	burnRates, err := json.Marshal(status.BurnRates)
+	if err != nil {
+		return err
+	}
+
+	_, err = n.repo.AppSLO().CreateAppSLOStatus(&models.AppSLOStatus{
+		AppSLOID:        slo.ID,
+		Status:          status.Status,
+		ErrorRatio:      status.ErrorRatio,
+		BudgetRemaining: status.BudgetRemaining,
+		BurnRates:       burnRates,
+		CheckedAt:       status.CheckedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := n.repo.AppSLO().DeleteAppSLOStatusesBefore(slo.ID, status.CheckedAt.Add(-sloStatusRetention)); err != nil {
+		log.Printf("error deleting old statuses of slo %d: %v", slo.ID, err)
+	}
+
+	slo.LastStatus = status.Status
+	slo.LastCheckedAt = &status.CheckedAt
+
+	switch {
+	case status.Status == types.AppSLOStatus_OK:
+		slo.LastNotifiedStatus = types.AppSLOStatus_OK
+	case sloStatusSeverity(status.Status) > sloStatusSeverity(slo.LastNotifiedStatus):
+		sloType := slo.ToAppSLOType()
+		sloType.LastStatus = &status
+
+		if err := n.notifySLOBurn(slo.ProjectID, appName, &sloType); err != nil {
+			// a failed notification is retried on the next evaluation
+			log.Printf("error sending notification for slo %d of app %s: %v", slo.ID, appName, err)
+		} else {
+			slo.LastNotifiedStatus = status.Status
+		}
+	}
+
+	_, err = n.repo.AppSLO().UpdateAppSLO(slo)
+
+	return err
+}
+
+func (n *sloEvaluator) notifySLOBurn(projectID uint, appName string, slo *types.AppSLO) error {
+	slackInts, err := n.repo.SlackIntegration().ListSlackIntegrationsByProjectID(projectID)
+	if err != nil {
+		return err
+	}
+
+	multi := notifier.NewMultiAppSLONotifier(slack.NewAppSLONotifier(slackInts...))
+
+	url := fmt.Sprintf("%s/apps/%s/metrics?project_id=%d", n.serverURL, appName, projectID)
+
+	return multi.NotifySLOBurn(appName, slo, url)
+}
+
+// sloStatusSeverity orders statuses by how quickly the error budget is being consumed
+func sloStatusSeverity(status types.AppSLOStatus) int {
+	switch status {
+	case types.AppSLOStatus_SlowBurn:
+		return 1
+	case types.AppSLOStatus_FastBurn:
+		return 2
+	case types.AppSLOStatus_Exhausted:
+		return 3
+	default:
+		return 0
+	}
+}
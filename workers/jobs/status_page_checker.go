@@ -0,0 +1,180 @@
+//go:build ee
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/ee/integrations/vault"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	rcreds "github.com/porter-dev/porter/internal/repository/credentials"
+	rgorm "github.com/porter-dev/porter/internal/repository/gorm"
+	"github.com/porter-dev/porter/internal/statuspage"
+	"github.com/porter-dev/porter/internal/synthetics"
+	"gorm.io/gorm"
+)
+
+/*
+
+                                  === Status Page Checker Job ===
+
+   This job goes through every status page, and checks the URL of each of its components from
+   outside the cluster.
+
+   The result of each check is stored on the component. When a component fails several checks
+   in a row an incident is opened on its status page, which is resolved by the next check that
+   passes. Failure incidents count as downtime in the uptime history of the component.
+
+*/
+
+// statusPageCheckConcurrency is the number of URLs that are checked at the same time
+const statusPageCheckConcurrency = 10
+
+type statusPageChecker struct {
+	enqueueTime time.Time
+	db          *gorm.DB
+	repo        repository.Repository
+	client      *http.Client
+}
+
+// StatusPageCheckerOpts holds the options required to run this job
+type StatusPageCheckerOpts struct {
+	DBConf *env.DBConf
+	// CheckTimeout is how long each check waits for a response
+	CheckTimeout time.Duration
+}
+
+func NewStatusPageChecker(
+	db *gorm.DB,
+	enqueueTime time.Time,
+	opts *StatusPageCheckerOpts,
+) (*statusPageChecker, error) {
+	var credBackend rcreds.CredentialStorage
+
+	if opts.DBConf.VaultAPIKey != "" && opts.DBConf.VaultServerURL != "" && opts.DBConf.VaultPrefix != "" {
+		credBackend = vault.NewClient(
+			opts.DBConf.VaultServerURL,
+			opts.DBConf.VaultAPIKey,
+			opts.DBConf.VaultPrefix,
+		)
+	}
+
+	var key [32]byte
+
+	for i, b := range []byte(opts.DBConf.EncryptionKey) {
+		key[i] = b
+	}
+
+	repo := rgorm.NewRepository(db, &key, credBackend)
+
+	timeout := opts.CheckTimeout
+	if timeout == 0 {
+		timeout = synthetics.DefaultTimeout
+	}
+
+	return &statusPageChecker{enqueueTime, db, repo, synthetics.NewProbeClient(timeout)}, nil
+}
+
+func (n *statusPageChecker) ID() string {
+	return "status-page-checker"
+}
+
+func (n *statusPageChecker) EnqueueTime() time.Time {
+	return n.enqueueTime
+}
+
+func (n *statusPageChecker) Run(ctx context.Context) error {
+	pages, err := n.repo.StatusPage().ListStatusPages()
+	if err != nil {
+		return fmt.Errorf("error listing status pages: %w", err)
+	}
+
+	log.Println("starting status page checks")
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, statusPageCheckConcurrency)
+
+	for _, page := range pages {
+		components, err := n.repo.StatusPage().ListStatusPageComponents(page.ID)
+		if err != nil {
+			log.Printf("error listing components of status page %s: %v", page.Slug, err)
+			continue
+		}
+
+		for _, component := range components {
+			if component.URL == "" {
+				continue
+			}
+
+			// components saved before their urls were validated are not checked until their url is changed
+			if err := synthetics.ValidatePublicURL(component.URL); err != nil {
+				log.Printf("skipping component %s of status page %s: %v", component.Name, page.Slug, err)
+				continue
+			}
+
+			wg.Add(1)
+			sem <- struct{}{}
+
+			go func(page *models.StatusPage, component *models.StatusPageComponent) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				if err := n.checkComponent(ctx, component); err != nil {
+					log.Printf("error checking component %s of status page %s: %v", component.Name, page.Slug, err)
+				}
+			}(page, component)
+		}
+	}
+
+	wg.Wait()
+
+	log.Println("finished status page checks")
+
+	return nil
+}
+
+func (n *statusPageChecker) SetData([]byte) {}
+
+// checkComponent checks the URL of a component, and opens or resolves its automatic incident
+func (n *statusPageChecker) checkComponent(ctx context.Context, component *models.StatusPageComponent) error {
+	var interval time.Duration
+	if component.LastCheckedAt != nil {
+		interval = time.Since(*component.LastCheckedAt)
+	}
+
+	result := synthetics.Probe(ctx, n.client, component.URL)
+
+	incident, err := n.repo.StatusPage().ReadOpenAutomaticStatusPageIncident(component.StatusPageID, component.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("error reading open incident: %w", err)
+	}
+
+	hasOpenIncident := err == nil
+
+	switch statuspage.ApplyCheckResult(component, result, hasOpenIncident) {
+	case statuspage.CheckAction_OpenIncident:
+		if _, err := n.repo.StatusPage().CreateStatusPageIncident(statuspage.NewAutomaticIncident(component, result, interval)); err != nil {
+			return fmt.Errorf("error opening incident: %w", err)
+		}
+	case statuspage.CheckAction_ResolveIncident:
+		statuspage.ResolveIncident(incident, fmt.Sprintf("%s is reachable again", component.Name), result.CheckedAt)
+
+		if _, err := n.repo.StatusPage().UpdateStatusPageIncident(incident); err != nil {
+			return fmt.Errorf("error resolving incident: %w", err)
+		}
+	}
+
+	if _, err := n.repo.StatusPage().UpdateStatusPageComponent(component); err != nil {
+		return fmt.Errorf("error updating component: %w", err)
+	}
+
+	return nil
+}
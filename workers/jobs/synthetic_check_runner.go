@@ -0,0 +1,244 @@
+//go:build ee
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/ee/integrations/vault"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/notifier"
+	"github.com/porter-dev/porter/internal/notifier/slack"
+	"github.com/porter-dev/porter/internal/repository"
+	rcreds "github.com/porter-dev/porter/internal/repository/credentials"
+	rgorm "github.com/porter-dev/porter/internal/repository/gorm"
+	"github.com/porter-dev/porter/internal/synthetics"
+	"gorm.io/gorm"
+)
+
+/*
+
+                                  === Synthetic Check Runner Job ===
+
+   This job runs every synthetic check of the web services of porter apps which is due, using a
+   pool of workers. Each check requests a domain of a web service from outside the cluster, and
+   matches the response against the check's expected status, body regex and TLS expiry.
+
+   The latency and result of each run are stored in the check's result history. When a check
+   fails several times in a row an incident is sent through the incident notifier, and once the
+   check passes again the incident is notified as resolved.
+
+*/
+
+const (
+	// defaultSyntheticCheckWorkers is the number of checks which run at the same time when no worker count is set
+	defaultSyntheticCheckWorkers = 20
+	// syntheticCheckBatchSize is the maximum number of due checks which are run by one run of the job
+	syntheticCheckBatchSize = 1000
+)
+
+type syntheticCheckRunner struct {
+	enqueueTime time.Time
+	db          *gorm.DB
+	repo        repository.Repository
+	client      *http.Client
+	workers     int
+	serverURL   string
+
+	appNamesMu sync.Mutex
+	appNames   map[uint]string
+}
+
+// SyntheticCheckRunnerOpts holds the options required to run this job
+type SyntheticCheckRunnerOpts struct {
+	DBConf    *env.DBConf
+	ServerURL string
+	// Workers is the number of checks which run at the same time
+	Workers int
+	// CheckTimeout is how long each check waits for a response
+	CheckTimeout time.Duration
+}
+
+func NewSyntheticCheckRunner(
+	db *gorm.DB,
+	enqueueTime time.Time,
+	opts *SyntheticCheckRunnerOpts,
+) (*syntheticCheckRunner, error) {
+	var credBackend rcreds.CredentialStorage
+
+	if opts.DBConf.VaultAPIKey != "" && opts.DBConf.VaultServerURL != "" && opts.DBConf.VaultPrefix != "" {
+		credBackend = vault.NewClient(
+			opts.DBConf.VaultServerURL,
+			opts.DBConf.VaultAPIKey,
+			opts.DBConf.VaultPrefix,
+		)
+	}
+
+	var key [32]byte
+
+	for i, b := range []byte(opts.DBConf.EncryptionKey) {
+		key[i] = b
+	}
+
+	repo := rgorm.NewRepository(db, &key, credBackend)
+
+	timeout := opts.CheckTimeout
+	if timeout == 0 {
+		timeout = synthetics.DefaultTimeout
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = defaultSyntheticCheckWorkers
+	}
+
+	return &syntheticCheckRunner{
+		enqueueTime: enqueueTime,
+		db:          db,
+		repo:        repo,
+		client:      synthetics.NewProbeClient(timeout),
+		workers:     workers,
+		serverURL:   opts.ServerURL,
+		appNames:    make(map[uint]string),
+	}, nil
+}
+
+func (n *syntheticCheckRunner) ID() string {
+	return "synthetic-check-runner"
+}
+
+func (n *syntheticCheckRunner) EnqueueTime() time.Time {
+	return n.enqueueTime
+}
+
+func (n *syntheticCheckRunner) Run(ctx context.Context) error {
+	checks, err := n.repo.AppSyntheticCheck().ListDueAppSyntheticChecks(time.Now().UTC(), syntheticCheckBatchSize)
+	if err != nil {
+		return fmt.Errorf("error listing due synthetic checks: %w", err)
+	}
+
+	log.Printf("running %d synthetic checks", len(checks))
+
+	queue := make(chan *models.AppSyntheticCheck)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < n.workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for check := range queue {
+				if err := n.runCheck(ctx, check); err != nil {
+					log.Printf("error running synthetic check %d: %v", check.ID, err)
+				}
+			}
+		}()
+	}
+
+	for _, check := range checks {
+		queue <- check
+	}
+
+	close(queue)
+	wg.Wait()
+
+	log.Println("finished running synthetic checks")
+
+	return nil
+}
+
+func (n *syntheticCheckRunner) SetData([]byte) {}
+
+// runCheck runs a check, stores its result and notifies its incident if it opened or resolved
+func (n *syntheticCheckRunner) runCheck(ctx context.Context, check *models.AppSyntheticCheck) error {
+	var result synthetics.Result
+
+	spec, err := synthetics.CheckFromModel(check)
+	if err != nil {
+		result = synthetics.Result{Error: err.Error(), CheckedAt: time.Now().UTC()}
+	} else {
+		result = synthetics.Run(ctx, n.client, spec)
+	}
+
+	stored, action := synthetics.RecordResult(check, result)
+
+	if _, err := n.repo.AppSyntheticCheck().CreateAppSyntheticCheckResult(stored); err != nil {
+		return fmt.Errorf("error storing result: %w", err)
+	}
+
+	if err := n.repo.AppSyntheticCheck().DeleteAppSyntheticCheckResultsBefore(check.ID, stored.CheckedAt.Add(-synthetics.ResultRetention)); err != nil {
+		log.Printf("error deleting old results of synthetic check %d: %v", check.ID, err)
+	}
+
+	if action != synthetics.IncidentAction_None {
+		// a failed notification is retried on the next run
+		if err := n.notifyIncident(ctx, check, stored, action); err != nil {
+			log.Printf("error notifying incident of synthetic check %d: %v", check.ID, err)
+		} else if action == synthetics.IncidentAction_Open {
+			openedAt := stored.CheckedAt
+			check.IncidentOpenedAt = &openedAt
+		} else {
+			check.IncidentOpenedAt = nil
+		}
+	}
+
+	if _, err := n.repo.AppSyntheticCheck().UpdateAppSyntheticCheck(check); err != nil {
+		return fmt.Errorf("error updating check: %w", err)
+	}
+
+	return nil
+}
+
+func (n *syntheticCheckRunner) notifyIncident(ctx context.Context, check *models.AppSyntheticCheck, result *models.AppSyntheticCheckResult, action synthetics.IncidentAction) error {
+	appName, err := n.appName(ctx, check.PorterAppID)
+	if err != nil {
+		return err
+	}
+
+	slackInts, err := n.repo.SlackIntegration().ListSlackIntegrationsByProjectID(check.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	multi := notifier.NewMultiIncidentNotifier(nil, slack.NewIncidentNotifier(slackInts...))
+
+	url := fmt.Sprintf("%s/apps/%s?project_id=%d", n.serverURL, appName, check.ProjectID)
+
+	if action == synthetics.IncidentAction_Open {
+		return multi.NotifyNew(synthetics.Incident(check, appName, result, result.CheckedAt), url)
+	}
+
+	openedAt := result.CheckedAt
+	if check.IncidentOpenedAt != nil {
+		openedAt = *check.IncidentOpenedAt
+	}
+
+	return multi.NotifyResolved(synthetics.Incident(check, appName, result, openedAt), url)
+}
+
+// appName returns the name of a porter app, which is cached for the run of the job
+func (n *syntheticCheckRunner) appName(ctx context.Context, porterAppID uint) (string, error) {
+	n.appNamesMu.Lock()
+	defer n.appNamesMu.Unlock()
+
+	if name, ok := n.appNames[porterAppID]; ok {
+		return name, nil
+	}
+
+	app, err := n.repo.PorterApp().ReadPorterAppByID(ctx, porterAppID)
+	if err != nil {
+		return "", fmt.Errorf("error reading porter app %d: %w", porterAppID, err)
+	}
+
+	n.appNames[porterAppID] = app.Name
+
+	return app.Name, nil
+}
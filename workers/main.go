@@ -72,6 +72,21 @@ type EnvConf struct {
 
 	// "preview-deployments-ttl-deleter"
 	PreviewDeploymentsTTL string `env:"PREVIEW_DEPLOYMENTS_TTL"`
+	PreviewWakerHost      string `env:"PREVIEW_WAKER_HOST"`
+	PreviewWakerPort      int    `env:"PREVIEW_WAKER_PORT,default=80"`
+
+	// "secret-reference-refresher"
+	SecretReferenceConf env.SecretReferenceConf
+
+	// "env-group-secret-rotator"
+	ClusterControlPlaneAddress string `env:"CLUSTER_CONTROL_PLANE_ADDRESS"`
+
+	// "status-page-checker"
+	StatusPageCheckTimeout time.Duration `env:"STATUS_PAGE_CHECK_TIMEOUT,default=10s"`
+
+	// "synthetic-check-runner"
+	SyntheticCheckWorkers int           `env:"SYNTHETIC_CHECK_WORKERS,default=20"`
+	SyntheticCheckTimeout time.Duration `env:"SYNTHETIC_CHECK_TIMEOUT,default=10s"`
 }
 
 func main() {
@@ -253,12 +268,110 @@ func getJob(ctx context.Context, id string, input map[string]interface{}) worker
 			DOClientSecret:        envDecoder.DOClientSecret,
 			DOScopes:              []string{"read", "write"},
 			PreviewDeploymentsTTL: envDecoder.PreviewDeploymentsTTL,
+			PreviewWakerHost:      envDecoder.PreviewWakerHost,
+			PreviewWakerPort:      envDecoder.PreviewWakerPort,
 		})
 		if err != nil {
 			log.Printf("error creating job with ID: preview-deployments-ttl-deleter. Error: %v", err)
 			return nil
 		}
 
+		return newJob
+	} else if id == "slo-evaluator" {
+		newJob, err := jobs.NewSLOEvaluator(dbConn, time.Now().UTC(), &jobs.SLOEvaluatorOpts{
+			DBConf:         &envDecoder.DBConf,
+			ServerURL:      envDecoder.ServerURL,
+			DOClientID:     envDecoder.DOClientID,
+			DOClientSecret: envDecoder.DOClientSecret,
+			DOScopes:       []string{"read", "write"},
+		})
+		if err != nil {
+			log.Printf("error creating job with ID: slo-evaluator. Error: %v", err)
+			return nil
+		}
+
+		return newJob
+	} else if id == "log-alert-evaluator" {
+		newJob, err := jobs.NewLogAlertEvaluator(dbConn, time.Now().UTC(), &jobs.LogAlertEvaluatorOpts{
+			DBConf:         &envDecoder.DBConf,
+			ServerURL:      envDecoder.ServerURL,
+			DOClientID:     envDecoder.DOClientID,
+			DOClientSecret: envDecoder.DOClientSecret,
+			DOScopes:       []string{"read", "write"},
+		})
+		if err != nil {
+			log.Printf("error creating job with ID: log-alert-evaluator. Error: %v", err)
+			return nil
+		}
+
+		return newJob
+	} else if id == "secret-reference-refresher" {
+		newJob, err := jobs.NewSecretReferenceRefresher(dbConn, time.Now().UTC(), &jobs.SecretReferenceRefresherOpts{
+			DBConf:              &envDecoder.DBConf,
+			SecretReferenceConf: envDecoder.SecretReferenceConf,
+			ServerURL:           envDecoder.ServerURL,
+			DOClientID:          envDecoder.DOClientID,
+			DOClientSecret:      envDecoder.DOClientSecret,
+			DOScopes:            []string{"read", "write"},
+		})
+		if err != nil {
+			log.Printf("error creating job with ID: secret-reference-refresher. Error: %v", err)
+			return nil
+		}
+
+		return newJob
+	} else if id == "env-group-secret-rotator" {
+		newJob, err := jobs.NewEnvGroupSecretRotator(dbConn, time.Now().UTC(), &jobs.EnvGroupSecretRotatorOpts{
+			DBConf:                     &envDecoder.DBConf,
+			ClusterControlPlaneAddress: envDecoder.ClusterControlPlaneAddress,
+			ServerURL:                  envDecoder.ServerURL,
+			DOClientID:                 envDecoder.DOClientID,
+			DOClientSecret:             envDecoder.DOClientSecret,
+			DOScopes:                   []string{"read", "write"},
+		})
+		if err != nil {
+			log.Printf("error creating job with ID: env-group-secret-rotator. Error: %v", err)
+			return nil
+		}
+
+		return newJob
+	} else if id == "status-page-checker" {
+		newJob, err := jobs.NewStatusPageChecker(dbConn, time.Now().UTC(), &jobs.StatusPageCheckerOpts{
+			DBConf:       &envDecoder.DBConf,
+			CheckTimeout: envDecoder.StatusPageCheckTimeout,
+		})
+		if err != nil {
+			log.Printf("error creating job with ID: status-page-checker. Error: %v", err)
+			return nil
+		}
+
+		return newJob
+	} else if id == "canary-reconciler" {
+		newJob, err := jobs.NewCanaryReconciler(dbConn, time.Now().UTC(), &jobs.CanaryReconcilerOpts{
+			DBConf:         &envDecoder.DBConf,
+			ServerURL:      envDecoder.ServerURL,
+			DOClientID:     envDecoder.DOClientID,
+			DOClientSecret: envDecoder.DOClientSecret,
+			DOScopes:       []string{"read", "write"},
+		})
+		if err != nil {
+			log.Printf("error creating job with ID: canary-reconciler. Error: %v", err)
+			return nil
+		}
+
+		return newJob
+	} else if id == "synthetic-check-runner" {
+		newJob, err := jobs.NewSyntheticCheckRunner(dbConn, time.Now().UTC(), &jobs.SyntheticCheckRunnerOpts{
+			DBConf:       &envDecoder.DBConf,
+			ServerURL:    envDecoder.ServerURL,
+			Workers:      envDecoder.SyntheticCheckWorkers,
+			CheckTimeout: envDecoder.SyntheticCheckTimeout,
+		})
+		if err != nil {
+			log.Printf("error creating job with ID: synthetic-check-runner. Error: %v", err)
+			return nil
+		}
+
 		return newJob
 	}
 